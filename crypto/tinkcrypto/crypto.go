@@ -20,15 +20,20 @@ import (
 	"github.com/google/tink/go/keyset"
 	"github.com/google/tink/go/mac"
 	"github.com/google/tink/go/signature"
+	"github.com/google/tink/go/tink"
 	"golang.org/x/crypto/chacha20poly1305"
 
 	"github.com/dellekappa/kms-go/spi/crypto"
 
 	"github.com/dellekappa/kms-go/crypto/tinkcrypto/primitive/aead/subtle"
 	"github.com/dellekappa/kms-go/crypto/tinkcrypto/primitive/bbs"
+	"github.com/dellekappa/kms-go/crypto/tinkcrypto/primitive/secp256k1"
+	secp256k1subtle "github.com/dellekappa/kms-go/crypto/tinkcrypto/primitive/secp256k1/subtle"
 )
 
 const (
+	// ECDHESAlg is the ECDH-ES Direct Key Agreement algorithm, the agreed key is used as the CEK.
+	ECDHESAlg = "ECDH-ES"
 	// ECDHESA256KWAlg is the ECDH-ES with AES-GCM 256 key wrapping algorithm.
 	ECDHESA256KWAlg = "ECDH-ES+A256KW"
 	// ECDH1PUA128KWAlg is the ECDH-1PU with AES-CBC 128+HMAC-SHA 256 key wrapping algorithm.
@@ -57,11 +62,36 @@ var errBadKeyHandleFormat = errors.New("bad key handle format")
 type Crypto struct {
 	ecKW  keyWrapper
 	okpKW keyWrapper
+	// allowHighS is set to accept high-S secp256k1 signatures, see EnforceLowS.
+	allowHighS bool
+}
+
+// cryptoOpts holds options for the Crypto.
+type cryptoOpts struct {
+	enforceLowS bool
+}
+
+// Opt is the Crypto option.
+type Opt func(opts *cryptoOpts)
+
+// EnforceLowS option sets whether Verify, VerifyECDSA and VerifyDigest reject high-S secp256k1 signatures with
+// secp256k1 subtle.ErrNonCanonicalSignature. It defaults to true, set it to false to accept legacy high-S signatures.
+// Signatures are always created in their low-S form.
+func EnforceLowS(enforce bool) Opt {
+	return func(opts *cryptoOpts) {
+		opts.enforceLowS = enforce
+	}
 }
 
 // New creates a new Crypto instance.
-func New() (*Crypto, error) {
-	return &Crypto{ecKW: &ecKWSupport{}, okpKW: &okpKWSupport{}}, nil
+func New(opts ...Opt) (*Crypto, error) {
+	cOpts := &cryptoOpts{enforceLowS: true}
+
+	for _, opt := range opts {
+		opt(cOpts)
+	}
+
+	return &Crypto{ecKW: &ecKWSupport{}, okpKW: &okpKWSupport{}, allowHighS: !cOpts.enforceLowS}, nil
 }
 
 // Encrypt will encrypt msg using the implementation's corresponding encryption key and primitive in kh of a public key.
@@ -151,6 +181,23 @@ func (t *Crypto) Decrypt(cipher, aad, nonce []byte, kh interface{}) ([]byte, err
 
 // Sign will sign msg using the implementation's corresponding signing key referenced by kh of a private key.
 func (t *Crypto) Sign(msg []byte, kh interface{}) ([]byte, error) {
+	signer, err := t.NewSigner(kh)
+	if err != nil {
+		return nil, err
+	}
+
+	s, err := signer.Sign(msg)
+	if err != nil {
+		return nil, fmt.Errorf("sign msg: %w", err)
+	}
+
+	return s, nil
+}
+
+// NewSigner creates the signing primitive of the private key referenced by kh, signing as Sign does. Sign creates a
+// primitive per call, callers signing many messages with the same key can reuse the returned signer instead, it is
+// safe for concurrent use.
+func (t *Crypto) NewSigner(kh interface{}) (tink.Signer, error) {
 	keyHandle, ok := kh.(*keyset.Handle)
 	if !ok {
 		return nil, errBadKeyHandleFormat
@@ -161,12 +208,7 @@ func (t *Crypto) Sign(msg []byte, kh interface{}) ([]byte, error) {
 		return nil, fmt.Errorf("create new signer: %w", err)
 	}
 
-	s, err := signer.Sign(msg)
-	if err != nil {
-		return nil, fmt.Errorf("sign msg: %w", err)
-	}
-
-	return s, nil
+	return signer, nil
 }
 
 // Verify will verify sig signature of msg using the implementation's corresponding signing key referenced by kh of
@@ -177,7 +219,7 @@ func (t *Crypto) Verify(sig, msg []byte, kh interface{}) error {
 		return errBadKeyHandleFormat
 	}
 
-	verifier, err := signature.NewVerifier(keyHandle)
+	verifier, err := t.newVerifier(keyHandle)
 	if err != nil {
 		return fmt.Errorf("create new verifier: %w", err)
 	}
@@ -190,6 +232,20 @@ func (t *Crypto) Verify(sig, msg []byte, kh interface{}) error {
 	return err
 }
 
+// newVerifier creates the verifier of keyHandle, applying the EnforceLowS option to secp256k1 keys.
+func (t *Crypto) newVerifier(keyHandle *keyset.Handle) (tink.Verifier, error) {
+	typeURL, err := primaryKeyTypeURL(keyHandle)
+	if err != nil {
+		return nil, err
+	}
+
+	if typeURL == secp256k1PublicKeyTypeURL {
+		return secp256k1.NewVerifier(keyHandle, secp256k1subtle.EnforceLowS(!t.allowHighS))
+	}
+
+	return signature.NewVerifier(keyHandle)
+}
+
 // ComputeMAC computes message authentication code (MAC) for code data
 // using a matching MAC primitive in kh key handle.
 func (t *Crypto) ComputeMAC(data []byte, kh interface{}) ([]byte, error) {
@@ -274,6 +330,8 @@ func (t *Crypto) WrapKey(cek, apu, apv []byte, recPubKey *crypto.PublicKey,
 //   - `ECDH-1PU+A192KW` alg (AES192-GCM, authcrypt KW using cek size=48).
 //   - `ECDH-1PU+A256KW` alg (AES256-GCM, authcrypt KW using cek size=64).
 //   - `ECDH-1PU+XC20PKW` alg (XChacha20Poly1305, authcrypt using crypto.WithXC20PKW() with cek size=32).
+//   - `ECDH-ES` alg (anoncrypt Direct Key Agreement using crypto.WithDirectKeyAgreement() option in wrapKeyOpts), the
+//     derived key is returned as the CEK and recWK.EncryptedCEK must be empty.
 //   - KDF (based on recWk.EPK.KeyType): `Concat KDF` as per https://tools.ietf.org/html/rfc7518#section-4.6 (for type
 //     value as EC) or `Curve25519`+`Concat KDF` as per https://tools.ietf.org/html/rfc7748#section-6.1 (for type value
 //     as OKP, ie X25519 key).
@@ -307,6 +365,16 @@ func (t *Crypto) UnwrapKey(recWK *crypto.RecipientWrappedKey, recipientKH interf
 		opt(pOpts)
 	}
 
+	if recWK.Alg == ECDHESAlg {
+		cek, err := t.deriveDirectCEK(recWK, pOpts.DirectKeyAgreementEnc(), pOpts.DirectKeyAgreementKeySize(),
+			recipientKH)
+		if err != nil {
+			return nil, fmt.Errorf("unwrapKey: %w", err)
+		}
+
+		return cek, nil
+	}
+
 	key, err := t.deriveKEKAndUnwrap(recWK.Alg, recWK.EncryptedCEK, recWK.APU, recWK.APV, pOpts.Tag(), &recWK.EPK,
 		pOpts.SenderKey(), recipientKH)
 	if err != nil {
@@ -0,0 +1,94 @@
+/*
+Copyright Gen Digital Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package tinkcrypto
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/google/tink/go/keyset"
+)
+
+// ErrEd25519RequiresFullMessage is returned by SignDigest and VerifyDigest for Ed25519 keys: Ed25519 hashes the
+// message together with the key, a digest computed beforehand can't be signed.
+var ErrEd25519RequiresFullMessage = errors.New("Ed25519 requires full message") //nolint:stylecheck
+
+// SignDigest signs digest, pre-computed outside of the KMS, with the ECDSA private key referenced by kh (NIST P or
+// secp256k1 curves) without hashing it. digest must have the size of the hash set in the key's parameters, e.g. 32
+// bytes for an ES256 key. The signature is encoded as set in the key's parameters (IEEE P1363 or DER).
+// Ed25519 keys return ErrEd25519RequiresFullMessage, other keys ErrNotECDSAKey.
+func (t *Crypto) SignDigest(digest []byte, kh interface{}) ([]byte, error) {
+	key, err := digestKey(digest, kh)
+	if err != nil {
+		return nil, fmt.Errorf("signDigest: %w", err)
+	}
+
+	r, s, err := key.sign(digest)
+	if err != nil {
+		return nil, fmt.Errorf("signDigest: %w", err)
+	}
+
+	sig, err := key.encode(r, s)
+	if err != nil {
+		return nil, fmt.Errorf("signDigest: %w", err)
+	}
+
+	return sig, nil
+}
+
+// VerifyDigest verifies sig signature of digest, pre-computed outside of the KMS, using the ECDSA key referenced by
+// kh, see SignDigest.
+func (t *Crypto) VerifyDigest(sig, digest []byte, kh interface{}) error {
+	key, err := digestKey(digest, kh)
+	if err != nil {
+		return fmt.Errorf("verifyDigest: %w", err)
+	}
+
+	r, s, err := key.decode(sig)
+	if err != nil {
+		return fmt.Errorf("verifyDigest: %w", err)
+	}
+
+	if err = key.verify(digest, r, s, t.allowHighS); err != nil {
+		return fmt.Errorf("verifyDigest: %w", err)
+	}
+
+	return nil
+}
+
+// digestKey returns the ECDSA key referenced by kh, checking digest has the size of the key's hash.
+func digestKey(digest []byte, kh interface{}) (*ecdsaKey, error) {
+	keyHandle, ok := kh.(*keyset.Handle)
+	if !ok {
+		return nil, errBadKeyHandleFormat
+	}
+
+	typeURL, err := primaryKeyTypeURL(keyHandle)
+	if err != nil {
+		return nil, err
+	}
+
+	if typeURL == ed25519PrivateKeyTypeURL || typeURL == ed25519PublicKeyTypeURL {
+		return nil, ErrEd25519RequiresFullMessage
+	}
+
+	key, err := ecdsaKeyFromHandle(keyHandle)
+	if err != nil {
+		return nil, err
+	}
+
+	if key.hash == 0 {
+		return nil, errors.New("unsupported key hash type")
+	}
+
+	if len(digest) != key.hash.Size() {
+		return nil, fmt.Errorf("invalid digest length for %s: expected %d bytes, got %d", key.hash, key.hash.Size(),
+			len(digest))
+	}
+
+	return key, nil
+}
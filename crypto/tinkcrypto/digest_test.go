@@ -0,0 +1,174 @@
+/*
+Copyright Gen Digital Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package tinkcrypto
+
+import (
+	"crypto"
+	"crypto/sha256"
+	"math/big"
+	"testing"
+
+	"github.com/google/tink/go/keyset"
+	tinkpb "github.com/google/tink/go/proto/tink_go_proto"
+	"github.com/google/tink/go/signature"
+	"github.com/stretchr/testify/require"
+
+	"github.com/dellekappa/kms-go/crypto/tinkcrypto/primitive/secp256k1"
+	secp256k1subtle "github.com/dellekappa/kms-go/crypto/tinkcrypto/primitive/secp256k1/subtle"
+)
+
+func TestCrypto_SignVerifyDigest(t *testing.T) {
+	secp256k1IEEE, err := secp256k1.IEEEP1363KeyTemplate()
+	require.NoError(t, err)
+
+	c := Crypto{}
+	msg := []byte(testMessage)
+
+	tests := []struct {
+		name     string
+		template *tinkpb.KeyTemplate
+		hash     crypto.Hash
+		// prefixed keys output Tink prefixed signatures with Sign, digest signatures are never prefixed.
+		prefixed bool
+	}{
+		{name: "P-256 DER", template: signature.ECDSAP256KeyWithoutPrefixTemplate(), hash: crypto.SHA256},
+		{name: "P-521 DER", template: signature.ECDSAP521KeyWithoutPrefixTemplate(), hash: crypto.SHA512},
+		{name: "P-384 IEEE P1363", template: ecdsaIEEEP1363KeyTemplate(t), hash: crypto.SHA384},
+		{name: "secp256k1 IEEE P1363", template: secp256k1IEEE, hash: crypto.SHA256, prefixed: true},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			kh, err := keyset.NewHandle(tc.template)
+			require.NoError(t, err)
+
+			pubKH, err := kh.Public()
+			require.NoError(t, err)
+
+			h := tc.hash.New()
+			h.Write(msg)
+			digest := h.Sum(nil)
+
+			sig, err := c.SignDigest(digest, kh)
+			require.NoError(t, err)
+
+			require.NoError(t, c.VerifyDigest(sig, digest, pubKH))
+
+			if !tc.prefixed {
+				// a digest signature is a signature of the message.
+				require.NoError(t, c.Verify(sig, msg, pubKH))
+
+				sig, err = c.Sign(msg, kh)
+				require.NoError(t, err)
+				require.NoError(t, c.VerifyDigest(sig, digest, pubKH))
+			}
+
+			digest[0] ^= 0xff
+			require.EqualError(t, c.VerifyDigest(sig, digest, pubKH), "verifyDigest: invalid signature")
+
+			_, err = c.SignDigest(digest[1:], kh)
+			require.ErrorContains(t, err, "signDigest: invalid digest length")
+
+			err = c.VerifyDigest(sig, append(digest, 0), pubKH)
+			require.ErrorContains(t, err, "verifyDigest: invalid digest length")
+
+			_, err = c.SignDigest(digest, pubKH)
+			require.ErrorIs(t, err, ErrNotECDSAKey)
+		})
+	}
+
+	t.Run("secp256k1 signatures are low-S", func(t *testing.T) {
+		kh, err := keyset.NewHandle(secp256k1IEEE)
+		require.NoError(t, err)
+
+		pubKH, err := kh.Public()
+		require.NoError(t, err)
+
+		digest := sha256.Sum256(msg)
+		n := secp256k1subtle.GetCurve("SECP256K1").Params().N
+
+		for i := 0; i < 16; i++ {
+			sig, err := c.SignDigest(digest[:], kh)
+			require.NoError(t, err)
+
+			s := new(big.Int).SetBytes(sig[32:])
+			require.True(t, s.Cmp(new(big.Int).Rsh(n, 1)) <= 0)
+
+			// (r, n - s) is a valid but non canonical signature.
+			highSSig := append([]byte{}, sig[:32]...)
+			highSSig = append(highSSig, new(big.Int).Sub(n, s).FillBytes(make([]byte, 32))...)
+
+			require.ErrorIs(t, c.VerifyDigest(highSSig, digest[:], pubKH), secp256k1subtle.ErrNonCanonicalSignature)
+		}
+	})
+
+	t.Run("secp256k1 high-S signatures with EnforceLowS(false)", func(t *testing.T) {
+		kh, err := keyset.NewHandle(secp256k1IEEE)
+		require.NoError(t, err)
+
+		pubKH, err := kh.Public()
+		require.NoError(t, err)
+
+		lenient, err := New(EnforceLowS(false))
+		require.NoError(t, err)
+
+		n := secp256k1subtle.GetCurve("SECP256K1").Params().N
+
+		// highS returns the (r, n - s) form of the IEEE P1363 signature ending sig.
+		highS := func(sig []byte) []byte {
+			rs := sig[len(sig)-64:]
+			s := new(big.Int).SetBytes(rs[32:])
+
+			highSSig := append([]byte{}, sig[:len(sig)-32]...)
+
+			return append(highSSig, new(big.Int).Sub(n, s).FillBytes(make([]byte, 32))...)
+		}
+
+		digest := sha256.Sum256(msg)
+
+		sig, err := c.SignDigest(digest[:], kh)
+		require.NoError(t, err)
+
+		require.ErrorIs(t, c.VerifyDigest(highS(sig), digest[:], pubKH), secp256k1subtle.ErrNonCanonicalSignature)
+		require.NoError(t, lenient.VerifyDigest(highS(sig), digest[:], pubKH))
+
+		require.ErrorIs(t, c.VerifyECDSA(highS(sig), msg, pubKH, crypto.SHA256),
+			secp256k1subtle.ErrNonCanonicalSignature)
+		require.NoError(t, lenient.VerifyECDSA(highS(sig), msg, pubKH, crypto.SHA256))
+
+		// Tink prefixed signature.
+		sig, err = c.Sign(msg, kh)
+		require.NoError(t, err)
+
+		require.ErrorIs(t, c.Verify(highS(sig), msg, pubKH), secp256k1subtle.ErrNonCanonicalSignature)
+		require.NoError(t, lenient.Verify(highS(sig), msg, pubKH))
+		require.NoError(t, lenient.Verify(sig, msg, pubKH))
+	})
+
+	t.Run("Ed25519 requires full message", func(t *testing.T) {
+		kh, err := keyset.NewHandle(signature.ED25519KeyWithoutPrefixTemplate())
+		require.NoError(t, err)
+
+		_, err = c.SignDigest(make([]byte, 32), kh)
+		require.ErrorIs(t, err, ErrEd25519RequiresFullMessage)
+		require.EqualError(t, err, "signDigest: Ed25519 requires full message")
+
+		pubKH, err := kh.Public()
+		require.NoError(t, err)
+
+		err = c.VerifyDigest(make([]byte, 64), make([]byte, 32), pubKH)
+		require.ErrorIs(t, err, ErrEd25519RequiresFullMessage)
+	})
+
+	t.Run("invalid key handle", func(t *testing.T) {
+		_, err := c.SignDigest(make([]byte, 32), "not a key handle")
+		require.ErrorIs(t, err, errBadKeyHandleFormat)
+
+		err = c.VerifyDigest(nil, make([]byte, 32), "not a key handle")
+		require.ErrorIs(t, err, errBadKeyHandleFormat)
+	})
+}
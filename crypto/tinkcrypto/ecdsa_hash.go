@@ -0,0 +1,323 @@
+/*
+Copyright Gen Digital Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package tinkcrypto
+
+import (
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"errors"
+	"fmt"
+	"math/big"
+
+	"github.com/golang/protobuf/proto"
+	"github.com/google/tink/go/keyset"
+	commonpb "github.com/google/tink/go/proto/common_go_proto"
+	ecdsapb "github.com/google/tink/go/proto/ecdsa_go_proto"
+	tinksignature "github.com/google/tink/go/signature/subtle"
+	tinksubtle "github.com/google/tink/go/subtle"
+	_ "golang.org/x/crypto/sha3" // registers the SHA-3 crypto.Hash functions.
+
+	secp256k1pb "github.com/dellekappa/kms-go/crypto/tinkcrypto/primitive/proto/secp256k1_go_proto"
+	secp256k1subtle "github.com/dellekappa/kms-go/crypto/tinkcrypto/primitive/secp256k1/subtle"
+)
+
+const (
+	ecdsaPrivateKeyTypeURL     = "type.googleapis.com/google.crypto.tink.EcdsaPrivateKey"
+	ecdsaPublicKeyTypeURL      = "type.googleapis.com/google.crypto.tink.EcdsaPublicKey"
+	secp256k1PrivateKeyTypeURL = "type.googleapis.com/google.crypto.tink.secp256k1PrivateKey"
+	secp256k1PublicKeyTypeURL  = "type.googleapis.com/google.crypto.tink.secp256k1PublicKey"
+)
+
+// ErrNotECDSAKey is returned by SignECDSA and VerifyECDSA when kh does not reference an ECDSA key.
+var ErrNotECDSAKey = errors.New("not an ECDSA key")
+
+// SignECDSA signs msg with the ECDSA private key referenced by kh (NIST P or secp256k1 curves), digesting msg with hash
+// instead of the hash set in the key's parameters. This allows SHA-3 digests (crypto.SHA3_256, crypto.SHA3_384 and
+// crypto.SHA3_512) which Tink keys can't be created with. The signature is encoded as set in the key's parameters
+// (IEEE P1363 or DER).
+// Any hash is accepted with any curve, an error is returned only if the digest can't be computed.
+func (t *Crypto) SignECDSA(msg []byte, kh interface{}, hash crypto.Hash) ([]byte, error) {
+	keyHandle, ok := kh.(*keyset.Handle)
+	if !ok {
+		return nil, errBadKeyHandleFormat
+	}
+
+	digest, err := ecdsaDigest(msg, hash)
+	if err != nil {
+		return nil, fmt.Errorf("signECDSA: %w", err)
+	}
+
+	key, err := ecdsaKeyFromHandle(keyHandle)
+	if err != nil {
+		return nil, fmt.Errorf("signECDSA: %w", err)
+	}
+
+	r, s, err := key.sign(digest)
+	if err != nil {
+		return nil, fmt.Errorf("signECDSA: %w", err)
+	}
+
+	sig, err := key.encode(r, s)
+	if err != nil {
+		return nil, fmt.Errorf("signECDSA: %w", err)
+	}
+
+	return sig, nil
+}
+
+// VerifyECDSA verifies sig signature of msg using the ECDSA key referenced by kh, digesting msg with hash, see
+// SignECDSA.
+func (t *Crypto) VerifyECDSA(sig, msg []byte, kh interface{}, hash crypto.Hash) error {
+	keyHandle, ok := kh.(*keyset.Handle)
+	if !ok {
+		return errBadKeyHandleFormat
+	}
+
+	digest, err := ecdsaDigest(msg, hash)
+	if err != nil {
+		return fmt.Errorf("verifyECDSA: %w", err)
+	}
+
+	key, err := ecdsaKeyFromHandle(keyHandle)
+	if err != nil {
+		return fmt.Errorf("verifyECDSA: %w", err)
+	}
+
+	r, s, err := key.decode(sig)
+	if err != nil {
+		return fmt.Errorf("verifyECDSA: %w", err)
+	}
+
+	if err = key.verify(digest, r, s, t.allowHighS); err != nil {
+		return fmt.Errorf("verifyECDSA: %w", err)
+	}
+
+	return nil
+}
+
+func ecdsaDigest(msg []byte, hash crypto.Hash) ([]byte, error) {
+	if hash == 0 || !hash.Available() {
+		return nil, fmt.Errorf("hash %s is not available", hash)
+	}
+
+	h := hash.New()
+	h.Write(msg)
+
+	return h.Sum(nil), nil
+}
+
+// ecdsaKey is the primitive of the ECDSA operations of Crypto, created by a signingKeyManager. priv is nil for public
+// keys.
+type ecdsaKey struct {
+	priv *ecdsa.PrivateKey
+	pub  *ecdsa.PublicKey
+	// hash is the hash set in the key's parameters.
+	hash   crypto.Hash
+	encode func(r, s *big.Int) ([]byte, error)
+	decode func(sig []byte) (*big.Int, *big.Int, error)
+	// lowS is set for secp256k1 keys, whose signatures must be canonical (low-S), see secp256k1subtle.IsLowS.
+	lowS bool
+}
+
+// sign signs digest, returning the r and s values of the signature with s in its canonical form.
+func (k *ecdsaKey) sign(digest []byte) (*big.Int, *big.Int, error) {
+	if k.priv == nil {
+		return nil, nil, fmt.Errorf("%w: public key handle", ErrNotECDSAKey)
+	}
+
+	r, s, err := ecdsa.Sign(rand.Reader, k.priv, digest)
+	if err != nil {
+		return nil, nil, fmt.Errorf("sign: %w", err)
+	}
+
+	return r, k.canonicalS(s), nil
+}
+
+// verify verifies the r and s values of a signature of digest, accepting high-S secp256k1 signatures if allowHighS is
+// set.
+func (k *ecdsaKey) verify(digest []byte, r, s *big.Int, allowHighS bool) error {
+	if err := k.verifyS(s, allowHighS); err != nil {
+		return err
+	}
+
+	if !ecdsa.Verify(k.pub, digest, r, s) {
+		return errors.New("invalid signature")
+	}
+
+	return nil
+}
+
+// canonicalS returns the S value to sign with: its low-S form for secp256k1 keys, s otherwise.
+func (k *ecdsaKey) canonicalS(s *big.Int) *big.Int {
+	if !k.lowS {
+		return s
+	}
+
+	return secp256k1subtle.ToLowS(k.pub.Curve, s)
+}
+
+// verifyS checks the S value of a signature is canonical for secp256k1 keys, unless allowHighS is set.
+func (k *ecdsaKey) verifyS(s *big.Int, allowHighS bool) error {
+	if k.lowS && !allowHighS && !secp256k1subtle.IsLowS(k.pub.Curve, s) {
+		return secp256k1subtle.ErrNonCanonicalSignature
+	}
+
+	return nil
+}
+
+func ecdsaKeyFromHandle(kh *keyset.Handle) (*ecdsaKey, error) {
+	typeURL, err := primaryKeyTypeURL(kh)
+	if err != nil {
+		return nil, err
+	}
+
+	switch typeURL {
+	case ecdsaPrivateKeyTypeURL, ecdsaPublicKeyTypeURL, secp256k1PrivateKeyTypeURL, secp256k1PublicKeyTypeURL:
+	default:
+		return nil, fmt.Errorf("%w: '%s'", ErrNotECDSAKey, typeURL)
+	}
+
+	primitive, err := signingPrimitive(kh, typeURL)
+	if err != nil {
+		return nil, err
+	}
+
+	key, ok := primitive.(*ecdsaKey)
+	if !ok {
+		return nil, fmt.Errorf("%w: '%s'", ErrNotECDSAKey, typeURL)
+	}
+
+	return key, nil
+}
+
+func nistPECDSAPrivateKeyPrimitive(serializedKey []byte) (interface{}, error) {
+	pbPriv := new(ecdsapb.EcdsaPrivateKey)
+	if err := proto.Unmarshal(serializedKey, pbPriv); err != nil || pbPriv.PublicKey == nil {
+		return nil, errors.New("invalid key in keyset")
+	}
+
+	return nistPECDSAKey(pbPriv.PublicKey, pbPriv.KeyValue)
+}
+
+func nistPECDSAPublicKeyPrimitive(serializedKey []byte) (interface{}, error) {
+	pbPub := new(ecdsapb.EcdsaPublicKey)
+	if err := proto.Unmarshal(serializedKey, pbPub); err != nil {
+		return nil, errors.New("invalid key in keyset")
+	}
+
+	return nistPECDSAKey(pbPub, nil)
+}
+
+// nistPECDSAKey returns the ecdsaKey of pbPub, with the private key d if not nil.
+func nistPECDSAKey(pbPub *ecdsapb.EcdsaPublicKey, d []byte) (*ecdsaKey, error) {
+	curveName := pbPub.GetParams().GetCurve().String()
+	encoding := pbPub.GetParams().GetEncoding().String()
+
+	curve := tinksubtle.GetCurve(curveName)
+	if curve == nil {
+		return nil, fmt.Errorf("unsupported curve %s", curveName)
+	}
+
+	return &ecdsaKey{
+		priv: newECDSAPrivateKey(curve, pbPub.X, pbPub.Y, d),
+		pub:  newECDSAPublicKey(curve, pbPub.X, pbPub.Y),
+		hash: tinkHash(pbPub.GetParams().GetHashType()),
+		encode: func(r, s *big.Int) ([]byte, error) {
+			return tinksignature.NewECDSASignature(r, s).EncodeECDSASignature(encoding, curve.Params().Name)
+		},
+		decode: func(sig []byte) (*big.Int, *big.Int, error) {
+			decoded, err := tinksignature.DecodeECDSASignature(sig, encoding)
+			if err != nil {
+				return nil, nil, err
+			}
+
+			return decoded.R, decoded.S, nil
+		},
+	}, nil
+}
+
+func secp256k1PrivateKeyPrimitive(serializedKey []byte) (interface{}, error) {
+	pbPriv := new(secp256k1pb.Secp256K1PrivateKey)
+	if err := proto.Unmarshal(serializedKey, pbPriv); err != nil || pbPriv.PublicKey == nil {
+		return nil, errors.New("invalid key in keyset")
+	}
+
+	return secp256k1ECDSAKey(pbPriv.PublicKey, pbPriv.KeyValue)
+}
+
+func secp256k1PublicKeyPrimitive(serializedKey []byte) (interface{}, error) {
+	pbPub := new(secp256k1pb.Secp256K1PublicKey)
+	if err := proto.Unmarshal(serializedKey, pbPub); err != nil {
+		return nil, errors.New("invalid key in keyset")
+	}
+
+	return secp256k1ECDSAKey(pbPub, nil)
+}
+
+// secp256k1ECDSAKey returns the ecdsaKey of pbPub, with the private key d if not nil.
+func secp256k1ECDSAKey(pbPub *secp256k1pb.Secp256K1PublicKey, d []byte) (*ecdsaKey, error) {
+	curveName := pbPub.GetParams().GetCurve().String()
+	encoding := pbPub.GetParams().GetEncoding().String()
+
+	curve := secp256k1subtle.GetCurve(curveName)
+	if curve == nil {
+		return nil, fmt.Errorf("unsupported curve %s", curveName)
+	}
+
+	return &ecdsaKey{
+		priv: newECDSAPrivateKey(curve, pbPub.X, pbPub.Y, d),
+		pub:  newECDSAPublicKey(curve, pbPub.X, pbPub.Y),
+		hash: tinkHash(pbPub.GetParams().GetHashType()),
+		encode: func(r, s *big.Int) ([]byte, error) {
+			return secp256k1subtle.NewSecp256K1Signature(r, s).EncodeSecp256K1Signature(encoding, curve.Params().Name)
+		},
+		decode: func(sig []byte) (*big.Int, *big.Int, error) {
+			decoded, err := secp256k1subtle.DecodeSecp256K1Signature(sig, encoding)
+			if err != nil {
+				return nil, nil, err
+			}
+
+			return decoded.R, decoded.S, nil
+		},
+		lowS: true,
+	}, nil
+}
+
+// tinkHash returns the crypto.Hash of a Tink hash type, 0 if it is not supported for ECDSA signatures.
+func tinkHash(hashType commonpb.HashType) crypto.Hash {
+	switch hashType { //nolint:exhaustive
+	case commonpb.HashType_SHA256:
+		return crypto.SHA256
+	case commonpb.HashType_SHA384:
+		return crypto.SHA384
+	case commonpb.HashType_SHA512:
+		return crypto.SHA512
+	default:
+		return 0
+	}
+}
+
+func newECDSAPublicKey(curve elliptic.Curve, x, y []byte) *ecdsa.PublicKey {
+	return &ecdsa.PublicKey{
+		Curve: curve,
+		X:     new(big.Int).SetBytes(x),
+		Y:     new(big.Int).SetBytes(y),
+	}
+}
+
+func newECDSAPrivateKey(curve elliptic.Curve, x, y, d []byte) *ecdsa.PrivateKey {
+	if d == nil {
+		return nil
+	}
+
+	return &ecdsa.PrivateKey{
+		PublicKey: *newECDSAPublicKey(curve, x, y),
+		D:         new(big.Int).SetBytes(d),
+	}
+}
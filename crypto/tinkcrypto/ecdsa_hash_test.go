@@ -0,0 +1,174 @@
+/*
+Copyright Gen Digital Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package tinkcrypto
+
+import (
+	"crypto"
+	"crypto/ecdsa"
+	"math/big"
+	"testing"
+
+	"github.com/golang/protobuf/proto"
+	"github.com/google/tink/go/keyset"
+	commonpb "github.com/google/tink/go/proto/common_go_proto"
+	ecdsapb "github.com/google/tink/go/proto/ecdsa_go_proto"
+	tinkpb "github.com/google/tink/go/proto/tink_go_proto"
+	"github.com/google/tink/go/signature"
+	"github.com/stretchr/testify/require"
+
+	"github.com/dellekappa/kms-go/crypto/tinkcrypto/primitive/secp256k1"
+)
+
+func TestCrypto_SignVerifyECDSA(t *testing.T) {
+	secp256k1DER, err := secp256k1.DERKeyTemplate()
+	require.NoError(t, err)
+
+	secp256k1IEEE, err := secp256k1.IEEEP1363KeyTemplate()
+	require.NoError(t, err)
+
+	c := Crypto{}
+	msg := []byte(testMessage)
+
+	tests := []struct {
+		name     string
+		template *tinkpb.KeyTemplate
+		ieeeSize int
+	}{
+		{name: "P-256 DER", template: signature.ECDSAP256KeyWithoutPrefixTemplate()},
+		{name: "P-384 DER", template: signature.ECDSAP384KeyWithoutPrefixTemplate()},
+		{name: "P-521 DER", template: signature.ECDSAP521KeyWithoutPrefixTemplate()},
+		{name: "P-384 IEEE P1363", template: ecdsaIEEEP1363KeyTemplate(t), ieeeSize: 96},
+		{name: "secp256k1 DER", template: secp256k1DER},
+		{name: "secp256k1 IEEE P1363", template: secp256k1IEEE, ieeeSize: 64},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			kh, err := keyset.NewHandle(tc.template)
+			require.NoError(t, err)
+
+			pubKH, err := kh.Public()
+			require.NoError(t, err)
+
+			for _, hash := range []crypto.Hash{crypto.SHA3_256, crypto.SHA3_384, crypto.SHA3_512, crypto.SHA256} {
+				sig, err := c.SignECDSA(msg, kh, hash)
+				require.NoError(t, err)
+
+				if tc.ieeeSize > 0 {
+					require.Len(t, sig, tc.ieeeSize)
+				}
+
+				require.NoError(t, c.VerifyECDSA(sig, msg, pubKH, hash))
+				require.NoError(t, c.VerifyECDSA(sig, msg, kh, hash))
+
+				err = c.VerifyECDSA(sig, []byte("other message"), pubKH, hash)
+				require.EqualError(t, err, "verifyECDSA: invalid signature")
+			}
+
+			// a SHA-3 signature is not valid for the SHA-2 key hash.
+			sig, err := c.SignECDSA(msg, kh, crypto.SHA3_256)
+			require.NoError(t, err)
+			require.Error(t, c.Verify(sig, msg, pubKH))
+		})
+	}
+
+	t.Run("key hash signatures interoperate with Sign and Verify", func(t *testing.T) {
+		kh, err := keyset.NewHandle(signature.ECDSAP256KeyWithoutPrefixTemplate())
+		require.NoError(t, err)
+
+		pubKH, err := kh.Public()
+		require.NoError(t, err)
+
+		sig, err := c.SignECDSA(msg, kh, crypto.SHA256)
+		require.NoError(t, err)
+		require.NoError(t, c.Verify(sig, msg, pubKH))
+
+		sig, err = c.Sign(msg, kh)
+		require.NoError(t, err)
+		require.NoError(t, c.VerifyECDSA(sig, msg, pubKH, crypto.SHA256))
+	})
+
+	t.Run("error hash not available", func(t *testing.T) {
+		kh, err := keyset.NewHandle(signature.ECDSAP256KeyWithoutPrefixTemplate())
+		require.NoError(t, err)
+
+		_, err = c.SignECDSA(msg, kh, crypto.MD4)
+		require.EqualError(t, err, "signECDSA: hash MD4 is not available")
+
+		err = c.VerifyECDSA(nil, msg, kh, 0)
+		require.ErrorContains(t, err, "verifyECDSA: hash")
+	})
+
+	t.Run("error not an ECDSA key", func(t *testing.T) {
+		kh, err := keyset.NewHandle(signature.ED25519KeyWithoutPrefixTemplate())
+		require.NoError(t, err)
+
+		_, err = c.SignECDSA(msg, kh, crypto.SHA3_256)
+		require.ErrorIs(t, err, ErrNotECDSAKey)
+
+		err = c.VerifyECDSA(nil, msg, kh, crypto.SHA3_256)
+		require.ErrorIs(t, err, ErrNotECDSAKey)
+
+		ecKH, err := keyset.NewHandle(signature.ECDSAP256KeyWithoutPrefixTemplate())
+		require.NoError(t, err)
+
+		pubKH, err := ecKH.Public()
+		require.NoError(t, err)
+
+		_, err = c.SignECDSA(msg, pubKH, crypto.SHA3_256)
+		require.ErrorIs(t, err, ErrNotECDSAKey)
+	})
+
+	t.Run("error invalid signature encoding", func(t *testing.T) {
+		kh, err := keyset.NewHandle(signature.ECDSAP256KeyWithoutPrefixTemplate())
+		require.NoError(t, err)
+
+		err = c.VerifyECDSA([]byte("signature"), msg, kh, crypto.SHA3_256)
+		require.ErrorContains(t, err, "verifyECDSA:")
+	})
+
+	t.Run("error bad key handle format", func(t *testing.T) {
+		_, err := c.SignECDSA(msg, nil, crypto.SHA3_256)
+		require.Equal(t, errBadKeyHandleFormat, err)
+
+		err = c.VerifyECDSA(nil, msg, nil, crypto.SHA3_256)
+		require.Equal(t, errBadKeyHandleFormat, err)
+	})
+}
+
+func ecdsaIEEEP1363KeyTemplate(t *testing.T) *tinkpb.KeyTemplate {
+	t.Helper()
+
+	format := &ecdsapb.EcdsaKeyFormat{
+		Params: &ecdsapb.EcdsaParams{
+			HashType: commonpb.HashType_SHA384,
+			Curve:    commonpb.EllipticCurveType_NIST_P384,
+			Encoding: ecdsapb.EcdsaSignatureEncoding_IEEE_P1363,
+		},
+	}
+
+	serializedFormat, err := proto.Marshal(format)
+	require.NoError(t, err)
+
+	return &tinkpb.KeyTemplate{
+		TypeUrl:          ecdsaPrivateKeyTypeURL,
+		Value:            serializedFormat,
+		OutputPrefixType: tinkpb.OutputPrefixType_RAW,
+	}
+}
+
+func TestECDSAKeyFromHandle(t *testing.T) {
+	kh, err := keyset.NewHandle(signature.ECDSAP384KeyWithoutPrefixTemplate())
+	require.NoError(t, err)
+
+	key, err := ecdsaKeyFromHandle(kh)
+	require.NoError(t, err)
+	require.Equal(t, &key.priv.PublicKey, key.pub)
+	require.True(t, key.pub.Curve.IsOnCurve(key.pub.X, key.pub.Y))
+	require.Equal(t, key.pub, key.priv.Public().(*ecdsa.PublicKey))
+	require.Equal(t, 1, key.priv.D.Cmp(big.NewInt(0)))
+}
@@ -0,0 +1,185 @@
+/*
+Copyright Gen Digital Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package tinkcrypto
+
+import (
+	"crypto"
+	"crypto/ed25519"
+	"crypto/rand"
+	"crypto/sha512"
+	"errors"
+	"fmt"
+
+	"github.com/golang/protobuf/proto"
+	"github.com/google/tink/go/keyset"
+	ed25519pb "github.com/google/tink/go/proto/ed25519_go_proto"
+)
+
+const (
+	// Ed25519MaxContextSize is the maximum size in bytes of an Ed25519ctx or Ed25519ph context (RFC 8032).
+	Ed25519MaxContextSize = 255
+
+	ed25519PrivateKeyTypeURL = "type.googleapis.com/google.crypto.tink.Ed25519PrivateKey"
+	ed25519PublicKeyTypeURL  = "type.googleapis.com/google.crypto.tink.Ed25519PublicKey"
+)
+
+// ErrNotEd25519Key is returned by SignEd25519 and VerifyEd25519 when kh does not reference an Ed25519 key.
+var ErrNotEd25519Key = errors.New("not an Ed25519 key")
+
+// SignEd25519 signs msg with the Ed25519 private key referenced by kh using the RFC 8032 variant selected by opts:
+// Ed25519ph when opts.Hash is crypto.SHA512 (msg is pre-hashed with SHA-512 by this function), Ed25519ctx when
+// opts.Context is set and plain Ed25519 otherwise. A nil opts signs with plain Ed25519, as Sign does.
+func (t *Crypto) SignEd25519(msg []byte, kh interface{}, opts *ed25519.Options) ([]byte, error) {
+	keyHandle, ok := kh.(*keyset.Handle)
+	if !ok {
+		return nil, errBadKeyHandleFormat
+	}
+
+	msg, opts, err := ed25519SigningInput(msg, opts)
+	if err != nil {
+		return nil, fmt.Errorf("signEd25519: %w", err)
+	}
+
+	typeURL, err := primaryKeyTypeURL(keyHandle)
+	if err != nil {
+		return nil, fmt.Errorf("signEd25519: %w", err)
+	}
+
+	if typeURL != ed25519PrivateKeyTypeURL {
+		return nil, fmt.Errorf("signEd25519: %w: '%s'", ErrNotEd25519Key, typeURL)
+	}
+
+	key, err := ed25519KeyFromHandle(keyHandle, typeURL)
+	if err != nil {
+		return nil, fmt.Errorf("signEd25519: %w", err)
+	}
+
+	s, err := key.sign(msg, opts)
+	if err != nil {
+		return nil, fmt.Errorf("signEd25519: sign msg: %w", err)
+	}
+
+	return s, nil
+}
+
+// VerifyEd25519 verifies sig signature of msg using the Ed25519 public key referenced by kh and the RFC 8032 variant
+// selected by opts, see SignEd25519.
+func (t *Crypto) VerifyEd25519(sig, msg []byte, kh interface{}, opts *ed25519.Options) error {
+	keyHandle, ok := kh.(*keyset.Handle)
+	if !ok {
+		return errBadKeyHandleFormat
+	}
+
+	msg, opts, err := ed25519SigningInput(msg, opts)
+	if err != nil {
+		return fmt.Errorf("verifyEd25519: %w", err)
+	}
+
+	typeURL, err := primaryKeyTypeURL(keyHandle)
+	if err != nil {
+		return fmt.Errorf("verifyEd25519: %w", err)
+	}
+
+	if typeURL != ed25519PrivateKeyTypeURL && typeURL != ed25519PublicKeyTypeURL {
+		return fmt.Errorf("verifyEd25519: %w: '%s'", ErrNotEd25519Key, typeURL)
+	}
+
+	key, err := ed25519KeyFromHandle(keyHandle, typeURL)
+	if err != nil {
+		return fmt.Errorf("verifyEd25519: %w", err)
+	}
+
+	err = ed25519.VerifyWithOptions(key.pub, msg, sig, opts)
+	if err != nil {
+		return fmt.Errorf("verifyEd25519: verify msg: %w", err)
+	}
+
+	return nil
+}
+
+// ed25519Key is the primitive of SignEd25519 and VerifyEd25519, created by a signingKeyManager. priv is nil for public
+// keys.
+type ed25519Key struct {
+	priv ed25519.PrivateKey
+	pub  ed25519.PublicKey
+}
+
+func (k *ed25519Key) sign(msg []byte, opts *ed25519.Options) ([]byte, error) {
+	if k.priv == nil {
+		return nil, fmt.Errorf("%w: public key handle", ErrNotEd25519Key)
+	}
+
+	return k.priv.Sign(rand.Reader, msg, opts)
+}
+
+func ed25519KeyFromHandle(kh *keyset.Handle, typeURL string) (*ed25519Key, error) {
+	primitive, err := signingPrimitive(kh, typeURL)
+	if err != nil {
+		return nil, err
+	}
+
+	key, ok := primitive.(*ed25519Key)
+	if !ok {
+		return nil, fmt.Errorf("%w: '%s'", ErrNotEd25519Key, typeURL)
+	}
+
+	return key, nil
+}
+
+func ed25519PrivateKeyPrimitive(serializedKey []byte) (interface{}, error) {
+	pbKey := new(ed25519pb.Ed25519PrivateKey)
+
+	if err := proto.Unmarshal(serializedKey, pbKey); err != nil {
+		return nil, errors.New("invalid key in keyset")
+	}
+
+	if len(pbKey.KeyValue) != ed25519.SeedSize {
+		return nil, errors.New("invalid Ed25519 private key size")
+	}
+
+	priv := ed25519.NewKeyFromSeed(pbKey.KeyValue)
+
+	return &ed25519Key{priv: priv, pub: priv.Public().(ed25519.PublicKey)}, nil
+}
+
+func ed25519PublicKeyPrimitive(serializedKey []byte) (interface{}, error) {
+	pbKey := new(ed25519pb.Ed25519PublicKey)
+
+	if err := proto.Unmarshal(serializedKey, pbKey); err != nil {
+		return nil, errors.New("invalid key in keyset")
+	}
+
+	if len(pbKey.KeyValue) != ed25519.PublicKeySize {
+		return nil, errors.New("invalid Ed25519 public key size")
+	}
+
+	return &ed25519Key{pub: pbKey.KeyValue}, nil
+}
+
+// ed25519SigningInput validates opts and returns the message and options to pass to the ed25519 package, the message
+// being pre-hashed for Ed25519ph.
+func ed25519SigningInput(msg []byte, opts *ed25519.Options) ([]byte, *ed25519.Options, error) {
+	if opts == nil {
+		return msg, &ed25519.Options{}, nil
+	}
+
+	if len(opts.Context) > Ed25519MaxContextSize {
+		return nil, nil, fmt.Errorf("context of %d bytes exceeds the maximum of %d bytes", len(opts.Context),
+			Ed25519MaxContextSize)
+	}
+
+	switch opts.Hash {
+	case crypto.Hash(0):
+		return msg, opts, nil
+	case crypto.SHA512:
+		digest := sha512.Sum512(msg)
+
+		return digest[:], opts, nil
+	default:
+		return nil, nil, fmt.Errorf("unsupported Ed25519 hash %s, only SHA-512 (Ed25519ph) is supported", opts.Hash)
+	}
+}
@@ -0,0 +1,110 @@
+/*
+Copyright Gen Digital Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package tinkcrypto
+
+import (
+	"crypto"
+	"crypto/ed25519"
+	"strings"
+	"testing"
+
+	"github.com/google/tink/go/keyset"
+	"github.com/google/tink/go/signature"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCrypto_SignVerifyEd25519(t *testing.T) {
+	kh, err := keyset.NewHandle(signature.ED25519KeyWithoutPrefixTemplate())
+	require.NoError(t, err)
+
+	pubKH, err := kh.Public()
+	require.NoError(t, err)
+
+	c := Crypto{}
+	msg := []byte(testMessage)
+
+	tests := []struct {
+		name string
+		opts *ed25519.Options
+	}{
+		{name: "Ed25519", opts: nil},
+		{name: "Ed25519ctx", opts: &ed25519.Options{Context: "test context"}},
+		{name: "Ed25519ph", opts: &ed25519.Options{Hash: crypto.SHA512}},
+		{name: "Ed25519ph with context", opts: &ed25519.Options{Hash: crypto.SHA512, Context: "test context"}},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			sig, err := c.SignEd25519(msg, kh, tc.opts)
+			require.NoError(t, err)
+
+			require.NoError(t, c.VerifyEd25519(sig, msg, pubKH, tc.opts))
+			require.NoError(t, c.VerifyEd25519(sig, msg, kh, tc.opts))
+
+			err = c.VerifyEd25519(sig, []byte("other message"), pubKH, tc.opts)
+			require.ErrorContains(t, err, "verifyEd25519: verify msg")
+
+			// plain Ed25519 signatures are interoperable with Sign/Verify only.
+			if tc.opts == nil {
+				require.NoError(t, c.Verify(sig, msg, pubKH))
+			} else {
+				require.Error(t, c.Verify(sig, msg, pubKH))
+			}
+		})
+	}
+
+	t.Run("contexts are domain separated", func(t *testing.T) {
+		sig, err := c.SignEd25519(msg, kh, &ed25519.Options{Context: "context 1"})
+		require.NoError(t, err)
+
+		err = c.VerifyEd25519(sig, msg, pubKH, &ed25519.Options{Context: "context 2"})
+		require.ErrorContains(t, err, "verifyEd25519: verify msg")
+	})
+
+	t.Run("error context too long", func(t *testing.T) {
+		opts := &ed25519.Options{Context: strings.Repeat("c", Ed25519MaxContextSize+1)}
+
+		_, err := c.SignEd25519(msg, kh, opts)
+		require.EqualError(t, err, "signEd25519: context of 256 bytes exceeds the maximum of 255 bytes")
+
+		err = c.VerifyEd25519(nil, msg, pubKH, opts)
+		require.EqualError(t, err, "verifyEd25519: context of 256 bytes exceeds the maximum of 255 bytes")
+
+		_, err = c.SignEd25519(msg, kh, &ed25519.Options{Context: strings.Repeat("c", Ed25519MaxContextSize)})
+		require.NoError(t, err)
+	})
+
+	t.Run("error unsupported hash", func(t *testing.T) {
+		_, err := c.SignEd25519(msg, kh, &ed25519.Options{Hash: crypto.SHA256})
+		require.ErrorContains(t, err, "signEd25519: unsupported Ed25519 hash SHA-256")
+	})
+
+	t.Run("error not an Ed25519 key", func(t *testing.T) {
+		ecKH, err := keyset.NewHandle(signature.ECDSAP256KeyTemplate())
+		require.NoError(t, err)
+
+		_, err = c.SignEd25519(msg, ecKH, nil)
+		require.ErrorIs(t, err, ErrNotEd25519Key)
+
+		_, err = c.SignEd25519(msg, pubKH, nil)
+		require.ErrorIs(t, err, ErrNotEd25519Key)
+
+		ecPubKH, err := ecKH.Public()
+		require.NoError(t, err)
+
+		err = c.VerifyEd25519(nil, msg, ecPubKH, nil)
+		require.ErrorIs(t, err, ErrNotEd25519Key)
+	})
+
+	t.Run("error bad key handle format", func(t *testing.T) {
+		_, err := c.SignEd25519(msg, nil, nil)
+		require.Equal(t, errBadKeyHandleFormat, err)
+
+		err = c.VerifyEd25519(nil, msg, nil, nil)
+		require.Equal(t, errBadKeyHandleFormat, err)
+	})
+}
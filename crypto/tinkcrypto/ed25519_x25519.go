@@ -0,0 +1,91 @@
+/*
+Copyright Gen Digital Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package tinkcrypto
+
+import (
+	"crypto/ed25519"
+	"errors"
+	"fmt"
+
+	"github.com/golang/protobuf/proto"
+	"github.com/google/tink/go/keyset"
+	ed25519pb "github.com/google/tink/go/proto/ed25519_go_proto"
+
+	"github.com/dellekappa/kms-go/crypto/tinkcrypto/primitive/composite/ecdh"
+	"github.com/dellekappa/kms-go/crypto/tinkcrypto/primitive/composite/keyio"
+	ecdhpb "github.com/dellekappa/kms-go/crypto/tinkcrypto/primitive/proto/ecdh_aead_go_proto"
+	cryptoapi "github.com/dellekappa/kms-go/spi/crypto"
+	"github.com/dellekappa/kms-go/util/cryptoutil"
+)
+
+// x25519ConversionKeyManager creates, as primitive of an Ed25519 private key, the X25519 ECDH key wrapping handle of
+// its X25519 conversion. Like the signing key managers, the Ed25519 key is never exported from the keyset handle.
+//
+//nolint:gochecknoglobals
+var x25519ConversionKeyManager = &signingKeyManager{
+	typeURL:   ed25519PrivateKeyTypeURL,
+	primitive: x25519KeyHandlePrimitive,
+}
+
+// X25519KeyHandleFromEd25519 returns an X25519 ECDH key wrapping handle for the Ed25519 private key of kh, to unwrap
+// keys wrapped to the X25519 conversion of its Ed25519 public key. Key handles not referencing an Ed25519 private key
+// are returned as is.
+func X25519KeyHandleFromEd25519(kh interface{}) (interface{}, error) {
+	keyHandle, ok := kh.(*keyset.Handle)
+	if !ok || keyHandle == nil {
+		return kh, nil
+	}
+
+	typeURL, err := primaryKeyTypeURL(keyHandle)
+	if err != nil {
+		return nil, fmt.Errorf("x25519KeyHandleFromEd25519: %w", err)
+	}
+
+	if typeURL != ed25519PrivateKeyTypeURL {
+		return kh, nil
+	}
+
+	ps, err := keyHandle.PrimitivesWithKeyManager(x25519ConversionKeyManager)
+	if err != nil {
+		return nil, fmt.Errorf("x25519KeyHandleFromEd25519: %w", err)
+	}
+
+	return ps.Primary.Primitive, nil
+}
+
+func x25519KeyHandlePrimitive(serializedKey []byte) (interface{}, error) {
+	pbKey := new(ed25519pb.Ed25519PrivateKey)
+
+	if err := proto.Unmarshal(serializedKey, pbKey); err != nil {
+		return nil, errors.New("invalid key in keyset")
+	}
+
+	if len(pbKey.KeyValue) != ed25519.SeedSize {
+		return nil, errors.New("invalid Ed25519 private key size")
+	}
+
+	privKey := ed25519.NewKeyFromSeed(pbKey.KeyValue)
+
+	x25519Pub, err := cryptoutil.PublicEd25519toCurve25519(privKey.Public().(ed25519.PublicKey))
+	if err != nil {
+		return nil, fmt.Errorf("convert public key: %w", err)
+	}
+
+	x25519Priv, err := cryptoutil.SecretEd25519toCurve25519(privKey)
+	if err != nil {
+		return nil, fmt.Errorf("convert private key: %w", err)
+	}
+
+	return keyio.PrivateKeyToKeysetHandle(&cryptoapi.PrivateKey{
+		PublicKey: cryptoapi.PublicKey{
+			X:     x25519Pub,
+			Curve: "X25519",
+			Type:  ecdhpb.KeyType_OKP.String(),
+		},
+		D: x25519Priv,
+	}, ecdh.AES256GCM)
+}
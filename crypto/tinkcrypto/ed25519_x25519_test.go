@@ -0,0 +1,53 @@
+/*
+Copyright Gen Digital Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package tinkcrypto
+
+import (
+	"testing"
+
+	"github.com/google/tink/go/aead"
+	"github.com/google/tink/go/keyset"
+	"github.com/google/tink/go/signature"
+	"github.com/stretchr/testify/require"
+
+	"github.com/dellekappa/kms-go/crypto/tinkcrypto/primitive/composite/keyio"
+	"github.com/dellekappa/kms-go/util/cryptoutil"
+)
+
+func TestX25519KeyHandleFromEd25519(t *testing.T) {
+	t.Run("converts an Ed25519 private key", func(t *testing.T) {
+		kh, err := keyset.NewHandle(signature.ED25519KeyWithoutPrefixTemplate())
+		require.NoError(t, err)
+
+		edKey, err := ed25519KeyFromHandle(kh, ed25519PrivateKeyTypeURL)
+		require.NoError(t, err)
+
+		wantX, err := cryptoutil.PublicEd25519toCurve25519(edKey.pub)
+		require.NoError(t, err)
+
+		x25519KH, err := X25519KeyHandleFromEd25519(kh)
+		require.NoError(t, err)
+
+		pubKey, err := keyio.ExtractPrimaryPublicKey(x25519KH.(*keyset.Handle))
+		require.NoError(t, err)
+		require.Equal(t, "X25519", pubKey.Curve)
+		require.Equal(t, wantX, pubKey.X)
+	})
+
+	t.Run("other keys are returned as is", func(t *testing.T) {
+		kh, err := keyset.NewHandle(aead.AES256GCMKeyTemplate())
+		require.NoError(t, err)
+
+		out, err := X25519KeyHandleFromEd25519(kh)
+		require.NoError(t, err)
+		require.Equal(t, kh, out)
+
+		out, err = X25519KeyHandleFromEd25519("not a key handle")
+		require.NoError(t, err)
+		require.Equal(t, "not a key handle", out)
+	})
+}
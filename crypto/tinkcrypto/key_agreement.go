@@ -0,0 +1,88 @@
+/*
+Copyright Gen Digital Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package tinkcrypto
+
+import (
+	"crypto/ecdsa"
+	"errors"
+	"fmt"
+	"math/big"
+
+	"golang.org/x/crypto/curve25519"
+
+	ecdhpb "github.com/dellekappa/kms-go/crypto/tinkcrypto/primitive/proto/ecdh_aead_go_proto"
+	cryptoapi "github.com/dellekappa/kms-go/spi/crypto"
+)
+
+// ComputeSharedSecret returns the ECDH shared secret Z of the private key of the kh key handle with recPubKey: the X
+// coordinate of the shared point padded to the curve size for NIST P curve keys, the X25519 output for X25519 keys.
+// kh must be a *keyset.Handle of an ECDH private key. It lets KMSs implement cryptoapi.KeyAgreer without exporting
+// their private keys.
+func (t *Crypto) ComputeSharedSecret(recPubKey *cryptoapi.PublicKey, kh interface{}) ([]byte, error) {
+	if recPubKey == nil {
+		return nil, errors.New("computeSharedSecret: recipient public key is required")
+	}
+
+	switch recPubKey.Type {
+	case ecdhpb.KeyType_EC.String():
+		privKey, err := ksToPrivateECDSAKey(kh)
+		if err != nil {
+			return nil, fmt.Errorf("computeSharedSecret: %w", err)
+		}
+
+		curve, err := t.ecKW.getCurve(recPubKey.Curve)
+		if err != nil {
+			return nil, fmt.Errorf("computeSharedSecret: %w", err)
+		}
+
+		pubKey := &ecdsa.PublicKey{
+			Curve: curve,
+			X:     new(big.Int).SetBytes(recPubKey.X),
+			Y:     new(big.Int).SetBytes(recPubKey.Y),
+		}
+
+		if curve != privKey.Curve || !curve.IsOnCurve(pubKey.X, pubKey.Y) {
+			return nil, errors.New("computeSharedSecret: recipient key is not on the curve of the private key")
+		}
+
+		return deriveECDH(privKey, pubKey, dSize(curve)), nil
+	case ecdhpb.KeyType_OKP.String():
+		privKey, err := ksToPrivateX25519Key(kh)
+		if err != nil {
+			return nil, fmt.Errorf("computeSharedSecret: %w", err)
+		}
+
+		z, err := curve25519.X25519(privKey, recPubKey.X)
+		if err != nil {
+			return nil, fmt.Errorf("computeSharedSecret: %w", err)
+		}
+
+		return z, nil
+	default:
+		return nil, fmt.Errorf("computeSharedSecret: invalid recipient key type '%s'", recPubKey.Type)
+	}
+}
+
+// deriveKMSSender1Pu derives the ECDH-1PU KEK of a sender key kept in a KMS: ze is the ephemeral shared secret, the
+// sender static shared secret is computed by the KMS.
+func deriveKMSSender1Pu(alg string, apu, apv, tag, ze []byte, sender *cryptoapi.KMSSenderKey,
+	recPubKey *cryptoapi.PublicKey, keySize int) ([]byte, error) {
+	if sender.KeyAgreer == nil {
+		return nil, errors.New("deriveKMSSender1Pu: KMS sender key has no KeyAgreer")
+	}
+
+	zs, err := sender.KeyAgreer.ComputeSharedSecret(sender.KID, recPubKey)
+	if err != nil {
+		return nil, fmt.Errorf("deriveKMSSender1Pu: compute sender shared secret: %w", err)
+	}
+
+	if len(zs) != len(ze) {
+		return nil, fmt.Errorf("deriveKMSSender1Pu: sender shared secret size %d does not match %d", len(zs), len(ze))
+	}
+
+	return derive1Pu(alg, ze, zs, apu, apv, tag, keySize), nil
+}
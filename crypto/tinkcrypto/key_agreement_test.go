@@ -0,0 +1,165 @@
+/*
+Copyright Gen Digital Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package tinkcrypto
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/google/tink/go/keyset"
+	tinkpb "github.com/google/tink/go/proto/tink_go_proto"
+	"github.com/google/tink/go/subtle/random"
+	"github.com/stretchr/testify/require"
+
+	"github.com/dellekappa/kms-go/crypto/tinkcrypto/primitive/composite/ecdh"
+	"github.com/dellekappa/kms-go/crypto/tinkcrypto/primitive/composite/keyio"
+	cryptoapi "github.com/dellekappa/kms-go/spi/crypto"
+)
+
+// testKeyAgreer is a KMS computing shared secrets with the private key handles it keeps.
+type testKeyAgreer struct {
+	c    *Crypto
+	keys map[string]*keyset.Handle
+}
+
+func (a *testKeyAgreer) ComputeSharedSecret(senderKID string, recipientPub *cryptoapi.PublicKey) ([]byte, error) {
+	kh, ok := a.keys[senderKID]
+	if !ok {
+		return nil, fmt.Errorf("key '%s' not found", senderKID)
+	}
+
+	return a.c.ComputeSharedSecret(recipientPub, kh)
+}
+
+func TestCrypto_ComputeSharedSecret(t *testing.T) {
+	c, err := New()
+	require.NoError(t, err)
+
+	for _, tc := range []struct {
+		template *tinkpb.KeyTemplate
+		size     int
+	}{
+		{template: ecdh.NISTP256ECDHKWKeyTemplate(), size: 32},
+		{template: ecdh.NISTP521ECDHKWKeyTemplate(), size: 66},
+		{template: ecdh.X25519ECDHKWKeyTemplate(), size: 32},
+	} {
+		aliceKH, err := keyset.NewHandle(tc.template)
+		require.NoError(t, err)
+
+		bobKH, err := keyset.NewHandle(tc.template)
+		require.NoError(t, err)
+
+		alicePub, err := keyio.ExtractPrimaryPublicKey(aliceKH)
+		require.NoError(t, err)
+
+		bobPub, err := keyio.ExtractPrimaryPublicKey(bobKH)
+		require.NoError(t, err)
+
+		z, err := c.ComputeSharedSecret(bobPub, aliceKH)
+		require.NoError(t, err)
+
+		bobZ, err := c.ComputeSharedSecret(alicePub, bobKH)
+		require.NoError(t, err)
+		require.Equal(t, z, bobZ)
+		require.Len(t, z, tc.size)
+	}
+
+	t.Run("failures", func(t *testing.T) {
+		ecKH, err := keyset.NewHandle(ecdh.NISTP256ECDHKWKeyTemplate())
+		require.NoError(t, err)
+
+		p384KH, err := keyset.NewHandle(ecdh.NISTP384ECDHKWKeyTemplate())
+		require.NoError(t, err)
+
+		p384Pub, err := keyio.ExtractPrimaryPublicKey(p384KH)
+		require.NoError(t, err)
+
+		_, err = c.ComputeSharedSecret(p384Pub, ecKH)
+		require.EqualError(t, err, "computeSharedSecret: recipient key is not on the curve of the private key")
+
+		_, err = c.ComputeSharedSecret(p384Pub, "not a key handle")
+		require.ErrorIs(t, err, errBadKeyHandleFormat)
+
+		_, err = c.ComputeSharedSecret(&cryptoapi.PublicKey{Type: "RSA"}, ecKH)
+		require.EqualError(t, err, "computeSharedSecret: invalid recipient key type 'RSA'")
+
+		_, err = c.ComputeSharedSecret(nil, ecKH)
+		require.EqualError(t, err, "computeSharedSecret: recipient public key is required")
+	})
+}
+
+func TestCrypto_ECDH1PU_Wrap_Unwrap_Key_Using_KMSSenderKey(t *testing.T) {
+	c, err := New()
+	require.NoError(t, err)
+
+	apu := random.GetRandomBytes(uint32(10))
+	apv := random.GetRandomBytes(uint32(10))
+
+	for _, tc := range []struct {
+		template *tinkpb.KeyTemplate
+		opts     []cryptoapi.WrapKeyOpts
+		cekSize  int
+	}{
+		{template: ecdh.NISTP256ECDHKWKeyTemplate(), cekSize: 64},
+		{template: ecdh.NISTP521ECDHKWKeyTemplate(), cekSize: 32},
+		{
+			template: ecdh.X25519ECDHKWKeyTemplate(),
+			opts:     []cryptoapi.WrapKeyOpts{cryptoapi.WithXC20PKW()},
+			cekSize:  32,
+		},
+	} {
+		recipientKH, err := keyset.NewHandle(tc.template)
+		require.NoError(t, err)
+
+		recipientKey, err := keyio.ExtractPrimaryPublicKey(recipientKH)
+		require.NoError(t, err)
+
+		senderKH, err := keyset.NewHandle(tc.template)
+		require.NoError(t, err)
+
+		senderPubKey, err := keyio.ExtractPrimaryPublicKey(senderKH)
+		require.NoError(t, err)
+
+		kms := &testKeyAgreer{c: c, keys: map[string]*keyset.Handle{"sender": senderKH}}
+		cek := random.GetRandomBytes(uint32(tc.cekSize))
+
+		wrappedKey, err := c.WrapKey(cek, apu, apv, recipientKey, append(tc.opts, cryptoapi.WithSender(
+			&cryptoapi.KMSSenderKey{KID: "sender", KeyAgreer: kms}))...)
+		require.NoError(t, err)
+
+		uCEK, err := c.UnwrapKey(wrappedKey, recipientKH, cryptoapi.WithSender(senderPubKey))
+		require.NoError(t, err)
+		require.Equal(t, cek, uCEK)
+
+		_, err = c.WrapKey(cek, apu, apv, recipientKey, append(tc.opts, cryptoapi.WithSender(
+			&cryptoapi.KMSSenderKey{KID: "unknown", KeyAgreer: kms}))...)
+		require.ErrorContains(t, err, "compute sender shared secret: key 'unknown' not found")
+	}
+
+	t.Run("failing KMS", func(t *testing.T) {
+		recipientKH, err := keyset.NewHandle(ecdh.NISTP256ECDHKWKeyTemplate())
+		require.NoError(t, err)
+
+		recipientKey, err := keyio.ExtractPrimaryPublicKey(recipientKH)
+		require.NoError(t, err)
+
+		_, err = c.WrapKey(random.GetRandomBytes(64), apu, apv, recipientKey,
+			cryptoapi.WithSender(&cryptoapi.KMSSenderKey{KID: "sender"}))
+		require.ErrorContains(t, err, "deriveKMSSender1Pu: KMS sender key has no KeyAgreer")
+
+		_, err = c.WrapKey(random.GetRandomBytes(64), apu, apv, recipientKey,
+			cryptoapi.WithSender(&cryptoapi.KMSSenderKey{KID: "sender", KeyAgreer: failingKeyAgreer{}}))
+		require.ErrorContains(t, err, "compute sender shared secret: kms failure")
+	})
+}
+
+type failingKeyAgreer struct{}
+
+func (failingKeyAgreer) ComputeSharedSecret(string, *cryptoapi.PublicKey) ([]byte, error) {
+	return nil, errors.New("kms failure")
+}
@@ -118,7 +118,7 @@ func (t *Crypto) deriveKEKAndUnwrap(alg string, encCEK, apu, apv, tag []byte, ep
 			return nil, fmt.Errorf("deriveKEKAndUnwrap: error ECDH-1PU kek derivation: %w", err)
 		}
 	case ECDHESA256KWAlg, ECDHESXC20PKWAlg:
-		kek, err = t.deriveESKEKForUnwrap(alg, apu, apv, epk, recipientPrivateKey)
+		kek, err = t.deriveESKEKForUnwrap(alg, apu, apv, epk, recipientPrivateKey, defKeySize)
 		if err != nil {
 			return nil, fmt.Errorf("deriveKEKAndUnwrap: error ECDH-ES kek derivation: %w", err)
 		}
@@ -129,6 +129,37 @@ func (t *Crypto) deriveKEKAndUnwrap(alg string, encCEK, apu, apv, tag []byte, ep
 	return t.unwrapRaw(alg, kek, encCEK)
 }
 
+// deriveDirectCEK is the entry point for Crypto.UnwrapKey() with the ECDH-ES direct key agreement alg, the derived
+// key is the CEK of the enc content encryption algorithm.
+func (t *Crypto) deriveDirectCEK(recWK *cryptoapi.RecipientWrappedKey, enc string, keySize int,
+	recKH interface{}) ([]byte, error) {
+	if enc == "" || keySize <= 0 {
+		return nil, fmt.Errorf("deriveDirectCEK: content encryption algorithm and key size are required for '%s'",
+			ECDHESAlg)
+	}
+
+	if len(recWK.EncryptedCEK) > 0 {
+		return nil, fmt.Errorf("deriveDirectCEK: encrypted key must be empty for '%s'", ECDHESAlg)
+	}
+
+	recPrivKH, ok := recKH.(*keyset.Handle)
+	if !ok {
+		return nil, fmt.Errorf("deriveDirectCEK: %w", errBadKeyHandleFormat)
+	}
+
+	recipientPrivateKey, err := extractPrivKey(recPrivKH)
+	if err != nil {
+		return nil, fmt.Errorf("deriveDirectCEK: %w", err)
+	}
+
+	cek, err := t.deriveESKEKForUnwrap(enc, recWK.APU, recWK.APV, &recWK.EPK, recipientPrivateKey, keySize)
+	if err != nil {
+		return nil, fmt.Errorf("deriveDirectCEK: error ECDH-ES cek derivation: %w", err)
+	}
+
+	return cek, nil
+}
+
 func (t *Crypto) unwrapRaw(alg string, kek, encCEK []byte) ([]byte, error) {
 	var wk []byte
 
@@ -264,7 +295,7 @@ func (t *Crypto) deriveESKEK(apu, apv []byte, recPubKey *cryptoapi.PublicKey,
 }
 
 func (t *Crypto) deriveESKEKForUnwrap(alg string, apu, apv []byte, epk *cryptoapi.PublicKey,
-	recipientPrivateKey interface{}) ([]byte, error) {
+	recipientPrivateKey interface{}, keySize int) ([]byte, error) {
 	var (
 		kek []byte
 		err error
@@ -272,12 +303,12 @@ func (t *Crypto) deriveESKEKForUnwrap(alg string, apu, apv []byte, epk *cryptoap
 
 	switch epk.Type {
 	case ecdhpb.KeyType_EC.String():
-		kek, err = t.deriveESWithECKeyForUnwrap(alg, apu, apv, epk, recipientPrivateKey)
+		kek, err = t.deriveESWithECKeyForUnwrap(alg, apu, apv, epk, recipientPrivateKey, keySize)
 		if err != nil {
 			return nil, fmt.Errorf("deriveESKEKForUnwrap: error: %w", err)
 		}
 	case ecdhpb.KeyType_OKP.String():
-		kek, err = t.deriveESWithOKPKeyForUnwrap(alg, apu, apv, epk, recipientPrivateKey)
+		kek, err = t.deriveESWithOKPKeyForUnwrap(alg, apu, apv, epk, recipientPrivateKey, keySize)
 		if err != nil {
 			return nil, fmt.Errorf("deriveESKEKForUnwrap: error: %w", err)
 		}
@@ -290,9 +321,17 @@ func (t *Crypto) deriveESKEKForUnwrap(alg string, apu, apv []byte, epk *cryptoap
 
 func (t *Crypto) derive1PUWithECKey(wrappingAlg string, apu, apv, tag []byte, senderKH interface{},
 	recPubKey *cryptoapi.PublicKey, epkPrv *cryptoapi.PrivateKey) (string, []byte, *cryptoapi.PublicKey, []byte, error) {
-	senderPrivKey, err := ksToPrivateECDSAKey(senderKH)
-	if err != nil {
-		return "", nil, nil, nil, fmt.Errorf("derive1PUWithECKey: failed to retrieve sender key: %w", err)
+	kmsSender, isKMSSender := senderKH.(*cryptoapi.KMSSenderKey)
+
+	var senderPrivKey *ecdsa.PrivateKey
+
+	if !isKMSSender {
+		var err error
+
+		senderPrivKey, err = ksToPrivateECDSAKey(senderKH)
+		if err != nil {
+			return "", nil, nil, nil, fmt.Errorf("derive1PUWithECKey: failed to retrieve sender key: %w", err)
+		}
 	}
 
 	pubKey, ephemeralPrivKey, err := t.convertRecKeyAndGenOrGetEPKEC(recPubKey, epkPrv)
@@ -309,7 +348,20 @@ func (t *Crypto) derive1PUWithECKey(wrappingAlg string, apu, apv, tag []byte, se
 
 	keySize := aesCEKSize1PU(wrappingAlg)
 
-	kek, err := t.ecKW.deriveSender1Pu(wrappingAlg, apu, apv, tag, ephemeralPrivKey, senderPrivKey, pubKey, keySize)
+	var kek []byte
+
+	if isKMSSender {
+		if !pubKey.Curve.IsOnCurve(pubKey.X, pubKey.Y) {
+			return "", nil, nil, nil, errors.New("derive1PUWithECKey: recipient key is not on curve")
+		}
+
+		ze := deriveECDH(ephemeralPrivKey, pubKey, keySize)
+
+		kek, err = deriveKMSSender1Pu(wrappingAlg, apu, apv, tag, ze, kmsSender, recPubKey, keySize)
+	} else {
+		kek, err = t.ecKW.deriveSender1Pu(wrappingAlg, apu, apv, tag, ephemeralPrivKey, senderPrivKey, pubKey, keySize)
+	}
+
 	if err != nil {
 		return "", nil, nil, nil, fmt.Errorf("derive1PUWithECKey: failed to derive key: %w", err)
 	}
@@ -382,7 +434,7 @@ func aesCEKSize1PU(alg string) int {
 }
 
 func (t *Crypto) deriveESWithECKeyForUnwrap(alg string, apu, apv []byte, epk *cryptoapi.PublicKey,
-	recipientPrivateKey interface{}) ([]byte, error) {
+	recipientPrivateKey interface{}, keySize int) ([]byte, error) {
 	var (
 		epkCurve elliptic.Curve
 		err      error
@@ -410,7 +462,11 @@ func (t *Crypto) deriveESWithECKeyForUnwrap(alg string, apu, apv []byte, epk *cr
 		return nil, errors.New("deriveESWithECKeyForUnwrap: recipient and ephemeral keys are not on the same curve")
 	}
 
-	return josecipher.DeriveECDHES(alg, apu, apv, recPrivKey, epkPubKey, defKeySize), nil
+	if !epkCurve.IsOnCurve(epkPubKey.X, epkPubKey.Y) {
+		return nil, errors.New("deriveESWithECKeyForUnwrap: ephemeral key is not on curve")
+	}
+
+	return josecipher.DeriveECDHES(alg, apu, apv, recPrivKey, epkPubKey, keySize), nil
 }
 
 func (t *Crypto) deriveESWithECKey(apu, apv []byte, recPubKey *cryptoapi.PublicKey,
@@ -446,9 +502,17 @@ func (t *Crypto) deriveESWithECKey(apu, apv []byte, recPubKey *cryptoapi.PublicK
 
 func (t *Crypto) derive1PUWithOKPKey(wrappingAlg string, apu, apv, tag []byte, senderKH interface{},
 	recPubKey *cryptoapi.PublicKey, epkPrv *cryptoapi.PrivateKey) (string, []byte, *cryptoapi.PublicKey, []byte, error) {
-	senderPrivKey, err := ksToPrivateX25519Key(senderKH)
-	if err != nil {
-		return "", nil, nil, nil, fmt.Errorf("derive1PUWithOKPKey: failed to retrieve sender key: %w", err)
+	kmsSender, isKMSSender := senderKH.(*cryptoapi.KMSSenderKey)
+
+	var senderPrivKey []byte
+
+	if !isKMSSender {
+		var err error
+
+		senderPrivKey, err = ksToPrivateX25519Key(senderKH)
+		if err != nil {
+			return "", nil, nil, nil, fmt.Errorf("derive1PUWithOKPKey: failed to retrieve sender key: %w", err)
+		}
 	}
 
 	ephemeralPubKey, ephemeralPrivKey, err := t.generateOrGetEphemeralOKPKey(epkPrv)
@@ -461,8 +525,22 @@ func (t *Crypto) derive1PUWithOKPKey(wrappingAlg string, apu, apv, tag []byte, s
 		base64.RawURLEncoding.Encode(apu, ephemeralPubKey)
 	}
 
-	kek, err := t.okpKW.deriveSender1Pu(wrappingAlg, apu, apv, tag, ephemeralPrivKey, senderPrivKey, recPubKey.X,
-		defKeySize)
+	var kek []byte
+
+	if isKMSSender {
+		var ze []byte
+
+		ze, err = curve25519.X25519(ephemeralPrivKey, recPubKey.X)
+		if err != nil {
+			return "", nil, nil, nil, fmt.Errorf("derive1PUWithOKPKey: %w", err)
+		}
+
+		kek, err = deriveKMSSender1Pu(wrappingAlg, apu, apv, tag, ze, kmsSender, recPubKey, chacha20poly1305.KeySize)
+	} else {
+		kek, err = t.okpKW.deriveSender1Pu(wrappingAlg, apu, apv, tag, ephemeralPrivKey, senderPrivKey, recPubKey.X,
+			defKeySize)
+	}
+
 	if err != nil {
 		return "", nil, nil, nil, fmt.Errorf("derive1PUWithOKPKey: failed to derive key: %w", err)
 	}
@@ -537,7 +615,7 @@ func (t *Crypto) deriveESWithOKPKey(apu, apv []byte, recPubKey *cryptoapi.Public
 }
 
 func (t *Crypto) deriveESWithOKPKeyForUnwrap(alg string, apu, apv []byte, epk *cryptoapi.PublicKey,
-	recipientPrivateKey interface{}) ([]byte, error) {
+	recipientPrivateKey interface{}, keySize int) ([]byte, error) {
 	recPrivOKPKey, ok := recipientPrivateKey.([]byte)
 	if !ok {
 		return nil, errors.New("deriveESWithOKPKeyForUnwrap: recipient key is not an OKP key")
@@ -554,7 +632,7 @@ func (t *Crypto) deriveESWithOKPKeyForUnwrap(alg string, apu, apv []byte, epk *c
 		return nil, fmt.Errorf("deriveESWithOKPKeyForUnwrap: %w", err)
 	}
 
-	return kdf(alg, z, apu, apv, chacha20poly1305.KeySize), nil
+	return kdf(alg, z, apu, apv, keySize), nil
 }
 
 // convertRecKeyAndGenOrGetEPKEC converts recPubKey into *ecdsa.PublicKey and generates an ephemeral EC private key
@@ -415,6 +415,30 @@ func Test_deriveKEKUnwrapFailureDueToExtractPrivKeyError(t *testing.T) {
 	require.EqualError(t, err, "deriveKEKAndUnwrap: extractPrivKey: invalid key: unsupported curve")
 }
 
+func Test_deriveDirectCEK_Failure(t *testing.T) {
+	c, err := New()
+	require.NoError(t, err)
+
+	recKH, err := keyset.NewHandle(ecdh.NISTP256ECDHKWKeyTemplate())
+	require.NoError(t, err)
+
+	recWK := &crypto.RecipientWrappedKey{Alg: ECDHESAlg}
+
+	_, err = c.UnwrapKey(recWK, recKH)
+	require.EqualError(t, err, "unwrapKey: deriveDirectCEK: content encryption algorithm and key size are required"+
+		" for 'ECDH-ES'")
+
+	recWK.EncryptedCEK = []byte("unexpected key")
+
+	_, err = c.UnwrapKey(recWK, recKH, crypto.WithDirectKeyAgreement("A256GCM", 32))
+	require.EqualError(t, err, "unwrapKey: deriveDirectCEK: encrypted key must be empty for 'ECDH-ES'")
+
+	recWK.EncryptedCEK = nil
+
+	_, err = c.UnwrapKey(recWK, "bad key handle", crypto.WithDirectKeyAgreement("A256GCM", 32))
+	require.EqualError(t, err, "unwrapKey: deriveDirectCEK: bad key handle format")
+}
+
 func Test_generateEphemeralOKPKey_Failure(t *testing.T) {
 	c := Crypto{
 		okpKW: &mockKeyWrapperSupport{
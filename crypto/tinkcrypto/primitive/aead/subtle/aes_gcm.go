@@ -0,0 +1,75 @@
+/*
+Copyright Gen Digital Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package subtle
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"errors"
+	"fmt"
+
+	"github.com/google/tink/go/subtle/random"
+)
+
+const (
+	// AESGCMIVSize is the IV size that this implementation supports.
+	AESGCMIVSize = 12
+	// AESGCMTagSize is the authentication tag size that this implementation supports.
+	AESGCMTagSize = 16
+)
+
+// AESGCM is an implementation of the AEAD interface with AES-GCM. Unlike Tink's AES-GCM, it supports 24 bytes
+// (AES-192) keys as required by the A192GCM JWE content encryption algorithm.
+type AESGCM struct {
+	aead cipher.AEAD
+}
+
+// NewAESGCM returns an AESGCM instance. The key argument should be the AES key, either 16, 24 or 32 bytes to select
+// AES-128, AES-192 or AES-256.
+func NewAESGCM(key []byte) (*AESGCM, error) {
+	if err := ValidateAESKeySize(uint32(len(key))); err != nil {
+		return nil, fmt.Errorf("aes_gcm: NewAESGCM() %w", err)
+	}
+
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, fmt.Errorf("aes_gcm: NewAESGCM() %w", err)
+	}
+
+	aead, err := cipher.NewGCM(block)
+	if err != nil {
+		return nil, fmt.Errorf("aes_gcm: NewAESGCM() %w", err)
+	}
+
+	return &AESGCM{aead: aead}, nil
+}
+
+// Encrypt encrypts plaintext with additionalData. The resulting ciphertext consists of the random IV, the actual
+// ciphertext and the authentication tag, the same format as Tink's AES-GCM.
+func (a *AESGCM) Encrypt(plaintext, additionalData []byte) ([]byte, error) {
+	if len(plaintext) > maxInt-AESGCMIVSize-AESGCMTagSize {
+		return nil, errors.New("aes_gcm: plaintext too long")
+	}
+
+	iv := random.GetRandomBytes(AESGCMIVSize)
+
+	return a.aead.Seal(iv, iv, plaintext, additionalData), nil
+}
+
+// Decrypt decrypts ciphertext produced by Encrypt with additionalData.
+func (a *AESGCM) Decrypt(ciphertext, additionalData []byte) ([]byte, error) {
+	if len(ciphertext) < AESGCMIVSize+AESGCMTagSize {
+		return nil, errors.New("aes_gcm: ciphertext too short")
+	}
+
+	plaintext, err := a.aead.Open(nil, ciphertext[:AESGCMIVSize], ciphertext[AESGCMIVSize:], additionalData)
+	if err != nil {
+		return nil, fmt.Errorf("aes_gcm: Decrypt() %w", err)
+	}
+
+	return plaintext, nil
+}
@@ -0,0 +1,86 @@
+/*
+Copyright Gen Digital Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package subtle_test
+
+import (
+	"fmt"
+	"testing"
+
+	tinksubtle "github.com/google/tink/go/aead/subtle"
+	"github.com/google/tink/go/subtle/random"
+	"github.com/stretchr/testify/require"
+
+	"github.com/dellekappa/kms-go/crypto/tinkcrypto/primitive/aead/subtle"
+)
+
+func TestNewAESGCM(t *testing.T) {
+	for i := 0; i < 40; i++ {
+		_, err := subtle.NewAESGCM(make([]byte, i))
+
+		switch i {
+		case 16, 24, 32:
+			require.NoError(t, err)
+		default:
+			require.EqualError(t, err, fmt.Sprintf("aes_gcm: NewAESGCM() invalid AES key size; want 16, 24 or 32, "+
+				"got %d", i))
+		}
+	}
+}
+
+func TestAESGCMEncryptDecrypt(t *testing.T) {
+	pt := []byte("some plaintext")
+	aad := []byte("some aad")
+
+	for _, keySize := range []uint32{16, 24, 32} {
+		t.Run(fmt.Sprintf("key size %d", keySize), func(t *testing.T) {
+			key := random.GetRandomBytes(keySize)
+
+			gcm, err := subtle.NewAESGCM(key)
+			require.NoError(t, err)
+
+			ct, err := gcm.Encrypt(pt, aad)
+			require.NoError(t, err)
+			require.Len(t, ct, subtle.AESGCMIVSize+len(pt)+subtle.AESGCMTagSize)
+
+			decrypted, err := gcm.Decrypt(ct, aad)
+			require.NoError(t, err)
+			require.Equal(t, pt, decrypted)
+
+			_, err = gcm.Decrypt(ct, []byte("other aad"))
+			require.EqualError(t, err, "aes_gcm: Decrypt() cipher: message authentication failed")
+
+			_, err = gcm.Decrypt(ct[:subtle.AESGCMIVSize+subtle.AESGCMTagSize-1], aad)
+			require.EqualError(t, err, "aes_gcm: ciphertext too short")
+		})
+	}
+
+	t.Run("interop with Tink AES-GCM", func(t *testing.T) {
+		for _, keySize := range []uint32{16, 32} {
+			key := random.GetRandomBytes(keySize)
+
+			gcm, err := subtle.NewAESGCM(key)
+			require.NoError(t, err)
+
+			tinkGCM, err := tinksubtle.NewAESGCM(key)
+			require.NoError(t, err)
+
+			ct, err := tinkGCM.Encrypt(pt, aad)
+			require.NoError(t, err)
+
+			decrypted, err := gcm.Decrypt(ct, aad)
+			require.NoError(t, err)
+			require.Equal(t, pt, decrypted)
+
+			ct, err = gcm.Encrypt(pt, aad)
+			require.NoError(t, err)
+
+			decrypted, err = tinkGCM.Decrypt(ct, aad)
+			require.NoError(t, err)
+			require.Equal(t, pt, decrypted)
+		}
+	})
+}
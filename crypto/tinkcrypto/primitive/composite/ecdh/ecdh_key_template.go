@@ -109,6 +109,7 @@ func X25519ECDHKWKeyTemplate() *tinkpb.KeyTemplate {
 // NIST P kw or XC20P kw
 // cek should be of size:
 // - 32 bytes for AES256GCM, XChacaha20Poly1305, AES128CBC+HMAC256.
+// - 16 or 24 bytes for AES256GCM to execute AES-GCM with a 128 or 192 bits key (JWE A128GCM and A192GCM).
 // - 48 bytes for AES192CBC+HMAC384.
 // - 56 bytes for AES256CBC+HMAC384.
 // - 64 bytes for AES256CBC+HMAC512.
@@ -185,6 +185,11 @@ func (r *RegisterCompositeAEADEncHelper) GetIVSize() int {
 
 // GetAEAD returns the AEAD primitive from the DEM.
 func (r *RegisterCompositeAEADEncHelper) GetAEAD(symmetricKeyValue []byte) (tink.AEAD, error) {
+	if r.encKeyURL == AESGCMTypeURL && len(symmetricKeyValue) == subtle.AES192Size {
+		// Tink's AES-GCM key manager rejects AES-192 keys, they are used by the A192GCM content encryption.
+		return subtle.NewAESGCM(symmetricKeyValue)
+	}
+
 	sk, err := r.getSerializedKey(symmetricKeyValue)
 	if err != nil {
 		return nil, err
@@ -192,6 +192,32 @@ func TestAead(t *testing.T) {
 	}
 }
 
+func TestAeadAES192GCM(t *testing.T) {
+	rEnc, err := NewRegisterCompositeAEADEncHelper(aead.AES256GCMKeyTemplate())
+	require.NoError(t, err)
+
+	pt := random.GetRandomBytes(20)
+	ad := random.GetRandomBytes(20)
+
+	a, err := rEnc.GetAEAD(random.GetRandomBytes(subtlecbchmacaead.AES192Size))
+	require.NoError(t, err)
+
+	ct, err := a.Encrypt(pt, ad)
+	require.NoError(t, err)
+
+	encData, err := rEnc.BuildEncData(ct)
+	require.NoError(t, err)
+
+	ed := new(EncryptedData)
+	require.NoError(t, json.Unmarshal(encData, ed))
+	require.Len(t, ed.IV, subtleaead.AESGCMIVSize)
+	require.Len(t, ed.Tag, subtleaead.AESGCMTagSize)
+
+	dt, err := a.Decrypt(rEnc.BuildDecData(ed), ad)
+	require.NoError(t, err)
+	require.Equal(t, pt, dt)
+}
+
 func TestBuildEncDecData(t *testing.T) {
 	rEnc, err := NewRegisterCompositeAEADEncHelper(aead.AES256GCMKeyTemplate())
 	require.NoError(t, err)
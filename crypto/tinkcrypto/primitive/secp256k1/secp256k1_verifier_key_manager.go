@@ -30,11 +30,13 @@ var (
 
 // secp256k1VerifierKeyManager is an implementation of KeyManager interface.
 // It doesn't support key generation.
-type secp256k1VerifierKeyManager struct{}
+type secp256k1VerifierKeyManager struct {
+	opts []subtle.VerifierOpt
+}
 
-// newSecp256K1VerifierKeyManager creates a new secp256k1VerifierKeyManager.
-func newSecp256K1VerifierKeyManager() *secp256k1VerifierKeyManager {
-	return new(secp256k1VerifierKeyManager)
+// newSecp256K1VerifierKeyManager creates a new secp256k1VerifierKeyManager, creating verifiers with opts.
+func newSecp256K1VerifierKeyManager(opts ...subtle.VerifierOpt) *secp256k1VerifierKeyManager {
+	return &secp256k1VerifierKeyManager{opts: opts}
 }
 
 // Primitive creates an secp256k1Verifier subtle for the given serialized secp256k1PublicKey proto.
@@ -54,7 +56,7 @@ func (km *secp256k1VerifierKeyManager) Primitive(serializedKey []byte) (interfac
 
 	hash, curve, encoding := getSecp256K1ParamNames(key.Params)
 
-	ret, err := subtle.NewSecp256K1Verifier(hash, curve, encoding, key.X, key.Y)
+	ret, err := subtle.NewSecp256K1Verifier(hash, curve, encoding, key.X, key.Y, km.opts...)
 	if err != nil {
 		return nil, fmt.Errorf("secp256k1_verifier_key_manager: invalid key: %w", err)
 	}
@@ -110,3 +110,20 @@ func GetCurve(curve string) elliptic.Curve {
 		return nil
 	}
 }
+
+// IsLowS reports whether s is at most half the order of curve. Both s and the order minus s make valid ECDSA
+// signatures, the low-S one is the canonical form required by Bitcoin (BIP 62) and Ethereum (EIP-2).
+func IsLowS(curve elliptic.Curve, s *big.Int) bool {
+	halfOrder := new(big.Int).Rsh(curve.Params().N, 1)
+
+	return s.Cmp(halfOrder) <= 0
+}
+
+// ToLowS returns the low-S form of s for curve, see IsLowS.
+func ToLowS(curve elliptic.Curve, s *big.Int) *big.Int {
+	if IsLowS(curve, s) {
+		return s
+	}
+
+	return new(big.Int).Sub(curve.Params().N, s)
+}
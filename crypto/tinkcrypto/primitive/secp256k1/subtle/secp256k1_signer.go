@@ -60,7 +60,7 @@ func NewSecp256K1SignerFromPrivateKey(hashAlg string, encoding string,
 	}, nil
 }
 
-// Sign computes a signature for the given data.
+// Sign computes a signature for the given data. Signatures are always low-S, see IsLowS.
 func (e *Secp256K1Signer) Sign(data []byte) ([]byte, error) {
 	hashed, err := subtle.ComputeHash(e.hashFunc, data)
 	if err != nil {
@@ -72,8 +72,8 @@ func (e *Secp256K1Signer) Sign(data []byte) ([]byte, error) {
 		return nil, fmt.Errorf("secp256k1_signer: signing failed: %w", err)
 	}
 
-	// format the signature
-	sig := NewSecp256K1Signature(r, s)
+	// format the signature, in its canonical low-S form.
+	sig := NewSecp256K1Signature(r, ToLowS(e.privateKey.Curve, s))
 
 	ret, err := sig.EncodeSecp256K1Signature(e.encoding, e.privateKey.PublicKey.Curve.Params().Name)
 	if err != nil {
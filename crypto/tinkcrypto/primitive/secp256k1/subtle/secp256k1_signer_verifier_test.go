@@ -9,6 +9,7 @@ package subtle_test
 import (
 	"crypto/ecdsa"
 	"crypto/rand"
+	"math/big"
 	"testing"
 
 	"github.com/google/tink/go/subtle/random"
@@ -54,3 +55,49 @@ func TestSignVerify(t *testing.T) {
 		require.NoError(t, err, "unexpected error when verifying")
 	}
 }
+
+func TestLowS(t *testing.T) {
+	data := random.GetRandomBytes(20)
+	hash := "SHA256"
+	curve := subtleSignature.GetCurve("SECP256K1")
+
+	for _, encoding := range []string{"Bitcoin_DER", "Bitcoin_IEEE_P1363"} {
+		priv, err := ecdsa.GenerateKey(curve, rand.Reader)
+		require.NoError(t, err)
+
+		signer, err := subtleSignature.NewSecp256K1SignerFromPrivateKey(hash, encoding, priv)
+		require.NoError(t, err)
+
+		verifier, err := subtleSignature.NewSecp256K1VerifierFromPublicKey(hash, encoding, &priv.PublicKey)
+		require.NoError(t, err)
+
+		legacyVerifier, err := subtleSignature.NewSecp256K1VerifierFromPublicKey(hash, encoding, &priv.PublicKey,
+			subtleSignature.EnforceLowS(false))
+		require.NoError(t, err)
+
+		// ecdsa.Sign returns high-S values half of the time, make sure they are all normalized.
+		for i := 0; i < 16; i++ {
+			lowSSig, e := signer.Sign(data)
+			require.NoError(t, e)
+
+			decoded, e := subtleSignature.DecodeSecp256K1Signature(lowSSig, encoding)
+			require.NoError(t, e)
+			require.True(t, subtleSignature.IsLowS(curve, decoded.S), encoding)
+
+			require.NoError(t, verifier.Verify(lowSSig, data))
+			require.NoError(t, legacyVerifier.Verify(lowSSig, data))
+
+			// (r, n - s) is a valid signature too, in its non canonical form.
+			highS := new(big.Int).Sub(curve.Params().N, decoded.S)
+			require.False(t, subtleSignature.IsLowS(curve, highS))
+			require.Equal(t, decoded.S, subtleSignature.ToLowS(curve, highS))
+
+			highSSig, e := subtleSignature.NewSecp256K1Signature(decoded.R, highS).
+				EncodeSecp256K1Signature(encoding, curve.Params().Name)
+			require.NoError(t, e)
+
+			require.ErrorIs(t, verifier.Verify(highSSig, data), subtleSignature.ErrNonCanonicalSignature)
+			require.NoError(t, legacyVerifier.Verify(highSSig, data))
+		}
+	}
+}
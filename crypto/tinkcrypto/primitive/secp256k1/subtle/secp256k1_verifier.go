@@ -18,27 +18,49 @@ import (
 
 var errInvalidSecp256K1Signature = errors.New("secp256k1_verifier: invalid signature")
 
+// ErrNonCanonicalSignature is returned by ECDSAVerifier.Verify for high-S signatures, whose S value exceeds half the
+// curve order, unless EnforceLowS(false) is set.
+var ErrNonCanonicalSignature = errors.New("secp256k1_verifier: non canonical high-S signature")
+
+// verifierOpts holds options for the ECDSAVerifier.
+type verifierOpts struct {
+	enforceLowS bool
+}
+
+// VerifierOpt is the ECDSAVerifier option.
+type VerifierOpt func(opts *verifierOpts)
+
+// EnforceLowS option sets whether high-S signatures are rejected with ErrNonCanonicalSignature, as required for
+// Ethereum interoperability. It defaults to true, set it to false to accept legacy high-S signatures.
+func EnforceLowS(enforce bool) VerifierOpt {
+	return func(opts *verifierOpts) {
+		opts.enforceLowS = enforce
+	}
+}
+
 // ECDSAVerifier is an implementation of Verifier for ECDSA.
 // At the moment, the implementation only accepts signatures with strict DER encoding.
 type ECDSAVerifier struct {
-	publicKey *ecdsa.PublicKey
-	hashFunc  func() hash.Hash
-	encoding  string
+	publicKey   *ecdsa.PublicKey
+	hashFunc    func() hash.Hash
+	encoding    string
+	enforceLowS bool
 }
 
 // NewSecp256K1Verifier creates a new instance of Secp256K1Verifier.
-func NewSecp256K1Verifier(hashAlg, curve, encoding string, x, y []byte) (*ECDSAVerifier, error) {
+func NewSecp256K1Verifier(hashAlg, curve, encoding string, x, y []byte, opts ...VerifierOpt) (*ECDSAVerifier, error) {
 	publicKey := &ecdsa.PublicKey{
 		Curve: GetCurve(curve),
 		X:     new(big.Int).SetBytes(x),
 		Y:     new(big.Int).SetBytes(y),
 	}
 
-	return NewSecp256K1VerifierFromPublicKey(hashAlg, encoding, publicKey)
+	return NewSecp256K1VerifierFromPublicKey(hashAlg, encoding, publicKey, opts...)
 }
 
 // NewSecp256K1VerifierFromPublicKey creates a new instance of ECDSAVerifier.
-func NewSecp256K1VerifierFromPublicKey(hashAlg, encoding string, publicKey *ecdsa.PublicKey) (*ECDSAVerifier, error) {
+func NewSecp256K1VerifierFromPublicKey(hashAlg, encoding string, publicKey *ecdsa.PublicKey,
+	opts ...VerifierOpt) (*ECDSAVerifier, error) {
 	if publicKey.Curve == nil {
 		return nil, errors.New("ecdsa_verifier: invalid curve")
 	}
@@ -48,12 +70,19 @@ func NewSecp256K1VerifierFromPublicKey(hashAlg, encoding string, publicKey *ecds
 		return nil, fmt.Errorf("ecdsa_verifier: %w", err)
 	}
 
+	vOpts := &verifierOpts{enforceLowS: true}
+
+	for _, opt := range opts {
+		opt(vOpts)
+	}
+
 	hashFunc := subtle.GetHashFunc(hashAlg)
 
 	return &ECDSAVerifier{
-		publicKey: publicKey,
-		hashFunc:  hashFunc,
-		encoding:  encoding,
+		publicKey:   publicKey,
+		hashFunc:    hashFunc,
+		encoding:    encoding,
+		enforceLowS: vOpts.enforceLowS,
 	}, nil
 }
 
@@ -65,6 +94,10 @@ func (e *ECDSAVerifier) Verify(signatureBytes, data []byte) error {
 		return fmt.Errorf("secp256k1_verifier: %w", err)
 	}
 
+	if e.enforceLowS && !IsLowS(e.publicKey.Curve, signature.S) {
+		return ErrNonCanonicalSignature
+	}
+
 	hashed, err := subtle.ComputeHash(e.hashFunc, data)
 	if err != nil {
 		return err
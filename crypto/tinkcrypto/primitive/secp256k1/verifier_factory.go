@@ -16,11 +16,20 @@ import (
 	"github.com/google/tink/go/keyset"
 	tinkpb "github.com/google/tink/go/proto/tink_go_proto"
 	"github.com/google/tink/go/tink"
+
+	"github.com/dellekappa/kms-go/crypto/tinkcrypto/primitive/secp256k1/subtle"
 )
 
-// NewVerifier returns a Verifier primitive from the given keyset handle.
-func NewVerifier(h *keyset.Handle) (tink.Verifier, error) {
-	return NewVerifierWithKeyManager(h, nil /*keyManager*/)
+// NewVerifier returns a Verifier primitive from the given keyset handle. The secp256k1 verifiers are created with opts,
+// e.g. subtle.EnforceLowS(false) to accept high-S signatures.
+func NewVerifier(h *keyset.Handle, opts ...subtle.VerifierOpt) (tink.Verifier, error) {
+	var km registry.KeyManager
+
+	if len(opts) > 0 {
+		km = newSecp256K1VerifierKeyManager(opts...)
+	}
+
+	return NewVerifierWithKeyManager(h, km)
 }
 
 // NewVerifierWithKeyManager returns a Verifier primitive from the given keyset handle and custom key manager.
@@ -64,9 +73,12 @@ func newWrappedVerifier(ps *primitiveset.PrimitiveSet) (*wrappedVerifier, error)
 
 var errInvalidSignature = errors.New("verifier_factory: invalid signature")
 
-// Verify checks whether the given signature is a valid signature of the given data.
+// Verify checks whether the given signature is a valid signature of the given data. It returns
+// subtle.ErrNonCanonicalSignature if a verifier rejected a high-S signature, errInvalidSignature otherwise.
 // nolint:gocyclo
 func (v *wrappedVerifier) Verify(signature, data []byte) error {
+	verifyErr := errInvalidSignature
+
 	prefixSize := cryptofmt.NonRawPrefixSize
 	if len(signature) < prefixSize {
 		return errInvalidSignature
@@ -94,6 +106,8 @@ func (v *wrappedVerifier) Verify(signature, data []byte) error {
 			if err = verifier.Verify(signatureNoPrefix, signedData); err == nil {
 				return nil
 			}
+
+			verifyErr = nonCanonicalOr(err, verifyErr)
 		}
 	}
 
@@ -109,8 +123,19 @@ func (v *wrappedVerifier) Verify(signature, data []byte) error {
 			if err = verifier.Verify(signature, data); err == nil {
 				return nil
 			}
+
+			verifyErr = nonCanonicalOr(err, verifyErr)
 		}
 	}
 
-	return errInvalidSignature
+	return verifyErr
+}
+
+// nonCanonicalOr returns err if it is subtle.ErrNonCanonicalSignature, otherwise verifyErr.
+func nonCanonicalOr(err, verifyErr error) error {
+	if errors.Is(err, subtle.ErrNonCanonicalSignature) {
+		return err
+	}
+
+	return verifyErr
 }
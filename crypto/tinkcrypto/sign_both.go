@@ -0,0 +1,66 @@
+/*
+Copyright Gen Digital Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package tinkcrypto
+
+import (
+	"crypto/ecdsa"
+	"encoding/asn1"
+	"errors"
+	"fmt"
+	"math/big"
+
+	"github.com/google/tink/go/keyset"
+)
+
+// SignBoth signs msg once with the ECDSA private key referenced by kh (NIST P or secp256k1 curves), digesting msg
+// with the hash set in the key's parameters, and returns the signature encoded both as IEEE P1363 (r || s) and as
+// DER, regardless of the encoding set in the key's parameters. Unlike two calls to Sign, the two encodings are
+// guaranteed to hold the same r and s values. Other keys return ErrNotECDSAKey.
+func (t *Crypto) SignBoth(msg []byte, kh interface{}) ([]byte, []byte, error) {
+	keyHandle, ok := kh.(*keyset.Handle)
+	if !ok {
+		return nil, nil, errBadKeyHandleFormat
+	}
+
+	key, err := ecdsaKeyFromHandle(keyHandle)
+	if err != nil {
+		return nil, nil, fmt.Errorf("signBoth: %w", err)
+	}
+
+	if key.hash == 0 {
+		return nil, nil, errors.New("signBoth: unsupported key hash type")
+	}
+
+	digest, err := ecdsaDigest(msg, key.hash)
+	if err != nil {
+		return nil, nil, fmt.Errorf("signBoth: %w", err)
+	}
+
+	r, s, err := key.sign(digest)
+	if err != nil {
+		return nil, nil, fmt.Errorf("signBoth: %w", err)
+	}
+
+	der, err := asn1.Marshal(struct{ R, S *big.Int }{R: r, S: s})
+	if err != nil {
+		return nil, nil, fmt.Errorf("signBoth: encode DER signature: %w", err)
+	}
+
+	return encodeIEEEP1363(r, s, key.pub), der, nil
+}
+
+// encodeIEEEP1363 returns the IEEE P1363 encoding of the r and s values of a signature made with pub's curve: their
+// big-endian values padded to the curve size, concatenated.
+func encodeIEEEP1363(r, s *big.Int, pub *ecdsa.PublicKey) []byte {
+	size := (pub.Curve.Params().BitSize + 7) / 8 //nolint:gomnd
+
+	sig := make([]byte, 2*size) //nolint:gomnd
+	r.FillBytes(sig[:size])
+	s.FillBytes(sig[size:])
+
+	return sig
+}
@@ -0,0 +1,87 @@
+/*
+Copyright Gen Digital Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package tinkcrypto
+
+import (
+	"crypto"
+	"encoding/asn1"
+	"math/big"
+	"testing"
+
+	"github.com/google/tink/go/keyset"
+	tinkpb "github.com/google/tink/go/proto/tink_go_proto"
+	"github.com/google/tink/go/signature"
+	"github.com/stretchr/testify/require"
+
+	"github.com/dellekappa/kms-go/crypto/tinkcrypto/primitive/secp256k1"
+)
+
+func TestCrypto_SignBoth(t *testing.T) {
+	secp256k1DER, err := secp256k1.DERKeyTemplate()
+	require.NoError(t, err)
+
+	c := Crypto{}
+	msg := []byte(testMessage)
+
+	tests := []struct {
+		name     string
+		template *tinkpb.KeyTemplate
+		hash     crypto.Hash
+		size     int
+		ieee     bool
+	}{
+		{name: "P-256 DER", template: signature.ECDSAP256KeyWithoutPrefixTemplate(), hash: crypto.SHA256, size: 32},
+		{name: "P-521 DER", template: signature.ECDSAP521KeyWithoutPrefixTemplate(), hash: crypto.SHA512, size: 66},
+		{name: "P-384 IEEE P1363", template: ecdsaIEEEP1363KeyTemplate(t), hash: crypto.SHA384, size: 48, ieee: true},
+		{name: "secp256k1 DER", template: secp256k1DER, hash: crypto.SHA256, size: 32},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			kh, err := keyset.NewHandle(tc.template)
+			require.NoError(t, err)
+
+			pubKH, err := kh.Public()
+			require.NoError(t, err)
+
+			p1363, der, err := c.SignBoth(msg, kh)
+			require.NoError(t, err)
+			require.Len(t, p1363, 2*tc.size)
+
+			var derSig struct{ R, S *big.Int }
+
+			_, err = asn1.Unmarshal(der, &derSig)
+			require.NoError(t, err)
+
+			// both encodings hold the same signature.
+			require.Equal(t, derSig.R.FillBytes(make([]byte, tc.size)), p1363[:tc.size])
+			require.Equal(t, derSig.S.FillBytes(make([]byte, tc.size)), p1363[tc.size:])
+
+			// the encoding of the key's parameters verifies as a signature of the key.
+			keySig := der
+			if tc.ieee {
+				keySig = p1363
+			}
+
+			require.NoError(t, c.VerifyECDSA(keySig, msg, pubKH, tc.hash))
+
+			_, _, err = c.SignBoth(msg, pubKH)
+			require.ErrorIs(t, err, ErrNotECDSAKey)
+		})
+	}
+
+	t.Run("not an ECDSA key", func(t *testing.T) {
+		kh, err := keyset.NewHandle(signature.ED25519KeyWithoutPrefixTemplate())
+		require.NoError(t, err)
+
+		_, _, err = c.SignBoth(msg, kh)
+		require.ErrorIs(t, err, ErrNotECDSAKey)
+
+		_, _, err = c.SignBoth(msg, "not a key handle")
+		require.ErrorIs(t, err, errBadKeyHandleFormat)
+	})
+}
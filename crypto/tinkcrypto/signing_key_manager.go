@@ -0,0 +1,100 @@
+/*
+Copyright Gen Digital Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package tinkcrypto
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/google/tink/go/keyset"
+	tinkpb "github.com/google/tink/go/proto/tink_go_proto"
+	"google.golang.org/protobuf/proto"
+)
+
+var errSigningKeyManagerNewKey = errors.New("signing_key_manager: keys are created by the Tink signature key managers")
+
+// signingKeyManager is a registry.KeyManager creating the primitives of the SignEd25519, SignECDSA, SignDigest and
+// SignBoth operations, and of their verification counterparts, for the Tink keys of typeURL. Like the Tink signature
+// key managers, it reads the key material only to create the primitive, the key is never exported from the keyset
+// handle. It doesn't create keys, they are created by the key managers registered for typeURL.
+type signingKeyManager struct {
+	typeURL   string
+	primitive func(serializedKey []byte) (interface{}, error)
+}
+
+// signingKeyManagers are the signing key managers by type URL.
+//
+//nolint:gochecknoglobals
+var signingKeyManagers = map[string]*signingKeyManager{
+	ed25519PrivateKeyTypeURL:   {typeURL: ed25519PrivateKeyTypeURL, primitive: ed25519PrivateKeyPrimitive},
+	ed25519PublicKeyTypeURL:    {typeURL: ed25519PublicKeyTypeURL, primitive: ed25519PublicKeyPrimitive},
+	ecdsaPrivateKeyTypeURL:     {typeURL: ecdsaPrivateKeyTypeURL, primitive: nistPECDSAPrivateKeyPrimitive},
+	ecdsaPublicKeyTypeURL:      {typeURL: ecdsaPublicKeyTypeURL, primitive: nistPECDSAPublicKeyPrimitive},
+	secp256k1PrivateKeyTypeURL: {typeURL: secp256k1PrivateKeyTypeURL, primitive: secp256k1PrivateKeyPrimitive},
+	secp256k1PublicKeyTypeURL:  {typeURL: secp256k1PublicKeyTypeURL, primitive: secp256k1PublicKeyPrimitive},
+}
+
+// Primitive creates the primitive of the given serialized key.
+func (km *signingKeyManager) Primitive(serializedKey []byte) (interface{}, error) {
+	if len(serializedKey) == 0 {
+		return nil, errors.New("signing_key_manager: invalid key")
+	}
+
+	return km.primitive(serializedKey)
+}
+
+// NewKey is not supported.
+func (km *signingKeyManager) NewKey(_ []byte) (proto.Message, error) {
+	return nil, errSigningKeyManagerNewKey
+}
+
+// NewKeyData is not supported.
+func (km *signingKeyManager) NewKeyData(_ []byte) (*tinkpb.KeyData, error) {
+	return nil, errSigningKeyManagerNewKey
+}
+
+// DoesSupport indicates if this key manager supports the given key type.
+func (km *signingKeyManager) DoesSupport(typeURL string) bool {
+	return typeURL == km.typeURL
+}
+
+// TypeURL returns the key type of keys managed by this key manager.
+func (km *signingKeyManager) TypeURL() string {
+	return km.typeURL
+}
+
+// primaryKeyTypeURL returns the type URL of the primary key of kh, read from the keyset info without key material.
+func primaryKeyTypeURL(kh *keyset.Handle) (string, error) {
+	info := kh.KeysetInfo()
+
+	for _, key := range info.GetKeyInfo() {
+		if key.GetKeyId() == info.GetPrimaryKeyId() {
+			return key.GetTypeUrl(), nil
+		}
+	}
+
+	return "", errors.New("keyset has no primary key")
+}
+
+// signingPrimitive returns the signing primitive of the primary key of kh, whose type URL is typeURL.
+func signingPrimitive(kh *keyset.Handle, typeURL string) (interface{}, error) {
+	km, ok := signingKeyManagers[typeURL]
+	if !ok {
+		return nil, fmt.Errorf("unsupported key type '%s'", typeURL)
+	}
+
+	ps, err := kh.PrimitivesWithKeyManager(km)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read keyset: %w", err)
+	}
+
+	if ps.Primary == nil {
+		return nil, errors.New("keyset has no primary key")
+	}
+
+	return ps.Primary.Primitive, nil
+}
@@ -0,0 +1,78 @@
+/*
+Copyright Gen Digital Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package tinkcrypto
+
+import (
+	"testing"
+
+	"github.com/google/tink/go/aead"
+	"github.com/google/tink/go/keyset"
+	"github.com/google/tink/go/signature"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSigningKeyManager(t *testing.T) {
+	t.Run("primary key type URL from the keyset info", func(t *testing.T) {
+		kh, err := keyset.NewHandle(signature.ED25519KeyWithoutPrefixTemplate())
+		require.NoError(t, err)
+
+		typeURL, err := primaryKeyTypeURL(kh)
+		require.NoError(t, err)
+		require.Equal(t, ed25519PrivateKeyTypeURL, typeURL)
+
+		pubKH, err := kh.Public()
+		require.NoError(t, err)
+
+		typeURL, err = primaryKeyTypeURL(pubKH)
+		require.NoError(t, err)
+		require.Equal(t, ed25519PublicKeyTypeURL, typeURL)
+	})
+
+	t.Run("signing primitives", func(t *testing.T) {
+		kh, err := keyset.NewHandle(signature.ED25519KeyWithoutPrefixTemplate())
+		require.NoError(t, err)
+
+		primitive, err := signingPrimitive(kh, ed25519PrivateKeyTypeURL)
+		require.NoError(t, err)
+		require.IsType(t, &ed25519Key{}, primitive)
+
+		pubKH, err := kh.Public()
+		require.NoError(t, err)
+
+		primitive, err = signingPrimitive(pubKH, ed25519PublicKeyTypeURL)
+		require.NoError(t, err)
+		require.Nil(t, primitive.(*ed25519Key).priv)
+
+		aeadKH, err := keyset.NewHandle(aead.AES256GCMKeyTemplate())
+		require.NoError(t, err)
+
+		typeURL, err := primaryKeyTypeURL(aeadKH)
+		require.NoError(t, err)
+
+		_, err = signingPrimitive(aeadKH, typeURL)
+		require.EqualError(t, err, "unsupported key type '"+typeURL+"'")
+	})
+
+	t.Run("key managers don't create keys", func(t *testing.T) {
+		km := signingKeyManagers[ecdsaPrivateKeyTypeURL]
+		require.True(t, km.DoesSupport(ecdsaPrivateKeyTypeURL))
+		require.False(t, km.DoesSupport(ecdsaPublicKeyTypeURL))
+		require.Equal(t, ecdsaPrivateKeyTypeURL, km.TypeURL())
+
+		_, err := km.NewKey(nil)
+		require.ErrorIs(t, err, errSigningKeyManagerNewKey)
+
+		_, err = km.NewKeyData(nil)
+		require.ErrorIs(t, err, errSigningKeyManagerNewKey)
+
+		_, err = km.Primitive(nil)
+		require.EqualError(t, err, "signing_key_manager: invalid key")
+
+		_, err = km.Primitive([]byte("invalid"))
+		require.EqualError(t, err, "invalid key in keyset")
+	})
+}
@@ -319,6 +319,14 @@ func derive1Pu(kwAlg string, ze, zs, apu, apv, tag []byte, keySize int) []byte {
 	return kdfWithTag(kwAlg, z, apu, apv, tag, keySize, true)
 }
 
+// ConcatKDF derives a key of keySize bytes from the ECDH shared secret z with the Concat KDF of ECDH-ES and ECDH-1PU key
+// agreement (https://tools.ietf.org/html/rfc7518#section-4.6.2), alg being the AlgorithmID (the "enc" header for
+// Direct Key Agreement). A non nil tag is appended to SuppPubInfo as done by ECDH-1PU key wrapping:
+// https://datatracker.ietf.org/doc/html/draft-madden-jose-ecdh-1pu-04#section-2.3.
+func ConcatKDF(alg string, z, apu, apv, tag []byte, keySize int) []byte {
+	return kdfWithTag(alg, z, apu, apv, tag, keySize, tag != nil)
+}
+
 func kdf(kwAlg string, z, apu, apv []byte, keySize int) []byte {
 	return kdfWithTag(kwAlg, z, apu, apv, nil, keySize, false)
 }
@@ -0,0 +1,153 @@
+/*
+Copyright Gen Digital Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package jose
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"encoding/json"
+	"errors"
+	"fmt"
+
+	"github.com/dellekappa/kms-go/crypto/tinkcrypto/primitive/composite"
+)
+
+// AEADProvider is a content encryption AEAD bound to a CEK, to encrypt and decrypt the content of JWEs with a custom
+// implementation, e.g. a FIPS validated module, see WithEncryptAEADProvider and WithDecryptAEADProvider.
+type AEADProvider interface {
+	// Seal encrypts and authenticates plaintext and authenticates aad, it returns the ciphertext followed by the
+	// Overhead() bytes authentication tag.
+	Seal(nonce, plaintext, aad []byte) ([]byte, error)
+	// Open authenticates and decrypts ciphertext, the output of Seal, and authenticates aad.
+	Open(nonce, ciphertext, aad []byte) ([]byte, error)
+	// NonceSize is the size of the nonces of Seal and Open, the JWE "iv".
+	NonceSize() int
+	// Overhead is the size of the authentication tag, the JWE "tag".
+	Overhead() int
+	// KeySize is the size of the CEK.
+	KeySize() int
+}
+
+// NewAEADProviderFunc creates the AEADProvider of a CEK.
+type NewAEADProviderFunc func(cek []byte) (AEADProvider, error)
+
+// NewAESGCMProvider creates an AEADProvider with the Go standard library AES-GCM of cek (A128GCM, A192GCM and
+// A256GCM), e.g. to wrap it in a custom provider.
+func NewAESGCMProvider(cek []byte) (AEADProvider, error) {
+	block, err := aes.NewCipher(cek)
+	if err != nil {
+		return nil, fmt.Errorf("newAESGCMProvider: %w", err)
+	}
+
+	aead, err := cipher.NewGCM(block)
+	if err != nil {
+		return nil, fmt.Errorf("newAESGCMProvider: %w", err)
+	}
+
+	return &stdAEADProvider{aead: aead, keySize: len(cek)}, nil
+}
+
+// stdAEADProvider is an AEADProvider of a cipher.AEAD.
+type stdAEADProvider struct {
+	aead    cipher.AEAD
+	keySize int
+}
+
+func (p *stdAEADProvider) Seal(nonce, plaintext, aad []byte) ([]byte, error) {
+	if len(nonce) != p.aead.NonceSize() {
+		return nil, fmt.Errorf("invalid nonce size %d, expected %d", len(nonce), p.aead.NonceSize())
+	}
+
+	return p.aead.Seal(nil, nonce, plaintext, aad), nil
+}
+
+func (p *stdAEADProvider) Open(nonce, ciphertext, aad []byte) ([]byte, error) {
+	if len(nonce) != p.aead.NonceSize() {
+		return nil, fmt.Errorf("invalid nonce size %d, expected %d", len(nonce), p.aead.NonceSize())
+	}
+
+	return p.aead.Open(nil, nonce, ciphertext, aad)
+}
+
+func (p *stdAEADProvider) NonceSize() int {
+	return p.aead.NonceSize()
+}
+
+func (p *stdAEADProvider) Overhead() int {
+	return p.aead.Overhead()
+}
+
+func (p *stdAEADProvider) KeySize() int {
+	return p.keySize
+}
+
+// aeadProviderPrimitive is the content encryption primitive of an AEADProvider, it encrypts to and decrypts from
+// serialized composite.EncryptedData as the ECDH primitives do.
+type aeadProviderPrimitive struct {
+	provider AEADProvider
+}
+
+// newAEADProviderPrimitive creates the AEADProvider of cek with newProvider.
+func newAEADProviderPrimitive(newProvider NewAEADProviderFunc, cek []byte) (*aeadProviderPrimitive, error) {
+	provider, err := newProvider(cek)
+	if err != nil {
+		return nil, fmt.Errorf("new AEAD provider: %w", err)
+	}
+
+	if provider.KeySize() != len(cek) {
+		return nil, fmt.Errorf("AEAD provider key size %d does not match the %d bytes CEK", provider.KeySize(),
+			len(cek))
+	}
+
+	return &aeadProviderPrimitive{provider: provider}, nil
+}
+
+func (p *aeadProviderPrimitive) Encrypt(plaintext, aad []byte) ([]byte, error) {
+	nonce := make([]byte, p.provider.NonceSize())
+
+	_, err := rand.Read(nonce)
+	if err != nil {
+		return nil, fmt.Errorf("generate nonce: %w", err)
+	}
+
+	sealed, err := p.provider.Seal(nonce, plaintext, aad)
+	if err != nil {
+		return nil, err
+	}
+
+	tagOffset := len(sealed) - p.provider.Overhead()
+	if tagOffset < 0 {
+		return nil, errors.New("AEAD provider output is shorter than its overhead")
+	}
+
+	return json.Marshal(&composite.EncryptedData{
+		Ciphertext: sealed[:tagOffset],
+		IV:         nonce,
+		Tag:        sealed[tagOffset:],
+	})
+}
+
+func (p *aeadProviderPrimitive) Decrypt(cipherText, aad []byte) ([]byte, error) {
+	encData := &composite.EncryptedData{}
+
+	err := json.Unmarshal(cipherText, encData)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(encData.IV) != p.provider.NonceSize() || len(encData.Tag) != p.provider.Overhead() {
+		return nil, fmt.Errorf("invalid iv or tag size: got %d and %d bytes, expected %d and %d bytes",
+			len(encData.IV), len(encData.Tag), p.provider.NonceSize(), p.provider.Overhead())
+	}
+
+	sealed := make([]byte, 0, len(encData.Ciphertext)+len(encData.Tag))
+	sealed = append(sealed, encData.Ciphertext...)
+	sealed = append(sealed, encData.Tag...)
+
+	return p.provider.Open(encData.IV, sealed, aad)
+}
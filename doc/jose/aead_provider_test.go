@@ -0,0 +1,181 @@
+/*
+Copyright Gen Digital Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package jose_test
+
+import (
+	"encoding/json"
+	"errors"
+	"sync/atomic"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	ariesjose "github.com/dellekappa/kms-go/doc/jose"
+	resolver "github.com/dellekappa/kms-go/doc/jose/kidresolver"
+)
+
+// countingAEADProvider counts the Seal and Open calls of the wrapped AEADProvider.
+type countingAEADProvider struct {
+	ariesjose.AEADProvider
+	seals, opens *atomic.Int32
+	keySize      int
+}
+
+func (p *countingAEADProvider) Seal(nonce, plaintext, aad []byte) ([]byte, error) {
+	p.seals.Add(1)
+
+	return p.AEADProvider.Seal(nonce, plaintext, aad)
+}
+
+func (p *countingAEADProvider) Open(nonce, ciphertext, aad []byte) ([]byte, error) {
+	p.opens.Add(1)
+
+	return p.AEADProvider.Open(nonce, ciphertext, aad)
+}
+
+func (p *countingAEADProvider) KeySize() int {
+	if p.keySize != 0 {
+		return p.keySize
+	}
+
+	return p.AEADProvider.KeySize()
+}
+
+func TestAEADProvider(t *testing.T) {
+	recipients, recKHs, _, _ := createRecipients(t, 2)
+	cryptoSvc, kmsSvc := createCryptoAndKMSServices(t, recKHs)
+
+	var seals, opens atomic.Int32
+
+	newProvider := func(cek []byte) (ariesjose.AEADProvider, error) {
+		provider, err := ariesjose.NewAESGCMProvider(cek)
+		if err != nil {
+			return nil, err
+		}
+
+		return &countingAEADProvider{AEADProvider: provider, seals: &seals, opens: &opens}, nil
+	}
+
+	pt := []byte("secret message")
+
+	encryptDecrypt := func(t *testing.T, encOpts []ariesjose.JWEEncryptOpt,
+		decOpts []ariesjose.JWEDecryptOpt) ([]byte, error) {
+		t.Helper()
+
+		jweEncrypter, err := ariesjose.NewJWEEncrypt(ariesjose.A256GCM, EnvelopeEncodingType,
+			DIDCommContentEncodingType, "", nil, recipients, cryptoSvc, encOpts...)
+		require.NoError(t, err)
+
+		jwe, err := jweEncrypter.EncryptWithAuthData(pt, []byte("aad"))
+		require.NoError(t, err)
+
+		serializedJWE, err := jwe.FullSerialize(json.Marshal)
+		require.NoError(t, err)
+
+		localJWE, err := ariesjose.Deserialize(serializedJWE)
+		require.NoError(t, err)
+
+		return ariesjose.NewJWEDecrypt(nil, cryptoSvc, kmsSvc, decOpts...).Decrypt(localJWE)
+	}
+
+	t.Run("encrypt and decrypt with the provider", func(t *testing.T) {
+		seals.Store(0)
+		opens.Store(0)
+
+		msg, err := encryptDecrypt(t,
+			[]ariesjose.JWEEncryptOpt{ariesjose.WithEncryptAEADProvider(ariesjose.A256GCM, newProvider)},
+			[]ariesjose.JWEDecryptOpt{ariesjose.WithDecryptAEADProvider(ariesjose.A256GCM, newProvider)})
+		require.NoError(t, err)
+		require.Equal(t, pt, msg)
+		require.EqualValues(t, 1, seals.Load())
+		require.EqualValues(t, 1, opens.Load())
+	})
+
+	t.Run("interoperable with the built-in implementation", func(t *testing.T) {
+		seals.Store(0)
+		opens.Store(0)
+
+		msg, err := encryptDecrypt(t,
+			[]ariesjose.JWEEncryptOpt{ariesjose.WithEncryptAEADProvider(ariesjose.A256GCM, newProvider)}, nil)
+		require.NoError(t, err)
+		require.Equal(t, pt, msg)
+
+		msg, err = encryptDecrypt(t, nil,
+			[]ariesjose.JWEDecryptOpt{ariesjose.WithDecryptAEADProvider(ariesjose.A256GCM, newProvider)})
+		require.NoError(t, err)
+		require.Equal(t, pt, msg)
+		require.EqualValues(t, 1, seals.Load())
+		require.EqualValues(t, 1, opens.Load())
+	})
+
+	t.Run("provider of another enc is not used", func(t *testing.T) {
+		seals.Store(0)
+
+		msg, err := encryptDecrypt(t,
+			[]ariesjose.JWEEncryptOpt{ariesjose.WithEncryptAEADProvider(ariesjose.A128GCM, newProvider)}, nil)
+		require.NoError(t, err)
+		require.Equal(t, pt, msg)
+		require.Zero(t, seals.Load())
+	})
+
+	t.Run("direct key agreement", func(t *testing.T) {
+		seals.Store(0)
+
+		jweEncrypter, err := ariesjose.NewJWEEncrypt(ariesjose.A256GCM, EnvelopeEncodingType,
+			DIDCommContentEncodingType, "", nil, recipients[:1], cryptoSvc, ariesjose.WithDirectKeyAgreement(),
+			ariesjose.WithEncryptAEADProvider(ariesjose.A256GCM, newProvider))
+		require.NoError(t, err)
+
+		jwe, err := jweEncrypter.Encrypt(pt)
+		require.NoError(t, err)
+		require.EqualValues(t, 1, seals.Load())
+
+		serializedJWE, err := jwe.CompactSerialize(json.Marshal)
+		require.NoError(t, err)
+
+		localJWE, err := ariesjose.Deserialize(serializedJWE)
+		require.NoError(t, err)
+
+		msg, err := ariesjose.NewJWEDecrypt([]resolver.KIDResolver{}, cryptoSvc, kmsSvc).Decrypt(localJWE)
+		require.NoError(t, err)
+		require.Equal(t, pt, msg)
+	})
+
+	t.Run("failures", func(t *testing.T) {
+		badKeySize := func(cek []byte) (ariesjose.AEADProvider, error) {
+			provider, err := ariesjose.NewAESGCMProvider(cek)
+			if err != nil {
+				return nil, err
+			}
+
+			return &countingAEADProvider{AEADProvider: provider, seals: &seals, opens: &opens, keySize: 16}, nil
+		}
+
+		jweEncrypter, err := ariesjose.NewJWEEncrypt(ariesjose.A256GCM, EnvelopeEncodingType,
+			DIDCommContentEncodingType, "", nil, recipients, cryptoSvc,
+			ariesjose.WithEncryptAEADProvider(ariesjose.A256GCM, badKeySize))
+		require.NoError(t, err)
+
+		_, err = jweEncrypter.Encrypt(pt)
+		require.EqualError(t, err, "jweencrypt: failed to get encryption primitive: AEAD provider key size 16 "+
+			"does not match the 32 bytes CEK")
+
+		jweEncrypter, err = ariesjose.NewJWEEncrypt(ariesjose.A256GCM, EnvelopeEncodingType,
+			DIDCommContentEncodingType, "", nil, recipients, cryptoSvc,
+			ariesjose.WithEncryptAEADProvider(ariesjose.A256GCM, func([]byte) (ariesjose.AEADProvider, error) {
+				return nil, errors.New("module failure")
+			}))
+		require.NoError(t, err)
+
+		_, err = jweEncrypter.Encrypt(pt)
+		require.EqualError(t, err, "jweencrypt: failed to get encryption primitive: new AEAD provider: "+
+			"module failure")
+	})
+
+	_, err := ariesjose.NewAESGCMProvider(make([]byte, 20))
+	require.EqualError(t, err, "newAESGCMProvider: crypto/aes: invalid key size 20")
+}
@@ -80,8 +80,15 @@ const (
 
 	// HeaderEPK is used by JWE applications to wrap/unwrap the CEK for a recipient.
 	HeaderEPK = "epk" // JSON
+
+	// HeaderCompression is used by JWE applications to declare the compression algorithm applied to the plaintext
+	// before encryption.
+	HeaderCompression = "zip" // string
 )
 
+// CompressionDEFLATE is the "zip" header value of the DEFLATE (RFC 1951) compression algorithm.
+const CompressionDEFLATE = "DEF"
+
 // Header defined in https://tools.ietf.org/html/rfc7797
 const (
 	// HeaderB64 determines whether the payload is represented in the JWS and the JWS Signing
@@ -90,6 +97,10 @@ const (
 	// A256GCMALG is the default content encryption algorithm value as per
 	// the JWA specification: https://tools.ietf.org/html/rfc7518#section-5.1
 	A256GCMALG = "A256GCM"
+	// A128GCMALG represents AES GCM with a 128 bits key encryption algorithm value.
+	A128GCMALG = "A128GCM"
+	// A192GCMALG represents AES GCM with a 192 bits key encryption algorithm value.
+	A192GCMALG = "A192GCM"
 	// XC20PALG represents XChacha20Poly1305 content encryption algorithm value.
 	XC20PALG = "XC20P"
 	// A128CBCHS256ALG represents AES_128_CBC_HMAC_SHA_256 encryption algorithm value.
@@ -100,10 +111,21 @@ const (
 	A256CBCHS384ALG = "A256CBC-HS384"
 	// A256CBCHS512ALG represents AES_256_CBC_HMAC_SHA_512 encryption algorithm value.
 	A256CBCHS512ALG = "A256CBC-HS512"
+	// A256GCMKCALG represents the key-committing AES256GCM encryption algorithm value (not defined in JWA spec above).
+	A256GCMKCALG = "A256GCM-KC"
 )
 
+// HeaderKeyCommitment is the base64url encoded key commitment of the A256GCM-KC content encryption key. It is an
+// additional protected header which can be ignored by implementations not supporting key commitment.
+const HeaderKeyCommitment = "kc" // string
+
+// aeadAlg maps content encryption algorithms to their ECDH AEAD primitive. ecdh.AES256GCM selects the AES-GCM AEAD,
+// its key size is the size of the CEK: 16 bytes for A128GCM and 24 bytes for A192GCM.
 var aeadAlg = map[EncAlg]ecdh.AEADAlg{ //nolint:gochecknoglobals
+	A128GCM:      ecdh.AES256GCM,
+	A192GCM:      ecdh.AES256GCM,
 	A256GCM:      ecdh.AES256GCM,
+	A256GCMKC:    ecdh.AES256GCM,
 	XC20P:        ecdh.XC20P,
 	A128CBCHS256: ecdh.AES128CBCHMACSHA256,
 	A192CBCHS384: ecdh.AES192CBCHMACSHA384,
@@ -144,6 +166,11 @@ func (h Headers) ContentType() (string, bool) {
 	return h.stringValue(HeaderContentType)
 }
 
+// Compression gets the JWE plaintext compression algorithm from JOSE headers.
+func (h Headers) Compression() (string, bool) {
+	return h.stringValue(HeaderCompression)
+}
+
 func (h Headers) stringValue(key string) (string, bool) {
 	raw, ok := h[key]
 	if !ok {
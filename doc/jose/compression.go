@@ -0,0 +1,56 @@
+/*
+Copyright Gen Digital Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package jose
+
+import (
+	"bytes"
+	"compress/flate"
+	"fmt"
+	"io"
+)
+
+// DefaultMaxDecompressedSize is the default maximum size in bytes of the plaintext of a compressed JWE once inflated.
+const DefaultMaxDecompressedSize = 1 << 20 // 1 MiB
+
+// deflate compresses plaintext with DEFLATE (RFC 1951) as required by the "DEF" compression algorithm.
+func deflate(plaintext []byte) ([]byte, error) {
+	buf := new(bytes.Buffer)
+
+	w, err := flate.NewWriter(buf, flate.DefaultCompression)
+	if err != nil {
+		return nil, fmt.Errorf("deflate: %w", err)
+	}
+
+	if _, err = w.Write(plaintext); err != nil {
+		return nil, fmt.Errorf("deflate: %w", err)
+	}
+
+	if err = w.Close(); err != nil {
+		return nil, fmt.Errorf("deflate: %w", err)
+	}
+
+	return buf.Bytes(), nil
+}
+
+// inflate decompresses DEFLATE compressed data. It fails if the inflated data exceeds maxSize bytes to prevent
+// decompression bombs from exhausting memory.
+func inflate(compressed []byte, maxSize int64) ([]byte, error) {
+	r := flate.NewReader(bytes.NewReader(compressed))
+	defer r.Close() //nolint:errcheck
+
+	// read one extra byte to detect data exceeding maxSize.
+	plaintext, err := io.ReadAll(io.LimitReader(r, maxSize+1))
+	if err != nil {
+		return nil, fmt.Errorf("inflate: %w", err)
+	}
+
+	if int64(len(plaintext)) > maxSize {
+		return nil, fmt.Errorf("inflate: decompressed plaintext exceeds the maximum size of %d bytes", maxSize)
+	}
+
+	return plaintext, nil
+}
@@ -8,25 +8,35 @@ package jose
 
 import (
 	"crypto/ecdsa"
+	"crypto/rand"
 	"encoding/base64"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"strings"
 
 	"github.com/google/tink/go/keyset"
 
+	"github.com/dellekappa/kms-go/crypto/tinkcrypto"
 	"github.com/dellekappa/kms-go/crypto/tinkcrypto/primitive/composite"
 	"github.com/dellekappa/kms-go/crypto/tinkcrypto/primitive/composite/api"
 	"github.com/dellekappa/kms-go/crypto/tinkcrypto/primitive/composite/ecdh"
 	"github.com/dellekappa/kms-go/crypto/tinkcrypto/primitive/composite/keyio"
 	ecdhpb "github.com/dellekappa/kms-go/crypto/tinkcrypto/primitive/proto/ecdh_aead_go_proto"
-	"github.com/dellekappa/kms-go/doc/jose/jwk"
 	resolver "github.com/dellekappa/kms-go/doc/jose/kidresolver"
 
 	cryptoapi "github.com/dellekappa/kms-go/spi/crypto"
 	"github.com/dellekappa/kms-go/spi/kms"
 )
 
+// ErrDecryptionFailed is returned by JWEDecrypt.Decrypt when the CEK can't be unwrapped with the recipient's key or the
+// authentication tag of the JWE is invalid. Both failures return the same error so that a tampered encrypted key can't
+// be told apart from a tampered ciphertext (padding and Bleichenbacher-style oracles).
+var ErrDecryptionFailed = errors.New("jwe decryption failed")
+
+// errCEKUnwrap is the internal error of a cryptographic CEK unwrap failure, Decrypt reports it as ErrDecryptionFailed.
+var errCEKUnwrap = errors.New("failed to unwrap cek") //nolint:gochecknoglobals
+
 // Decrypter interface to Decrypt JWE messages.
 type Decrypter interface {
 	// Decrypt a deserialized JWE, extracts the corresponding recipient key to decrypt plaintext and returns it
@@ -35,18 +45,82 @@ type Decrypter interface {
 
 // JWEDecrypt is responsible for decrypting a JWE message and returns its protected plaintext.
 type JWEDecrypt struct {
-	kidResolvers []resolver.KIDResolver
-	crypto       cryptoapi.Crypto
-	kms          kms.KeyManager
+	kidResolvers        []resolver.KIDResolver
+	crypto              cryptoapi.Crypto
+	kms                 kms.KeyManager
+	maxDecompressedSize int64
+	pbes2Password       []byte
+	minPBES2Count       int
+	aeadProviders       map[EncAlg]NewAEADProviderFunc
+}
+
+// jweDecryptOpts holds options for the JWEDecrypt.
+type jweDecryptOpts struct {
+	maxDecompressedSize int64
+	pbes2Password       []byte
+	minPBES2Count       int
+	aeadProviders       map[EncAlg]NewAEADProviderFunc
+}
+
+// JWEDecryptOpt is the JWEDecrypt option.
+type JWEDecryptOpt func(opts *jweDecryptOpts)
+
+// WithMaxDecompressedSize option sets the maximum size in bytes of the inflated plaintext of a JWE compressed with
+// DEFLATE ("zip":"DEF" header). Decryption fails if the plaintext exceeds it, guarding against decompression bombs.
+// Defaults to DefaultMaxDecompressedSize.
+func WithMaxDecompressedSize(size int64) JWEDecryptOpt {
+	return func(opts *jweDecryptOpts) {
+		opts.maxDecompressedSize = size
+	}
+}
+
+// WithPBES2Password option sets the password of JWEs using PBES2 key management (PBES2-HS256+A128KW,
+// PBES2-HS384+A192KW or PBES2-HS512+A256KW). The key wrapping key is derived from password and the p2s and p2c
+// protected headers. JWEs using PBES2 fail to decrypt without a password.
+func WithPBES2Password(password []byte) JWEDecryptOpt {
+	return func(opts *jweDecryptOpts) {
+		opts.pbes2Password = password
+	}
+}
+
+// WithMinPBES2Count option sets the minimum PBES2 iteration count (p2c header) accepted when decrypting, JWEs with a
+// lower count fail with ErrPBES2CountTooLow. Defaults to DefaultMinPBES2Count.
+func WithMinPBES2Count(count int) JWEDecryptOpt {
+	return func(opts *jweDecryptOpts) {
+		opts.minPBES2Count = count
+	}
+}
+
+// WithDecryptAEADProvider option decrypts the content of JWEs whose enc is encAlg with the AEADProviders created by
+// newProvider, instead of the built-in implementation of encAlg.
+func WithDecryptAEADProvider(encAlg EncAlg, newProvider NewAEADProviderFunc) JWEDecryptOpt {
+	return func(opts *jweDecryptOpts) {
+		if opts.aeadProviders == nil {
+			opts.aeadProviders = map[EncAlg]NewAEADProviderFunc{}
+		}
+
+		opts.aeadProviders[encAlg] = newProvider
+	}
 }
 
 // NewJWEDecrypt creates a new JWEDecrypt instance to parse and decrypt a JWE message for a given recipient
 // store is needed for Authcrypt only (to fetch sender's pre agreed upon public key), it is not needed for Anoncrypt.
-func NewJWEDecrypt(kidResolvers []resolver.KIDResolver, c cryptoapi.Crypto, k kms.KeyManager) *JWEDecrypt {
+func NewJWEDecrypt(kidResolvers []resolver.KIDResolver, c cryptoapi.Crypto, k kms.KeyManager,
+	opts ...JWEDecryptOpt) *JWEDecrypt {
+	dOpts := &jweDecryptOpts{maxDecompressedSize: DefaultMaxDecompressedSize, minPBES2Count: DefaultMinPBES2Count}
+
+	for _, opt := range opts {
+		opt(dOpts)
+	}
+
 	return &JWEDecrypt{
-		kidResolvers: kidResolvers,
-		crypto:       c,
-		kms:          k,
+		kidResolvers:        kidResolvers,
+		crypto:              c,
+		kms:                 k,
+		maxDecompressedSize: dOpts.maxDecompressedSize,
+		pbes2Password:       dOpts.pbes2Password,
+		minPBES2Count:       dOpts.minPBES2Count,
+		aeadProviders:       dOpts.aeadProviders,
 	}
 }
 
@@ -67,6 +141,15 @@ func getECDHDecPrimitive(cek []byte, encAlg EncAlg, nistpKW bool) (api.Composite
 	return ecdh.NewECDHDecrypt(kh)
 }
 
+// getDecPrimitive returns the content decryption primitive of encAlg, using the AEADProvider of encAlg if set.
+func (jd *JWEDecrypt) getDecPrimitive(cek []byte, encAlg EncAlg) (api.CompositeDecrypt, error) {
+	if newProvider, ok := jd.aeadProviders[encAlg]; ok {
+		return newAEADProviderPrimitive(newProvider, cek)
+	}
+
+	return getECDHDecPrimitive(cek, encAlg, true)
+}
+
 // Decrypt a deserialized JWE, decrypts its protected content and returns plaintext.
 func (jd *JWEDecrypt) Decrypt(jwe *JSONWebEncryption) ([]byte, error) {
 	encAlg, err := jd.validateAndExtractProtectedHeaders(jwe)
@@ -74,6 +157,71 @@ func (jd *JWEDecrypt) Decrypt(jwe *JSONWebEncryption) ([]byte, error) {
 		return nil, fmt.Errorf("jwedecrypt: %w", err)
 	}
 
+	var cek []byte
+
+	if alg, _ := jwe.ProtectedHeaders.Algorithm(); isPBES2(alg) {
+		cek, err = jd.unwrapPBES2CEK(jwe, alg)
+	} else {
+		cek, err = jd.unwrapECDHCEK(jwe, encAlg)
+	}
+
+	if err == nil {
+		cek, err = unwrapSectionCEK(jwe.ProtectedHeaders, cek)
+	}
+
+	if errors.Is(err, errCEKUnwrap) {
+		return nil, jd.failDecryption(jwe, encAlg)
+	}
+
+	if err != nil {
+		return nil, fmt.Errorf("jwedecrypt: %w", err)
+	}
+
+	if len(cek) != cekSize(EncAlg(encAlg)) {
+		return nil, fmt.Errorf("jwedecrypt: CEK size %d is invalid for %s, expected %d", len(cek), encAlg,
+			cekSize(EncAlg(encAlg)))
+	}
+
+	if EncAlg(encAlg) == A256GCMKC {
+		err = verifyKeyCommitment(jwe.ProtectedHeaders, cek)
+		if errors.Is(err, errCEKUnwrap) {
+			return nil, jd.failDecryption(jwe, encAlg)
+		}
+
+		if err != nil {
+			return nil, fmt.Errorf("jwedecrypt: %w", err)
+		}
+	}
+
+	plaintext, err := jd.decryptJWE(jwe, cek)
+	if err != nil {
+		return nil, err
+	}
+
+	if _, ok := jwe.ProtectedHeaders.Compression(); ok {
+		plaintext, err = inflate(plaintext, jd.maxDecompressedSize)
+		if err != nil {
+			return nil, fmt.Errorf("jwedecrypt: %w", err)
+		}
+	}
+
+	return plaintext, nil
+}
+
+// failDecryption returns ErrDecryptionFailed for a JWE whose CEK unwrap failed. As recommended by RFC 7516 section 11.5,
+// the content is still decrypted, with a random CEK, so that the failure takes as long as an invalid authentication tag.
+func (jd *JWEDecrypt) failDecryption(jwe *JSONWebEncryption, encAlg string) error {
+	cek := make([]byte, cekSize(EncAlg(encAlg)))
+
+	if _, err := rand.Read(cek); err == nil {
+		_, _ = jd.decryptJWE(jwe, cek) //nolint:errcheck
+	}
+
+	return fmt.Errorf("jwedecrypt: %w", ErrDecryptionFailed)
+}
+
+// unwrapECDHCEK unwraps the CEK of the ECDH key agreement JWE jwe with the recipient's key in the KMS.
+func (jd *JWEDecrypt) unwrapECDHCEK(jwe *JSONWebEncryption, encAlg string) ([]byte, error) {
 	var wkOpts []cryptoapi.WrapKeyOpts
 
 	skid, ok := jwe.ProtectedHeaders.SenderKeyID()
@@ -84,7 +232,7 @@ func (jd *JWEDecrypt) Decrypt(jwe *JSONWebEncryption) ([]byte, error) {
 	if ok && skid != "" {
 		senderKH, e := jd.fetchSenderPubKey(skid, EncAlg(encAlg))
 		if e != nil {
-			return nil, fmt.Errorf("jwedecrypt: failed to add sender public key for skid: %w", e)
+			return nil, fmt.Errorf("failed to add sender public key for skid: %w", e)
 		}
 
 		wkOpts = append(wkOpts, cryptoapi.WithSender(senderKH), cryptoapi.WithTag([]byte(jwe.Tag)))
@@ -92,31 +240,44 @@ func (jd *JWEDecrypt) Decrypt(jwe *JSONWebEncryption) ([]byte, error) {
 
 	recWK, err := buildRecipientsWrappedKey(jwe)
 	if err != nil {
-		return nil, fmt.Errorf("jwedecrypt: failed to build recipients WK: %w", err)
+		return nil, fmt.Errorf("failed to build recipients WK: %w", err)
 	}
 
-	cek, err := jd.unwrapCEK(recWK, wkOpts...)
+	if alg, _ := jwe.ProtectedHeaders.Algorithm(); alg == tinkcrypto.ECDHESAlg {
+		if len(recWK) != 1 {
+			return nil, fmt.Errorf("'%s' direct key agreement requires a single recipient", alg)
+		}
+
+		// the CEK is derived from the EPK, there is no encrypted key to unwrap.
+		wkOpts = append(wkOpts, cryptoapi.WithDirectKeyAgreement(encAlg, cekSize(EncAlg(encAlg))))
+	}
+
+	keyConversion, _ := jwe.ProtectedHeaders.stringValue(HeaderKeyConversion)
+
+	cek, err := jd.unwrapCEK(recWK, keyConversion == KeyConversionEd25519ToX25519, wkOpts...)
 	if err != nil {
-		return nil, fmt.Errorf("jwedecrypt: %w", err)
+		return nil, err
 	}
 
 	if len(recWK) == 1 {
 		// ensure EPK is marshalled the same way as during encryption since it is merged into ProtectHeaders.
 		marshalledEPK, err := convertRecEPKToMarshalledJWK(&recWK[0].EPK)
 		if err != nil {
-			return nil, fmt.Errorf("jwedecrypt: %w", err)
+			return nil, err
 		}
 
 		jwe.ProtectedHeaders["epk"] = json.RawMessage(marshalledEPK)
 	}
 
-	return jd.decryptJWE(jwe, cek)
+	return cek, nil
 }
 
 func fetchSKIDFromAPU(jwe *JSONWebEncryption) (string, bool) {
 	// for multi-recipients only: check apu in protectedHeaders if it's found for ECDH-1PU, if skid header is empty then
 	// use apu as skid instead.
-	if len(jwe.Recipients) > 1 {
+	alg, _ := jwe.ProtectedHeaders.Algorithm()
+
+	if len(jwe.Recipients) > 1 && strings.Contains(strings.ToUpper(alg), "1PU") {
 		if a, apuOK := jwe.ProtectedHeaders["apu"]; apuOK {
 			skidBytes, err := base64.RawURLEncoding.DecodeString(a.(string))
 			if err != nil {
@@ -131,11 +292,12 @@ func fetchSKIDFromAPU(jwe *JSONWebEncryption) (string, bool) {
 }
 
 //nolint:gocyclo
-func (jd *JWEDecrypt) unwrapCEK(recWK []*cryptoapi.RecipientWrappedKey,
+func (jd *JWEDecrypt) unwrapCEK(recWK []*cryptoapi.RecipientWrappedKey, fromEd25519 bool,
 	senderOpt ...cryptoapi.WrapKeyOpts) ([]byte, error) {
 	var (
-		cek  []byte
-		errs []error
+		cek       []byte
+		errs      []error
+		attempted bool
 	)
 
 	for _, rec := range recWK {
@@ -159,6 +321,15 @@ func (jd *JWEDecrypt) unwrapCEK(recWK []*cryptoapi.RecipientWrappedKey,
 			continue
 		}
 
+		if fromEd25519 {
+			// the sender converted the recipient's Ed25519 public key, convert its private key likewise.
+			recKH, err = tinkcrypto.X25519KeyHandleFromEd25519(recKH)
+			if err != nil {
+				errs = append(errs, err)
+				continue
+			}
+		}
+
 		if rec.EPK.Type == ecdhpb.KeyType_OKP.String() {
 			unwrapOpts = append(unwrapOpts, cryptoapi.WithXC20PKW())
 		}
@@ -167,6 +338,8 @@ func (jd *JWEDecrypt) unwrapCEK(recWK []*cryptoapi.RecipientWrappedKey,
 			unwrapOpts = append(unwrapOpts, senderOpt...)
 		}
 
+		attempted = true
+
 		if len(unwrapOpts) > 0 {
 			cek, err = jd.crypto.UnwrapKey(rec, recKH, unwrapOpts...)
 		} else {
@@ -180,6 +353,11 @@ func (jd *JWEDecrypt) unwrapCEK(recWK []*cryptoapi.RecipientWrappedKey,
 		errs = append(errs, err)
 	}
 
+	if len(cek) == 0 && attempted {
+		// the recipient's key was found, don't tell why the unwrap failed.
+		return nil, errCEKUnwrap
+	}
+
 	if len(cek) == 0 {
 		return nil, fmt.Errorf("failed to unwrap cek: %v", errs)
 	}
@@ -208,7 +386,7 @@ func (jd *JWEDecrypt) decryptJWE(jwe *JSONWebEncryption, cek []byte) ([]byte, er
 		return nil, fmt.Errorf("jwedecrypt: JWE 'enc' protected header is missing")
 	}
 
-	decPrimitive, err := getECDHDecPrimitive(cek, EncAlg(encAlg), true)
+	decPrimitive, err := jd.getDecPrimitive(cek, EncAlg(encAlg))
 	if err != nil {
 		return nil, fmt.Errorf("jwedecrypt: failed to get decryption primitive: %w", err)
 	}
@@ -225,7 +403,12 @@ func (jd *JWEDecrypt) decryptJWE(jwe *JSONWebEncryption, cek []byte) ([]byte, er
 		return nil, err
 	}
 
-	return decPrimitive.Decrypt(encryptedData, authData)
+	plaintext, err := decPrimitive.Decrypt(encryptedData, authData)
+	if err != nil {
+		return nil, fmt.Errorf("jwedecrypt: %w", ErrDecryptionFailed)
+	}
+
+	return plaintext, nil
 }
 
 func (jd *JWEDecrypt) fetchSenderPubKey(skid string, encAlg EncAlg) (*keyset.Handle, error) {
@@ -260,12 +443,16 @@ func (jd *JWEDecrypt) validateAndExtractProtectedHeaders(jwe *JSONWebEncryption)
 	}
 
 	switch encAlg {
-	case string(A256GCM), string(XC20P), string(A128CBCHS256),
-		string(A192CBCHS384), string(A256CBCHS384), string(A256CBCHS512):
+	case string(A128GCM), string(A192GCM), string(A256GCM), string(XC20P), string(A128CBCHS256),
+		string(A192CBCHS384), string(A256CBCHS384), string(A256CBCHS512), string(A256GCMKC):
 	default:
 		return "", fmt.Errorf("encryption algorithm '%s' not supported", encAlg)
 	}
 
+	if zip, ok := protectedHeaders[HeaderCompression]; ok && zip != CompressionDEFLATE {
+		return "", fmt.Errorf("compression algorithm '%v' not supported", zip)
+	}
+
 	return encAlg, nil
 }
 
@@ -286,6 +473,8 @@ func buildRecipientsWrappedKey(jwe *JSONWebEncryption) ([]*cryptoapi.RecipientWr
 			if err != nil {
 				return nil, err
 			}
+		} else {
+			headers = mergeProtectedAPUAPV(headers, jwe.ProtectedHeaders)
 		}
 
 		var recWK *cryptoapi.RecipientWrappedKey
@@ -305,6 +494,26 @@ func buildRecipientsWrappedKey(jwe *JSONWebEncryption) ([]*cryptoapi.RecipientWr
 	return recipients, nil
 }
 
+// mergeProtectedAPUAPV returns a copy of the recipient headers with the apu and apv headers shared by all recipients
+// in protectedHeaders, unless the recipient headers have their own.
+func mergeProtectedAPUAPV(headers *RecipientHeaders, protectedHeaders Headers) *RecipientHeaders {
+	if headers == nil {
+		return nil
+	}
+
+	merged := *headers
+
+	if apu, ok := protectedHeaders["apu"].(string); ok && merged.APU == "" {
+		merged.APU = apu
+	}
+
+	if apv, ok := protectedHeaders["apv"].(string); ok && merged.APV == "" {
+		merged.APV = apv
+	}
+
+	return &merged
+}
+
 func createRecWK(headers *RecipientHeaders, encryptedKey []byte) (*cryptoapi.RecipientWrappedKey, error) {
 	recWK, err := convertMarshalledJWKToRecKey(headers.EPK)
 	if err != nil {
@@ -400,9 +609,7 @@ func extractRecipientHeaders(headers map[string]interface{}) (*RecipientHeaders,
 }
 
 func convertMarshalledJWKToRecKey(marshalledJWK []byte) (*cryptoapi.RecipientWrappedKey, error) {
-	j := &jwk.JWK{}
-
-	err := j.UnmarshalJSON(marshalledJWK)
+	j, err := parseEPK(marshalledJWK)
 	if err != nil {
 		return nil, err
 	}
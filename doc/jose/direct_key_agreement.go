@@ -0,0 +1,183 @@
+/*
+Copyright Gen Digital Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package jose
+
+import (
+	"crypto/ecdsa"
+	"crypto/rand"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math/big"
+
+	hybrid "github.com/google/tink/go/hybrid/subtle"
+	"golang.org/x/crypto/chacha20poly1305"
+	"golang.org/x/crypto/curve25519"
+
+	"github.com/dellekappa/kms-go/crypto/tinkcrypto"
+	"github.com/dellekappa/kms-go/crypto/tinkcrypto/primitive/composite"
+	ecdhpb "github.com/dellekappa/kms-go/crypto/tinkcrypto/primitive/proto/ecdh_aead_go_proto"
+	"github.com/dellekappa/kms-go/util/cryptoutil"
+
+	cryptoapi "github.com/dellekappa/kms-go/spi/crypto"
+)
+
+// encryptDirect encrypts plaintext with a CEK derived using ECDH-ES Direct Key Agreement with the single recipient key.
+// The recipient headers are merged into protectedHeaders and the JWE has no encrypted key.
+func (je *JWEEncrypt) encryptDirect(protectedHeaders map[string]interface{},
+	plaintext, aad []byte) (*JSONWebEncryption, error) {
+	recPubKey := je.recipientsKeys[0]
+
+	cek, epk, apu, err := deriveDirectCEK(recPubKey, string(je.encAlg), je.apu, je.apv, cekSize(je.encAlg))
+	if err != nil {
+		return nil, fmt.Errorf("jweencryptDirect: %w", err)
+	}
+
+	mEPK, err := convertRecEPKToMarshalledJWK(epk)
+	if err != nil {
+		return nil, fmt.Errorf("jweencryptDirect: %w", err)
+	}
+
+	protectedHeaders[HeaderAlgorithm] = tinkcrypto.ECDHESAlg
+	protectedHeaders[HeaderEPK] = json.RawMessage(mEPK)
+
+	if recPubKey.KID != "" {
+		protectedHeaders[HeaderKeyID] = recPubKey.KID
+	}
+
+	if len(apu) > 0 {
+		protectedHeaders["apu"] = base64.RawURLEncoding.EncodeToString(apu)
+	}
+
+	if len(je.apv) > 0 {
+		protectedHeaders["apv"] = base64.RawURLEncoding.EncodeToString(je.apv)
+	}
+
+	if je.encAlg == A256GCMKC {
+		protectedHeaders[HeaderKeyCommitment] = base64.RawURLEncoding.EncodeToString(keyCommitment(cek))
+	}
+
+	encPrimitive, err := je.getECDHEncPrimitive(cek)
+	if err != nil {
+		return nil, fmt.Errorf("jweencryptDirect: failed to get encryption primitive: %w", err)
+	}
+
+	authData, err := computeAuthData(protectedHeaders, "", aad)
+	if err != nil {
+		return nil, fmt.Errorf("jweencryptDirect: computeAuthData: marshal error %w", err)
+	}
+
+	serializedEncData, err := encPrimitive.Encrypt(plaintext, authData)
+	if err != nil {
+		return nil, fmt.Errorf("jweencryptDirect: failed to Encrypt: %w", err)
+	}
+
+	encData := new(composite.EncryptedData)
+
+	err = json.Unmarshal(serializedEncData, encData)
+	if err != nil {
+		return nil, fmt.Errorf("jweencryptDirect: unmarshal encrypted data failed: %w", err)
+	}
+
+	return getJSONWebEncryption(encData, []*Recipient{{}}, protectedHeaders, aad), nil
+}
+
+// deriveDirectCEK generates an ephemeral key on the curve of recPubKey and derives a CEK of keySize bytes for the enc
+// content encryption algorithm with the ECDH-ES Concat KDF. Like key wrapping anoncrypt, apu defaults to the
+// base64url encoded X coordinate of the ephemeral key when empty. It returns the CEK, the ephemeral public key and apu.
+func deriveDirectCEK(recPubKey *cryptoapi.PublicKey, enc string, apu, apv []byte,
+	keySize int) ([]byte, *cryptoapi.PublicKey, []byte, error) {
+	z, epk, err := ephemeralKeyAgreement(recPubKey)
+	if err != nil {
+		return nil, nil, nil, fmt.Errorf("deriveDirectCEK: %w", err)
+	}
+
+	if len(apu) == 0 {
+		apu = []byte(base64.RawURLEncoding.EncodeToString(epk.X))
+	}
+
+	return tinkcrypto.ConcatKDF(enc, z, apu, apv, nil, keySize), epk, apu, nil
+}
+
+// ephemeralKeyAgreement generates an ephemeral key on the curve of recPubKey. It returns the ECDH shared secret of the
+// ephemeral key with recPubKey and the ephemeral public key.
+func ephemeralKeyAgreement(recPubKey *cryptoapi.PublicKey) ([]byte, *cryptoapi.PublicKey, error) {
+	switch recPubKey.Type {
+	case ecdhpb.KeyType_EC.String():
+		return ephemeralKeyAgreementWithECKey(recPubKey)
+	case ecdhpb.KeyType_OKP.String():
+		return ephemeralKeyAgreementWithOKPKey(recPubKey)
+	default:
+		return nil, nil, fmt.Errorf("invalid recipient key type '%s'", recPubKey.Type)
+	}
+}
+
+func ephemeralKeyAgreementWithECKey(recPubKey *cryptoapi.PublicKey) ([]byte, *cryptoapi.PublicKey, error) {
+	curve, err := hybrid.GetCurve(recPubKey.Curve)
+	if err != nil {
+		return nil, nil, fmt.Errorf("getCurve: %w", err)
+	}
+
+	recECPubKey := &ecdsa.PublicKey{
+		Curve: curve,
+		X:     new(big.Int).SetBytes(recPubKey.X),
+		Y:     new(big.Int).SetBytes(recPubKey.Y),
+	}
+
+	if !curve.IsOnCurve(recECPubKey.X, recECPubKey.Y) {
+		return nil, nil, errors.New("recipient key is not on curve")
+	}
+
+	ephemeralPrivKey, err := ecdsa.GenerateKey(curve, rand.Reader)
+	if err != nil {
+		return nil, nil, fmt.Errorf("generate ec key: %w", err)
+	}
+
+	return ecSharedSecret(ephemeralPrivKey, recECPubKey), &cryptoapi.PublicKey{
+		X:     ephemeralPrivKey.X.Bytes(),
+		Y:     ephemeralPrivKey.Y.Bytes(),
+		Curve: curve.Params().Name,
+		Type:  recPubKey.Type,
+	}, nil
+}
+
+func ephemeralKeyAgreementWithOKPKey(recPubKey *cryptoapi.PublicKey) ([]byte, *cryptoapi.PublicKey, error) {
+	ephemeralPrivKey := new([chacha20poly1305.KeySize]byte)
+
+	_, err := rand.Read(ephemeralPrivKey[:])
+	if err != nil {
+		return nil, nil, fmt.Errorf("generate random key for OKP: %w", err)
+	}
+
+	ephemeralPubKey, err := curve25519.X25519(ephemeralPrivKey[:], curve25519.Basepoint)
+	if err != nil {
+		return nil, nil, fmt.Errorf("get public epk for OKP: %w", err)
+	}
+
+	recPubKeyChacha := new([chacha20poly1305.KeySize]byte)
+	copy(recPubKeyChacha[:], recPubKey.X)
+
+	z, err := cryptoutil.DeriveECDHX25519(ephemeralPrivKey, recPubKeyChacha)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return z, &cryptoapi.PublicKey{
+		X:     ephemeralPubKey,
+		Curve: "X25519",
+		Type:  recPubKey.Type,
+	}, nil
+}
+
+// ecSharedSecret returns the X coordinate of the ECDH shared point of privKey and pubKey, padded to the curve size as
+// done by josecipher.DeriveECDHES. pubKey must be on the curve of privKey.
+func ecSharedSecret(privKey *ecdsa.PrivateKey, pubKey *ecdsa.PublicKey) []byte {
+	z, _ := privKey.Curve.ScalarMult(pubKey.X, pubKey.Y, privKey.D.Bytes())
+
+	return z.FillBytes(make([]byte, (privKey.Curve.Params().BitSize+7)/8)) //nolint:gomnd
+}
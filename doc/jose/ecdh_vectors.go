@@ -0,0 +1,203 @@
+/*
+Copyright Gen Digital Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package jose
+
+import (
+	"bytes"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"math/big"
+
+	"golang.org/x/crypto/curve25519"
+
+	"github.com/dellekappa/kms-go/crypto/tinkcrypto"
+)
+
+// ECDHVector is a known answer vector for ECDH-ES or ECDH-1PU key agreement followed by the Concat KDF.
+type ECDHVector struct {
+	// Name identifies the vector in mismatch errors.
+	Name string
+	// AlgorithmID is the Concat KDF AlgorithmID: the 'enc' value for Direct Key Agreement or the 'alg' value when
+	// the derived key wraps the CEK.
+	AlgorithmID string
+	// KeySize is the size in bytes of the derived key.
+	KeySize int
+	// EphemeralKey is the sender's ephemeral private key, either an *ecdsa.PrivateKey or a raw X25519 private key.
+	EphemeralKey interface{}
+	// StaticKey is the recipient's static private key, of the same type and curve as EphemeralKey.
+	StaticKey interface{}
+	// SenderKey is the sender's static private key for ECDH-1PU, nil for ECDH-ES.
+	SenderKey interface{}
+	APU       []byte
+	APV       []byte
+	// Tag is the JWE authentication tag used by ECDH-1PU key wrapping, nil for Direct Key Agreement.
+	Tag []byte
+	// ExpectedCEK is the key the vector must derive.
+	ExpectedCEK []byte
+}
+
+// RunECDHESVector derives the key of vector both as the sender (ephemeral and sender private keys with the recipient
+// public key) and as the recipient (recipient private key with the ephemeral and sender public keys). It returns an
+// error describing the first difference if either derived key does not match vector.ExpectedCEK.
+func RunECDHESVector(vector ECDHVector) error {
+	senderZ, err := vectorSharedSecret(vector.EphemeralKey, vector.StaticKey)
+	if err != nil {
+		return fmt.Errorf("runECDHESVector: vector '%s': ephemeral key: %w", vector.Name, err)
+	}
+
+	recipientZ, err := vectorSharedSecret(vector.StaticKey, vector.EphemeralKey)
+	if err != nil {
+		return fmt.Errorf("runECDHESVector: vector '%s': static key: %w", vector.Name, err)
+	}
+
+	if vector.SenderKey != nil {
+		zs, e := vectorSharedSecret(vector.SenderKey, vector.StaticKey)
+		if e != nil {
+			return fmt.Errorf("runECDHESVector: vector '%s': sender key: %w", vector.Name, e)
+		}
+
+		senderZ = append(senderZ, zs...)
+
+		zs, e = vectorSharedSecret(vector.StaticKey, vector.SenderKey)
+		if e != nil {
+			return fmt.Errorf("runECDHESVector: vector '%s': static key: %w", vector.Name, e)
+		}
+
+		recipientZ = append(recipientZ, zs...)
+	}
+
+	for _, derived := range []struct {
+		party string
+		z     []byte
+	}{
+		{party: "sender", z: senderZ},
+		{party: "recipient", z: recipientZ},
+	} {
+		cek := tinkcrypto.ConcatKDF(vector.AlgorithmID, derived.z, vector.APU, vector.APV, vector.Tag, vector.KeySize)
+
+		if !bytes.Equal(cek, vector.ExpectedCEK) {
+			return fmt.Errorf("runECDHESVector: vector '%s': %s derived key %x does not match expected key %x: %s",
+				vector.Name, derived.party, cek, vector.ExpectedCEK, firstDifference(cek, vector.ExpectedCEK))
+		}
+	}
+
+	return nil
+}
+
+// vectorSharedSecret computes the ECDH shared secret of privKey with the public key of peerKey.
+func vectorSharedSecret(privKey, peerKey interface{}) ([]byte, error) {
+	switch pk := privKey.(type) {
+	case *ecdsa.PrivateKey:
+		peer, ok := peerKey.(*ecdsa.PrivateKey)
+		if !ok {
+			return nil, fmt.Errorf("peer key type %T does not match *ecdsa.PrivateKey", peerKey)
+		}
+
+		if pk.Curve != peer.Curve {
+			return nil, fmt.Errorf("curve %s does not match peer curve %s",
+				pk.Curve.Params().Name, peer.Curve.Params().Name)
+		}
+
+		if !pk.Curve.IsOnCurve(peer.X, peer.Y) {
+			return nil, errors.New("peer public key is not on curve")
+		}
+
+		z, _ := pk.Curve.ScalarMult(peer.X, peer.Y, pk.D.Bytes())
+
+		return z.FillBytes(make([]byte, (pk.Curve.Params().BitSize+7)/8)), nil //nolint:gomnd
+	case []byte:
+		peer, ok := peerKey.([]byte)
+		if !ok {
+			return nil, fmt.Errorf("peer key type %T does not match X25519 []byte", peerKey)
+		}
+
+		peerPub, err := curve25519.X25519(peer, curve25519.Basepoint)
+		if err != nil {
+			return nil, fmt.Errorf("peer public key: %w", err)
+		}
+
+		return curve25519.X25519(pk, peerPub)
+	default:
+		return nil, fmt.Errorf("unsupported private key type %T", privKey)
+	}
+}
+
+func firstDifference(got, expected []byte) string {
+	for i := 0; i < len(got) && i < len(expected); i++ {
+		if got[i] != expected[i] {
+			return fmt.Sprintf("first difference at byte %d (%#02x != %#02x)", i, got[i], expected[i])
+		}
+	}
+
+	return fmt.Sprintf("length %d != %d", len(got), len(expected))
+}
+
+// KnownECDHVectors returns the key agreement vectors of RFC 7518 Appendix C (ECDH-ES) and of the ECDH-1PU draft
+// Appendices A and B (https://datatracker.ietf.org/doc/html/draft-madden-jose-ecdh-1pu-04), to be checked with
+// RunECDHESVector.
+func KnownECDHVectors() []ECDHVector {
+	aliceP256 := vectorP256Key("Hndv7ZZjs_ke8o9zXYo3iq-Yr8SewI5vrqd0pAvEPqg")
+	bobP256 := vectorP256Key("VEmDZpDXXK8p8N0Cndsxs924q6nS1RXFASRl6BfUqdw")
+	ephemeralP256 := vectorP256Key("0_NxaRPUMQoAJt50Gz8YiTr8gRTwyEaCumd-MToTmIo")
+
+	return []ECDHVector{
+		{
+			Name:         "RFC 7518 Appendix C ECDH-ES",
+			AlgorithmID:  "A128GCM",
+			KeySize:      16,
+			EphemeralKey: ephemeralP256,
+			StaticKey:    bobP256,
+			APU:          []byte("Alice"),
+			APV:          []byte("Bob"),
+			ExpectedCEK:  vectorBytes("VqqN6vgjbSBcIijNcacQGg"),
+		},
+		{
+			Name:         "ECDH-1PU Appendix A P-256 direct",
+			AlgorithmID:  "A256GCM",
+			KeySize:      32,
+			EphemeralKey: ephemeralP256,
+			StaticKey:    bobP256,
+			SenderKey:    aliceP256,
+			APU:          []byte("Alice"),
+			APV:          []byte("Bob"),
+			ExpectedCEK:  vectorBytes("bK8Tcj0UhQrUtCzW3ek1v_0v_wCpunDeBcIDpeFyLKc"),
+		},
+		{
+			Name:         "ECDH-1PU Appendix B X25519 key wrapping",
+			AlgorithmID:  "ECDH-1PU+A128KW",
+			KeySize:      16,
+			EphemeralKey: vectorBytes("x8EVZH4Fwk673_mUujnliJoSrLz0zYzzCWp5GUX2fc8"),
+			StaticKey:    vectorBytes("1gDirl_r_Y3-qUa3WXHgEXrrEHngWThU3c9zj9A2uBg"),
+			SenderKey:    vectorBytes("i9KuFhSzEBsiv3PKVL5115OCdsqQai5nj_Flzfkw5jU"),
+			APU:          []byte("Alice"),
+			APV:          []byte("Bob and Charlie"),
+			Tag:          vectorBytes("HLb4fTlm8spGmij3RyOs2gJ4DpHM4hhVRwdF_hGb3WQ"),
+			ExpectedCEK:  vectorBytes("30w3oGaDBqEePWsAdLXY3w"),
+		},
+	}
+}
+
+// vectorBytes decodes the base64url constants of KnownECDHVectors.
+func vectorBytes(s string) []byte {
+	b, _ := base64.RawURLEncoding.DecodeString(s) //nolint:errcheck // constants are valid base64url.
+
+	return b
+}
+
+func vectorP256Key(d string) *ecdsa.PrivateKey {
+	priv := &ecdsa.PrivateKey{
+		PublicKey: ecdsa.PublicKey{Curve: elliptic.P256()},
+		D:         new(big.Int).SetBytes(vectorBytes(d)),
+	}
+
+	priv.X, priv.Y = priv.Curve.ScalarBaseMult(priv.D.Bytes())
+
+	return priv
+}
@@ -0,0 +1,88 @@
+/*
+Copyright Gen Digital Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package jose
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestRunECDHESVector(t *testing.T) {
+	for _, vector := range KnownECDHVectors() {
+		t.Run(vector.Name, func(t *testing.T) {
+			require.NoError(t, RunECDHESVector(vector))
+		})
+	}
+
+	t.Run("mismatch", func(t *testing.T) {
+		vector := KnownECDHVectors()[0]
+		vector.ExpectedCEK = append([]byte{}, vector.ExpectedCEK...)
+		vector.ExpectedCEK[3] ^= 0xff
+
+		err := RunECDHESVector(vector)
+		require.ErrorContains(t, err, "runECDHESVector: vector 'RFC 7518 Appendix C ECDH-ES': sender derived key")
+		require.ErrorContains(t, err, "first difference at byte 3")
+	})
+
+	t.Run("expected key length mismatch", func(t *testing.T) {
+		vector := KnownECDHVectors()[0]
+		vector.ExpectedCEK = vector.ExpectedCEK[:8]
+
+		require.ErrorContains(t, RunECDHESVector(vector), "length 16 != 8")
+	})
+
+	p384Key, err := ecdsa.GenerateKey(elliptic.P384(), rand.Reader)
+	require.NoError(t, err)
+
+	tests := []struct {
+		name   string
+		modify func(vector *ECDHVector)
+		err    string
+	}{
+		{
+			name:   "unsupported ephemeral key type",
+			modify: func(vector *ECDHVector) { vector.EphemeralKey = "key" },
+			err:    "ephemeral key: unsupported private key type string",
+		},
+		{
+			name:   "static key type not matching ephemeral key",
+			modify: func(vector *ECDHVector) { vector.StaticKey = make([]byte, 32) },
+			err:    "ephemeral key: peer key type []uint8 does not match *ecdsa.PrivateKey",
+		},
+		{
+			name:   "static key curve not matching ephemeral key",
+			modify: func(vector *ECDHVector) { vector.StaticKey = p384Key },
+			err:    "ephemeral key: curve P-256 does not match peer curve P-384",
+		},
+		{
+			name:   "sender key curve not matching static key",
+			modify: func(vector *ECDHVector) { vector.SenderKey = p384Key },
+			err:    "sender key: curve P-384 does not match peer curve P-256",
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			vector := KnownECDHVectors()[0]
+			tc.modify(&vector)
+
+			require.ErrorContains(t, RunECDHESVector(vector), tc.err)
+		})
+	}
+
+	t.Run("X25519 static key type not matching ephemeral key", func(t *testing.T) {
+		vector := KnownECDHVectors()[2]
+		vector.StaticKey = p384Key
+
+		require.ErrorContains(t, RunECDHESVector(vector),
+			"ephemeral key: peer key type *ecdsa.PrivateKey does not match X25519 []byte")
+	})
+}
@@ -0,0 +1,60 @@
+/*
+Copyright Gen Digital Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package jose
+
+import (
+	"fmt"
+
+	ecdhpb "github.com/dellekappa/kms-go/crypto/tinkcrypto/primitive/proto/ecdh_aead_go_proto"
+	"github.com/dellekappa/kms-go/util/cryptoutil"
+
+	cryptoapi "github.com/dellekappa/kms-go/spi/crypto"
+)
+
+// HeaderKeyConversion declares that the key agreement key of at least one recipient was converted from another key
+// type. It is set to KeyConversionEd25519ToX25519 when encrypting to Ed25519 recipient keys.
+const HeaderKeyConversion = "kconv" // string
+
+// KeyConversionEd25519ToX25519 is the HeaderKeyConversion value of recipients which Ed25519 public key was converted to
+// an X25519 key for ECDH key agreement. These recipients decrypt with the X25519 conversion of their Ed25519 private key.
+const KeyConversionEd25519ToX25519 = "Ed25519-X25519"
+
+const (
+	ed25519Curve = "Ed25519"
+	x25519Curve  = "X25519"
+)
+
+// convertEd25519Recipients returns recipientsPubKeys with the Ed25519 keys converted to X25519 keys and whether any
+// key was converted. recipientsPubKeys is not modified.
+func convertEd25519Recipients(recipientsPubKeys []*cryptoapi.PublicKey) ([]*cryptoapi.PublicKey, bool, error) {
+	var converted bool
+
+	keys := make([]*cryptoapi.PublicKey, len(recipientsPubKeys))
+
+	for i, key := range recipientsPubKeys {
+		keys[i] = key
+
+		if key == nil || key.Type != ecdhpb.KeyType_OKP.String() || key.Curve != ed25519Curve {
+			continue
+		}
+
+		x, err := cryptoutil.PublicEd25519toCurve25519(key.X)
+		if err != nil {
+			return nil, false, fmt.Errorf("convert Ed25519 recipient key '%s' to X25519: %w", key.KID, err)
+		}
+
+		keys[i] = &cryptoapi.PublicKey{
+			KID:   key.KID,
+			X:     x,
+			Curve: x25519Curve,
+			Type:  key.Type,
+		}
+		converted = true
+	}
+
+	return keys, converted, nil
+}
@@ -0,0 +1,209 @@
+/*
+Copyright Gen Digital Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package jose_test
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/dellekappa/kms-go/crypto/tinkcrypto"
+	"github.com/dellekappa/kms-go/crypto/tinkcrypto/primitive/composite/ecdh"
+	mockstorage "github.com/dellekappa/kms-go/internal/mock/storage"
+	kmsservice "github.com/dellekappa/kms-go/kms"
+	"github.com/dellekappa/kms-go/kms/localkms"
+	"github.com/dellekappa/kms-go/secretlock/noop"
+
+	ariesjose "github.com/dellekappa/kms-go/doc/jose"
+	"github.com/dellekappa/kms-go/doc/jose/jwk/jwksupport"
+	cryptoapi "github.com/dellekappa/kms-go/spi/crypto"
+	"github.com/dellekappa/kms-go/spi/kms"
+	"github.com/dellekappa/kms-go/spi/secretlock"
+)
+
+func TestJWEEncryptToEd25519Recipients(t *testing.T) {
+	km := newLocalKMS(t)
+
+	c, err := tinkcrypto.New()
+	require.NoError(t, err)
+
+	edRecKeys := createEd25519Recipients(t, km, 2)
+
+	x25519RecKeys, x25519RecKHs, _, _ := createRecipientsByKeyTemplate(t, 1, ecdh.X25519ECDHKWKeyTemplate(),
+		kms.X25519ECDHKWType)
+	_, x25519KMS := createCryptoAndKMSServices(t, x25519RecKHs)
+
+	pt := []byte("secret message for Ed25519 recipients")
+
+	tests := []struct {
+		name    string
+		recKeys []*cryptoapi.PublicKey
+		enc     ariesjose.EncAlg
+		opts    []ariesjose.JWEEncryptOpt
+	}{
+		{
+			name:    "single Ed25519 recipient",
+			recKeys: edRecKeys[:1],
+			enc:     ariesjose.XC20P,
+		},
+		{
+			name:    "multiple Ed25519 recipients",
+			recKeys: edRecKeys,
+			enc:     ariesjose.A256GCM,
+		},
+		{
+			name:    "Ed25519 recipient with direct key agreement",
+			recKeys: edRecKeys[:1],
+			enc:     ariesjose.A256CBCHS512,
+			opts:    []ariesjose.JWEEncryptOpt{ariesjose.WithDirectKeyAgreement()},
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			jweEncrypter, err := ariesjose.NewJWEEncrypt(tc.enc, EnvelopeEncodingType, DIDCommContentEncodingType,
+				"", nil, tc.recKeys, c, tc.opts...)
+			require.NoError(t, err)
+
+			jwe, err := jweEncrypter.Encrypt(pt)
+			require.NoError(t, err)
+			require.Equal(t, ariesjose.KeyConversionEd25519ToX25519,
+				jwe.ProtectedHeaders[ariesjose.HeaderKeyConversion])
+
+			serializedJWE, err := jwe.FullSerialize(json.Marshal)
+			require.NoError(t, err)
+
+			localJWE, err := ariesjose.Deserialize(serializedJWE)
+			require.NoError(t, err)
+
+			msg, err := ariesjose.NewJWEDecrypt(nil, c, km).Decrypt(localJWE)
+			require.NoError(t, err)
+			require.EqualValues(t, pt, msg)
+		})
+	}
+
+	t.Run("recipient keys are not modified", func(t *testing.T) {
+		_, err := ariesjose.NewJWEEncrypt(ariesjose.XC20P, EnvelopeEncodingType, DIDCommContentEncodingType,
+			"", nil, edRecKeys, c)
+		require.NoError(t, err)
+		require.Equal(t, "Ed25519", edRecKeys[0].Curve)
+	})
+
+	t.Run("Ed25519 and X25519 recipients", func(t *testing.T) {
+		jweEncrypter, err := ariesjose.NewJWEEncrypt(ariesjose.XC20P, EnvelopeEncodingType,
+			DIDCommContentEncodingType, "", nil, append([]*cryptoapi.PublicKey{edRecKeys[0]}, x25519RecKeys...), c)
+		require.NoError(t, err)
+
+		jwe, err := jweEncrypter.Encrypt(pt)
+		require.NoError(t, err)
+
+		serializedJWE, err := jwe.FullSerialize(json.Marshal)
+		require.NoError(t, err)
+
+		localJWE, err := ariesjose.Deserialize(serializedJWE)
+		require.NoError(t, err)
+
+		msg, err := ariesjose.NewJWEDecrypt(nil, c, km).Decrypt(localJWE)
+		require.NoError(t, err)
+		require.EqualValues(t, pt, msg)
+
+		localJWE, err = ariesjose.Deserialize(serializedJWE)
+		require.NoError(t, err)
+
+		// X25519 key handles are used as is.
+		msg, err = ariesjose.NewJWEDecrypt(nil, c, x25519KMS).Decrypt(localJWE)
+		require.NoError(t, err)
+		require.EqualValues(t, pt, msg)
+	})
+
+	t.Run("X25519 recipients only do not set the key conversion header", func(t *testing.T) {
+		jweEncrypter, err := ariesjose.NewJWEEncrypt(ariesjose.XC20P, EnvelopeEncodingType,
+			DIDCommContentEncodingType, "", nil, x25519RecKeys, c)
+		require.NoError(t, err)
+
+		jwe, err := jweEncrypter.Encrypt(pt)
+		require.NoError(t, err)
+		require.NotContains(t, jwe.ProtectedHeaders, ariesjose.HeaderKeyConversion)
+	})
+
+	t.Run("error without the key conversion header", func(t *testing.T) {
+		jweEncrypter, err := ariesjose.NewJWEEncrypt(ariesjose.XC20P, EnvelopeEncodingType,
+			DIDCommContentEncodingType, "", nil, edRecKeys[:1], c)
+		require.NoError(t, err)
+
+		jwe, err := jweEncrypter.Encrypt(pt)
+		require.NoError(t, err)
+
+		serializedJWE, err := jwe.FullSerialize(json.Marshal)
+		require.NoError(t, err)
+
+		localJWE, err := ariesjose.Deserialize(serializedJWE)
+		require.NoError(t, err)
+
+		// without the header, the recipient's Ed25519 key can't unwrap the CEK.
+		delete(localJWE.ProtectedHeaders, ariesjose.HeaderKeyConversion)
+
+		_, err = ariesjose.NewJWEDecrypt(nil, c, km).Decrypt(localJWE)
+		require.ErrorIs(t, err, ariesjose.ErrDecryptionFailed)
+	})
+
+	t.Run("error with invalid Ed25519 recipient key", func(t *testing.T) {
+		_, err := ariesjose.NewJWEEncrypt(ariesjose.XC20P, EnvelopeEncodingType, DIDCommContentEncodingType,
+			"", nil, []*cryptoapi.PublicKey{{KID: "bad", Type: "OKP", Curve: "Ed25519", X: []byte{1, 2, 3}}}, c)
+		require.ErrorContains(t, err, "convert Ed25519 recipient key 'bad' to X25519")
+	})
+}
+
+// createEd25519Recipients creates nbOfEntities Ed25519 keys in km and returns their public keys built from their JWK.
+func createEd25519Recipients(t *testing.T, km kms.KeyManager, nbOfEntities int) []*cryptoapi.PublicKey {
+	t.Helper()
+
+	var keys []*cryptoapi.PublicKey
+
+	for i := 0; i < nbOfEntities; i++ {
+		kid, pubKeyBytes, err := km.CreateAndExportPubKeyBytes(kms.ED25519Type)
+		require.NoError(t, err)
+
+		pubJWK, err := jwksupport.PubKeyBytesToJWK(pubKeyBytes, kms.ED25519Type)
+		require.NoError(t, err)
+
+		pubJWK.KeyID = kid
+
+		pubKey, err := jwksupport.PublicKeyFromJWK(pubJWK)
+		require.NoError(t, err)
+
+		keys = append(keys, pubKey)
+	}
+
+	return keys
+}
+
+func newLocalKMS(t *testing.T) kms.KeyManager {
+	t.Helper()
+
+	store, err := kmsservice.NewAriesProviderWrapper(mockstorage.NewMockStoreProvider())
+	require.NoError(t, err)
+
+	km, err := localkms.New("local-lock://primary/test/", &localKMSProvider{store: store, lock: &noop.NoLock{}})
+	require.NoError(t, err)
+
+	return km
+}
+
+type localKMSProvider struct {
+	store kms.Store
+	lock  secretlock.Service
+}
+
+func (p *localKMSProvider) StorageProvider() kms.Store {
+	return p.store
+}
+
+func (p *localKMSProvider) SecretLock() secretlock.Service {
+	return p.lock
+}
@@ -21,6 +21,7 @@ import (
 	"github.com/dellekappa/kms-go/crypto/tinkcrypto"
 	"github.com/dellekappa/kms-go/crypto/tinkcrypto/primitive/composite/ecdh"
 	"github.com/dellekappa/kms-go/crypto/tinkcrypto/primitive/composite/keyio"
+	"github.com/dellekappa/kms-go/doc/jose/jwk"
 
 	cryptoapi "github.com/dellekappa/kms-go/spi/crypto"
 )
@@ -373,3 +374,73 @@ func TestJWKMarshalEPKFailure(t *testing.T) {
 		require.EqualError(t, err, "unable to read JWK: invalid character 'b' looking for beginning of value")
 	})
 }
+
+func TestDecodeAPUAPVConcatKDFVector(t *testing.T) {
+	// test vector from https://tools.ietf.org/html/rfc7518#appendix-C, Alice's ephemeral key is the epk and Bob is the
+	// recipient.
+	epk := &jwk.JWK{}
+	require.NoError(t, epk.UnmarshalJSON([]byte(`{"kty":"EC","crv":"P-256",
+		"x":"gI0GAILBdu7T53akrFmMyGcsF3n5dO7MmwNBHKW5SV0","y":"SLW_xSffzlPWrHEVI30DHM_4egVwt3NQqeUD7nMFpps"}`)))
+
+	bobPriv := &jwk.JWK{}
+	require.NoError(t, bobPriv.UnmarshalJSON([]byte(`{"kty":"EC","crv":"P-256",
+		"x":"weNJy2HscCSM6AEDTDg04biOvhFhyyWvOHQfeF_PxMQ","y":"e8lnCO-AlStT-NJVX-crhB7QRYhiix03illJOVAOyck",
+		"d":"VEmDZpDXXK8p8N0Cndsxs924q6nS1RXFASRl6BfUqdw"}`)))
+
+	apu, apv, err := decodeAPUAPV(&RecipientHeaders{APU: "QWxpY2U", APV: "Qm9i"})
+	require.NoError(t, err)
+	require.Equal(t, []byte("Alice"), apu)
+	require.Equal(t, []byte("Bob"), apv)
+
+	key, err := RecoverCEK(bobPriv, epk, "A128GCM", apu, apv)
+	require.NoError(t, err)
+	require.Equal(t, "VqqN6vgjbSBcIijNcacQGg", base64.RawURLEncoding.EncodeToString(key))
+}
+
+// TestDecryptJWERFC7516AppendixB decrypts the A128CBC-HS256 content of the JWE example of RFC 7516 Appendix B with its
+// (already unwrapped) CEK.
+func TestDecryptJWERFC7516AppendixB(t *testing.T) {
+	cek := []byte{
+		4, 211, 31, 197, 84, 157, 252, 254, 11, 100, 157, 250, 63, 170, 106, 206,
+		107, 124, 212, 45, 111, 107, 9, 219, 200, 177, 0, 240, 143, 156, 44, 207,
+	}
+
+	compactJWE := "eyJhbGciOiJBMTI4S1ciLCJlbmMiOiJBMTI4Q0JDLUhTMjU2In0." +
+		"6KB707dM9YTIgHtLvtgWQ8mKwboJW3of9locizkDTHzBC2IlrT1oOQ." +
+		"AxY8DCtDaGlsbGljb3RoZQ." +
+		"KDlTtXchhZTGufMYmOYGS4HffxPSUrfmqCHXaI9wOGY." +
+		"U0m_YmjN04DJvceFICbCVQ"
+
+	jd := &JWEDecrypt{}
+
+	t.Run("success", func(t *testing.T) {
+		jwe, err := Deserialize(compactJWE)
+		require.NoError(t, err)
+
+		pt, err := jd.decryptJWE(jwe, append([]byte{}, cek...))
+		require.NoError(t, err)
+		require.Equal(t, "Live long and prosper.", string(pt))
+	})
+
+	t.Run("tampered tag fails MAC verification", func(t *testing.T) {
+		jwe, err := Deserialize(compactJWE)
+		require.NoError(t, err)
+
+		tag := []byte(jwe.Tag)
+		tag[len(tag)-1] ^= 0x01
+		jwe.Tag = string(tag)
+
+		_, err = jd.decryptJWE(jwe, append([]byte{}, cek...))
+		require.Error(t, err)
+	})
+
+	t.Run("tampered protected header fails MAC verification", func(t *testing.T) {
+		jwe, err := Deserialize(compactJWE)
+		require.NoError(t, err)
+
+		jwe.OrigProtectedHders = "eyJhbGciOiJBMTI4S1ciLCJlbmMiOiJBMTI4Q0JDLUhTMjU2IiwieCI6MX0"
+
+		_, err = jd.decryptJWE(jwe, append([]byte{}, cek...))
+		require.Error(t, err)
+	})
+}
@@ -44,6 +44,10 @@ type EncAlg string
 const (
 	// A256GCM for AES256GCM content encryption.
 	A256GCM = EncAlg(A256GCMALG)
+	// A128GCM for AES128GCM content encryption.
+	A128GCM = EncAlg(A128GCMALG)
+	// A192GCM for AES192GCM content encryption.
+	A192GCM = EncAlg(A192GCMALG)
 	// XC20P for XChacha20Poly1305 content encryption.
 	XC20P = EncAlg(XC20PALG)
 	// A128CBCHS256 for A128CBC-HS256 (AES128-CBC+HMAC-SHA256) content encryption.
@@ -54,6 +58,8 @@ const (
 	A256CBCHS384 = EncAlg(A256CBCHS384ALG)
 	// A256CBCHS512 for A256CBC-HS512 (AES256-CBC+HMAC-SHA512) content encryption.
 	A256CBCHS512 = EncAlg(A256CBCHS512ALG)
+	// A256GCMKC for A256GCM-KC (AES256GCM with an HMAC-SHA256 key commitment) content encryption.
+	A256GCMKC = EncAlg(A256GCMKCALG)
 )
 
 // Encrypter interface to Encrypt/Decrypt JWE messages.
@@ -71,22 +77,116 @@ type JWEEncrypt struct {
 	recipientsKeys []*cryptoapi.PublicKey
 	skid           string
 	senderKH       *keyset.Handle
+	kmsSenderKey   *cryptoapi.KMSSenderKey
+	aeadProviders  map[EncAlg]NewAEADProviderFunc
 	encAlg         EncAlg
 	encTyp         string
 	cty            string
 	crypto         cryptoapi.Crypto
+	apu            []byte
+	apv            []byte
+	compress       bool
+	direct         bool
+	// fromEd25519 is set if the key of at least one recipient was converted from Ed25519 to X25519.
+	fromEd25519 bool
+}
+
+// jweEncryptOpts holds options for the JWEEncrypt.
+type jweEncryptOpts struct {
+	apu           []byte
+	apv           []byte
+	compress      bool
+	direct        bool
+	kmsSenderKey  *cryptoapi.KMSSenderKey
+	headerOpts    []HeaderOpt
+	aeadProviders map[EncAlg]NewAEADProviderFunc
+}
+
+// JWEEncryptOpt is the JWEEncrypt option.
+type JWEEncryptOpt func(opts *jweEncryptOpts)
+
+// WithAgreementPartyUInfo option sets the ECDH key agreement PartyUInfo (producer information) fed into the Concat KDF
+// otherInfo as per https://tools.ietf.org/html/rfc7518#section-4.6.2. apu is the raw (not base64url encoded) value, it
+// is base64url encoded in the "apu" JWE header.
+func WithAgreementPartyUInfo(apu []byte) JWEEncryptOpt {
+	return func(opts *jweEncryptOpts) {
+		opts.apu = apu
+	}
+}
+
+// WithAgreementPartyVInfo option sets the ECDH key agreement PartyVInfo (recipient information) fed into the Concat
+// KDF otherInfo as per https://tools.ietf.org/html/rfc7518#section-4.6.2. apv is the raw (not base64url encoded) value,
+// it is base64url encoded in the "apv" JWE header.
+func WithAgreementPartyVInfo(apv []byte) JWEEncryptOpt {
+	return func(opts *jweEncryptOpts) {
+		opts.apv = apv
+	}
+}
+
+// WithCompression option enables the DEFLATE compression of the plaintext before content encryption. The "zip"
+// protected header is set to "DEF" so that recipients inflate the plaintext after decryption.
+func WithCompression(compress bool) JWEEncryptOpt {
+	return func(opts *jweEncryptOpts) {
+		opts.compress = compress
+	}
+}
+
+// WithDirectKeyAgreement option enables ECDH-ES Direct Key Agreement (alg "ECDH-ES") as per
+// https://tools.ietf.org/html/rfc7518#section-4.6: the Concat KDF output of the ephemeral key agreement is used as the
+// CEK, sized for the content encryption algorithm, and no encrypted key is emitted. It requires a single recipient and
+// is not supported with Authcrypt.
+func WithDirectKeyAgreement() JWEEncryptOpt {
+	return func(opts *jweEncryptOpts) {
+		opts.direct = true
+	}
+}
+
+// WithKMSSenderKey option sets an Authcrypt (ECDH-1PU) sender key kept in a KMS, in place of the senderKH private key
+// handle of NewJWEEncrypt, for KMSs whose private keys can't be exported: the sender part of the ECDH-1PU shared secret
+// is computed by senderKey.KeyAgreer (e.g. a wrapper api.KeyAgreer) with the senderKey.KID key. The senderKID argument
+// of NewJWEEncrypt is still required, it is set as the skid header.
+func WithKMSSenderKey(senderKey *cryptoapi.KMSSenderKey) JWEEncryptOpt {
+	return func(opts *jweEncryptOpts) {
+		opts.kmsSenderKey = senderKey
+	}
+}
+
+// WithHeaderOpts option sets the protected headers of the JWE with the JWS and JWE builders HeaderOpts, e.g.
+// WithHeaderOpts(WithType("JWT"), WithContentType("JWT")). WithType and WithContentType take precedence over the
+// envelopMediaType and cty arguments of NewJWEEncrypt.
+func WithHeaderOpts(headerOpts ...HeaderOpt) JWEEncryptOpt {
+	return func(opts *jweEncryptOpts) {
+		opts.headerOpts = append(opts.headerOpts, headerOpts...)
+	}
+}
+
+// WithEncryptAEADProvider option encrypts the content of JWEs whose enc is encAlg with the AEADProviders created by
+// newProvider, instead of the built-in implementation of encAlg. The CEK is generated or derived as usual, with the
+// size of encAlg.
+func WithEncryptAEADProvider(encAlg EncAlg, newProvider NewAEADProviderFunc) JWEEncryptOpt {
+	return func(opts *jweEncryptOpts) {
+		if opts.aeadProviders == nil {
+			opts.aeadProviders = map[EncAlg]NewAEADProviderFunc{}
+		}
+
+		opts.aeadProviders[encAlg] = newProvider
+	}
 }
 
 // NewJWEEncrypt creates a new JWEEncrypt instance to build JWE with recipientsPubKeys
 // senderKID and senderKH are used for Authcrypt (to authenticate the sender), if not set JWEEncrypt assumes Anoncrypt.
+// Without the WithAgreementPartyUInfo and WithAgreementPartyVInfo options, Anoncrypt uses the ephemeral public key as
+// apu and an empty apv, Authcrypt uses senderKID as apu and the SHA256 of the sorted recipients KIDs as apv.
+// Ed25519 (OKP) recipient keys are converted to X25519 keys for ECDH key agreement and the HeaderKeyConversion
+// protected header is set.
 func NewJWEEncrypt(encAlg EncAlg, envelopMediaType, cty, senderKID string, senderKH *keyset.Handle,
-	recipientsPubKeys []*cryptoapi.PublicKey, crypto cryptoapi.Crypto) (*JWEEncrypt, error) {
+	recipientsPubKeys []*cryptoapi.PublicKey, crypto cryptoapi.Crypto, opts ...JWEEncryptOpt) (*JWEEncrypt, error) {
 	if len(recipientsPubKeys) == 0 {
 		return nil, fmt.Errorf("empty recipientsPubKeys list")
 	}
 
 	switch encAlg {
-	case A256GCM, XC20P, A128CBCHS256, A192CBCHS384, A256CBCHS384, A256CBCHS512:
+	case A128GCM, A192GCM, A256GCM, XC20P, A128CBCHS256, A192CBCHS384, A256CBCHS384, A256CBCHS512, A256GCMKC:
 	default:
 		return nil, fmt.Errorf("encryption algorithm '%s' not supported", encAlg)
 	}
@@ -102,18 +202,70 @@ func NewJWEEncrypt(encAlg EncAlg, envelopMediaType, cty, senderKID string, sende
 		}
 	}
 
+	eOpts := &jweEncryptOpts{}
+
+	for _, opt := range opts {
+		opt(eOpts)
+	}
+
+	if eOpts.kmsSenderKey != nil {
+		if senderKH != nil {
+			return nil, errors.New("senderKH and a KMS sender key can't be both set")
+		}
+
+		if senderKID == "" {
+			return nil, errors.New("senderKID is required with a KMS sender key")
+		}
+	}
+
+	if eOpts.direct {
+		if len(recipientsPubKeys) != 1 {
+			return nil, errors.New("direct key agreement requires a single recipient")
+		}
+
+		if senderKH != nil || eOpts.kmsSenderKey != nil {
+			return nil, errors.New("direct key agreement is not supported with a sender key")
+		}
+	}
+
+	recipientsPubKeys, fromEd25519, err := convertEd25519Recipients(recipientsPubKeys)
+	if err != nil {
+		return nil, err
+	}
+
+	if newHeaderOpts(eOpts.headerOpts).embedJWK {
+		return nil, errors.New("embedded jwk header is only supported by JWS")
+	}
+
+	headers := Headers{HeaderType: envelopMediaType, HeaderContentType: cty}
+	applyHeaderOpts(headers, eOpts.headerOpts)
+
+	envelopMediaType, _ = headers.Type()
+	cty, _ = headers.ContentType()
+
 	return &JWEEncrypt{
 		recipientsKeys: recipientsPubKeys,
 		skid:           senderKID,
 		senderKH:       senderKH,
+		kmsSenderKey:   eOpts.kmsSenderKey,
+		aeadProviders:  eOpts.aeadProviders,
 		encAlg:         encAlg,
 		encTyp:         envelopMediaType,
 		cty:            cty,
 		crypto:         crypto,
+		apu:            eOpts.apu,
+		apv:            eOpts.apv,
+		compress:       eOpts.compress,
+		direct:         eOpts.direct,
+		fromEd25519:    fromEd25519,
 	}, nil
 }
 
 func (je *JWEEncrypt) getECDHEncPrimitive(cek []byte) (api.CompositeEncrypt, error) {
+	if newProvider, ok := je.aeadProviders[je.encAlg]; ok {
+		return newAEADProviderPrimitive(newProvider, cek)
+	}
+
 	nistpKW := je.useNISTPKW()
 
 	encAlg, ok := aeadAlg[je.encAlg]
@@ -143,6 +295,23 @@ func (je *JWEEncrypt) Encrypt(plaintext []byte) (*JSONWebEncryption, error) {
 
 // EncryptWithAuthData encrypt plaintext with AAD and returns a JSONWebEncryption instance to serialize a JWE instance.
 func (je *JWEEncrypt) EncryptWithAuthData(plaintext, aad []byte) (*JSONWebEncryption, error) {
+	protectedHeaders, plaintext, err := je.buildProtectedHeaders(plaintext)
+	if err != nil {
+		return nil, fmt.Errorf("jweencrypt: %w", err)
+	}
+
+	if je.direct {
+		return je.encryptDirect(protectedHeaders, plaintext, aad)
+	}
+
+	cek := je.newCEK()
+
+	return je.encryptWithCEK(protectedHeaders, plaintext, aad, cek, cek)
+}
+
+// buildProtectedHeaders returns the common protected headers of a JWE with plaintext compressed if compression is
+// enabled.
+func (je *JWEEncrypt) buildProtectedHeaders(plaintext []byte) (map[string]interface{}, []byte, error) {
 	protectedHeaders := map[string]interface{}{
 		HeaderEncryption: je.encAlg,
 		HeaderType:       je.encTyp,
@@ -150,7 +319,27 @@ func (je *JWEEncrypt) EncryptWithAuthData(plaintext, aad []byte) (*JSONWebEncryp
 
 	je.addExtraProtectedHeaders(protectedHeaders)
 
-	cek := je.newCEK()
+	if je.compress {
+		protectedHeaders[HeaderCompression] = CompressionDEFLATE
+
+		var err error
+
+		plaintext, err = deflate(plaintext)
+		if err != nil {
+			return nil, nil, err
+		}
+	}
+
+	return protectedHeaders, plaintext, nil
+}
+
+// encryptWithCEK encrypts plaintext with cek and wraps wrappedKey for the recipients. wrappedKey is cek itself unless
+// cek is wrapped under a master key (see EncryptWithMasterCEK).
+func (je *JWEEncrypt) encryptWithCEK(protectedHeaders map[string]interface{}, plaintext, aad, cek,
+	wrappedKey []byte) (*JSONWebEncryption, error) {
+	if je.encAlg == A256GCMKC {
+		protectedHeaders[HeaderKeyCommitment] = base64.RawURLEncoding.EncodeToString(keyCommitment(cek))
+	}
 
 	// creating the crypto primitive requires a pre-built cek
 	encPrimitive, err := je.getECDHEncPrimitive(cek)
@@ -163,17 +352,17 @@ func (je *JWEEncrypt) EncryptWithAuthData(plaintext, aad []byte) (*JSONWebEncryp
 		return nil, fmt.Errorf("jweencrypt: computeAuthData: marshal error %w", err)
 	}
 
-	if je.senderKH != nil && je.skid != "" {
+	if je.hasSender() {
 		// ecdh-1pu encryption requires CBC+HMAC encAlg types.
 		return je.encryptWithSender(encPrimitive, plaintext, authData, cek, aad)
 	}
 
-	return je.encrypt(protectedHeaders, encPrimitive, plaintext, authData, cek, aad)
+	return je.encrypt(protectedHeaders, encPrimitive, plaintext, authData, wrappedKey, aad)
 }
 
 func (je *JWEEncrypt) encrypt(protectedHeaders map[string]interface{}, encPrimitive api.CompositeEncrypt,
 	plaintext, authData, cek, aad []byte) (*JSONWebEncryption, error) {
-	recipients, singleRecipientHeaderADDs, err := je.wrapCEKForRecipients(cek, []byte{}, []byte{}, authData, json.Marshal)
+	recipients, singleRecipientHeaderADDs, err := je.wrapCEKForRecipients(cek, je.apu, je.apv, authData, json.Marshal)
 	if err != nil {
 		return nil, fmt.Errorf("jweencrypt: failed to wrap cek: %w", err)
 	}
@@ -355,6 +544,11 @@ func (je *JWEEncrypt) encodeAPUAPV(kek *cryptoapi.RecipientWrappedKey) {
 	}
 }
 
+// hasSender reports whether the JWE is encrypted with Authcrypt (ECDH-1PU) with a sender key handle or a KMS sender key.
+func (je *JWEEncrypt) hasSender() bool {
+	return je.skid != "" && (je.senderKH != nil || je.kmsSenderKey != nil)
+}
+
 func (je *JWEEncrypt) getWrapKeyOpts(tag []byte, epk *cryptoapi.PrivateKey) []cryptoapi.WrapKeyOpts {
 	var wrapOpts []cryptoapi.WrapKeyOpts
 
@@ -362,8 +556,12 @@ func (je *JWEEncrypt) getWrapKeyOpts(tag []byte, epk *cryptoapi.PrivateKey) []cr
 		wrapOpts = append(wrapOpts, cryptoapi.WithXC20PKW())
 	}
 
-	if je.skid != "" && je.senderKH != nil {
-		wrapOpts = append(wrapOpts, cryptoapi.WithSender(je.senderKH))
+	if je.hasSender() {
+		if je.senderKH != nil {
+			wrapOpts = append(wrapOpts, cryptoapi.WithSender(je.senderKH))
+		} else {
+			wrapOpts = append(wrapOpts, cryptoapi.WithSender(je.kmsSenderKey))
+		}
 	}
 
 	if len(tag) > 0 {
@@ -542,6 +740,10 @@ func (je *JWEEncrypt) addExtraProtectedHeaders(protectedHeaders map[string]inter
 	if je.skid != "" {
 		protectedHeaders[HeaderSenderKeyID] = je.skid
 	}
+
+	if je.fromEd25519 {
+		protectedHeaders[HeaderKeyConversion] = KeyConversionEd25519ToX25519
+	}
 }
 
 func (je *JWEEncrypt) useNISTPKW() bool {
@@ -564,22 +766,31 @@ func (je *JWEEncrypt) useNISTPKW() bool {
 }
 
 func (je *JWEEncrypt) newCEK() []byte {
+	return random.GetRandomBytes(uint32(cekSize(je.encAlg)))
+}
+
+// cekSize returns the size in bytes of the content encryption key of encAlg.
+func cekSize(encAlg EncAlg) int {
 	twoKeys := 2
 	defKeySize := 32
 
-	switch je.encAlg {
-	case A256GCM, XC20P:
-		return random.GetRandomBytes(uint32(defKeySize))
+	switch encAlg {
+	case A128GCM:
+		return subtle.AES128Size // cek: 16 bytes.
+	case A192GCM:
+		return subtle.AES192Size // cek: 24 bytes.
+	case A256GCM, XC20P, A256GCMKC:
+		return defKeySize
 	case A128CBCHS256:
-		return random.GetRandomBytes(uint32(subtle.AES128Size * twoKeys)) // cek: 32 bytes.
+		return subtle.AES128Size * twoKeys // cek: 32 bytes.
 	case A192CBCHS384:
-		return random.GetRandomBytes(uint32(subtle.AES192Size * twoKeys)) // cek: 48 bytes.
+		return subtle.AES192Size * twoKeys // cek: 48 bytes.
 	case A256CBCHS384:
-		return random.GetRandomBytes(uint32(subtle.AES256Size + subtle.AES192Size)) // cek: 56 bytes.
+		return subtle.AES256Size + subtle.AES192Size // cek: 56 bytes.
 	case A256CBCHS512:
-		return random.GetRandomBytes(uint32(subtle.AES256Size * twoKeys)) // cek: 64 bytes.
+		return subtle.AES256Size * twoKeys // cek: 64 bytes.
 	default:
-		return random.GetRandomBytes(uint32(defKeySize)) // default cek: 32 bytes.
+		return defKeySize // default cek: 32 bytes.
 	}
 }
 
@@ -597,6 +808,18 @@ func (je *JWEEncrypt) buildAPUAPV() ([]byte, []byte, error) {
 	apu := make([]byte, len(je.skid))
 	copy(apu, je.skid)
 
+	if len(je.apu) > 0 {
+		apu = make([]byte, len(je.apu))
+		copy(apu, je.apu)
+	}
+
+	if len(je.apv) > 0 {
+		apv := make([]byte, len(je.apv))
+		copy(apv, je.apv)
+
+		return apu, apv, nil
+	}
+
 	for _, r := range je.recipientsKeys {
 		recKIDs = append(recKIDs, r.KID)
 	}
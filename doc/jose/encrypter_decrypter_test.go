@@ -8,6 +8,8 @@ package jose_test
 
 import (
 	"bytes"
+	"crypto/aes"
+	"crypto/cipher"
 	"crypto/ecdsa"
 	"crypto/ed25519"
 	"crypto/rand"
@@ -22,10 +24,12 @@ import (
 	"time"
 
 	"github.com/go-jose/go-jose/v3"
+	josecipher "github.com/go-jose/go-jose/v3/cipher"
 	hybrid "github.com/google/tink/go/hybrid/subtle"
 	"github.com/google/tink/go/keyset"
 	tinkpb "github.com/google/tink/go/proto/tink_go_proto"
 	"github.com/google/tink/go/subtle"
+	"github.com/google/tink/go/subtle/random"
 	"github.com/stretchr/testify/require"
 
 	mockstorage "github.com/dellekappa/kms-go/internal/mock/storage"
@@ -62,11 +66,9 @@ func TestJWEEncryptRoundTrip(t *testing.T) {
 	require.EqualError(t, err, "empty recipientsPubKeys list",
 		"NewJWEEncrypt should fail with empty recipientPubKeys")
 
-	singleRecipientNISTPKWError := "jwedecrypt: failed to unwrap cek: [unwrapKey: deriveKEKAndUnwrap:" +
-		" failed to AES unwrap key: go-jose/go-jose: key wrap input must be 8 byte blocks]"
+	singleRecipientNISTPKWError := "jwedecrypt: jwe decryption failed"
 
-	singleRecipientX25519KWError := "jwedecrypt: failed to unwrap cek: [unwrapKey: deriveKEKAndUnwrap: failed to XC20P " +
-		"unwrap key: unwrap support: OKP unwrap invalid key]"
+	singleRecipientX25519KWError := "jwedecrypt: jwe decryption failed"
 
 	multiRecKWError := "jwedecrypt: failed to build recipients WK: unable to read " +
 		"JWK: invalid character 's' looking for beginning of value"
@@ -88,6 +90,23 @@ func TestJWEEncryptRoundTrip(t *testing.T) {
 			nbRec:            2,
 			recipientKWError: multiRecKWError,
 		},
+		{
+			name:             "P-256 ECDH KW and A256GCM-KC encryption with 2 recipients (Full serialization)",
+			kt:               ecdh.NISTP256ECDHKWKeyTemplate(),
+			enc:              ariesjose.A256GCMKC,
+			keyType:          kms.NISTP256ECDHKWType,
+			nbRec:            2,
+			recipientKWError: multiRecKWError,
+		},
+		{
+			name:             "P-256 ECDH KW and A256GCM-KC encryption with 1 recipient (Compact serialization)",
+			kt:               ecdh.NISTP256ECDHKWKeyTemplate(),
+			enc:              ariesjose.A256GCMKC,
+			keyType:          kms.NISTP256ECDHKWType,
+			nbRec:            1,
+			useCompact:       true,
+			recipientKWError: singleRecipientNISTPKWError,
+		},
 		{
 			name:             "P-256 ECDH KW and AES256GCM encryption with 1 recipient (Flattened serialization)",
 			kt:               ecdh.NISTP256ECDHKWKeyTemplate(),
@@ -1102,6 +1121,76 @@ func TestFailNewJWEEncrypt(t *testing.T) {
 			kids[0], recsKH[kids[0]], recipients, nil)
 		require.EqualError(t, err, "crypto service is required to create a JWEEncrypt instance")
 	})
+
+	t.Run("test with senderKH and KMS sender key", func(t *testing.T) {
+		_, err = ariesjose.NewJWEEncrypt(ariesjose.A256GCM, EnvelopeEncodingType, DIDCommContentEncodingType,
+			kids[0], recsKH[kids[0]], recipients, c, ariesjose.WithKMSSenderKey(&cryptoapi.KMSSenderKey{KID: kids[0]}))
+		require.EqualError(t, err, "senderKH and a KMS sender key can't be both set")
+	})
+
+	t.Run("test KMS sender key with missing skid", func(t *testing.T) {
+		_, err = ariesjose.NewJWEEncrypt(ariesjose.A256GCM, EnvelopeEncodingType, DIDCommContentEncodingType,
+			"", nil, recipients, c, ariesjose.WithKMSSenderKey(&cryptoapi.KMSSenderKey{KID: kids[0]}))
+		require.EqualError(t, err, "senderKID is required with a KMS sender key")
+	})
+}
+
+// kmsKeyAgreer computes the ECDH-1PU sender shared secret with the key handles of a KMS.
+type kmsKeyAgreer struct {
+	c    *tinkcrypto.Crypto
+	keys map[string]*keyset.Handle
+}
+
+func (a *kmsKeyAgreer) ComputeSharedSecret(senderKID string, recipientPub *cryptoapi.PublicKey) ([]byte, error) {
+	return a.c.ComputeSharedSecret(recipientPub, a.keys[senderKID])
+}
+
+func TestECDH1PUWithKMSSenderKey(t *testing.T) {
+	senders, senderKHs, senderKIDs, _ := createRecipients(t, 1)
+	recipientsKeys, recKHs, _, _ := createRecipients(t, 2)
+
+	cryptoSvc, kmsSvc := createCryptoAndKMSServices(t, recKHs)
+
+	c, err := tinkcrypto.New()
+	require.NoError(t, err)
+
+	senderKey := &cryptoapi.KMSSenderKey{
+		KID:       senderKIDs[0],
+		KeyAgreer: &kmsKeyAgreer{c: c, keys: senderKHs},
+	}
+
+	jweEncrypter, err := ariesjose.NewJWEEncrypt(ariesjose.A256CBCHS512, EnvelopeEncodingType,
+		DIDCommContentEncodingType, senderKIDs[0], nil, recipientsKeys, cryptoSvc,
+		ariesjose.WithKMSSenderKey(senderKey))
+	require.NoError(t, err)
+
+	pt := []byte("secret message")
+
+	jwe, err := jweEncrypter.Encrypt(pt)
+	require.NoError(t, err)
+
+	skid, ok := jwe.ProtectedHeaders.SenderKeyID()
+	require.True(t, ok)
+	require.Equal(t, senderKIDs[0], skid)
+
+	senderPubKey, err := json.Marshal(senders[0])
+	require.NoError(t, err)
+
+	mockStore := &mockstorage.MockStore{
+		Store: map[string]mockstorage.DBEntry{senderKIDs[0]: {Value: senderPubKey}},
+	}
+
+	jd := ariesjose.NewJWEDecrypt([]resolver.KIDResolver{&resolver.StoreResolver{Store: mockStore}}, cryptoSvc, kmsSvc)
+
+	serializedJWE, err := jwe.FullSerialize(json.Marshal)
+	require.NoError(t, err)
+
+	localJWE, err := ariesjose.Deserialize(serializedJWE)
+	require.NoError(t, err)
+
+	msg, err := jd.Decrypt(localJWE)
+	require.NoError(t, err)
+	require.Equal(t, pt, msg)
 }
 
 //nolint:gocognit
@@ -1705,3 +1794,686 @@ func convertX25519ToKH(t *testing.T, keys, kids []string) map[string]*keyset.Han
 
 	return khs
 }
+
+func TestInteropAgreementPartyInfo(t *testing.T) {
+	apu := []byte("Alice")
+	apv := []byte("Bob")
+
+	c, err := tinkcrypto.New()
+	require.NoError(t, err)
+
+	t.Run("local jose encrypt with apu and apv and go-jose decrypt", func(t *testing.T) {
+		for _, nbRecipients := range []int{1, 3} {
+			var (
+				recECKeys   []*cryptoapi.PublicKey
+				recPrivKeys []*ecdsa.PrivateKey
+			)
+
+			for i := 0; i < nbRecipients; i++ {
+				recPrivKey, e := ecdsa.GenerateKey(subtle.GetCurve("NIST_P256"), rand.Reader)
+				require.NoError(t, e)
+
+				recPrivKeys = append(recPrivKeys, recPrivKey)
+				recECKeys = append(recECKeys, &cryptoapi.PublicKey{
+					KID:   fmt.Sprintf("kid-%d", i),
+					X:     recPrivKey.PublicKey.X.Bytes(),
+					Y:     recPrivKey.PublicKey.Y.Bytes(),
+					Curve: recPrivKey.PublicKey.Curve.Params().Name,
+					Type:  "EC",
+				})
+			}
+
+			jweEncrypter, e := ariesjose.NewJWEEncrypt(ariesjose.A256GCM, EnvelopeEncodingType,
+				DIDCommContentEncodingType, "", nil, recECKeys, c,
+				ariesjose.WithAgreementPartyUInfo(apu), ariesjose.WithAgreementPartyVInfo(apv))
+			require.NoError(t, e)
+
+			pt := []byte("some msg")
+			jwe, e := jweEncrypter.Encrypt(pt)
+			require.NoError(t, e)
+
+			serializedJWE, e := jwe.FullSerialize(json.Marshal)
+			require.NoError(t, e)
+
+			if nbRecipients == 1 {
+				require.Equal(t, "QWxpY2U", jwe.ProtectedHeaders["apu"])
+				require.Equal(t, "Qm9i", jwe.ProtectedHeaders["apv"])
+			} else {
+				require.Equal(t, "QWxpY2U", jwe.Recipients[0].Header.APU)
+				require.Equal(t, "Qm9i", jwe.Recipients[0].Header.APV)
+			}
+
+			gjParsedJWE, e := jose.ParseEncrypted(serializedJWE)
+			require.NoError(t, e)
+
+			// go-jose feeds the base64url decoded apu and apv headers into the Concat KDF.
+			for _, recPrivKey := range recPrivKeys {
+				_, _, msg, e := gjParsedJWE.DecryptMulti(recPrivKey)
+				require.NoError(t, e)
+				require.EqualValues(t, pt, msg)
+			}
+		}
+	})
+
+	t.Run("decrypt JWE with apu and apv in the shared protected headers", func(t *testing.T) {
+		recECKeys, recKHs, recKIDs, _ := createRecipients(t, 2)
+		cr, k := createCryptoAndKMSServices(t, recKHs)
+
+		pt := []byte("Test secret message")
+		serializedJWE := encryptWithSharedAgreementPartyInfo(t, pt, apu, apv, recECKeys, recKIDs)
+
+		localJWE, err := ariesjose.Deserialize(serializedJWE)
+		require.NoError(t, err)
+
+		msg, err := ariesjose.NewJWEDecrypt(nil, cr, k).Decrypt(localJWE)
+		require.NoError(t, err)
+		require.EqualValues(t, pt, msg)
+	})
+}
+
+// encryptWithSharedAgreementPartyInfo builds an ECDH-ES+A256KW/A256GCM JWE with the apu and apv headers set in the
+// protected headers shared by all recipients, as done by other JOSE implementations.
+func encryptWithSharedAgreementPartyInfo(t *testing.T, pt, apu, apv []byte, recKeys []*cryptoapi.PublicKey,
+	recKIDs []string) string {
+	t.Helper()
+
+	protectedHeaders, err := json.Marshal(map[string]interface{}{
+		"enc": "A256GCM",
+		"apu": base64.RawURLEncoding.EncodeToString(apu),
+		"apv": base64.RawURLEncoding.EncodeToString(apv),
+	})
+	require.NoError(t, err)
+
+	b64ProtectedHeaders := base64.RawURLEncoding.EncodeToString(protectedHeaders)
+
+	cek := make([]byte, 32)
+	_, err = rand.Read(cek)
+	require.NoError(t, err)
+
+	var recipients []map[string]interface{}
+
+	for i, recKey := range recKeys {
+		curve := subtle.GetCurve(recKey.Curve)
+		recPubKey := &ecdsa.PublicKey{
+			Curve: curve,
+			X:     new(big.Int).SetBytes(recKey.X),
+			Y:     new(big.Int).SetBytes(recKey.Y),
+		}
+
+		epk, e := ecdsa.GenerateKey(curve, rand.Reader)
+		require.NoError(t, e)
+
+		kek := josecipher.DeriveECDHES(string(jose.ECDH_ES_A256KW), apu, apv, epk, recPubKey, 32)
+
+		block, e := aes.NewCipher(kek)
+		require.NoError(t, e)
+
+		encryptedKey, e := josecipher.KeyWrap(block, cek)
+		require.NoError(t, e)
+
+		recipients = append(recipients, map[string]interface{}{
+			"header": map[string]interface{}{
+				"alg": string(jose.ECDH_ES_A256KW),
+				"kid": recKIDs[i],
+				"epk": &jose.JSONWebKey{Key: &epk.PublicKey},
+			},
+			"encrypted_key": base64.RawURLEncoding.EncodeToString(encryptedKey),
+		})
+	}
+
+	block, err := aes.NewCipher(cek)
+	require.NoError(t, err)
+
+	gcm, err := cipher.NewGCM(block)
+	require.NoError(t, err)
+
+	iv := make([]byte, gcm.NonceSize())
+	_, err = rand.Read(iv)
+	require.NoError(t, err)
+
+	sealed := gcm.Seal(nil, iv, pt, []byte(b64ProtectedHeaders))
+	tagIdx := len(sealed) - gcm.Overhead()
+
+	jwe, err := json.Marshal(map[string]interface{}{
+		"protected":  b64ProtectedHeaders,
+		"recipients": recipients,
+		"iv":         base64.RawURLEncoding.EncodeToString(iv),
+		"ciphertext": base64.RawURLEncoding.EncodeToString(sealed[:tagIdx]),
+		"tag":        base64.RawURLEncoding.EncodeToString(sealed[tagIdx:]),
+	})
+	require.NoError(t, err)
+
+	return string(jwe)
+}
+
+func TestJWEKeyCommitment(t *testing.T) {
+	recECKeys, recKHs, _, _ := createRecipients(t, 2)
+	c, k := createCryptoAndKMSServices(t, recKHs)
+
+	jweEncrypter, err := ariesjose.NewJWEEncrypt(ariesjose.A256GCMKC, EnvelopeEncodingType,
+		DIDCommContentEncodingType, "", nil, recECKeys, c)
+	require.NoError(t, err)
+
+	pt := []byte("some msg")
+
+	jwe, err := jweEncrypter.Encrypt(pt)
+	require.NoError(t, err)
+	require.Equal(t, ariesjose.A256GCMKC, jwe.ProtectedHeaders[ariesjose.HeaderEncryption])
+	require.NotEmpty(t, jwe.ProtectedHeaders[ariesjose.HeaderKeyCommitment])
+
+	serializedJWE, err := jwe.FullSerialize(json.Marshal)
+	require.NoError(t, err)
+
+	jweDecrypter := ariesjose.NewJWEDecrypt(nil, c, k)
+
+	t.Run("success", func(t *testing.T) {
+		localJWE, e := ariesjose.Deserialize(serializedJWE)
+		require.NoError(t, e)
+
+		msg, e := jweDecrypter.Decrypt(localJWE)
+		require.NoError(t, e)
+		require.EqualValues(t, pt, msg)
+	})
+
+	t.Run("error commitment mismatch", func(t *testing.T) {
+		localJWE, e := ariesjose.Deserialize(serializedJWE)
+		require.NoError(t, e)
+
+		localJWE.ProtectedHeaders[ariesjose.HeaderKeyCommitment] = base64.RawURLEncoding.EncodeToString(
+			make([]byte, 32))
+
+		_, e = jweDecrypter.Decrypt(localJWE)
+		require.ErrorIs(t, e, ariesjose.ErrDecryptionFailed)
+	})
+
+	t.Run("error missing commitment", func(t *testing.T) {
+		localJWE, e := ariesjose.Deserialize(serializedJWE)
+		require.NoError(t, e)
+
+		delete(localJWE.ProtectedHeaders, ariesjose.HeaderKeyCommitment)
+
+		_, e = jweDecrypter.Decrypt(localJWE)
+		require.EqualError(t, e, "jwedecrypt: kc header is required with A256GCM-KC content encryption")
+	})
+
+	t.Run("error invalid commitment encoding", func(t *testing.T) {
+		localJWE, e := ariesjose.Deserialize(serializedJWE)
+		require.NoError(t, e)
+
+		localJWE.ProtectedHeaders[ariesjose.HeaderKeyCommitment] = "!"
+
+		_, e = jweDecrypter.Decrypt(localJWE)
+		require.ErrorContains(t, e, "jwedecrypt: decode kc header")
+	})
+}
+
+func TestJWECompression(t *testing.T) {
+	recECKeys, recKHs, _, _ := createRecipients(t, 2)
+	c, k := createCryptoAndKMSServices(t, recKHs)
+
+	jweEncrypter, err := ariesjose.NewJWEEncrypt(ariesjose.A256GCM, EnvelopeEncodingType,
+		DIDCommContentEncodingType, "", nil, recECKeys, c, ariesjose.WithCompression(true))
+	require.NoError(t, err)
+
+	pt := []byte(strings.Repeat(`{"some":"compressible msg"}`, 100))
+
+	jwe, err := jweEncrypter.Encrypt(pt)
+	require.NoError(t, err)
+	require.Equal(t, ariesjose.CompressionDEFLATE, jwe.ProtectedHeaders[ariesjose.HeaderCompression])
+	require.Less(t, len(jwe.Ciphertext), len(pt))
+
+	serializedJWE, err := jwe.FullSerialize(json.Marshal)
+	require.NoError(t, err)
+
+	t.Run("success", func(t *testing.T) {
+		localJWE, e := ariesjose.Deserialize(serializedJWE)
+		require.NoError(t, e)
+
+		msg, e := ariesjose.NewJWEDecrypt(nil, c, k).Decrypt(localJWE)
+		require.NoError(t, e)
+		require.EqualValues(t, pt, msg)
+	})
+
+	t.Run("success without compression", func(t *testing.T) {
+		encrypter, e := ariesjose.NewJWEEncrypt(ariesjose.A256GCM, EnvelopeEncodingType,
+			DIDCommContentEncodingType, "", nil, recECKeys, c, ariesjose.WithCompression(false))
+		require.NoError(t, e)
+
+		plainJWE, e := encrypter.Encrypt(pt)
+		require.NoError(t, e)
+		require.NotContains(t, plainJWE.ProtectedHeaders, ariesjose.HeaderCompression)
+
+		serialized, e := plainJWE.FullSerialize(json.Marshal)
+		require.NoError(t, e)
+
+		localJWE, e := ariesjose.Deserialize(serialized)
+		require.NoError(t, e)
+
+		msg, e := ariesjose.NewJWEDecrypt(nil, c, k).Decrypt(localJWE)
+		require.NoError(t, e)
+		require.EqualValues(t, pt, msg)
+	})
+
+	t.Run("error decompressed size exceeds the limit", func(t *testing.T) {
+		localJWE, e := ariesjose.Deserialize(serializedJWE)
+		require.NoError(t, e)
+
+		jweDecrypter := ariesjose.NewJWEDecrypt(nil, c, k, ariesjose.WithMaxDecompressedSize(int64(len(pt)-1)))
+
+		_, e = jweDecrypter.Decrypt(localJWE)
+		require.EqualError(t, e, fmt.Sprintf(
+			"jwedecrypt: inflate: decompressed plaintext exceeds the maximum size of %d bytes", len(pt)-1))
+	})
+
+	t.Run("error unsupported compression algorithm", func(t *testing.T) {
+		localJWE, e := ariesjose.Deserialize(serializedJWE)
+		require.NoError(t, e)
+
+		localJWE.ProtectedHeaders[ariesjose.HeaderCompression] = "GZIP"
+
+		_, e = ariesjose.NewJWEDecrypt(nil, c, k).Decrypt(localJWE)
+		require.EqualError(t, e, "jwedecrypt: compression algorithm 'GZIP' not supported")
+	})
+}
+
+func TestInteropCompressionWithGoJoseDecrypt(t *testing.T) {
+	c, err := tinkcrypto.New()
+	require.NoError(t, err)
+
+	recPrivKey, err := ecdsa.GenerateKey(subtle.GetCurve("NIST_P256"), rand.Reader)
+	require.NoError(t, err)
+
+	recKey := &cryptoapi.PublicKey{
+		X:     recPrivKey.PublicKey.X.Bytes(),
+		Y:     recPrivKey.PublicKey.Y.Bytes(),
+		Curve: recPrivKey.PublicKey.Curve.Params().Name,
+		Type:  "EC",
+	}
+
+	jweEncrypter, err := ariesjose.NewJWEEncrypt(ariesjose.A256GCM, EnvelopeEncodingType, DIDCommContentEncodingType,
+		"", nil, []*cryptoapi.PublicKey{recKey}, c, ariesjose.WithCompression(true))
+	require.NoError(t, err)
+
+	pt := []byte(strings.Repeat("some msg ", 50))
+
+	jwe, err := jweEncrypter.Encrypt(pt)
+	require.NoError(t, err)
+
+	serializedJWE, err := jwe.FullSerialize(json.Marshal)
+	require.NoError(t, err)
+
+	gjParsedJWE, err := jose.ParseEncrypted(serializedJWE)
+	require.NoError(t, err)
+
+	msg, err := gjParsedJWE.Decrypt(recPrivKey)
+	require.NoError(t, err)
+	require.EqualValues(t, pt, msg)
+}
+
+func TestJWEDirectKeyAgreement(t *testing.T) {
+	tests := []struct {
+		name    string
+		kt      *tinkpb.KeyTemplate
+		keyType kms.KeyType
+		enc     ariesjose.EncAlg
+	}{
+		{
+			name:    "NIST P-256 key with A256GCM",
+			kt:      ecdh.NISTP256ECDHKWKeyTemplate(),
+			keyType: kms.NISTP256ECDHKWType,
+			enc:     ariesjose.A256GCM,
+		},
+		{
+			name:    "NIST P-384 key with A256CBC-HS512",
+			kt:      ecdh.NISTP384ECDHKWKeyTemplate(),
+			keyType: kms.NISTP384ECDHKWType,
+			enc:     ariesjose.A256CBCHS512,
+		},
+		{
+			name:    "NIST P-256 key with A256GCM-KC",
+			kt:      ecdh.NISTP256ECDHKWKeyTemplate(),
+			keyType: kms.NISTP256ECDHKWType,
+			enc:     ariesjose.A256GCMKC,
+		},
+		{
+			name:    "X25519 key with XC20P",
+			kt:      ecdh.X25519ECDHKWKeyTemplate(),
+			keyType: kms.X25519ECDHKWType,
+			enc:     ariesjose.XC20P,
+		},
+		{
+			name:    "NIST P-256 key with A128GCM",
+			kt:      ecdh.NISTP256ECDHKWKeyTemplate(),
+			keyType: kms.NISTP256ECDHKWType,
+			enc:     ariesjose.A128GCM,
+		},
+		{
+			name:    "X25519 key with A192GCM",
+			kt:      ecdh.X25519ECDHKWKeyTemplate(),
+			keyType: kms.X25519ECDHKWType,
+			enc:     ariesjose.A192GCM,
+		},
+	}
+
+	pt := []byte("direct key agreement msg")
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			recKeys, recKHs, _, _ := createRecipientsByKeyTemplate(t, 1, tc.kt, tc.keyType)
+			c, k := createCryptoAndKMSServices(t, recKHs)
+
+			jweEncrypter, err := ariesjose.NewJWEEncrypt(tc.enc, EnvelopeEncodingType, DIDCommContentEncodingType,
+				"", nil, recKeys, c, ariesjose.WithDirectKeyAgreement())
+			require.NoError(t, err)
+
+			jwe, err := jweEncrypter.Encrypt(pt)
+			require.NoError(t, err)
+			require.Equal(t, tinkcrypto.ECDHESAlg, jwe.ProtectedHeaders[ariesjose.HeaderAlgorithm])
+			require.Contains(t, jwe.ProtectedHeaders, ariesjose.HeaderEPK)
+			require.Empty(t, jwe.Recipients[0].EncryptedKey)
+
+			serializedJWE, err := jwe.CompactSerialize(json.Marshal)
+			require.NoError(t, err)
+			require.Empty(t, strings.Split(serializedJWE, ".")[1])
+
+			localJWE, err := ariesjose.Deserialize(serializedJWE)
+			require.NoError(t, err)
+
+			msg, err := ariesjose.NewJWEDecrypt(nil, c, k).Decrypt(localJWE)
+			require.NoError(t, err)
+			require.EqualValues(t, pt, msg)
+		})
+	}
+
+	recECKeys, recKHs, _, _ := createRecipients(t, 2)
+	c, k := createCryptoAndKMSServices(t, recKHs)
+
+	t.Run("error with multiple recipients", func(t *testing.T) {
+		_, err := ariesjose.NewJWEEncrypt(ariesjose.A256GCM, EnvelopeEncodingType, DIDCommContentEncodingType,
+			"", nil, recECKeys, c, ariesjose.WithDirectKeyAgreement())
+		require.EqualError(t, err, "direct key agreement requires a single recipient")
+	})
+
+	t.Run("error with sender key", func(t *testing.T) {
+		_, err := ariesjose.NewJWEEncrypt(ariesjose.A256CBCHS512, EnvelopeEncodingType, DIDCommContentEncodingType,
+			recECKeys[1].KID, recKHs[recECKeys[1].KID], recECKeys[:1], c, ariesjose.WithDirectKeyAgreement())
+		require.EqualError(t, err, "direct key agreement is not supported with a sender key")
+	})
+
+	t.Run("error with encrypted key", func(t *testing.T) {
+		jweEncrypter, err := ariesjose.NewJWEEncrypt(ariesjose.A256GCM, EnvelopeEncodingType,
+			DIDCommContentEncodingType, "", nil, recECKeys[:1], c, ariesjose.WithDirectKeyAgreement())
+		require.NoError(t, err)
+
+		jwe, err := jweEncrypter.Encrypt(pt)
+		require.NoError(t, err)
+
+		serializedJWE, err := jwe.CompactSerialize(json.Marshal)
+		require.NoError(t, err)
+
+		localJWE, err := ariesjose.Deserialize(serializedJWE)
+		require.NoError(t, err)
+
+		localJWE.Recipients[0].EncryptedKey = "unexpected key"
+
+		_, err = ariesjose.NewJWEDecrypt(nil, c, k).Decrypt(localJWE)
+		require.ErrorIs(t, err, ariesjose.ErrDecryptionFailed)
+	})
+}
+
+func TestJWEDecryptTampered(t *testing.T) {
+	recipients, recKHs, _, _ := createRecipients(t, 1)
+	c, k := createCryptoAndKMSServices(t, recKHs)
+
+	jweEncrypter, err := ariesjose.NewJWEEncrypt(ariesjose.A256GCM, EnvelopeEncodingType,
+		DIDCommContentEncodingType, "", nil, recipients, c)
+	require.NoError(t, err)
+
+	jwe, err := jweEncrypter.Encrypt([]byte("secret message"))
+	require.NoError(t, err)
+
+	serializedJWE, err := jwe.FullSerialize(json.Marshal)
+	require.NoError(t, err)
+
+	jweDecrypter := ariesjose.NewJWEDecrypt(nil, c, k)
+
+	flip := func(s string) string {
+		b := []byte(s)
+		b[len(b)/2] ^= 0x01
+
+		return string(b)
+	}
+
+	var errs []error
+
+	for name, tamper := range map[string]func(*ariesjose.JSONWebEncryption){
+		"encrypted key": func(jwe *ariesjose.JSONWebEncryption) {
+			jwe.Recipients[0].EncryptedKey = flip(jwe.Recipients[0].EncryptedKey)
+		},
+		"ciphertext": func(jwe *ariesjose.JSONWebEncryption) { jwe.Ciphertext = flip(jwe.Ciphertext) },
+		"tag":        func(jwe *ariesjose.JSONWebEncryption) { jwe.Tag = flip(jwe.Tag) },
+		"AAD":        func(jwe *ariesjose.JSONWebEncryption) { jwe.AAD = "tampered" },
+	} {
+		localJWE, e := ariesjose.Deserialize(serializedJWE)
+		require.NoError(t, e)
+
+		tamper(localJWE)
+
+		_, e = jweDecrypter.Decrypt(localJWE)
+		require.ErrorIs(t, e, ariesjose.ErrDecryptionFailed, name)
+
+		errs = append(errs, e)
+	}
+
+	for _, e := range errs[1:] {
+		require.Equal(t, errs[0].Error(), e.Error())
+	}
+}
+
+func TestInteropDirectKeyAgreementWithGoJose(t *testing.T) {
+	recECKeys, recKHs, recKIDs, _ := createRecipients(t, 1)
+	c, k := createCryptoAndKMSServices(t, recKHs)
+
+	pt := []byte("direct key agreement msg")
+
+	t.Run("go-jose encrypt and local jose decrypt", func(t *testing.T) {
+		gjRecipient := convertToGoJoseRecipients(t, recECKeys, recKIDs)[0]
+		gjRecipient.Algorithm = jose.ECDH_ES
+
+		gjEncrypter, err := jose.NewEncrypter(jose.A256GCM, gjRecipient, nil)
+		require.NoError(t, err)
+
+		gjJWE, err := gjEncrypter.Encrypt(pt)
+		require.NoError(t, err)
+
+		gjSerializedJWE, err := gjJWE.CompactSerialize()
+		require.NoError(t, err)
+
+		localJWE, err := ariesjose.Deserialize(gjSerializedJWE)
+		require.NoError(t, err)
+
+		msg, err := ariesjose.NewJWEDecrypt(nil, c, k).Decrypt(localJWE)
+		require.NoError(t, err)
+		require.EqualValues(t, pt, msg)
+	})
+
+	t.Run("local jose encrypt and go-jose decrypt", func(t *testing.T) {
+		recPrivKey, err := ecdsa.GenerateKey(subtle.GetCurve("NIST_P256"), rand.Reader)
+		require.NoError(t, err)
+
+		recKey := &cryptoapi.PublicKey{
+			X:     recPrivKey.PublicKey.X.Bytes(),
+			Y:     recPrivKey.PublicKey.Y.Bytes(),
+			Curve: recPrivKey.PublicKey.Curve.Params().Name,
+			Type:  "EC",
+		}
+
+		jweEncrypter, err := ariesjose.NewJWEEncrypt(ariesjose.A256GCM, EnvelopeEncodingType,
+			DIDCommContentEncodingType, "", nil, []*cryptoapi.PublicKey{recKey}, c,
+			ariesjose.WithDirectKeyAgreement(), ariesjose.WithAgreementPartyVInfo([]byte("Bob")))
+		require.NoError(t, err)
+
+		jwe, err := jweEncrypter.Encrypt(pt)
+		require.NoError(t, err)
+
+		serializedJWE, err := jwe.CompactSerialize(json.Marshal)
+		require.NoError(t, err)
+
+		gjParsedJWE, err := jose.ParseEncrypted(serializedJWE)
+		require.NoError(t, err)
+
+		msg, err := gjParsedJWE.Decrypt(recPrivKey)
+		require.NoError(t, err)
+		require.EqualValues(t, pt, msg)
+	})
+}
+
+func TestJWEEncryptWithMasterCEK(t *testing.T) {
+	recECKeys, recKHs, _, _ := createRecipients(t, 2)
+	c, k := createCryptoAndKMSServices(t, recKHs)
+
+	jweEncrypter, err := ariesjose.NewJWEEncrypt(ariesjose.A256GCM, EnvelopeEncodingType,
+		DIDCommContentEncodingType, "", nil, recECKeys, c)
+	require.NoError(t, err)
+
+	masterCEK := random.GetRandomBytes(32)
+	jweDecrypter := ariesjose.NewJWEDecrypt(nil, c, k)
+
+	sections := [][]byte{[]byte("section one"), []byte("section two")}
+
+	var serializedJWEs []string
+
+	for _, section := range sections {
+		sectionCEK := random.GetRandomBytes(32)
+
+		jwe, e := jweEncrypter.EncryptWithMasterCEK(section, nil, sectionCEK, masterCEK)
+		require.NoError(t, e)
+		require.NotEmpty(t, jwe.ProtectedHeaders[ariesjose.HeaderWrappedCEK])
+
+		serializedJWE, e := jwe.FullSerialize(json.Marshal)
+		require.NoError(t, e)
+
+		serializedJWEs = append(serializedJWEs, serializedJWE)
+	}
+
+	t.Run("success", func(t *testing.T) {
+		for i, serializedJWE := range serializedJWEs {
+			localJWE, e := ariesjose.Deserialize(serializedJWE)
+			require.NoError(t, e)
+
+			msg, e := jweDecrypter.Decrypt(localJWE)
+			require.NoError(t, e)
+			require.EqualValues(t, sections[i], msg)
+		}
+	})
+
+	t.Run("error invalid wrapped CEK", func(t *testing.T) {
+		localJWE, e := ariesjose.Deserialize(serializedJWEs[0])
+		require.NoError(t, e)
+
+		localJWE.ProtectedHeaders[ariesjose.HeaderWrappedCEK] = base64.RawURLEncoding.EncodeToString(
+			make([]byte, 40))
+
+		_, e = jweDecrypter.Decrypt(localJWE)
+		require.ErrorIs(t, e, ariesjose.ErrDecryptionFailed)
+	})
+
+	t.Run("error invalid wrapped CEK encoding", func(t *testing.T) {
+		localJWE, e := ariesjose.Deserialize(serializedJWEs[0])
+		require.NoError(t, e)
+
+		localJWE.ProtectedHeaders[ariesjose.HeaderWrappedCEK] = "!"
+
+		_, e = jweDecrypter.Decrypt(localJWE)
+		require.ErrorContains(t, e, "jwedecrypt: decode wcek header")
+	})
+
+	t.Run("error invalid section CEK size", func(t *testing.T) {
+		_, e := jweEncrypter.EncryptWithMasterCEK([]byte("pt"), nil, random.GetRandomBytes(16), masterCEK)
+		require.EqualError(t, e,
+			"jweencryptWithMasterCEK: section CEK size 16 is invalid for A256GCM, expected 32")
+	})
+
+	t.Run("error invalid master CEK", func(t *testing.T) {
+		_, e := jweEncrypter.EncryptWithMasterCEK([]byte("pt"), nil, random.GetRandomBytes(32),
+			random.GetRandomBytes(7))
+		require.ErrorContains(t, e, "jweencryptWithMasterCEK: wrapCEKUnderMaster: invalid master CEK")
+	})
+
+	t.Run("error direct key agreement", func(t *testing.T) {
+		directEncrypter, e := ariesjose.NewJWEEncrypt(ariesjose.A256GCM, EnvelopeEncodingType,
+			DIDCommContentEncodingType, "", nil, recECKeys[:1], c, ariesjose.WithDirectKeyAgreement())
+		require.NoError(t, e)
+
+		_, e = directEncrypter.EncryptWithMasterCEK([]byte("pt"), nil, random.GetRandomBytes(32), masterCEK)
+		require.EqualError(t, e, "jweencryptWithMasterCEK: direct key agreement has no wrapped key")
+	})
+}
+
+func TestJWEAESGCMKeySizes(t *testing.T) {
+	recECKeys, recKHs, recKIDs, _ := createRecipients(t, 2)
+	c, k := createCryptoAndKMSServices(t, recKHs)
+
+	pt := []byte("some msg")
+
+	for _, enc := range []ariesjose.EncAlg{ariesjose.A128GCM, ariesjose.A192GCM} {
+		t.Run(string(enc), func(t *testing.T) {
+			jweEncrypter, err := ariesjose.NewJWEEncrypt(enc, EnvelopeEncodingType, DIDCommContentEncodingType,
+				"", nil, recECKeys, c)
+			require.NoError(t, err)
+
+			jwe, err := jweEncrypter.Encrypt(pt)
+			require.NoError(t, err)
+			require.Equal(t, enc, jwe.ProtectedHeaders[ariesjose.HeaderEncryption])
+
+			serializedJWE, err := jwe.FullSerialize(json.Marshal)
+			require.NoError(t, err)
+
+			localJWE, err := ariesjose.Deserialize(serializedJWE)
+			require.NoError(t, err)
+
+			msg, err := ariesjose.NewJWEDecrypt(nil, c, k).Decrypt(localJWE)
+			require.NoError(t, err)
+			require.EqualValues(t, pt, msg)
+		})
+
+		t.Run(string(enc)+" go-jose encrypt and local jose decrypt", func(t *testing.T) {
+			gjEncrypter, err := jose.NewEncrypter(jose.ContentEncryption(enc),
+				convertToGoJoseRecipients(t, recECKeys[:1], recKIDs[:1])[0], nil)
+			require.NoError(t, err)
+
+			gjJWE, err := gjEncrypter.Encrypt(pt)
+			require.NoError(t, err)
+
+			gjSerializedJWE, err := gjJWE.CompactSerialize()
+			require.NoError(t, err)
+
+			localJWE, err := ariesjose.Deserialize(gjSerializedJWE)
+			require.NoError(t, err)
+
+			msg, err := ariesjose.NewJWEDecrypt(nil, c, k).Decrypt(localJWE)
+			require.NoError(t, err)
+			require.EqualValues(t, pt, msg)
+		})
+	}
+
+	t.Run("error CEK size not matching enc header", func(t *testing.T) {
+		jweEncrypter, err := ariesjose.NewJWEEncrypt(ariesjose.A256GCM, EnvelopeEncodingType,
+			DIDCommContentEncodingType, "", nil, recECKeys, c)
+		require.NoError(t, err)
+
+		jwe, err := jweEncrypter.Encrypt(pt)
+		require.NoError(t, err)
+
+		serializedJWE, err := jwe.FullSerialize(json.Marshal)
+		require.NoError(t, err)
+
+		localJWE, err := ariesjose.Deserialize(serializedJWE)
+		require.NoError(t, err)
+
+		localJWE.ProtectedHeaders[ariesjose.HeaderEncryption] = ariesjose.A128GCMALG
+
+		_, err = ariesjose.NewJWEDecrypt(nil, c, k).Decrypt(localJWE)
+		require.EqualError(t, err, "jwedecrypt: CEK size 32 is invalid for A128GCM, expected 16")
+	})
+}
@@ -0,0 +1,122 @@
+/*
+Copyright Gen Digital Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package jose
+
+import (
+	"crypto/ecdsa"
+	"encoding/json"
+	"errors"
+	"fmt"
+
+	"github.com/dellekappa/kms-go/doc/jose/jwk"
+)
+
+// ErrEPKPrivateKey is returned by ParseEPK when the epk header carries private key material. A sender setting the
+// private key of its ephemeral key in epk would disclose the shared secret of the key agreement, epk values with
+// private members are rejected rather than stripped.
+var ErrEPKPrivateKey = errors.New("epk contains private key material")
+
+// errUnsupportedEPK is returned for epk values which are not EC or OKP public keys on an allowed curve.
+var errUnsupportedEPK = errors.New("unsupported recipient key type")
+
+// epkCurves are the curves allowed for EC and OKP ephemeral public keys.
+var epkCurves = map[string]string{ //nolint:gochecknoglobals
+	"P-256":     ecKty,
+	"P-384":     ecKty,
+	"P-521":     ecKty,
+	x25519Curve: okpKty,
+}
+
+const (
+	ecKty  = "EC"
+	okpKty = "OKP"
+
+	x25519KeySize = 32
+)
+
+// ParseEPK decodes the epk (ephemeral public key) member of the JWE protectedHeader without parsing the full JWE, e.g.
+// to inspect the key agreement of a message before decrypting it. The epk must be an EC public key on P-256, P-384
+// or P-521 or an X25519 OKP public key. An epk carrying private key material returns an error wrapping
+// ErrEPKPrivateKey.
+func ParseEPK(protectedHeader map[string]interface{}) (*jwk.JWK, error) {
+	value, ok := protectedHeader[HeaderEPK]
+	if !ok || value == nil {
+		return nil, errors.New("parseEPK: epk header is missing")
+	}
+
+	var (
+		epkBytes []byte
+		err      error
+	)
+
+	switch v := value.(type) {
+	case json.RawMessage:
+		epkBytes = v
+	case []byte:
+		epkBytes = v
+	case map[string]interface{}:
+		epkBytes, err = json.Marshal(v)
+		if err != nil {
+			return nil, fmt.Errorf("parseEPK: %w", err)
+		}
+	default:
+		return nil, fmt.Errorf("parseEPK: epk header is not a JSON object (%T)", value)
+	}
+
+	key, err := parseEPK(epkBytes)
+	if err != nil {
+		return nil, fmt.Errorf("parseEPK: %w", err)
+	}
+
+	return key, nil
+}
+
+// parseEPK decodes the marshalled epk JWK, see ParseEPK.
+func parseEPK(epkBytes []byte) (*jwk.JWK, error) {
+	var members map[string]json.RawMessage
+
+	err := json.Unmarshal(epkBytes, &members)
+	if err != nil {
+		return nil, fmt.Errorf("unable to read JWK: %w", err)
+	}
+
+	for _, name := range jwk.PrivateMembers() {
+		if _, ok := members[name]; ok {
+			return nil, &jwk.JWKError{
+				Field:  name,
+				Reason: "private key material is not allowed in epk",
+				Err:    ErrEPKPrivateKey,
+			}
+		}
+	}
+
+	key := &jwk.JWK{}
+
+	err = key.UnmarshalJSON(epkBytes)
+	if err != nil {
+		return nil, err
+	}
+
+	if kty, ok := epkCurves[key.Crv]; !ok || kty != key.Kty {
+		return nil, errUnsupportedEPK
+	}
+
+	switch k := key.Key.(type) {
+	case *ecdsa.PublicKey:
+		if !k.Curve.IsOnCurve(k.X, k.Y) {
+			return nil, errors.New("epk is not on its curve")
+		}
+	case []byte:
+		if len(k) != x25519KeySize {
+			return nil, fmt.Errorf("invalid epk X25519 key size %d", len(k))
+		}
+	default:
+		return nil, errUnsupportedEPK
+	}
+
+	return key, nil
+}
@@ -0,0 +1,108 @@
+/*
+Copyright Gen Digital Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package jose
+
+import (
+	"crypto/ecdh"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/dellekappa/kms-go/doc/jose/jwk"
+)
+
+func TestParseEPK(t *testing.T) {
+	ecKey, err := ecdsa.GenerateKey(elliptic.P384(), rand.Reader)
+	require.NoError(t, err)
+
+	ecEPK := map[string]interface{}{
+		"kty": "EC",
+		"crv": "P-384",
+		"x":   base64.RawURLEncoding.EncodeToString(ecKey.X.FillBytes(make([]byte, 48))),
+		"y":   base64.RawURLEncoding.EncodeToString(ecKey.Y.FillBytes(make([]byte, 48))),
+	}
+
+	xKey, err := ecdh.X25519().GenerateKey(rand.Reader)
+	require.NoError(t, err)
+
+	okpEPK := map[string]interface{}{
+		"kty": "OKP",
+		"crv": "X25519",
+		"x":   base64.RawURLEncoding.EncodeToString(xKey.PublicKey().Bytes()),
+	}
+
+	t.Run("EC epk", func(t *testing.T) {
+		epk, err := ParseEPK(map[string]interface{}{HeaderEPK: ecEPK})
+		require.NoError(t, err)
+		require.Equal(t, &ecKey.PublicKey, epk.Key)
+	})
+
+	t.Run("X25519 epk as raw JSON", func(t *testing.T) {
+		epkBytes, err := json.Marshal(okpEPK)
+		require.NoError(t, err)
+
+		epk, err := ParseEPK(map[string]interface{}{HeaderEPK: json.RawMessage(epkBytes)})
+		require.NoError(t, err)
+		require.Equal(t, xKey.PublicKey().Bytes(), epk.Key)
+	})
+
+	t.Run("private key material", func(t *testing.T) {
+		for name, epk := range map[string]map[string]interface{}{"EC": ecEPK, "OKP": okpEPK} {
+			withD := map[string]interface{}{"d": "AAAA"}
+			for k, v := range epk {
+				withD[k] = v
+			}
+
+			_, err := ParseEPK(map[string]interface{}{HeaderEPK: withD})
+			require.ErrorIs(t, err, ErrEPKPrivateKey, name)
+
+			var jwkErr *jwk.JWKError
+
+			require.True(t, errors.As(err, &jwkErr), name)
+			require.Equal(t, "d", jwkErr.Field)
+		}
+	})
+
+	t.Run("invalid epk", func(t *testing.T) {
+		_, err := ParseEPK(map[string]interface{}{})
+		require.EqualError(t, err, "parseEPK: epk header is missing")
+
+		_, err = ParseEPK(map[string]interface{}{HeaderEPK: "epk"})
+		require.EqualError(t, err, "parseEPK: epk header is not a JSON object (string)")
+
+		_, err = ParseEPK(map[string]interface{}{HeaderEPK: map[string]interface{}{
+			"kty": "oct", "k": "AAAA",
+		}})
+		require.ErrorIs(t, err, ErrEPKPrivateKey)
+
+		secp256k1 := map[string]interface{}{
+			"kty": "EC",
+			"crv": "secp256k1",
+			"x":   "vC7Vx0Vd2bHwS7ReyYjfb4sfwydK9s5ZVfNFfdHM4wI",
+			"y":   "d2vAm5oZ7ofpC8Sq-Wlaa_sVJ7s5Hj1Xrb8sbD45HNY",
+		}
+
+		_, err = ParseEPK(map[string]interface{}{HeaderEPK: secp256k1})
+		require.Error(t, err)
+
+		offCurve := map[string]interface{}{}
+		for k, v := range ecEPK {
+			offCurve[k] = v
+		}
+
+		offCurve["y"] = offCurve["x"]
+
+		_, err = ParseEPK(map[string]interface{}{HeaderEPK: offCurve})
+		require.Error(t, err)
+	})
+}
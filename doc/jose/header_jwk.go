@@ -0,0 +1,125 @@
+/*
+Copyright Gen Digital Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package jose
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+
+	"github.com/dellekappa/kms-go/doc/jose/jwk"
+)
+
+// ErrUntrustedJWK is returned by ParseHeaderJWK when the jwk header does not match any of the keys set with
+// WithTrustedJWKs.
+var ErrUntrustedJWK = errors.New("jwk header is not a trusted key")
+
+// headerJWKOpts holds options for ParseHeaderJWK.
+type headerJWKOpts struct {
+	trustedKeys  []*jwk.JWK
+	checkTrusted bool
+}
+
+// HeaderJWKOpt is the ParseHeaderJWK option.
+type HeaderJWKOpt func(opts *headerJWKOpts)
+
+// WithTrustedJWKs option requires the jwk header to hold the same public key as one of keys (compared with
+// jwk.JWK.SamePublicKey, their kid and other members are ignored), else ParseHeaderJWK fails with ErrUntrustedJWK.
+// An empty keys list trusts no key.
+func WithTrustedJWKs(keys ...*jwk.JWK) HeaderJWKOpt {
+	return func(opts *headerJWKOpts) {
+		opts.trustedKeys = keys
+		opts.checkTrusted = true
+	}
+}
+
+// ParseHeaderJWK decodes the public key embedded in the "jwk" member of a JWS or JWE header (RFC 7515 section 4.1.3,
+// RFC 7516 section 4.1.5). A jwk carrying private or secret key material is rejected with an error wrapping
+// jwk.ErrPrivateKeyMaterial.
+//
+// WARNING: the jwk header is chosen by whoever produced the message. A JWS verified with its own embedded key only
+// proves that the signer holds the matching private key, not who the signer is: an attacker can sign any payload
+// with a fresh key and embed it. Never use the returned key to authenticate a message unless it is checked against
+// keys trusted by other means, either with WithTrustedJWKs or by the caller (e.g. resolving the key of the expected
+// issuer, or validating a certificate chain bound to the key). The same holds for the sender of a JWE.
+func ParseHeaderJWK(header map[string]interface{}, opts ...HeaderJWKOpt) (*jwk.JWK, error) {
+	pOpts := &headerJWKOpts{}
+
+	for _, opt := range opts {
+		opt(pOpts)
+	}
+
+	value, ok := header[HeaderJSONWebKey]
+	if !ok || value == nil {
+		return nil, errors.New("parseHeaderJWK: jwk header is missing")
+	}
+
+	var (
+		jwkBytes []byte
+		err      error
+	)
+
+	switch v := value.(type) {
+	case json.RawMessage:
+		jwkBytes = v
+	case []byte:
+		jwkBytes = v
+	case map[string]interface{}, *jwk.JWK:
+		jwkBytes, err = json.Marshal(v)
+		if err != nil {
+			return nil, fmt.Errorf("parseHeaderJWK: %w", err)
+		}
+	default:
+		return nil, fmt.Errorf("parseHeaderJWK: jwk header is not a JSON object (%T)", value)
+	}
+
+	key, err := parseHeaderJWK(jwkBytes)
+	if err != nil {
+		return nil, fmt.Errorf("parseHeaderJWK: %w", err)
+	}
+
+	if !pOpts.checkTrusted {
+		return key, nil
+	}
+
+	for _, trusted := range pOpts.trustedKeys {
+		if key.SamePublicKey(trusted) {
+			return key, nil
+		}
+	}
+
+	return nil, fmt.Errorf("parseHeaderJWK: %w", ErrUntrustedJWK)
+}
+
+// parseHeaderJWK decodes the marshalled jwk header, see ParseHeaderJWK.
+func parseHeaderJWK(jwkBytes []byte) (*jwk.JWK, error) {
+	var members map[string]json.RawMessage
+
+	err := json.Unmarshal(jwkBytes, &members)
+	if err != nil {
+		return nil, fmt.Errorf("unable to read JWK: %w", err)
+	}
+
+	for _, name := range jwk.PrivateMembers() {
+		if _, ok := members[name]; ok {
+			return nil, &jwk.JWKError{
+				Field:  name,
+				Reason: "private key material is not allowed in the jwk header",
+				Err:    jwk.ErrPrivateKeyMaterial,
+			}
+		}
+	}
+
+	key := &jwk.JWK{}
+
+	err = key.UnmarshalJSON(jwkBytes)
+	if err != nil {
+		return nil, err
+	}
+
+	return key, nil
+}
@@ -0,0 +1,94 @@
+/*
+Copyright Gen Digital Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package jose
+
+import (
+	"crypto/ed25519"
+	"crypto/rand"
+	"encoding/json"
+	"errors"
+	"testing"
+
+	"github.com/go-jose/go-jose/v3"
+	"github.com/stretchr/testify/require"
+
+	"github.com/dellekappa/kms-go/doc/jose/jwk"
+)
+
+func TestParseHeaderJWK(t *testing.T) {
+	pubKey, privKey, err := ed25519.GenerateKey(rand.Reader)
+	require.NoError(t, err)
+
+	signerJWK := &jwk.JWK{JSONWebKey: jose.JSONWebKey{Key: pubKey, KeyID: "signer"}, Kty: "OKP", Crv: "Ed25519"}
+
+	signerJWKBytes, err := json.Marshal(signerJWK)
+	require.NoError(t, err)
+
+	var signerJWKMap map[string]interface{}
+
+	require.NoError(t, json.Unmarshal(signerJWKBytes, &signerJWKMap))
+
+	otherPubKey, _, err := ed25519.GenerateKey(rand.Reader)
+	require.NoError(t, err)
+
+	otherJWK := &jwk.JWK{JSONWebKey: jose.JSONWebKey{Key: otherPubKey}, Kty: "OKP", Crv: "Ed25519"}
+
+	t.Run("jwk header values", func(t *testing.T) {
+		for _, value := range []interface{}{signerJWKMap, json.RawMessage(signerJWKBytes), signerJWKBytes, signerJWK} {
+			key, e := ParseHeaderJWK(map[string]interface{}{HeaderJSONWebKey: value})
+			require.NoError(t, e)
+			require.Equal(t, pubKey, key.Key)
+			require.Equal(t, "signer", key.KeyID)
+		}
+	})
+
+	t.Run("trusted keys", func(t *testing.T) {
+		trusted := &jwk.JWK{JSONWebKey: jose.JSONWebKey{Key: privKey, KeyID: "other kid"}, Kty: "OKP", Crv: "Ed25519"}
+
+		key, e := ParseHeaderJWK(map[string]interface{}{HeaderJSONWebKey: signerJWKMap},
+			WithTrustedJWKs(otherJWK, trusted))
+		require.NoError(t, e)
+		require.Equal(t, pubKey, key.Key)
+
+		_, e = ParseHeaderJWK(map[string]interface{}{HeaderJSONWebKey: signerJWKMap}, WithTrustedJWKs(otherJWK))
+		require.ErrorIs(t, e, ErrUntrustedJWK)
+
+		_, e = ParseHeaderJWK(map[string]interface{}{HeaderJSONWebKey: signerJWKMap}, WithTrustedJWKs())
+		require.EqualError(t, e, "parseHeaderJWK: jwk header is not a trusted key")
+	})
+
+	t.Run("private key material", func(t *testing.T) {
+		privJWK := &jwk.JWK{JSONWebKey: jose.JSONWebKey{Key: privKey}, Kty: "OKP", Crv: "Ed25519"}
+
+		_, e := ParseHeaderJWK(map[string]interface{}{HeaderJSONWebKey: privJWK})
+		require.ErrorIs(t, e, jwk.ErrPrivateKeyMaterial)
+
+		var jwkErr *jwk.JWKError
+
+		require.True(t, errors.As(e, &jwkErr))
+		require.Equal(t, "d", jwkErr.Field)
+
+		_, e = ParseHeaderJWK(map[string]interface{}{HeaderJSONWebKey: map[string]interface{}{
+			"kty": "oct", "k": "AAAA",
+		}})
+		require.ErrorIs(t, e, jwk.ErrPrivateKeyMaterial)
+	})
+
+	t.Run("invalid jwk header", func(t *testing.T) {
+		_, e := ParseHeaderJWK(map[string]interface{}{})
+		require.EqualError(t, e, "parseHeaderJWK: jwk header is missing")
+
+		_, e = ParseHeaderJWK(map[string]interface{}{HeaderJSONWebKey: 42})
+		require.EqualError(t, e, "parseHeaderJWK: jwk header is not a JSON object (int)")
+
+		_, e = ParseHeaderJWK(map[string]interface{}{HeaderJSONWebKey: []byte("not json")})
+		require.ErrorContains(t, e, "parseHeaderJWK: unable to read JWK")
+
+		_, e = ParseHeaderJWK(map[string]interface{}{HeaderJSONWebKey: map[string]interface{}{"kty": "EC"}})
+		require.Error(t, e)
+	})
+}
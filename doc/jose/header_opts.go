@@ -0,0 +1,85 @@
+/*
+Copyright Gen Digital Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package jose
+
+import (
+	"fmt"
+)
+
+// headerOpts holds the protected headers set by HeaderOpts.
+type headerOpts struct {
+	typ      string
+	cty      string
+	embedJWK bool
+}
+
+// HeaderOpt is an option of NewJWS, JWSBuilder.AddSigner and, through WithHeaderOpts, NewJWEEncrypt setting a
+// protected header of the JWS or JWE. Headers set by options take precedence over the ones of the protected headers
+// maps and of the signer.
+type HeaderOpt func(opts *headerOpts)
+
+// WithType option sets the "typ" protected header (https://tools.ietf.org/html/rfc7515#section-4.1.9), the media type
+// of the complete JWS or JWE, e.g. "JWT" or "dpop+jwt".
+func WithType(typ string) HeaderOpt {
+	return func(opts *headerOpts) {
+		opts.typ = typ
+	}
+}
+
+// WithContentType option sets the "cty" protected header (https://tools.ietf.org/html/rfc7515#section-4.1.10), the
+// media type of the payload, e.g. "JWT" for nested tokens.
+func WithContentType(cty string) HeaderOpt {
+	return func(opts *headerOpts) {
+		opts.cty = cty
+	}
+}
+
+// WithEmbeddedJWK option sets the "jwk" protected header (https://tools.ietf.org/html/rfc7515#section-4.1.3) of a JWS
+// to the public key of its signer, for self-describing tokens like DPoP proofs. The signer must be a JWKSigner.
+// Embedding is rejected if the JWS has a "kid" header, identifying the key by kid only, and by NewJWEEncrypt.
+func WithEmbeddedJWK(embed bool) HeaderOpt {
+	return func(opts *headerOpts) {
+		opts.embedJWK = embed
+	}
+}
+
+func newHeaderOpts(opts []HeaderOpt) *headerOpts {
+	hOpts := &headerOpts{}
+
+	for _, opt := range opts {
+		opt(hOpts)
+	}
+
+	return hOpts
+}
+
+// applyHeaderOpts sets the "typ" and "cty" headers of opts in headers, empty values are not set. The "jwk" header is
+// set by embedSignerJWK.
+func applyHeaderOpts(headers Headers, opts []HeaderOpt) {
+	hOpts := newHeaderOpts(opts)
+
+	if hOpts.typ != "" {
+		headers[HeaderType] = hOpts.typ
+	}
+
+	if hOpts.cty != "" {
+		headers[HeaderContentType] = hOpts.cty
+	}
+}
+
+// checkStringHeaders checks the "typ" and "cty" headers, if set, are strings.
+func checkStringHeaders(headers Headers) error {
+	for _, name := range []string{HeaderType, HeaderContentType} {
+		if value, ok := headers[name]; ok {
+			if _, ok = value.(string); !ok {
+				return fmt.Errorf("%s header must be a string, got %T", name, value)
+			}
+		}
+	}
+
+	return nil
+}
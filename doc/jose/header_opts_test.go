@@ -0,0 +1,121 @@
+/*
+Copyright Gen Digital Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package jose
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"testing"
+
+	"github.com/google/tink/go/keyset"
+	"github.com/stretchr/testify/require"
+
+	"github.com/dellekappa/kms-go/crypto/tinkcrypto"
+	"github.com/dellekappa/kms-go/crypto/tinkcrypto/primitive/composite/ecdh"
+	"github.com/dellekappa/kms-go/crypto/tinkcrypto/primitive/composite/keyio"
+	cryptoapi "github.com/dellekappa/kms-go/spi/crypto"
+)
+
+func TestJWSTypeAndContentType(t *testing.T) {
+	signer := newEd25519TestSigner(t, "kid")
+	verifier := newEd25519TestVerifier(signer)
+
+	t.Run("compact JWS", func(t *testing.T) {
+		jws, err := NewJWS(Headers{HeaderType: "JWT"}, nil, []byte("payload"), signer,
+			WithType("dpop+jwt"), WithContentType("JWT"))
+		require.NoError(t, err)
+
+		jwsCompact, err := jws.SerializeCompact(false)
+		require.NoError(t, err)
+
+		parsed, err := ParseJWS(jwsCompact, verifier)
+		require.NoError(t, err)
+
+		typ, ok := parsed.Headers().Type()
+		require.True(t, ok)
+		require.Equal(t, "dpop+jwt", typ)
+
+		cty, ok := parsed.Headers().ContentType()
+		require.True(t, ok)
+		require.Equal(t, "JWT", cty)
+	})
+
+	t.Run("JWS builder", func(t *testing.T) {
+		jws, err := NewJWSBuilder([]byte("payload")).
+			AddSigner(signer, nil, nil, WithType("JWT")).
+			Build()
+		require.NoError(t, err)
+
+		jwsJSON, err := jws.SerializeJSON(false)
+		require.NoError(t, err)
+
+		parsed, err := ParseGeneralJWS(jwsJSON, verifier)
+		require.NoError(t, err)
+
+		typ, ok := parsed.Signatures[0].Headers().Type()
+		require.True(t, ok)
+		require.Equal(t, "JWT", typ)
+
+		_, ok = parsed.Signatures[0].Headers().ContentType()
+		require.False(t, ok)
+	})
+
+	t.Run("non string typ and cty are rejected", func(t *testing.T) {
+		_, err := NewJWS(Headers{HeaderType: 1}, nil, []byte("payload"), signer)
+		require.EqualError(t, err, "sign JWS: check JOSE headers: typ header must be a string, got int")
+
+		_, err = NewJWSBuilder([]byte("payload")).
+			AddSigner(signer, Headers{HeaderContentType: []string{"JWT"}}, nil).
+			Build()
+		require.EqualError(t, err, "build JWS: signature 0: check JOSE headers: cty header must be a string, "+
+			"got []string")
+
+		headers := base64.RawURLEncoding.EncodeToString([]byte(`{"alg":"EdDSA","kid":"kid","typ":true}`))
+
+		_, err = ParseJWS(headers+".cGF5bG9hZA.c2lnbmF0dXJl", verifier)
+		require.ErrorContains(t, err, "typ header must be a string, got bool")
+	})
+}
+
+func TestJWETypeAndContentType(t *testing.T) {
+	c, err := tinkcrypto.New()
+	require.NoError(t, err)
+
+	kh, err := keyset.NewHandle(ecdh.NISTP256ECDHKWKeyTemplate())
+	require.NoError(t, err)
+
+	recPubKey, err := keyio.ExtractPrimaryPublicKey(kh)
+	require.NoError(t, err)
+
+	jweEncrypter, err := NewJWEEncrypt(A256GCM, "application/didcomm-encrypted+json", "", "", nil,
+		[]*cryptoapi.PublicKey{recPubKey}, c, WithHeaderOpts(WithType("JWT"), WithContentType("JWT")))
+	require.NoError(t, err)
+
+	jwe, err := jweEncrypter.Encrypt([]byte("payload"))
+	require.NoError(t, err)
+
+	jweCompact, err := jwe.CompactSerialize(json.Marshal)
+	require.NoError(t, err)
+
+	parsed, err := Deserialize(jweCompact)
+	require.NoError(t, err)
+
+	typ, ok := parsed.ProtectedHeaders.Type()
+	require.True(t, ok)
+	require.Equal(t, "JWT", typ)
+
+	cty, ok := parsed.ProtectedHeaders.ContentType()
+	require.True(t, ok)
+	require.Equal(t, "JWT", cty)
+
+	t.Run("non string typ is rejected", func(t *testing.T) {
+		headers := base64.RawURLEncoding.EncodeToString([]byte(`{"enc":"A256GCM","typ":1}`))
+
+		_, err = Deserialize(headers + "..aXY.Y2lwaGVy.dGFn")
+		require.EqualError(t, err, "typ header must be a string, got float64")
+	})
+}
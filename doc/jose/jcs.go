@@ -0,0 +1,292 @@
+/*
+Copyright Gen Digital Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package jose
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"math"
+	"sort"
+	"strconv"
+	"strings"
+	"unicode/utf16"
+)
+
+// canonicalizeJSON returns the JSON Canonicalization Scheme (JCS, RFC 8785) serialization of the JSON data: object
+// members sorted by the UTF-16 code units of their names, no whitespace, ECMAScript number serialization and minimal
+// string escaping. data must be I-JSON (RFC 7493): objects with duplicate member names are rejected.
+func canonicalizeJSON(data []byte) ([]byte, error) {
+	dec := json.NewDecoder(bytes.NewReader(data))
+	dec.UseNumber()
+
+	value, err := decodeJCSValue(dec)
+	if err != nil {
+		return nil, err
+	}
+
+	if _, err = dec.Token(); !errors.Is(err, io.EOF) {
+		return nil, errors.New("unexpected data after the JSON value")
+	}
+
+	buf := &bytes.Buffer{}
+
+	err = writeJCSValue(buf, value)
+	if err != nil {
+		return nil, err
+	}
+
+	return buf.Bytes(), nil
+}
+
+// jcsMember is a member of a decoded JSON object, objects are decoded as []jcsMember to detect duplicate names.
+type jcsMember struct {
+	name  string
+	value interface{}
+}
+
+func decodeJCSValue(dec *json.Decoder) (interface{}, error) {
+	token, err := dec.Token()
+	if err != nil {
+		return nil, fmt.Errorf("read JSON: %w", err)
+	}
+
+	delim, ok := token.(json.Delim)
+	if !ok {
+		return token, nil
+	}
+
+	switch delim {
+	case '{':
+		return decodeJCSObject(dec)
+	case '[':
+		var values []interface{}
+
+		for dec.More() {
+			value, err := decodeJCSValue(dec)
+			if err != nil {
+				return nil, err
+			}
+
+			values = append(values, value)
+		}
+
+		// consume the closing ']'.
+		if _, err = dec.Token(); err != nil {
+			return nil, fmt.Errorf("read JSON: %w", err)
+		}
+
+		if values == nil {
+			values = []interface{}{}
+		}
+
+		return values, nil
+	default:
+		return nil, fmt.Errorf("read JSON: unexpected delimiter '%s'", delim)
+	}
+}
+
+func decodeJCSObject(dec *json.Decoder) ([]jcsMember, error) {
+	members := []jcsMember{}
+	names := map[string]struct{}{}
+
+	for dec.More() {
+		token, err := dec.Token()
+		if err != nil {
+			return nil, fmt.Errorf("read JSON: %w", err)
+		}
+
+		name, ok := token.(string)
+		if !ok {
+			return nil, fmt.Errorf("read JSON: invalid object member name %v", token)
+		}
+
+		if _, ok = names[name]; ok {
+			return nil, fmt.Errorf("duplicate object member name '%s'", name)
+		}
+
+		names[name] = struct{}{}
+
+		value, err := decodeJCSValue(dec)
+		if err != nil {
+			return nil, err
+		}
+
+		members = append(members, jcsMember{name: name, value: value})
+	}
+
+	// consume the closing '}'.
+	if _, err := dec.Token(); err != nil {
+		return nil, fmt.Errorf("read JSON: %w", err)
+	}
+
+	return members, nil
+}
+
+func writeJCSValue(buf *bytes.Buffer, value interface{}) error {
+	switch v := value.(type) {
+	case nil:
+		buf.WriteString("null")
+	case bool:
+		buf.WriteString(strconv.FormatBool(v))
+	case string:
+		writeJCSString(buf, v)
+	case json.Number:
+		f, err := strconv.ParseFloat(string(v), 64)
+		if err != nil || math.IsInf(f, 0) {
+			return fmt.Errorf("number %s is not an IEEE 754 double", v)
+		}
+
+		buf.WriteString(formatJCSNumber(f))
+	case []interface{}:
+		buf.WriteByte('[')
+
+		for i, elem := range v {
+			if i > 0 {
+				buf.WriteByte(',')
+			}
+
+			if err := writeJCSValue(buf, elem); err != nil {
+				return err
+			}
+		}
+
+		buf.WriteByte(']')
+	case []jcsMember:
+		sort.Slice(v, func(i, j int) bool {
+			return lessUTF16(v[i].name, v[j].name)
+		})
+
+		buf.WriteByte('{')
+
+		for i, member := range v {
+			if i > 0 {
+				buf.WriteByte(',')
+			}
+
+			writeJCSString(buf, member.name)
+			buf.WriteByte(':')
+
+			if err := writeJCSValue(buf, member.value); err != nil {
+				return err
+			}
+		}
+
+		buf.WriteByte('}')
+	default:
+		return fmt.Errorf("unsupported JSON value %T", value)
+	}
+
+	return nil
+}
+
+// lessUTF16 compares a and b by their UTF-16 code units, as required by RFC 8785 section 3.2.3. It differs from the
+// byte order of their UTF-8 encoding for characters above U+FFFF, encoded as surrogate pairs.
+func lessUTF16(a, b string) bool {
+	ua, ub := utf16.Encode([]rune(a)), utf16.Encode([]rune(b))
+
+	for i := 0; i < len(ua) && i < len(ub); i++ {
+		if ua[i] != ub[i] {
+			return ua[i] < ub[i]
+		}
+	}
+
+	return len(ua) < len(ub)
+}
+
+// writeJCSString writes s as a JSON string escaping only '"', '\' and control characters, as ECMAScript's
+// JSON.stringify does (RFC 8785 section 3.2.2.2).
+func writeJCSString(buf *bytes.Buffer, s string) {
+	const hex = "0123456789abcdef"
+
+	buf.WriteByte('"')
+
+	for _, r := range s {
+		switch r {
+		case '"':
+			buf.WriteString(`\"`)
+		case '\\':
+			buf.WriteString(`\\`)
+		case '\b':
+			buf.WriteString(`\b`)
+		case '\f':
+			buf.WriteString(`\f`)
+		case '\n':
+			buf.WriteString(`\n`)
+		case '\r':
+			buf.WriteString(`\r`)
+		case '\t':
+			buf.WriteString(`\t`)
+		default:
+			if r < 0x20 { //nolint:gomnd
+				buf.WriteString(`\u00`)
+				buf.WriteByte(hex[r>>4])
+				buf.WriteByte(hex[r&0xF]) //nolint:gomnd
+			} else {
+				buf.WriteRune(r)
+			}
+		}
+	}
+
+	buf.WriteByte('"')
+}
+
+// formatJCSNumber serializes f as ECMAScript's Number.prototype.toString does (RFC 8785 section 3.2.2.3): the shortest
+// digits representing f, in decimal notation for exponents from -7 to 20 and in exponential notation otherwise.
+func formatJCSNumber(f float64) string {
+	if f == 0 {
+		// -0 is serialized as 0.
+		return "0"
+	}
+
+	sign := ""
+
+	if f < 0 {
+		sign, f = "-", -f
+	}
+
+	// shortest digits d1.d2...dk and exponent e, f = 0.d1d2...dk * 10^n with n = e + 1.
+	mantissa, exp, _ := strings.Cut(strconv.FormatFloat(f, 'e', -1, 64), "e")
+	digits := strings.Replace(mantissa, ".", "", 1)
+
+	e, _ := strconv.Atoi(exp) //nolint:errcheck // FormatFloat always writes a valid exponent.
+	n, k := e+1, len(digits)
+
+	const maxDecimalExponent = 21
+
+	switch {
+	case k <= n && n <= maxDecimalExponent:
+		return sign + digits + strings.Repeat("0", n-k)
+	case 0 < n && n <= maxDecimalExponent:
+		return sign + digits[:n] + "." + digits[n:]
+	case -6 < n && n <= 0: //nolint:gomnd
+		return sign + "0." + strings.Repeat("0", -n) + digits
+	}
+
+	expSign := "+"
+	if n-1 < 0 {
+		expSign = "-"
+	}
+
+	exponent := strconv.Itoa(abs(n - 1))
+
+	if k == 1 {
+		return sign + digits + "e" + expSign + exponent
+	}
+
+	return sign + digits[:1] + "." + digits[1:] + "e" + expSign + exponent
+}
+
+func abs(i int) int {
+	if i < 0 {
+		return -i
+	}
+
+	return i
+}
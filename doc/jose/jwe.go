@@ -334,6 +334,11 @@ func deserializeAndDecodeHeaders(rawJWE *rawJSONWebEncryption) (*Headers, *Heade
 		return nil, nil, err
 	}
 
+	err = checkStringHeaders(protectedHeaders)
+	if err != nil {
+		return nil, nil, err
+	}
+
 	var unprotectedHeaders Headers
 
 	if rawJWE.UnprotectedHeaders != nil {
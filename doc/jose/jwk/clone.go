@@ -0,0 +1,152 @@
+/*
+Copyright Gen Digital Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package jwk
+
+import (
+	"crypto/ecdsa"
+	"crypto/rsa"
+	"math/big"
+
+	ml "github.com/IBM/mathlib"
+	"github.com/trustbloc/bbs-signature-go/bbs12381g2pub"
+	"golang.org/x/crypto/ed25519"
+)
+
+// Clone returns a deep copy of j which can be mutated independently of j: the key material (EC coordinates and
+// private scalars, RSA moduli and primes, raw key bytes, BLS12-381 points), byte slices, certificate chain slice and
+// Extra members are copied. Parsed x509 certificates are shared as they are not meant to be mutated. Keys of types
+// unknown to JWK are shared as well.
+func (j *JWK) Clone() *JWK {
+	if j == nil {
+		return nil
+	}
+
+	clone := &JWK{
+		JSONWebKey:             j.JSONWebKey,
+		Kty:                    j.Kty,
+		Crv:                    j.Crv,
+		X509CertThumbprintS256: cloneBytes(j.X509CertThumbprintS256),
+	}
+
+	clone.Key = cloneKey(j.Key)
+	clone.CertificateThumbprintSHA1 = cloneBytes(j.CertificateThumbprintSHA1)
+	clone.CertificateThumbprintSHA256 = cloneBytes(j.CertificateThumbprintSHA256)
+
+	if j.Certificates != nil {
+		clone.Certificates = append(clone.Certificates[:0:0], j.Certificates...)
+	}
+
+	if j.CertificatesURL != nil {
+		certsURL := *j.CertificatesURL
+		clone.CertificatesURL = &certsURL
+	}
+
+	if j.Extra != nil {
+		clone.Extra = cloneJSONValue(j.Extra).(map[string]interface{}) //nolint:forcetypeassert
+	}
+
+	return clone
+}
+
+func cloneKey(key interface{}) interface{} { //nolint:gocyclo
+	switch k := key.(type) {
+	case *ecdsa.PublicKey:
+		return cloneECDSAPublicKey(k)
+	case *ecdsa.PrivateKey:
+		return &ecdsa.PrivateKey{PublicKey: *cloneECDSAPublicKey(&k.PublicKey), D: cloneBigInt(k.D)}
+	case *rsa.PublicKey:
+		return &rsa.PublicKey{N: cloneBigInt(k.N), E: k.E}
+	case *rsa.PrivateKey:
+		return cloneRSAPrivateKey(k)
+	case ed25519.PublicKey:
+		return ed25519.PublicKey(cloneBytes(k))
+	case ed25519.PrivateKey:
+		return ed25519.PrivateKey(cloneBytes(k))
+	case []byte:
+		return cloneBytes(k)
+	case *ml.G1:
+		return k.Copy()
+	case *bbs12381g2pub.PublicKey:
+		// bbs12381g2pub keys don't expose their points, round trip them through their binary form instead.
+		if keyBytes, err := k.Marshal(); err == nil {
+			if clone, err := bbs12381g2pub.UnmarshalPublicKey(keyBytes); err == nil {
+				return clone
+			}
+		}
+	case *bbs12381g2pub.PrivateKey:
+		if keyBytes, err := k.Marshal(); err == nil {
+			if clone, err := bbs12381g2pub.UnmarshalPrivateKey(keyBytes); err == nil {
+				return clone
+			}
+		}
+	}
+
+	return key
+}
+
+func cloneECDSAPublicKey(key *ecdsa.PublicKey) *ecdsa.PublicKey {
+	return &ecdsa.PublicKey{Curve: key.Curve, X: cloneBigInt(key.X), Y: cloneBigInt(key.Y)}
+}
+
+func cloneRSAPrivateKey(key *rsa.PrivateKey) *rsa.PrivateKey {
+	clone := &rsa.PrivateKey{
+		PublicKey: rsa.PublicKey{N: cloneBigInt(key.N), E: key.E},
+		D:         cloneBigInt(key.D),
+	}
+
+	for _, prime := range key.Primes {
+		clone.Primes = append(clone.Primes, cloneBigInt(prime))
+	}
+
+	// the precomputed values are derived from the primes, recompute them instead of copying them.
+	if key.Precomputed.Dp != nil {
+		clone.Precompute()
+	}
+
+	return clone
+}
+
+func cloneBigInt(i *big.Int) *big.Int {
+	if i == nil {
+		return nil
+	}
+
+	return new(big.Int).Set(i)
+}
+
+func cloneBytes(b []byte) []byte {
+	if b == nil {
+		return nil
+	}
+
+	return append([]byte{}, b...)
+}
+
+// cloneJSONValue deep copies the objects and arrays of a value unmarshalled by encoding/json, other values are
+// immutable and returned as is.
+func cloneJSONValue(value interface{}) interface{} {
+	switch v := value.(type) {
+	case map[string]interface{}:
+		clone := make(map[string]interface{}, len(v))
+
+		for name, member := range v {
+			clone[name] = cloneJSONValue(member)
+		}
+
+		return clone
+	case []interface{}:
+		clone := make([]interface{}, len(v))
+
+		for i, elem := range v {
+			clone[i] = cloneJSONValue(elem)
+		}
+
+		return clone
+	default:
+		return value
+	}
+}
@@ -0,0 +1,174 @@
+/*
+Copyright Gen Digital Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package jwk
+
+import (
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
+	"net/url"
+	"testing"
+
+	"github.com/btcsuite/btcd/btcec/v2"
+	"github.com/go-jose/go-jose/v3"
+	"github.com/stretchr/testify/require"
+	"github.com/trustbloc/bbs-signature-go/bbs12381g2pub"
+)
+
+func TestJWK_Clone(t *testing.T) {
+	ecKey, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+
+	secp256k1Key, err := ecdsa.GenerateKey(btcec.S256(), rand.Reader)
+	require.NoError(t, err)
+
+	rsaKey, err := rsa.GenerateKey(rand.Reader, 2048)
+	require.NoError(t, err)
+
+	edPub, edPriv, err := ed25519.GenerateKey(rand.Reader)
+	require.NoError(t, err)
+
+	bbsPub, bbsPriv, err := bbs12381g2pub.GenerateKeyPair(sha256.New, nil)
+	require.NoError(t, err)
+
+	x25519Key := make([]byte, 32)
+	_, err = rand.Read(x25519Key)
+	require.NoError(t, err)
+
+	tests := []struct {
+		name string
+		jwk  *JWK
+		// mutate modifies the key material of the clone in place.
+		mutate func(key interface{})
+	}{
+		{
+			name: "P-256 private key",
+			jwk:  &JWK{JSONWebKey: jose.JSONWebKey{Key: ecKey}, Kty: ecKty, Crv: "P-256"},
+			mutate: func(key interface{}) {
+				key.(*ecdsa.PrivateKey).D.SetInt64(1)
+				key.(*ecdsa.PrivateKey).X.SetInt64(1)
+			},
+		},
+		{
+			name: "P-256 public key",
+			jwk:  &JWK{JSONWebKey: jose.JSONWebKey{Key: &ecKey.PublicKey}, Kty: ecKty, Crv: "P-256"},
+			mutate: func(key interface{}) {
+				key.(*ecdsa.PublicKey).Y.SetInt64(1)
+			},
+		},
+		{
+			name: "secp256k1 private key",
+			jwk: &JWK{
+				JSONWebKey: jose.JSONWebKey{Key: secp256k1Key, Algorithm: secp256k1Alg},
+				Kty:        ecKty,
+				Crv:        secp256k1Crv,
+			},
+			mutate: func(key interface{}) {
+				key.(*ecdsa.PrivateKey).D.SetInt64(1)
+			},
+		},
+		{
+			name: "RSA private key",
+			jwk:  &JWK{JSONWebKey: jose.JSONWebKey{Key: rsaKey}, Kty: "RSA"},
+			mutate: func(key interface{}) {
+				key.(*rsa.PrivateKey).N.SetInt64(1)
+				key.(*rsa.PrivateKey).Primes[0].SetInt64(1)
+				key.(*rsa.PrivateKey).Precomputed.Dp.SetInt64(1)
+			},
+		},
+		{
+			name: "RSA public key",
+			jwk:  &JWK{JSONWebKey: jose.JSONWebKey{Key: &rsaKey.PublicKey}, Kty: "RSA"},
+			mutate: func(key interface{}) {
+				key.(*rsa.PublicKey).N.SetInt64(1)
+			},
+		},
+		{
+			name: "Ed25519 private key",
+			jwk:  &JWK{JSONWebKey: jose.JSONWebKey{Key: edPriv}, Kty: okpKty, Crv: ed25519Crv},
+			mutate: func(key interface{}) {
+				key.(ed25519.PrivateKey)[0] ^= 0xff
+			},
+		},
+		{
+			name: "Ed25519 public key",
+			jwk:  &JWK{JSONWebKey: jose.JSONWebKey{Key: edPub}, Kty: okpKty, Crv: ed25519Crv},
+			mutate: func(key interface{}) {
+				key.(ed25519.PublicKey)[0] ^= 0xff
+			},
+		},
+		{
+			name: "X25519 public key",
+			jwk:  &JWK{JSONWebKey: jose.JSONWebKey{Key: x25519Key}, Kty: okpKty, Crv: x25519Crv},
+			mutate: func(key interface{}) {
+				key.([]byte)[0] ^= 0xff
+			},
+		},
+		{
+			name: "BBS+ private key",
+			jwk:  &JWK{JSONWebKey: jose.JSONWebKey{Key: bbsPriv}, Kty: ecKty, Crv: bls12381G2Crv},
+		},
+		{
+			name: "BBS+ public key",
+			jwk:  &JWK{JSONWebKey: jose.JSONWebKey{Key: bbsPub}, Kty: ecKty, Crv: bls12381G2Crv},
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			tc.jwk.KeyID = "key-1"
+			tc.jwk.Use = "sig"
+			tc.jwk.CertificateThumbprintSHA1 = make([]byte, 20)
+			tc.jwk.CertificatesURL = &url.URL{Scheme: "https", Host: "example.com", Path: "/certs"}
+			tc.jwk.SetExtra("ext", map[string]interface{}{"nested": []interface{}{"value"}})
+
+			jwkJSON, err := tc.jwk.MarshalJSON()
+			require.NoError(t, err)
+
+			clone := tc.jwk.Clone()
+
+			cloneJSON, err := clone.MarshalJSON()
+			require.NoError(t, err)
+			require.JSONEq(t, string(jwkJSON), string(cloneJSON))
+
+			if tc.jwk.Key != nil {
+				require.NotSame(t, tc.jwk.Key, clone.Key)
+			}
+
+			clone.KeyID = "key-2"
+			clone.Use = "enc"
+			clone.CertificateThumbprintSHA1[0] = 'X'
+			clone.CertificatesURL.Path = "/other"
+			clone.Extra["ext"].(map[string]interface{})["nested"].([]interface{})[0] = "modified"
+
+			if tc.mutate != nil {
+				tc.mutate(clone.Key)
+			}
+
+			// the original JWK is not modified.
+			jwkJSONAfter, err := tc.jwk.MarshalJSON()
+			require.NoError(t, err)
+			require.JSONEq(t, string(jwkJSON), string(jwkJSONAfter))
+		})
+	}
+
+	t.Run("nil JWK", func(t *testing.T) {
+		var j *JWK
+
+		require.Nil(t, j.Clone())
+	})
+
+	t.Run("unknown key type is shared", func(t *testing.T) {
+		key := &struct{ value int }{value: 1}
+
+		clone := (&JWK{JSONWebKey: jose.JSONWebKey{Key: key}}).Clone()
+		require.Same(t, key, clone.Key)
+	})
+}
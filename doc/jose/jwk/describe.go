@@ -0,0 +1,146 @@
+/*
+Copyright Gen Digital Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package jwk
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"strings"
+)
+
+// headerMembers are the JWK members describing a key, compared by DiffJWK and printed by DescribeJWK.
+var headerMembers = []string{"kty", "crv", "alg", "use", "kid"} //nolint:gochecknoglobals
+
+// publicMembers are the JWK members holding public key values (RFC 7518 section 6, RFC 8037 and the ML-DSA pub
+// member).
+var publicMembers = []string{"x", "y", "n", "e", "pub"} //nolint:gochecknoglobals
+
+// DescribeJWK returns a one line summary of j safe to log when debugging key mismatches, e.g.
+// "kty=EC crv=P-256 alg=ES256 use= kid=key-1 x=32B y=32B private=false". Public key values are described by their
+// decoded length and private or secret key material only by the names of its members ("private=true (d)"), their
+// values are never printed.
+func DescribeJWK(j *JWK) string {
+	if j == nil {
+		return "<nil>"
+	}
+
+	members, err := jwkMembers(j)
+	if err != nil {
+		return fmt.Sprintf("kty=%s crv=%s alg=%s use=%s kid=%s (%s)", j.Kty, j.Crv, j.Algorithm, j.Use, j.KeyID, err)
+	}
+
+	fields := make([]string, 0, len(headerMembers)+len(publicMembers)+1)
+
+	for _, name := range headerMembers {
+		fields = append(fields, name+"="+stringMember(members, name))
+	}
+
+	for _, name := range publicMembers {
+		if _, ok := members[name]; ok {
+			fields = append(fields, name+"="+memberLength(members, name))
+		}
+	}
+
+	var private []string
+
+	for _, name := range privateMembers {
+		if _, ok := members[name]; ok {
+			private = append(private, name)
+		}
+	}
+
+	if len(private) == 0 {
+		fields = append(fields, "private=false")
+	} else {
+		fields = append(fields, fmt.Sprintf("private=true (%s)", strings.Join(private, ", ")))
+	}
+
+	return strings.Join(fields, " ")
+}
+
+// DiffJWK lists, one per line, the public members differing between a and b: kty, crv, alg, use and kid with their
+// values, and public key values (x, y, n, e, pub) with their decoded lengths. Private key material is ignored, a
+// private key and its public key have no differences. DiffJWK returns an empty string when no public member differs.
+func DiffJWK(a, b *JWK) string {
+	if a == nil || b == nil {
+		if a == b {
+			return ""
+		}
+
+		return fmt.Sprintf("jwk: %s != %s", DescribeJWK(a), DescribeJWK(b))
+	}
+
+	aMembers, err := jwkMembers(a)
+	if err != nil {
+		return fmt.Sprintf("a: %s", err)
+	}
+
+	bMembers, err := jwkMembers(b)
+	if err != nil {
+		return fmt.Sprintf("b: %s", err)
+	}
+
+	var diffs []string
+
+	for _, name := range headerMembers {
+		if aValue, bValue := stringMember(aMembers, name), stringMember(bMembers, name); aValue != bValue {
+			diffs = append(diffs, fmt.Sprintf("%s: '%s' != '%s'", name, aValue, bValue))
+		}
+	}
+
+	for _, name := range publicMembers {
+		if stringMember(aMembers, name) != stringMember(bMembers, name) {
+			diffs = append(diffs, fmt.Sprintf("%s: differs (%s != %s)", name, memberLength(aMembers, name),
+				memberLength(bMembers, name)))
+		}
+	}
+
+	return strings.Join(diffs, "\n")
+}
+
+// jwkMembers returns the members of the marshalled j.
+func jwkMembers(j *JWK) (map[string]json.RawMessage, error) {
+	jwkBytes, err := j.MarshalJSON()
+	if err != nil {
+		return nil, fmt.Errorf("marshal error: %w", err)
+	}
+
+	var members map[string]json.RawMessage
+
+	err = json.Unmarshal(jwkBytes, &members)
+	if err != nil {
+		return nil, fmt.Errorf("marshal error: %w", err)
+	}
+
+	return members, nil
+}
+
+// stringMember returns the value of the string member name, or an empty string when it's not set.
+func stringMember(members map[string]json.RawMessage, name string) string {
+	var value string
+
+	if raw, ok := members[name]; ok {
+		_ = json.Unmarshal(raw, &value) //nolint:errcheck // non string members are described as empty.
+	}
+
+	return value
+}
+
+// memberLength describes the decoded length of the base64url member name, e.g. "32B".
+func memberLength(members map[string]json.RawMessage, name string) string {
+	if _, ok := members[name]; !ok {
+		return "absent"
+	}
+
+	value, err := base64.RawURLEncoding.DecodeString(stringMember(members, name))
+	if err != nil {
+		return "invalid"
+	}
+
+	return fmt.Sprintf("%dB", len(value))
+}
@@ -0,0 +1,87 @@
+/*
+Copyright Gen Digital Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package jwk
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/rsa"
+	"encoding/base64"
+	"testing"
+
+	"github.com/go-jose/go-jose/v3"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDescribeJWK(t *testing.T) {
+	ecKey, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+
+	ecJWK := &JWK{
+		JSONWebKey: jose.JSONWebKey{Key: ecKey, KeyID: "key-1", Algorithm: "ES256", Use: "sig"},
+		Kty:        ecKty,
+		Crv:        "P-256",
+	}
+
+	require.Equal(t, "kty=EC crv=P-256 alg=ES256 use=sig kid=key-1 x=32B y=32B private=true (d)",
+		DescribeJWK(ecJWK))
+	require.Equal(t, "kty=EC crv=P-256 alg=ES256 use=sig kid=key-1 x=32B y=32B private=false",
+		DescribeJWK(ecJWK.Public()))
+
+	rsaKey, err := rsa.GenerateKey(rand.Reader, 2048)
+	require.NoError(t, err)
+
+	require.Equal(t, "kty=RSA crv= alg= use= kid= n=256B e=3B private=true (d, p, q, dp, dq, qi)",
+		DescribeJWK(&JWK{JSONWebKey: jose.JSONWebKey{Key: rsaKey}, Kty: "RSA"}))
+
+	secret := []byte("0123456789abcdef")
+	description := DescribeJWK(&JWK{JSONWebKey: jose.JSONWebKey{Key: secret}, Kty: "oct"})
+	require.Equal(t, "kty=oct crv= alg= use= kid= private=true (k)", description)
+	require.NotContains(t, description, base64.RawURLEncoding.EncodeToString(secret))
+
+	require.Equal(t, "kty=OKP crv=X25519 alg= use= kid= x=32B private=false",
+		DescribeJWK(&JWK{JSONWebKey: jose.JSONWebKey{Key: make([]byte, 32)}, Kty: okpKty, Crv: x25519Crv}))
+
+	require.Equal(t, "<nil>", DescribeJWK(nil))
+	require.Contains(t, DescribeJWK(&JWK{JSONWebKey: jose.JSONWebKey{Key: "invalid", KeyID: "bad"}, Kty: "EC"}),
+		"kty=EC crv= alg= use= kid=bad (marshal error: ")
+}
+
+func TestDiffJWK(t *testing.T) {
+	ecKey, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+
+	otherKey, err := ecdsa.GenerateKey(elliptic.P384(), rand.Reader)
+	require.NoError(t, err)
+
+	a := &JWK{JSONWebKey: jose.JSONWebKey{Key: ecKey, KeyID: "key-1", Algorithm: "ES256"}, Kty: ecKty, Crv: "P-256"}
+
+	require.Empty(t, DiffJWK(a, a.Public()))
+	require.Empty(t, DiffJWK(nil, nil))
+
+	b := &JWK{JSONWebKey: jose.JSONWebKey{Key: &otherKey.PublicKey, KeyID: "key-2", Algorithm: "ES384"}, Kty: ecKty,
+		Crv: "P-384"}
+
+	require.Equal(t, "crv: 'P-256' != 'P-384'\n"+
+		"alg: 'ES256' != 'ES384'\n"+
+		"kid: 'key-1' != 'key-2'\n"+
+		"x: differs (32B != 48B)\n"+
+		"y: differs (32B != 48B)", DiffJWK(a, b))
+
+	x25519JWK := &JWK{JSONWebKey: jose.JSONWebKey{Key: make([]byte, 32), KeyID: "key-1"}, Kty: okpKty, Crv: x25519Crv}
+
+	require.Equal(t, "kty: 'EC' != 'OKP'\n"+
+		"crv: 'P-256' != 'X25519'\n"+
+		"alg: 'ES256' != ''\n"+
+		"x: differs (32B != 32B)\n"+
+		"y: differs (32B != absent)", DiffJWK(a, x25519JWK))
+
+	require.Equal(t, "jwk: <nil> != kty=OKP crv=X25519 alg= use= kid=key-1 x=32B private=false",
+		DiffJWK(nil, x25519JWK))
+	require.Contains(t, DiffJWK(a, &JWK{JSONWebKey: jose.JSONWebKey{Key: "invalid"}}), "b: marshal error: ")
+}
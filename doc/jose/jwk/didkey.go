@@ -0,0 +1,174 @@
+/*
+Copyright Gen Digital Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package jwk
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/btcsuite/btcd/btcec/v2"
+	"github.com/go-jose/go-jose/v3"
+	"github.com/trustbloc/bbs-signature-go/bbs12381g2pub"
+	"golang.org/x/crypto/ed25519"
+
+	"github.com/dellekappa/kms-go/doc/util/multicodec"
+	"github.com/dellekappa/kms-go/util/cryptoutil"
+)
+
+const didKeyPrefix = "did:key:"
+
+// DIDKey returns the did:key identifier of the JWK public key, the base58-btc multibase encoding of the public key
+// prefixed with its multicodec code (https://w3c-ccg.github.io/did-method-key/#format). Supported keys are Ed25519,
+// X25519, secp256k1, NIST P-256/384/521 (compressed) and BLS12381G2 keys.
+func (j *JWK) DIDKey() (string, error) {
+	code, pubKey, err := j.multicodecPublicKey()
+	if err != nil {
+		return "", fmt.Errorf("didKey: %w", err)
+	}
+
+	return didKeyPrefix + multicodec.Encode(code, pubKey), nil
+}
+
+func (j *JWK) multicodecPublicKey() (uint64, []byte, error) {
+	switch {
+	case j.isBLS12381G2():
+		pubKey, err := j.PublicKeyBytes()
+
+		return multicodec.BLS12381g2PubKey, pubKey, err
+	case j.isX25519():
+		pubKey, err := j.PublicKeyBytes()
+
+		return multicodec.X25519PubKey, pubKey, err
+	case j.isSecp256k1():
+		pubKey, err := j.PublicKeyBytes()
+
+		return multicodec.Secp256k1PubKey, pubKey, err
+	}
+
+	switch pubKey := j.Public().Key.(type) {
+	case ed25519.PublicKey:
+		return multicodec.ED25519PubKey, pubKey, nil
+	case *ecdsa.PublicKey:
+		var code uint64
+
+		switch pubKey.Curve {
+		case elliptic.P256():
+			code = multicodec.P256PubKey
+		case elliptic.P384():
+			code = multicodec.P384PubKey
+		case elliptic.P521():
+			code = multicodec.P521PubKey
+		default:
+			return 0, nil, fmt.Errorf("unsupported curve %s", pubKey.Curve.Params().Name)
+		}
+
+		return code, elliptic.MarshalCompressed(pubKey.Curve, pubKey.X, pubKey.Y), nil
+	default:
+		return 0, nil, fmt.Errorf("unsupported public key type %T", j.Key)
+	}
+}
+
+// DIDKeyToJWK parses the did:key identifier did, with an optional DID URL fragment, and returns the JWK of its public
+// key. It is the reverse of JWK.DIDKey.
+func DIDKeyToJWK(did string) (*JWK, error) {
+	if !strings.HasPrefix(did, didKeyPrefix) {
+		return nil, fmt.Errorf("didKeyToJWK: not a did:key identifier: %s", did)
+	}
+
+	methodID := strings.TrimPrefix(did, didKeyPrefix)
+	if i := strings.IndexByte(methodID, '#'); i >= 0 {
+		methodID = methodID[:i]
+	}
+
+	pubKey, code, err := multicodec.Decode(methodID)
+	if err != nil {
+		return nil, fmt.Errorf("didKeyToJWK: %w", err)
+	}
+
+	j, err := multicodecPublicKeyToJWK(code, pubKey)
+	if err != nil {
+		return nil, fmt.Errorf("didKeyToJWK: %w", err)
+	}
+
+	return j, nil
+}
+
+func multicodecPublicKeyToJWK(code uint64, pubKey []byte) (*JWK, error) { //nolint:funlen
+	switch code {
+	case multicodec.ED25519PubKey:
+		if len(pubKey) != ed25519.PublicKeySize {
+			return nil, errors.New("invalid Ed25519 public key size")
+		}
+
+		return &JWK{
+			JSONWebKey: jose.JSONWebKey{Key: ed25519.PublicKey(pubKey)},
+			Kty:        okpKty,
+			Crv:        ed25519Crv,
+		}, nil
+	case multicodec.X25519PubKey:
+		if len(pubKey) != cryptoutil.Curve25519KeySize {
+			return nil, errors.New("invalid X25519 public key size")
+		}
+
+		return &JWK{
+			JSONWebKey: jose.JSONWebKey{Key: pubKey},
+			Kty:        okpKty,
+			Crv:        x25519Crv,
+		}, nil
+	case multicodec.Secp256k1PubKey:
+		key, err := btcec.ParsePubKey(pubKey)
+		if err != nil {
+			return nil, fmt.Errorf("invalid secp256k1 public key: %w", err)
+		}
+
+		return &JWK{
+			JSONWebKey: jose.JSONWebKey{Key: key.ToECDSA(), Algorithm: secp256k1Alg},
+			Kty:        ecKty,
+			Crv:        secp256k1Crv,
+		}, nil
+	case multicodec.P256PubKey, multicodec.P384PubKey, multicodec.P521PubKey:
+		curve := nistCurveFromCode(code)
+
+		x, y := elliptic.UnmarshalCompressed(curve, pubKey)
+		if x == nil {
+			return nil, fmt.Errorf("invalid %s public key", curve.Params().Name)
+		}
+
+		return &JWK{
+			JSONWebKey: jose.JSONWebKey{Key: &ecdsa.PublicKey{Curve: curve, X: x, Y: y}},
+			Kty:        ecKty,
+			Crv:        curve.Params().Name,
+		}, nil
+	case multicodec.BLS12381g2PubKey:
+		key, err := bbs12381g2pub.UnmarshalPublicKey(pubKey)
+		if err != nil {
+			return nil, fmt.Errorf("invalid BLS12381G2 public key: %w", err)
+		}
+
+		return &JWK{
+			JSONWebKey: jose.JSONWebKey{Key: key},
+			Kty:        ecKty,
+			Crv:        bls12381G2Crv,
+		}, nil
+	default:
+		return nil, fmt.Errorf("unsupported key multicodec code [0x%x]", code)
+	}
+}
+
+func nistCurveFromCode(code uint64) elliptic.Curve {
+	switch code {
+	case multicodec.P384PubKey:
+		return elliptic.P384()
+	case multicodec.P521PubKey:
+		return elliptic.P521()
+	default:
+		return elliptic.P256()
+	}
+}
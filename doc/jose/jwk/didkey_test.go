@@ -0,0 +1,207 @@
+/*
+Copyright Gen Digital Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package jwk
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
+	"strings"
+	"testing"
+
+	"github.com/btcsuite/btcd/btcec/v2"
+	"github.com/go-jose/go-jose/v3"
+	"github.com/stretchr/testify/require"
+	"github.com/trustbloc/bbs-signature-go/bbs12381g2pub"
+	"golang.org/x/crypto/ed25519"
+
+	"github.com/dellekappa/kms-go/doc/util/multicodec"
+)
+
+func TestJWK_DIDKey(t *testing.T) {
+	edPub, edPriv, err := ed25519.GenerateKey(rand.Reader)
+	require.NoError(t, err)
+
+	x25519Pub := make([]byte, 32)
+	_, err = rand.Read(x25519Pub)
+	require.NoError(t, err)
+
+	secp256k1Key, err := ecdsa.GenerateKey(btcec.S256(), rand.Reader)
+	require.NoError(t, err)
+
+	p256Key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+
+	p384Key, err := ecdsa.GenerateKey(elliptic.P384(), rand.Reader)
+	require.NoError(t, err)
+
+	p521Key, err := ecdsa.GenerateKey(elliptic.P521(), rand.Reader)
+	require.NoError(t, err)
+
+	blsPub, _, err := bbs12381g2pub.GenerateKeyPair(sha256.New, nil)
+	require.NoError(t, err)
+
+	// did:key method specific ID prefixes from https://w3c-ccg.github.io/did-method-key/.
+	tests := []struct {
+		name   string
+		jwk    *JWK
+		prefix string
+	}{
+		{
+			name:   "Ed25519 public key",
+			jwk:    &JWK{JSONWebKey: jose.JSONWebKey{Key: edPub}, Kty: okpKty, Crv: ed25519Crv},
+			prefix: "did:key:z6Mk",
+		},
+		{
+			name:   "Ed25519 private key",
+			jwk:    &JWK{JSONWebKey: jose.JSONWebKey{Key: edPriv}, Kty: okpKty, Crv: ed25519Crv},
+			prefix: "did:key:z6Mk",
+		},
+		{
+			name:   "X25519",
+			jwk:    &JWK{JSONWebKey: jose.JSONWebKey{Key: x25519Pub}, Kty: okpKty, Crv: x25519Crv},
+			prefix: "did:key:z6LS",
+		},
+		{
+			name:   "secp256k1",
+			jwk:    &JWK{JSONWebKey: jose.JSONWebKey{Key: &secp256k1Key.PublicKey}, Kty: ecKty, Crv: secp256k1Crv},
+			prefix: "did:key:zQ3s",
+		},
+		{
+			name:   "P-256",
+			jwk:    &JWK{JSONWebKey: jose.JSONWebKey{Key: &p256Key.PublicKey}, Kty: ecKty, Crv: "P-256"},
+			prefix: "did:key:zDn",
+		},
+		{
+			name:   "P-384",
+			jwk:    &JWK{JSONWebKey: jose.JSONWebKey{Key: &p384Key.PublicKey}, Kty: ecKty, Crv: "P-384"},
+			prefix: "did:key:z82",
+		},
+		{
+			name:   "P-521 private key",
+			jwk:    &JWK{JSONWebKey: jose.JSONWebKey{Key: p521Key}, Kty: ecKty, Crv: "P-521"},
+			prefix: "did:key:z2J9",
+		},
+		{
+			name:   "BLS12381G2",
+			jwk:    &JWK{JSONWebKey: jose.JSONWebKey{Key: blsPub}, Kty: ecKty, Crv: bls12381G2Crv},
+			prefix: "did:key:zUC7",
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			did, err := tc.jwk.DIDKey()
+			require.NoError(t, err)
+			require.True(t, strings.HasPrefix(did, tc.prefix), did)
+
+			pubKey, err := tc.jwk.PublicKeyBytes()
+			require.NoError(t, err)
+
+			parsed, err := DIDKeyToJWK(did)
+			require.NoError(t, err)
+
+			parsedPubKey, err := parsed.PublicKeyBytes()
+			require.NoError(t, err)
+			require.Equal(t, pubKey, parsedPubKey)
+			require.Equal(t, tc.jwk.Kty, parsed.Kty)
+			require.Equal(t, tc.jwk.Crv, parsed.Crv)
+
+			parsed, err = DIDKeyToJWK(did + "#" + strings.TrimPrefix(did, didKeyPrefix))
+			require.NoError(t, err)
+
+			roundTrip, err := parsed.DIDKey()
+			require.NoError(t, err)
+			require.Equal(t, did, roundTrip)
+		})
+	}
+
+	t.Run("Ed25519 test vector", func(t *testing.T) {
+		// https://w3c-ccg.github.io/did-method-key/#example-a-simple-ed25519-did-key-value
+		const did = "did:key:z6MkhaXgBZDvotDkL5257faiztiGiC2QtKLGpbnnEGta2doK"
+
+		j, err := DIDKeyToJWK(did)
+		require.NoError(t, err)
+		require.IsType(t, ed25519.PublicKey{}, j.Key)
+
+		didKey, err := j.DIDKey()
+		require.NoError(t, err)
+		require.Equal(t, did, didKey)
+	})
+
+	t.Run("error unsupported key", func(t *testing.T) {
+		rsaKey, err := rsa.GenerateKey(rand.Reader, 2048)
+		require.NoError(t, err)
+
+		_, err = (&JWK{JSONWebKey: jose.JSONWebKey{Key: &rsaKey.PublicKey}, Kty: "RSA"}).DIDKey()
+		require.EqualError(t, err, "didKey: unsupported public key type *rsa.PublicKey")
+
+		p224Key, err := ecdsa.GenerateKey(elliptic.P224(), rand.Reader)
+		require.NoError(t, err)
+
+		_, err = (&JWK{JSONWebKey: jose.JSONWebKey{Key: &p224Key.PublicKey}, Kty: ecKty}).DIDKey()
+		require.EqualError(t, err, "didKey: unsupported curve P-224")
+	})
+
+	t.Run("DIDKeyToJWK errors", func(t *testing.T) {
+		tests := []struct {
+			name string
+			did  string
+			err  string
+		}{
+			{
+				name: "not a did:key",
+				did:  "did:example:123",
+				err:  "didKeyToJWK: not a did:key identifier: did:example:123",
+			},
+			{
+				name: "not base58-btc",
+				did:  "did:key:m123",
+				err:  "didKeyToJWK: unknown key encoding",
+			},
+			{
+				name: "unsupported code",
+				did:  didKeyPrefix + multicodec.Encode(multicodec.RSAPubKey, []byte("key")),
+				err:  "didKeyToJWK: unsupported key multicodec code [0x1205]",
+			},
+			{
+				name: "invalid Ed25519 key",
+				did:  didKeyPrefix + multicodec.Encode(multicodec.ED25519PubKey, []byte("key")),
+				err:  "didKeyToJWK: invalid Ed25519 public key size",
+			},
+			{
+				name: "invalid X25519 key",
+				did:  didKeyPrefix + multicodec.Encode(multicodec.X25519PubKey, []byte("key")),
+				err:  "didKeyToJWK: invalid X25519 public key size",
+			},
+			{
+				name: "invalid secp256k1 key",
+				did:  didKeyPrefix + multicodec.Encode(multicodec.Secp256k1PubKey, []byte("key")),
+				err:  "didKeyToJWK: invalid secp256k1 public key",
+			},
+			{
+				name: "invalid P-256 key",
+				did:  didKeyPrefix + multicodec.Encode(multicodec.P256PubKey, []byte("key")),
+				err:  "didKeyToJWK: invalid P-256 public key",
+			},
+			{
+				name: "invalid BLS12381G2 key",
+				did:  didKeyPrefix + multicodec.Encode(multicodec.BLS12381g2PubKey, []byte("key")),
+				err:  "didKeyToJWK: invalid BLS12381G2 public key",
+			},
+		}
+
+		for _, tc := range tests {
+			t.Run(tc.name, func(t *testing.T) {
+				_, err := DIDKeyToJWK(tc.did)
+				require.ErrorContains(t, err, tc.err)
+			})
+		}
+	})
+}
@@ -0,0 +1,116 @@
+/*
+Copyright Gen Digital Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package jwk
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"errors"
+	"fmt"
+	"math/big"
+
+	"github.com/btcsuite/btcd/btcec/v2"
+)
+
+// ECPointEncoding is the ANSI X9.62 encoding of an EC public key point.
+type ECPointEncoding int
+
+const (
+	// ECPointUncompressed encodes a point as 0x04 || X || Y.
+	ECPointUncompressed ECPointEncoding = iota
+	// ECPointCompressed encodes a point as 0x02 (even Y) or 0x03 (odd Y) || X.
+	ECPointCompressed
+	// ECPointHybrid encodes a point as 0x06 (even Y) or 0x07 (odd Y) || X || Y.
+	ECPointHybrid
+)
+
+const (
+	ecPointUncompressedPrefix = 0x04
+	ecPointCompressedPrefix   = 0x02
+	ecPointHybridPrefix       = 0x06
+)
+
+// PublicKeyBytesIn converts an EC public key (NIST P curves or secp256k1) to bytes in the given X9.62 encoding.
+// Coordinates are padded to the curve field size. Other key types are not supported, see PublicKeyBytes.
+func (j *JWK) PublicKeyBytesIn(encoding ECPointEncoding) ([]byte, error) {
+	var pubKey *ecdsa.PublicKey
+
+	switch key := j.Key.(type) {
+	case *ecdsa.PublicKey:
+		pubKey = key
+	case *ecdsa.PrivateKey:
+		pubKey = &key.PublicKey
+	default:
+		return nil, fmt.Errorf("publicKeyBytesIn: unsupported public key type %T in kid '%s', EC key expected",
+			j.Key, j.KeyID)
+	}
+
+	size := (pubKey.Curve.Params().BitSize + 7) / 8 //nolint:gomnd
+	yParity := byte(pubKey.Y.Bit(0))
+
+	switch encoding {
+	case ECPointUncompressed:
+		return appendECCoordinates([]byte{ecPointUncompressedPrefix}, size, pubKey.X, pubKey.Y), nil
+	case ECPointCompressed:
+		return appendECCoordinates([]byte{ecPointCompressedPrefix | yParity}, size, pubKey.X), nil
+	case ECPointHybrid:
+		return appendECCoordinates([]byte{ecPointHybridPrefix | yParity}, size, pubKey.X, pubKey.Y), nil
+	default:
+		return nil, fmt.Errorf("publicKeyBytesIn: unsupported EC point encoding %d", encoding)
+	}
+}
+
+func appendECCoordinates(b []byte, size int, coordinates ...*big.Int) []byte {
+	for _, c := range coordinates {
+		b = append(b, c.FillBytes(make([]byte, size))...)
+	}
+
+	return b
+}
+
+// ParseECPoint parses an EC public key point of curve (NIST P curves or secp256k1) in any X9.62 encoding:
+// uncompressed, compressed or hybrid, see ECPointEncoding. The point must be on the curve and, for the hybrid
+// encoding, the prefix must match the parity of Y.
+func ParseECPoint(curve elliptic.Curve, data []byte) (*ecdsa.PublicKey, error) {
+	if curve == btcec.S256() {
+		// btcec parses the three encodings, elliptic can't decompress secp256k1 points.
+		pubKey, err := btcec.ParsePubKey(data)
+		if err != nil {
+			return nil, fmt.Errorf("parseECPoint: %w", err)
+		}
+
+		return pubKey.ToECDSA(), nil
+	}
+
+	if len(data) == 0 {
+		return nil, errors.New("parseECPoint: empty point")
+	}
+
+	var x, y *big.Int
+
+	switch prefix := data[0]; prefix {
+	case ecPointUncompressedPrefix:
+		x, y = elliptic.Unmarshal(curve, data) //nolint:staticcheck // used for its point validation
+	case ecPointCompressedPrefix, ecPointCompressedPrefix | 1:
+		x, y = elliptic.UnmarshalCompressed(curve, data)
+	case ecPointHybridPrefix, ecPointHybridPrefix | 1:
+		uncompressed := append([]byte{ecPointUncompressedPrefix}, data[1:]...)
+
+		x, y = elliptic.Unmarshal(curve, uncompressed) //nolint:staticcheck // used for its point validation
+		if x != nil && y.Bit(0) != uint(prefix&1) {
+			return nil, errors.New("parseECPoint: hybrid point prefix does not match the parity of Y")
+		}
+	default:
+		return nil, fmt.Errorf("parseECPoint: invalid point prefix 0x%02x", prefix)
+	}
+
+	if x == nil {
+		return nil, fmt.Errorf("parseECPoint: invalid point for curve %s", curve.Params().Name)
+	}
+
+	return &ecdsa.PublicKey{Curve: curve, X: x, Y: y}, nil
+}
@@ -0,0 +1,138 @@
+/*
+Copyright Gen Digital Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package jwk
+
+import (
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/elliptic"
+	"crypto/rand"
+	"testing"
+
+	"github.com/btcsuite/btcd/btcec/v2"
+	"github.com/go-jose/go-jose/v3"
+	"github.com/stretchr/testify/require"
+)
+
+func TestJWK_PublicKeyBytesIn(t *testing.T) {
+	for _, curve := range []elliptic.Curve{elliptic.P256(), elliptic.P384(), elliptic.P521(), btcec.S256()} {
+		t.Run(curve.Params().Name, func(t *testing.T) {
+			privKey, err := ecdsa.GenerateKey(curve, rand.Reader)
+			require.NoError(t, err)
+
+			size := (curve.Params().BitSize + 7) / 8
+			parity := byte(privKey.Y.Bit(0))
+
+			tests := []struct {
+				name     string
+				encoding ECPointEncoding
+				prefix   byte
+				length   int
+			}{
+				{name: "uncompressed", encoding: ECPointUncompressed, prefix: 0x04, length: 1 + 2*size},
+				{name: "compressed", encoding: ECPointCompressed, prefix: 0x02 | parity, length: 1 + size},
+				{name: "hybrid", encoding: ECPointHybrid, prefix: 0x06 | parity, length: 1 + 2*size},
+			}
+
+			for _, tc := range tests {
+				t.Run(tc.name, func(t *testing.T) {
+					for _, key := range []interface{}{privKey, &privKey.PublicKey} {
+						pubBytes, err := (&JWK{JSONWebKey: jose.JSONWebKey{Key: key}}).PublicKeyBytesIn(tc.encoding)
+						require.NoError(t, err)
+						require.Len(t, pubBytes, tc.length)
+						require.Equal(t, tc.prefix, pubBytes[0])
+
+						pubKey, err := ParseECPoint(curve, pubBytes)
+						require.NoError(t, err)
+						require.True(t, privKey.PublicKey.Equal(pubKey))
+					}
+				})
+			}
+		})
+	}
+
+	t.Run("unsupported encoding", func(t *testing.T) {
+		privKey, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+		require.NoError(t, err)
+
+		_, err = (&JWK{JSONWebKey: jose.JSONWebKey{Key: privKey}}).PublicKeyBytesIn(ECPointEncoding(42))
+		require.EqualError(t, err, "publicKeyBytesIn: unsupported EC point encoding 42")
+	})
+
+	t.Run("not an EC key", func(t *testing.T) {
+		edPub, _, err := ed25519.GenerateKey(rand.Reader)
+		require.NoError(t, err)
+
+		_, err = (&JWK{JSONWebKey: jose.JSONWebKey{Key: edPub, KeyID: "ed"}}).PublicKeyBytesIn(ECPointUncompressed)
+		require.EqualError(t, err,
+			"publicKeyBytesIn: unsupported public key type ed25519.PublicKey in kid 'ed', EC key expected")
+	})
+}
+
+func TestParseECPoint_Failure(t *testing.T) {
+	privKey, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+
+	hybrid, err := (&JWK{JSONWebKey: jose.JSONWebKey{Key: privKey}}).PublicKeyBytesIn(ECPointHybrid)
+	require.NoError(t, err)
+
+	wrongParity := append([]byte{hybrid[0] ^ 1}, hybrid[1:]...)
+
+	notOnCurve := append([]byte{}, hybrid...)
+	notOnCurve[len(notOnCurve)-1] ^= 0xff
+
+	tests := []struct {
+		name  string
+		curve elliptic.Curve
+		data  []byte
+		err   string
+	}{
+		{
+			name:  "empty",
+			curve: elliptic.P256(),
+			err:   "parseECPoint: empty point",
+		},
+		{
+			name:  "invalid prefix",
+			curve: elliptic.P256(),
+			data:  append([]byte{0x05}, hybrid[1:]...),
+			err:   "parseECPoint: invalid point prefix 0x05",
+		},
+		{
+			name:  "hybrid prefix not matching Y parity",
+			curve: elliptic.P256(),
+			data:  wrongParity,
+			err:   "parseECPoint: hybrid point prefix does not match the parity of Y",
+		},
+		{
+			name:  "hybrid point not on curve",
+			curve: elliptic.P256(),
+			data:  notOnCurve,
+			err:   "parseECPoint: invalid point for curve P-256",
+		},
+		{
+			name:  "point of another curve",
+			curve: elliptic.P384(),
+			data:  hybrid,
+			err:   "parseECPoint: invalid point for curve P-384",
+		},
+		{
+			name:  "secp256k1 point not on curve",
+			curve: btcec.S256(),
+			data:  append([]byte{0x06}, make([]byte, 64)...),
+			err:   "not on secp256k1 curve",
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			pubKey, err := ParseECPoint(tc.curve, tc.data)
+			require.ErrorContains(t, err, tc.err)
+			require.Nil(t, pubKey)
+		})
+	}
+}
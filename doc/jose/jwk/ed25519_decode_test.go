@@ -0,0 +1,100 @@
+/*
+Copyright Gen Digital Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package jwk
+
+import (
+	"crypto/ed25519"
+	"crypto/rand"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseEd25519PublicKey(t *testing.T) {
+	pubKey, _, err := ed25519.GenerateKey(rand.Reader)
+	require.NoError(t, err)
+
+	x := base64.RawURLEncoding.EncodeToString(pubKey)
+
+	t.Run("success", func(t *testing.T) {
+		jwkBytes := []byte(`{"kty":"OKP","crv":"Ed25519","kid":"key-1","x":"` + x + `"}`)
+
+		key, err := ParseEd25519PublicKey(jwkBytes)
+		require.NoError(t, err)
+		require.Equal(t, pubKey, key)
+
+		// same key as the general decoding.
+		var j JWK
+
+		require.NoError(t, json.Unmarshal(jwkBytes, &j))
+		require.Equal(t, j.Key, key)
+	})
+
+	t.Run("failures", func(t *testing.T) {
+		tests := []struct {
+			name    string
+			jwkJSON string
+			field   string
+		}{
+			{name: "X25519 key", jwkJSON: `{"kty":"OKP","crv":"X25519","x":"` + x + `"}`, field: "crv"},
+			{name: "EC key", jwkJSON: `{"kty":"EC","crv":"Ed25519","x":"` + x + `"}`, field: "crv"},
+			{name: "private key", jwkJSON: `{"kty":"OKP","crv":"Ed25519","x":"` + x + `","d":"` + x + `"}`, field: "d"},
+			{name: "missing x", jwkJSON: `{"kty":"OKP","crv":"Ed25519"}`, field: "x"},
+			{name: "short x", jwkJSON: `{"kty":"OKP","crv":"Ed25519","x":"` + x[:42] + `"}`, field: "x"},
+			{name: "long x", jwkJSON: `{"kty":"OKP","crv":"Ed25519","x":"` + x + `AAAA"}`, field: "x"},
+			{name: "invalid base64", jwkJSON: `{"kty":"OKP","crv":"Ed25519","x":"` + x[:42] + `!"}`, field: "x"},
+		}
+
+		for _, tc := range tests {
+			t.Run(tc.name, func(t *testing.T) {
+				_, err := ParseEd25519PublicKey([]byte(tc.jwkJSON))
+				require.ErrorIs(t, err, ErrInvalidKey)
+
+				var jwkErr *JWKError
+
+				require.True(t, errors.As(err, &jwkErr))
+				require.Equal(t, tc.field, jwkErr.Field)
+			})
+		}
+
+		_, err := ParseEd25519PublicKey([]byte("not json"))
+		require.ErrorContains(t, err, "parseEd25519PublicKey: unable to read JWK")
+	})
+}
+
+func BenchmarkEd25519JWKDecode(b *testing.B) {
+	pubKey, _, err := ed25519.GenerateKey(rand.Reader)
+	require.NoError(b, err)
+
+	jwkBytes := []byte(`{"kty":"OKP","crv":"Ed25519","kid":"key-1","x":"` +
+		base64.RawURLEncoding.EncodeToString(pubKey) + `"}`)
+
+	b.Run("json.Unmarshal", func(b *testing.B) {
+		b.ReportAllocs()
+
+		for i := 0; i < b.N; i++ {
+			var j JWK
+
+			if err := json.Unmarshal(jwkBytes, &j); err != nil {
+				b.Fatal(err)
+			}
+		}
+	})
+
+	b.Run("ParseEd25519PublicKey", func(b *testing.B) {
+		b.ReportAllocs()
+
+		for i := 0; i < b.N; i++ {
+			if _, err := ParseEd25519PublicKey(jwkBytes); err != nil {
+				b.Fatal(err)
+			}
+		}
+	})
+}
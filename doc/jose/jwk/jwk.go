@@ -7,9 +7,11 @@ SPDX-License-Identifier: Apache-2.0
 package jwk
 
 import (
+	"bytes"
 	"crypto/ecdsa"
 	"crypto/elliptic"
 	"crypto/rsa"
+	"crypto/sha256"
 	"crypto/x509"
 	"encoding/base64"
 	"encoding/json"
@@ -18,6 +20,7 @@ import (
 	"math/big"
 	"strings"
 
+	ml "github.com/IBM/mathlib"
 	"github.com/btcsuite/btcd/btcec/v2"
 	"github.com/go-jose/go-jose/v3"
 	"github.com/trustbloc/bbs-signature-go/bbs12381g2pub"
@@ -38,6 +41,8 @@ const (
 	ed25519Crv     = "Ed25519"
 	bls12381G2Crv  = "BLS12381_G2"
 	bls12381G2Size = 96
+	bls12381G1Crv  = "BLS12381_G1"
+	bls12381G1Size = 48
 	blsComprPrivSz = 32
 )
 
@@ -47,11 +52,114 @@ type JWK struct {
 
 	Kty string
 	Crv string
+
+	// X509CertThumbprintS256 is the SHA-256 thumbprint of the DER encoded leaf certificate, serialized as `x5t#S256`.
+	X509CertThumbprintS256 []byte
+
+	// Extra holds the members of the JWK which are not registered JWK parameters, they are preserved when the JWK is
+	// unmarshalled and marshalled again. Values are unmarshalled as by encoding/json into an interface{}.
+	Extra map[string]interface{}
+}
+
+// registeredMembers are the JWK parameters registered for the key types supported by JWK, they can't be Extra members.
+var registeredMembers = map[string]struct{}{ //nolint:gochecknoglobals
+	"kty": {}, "use": {}, "key_ops": {}, "alg": {}, "kid": {}, "x5u": {}, "x5c": {}, "x5t": {}, "x5t#S256": {},
+	"crv": {}, "x": {}, "y": {}, "d": {}, "n": {}, "e": {}, "p": {}, "q": {}, "dp": {}, "dq": {}, "qi": {}, "oth": {},
+	"k": {}, "pub": {}, "priv": {},
+}
+
+// GetExtra returns the value of the non registered member name of the JWK, see Extra.
+func (j *JWK) GetExtra(name string) (interface{}, bool) {
+	value, ok := j.Extra[name]
+
+	return value, ok
+}
+
+// SetExtra sets the value of the non registered member name of the JWK, see Extra. Marshalling the JWK fails if name
+// is a registered JWK parameter.
+func (j *JWK) SetExtra(name string, value interface{}) {
+	if j.Extra == nil {
+		j.Extra = map[string]interface{}{}
+	}
+
+	j.Extra[name] = value
+}
+
+// Public returns a new JWK holding only the public key of j: private key material (EC and OKP `d`, RSA private
+// exponent and CRT values, Ed25519 seed, BBS+ private scalar) is stripped while `kid`, `use`, `alg`, `kty`, `crv`,
+// certificates and public key values are preserved. Public keys are returned as is in the new JWK.
+func (j *JWK) Public() *JWK {
+	pub := &JWK{
+		JSONWebKey:             j.JSONWebKey,
+		Kty:                    j.Kty,
+		Crv:                    j.Crv,
+		X509CertThumbprintS256: j.X509CertThumbprintS256,
+	}
+
+	if j.Extra != nil {
+		pub.Extra = make(map[string]interface{}, len(j.Extra))
+
+		for name, value := range j.Extra {
+			pub.Extra[name] = value
+		}
+	}
+
+	switch key := j.Key.(type) {
+	case *ecdsa.PrivateKey:
+		pub.Key = &key.PublicKey
+	case *rsa.PrivateKey:
+		pub.Key = &key.PublicKey
+	case ed25519.PrivateKey:
+		pub.Key = append(ed25519.PublicKey{}, key[ed25519.SeedSize:]...)
+	case *bbs12381g2pub.PrivateKey:
+		pub.Key = key.PublicKey()
+	}
+
+	return pub
 }
 
-// PublicKeyBytes converts a public key to bytes.
-// Note: the Public() member function is in go-jose, this means keys not supported by go-jose are not supported using
-// j.Public(). Instead use this function to get the public raw bytes.
+// SamePublicKey reports whether j and other hold the same public key, regardless of their other members (kid, alg,
+// use, ...), of either holding a private key and of how they were encoded: EC keys are compared by curve and
+// coordinate values, so that keys derived from IEEE P1363 and DER encodings or with differently padded coordinates
+// are recognized as identical.
+func (j *JWK) SamePublicKey(other *JWK) bool {
+	if j == nil || other == nil {
+		return false
+	}
+
+	switch pubKey := j.Public().Key.(type) {
+	case *ecdsa.PublicKey:
+		otherKey, ok := other.Public().Key.(*ecdsa.PublicKey)
+
+		return ok && pubKey.Curve.Params().Name == otherKey.Curve.Params().Name &&
+			pubKey.X.Cmp(otherKey.X) == 0 && pubKey.Y.Cmp(otherKey.Y) == 0
+	case *rsa.PublicKey:
+		otherKey, ok := other.Public().Key.(*rsa.PublicKey)
+
+		return ok && pubKey.E == otherKey.E && pubKey.N.Cmp(otherKey.N) == 0
+	}
+
+	// Ed25519 and X25519 keys have the same size, make sure they are not mixed up.
+	if j.isX25519() != other.isX25519() || (j.Crv != other.Crv && j.Crv != "" && other.Crv != "") {
+		return false
+	}
+
+	pubBytes, err := j.PublicKeyBytes()
+	if err != nil {
+		return false
+	}
+
+	otherBytes, err := other.PublicKeyBytes()
+	if err != nil {
+		return false
+	}
+
+	return bytes.Equal(pubBytes, otherBytes)
+}
+
+// PublicKeyBytes converts a public key to bytes. RSA public keys are marshalled as PKIX DER (SubjectPublicKeyInfo).
+// Note: keys not supported by go-jose are not supported using j.Key or go-jose's JSONWebKey functions. Instead use this
+// function to get the public raw bytes.
 func (j *JWK) PublicKeyBytes() ([]byte, error) { //nolint:gocyclo
 	if j.isBLS12381G2() {
 		switch bbsKey := j.Key.(type) {
@@ -62,6 +170,14 @@ func (j *JWK) PublicKeyBytes() ([]byte, error) { //nolint:gocyclo
 		}
 	}
 
+	if j.isBLS12381G1() {
+		return j.Key.(*ml.G1).Compressed(), nil
+	}
+
+	if j.isMLDSA() {
+		return j.Key.([]byte), nil
+	}
+
 	if j.isX25519() {
 		x25519Key, ok := j.Key.([]byte)
 		if !ok {
@@ -96,7 +212,10 @@ func (j *JWK) PublicKeyBytes() ([]byte, error) { //nolint:gocyclo
 	case *ecdsa.PublicKey:
 		return elliptic.Marshal(pubKey, pubKey.X, pubKey.Y), nil
 	case *rsa.PublicKey:
-		return x509.MarshalPKCS1PublicKey(pubKey), nil
+		// PKIX, like RSA public keys exported by localkms (ExportPubKeyBytes) and read by jwksupport.PubKeyBytesToKey,
+		// so that they round trip with the KMS. did:key (fingerprint.CreateDIDKeyByJwk) uses PKCS#1 instead, as
+		// required by the rsa-pub multicodec, and doesn't go through PublicKeyBytes.
+		return x509.MarshalPKIXPublicKey(pubKey)
 	default:
 		return nil, fmt.Errorf("unsupported public key type in kid '%s'", j.KeyID)
 	}
@@ -111,6 +230,15 @@ func (j *JWK) UnmarshalJSON(jwkBytes []byte) error {
 		return fmt.Errorf("unable to read JWK: %w", marshalErr)
 	}
 
+	if crv, ok := ecCurveAliases[key.Crv]; ok && strings.EqualFold(key.Kty, ecKty) {
+		jwkBytes, marshalErr = replaceCurve(jwkBytes, crv)
+		if marshalErr != nil {
+			return fmt.Errorf("unable to read JWK: %w", marshalErr)
+		}
+
+		key.Crv = crv
+	}
+
 	// nolint: gocritic, nestif
 	if isSecp256k1(key.Alg, key.Kty, key.Crv) {
 		jwk, err := unmarshalSecp256k1(&key)
@@ -125,6 +253,13 @@ func (j *JWK) UnmarshalJSON(jwkBytes []byte) error {
 			return fmt.Errorf("unable to read BBS+ JWE: %w", err)
 		}
 
+		*j = *jwk
+	} else if isBLS12381G1(key.Kty, key.Crv) {
+		jwk, err := unmarshalBLS12381G1(&key)
+		if err != nil {
+			return fmt.Errorf("unable to read BLS12381_G1 JWK: %w", err)
+		}
+
 		*j = *jwk
 	} else if isX25519(key.Kty, key.Crv) {
 		jwk, err := unmarshalX25519(&key)
@@ -132,6 +267,13 @@ func (j *JWK) UnmarshalJSON(jwkBytes []byte) error {
 			return fmt.Errorf("unable to read X25519 JWE: %w", err)
 		}
 
+		*j = *jwk
+	} else if isAKP(key.Kty) {
+		jwk, err := unmarshalMLDSA(&key)
+		if err != nil {
+			return fmt.Errorf("unable to read ML-DSA JWK: %w", err)
+		}
+
 		*j = *jwk
 	} else {
 		var joseJWK jose.JSONWebKey
@@ -141,17 +283,45 @@ func (j *JWK) UnmarshalJSON(jwkBytes []byte) error {
 			return fmt.Errorf("unable to read jose JWK, %w", err)
 		}
 
+		err = checkKeyValues(joseJWK.Key)
+		if err != nil {
+			return fmt.Errorf("unable to read JWK: %w", err)
+		}
+
 		j.JSONWebKey = joseJWK
+		j.X509CertThumbprintS256 = nil
+
+		if len(joseJWK.CertificateThumbprintSHA256) > 0 {
+			j.X509CertThumbprintS256 = joseJWK.CertificateThumbprintSHA256
+		}
 	}
 
 	j.Kty = key.Kty
 	j.Crv = key.Crv
 
+	if j.X509CertThumbprintS256 == nil && key.X5tS256 != nil && len(key.X5tS256.data) > 0 {
+		j.X509CertThumbprintS256 = key.X5tS256.data
+	}
+
+	j.Extra, marshalErr = unmarshalExtra(jwkBytes)
+	if marshalErr != nil {
+		return fmt.Errorf("unable to read JWK: %w", marshalErr)
+	}
+
 	return nil
 }
 
 // MarshalJSON serializes the given key to its JSON representation.
 func (j *JWK) MarshalJSON() ([]byte, error) {
+	jwkBytes, err := j.marshalRegistered()
+	if err != nil || len(j.Extra) == 0 {
+		return jwkBytes, err
+	}
+
+	return marshalExtra(jwkBytes, j.Extra)
+}
+
+func (j *JWK) marshalRegistered() ([]byte, error) {
 	if j.isSecp256k1() {
 		return marshalSecp256k1(j)
 	}
@@ -164,20 +334,88 @@ func (j *JWK) MarshalJSON() ([]byte, error) {
 		return marshalBLS12381G2(j)
 	}
 
-	return (&j.JSONWebKey).MarshalJSON()
+	if j.isBLS12381G1() {
+		return marshalBLS12381G1(j)
+	}
+
+	if j.isMLDSA() {
+		return marshalMLDSA(j)
+	}
+
+	joseJWK := j.JSONWebKey
+
+	if len(j.X509CertThumbprintS256) > 0 {
+		joseJWK.CertificateThumbprintSHA256 = j.X509CertThumbprintS256
+	}
+
+	return joseJWK.MarshalJSON()
+}
+
+// ComputeX5TS256 sets X509CertThumbprintS256 to the SHA-256 thumbprint of the leaf certificate of the x5c chain.
+func (j *JWK) ComputeX5TS256() error {
+	if len(j.Certificates) == 0 {
+		return errors.New("computeX5TS256: jwk has no x5c certificate")
+	}
+
+	thumbprint := sha256.Sum256(j.Certificates[0].Raw)
+	j.X509CertThumbprintS256 = thumbprint[:]
+
+	return nil
+}
+
+// ValidateX5TS256 checks that X509CertThumbprintS256 matches the leaf certificate of the x5c chain. It returns nil if
+// either the x5c chain or the thumbprint is not set.
+func (j *JWK) ValidateX5TS256() error {
+	if len(j.Certificates) == 0 || len(j.X509CertThumbprintS256) == 0 {
+		return nil
+	}
+
+	thumbprint := sha256.Sum256(j.Certificates[0].Raw)
+	if !bytes.Equal(thumbprint[:], j.X509CertThumbprintS256) {
+		return errors.New("validateX5TS256: x5t#S256 does not match x5c leaf certificate")
+	}
+
+	return nil
+}
+
+// KeyTypeOpt is an option of JWK.KeyType.
+type KeyTypeOpt func(opts *keyTypeOpts)
+
+type keyTypeOpts struct {
+	der bool
 }
 
-// KeyType returns the kms KeyType of the JWK, or an error if the JWK is of an unrecognized type.
-func (j *JWK) KeyType() (kms.KeyType, error) {
+// WithDERFormat option makes JWK.KeyType return the DER signature format variant of EC key types (e.g.
+// kms.ECDSAP256TypeDER) instead of the IEEE P1363 one.
+func WithDERFormat() KeyTypeOpt {
+	return func(opts *keyTypeOpts) {
+		opts.der = true
+	}
+}
+
+// KeyType returns the kms KeyType of the JWK, or an error if the JWK is of an unrecognized type. The key type is
+// derived from the key and its kty, crv and alg members. As a JWK doesn't tell the signature format of EC keys, the
+// IEEE P1363 variant (e.g. kms.ECDSAP256TypeIEEEP1363 for a P-256 key) is returned unless the WithDERFormat option is
+// set. An EC JWK whose alg is not the one of its curve (e.g. ES384 with a P-256 key) is rejected. RSA keys are
+// returned as kms.RSAPS256Type.
+func (j *JWK) KeyType(opts ...KeyTypeOpt) (kms.KeyType, error) {
+	ktOpts := &keyTypeOpts{}
+
+	for _, opt := range opts {
+		opt(ktOpts)
+	}
+
 	switch key := j.Key.(type) {
 	case ed25519.PublicKey, ed25519.PrivateKey:
 		return kms.ED25519Type, nil
 	case *bbs12381g2pub.PublicKey, *bbs12381g2pub.PrivateKey:
 		return kms.BLS12381G2Type, nil
+	case *ml.G1:
+		return kms.BLS12381G1Type, nil
 	case *ecdsa.PublicKey:
-		return ecdsaPubKeyType(key)
+		return ecdsaKeyType(key, j.Algorithm, ktOpts.der)
 	case *ecdsa.PrivateKey:
-		return ecdsaPubKeyType(&(key.PublicKey))
+		return ecdsaKeyType(&(key.PublicKey), j.Algorithm, ktOpts.der)
 	case *rsa.PublicKey, *rsa.PrivateKey:
 		return kms.RSAPS256Type, nil
 	}
@@ -188,25 +426,251 @@ func (j *JWK) KeyType() (kms.KeyType, error) {
 	case isEd25519(j.Kty, j.Crv):
 		return kms.ED25519Type, nil
 	case isSecp256k1(j.Algorithm, j.Kty, j.Crv):
+		if ktOpts.der {
+			return kms.ECDSASecp256k1TypeDER, nil
+		}
+
 		return kms.ECDSASecp256k1TypeIEEEP1363, nil
+	case isAKP(j.Kty):
+		if ps, ok := mldsaParameterSetByAlg(j.Algorithm); ok {
+			return ps.keyType, nil
+		}
+
+		return "", fmt.Errorf("no keytype recognized for AKP jwk with alg '%s'", j.Algorithm)
 	default:
 		return "", fmt.Errorf("no keytype recognized for jwk")
 	}
 }
 
-func ecdsaPubKeyType(pub *ecdsa.PublicKey) (kms.KeyType, error) {
-	switch pub.Curve {
-	case btcec.S256():
-		return kms.ECDSASecp256k1TypeIEEEP1363, nil
-	case elliptic.P256():
-		return kms.ECDSAP256TypeIEEEP1363, nil
-	case elliptic.P384():
-		return kms.ECDSAP384TypeIEEEP1363, nil
-	case elliptic.P521():
-		return kms.ECDSAP521TypeIEEEP1363, nil
+// ecdsaKeyTypes are the IEEE P1363 and DER key types and the alg of the EC curves.
+var ecdsaKeyTypes = map[elliptic.Curve]struct { //nolint:gochecknoglobals
+	ieee, der kms.KeyType
+	alg       string
+}{
+	btcec.S256():    {ieee: kms.ECDSASecp256k1TypeIEEEP1363, der: kms.ECDSASecp256k1TypeDER, alg: secp256k1Alg},
+	elliptic.P256(): {ieee: kms.ECDSAP256TypeIEEEP1363, der: kms.ECDSAP256TypeDER, alg: "ES256"},
+	elliptic.P384(): {ieee: kms.ECDSAP384TypeIEEEP1363, der: kms.ECDSAP384TypeDER, alg: "ES384"},
+	elliptic.P521(): {ieee: kms.ECDSAP521TypeIEEEP1363, der: kms.ECDSAP521TypeDER, alg: "ES512"},
+}
+
+// ecdsaKeyType returns the key type of pub, checking alg, if it is a JWS algorithm, is the alg of its curve. Other
+// algs, like the ECDH-ES key agreement algorithms of encryption keys, don't depend on the curve and are not checked.
+func ecdsaKeyType(pub *ecdsa.PublicKey, alg string, der bool) (kms.KeyType, error) {
+	keyTypes, ok := ecdsaKeyTypes[pub.Curve]
+	if !ok {
+		return "", fmt.Errorf("no keytype recognized for ecdsa jwk")
+	}
+
+	if _, isJWSAlg := signatureAlgs[alg]; isJWSAlg && alg != keyTypes.alg {
+		return "", fmt.Errorf("alg '%s' does not match the ecdsa jwk curve %s, expected '%s'", alg,
+			pub.Curve.Params().Name, keyTypes.alg)
+	}
+
+	if der {
+		return keyTypes.der, nil
+	}
+
+	return keyTypes.ieee, nil
+}
+
+// Validate checks the JWK key satisfies the constraints of the declared key type kt. RSA key types require an RSA key
+// with a modulus of at least 2048 bits for RSARS256Type and RSAPS256Type, 3072 bits for RSA3072Type and 4096 bits for
+// RSA4096Type, or of the size set with WithMinRSAKeyBits if larger. Smaller keys return a JWKError wrapping
+// ErrWeakKey. Other key types have no constraints.
+func (j *JWK) Validate(kt kms.KeyType, opts ...ValidateOpt) error {
+	minBits, ok := rsaMinModulusBits(kt)
+	if !ok {
+		return nil
+	}
+
+	if floor := MinRSAKeyBits(opts...); floor > minBits {
+		minBits = floor
+	}
+
+	var pub *rsa.PublicKey
+
+	switch key := j.Key.(type) {
+	case *rsa.PublicKey:
+		pub = key
+	case *rsa.PrivateKey:
+		pub = &key.PublicKey
+	default:
+		return fmt.Errorf("validate: key type %s requires an RSA key, got %T", kt, j.Key)
+	}
+
+	if pub.N == nil || pub.N.BitLen() < minBits {
+		return &JWKError{
+			Field:  "n",
+			Reason: fmt.Sprintf("must be at least %d bits for key type %s, got %d", minBits, kt, bitLen(pub.N)),
+			Err: fmt.Errorf("validate: %w: RSA key size is below the %d bits required by key type %s", ErrWeakKey,
+				minBits, kt),
+		}
+	}
+
+	return nil
+}
+
+// checkKeyValues checks the modulus and exponent of RSA keys and the value of symmetric keys are set: go-jose reads
+// empty n, e and k members as zero values, giving a key that can't be marshalled back.
+func checkKeyValues(key interface{}) error {
+	var pub *rsa.PublicKey
+
+	switch k := key.(type) {
+	case *rsa.PublicKey:
+		pub = k
+	case *rsa.PrivateKey:
+		pub = &k.PublicKey
+	case []byte:
+		if len(k) == 0 {
+			return missingFieldError("k")
+		}
+
+		return nil
+	default:
+		return nil
+	}
+
+	if pub.N == nil || pub.N.Sign() == 0 {
+		return missingFieldError("n")
+	}
+
+	if pub.E == 0 {
+		return missingFieldError("e")
+	}
+
+	return nil
+}
+
+// rsaMinModulusBits returns the minimum RSA modulus size in bits of kt, ok is false if kt is not an RSA key type.
+func rsaMinModulusBits(kt kms.KeyType) (int, bool) {
+	switch kt {
+	case kms.RSARS256Type, kms.RSAPS256Type:
+		return 2048, true //nolint:gomnd
+	case kms.RSA3072Type:
+		return 3072, true //nolint:gomnd
+	case kms.RSA4096Type:
+		return 4096, true //nolint:gomnd
+	default:
+		return 0, false
+	}
+}
+
+func bitLen(n *big.Int) int {
+	if n == nil {
+		return 0
+	}
+
+	return n.BitLen()
+}
+
+// ecCurves maps the canonical JOSE names of the EC curves (RFC 7518 and RFC 8812) to their implementation.
+var ecCurves = map[string]elliptic.Curve{ //nolint:gochecknoglobals
+	"P-256":      elliptic.P256(),
+	"P-384":      elliptic.P384(),
+	"P-521":      elliptic.P521(),
+	secp256k1Crv: btcec.S256(),
+}
+
+// ecCurveAliases maps alternate EC curve names to their canonical JOSE names: the OpenSSL and SEC names of the NIST
+// curves and the secp256k1 names used by blockchain tooling (P-256K) and derived from COSE (P-256K1).
+var ecCurveAliases = map[string]string{ //nolint:gochecknoglobals
+	"prime256v1": "P-256",
+	"secp256r1":  "P-256",
+	"secp384r1":  "P-384",
+	"secp521r1":  "P-521",
+	"P-256K":     secp256k1Crv,
+	"P-256K1":    secp256k1Crv,
+}
+
+// CanonicalCurveName returns the canonical JOSE name of the EC curve crv if it is an alias, see ECCurve, or crv.
+func CanonicalCurveName(crv string) string {
+	if canonical, ok := ecCurveAliases[crv]; ok {
+		return canonical
+	}
+
+	return crv
+}
+
+// ECCurve returns the EC curve named crv, a canonical JOSE name (P-256, P-384, P-521 or secp256k1) or one of its
+// aliases: prime256v1 and secp256r1 for P-256, secp384r1 for P-384, secp521r1 for P-521 and P-256K and P-256K1 for
+// secp256k1. JWKs are always marshalled with the canonical name.
+func ECCurve(crv string) (elliptic.Curve, bool) {
+	curve, ok := ecCurves[CanonicalCurveName(crv)]
+
+	return curve, ok
+}
+
+// unmarshalExtra returns the non registered members of the JWK in jwkBytes, nil if there are none.
+func unmarshalExtra(jwkBytes []byte) (map[string]interface{}, error) {
+	var members map[string]json.RawMessage
+
+	err := json.Unmarshal(jwkBytes, &members)
+	if err != nil {
+		return nil, err
+	}
+
+	var extra map[string]interface{}
+
+	for name, rawValue := range members {
+		if _, ok := registeredMembers[name]; ok {
+			continue
+		}
+
+		var value interface{}
+
+		err = json.Unmarshal(rawValue, &value)
+		if err != nil {
+			return nil, fmt.Errorf("extra member '%s': %w", name, err)
+		}
+
+		if extra == nil {
+			extra = map[string]interface{}{}
+		}
+
+		extra[name] = value
+	}
+
+	return extra, nil
+}
+
+// marshalExtra adds the extra members to the JWK in jwkBytes.
+func marshalExtra(jwkBytes []byte, extra map[string]interface{}) ([]byte, error) {
+	var members map[string]json.RawMessage
+
+	err := json.Unmarshal(jwkBytes, &members)
+	if err != nil {
+		return nil, err
+	}
+
+	for name, value := range extra {
+		if _, ok := registeredMembers[name]; ok {
+			return nil, fmt.Errorf("extra member '%s' is a registered JWK parameter", name)
+		}
+
+		members[name], err = json.Marshal(value)
+		if err != nil {
+			return nil, fmt.Errorf("extra member '%s': %w", name, err)
+		}
+	}
+
+	return json.Marshal(members)
+}
+
+// replaceCurve replaces the crv member of the JWK in jwkBytes with crv.
+func replaceCurve(jwkBytes []byte, crv string) ([]byte, error) {
+	var members map[string]json.RawMessage
+
+	err := json.Unmarshal(jwkBytes, &members)
+	if err != nil {
+		return nil, err
+	}
+
+	members["crv"], err = json.Marshal(crv)
+	if err != nil {
+		return nil, err
 	}
 
-	return "", fmt.Errorf("no keytype recognized for ecdsa jwk")
+	return json.Marshal(members)
 }
 
 func (j *JWK) isX25519() bool {
@@ -227,6 +691,12 @@ func (j *JWK) isBLS12381G2() bool {
 	}
 }
 
+func (j *JWK) isBLS12381G1() bool {
+	_, ok := j.Key.(*ml.G1)
+
+	return ok
+}
+
 func (j *JWK) isSecp256k1() bool {
 	return isSecp256k1Key(j.Key) || isSecp256k1(j.Algorithm, j.Kty, j.Crv)
 }
@@ -254,39 +724,45 @@ func isBLS12381G2(kty, crv string) bool {
 	return strings.EqualFold(kty, ecKty) && strings.EqualFold(crv, bls12381G2Crv)
 }
 
+func isBLS12381G1(kty, crv string) bool {
+	return strings.EqualFold(kty, ecKty) && strings.EqualFold(crv, bls12381G1Crv)
+}
+
 func isSecp256k1(alg, kty, crv string) bool {
 	return strings.EqualFold(alg, secp256k1Alg) ||
-		(strings.EqualFold(kty, ecKty) && strings.EqualFold(crv, secp256k1Crv))
+		(strings.EqualFold(kty, ecKty) && strings.EqualFold(CanonicalCurveName(crv), secp256k1Crv))
 }
 
 func unmarshalSecp256k1(jwk *jsonWebKey) (*JWK, error) {
 	if jwk.X == nil {
-		return nil, ErrInvalidKey
+		return nil, missingFieldError("x")
 	}
 
 	if jwk.Y == nil {
-		return nil, ErrInvalidKey
+		return nil, missingFieldError("y")
 	}
 
 	curve := btcec.S256()
 
-	if curveSize(curve) != len(jwk.X.data) {
-		return nil, ErrInvalidKey
+	if err := checkFieldSize("x", jwk.X, curveSize(curve)); err != nil {
+		return nil, err
 	}
 
-	if curveSize(curve) != len(jwk.Y.data) {
-		return nil, ErrInvalidKey
+	if err := checkFieldSize("y", jwk.Y, curveSize(curve)); err != nil {
+		return nil, err
 	}
 
-	if jwk.D != nil && dSize(curve) != len(jwk.D.data) {
-		return nil, ErrInvalidKey
+	if jwk.D != nil {
+		if err := checkFieldSize("d", jwk.D, dSize(curve)); err != nil {
+			return nil, err
+		}
 	}
 
 	x := jwk.X.bigInt()
 	y := jwk.Y.bigInt()
 
 	if !curve.IsOnCurve(x, y) {
-		return nil, ErrInvalidKey
+		return nil, &JWKError{Field: "x", Reason: "point is not on curve secp256k1", Err: ErrInvalidKey}
 	}
 
 	var key interface{}
@@ -317,11 +793,11 @@ func unmarshalSecp256k1(jwk *jsonWebKey) (*JWK, error) {
 
 func unmarshalX25519(jwk *jsonWebKey) (*JWK, error) {
 	if jwk.X == nil {
-		return nil, ErrInvalidKey
+		return nil, missingFieldError("x")
 	}
 
-	if len(jwk.X.data) != cryptoutil.Curve25519KeySize {
-		return nil, ErrInvalidKey
+	if err := checkFieldSize("x", jwk.X, cryptoutil.Curve25519KeySize); err != nil {
+		return nil, err
 	}
 
 	return &JWK{
@@ -355,20 +831,26 @@ func marshalX25519(jwk *JWK) ([]byte, error) {
 	raw.Alg = jwk.Algorithm
 	raw.Use = jwk.Use
 
+	if len(jwk.X509CertThumbprintS256) > 0 {
+		raw.X5tS256 = &byteBuffer{data: jwk.X509CertThumbprintS256}
+	}
+
 	return json.Marshal(raw)
 }
 
 func unmarshalBLS12381G2(jwk *jsonWebKey) (*JWK, error) {
 	if jwk.X == nil {
-		return nil, ErrInvalidKey
+		return nil, missingFieldError("x")
 	}
 
-	if len(jwk.X.data) != bls12381G2Size {
-		return nil, ErrInvalidKey
+	if err := checkFieldSize("x", jwk.X, bls12381G2Size); err != nil {
+		return nil, err
 	}
 
-	if jwk.D != nil && blsComprPrivSz != len(jwk.D.data) {
-		return nil, ErrInvalidKey
+	if jwk.D != nil {
+		if err := checkFieldSize("d", jwk.D, blsComprPrivSz); err != nil {
+			return nil, err
+		}
 	}
 
 	var (
@@ -449,6 +931,65 @@ func marshalBLS12381G2(jwk *JWK) ([]byte, error) {
 	raw.Alg = jwk.Algorithm
 	raw.Use = jwk.Use
 
+	if len(jwk.X509CertThumbprintS256) > 0 {
+		raw.X5tS256 = &byteBuffer{data: jwk.X509CertThumbprintS256}
+	}
+
+	return json.Marshal(raw)
+}
+
+func unmarshalBLS12381G1(jwk *jsonWebKey) (*JWK, error) {
+	if jwk.X == nil {
+		return nil, missingFieldError("x")
+	}
+
+	if jwk.D != nil {
+		return nil, &JWKError{Field: "d", Reason: "private keys are not supported", Err: ErrInvalidKey}
+	}
+
+	if err := checkFieldSize("x", jwk.X, bls12381G1Size); err != nil {
+		return nil, err
+	}
+
+	key, err := ml.Curves[ml.BLS12_381_BBS].NewG1FromCompressed(jwk.X.data)
+	if err != nil {
+		return nil, fmt.Errorf("jwk invalid public key unmarshal: %w", err)
+	}
+
+	return &JWK{
+		JSONWebKey: jose.JSONWebKey{
+			Key: key, KeyID: jwk.Kid, Algorithm: jwk.Alg, Use: jwk.Use,
+		},
+		Crv: jwk.Crv,
+		Kty: jwk.Kty,
+	}, nil
+}
+
+func marshalBLS12381G1(jwk *JWK) ([]byte, error) {
+	key, ok := jwk.Key.(*ml.G1)
+	if !ok {
+		return nil, errors.New("marshalBLS12381G1: invalid key")
+	}
+
+	mKey := key.Compressed()
+	if len(mKey) != bls12381G1Size {
+		return nil, errors.New("marshal BLS12381G1 public key: invalid key")
+	}
+
+	raw := jsonWebKey{
+		Kty: ecKty,
+		Crv: bls12381G1Crv,
+		X:   newFixedSizeBuffer(mKey, bls12381G1Size),
+	}
+
+	raw.Kid = jwk.KeyID
+	raw.Alg = jwk.Algorithm
+	raw.Use = jwk.Use
+
+	if len(jwk.X509CertThumbprintS256) > 0 {
+		raw.X5tS256 = &byteBuffer{data: jwk.X509CertThumbprintS256}
+	}
+
 	return json.Marshal(raw)
 }
 
@@ -478,6 +1019,10 @@ func marshalSecp256k1(jwk *JWK) ([]byte, error) {
 	raw.Alg = jwk.Algorithm
 	raw.Use = jwk.Use
 
+	if len(jwk.X509CertThumbprintS256) > 0 {
+		raw.X5tS256 = &byteBuffer{data: jwk.X509CertThumbprintS256}
+	}
+
 	return json.Marshal(raw)
 }
 
@@ -493,6 +1038,11 @@ type jsonWebKey struct {
 	Y *byteBuffer `json:"y,omitempty"`
 
 	D *byteBuffer `json:"d,omitempty"`
+
+	Pub  *byteBuffer `json:"pub,omitempty"`
+	Priv *byteBuffer `json:"priv,omitempty"`
+
+	X5tS256 *byteBuffer `json:"x5t#S256,omitempty"`
 }
 
 // Get size of curve in bytes.
@@ -572,3 +1122,43 @@ func newFixedSizeBuffer(data []byte, length int) *byteBuffer {
 
 // ErrInvalidKey is returned when passed JWK is invalid.
 var ErrInvalidKey = errors.New("invalid JWK")
+
+// JWKError describes why a JWK member failed to decode or validate. Field is the JSON name of the offending member
+// and Reason a human readable description (e.g. "must be 32 bytes, got 31"), allowing callers to report the failure
+// without parsing error strings. Error() returns the text of the wrapped error, ErrInvalidKey for most failures, and
+// errors.Is/errors.As see through it.
+type JWKError struct {
+	Field  string
+	Reason string
+	Err    error
+}
+
+// Error returns the wrapped error text or, when no error is wrapped, a description of the invalid field.
+func (e *JWKError) Error() string {
+	if e.Err != nil {
+		return e.Err.Error()
+	}
+
+	return fmt.Sprintf("invalid JWK field '%s': %s", e.Field, e.Reason)
+}
+
+// Unwrap returns the wrapped error.
+func (e *JWKError) Unwrap() error {
+	return e.Err
+}
+
+func missingFieldError(field string) *JWKError {
+	return &JWKError{Field: field, Reason: "is required", Err: ErrInvalidKey}
+}
+
+func checkFieldSize(field string, value *byteBuffer, size int) error {
+	if len(value.data) != size {
+		return &JWKError{
+			Field:  field,
+			Reason: fmt.Sprintf("must be %d bytes, got %d", size, len(value.data)),
+			Err:    ErrInvalidKey,
+		}
+	}
+
+	return nil
+}
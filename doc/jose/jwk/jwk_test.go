@@ -7,12 +7,21 @@ SPDX-License-Identifier: Apache-2.0
 package jwk
 
 import (
+	"bytes"
 	"crypto/ecdsa"
+	"crypto/ed25519"
 	"crypto/elliptic"
 	"crypto/rand"
+	"crypto/rsa"
 	"crypto/sha256"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/base64"
 	"fmt"
+	"math/big"
+	"strings"
 	"testing"
+	"time"
 
 	"github.com/btcsuite/btcd/btcec/v2"
 	"github.com/go-jose/go-jose/v3"
@@ -161,6 +170,17 @@ func TestDecodePublicKey(t *testing.T) {
 						}`,
 				err: "unable to read JWK",
 			},
+			{
+				name: "invalid BLS12381_G1 size",
+				jwkJSON: `{
+    						"kty": "EC",
+    						"use": "enc",
+    						"crv": "BLS12381_G1",
+    						"x": "wQehEGTVCu32yp8IwTaBCqPUIYslyd-WoFRsfDKE9II",
+    						"kid": "sample@sample.id"
+						}`,
+				err: "unable to read BLS12381_G1 JWK: invalid JWK",
+			},
 			{
 				name: "invalid X25519",
 				jwkJSON: `{
@@ -397,6 +417,16 @@ func TestJWK_KeyType(t *testing.T) {
 				}`,
 				keyType: kms.BLS12381G2Type,
 			},
+			{
+				jwk: `{
+					"kty": "EC",
+					"use": "enc",
+					"crv": "BLS12381_G1",
+					"kid": "sample@sample.id",
+					"x": "iwXn5IpOxG9Wi1CB-lntAnCiFe93GESJhU9KNOoSFxXsgGcXDGNOfTMSiY7hJS6R"
+				}`,
+				keyType: kms.BLS12381G1Type,
+			},
 			{
 				jwk: `{
 					"kty": "EC",
@@ -532,13 +562,637 @@ func TestJWK_KeyType(t *testing.T) {
 		require.Equal(t, kms.ECDSASecp256k1TypeIEEEP1363, kt)
 	})
 
+	t.Run("ecdsa keytype format and alg", func(t *testing.T) {
+		for _, tc := range []struct {
+			curve elliptic.Curve
+			alg   string
+			ieee  kms.KeyType
+			der   kms.KeyType
+		}{
+			{curve: elliptic.P256(), alg: "ES256", ieee: kms.ECDSAP256TypeIEEEP1363, der: kms.ECDSAP256TypeDER},
+			{curve: elliptic.P384(), alg: "ES384", ieee: kms.ECDSAP384TypeIEEEP1363, der: kms.ECDSAP384TypeDER},
+			{curve: elliptic.P521(), alg: "ES512", ieee: kms.ECDSAP521TypeIEEEP1363, der: kms.ECDSAP521TypeDER},
+			{curve: btcec.S256(), alg: "ES256K", ieee: kms.ECDSASecp256k1TypeIEEEP1363, der: kms.ECDSASecp256k1TypeDER},
+		} {
+			eckey, err := ecdsa.GenerateKey(tc.curve, rand.Reader)
+			require.NoError(t, err)
+
+			j := &JWK{JSONWebKey: jose.JSONWebKey{Key: &eckey.PublicKey, Algorithm: tc.alg}}
+
+			kt, err := j.KeyType()
+			require.NoError(t, err)
+			require.Equal(t, tc.ieee, kt)
+
+			kt, err = j.KeyType(WithDERFormat())
+			require.NoError(t, err)
+			require.Equal(t, tc.der, kt)
+
+			j.Algorithm = ""
+
+			kt, err = j.KeyType(WithDERFormat())
+			require.NoError(t, err)
+			require.Equal(t, tc.der, kt)
+		}
+
+		eckey, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+		require.NoError(t, err)
+
+		_, err = (&JWK{JSONWebKey: jose.JSONWebKey{Key: eckey, Algorithm: "ES384"}}).KeyType()
+		require.EqualError(t, err, "alg 'ES384' does not match the ecdsa jwk curve P-256, expected 'ES256'")
+
+		_, err = (&JWK{JSONWebKey: jose.JSONWebKey{Key: eckey, Algorithm: "EdDSA"}}).KeyType()
+		require.EqualError(t, err, "alg 'EdDSA' does not match the ecdsa jwk curve P-256, expected 'ES256'")
+
+		for _, alg := range []string{"ECDH-ES", "ECDH-ES+A256KW"} {
+			ecdhJWK := &JWK{}
+			require.NoError(t, ecdhJWK.UnmarshalJSON([]byte(`{"kty":"EC","crv":"P-256","alg":"`+alg+`",`+
+				`"x":"f83OJ3D2xF1Bg8vub9tLe1gHMzV76e8Tus9uPHvRVEU","y":"x_FEzRu9m36HLN_tue659LNpXW6pCyStikYjKIWI5a0"}`)))
+
+			kt, err := ecdhJWK.KeyType()
+			require.NoError(t, err, alg)
+			require.Equal(t, kms.ECDSAP256TypeIEEEP1363, kt, alg)
+		}
+
+		kt, err := (&JWK{Kty: "EC", Crv: "secp256k1", JSONWebKey: jose.JSONWebKey{Key: []byte{}}}).KeyType(
+			WithDERFormat())
+		require.NoError(t, err)
+		require.Equal(t, kms.ECDSASecp256k1TypeDER, kt)
+	})
+
 	t.Run("fail to get ecdsa keytype for (unsupported) p-224", func(t *testing.T) {
 		eckey, err := ecdsa.GenerateKey(elliptic.P224(), rand.Reader)
 		require.NoError(t, err)
 
-		kt, err := ecdsaPubKeyType(&eckey.PublicKey)
+		kt, err := ecdsaKeyType(&eckey.PublicKey, "", false)
 		require.Error(t, err)
 		require.Contains(t, err.Error(), "no keytype recognized for ecdsa jwk")
 		require.Equal(t, kms.KeyType(""), kt)
 	})
 }
+
+func TestJWK_X5TS256(t *testing.T) {
+	privKey, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+
+	template := &x509.Certificate{
+		SerialNumber: big.NewInt(1),
+		Subject:      pkix.Name{CommonName: "test"},
+		NotBefore:    time.Now().Add(-time.Hour),
+		NotAfter:     time.Now().Add(time.Hour),
+	}
+
+	certDER, err := x509.CreateCertificate(rand.Reader, template, template, &privKey.PublicKey, privKey)
+	require.NoError(t, err)
+
+	cert, err := x509.ParseCertificate(certDER)
+	require.NoError(t, err)
+
+	thumbprint := sha256.Sum256(cert.Raw)
+
+	t.Run("compute, marshal and unmarshal x5t#S256", func(t *testing.T) {
+		key := &JWK{
+			JSONWebKey: jose.JSONWebKey{
+				Key:          &privKey.PublicKey,
+				Certificates: []*x509.Certificate{cert},
+			},
+		}
+
+		require.NoError(t, key.ComputeX5TS256())
+		require.Equal(t, thumbprint[:], key.X509CertThumbprintS256)
+		require.NoError(t, key.ValidateX5TS256())
+
+		mJWK, err := key.MarshalJSON()
+		require.NoError(t, err)
+		require.Contains(t, string(mJWK), `"x5t#S256":"`+base64.RawURLEncoding.EncodeToString(thumbprint[:])+`"`)
+
+		parsed := &JWK{}
+		require.NoError(t, parsed.UnmarshalJSON(mJWK))
+		require.Equal(t, thumbprint[:], parsed.X509CertThumbprintS256)
+		require.NoError(t, parsed.ValidateX5TS256())
+	})
+
+	t.Run("marshal and unmarshal x5t#S256 for secp256k1 key", func(t *testing.T) {
+		secpKey, err := ecdsa.GenerateKey(btcec.S256(), rand.Reader)
+		require.NoError(t, err)
+
+		key := &JWK{
+			JSONWebKey: jose.JSONWebKey{
+				Key: &secpKey.PublicKey,
+			},
+			X509CertThumbprintS256: thumbprint[:],
+		}
+
+		mJWK, err := key.MarshalJSON()
+		require.NoError(t, err)
+		require.Contains(t, string(mJWK), `"x5t#S256"`)
+
+		parsed := &JWK{}
+		require.NoError(t, parsed.UnmarshalJSON(mJWK))
+		require.Equal(t, thumbprint[:], parsed.X509CertThumbprintS256)
+	})
+
+	t.Run("validate mismatching x5t#S256", func(t *testing.T) {
+		key := &JWK{
+			JSONWebKey: jose.JSONWebKey{
+				Key:          &privKey.PublicKey,
+				Certificates: []*x509.Certificate{cert},
+			},
+			X509CertThumbprintS256: make([]byte, sha256.Size),
+		}
+
+		require.EqualError(t, key.ValidateX5TS256(), "validateX5TS256: x5t#S256 does not match x5c leaf certificate")
+
+		key.Certificates = nil
+		require.NoError(t, key.ValidateX5TS256())
+	})
+
+	t.Run("compute x5t#S256 without x5c", func(t *testing.T) {
+		key := &JWK{JSONWebKey: jose.JSONWebKey{Key: &privKey.PublicKey}}
+
+		require.EqualError(t, key.ComputeX5TS256(), "computeX5TS256: jwk has no x5c certificate")
+		require.Nil(t, key.X509CertThumbprintS256)
+	})
+}
+
+func TestJWK_Public(t *testing.T) {
+	ecKey, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+
+	secp256k1Key, err := ecdsa.GenerateKey(btcec.S256(), rand.Reader)
+	require.NoError(t, err)
+
+	rsaKey, err := rsa.GenerateKey(rand.Reader, 2048)
+	require.NoError(t, err)
+
+	edPub, edPriv, err := ed25519.GenerateKey(rand.Reader)
+	require.NoError(t, err)
+
+	bbsPub, bbsPriv, err := bbs12381g2pub.GenerateKeyPair(sha256.New, nil)
+	require.NoError(t, err)
+
+	x25519Key := make([]byte, 32)
+	_, err = rand.Read(x25519Key)
+	require.NoError(t, err)
+
+	tests := []struct {
+		name   string
+		jwk    *JWK
+		pubKey interface{}
+	}{
+		{
+			name:   "P-256 private key",
+			jwk:    &JWK{JSONWebKey: jose.JSONWebKey{Key: ecKey, Algorithm: "ES256"}, Kty: ecKty, Crv: "P-256"},
+			pubKey: &ecKey.PublicKey,
+		},
+		{
+			name: "secp256k1 private key",
+			jwk: &JWK{
+				JSONWebKey: jose.JSONWebKey{Key: secp256k1Key, Algorithm: secp256k1Alg},
+				Kty:        ecKty,
+				Crv:        secp256k1Crv,
+			},
+			pubKey: &secp256k1Key.PublicKey,
+		},
+		{
+			name:   "RSA private key",
+			jwk:    &JWK{JSONWebKey: jose.JSONWebKey{Key: rsaKey, Algorithm: "PS256"}, Kty: "RSA"},
+			pubKey: &rsaKey.PublicKey,
+		},
+		{
+			name:   "Ed25519 private key",
+			jwk:    &JWK{JSONWebKey: jose.JSONWebKey{Key: edPriv, Algorithm: "EdDSA"}, Kty: okpKty, Crv: ed25519Crv},
+			pubKey: edPub,
+		},
+		{
+			name:   "BBS+ private key",
+			jwk:    &JWK{JSONWebKey: jose.JSONWebKey{Key: bbsPriv}, Kty: ecKty, Crv: bls12381G2Crv},
+			pubKey: bbsPub,
+		},
+		{
+			name:   "Ed25519 public key",
+			jwk:    &JWK{JSONWebKey: jose.JSONWebKey{Key: edPub, Algorithm: "EdDSA"}, Kty: okpKty, Crv: ed25519Crv},
+			pubKey: edPub,
+		},
+		{
+			name:   "X25519 public key",
+			jwk:    &JWK{JSONWebKey: jose.JSONWebKey{Key: x25519Key}, Kty: okpKty, Crv: x25519Crv},
+			pubKey: x25519Key,
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			tc.jwk.KeyID = "key-1"
+			tc.jwk.Use = "sig"
+			privKey := tc.jwk.Key
+
+			pub := tc.jwk.Public()
+			require.Equal(t, "key-1", pub.KeyID)
+			require.Equal(t, "sig", pub.Use)
+			require.Equal(t, tc.jwk.Algorithm, pub.Algorithm)
+			require.Equal(t, tc.jwk.Kty, pub.Kty)
+			require.Equal(t, tc.jwk.Crv, pub.Crv)
+
+			expectedBytes, err := (&JWK{JSONWebKey: jose.JSONWebKey{Key: tc.pubKey}, Kty: tc.jwk.Kty,
+				Crv: tc.jwk.Crv}).PublicKeyBytes()
+			require.NoError(t, err)
+
+			pubBytes, err := pub.PublicKeyBytes()
+			require.NoError(t, err)
+			require.Equal(t, expectedBytes, pubBytes)
+
+			// the original JWK is not modified.
+			require.Equal(t, privKey, tc.jwk.Key)
+
+			jwkJSON, err := pub.MarshalJSON()
+			require.NoError(t, err)
+
+			var fields map[string]interface{}
+
+			require.NoError(t, json.Unmarshal(jwkJSON, &fields))
+
+			for _, private := range []string{"d", "p", "q", "dp", "dq", "qi"} {
+				require.NotContains(t, fields, private)
+			}
+
+			require.Equal(t, "key-1", fields["kid"])
+		})
+	}
+}
+
+func TestJWK_OpenSSLCurveAliases(t *testing.T) {
+	t.Run("decode prime256v1 JWK", func(t *testing.T) {
+		// RFC 7517 appendix A.1 EC public key, labelled with its OpenSSL curve name.
+		const prime256v1JWK = `{
+			"kty": "EC",
+			"crv": "prime256v1",
+			"x": "MKBCTNIcKUSDii11ySs3526iDZ8AiTo7Tu6KPAqv7D4",
+			"y": "4Etl6SRW2YiLUrN5vfvVHuhp7x8PxltmWWlbbM4IFyM",
+			"use": "enc",
+			"kid": "1"
+		}`
+
+		j := &JWK{}
+		require.NoError(t, j.UnmarshalJSON([]byte(prime256v1JWK)))
+		require.Equal(t, "P-256", j.Crv)
+		require.Equal(t, "EC", j.Kty)
+		require.Equal(t, "1", j.KeyID)
+		require.Equal(t, "enc", j.Use)
+
+		ecKey, ok := j.Key.(*ecdsa.PublicKey)
+		require.True(t, ok)
+		require.Equal(t, elliptic.P256(), ecKey.Curve)
+
+		jwkBytes, err := j.MarshalJSON()
+		require.NoError(t, err)
+		require.Contains(t, string(jwkBytes), `"crv":"P-256"`)
+		require.NotContains(t, string(jwkBytes), "prime256v1")
+	})
+
+	tests := []struct {
+		alias string
+		curve elliptic.Curve
+	}{
+		{alias: "secp256r1", curve: elliptic.P256()},
+		{alias: "secp384r1", curve: elliptic.P384()},
+		{alias: "secp521r1", curve: elliptic.P521()},
+	}
+
+	for _, tc := range tests {
+		t.Run("decode "+tc.alias+" JWK", func(t *testing.T) {
+			privKey, err := ecdsa.GenerateKey(tc.curve, rand.Reader)
+			require.NoError(t, err)
+
+			jwkBytes, err := (&JWK{JSONWebKey: jose.JSONWebKey{Key: privKey}}).MarshalJSON()
+			require.NoError(t, err)
+
+			aliasBytes := bytes.Replace(jwkBytes, []byte(tc.curve.Params().Name), []byte(tc.alias), 1)
+			require.Contains(t, string(aliasBytes), tc.alias)
+
+			j := &JWK{}
+			require.NoError(t, j.UnmarshalJSON(aliasBytes))
+			require.Equal(t, tc.curve.Params().Name, j.Crv)
+			require.Equal(t, privKey, j.Key)
+
+			canonicalBytes, err := j.MarshalJSON()
+			require.NoError(t, err)
+			require.JSONEq(t, string(jwkBytes), string(canonicalBytes))
+		})
+	}
+
+	t.Run("alias is only accepted for EC keys", func(t *testing.T) {
+		j := &JWK{}
+		require.Error(t, j.UnmarshalJSON([]byte(`{"kty":"OKP","crv":"prime256v1","x":"AA"}`)))
+	})
+}
+
+func TestJWK_Secp256k1CurveAliases(t *testing.T) {
+	privKey, err := ecdsa.GenerateKey(btcec.S256(), rand.Reader)
+	require.NoError(t, err)
+
+	for _, alg := range []string{"", secp256k1Alg} {
+		jwkBytes, err := (&JWK{JSONWebKey: jose.JSONWebKey{Key: privKey, Algorithm: alg}}).MarshalJSON()
+		require.NoError(t, err)
+		require.Contains(t, string(jwkBytes), `"crv":"secp256k1"`)
+
+		for _, alias := range []string{"P-256K", "P-256K1"} {
+			t.Run("decode "+alias+" JWK with alg '"+alg+"'", func(t *testing.T) {
+				aliasBytes := bytes.Replace(jwkBytes, []byte(`"secp256k1"`), []byte(`"`+alias+`"`), 1)
+
+				j := &JWK{}
+				require.NoError(t, j.UnmarshalJSON(aliasBytes))
+				require.Equal(t, secp256k1Crv, j.Crv)
+				require.Equal(t, privKey, j.Key)
+
+				kt, err := j.KeyType()
+				require.NoError(t, err)
+				require.Equal(t, kms.ECDSASecp256k1TypeIEEEP1363, kt)
+
+				canonicalBytes, err := j.MarshalJSON()
+				require.NoError(t, err)
+				require.JSONEq(t, string(jwkBytes), string(canonicalBytes))
+			})
+		}
+	}
+
+	t.Run("alias set on a JWK", func(t *testing.T) {
+		j := &JWK{JSONWebKey: jose.JSONWebKey{Key: []byte("key")}, Kty: ecKty, Crv: "P-256K"}
+		require.True(t, j.isSecp256k1())
+	})
+}
+
+func TestECCurve(t *testing.T) {
+	tests := []struct {
+		crv       string
+		canonical string
+		curve     elliptic.Curve
+	}{
+		{crv: "P-256", canonical: "P-256", curve: elliptic.P256()},
+		{crv: "prime256v1", canonical: "P-256", curve: elliptic.P256()},
+		{crv: "secp256r1", canonical: "P-256", curve: elliptic.P256()},
+		{crv: "P-384", canonical: "P-384", curve: elliptic.P384()},
+		{crv: "secp384r1", canonical: "P-384", curve: elliptic.P384()},
+		{crv: "P-521", canonical: "P-521", curve: elliptic.P521()},
+		{crv: "secp521r1", canonical: "P-521", curve: elliptic.P521()},
+		{crv: "secp256k1", canonical: "secp256k1", curve: btcec.S256()},
+		{crv: "P-256K", canonical: "secp256k1", curve: btcec.S256()},
+		{crv: "P-256K1", canonical: "secp256k1", curve: btcec.S256()},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.crv, func(t *testing.T) {
+			require.Equal(t, tc.canonical, CanonicalCurveName(tc.crv))
+
+			curve, ok := ECCurve(tc.crv)
+			require.True(t, ok)
+			require.Equal(t, tc.curve, curve)
+		})
+	}
+
+	t.Run("unknown curve", func(t *testing.T) {
+		require.Equal(t, "P-224", CanonicalCurveName("P-224"))
+
+		curve, ok := ECCurve("P-224")
+		require.False(t, ok)
+		require.Nil(t, curve)
+	})
+}
+
+func TestJWK_Extra(t *testing.T) {
+	const ecJWKWithExtra = `{
+		"kty": "EC",
+		"crv": "P-256",
+		"x": "MKBCTNIcKUSDii11ySs3526iDZ8AiTo7Tu6KPAqv7D4",
+		"y": "4Etl6SRW2YiLUrN5vfvVHuhp7x8PxltmWWlbbM4IFyM",
+		"kid": "1",
+		"notBefore": 1700000000,
+		"notAfter": "2030-01-01T00:00:00Z",
+		"rotation": {"next": "2"}
+	}`
+
+	const x25519JWKWithExtra = `{
+		"kty": "OKP",
+		"crv": "X25519",
+		"x": "hSDwCYkwp1R0i33ctD73Wg2_Og0mOBr066SpjqqbTmo",
+		"notBefore": 1700000000
+	}`
+
+	t.Run("extra members round-trip", func(t *testing.T) {
+		for _, jwkJSON := range []string{ecJWKWithExtra, x25519JWKWithExtra} {
+			j := &JWK{}
+			require.NoError(t, j.UnmarshalJSON([]byte(jwkJSON)))
+
+			notBefore, ok := j.GetExtra("notBefore")
+			require.True(t, ok)
+			require.EqualValues(t, 1700000000, notBefore)
+
+			_, ok = j.GetExtra("kty")
+			require.False(t, ok)
+
+			jwkBytes, err := j.MarshalJSON()
+			require.NoError(t, err)
+			require.JSONEq(t, jwkJSON, string(jwkBytes))
+
+			require.Equal(t, j.Extra, j.Public().Extra)
+		}
+	})
+
+	t.Run("set extra members", func(t *testing.T) {
+		j := &JWK{}
+		require.NoError(t, j.UnmarshalJSON([]byte(`{"kty":"oct","k":"AyM1SysPpbyDfgZld3umj1qzKObwVMkoqQ-EstJQLr_T-1qS"}`)))
+		require.Nil(t, j.Extra)
+
+		_, ok := j.GetExtra("notAfter")
+		require.False(t, ok)
+
+		j.SetExtra("notAfter", 1800000000)
+
+		notAfter, ok := j.GetExtra("notAfter")
+		require.True(t, ok)
+		require.Equal(t, 1800000000, notAfter)
+
+		jwkBytes, err := j.MarshalJSON()
+		require.NoError(t, err)
+		require.Contains(t, string(jwkBytes), `"notAfter":1800000000`)
+
+		decoded := &JWK{}
+		require.NoError(t, decoded.UnmarshalJSON(jwkBytes))
+		require.EqualValues(t, 1800000000, decoded.Extra["notAfter"])
+	})
+
+	t.Run("error registered member set as extra", func(t *testing.T) {
+		j := &JWK{}
+		require.NoError(t, j.UnmarshalJSON([]byte(ecJWKWithExtra)))
+
+		j.SetExtra("kid", "2")
+
+		_, err := j.MarshalJSON()
+		require.EqualError(t, err, "extra member 'kid' is a registered JWK parameter")
+	})
+
+	t.Run("error extra member not marshallable", func(t *testing.T) {
+		j := &JWK{}
+		require.NoError(t, j.UnmarshalJSON([]byte(ecJWKWithExtra)))
+
+		j.SetExtra("invalid", make(chan int))
+
+		_, err := j.MarshalJSON()
+		require.ErrorContains(t, err, "extra member 'invalid'")
+	})
+}
+
+func TestJWKError(t *testing.T) {
+	x := base64.RawURLEncoding.EncodeToString(make([]byte, 31))
+	y := base64.RawURLEncoding.EncodeToString(make([]byte, 32))
+
+	tests := []struct {
+		name    string
+		jwkJSON string
+		field   string
+		reason  string
+	}{
+		{
+			name:    "X25519 key of invalid size",
+			jwkJSON: fmt.Sprintf(`{"kty":"OKP","crv":"X25519","x":"%s"}`, x),
+			field:   "x",
+			reason:  "must be 32 bytes, got 31",
+		},
+		{
+			name:    "secp256k1 key without y",
+			jwkJSON: fmt.Sprintf(`{"kty":"EC","crv":"secp256k1","x":"%s"}`, y),
+			field:   "y",
+			reason:  "is required",
+		},
+		{
+			name:    "secp256k1 key of invalid size",
+			jwkJSON: fmt.Sprintf(`{"kty":"EC","crv":"secp256k1","x":"%s","y":"%s"}`, x, y),
+			field:   "x",
+			reason:  "must be 32 bytes, got 31",
+		},
+		{
+			name:    "secp256k1 point not on curve",
+			jwkJSON: fmt.Sprintf(`{"kty":"EC","crv":"secp256k1","x":"%s","y":"%s"}`, y, y),
+			field:   "x",
+			reason:  "point is not on curve secp256k1",
+		},
+		{
+			name:    "BLS12381_G1 key with private key",
+			jwkJSON: fmt.Sprintf(`{"kty":"EC","crv":"BLS12381_G1","x":"%s","d":"%s"}`, y, y),
+			field:   "d",
+			reason:  "private keys are not supported",
+		},
+		{
+			name:    "BLS12381_G2 key of invalid size",
+			jwkJSON: fmt.Sprintf(`{"kty":"EC","crv":"BLS12381_G2","x":"%s"}`, x),
+			field:   "x",
+			reason:  "must be 96 bytes, got 31",
+		},
+		{
+			name:    "RSA key with empty modulus",
+			jwkJSON: `{"kty":"RSA","n":"","e":"AQAB"}`,
+			field:   "n",
+			reason:  "is required",
+		},
+		{
+			name:    "RSA key with empty exponent",
+			jwkJSON: `{"kty":"RSA","n":"AQAB","e":""}`,
+			field:   "e",
+			reason:  "is required",
+		},
+		{
+			name:    "oct key with empty value",
+			jwkJSON: `{"kty":"oct","k":""}`,
+			field:   "k",
+			reason:  "is required",
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			var j JWK
+
+			err := json.Unmarshal([]byte(tc.jwkJSON), &j)
+			require.ErrorIs(t, err, ErrInvalidKey)
+			require.True(t, strings.HasSuffix(err.Error(), ": invalid JWK"))
+
+			var jwkErr *JWKError
+			require.ErrorAs(t, err, &jwkErr)
+			require.Equal(t, tc.field, jwkErr.Field)
+			require.Equal(t, tc.reason, jwkErr.Reason)
+		})
+	}
+
+	t.Run("error text without wrapped error", func(t *testing.T) {
+		err := &JWKError{Field: "x", Reason: "must be 32 bytes, got 31"}
+		require.EqualError(t, err, "invalid JWK field 'x': must be 32 bytes, got 31")
+		require.NoError(t, err.Unwrap())
+	})
+}
+
+func TestJWK_Validate(t *testing.T) {
+	rsaKey, err := rsa.GenerateKey(rand.Reader, 2048)
+	require.NoError(t, err)
+
+	t.Run("success", func(t *testing.T) {
+		j := &JWK{JSONWebKey: jose.JSONWebKey{Key: rsaKey}}
+		require.NoError(t, j.Validate(kms.RSAPS256Type))
+		require.NoError(t, j.Validate(kms.RSARS256Type))
+		require.NoError(t, j.Public().Validate(kms.RSAPS256Type))
+
+		// non RSA key types have no size constraint.
+		require.NoError(t, (&JWK{JSONWebKey: jose.JSONWebKey{Key: ed25519.PublicKey{}}}).Validate(kms.ED25519Type))
+	})
+
+	t.Run("error RSA key below the size of the key type", func(t *testing.T) {
+		j := &JWK{JSONWebKey: jose.JSONWebKey{Key: &rsaKey.PublicKey}}
+
+		for _, tc := range []struct {
+			kt   kms.KeyType
+			bits int
+		}{
+			{kt: kms.RSA3072Type, bits: 3072},
+			{kt: kms.RSA4096Type, bits: 4096},
+		} {
+			err = j.Validate(tc.kt)
+			require.EqualError(t, err, fmt.Sprintf(
+				"validate: weak key: RSA key size is below the %d bits required by key type %s", tc.bits, tc.kt))
+
+			var jwkErr *JWKError
+			require.ErrorAs(t, err, &jwkErr)
+			require.Equal(t, "n", jwkErr.Field)
+			require.Equal(t, fmt.Sprintf("must be at least %d bits for key type %s, got 2048", tc.bits, tc.kt),
+				jwkErr.Reason)
+		}
+	})
+
+	t.Run("minimum RSA key size option", func(t *testing.T) {
+		j := &JWK{JSONWebKey: jose.JSONWebKey{Key: &rsaKey.PublicKey}}
+
+		err = j.Validate(kms.RSAPS256Type, WithMinRSAKeyBits(3072))
+		require.ErrorIs(t, err, ErrWeakKey)
+		require.EqualError(t, err, "validate: weak key: RSA key size is below the 3072 bits required by key type "+
+			"RSAPS256")
+
+		// the option can't lower the size required by the key type.
+		require.NoError(t, j.Validate(kms.RSAPS256Type, WithMinRSAKeyBits(1024)))
+		require.ErrorIs(t, j.Validate(kms.RSA3072Type, WithMinRSAKeyBits(1024)), ErrWeakKey)
+
+		require.Equal(t, DefaultMinRSAKeyBits, MinRSAKeyBits())
+		require.Equal(t, 3072, MinRSAKeyBits(WithMinRSAKeyBits(3072)))
+
+		require.NoError(t, CheckRSAKeySize(&rsaKey.PublicKey, 2048))
+
+		err = CheckRSAKeySize(&rsaKey.PublicKey, 3072)
+		require.ErrorIs(t, err, ErrWeakKey)
+		require.EqualError(t, err, "weak key: RSA key size 2048 bits is below the 3072 bits minimum")
+	})
+
+	t.Run("error not an RSA key", func(t *testing.T) {
+		ecKey, e := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+		require.NoError(t, e)
+
+		j := &JWK{JSONWebKey: jose.JSONWebKey{Key: &ecKey.PublicKey}}
+		require.EqualError(t, j.Validate(kms.RSA3072Type),
+			"validate: key type RSA3072 requires an RSA key, got *ecdsa.PublicKey")
+	})
+}
@@ -0,0 +1,48 @@
+/*
+Copyright Gen Digital Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package jwk
+
+// JWKSet is a JWK Set (https://tools.ietf.org/html/rfc7517#section-5), as published by JWKS endpoints.
+type JWKSet struct {
+	Keys []*JWK `json:"keys"`
+}
+
+// Dedupe removes the keys holding the same public key as a previous key of the set, compared with SamePublicKey
+// regardless of their kid and other members, and returns the number of keys removed. The first occurrence of each key
+// is kept, in its original order. Nil keys are removed too.
+func (s *JWKSet) Dedupe() int {
+	kept := s.Keys[:0]
+
+	for _, key := range s.Keys {
+		if key == nil || containsPublicKey(kept, key) {
+			continue
+		}
+
+		kept = append(kept, key)
+	}
+
+	removed := len(s.Keys) - len(kept)
+
+	// clear the tail so the removed keys can be garbage collected.
+	for i := len(kept); i < len(s.Keys); i++ {
+		s.Keys[i] = nil
+	}
+
+	s.Keys = kept
+
+	return removed
+}
+
+func containsPublicKey(keys []*JWK, key *JWK) bool {
+	for _, k := range keys {
+		if k.SamePublicKey(key) {
+			return true
+		}
+	}
+
+	return false
+}
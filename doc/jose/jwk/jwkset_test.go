@@ -0,0 +1,50 @@
+/*
+Copyright Gen Digital Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package jwk
+
+import (
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/elliptic"
+	"crypto/rand"
+	"encoding/json"
+	"testing"
+
+	"github.com/go-jose/go-jose/v3"
+	"github.com/stretchr/testify/require"
+)
+
+func TestJWKSet_Dedupe(t *testing.T) {
+	ecKey, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+
+	edPub, edPriv, err := ed25519.GenerateKey(rand.Reader)
+	require.NoError(t, err)
+
+	ec1 := &JWK{JSONWebKey: jose.JSONWebKey{Key: &ecKey.PublicKey, KeyID: "ec-1"}, Kty: "EC", Crv: "P-256"}
+	ec2 := &JWK{JSONWebKey: jose.JSONWebKey{Key: ecKey, KeyID: "ec-2"}, Kty: "EC", Crv: "P-256"}
+	ed1 := &JWK{JSONWebKey: jose.JSONWebKey{Key: edPub, KeyID: "ed-1"}, Kty: "OKP", Crv: "Ed25519"}
+	ed2 := &JWK{JSONWebKey: jose.JSONWebKey{Key: edPriv, KeyID: "ed-2", Use: "sig"}, Kty: "OKP", Crv: "Ed25519"}
+
+	set := &JWKSet{Keys: []*JWK{ec1, ed1, ec2, nil, ed2, ec1}}
+
+	require.Equal(t, 4, set.Dedupe())
+	require.Equal(t, []*JWK{ec1, ed1}, set.Keys)
+
+	require.Zero(t, set.Dedupe())
+	require.Zero(t, (&JWKSet{}).Dedupe())
+
+	setBytes, err := json.Marshal(set)
+	require.NoError(t, err)
+
+	var parsed JWKSet
+
+	require.NoError(t, json.Unmarshal(setBytes, &parsed))
+	require.Len(t, parsed.Keys, 2)
+	require.Equal(t, "ec-1", parsed.Keys[0].KeyID)
+	require.True(t, parsed.Keys[1].SamePublicKey(ed1))
+}
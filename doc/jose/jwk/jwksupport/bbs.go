@@ -0,0 +1,69 @@
+/*
+Copyright Gen Digital Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package jwksupport
+
+import (
+	"encoding/binary"
+	"fmt"
+
+	"github.com/dellekappa/kms-go/util/cryptoutil"
+)
+
+// bbsMessageLengthSize is the size of the big-endian length prefix of each message framed by BBSMessagesToBytes.
+const bbsMessageLengthSize = 4
+
+// BBSMessagesToBytes frames an ordered list of BBS messages into a single byte slice by prefixing each message with
+// its 4 bytes big-endian length, so message boundaries are unambiguous (unlike naive concatenation where e.g.
+// ["ab", "c"] and ["a", "bc"] collide). BBSBytesToMessages reverses the framing.
+//
+// The framed bytes are meant for storing or transporting the messages only: BBS signs each message separately, so
+// the messages passed to Crypto's SignMulti(), VerifyMulti(), DeriveProof() and VerifyProof() must be the split
+// list returned by BBSBytesToMessages, in the same order, and DeriveProof()'s revealedIndexes refer to positions
+// in that list.
+func BBSMessagesToBytes(msgs [][]byte) []byte {
+	size := 0
+
+	for _, msg := range msgs {
+		size += bbsMessageLengthSize + len(msg)
+	}
+
+	framed := make([]byte, 0, size)
+
+	for _, msg := range msgs {
+		framed = append(framed, cryptoutil.LengthPrefix(msg)...)
+	}
+
+	return framed
+}
+
+// BBSBytesToMessages splits bytes framed by BBSMessagesToBytes back into the ordered list of BBS messages. It fails
+// if a length prefix is truncated or announces more bytes than are left.
+func BBSBytesToMessages(framed []byte) ([][]byte, error) {
+	var msgs [][]byte
+
+	for offset := 0; offset < len(framed); {
+		if len(framed)-offset < bbsMessageLengthSize {
+			return nil, fmt.Errorf("bbsBytesToMessages: truncated length prefix of message %d", len(msgs))
+		}
+
+		msgLen := binary.BigEndian.Uint32(framed[offset:])
+		offset += bbsMessageLengthSize
+
+		if uint64(msgLen) > uint64(len(framed)-offset) {
+			return nil, fmt.Errorf("bbsBytesToMessages: message %d length %d exceeds the %d remaining bytes",
+				len(msgs), msgLen, len(framed)-offset)
+		}
+
+		msg := make([]byte, msgLen)
+		copy(msg, framed[offset:])
+		offset += int(msgLen)
+
+		msgs = append(msgs, msg)
+	}
+
+	return msgs, nil
+}
@@ -0,0 +1,88 @@
+/*
+Copyright Gen Digital Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package jwksupport
+
+import (
+	"crypto/sha256"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"github.com/trustbloc/bbs-signature-go/bbs12381g2pub"
+)
+
+func TestBBSMessagesFraming(t *testing.T) {
+	t.Run("round trip", func(t *testing.T) {
+		tests := []struct {
+			name string
+			msgs [][]byte
+		}{
+			{name: "single message", msgs: [][]byte{[]byte("message1")}},
+			{name: "multiple messages", msgs: [][]byte{[]byte("message1"), []byte("message2"), []byte("message3")}},
+			{name: "empty messages", msgs: [][]byte{{}, []byte("message2"), {}}},
+		}
+
+		for _, tc := range tests {
+			t.Run(tc.name, func(t *testing.T) {
+				msgs, err := BBSBytesToMessages(BBSMessagesToBytes(tc.msgs))
+				require.NoError(t, err)
+				require.Equal(t, tc.msgs, msgs)
+			})
+		}
+	})
+
+	t.Run("no messages", func(t *testing.T) {
+		require.Empty(t, BBSMessagesToBytes(nil))
+
+		msgs, err := BBSBytesToMessages(nil)
+		require.NoError(t, err)
+		require.Empty(t, msgs)
+	})
+
+	t.Run("message boundaries are unambiguous", func(t *testing.T) {
+		require.NotEqual(t,
+			BBSMessagesToBytes([][]byte{[]byte("ab"), []byte("c")}),
+			BBSMessagesToBytes([][]byte{[]byte("a"), []byte("bc")}))
+	})
+
+	t.Run("invalid framing", func(t *testing.T) {
+		framed := BBSMessagesToBytes([][]byte{[]byte("message1"), []byte("message2")})
+
+		_, err := BBSBytesToMessages(framed[:len(framed)-1])
+		require.EqualError(t, err, "bbsBytesToMessages: message 1 length 8 exceeds the 7 remaining bytes")
+
+		_, err = BBSBytesToMessages(append(framed, 0, 0))
+		require.EqualError(t, err, "bbsBytesToMessages: truncated length prefix of message 2")
+
+		_, err = BBSBytesToMessages([]byte{0xff, 0xff, 0xff, 0xff})
+		require.EqualError(t, err, "bbsBytesToMessages: message 0 length 4294967295 exceeds the 0 remaining bytes")
+	})
+
+	t.Run("sign and derive proof over split messages", func(t *testing.T) {
+		pubKey, privKey, err := bbs12381g2pub.GenerateKeyPair(sha256.New, nil)
+		require.NoError(t, err)
+
+		pubKeyBytes, err := pubKey.Marshal()
+		require.NoError(t, err)
+
+		framed := BBSMessagesToBytes([][]byte{[]byte("message1"), []byte("message2"), []byte("message3")})
+
+		msgs, err := BBSBytesToMessages(framed)
+		require.NoError(t, err)
+
+		bbs := bbs12381g2pub.New()
+
+		sig, err := bbs.SignWithKey(msgs, privKey)
+		require.NoError(t, err)
+
+		nonce := []byte("nonce")
+
+		proof, err := bbs.DeriveProof(msgs, sig, nonce, pubKeyBytes, []int{0, 2})
+		require.NoError(t, err)
+
+		require.NoError(t, bbs.VerifyProof([][]byte{msgs[0], msgs[2]}, proof, nonce, pubKeyBytes))
+	})
+}
@@ -0,0 +1,349 @@
+/*
+Copyright Gen Digital Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package jwksupport
+
+import (
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"math"
+	"sort"
+	"unicode/utf8"
+)
+
+// CBOR (RFC 8949) major types.
+const (
+	cborUnsigned byte = iota
+	cborNegative
+	cborBytes
+	cborText
+	cborArray
+	cborMap
+	cborTag
+	cborSimple
+)
+
+const (
+	cborInfoUint8    = 24
+	cborInfoUint16   = 25
+	cborInfoUint32   = 26
+	cborInfoUint64   = 27
+	cborSimpleFalse  = 20
+	cborSimpleTrue   = 21
+	cborMajorTypeBit = 5
+
+	// coseKeyMaxSize bounds the size of decoded COSE_Keys, it is well above the size of a 16384 bits RSA public key.
+	coseKeyMaxSize = 8192
+	// coseKeyMaxParams bounds the number of parameters of decoded COSE_Keys and of their key_ops.
+	coseKeyMaxParams = 16
+)
+
+// encodeCBORIntMap encodes m as a CBOR map with integer keys, sorted as required by the core deterministic encoding
+// (RFC 8949 section 4.2.1). Values must be int64, []byte, string or bool.
+func encodeCBORIntMap(m map[int64]interface{}) ([]byte, error) {
+	type entry struct {
+		key   []byte
+		value []byte
+	}
+
+	entries := make([]entry, 0, len(m))
+
+	for k, v := range m {
+		var value []byte
+
+		switch val := v.(type) {
+		case int64:
+			value = appendCBORInt(nil, val)
+		case []byte:
+			value = append(appendCBORHead(nil, cborBytes, uint64(len(val))), val...)
+		case string:
+			value = append(appendCBORHead(nil, cborText, uint64(len(val))), val...)
+		case bool:
+			value = []byte{cborSimple<<cborMajorTypeBit | cborSimpleFalse}
+			if val {
+				value[0] = cborSimple<<cborMajorTypeBit | cborSimpleTrue
+			}
+		default:
+			return nil, fmt.Errorf("unsupported CBOR value type %T", v)
+		}
+
+		entries = append(entries, entry{key: appendCBORInt(nil, k), value: value})
+	}
+
+	sort.Slice(entries, func(i, j int) bool {
+		return bytes.Compare(entries[i].key, entries[j].key) < 0
+	})
+
+	encoded := appendCBORHead(nil, cborMap, uint64(len(entries)))
+
+	for _, e := range entries {
+		encoded = append(encoded, e.key...)
+		encoded = append(encoded, e.value...)
+	}
+
+	return encoded, nil
+}
+
+func appendCBORInt(b []byte, v int64) []byte {
+	if v < 0 {
+		return appendCBORHead(b, cborNegative, uint64(-(v + 1)))
+	}
+
+	return appendCBORHead(b, cborUnsigned, uint64(v))
+}
+
+// appendCBORHead appends the shortest encoding of the head of a data item of major type and argument arg.
+func appendCBORHead(b []byte, major byte, arg uint64) []byte {
+	major <<= cborMajorTypeBit
+
+	switch {
+	case arg < cborInfoUint8:
+		return append(b, major|byte(arg))
+	case arg <= math.MaxUint8:
+		return append(b, major|cborInfoUint8, byte(arg))
+	case arg <= math.MaxUint16:
+		return binary.BigEndian.AppendUint16(append(b, major|cborInfoUint16), uint16(arg))
+	case arg <= math.MaxUint32:
+		return binary.BigEndian.AppendUint32(append(b, major|cborInfoUint32), uint32(arg))
+	default:
+		return binary.BigEndian.AppendUint64(append(b, major|cborInfoUint64), arg)
+	}
+}
+
+// decodeCOSEKeyMap decodes data, a CBOR encoded COSE_Key, into its parameters by label. It is a strict decoder for
+// COSE_Key maps only: data must be a single definite length map of at most coseKeyMaxParams integer labels, without
+// duplicates. Values must be integers, byte strings, text strings or booleans, and key_ops an array of integers and
+// text strings. Integers and lengths must be in their shortest form, as in the core deterministic encoding (RFC 8949
+// section 4.2.1). Tags, floats, nested maps and any other data item are rejected.
+func decodeCOSEKeyMap(data []byte) (map[int64]interface{}, error) {
+	if len(data) > coseKeyMaxSize {
+		return nil, fmt.Errorf("COSE_Key exceeds %d bytes", coseKeyMaxSize)
+	}
+
+	d := &cborDecoder{data: data}
+
+	major, n, err := d.head()
+	if err != nil {
+		return nil, err
+	}
+
+	if major != cborMap {
+		return nil, errors.New("CBOR data is not a map")
+	}
+
+	if n > coseKeyMaxParams {
+		return nil, fmt.Errorf("COSE_Key has more than %d parameters", coseKeyMaxParams)
+	}
+
+	m := make(map[int64]interface{}, n)
+
+	for i := uint64(0); i < n; i++ {
+		label, err := d.integer()
+		if err != nil {
+			return nil, fmt.Errorf("COSE_Key label: %w", err)
+		}
+
+		if _, ok := m[label]; ok {
+			return nil, fmt.Errorf("duplicate CBOR map key %d", label)
+		}
+
+		var value interface{}
+
+		if label == coseKeyKeyOps {
+			value, err = d.keyOps()
+		} else {
+			value, err = d.value()
+		}
+
+		if err != nil {
+			return nil, err
+		}
+
+		m[label] = value
+	}
+
+	if d.remaining() > 0 {
+		return nil, errors.New("unexpected data after CBOR map")
+	}
+
+	return m, nil
+}
+
+type cborDecoder struct {
+	data []byte
+	pos  int
+}
+
+func (d *cborDecoder) remaining() int {
+	return len(d.data) - d.pos
+}
+
+func (d *cborDecoder) next(n uint64) ([]byte, error) {
+	if n > uint64(d.remaining()) {
+		return nil, errors.New("unexpected end of CBOR data")
+	}
+
+	b := d.data[d.pos : d.pos+int(n)]
+	d.pos += int(n)
+
+	return b, nil
+}
+
+// head decodes the head of the next data item, returning its major type and argument. The argument must be encoded
+// in its shortest form.
+func (d *cborDecoder) head() (byte, uint64, error) {
+	b, err := d.next(1)
+	if err != nil {
+		return 0, 0, err
+	}
+
+	major, info := b[0]>>cborMajorTypeBit, b[0]&0x1f
+
+	var size, minArg uint64
+
+	switch info {
+	case cborInfoUint8:
+		size, minArg = 1, cborInfoUint8
+	case cborInfoUint16:
+		size, minArg = 2, math.MaxUint8+1
+	case cborInfoUint32:
+		size, minArg = 4, math.MaxUint16+1
+	case cborInfoUint64:
+		size, minArg = 8, math.MaxUint32+1
+	default:
+		if info > cborInfoUint64 {
+			return 0, 0, errors.New("unsupported CBOR indefinite length or reserved item")
+		}
+
+		return major, uint64(info), nil
+	}
+
+	argBytes, err := d.next(size)
+	if err != nil {
+		return 0, 0, err
+	}
+
+	var arg uint64
+
+	for _, v := range argBytes {
+		arg = arg<<8 | uint64(v)
+	}
+
+	if arg < minArg {
+		return 0, 0, errors.New("CBOR argument is not in its shortest form")
+	}
+
+	return major, arg, nil
+}
+
+// integer decodes the next data item, which must be an integer.
+func (d *cborDecoder) integer() (int64, error) {
+	v, err := d.value()
+	if err != nil {
+		return 0, err
+	}
+
+	i, ok := v.(int64)
+	if !ok {
+		return 0, errors.New("CBOR item is not an integer")
+	}
+
+	return i, nil
+}
+
+// value decodes the next data item, an integer as int64, a byte string as []byte, a text string as string or a
+// boolean as bool.
+func (d *cborDecoder) value() (interface{}, error) {
+	if d.remaining() > 0 && d.data[d.pos]>>cborMajorTypeBit == cborSimple {
+		// false and true are encoded in the initial byte, floats and other simple values are not supported.
+		switch d.data[d.pos] & 0x1f {
+		case cborSimpleFalse:
+			d.pos++
+
+			return false, nil
+		case cborSimpleTrue:
+			d.pos++
+
+			return true, nil
+		default:
+			return nil, errors.New("unsupported CBOR float or simple value")
+		}
+	}
+
+	major, arg, err := d.head()
+	if err != nil {
+		return nil, err
+	}
+
+	switch major {
+	case cborUnsigned, cborNegative:
+		if arg > math.MaxInt64 {
+			return nil, errors.New("CBOR integer overflows int64")
+		}
+
+		if major == cborNegative {
+			return -1 - int64(arg), nil
+		}
+
+		return int64(arg), nil
+	case cborBytes:
+		b, err := d.next(arg)
+		if err != nil {
+			return nil, err
+		}
+
+		return append([]byte{}, b...), nil
+	case cborText:
+		b, err := d.next(arg)
+		if err != nil {
+			return nil, err
+		}
+
+		if !utf8.Valid(b) {
+			return nil, errors.New("CBOR text string is not valid UTF-8")
+		}
+
+		return string(b), nil
+	default:
+		return nil, fmt.Errorf("unsupported CBOR major type %d", major)
+	}
+}
+
+// keyOps decodes the next data item, a key_ops array of integers and text strings.
+func (d *cborDecoder) keyOps() ([]interface{}, error) {
+	major, n, err := d.head()
+	if err != nil {
+		return nil, err
+	}
+
+	if major != cborArray {
+		return nil, errors.New("COSE_Key key_ops is not an array")
+	}
+
+	if n > coseKeyMaxParams {
+		return nil, fmt.Errorf("COSE_Key key_ops has more than %d operations", coseKeyMaxParams)
+	}
+
+	ops := make([]interface{}, 0, n)
+
+	for i := uint64(0); i < n; i++ {
+		op, err := d.value()
+		if err != nil {
+			return nil, err
+		}
+
+		switch op.(type) {
+		case int64, string:
+			ops = append(ops, op)
+		default:
+			return nil, errors.New("COSE_Key key_ops must be integers or text strings")
+		}
+	}
+
+	return ops, nil
+}
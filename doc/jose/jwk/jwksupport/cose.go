@@ -0,0 +1,312 @@
+/*
+Copyright Gen Digital Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package jwksupport
+
+import (
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/elliptic"
+	"crypto/rsa"
+	"errors"
+	"fmt"
+	"math"
+	"math/big"
+
+	"github.com/btcsuite/btcd/btcec/v2"
+
+	"github.com/dellekappa/kms-go/doc/jose/jwk"
+)
+
+// COSE_Key labels (RFC 9052 section 7.1 and RFC 9053 section 7, RFC 8230 section 4 for RSA).
+const (
+	coseKeyKty    = 1
+	coseKeyKid    = 2
+	coseKeyAlg    = 3
+	coseKeyKeyOps = 4
+
+	coseKeyCrv = -1
+	coseKeyX   = -2
+	coseKeyY   = -3
+	coseKeyD   = -4
+
+	coseKeyN = -1
+	coseKeyE = -2
+	// coseKeyRSAD is the first RSA private key label, the private exponent, followed by p, q, dP, dQ and qInv.
+	coseKeyRSAD    = -3
+	coseKeyRSAQInv = -8
+)
+
+// COSE key types.
+const (
+	coseKtyOKP = 1
+	coseKtyEC2 = 2
+	coseKtyRSA = 3
+)
+
+// COSE elliptic curves (RFC 9053 section 7.1, RFC 8812 section 3.1 for secp256k1).
+const (
+	coseCrvP256      = 1
+	coseCrvP384      = 2
+	coseCrvP521      = 3
+	coseCrvX25519    = 4
+	coseCrvEd25519   = 6
+	coseCrvSecp256k1 = 8
+)
+
+const x25519KeySize = 32
+
+// coseAlgs maps the JWA signature algorithms to their COSE algorithm identifiers.
+var coseAlgs = map[string]int64{ //nolint:gochecknoglobals
+	"ES256":  -7,
+	"EdDSA":  -8,
+	"ES384":  -35,
+	"ES512":  -36,
+	"PS256":  -37,
+	"PS384":  -38,
+	"PS512":  -39,
+	"ES256K": -47,
+	"RS256":  -257,
+	"RS384":  -258,
+	"RS512":  -259,
+}
+
+// ToCOSEKey encodes the public key of j as a CBOR encoded COSE_Key (RFC 9052 section 7), as found e.g. in WebAuthn
+// attestations. EC (P-256, P-384, P-521 and secp256k1) keys are encoded as EC2 keys with uncompressed coordinates,
+// Ed25519 and X25519 keys as OKP keys and RSA keys as RSA keys (RFC 8230). The JWK kid is encoded as the COSE kid
+// byte string and its alg, if set, as the matching COSE algorithm. Private key members are never encoded.
+func ToCOSEKey(j *jwk.JWK) ([]byte, error) {
+	if j == nil {
+		return nil, errors.New("toCOSEKey: jwk is empty")
+	}
+
+	var (
+		coseKey map[int64]interface{}
+		err     error
+	)
+
+	switch key := j.Public().Key.(type) {
+	case *ecdsa.PublicKey:
+		coseKey, err = ec2COSEKey(key)
+	case ed25519.PublicKey:
+		coseKey = map[int64]interface{}{coseKeyKty: int64(coseKtyOKP), coseKeyCrv: int64(coseCrvEd25519),
+			coseKeyX: []byte(key)}
+	case []byte:
+		if j.Crv != x25519Crv || len(key) != x25519KeySize {
+			return nil, errors.New("toCOSEKey: unsupported raw key, only X25519 keys are supported")
+		}
+
+		coseKey = map[int64]interface{}{coseKeyKty: int64(coseKtyOKP), coseKeyCrv: int64(coseCrvX25519),
+			coseKeyX: key}
+	case *rsa.PublicKey:
+		coseKey = map[int64]interface{}{coseKeyKty: int64(coseKtyRSA), coseKeyN: key.N.Bytes(),
+			coseKeyE: big.NewInt(int64(key.E)).Bytes()}
+	default:
+		return nil, fmt.Errorf("toCOSEKey: unsupported key type %T", j.Key)
+	}
+
+	if err != nil {
+		return nil, fmt.Errorf("toCOSEKey: %w", err)
+	}
+
+	if j.KeyID != "" {
+		coseKey[coseKeyKid] = []byte(j.KeyID)
+	}
+
+	if j.Algorithm != "" {
+		alg, ok := coseAlgs[j.Algorithm]
+		if !ok {
+			return nil, fmt.Errorf("toCOSEKey: unsupported alg '%s'", j.Algorithm)
+		}
+
+		coseKey[coseKeyAlg] = alg
+	}
+
+	encoded, err := encodeCBORIntMap(coseKey)
+	if err != nil {
+		return nil, fmt.Errorf("toCOSEKey: %w", err)
+	}
+
+	return encoded, nil
+}
+
+func ec2COSEKey(key *ecdsa.PublicKey) (map[int64]interface{}, error) {
+	var crv int64
+
+	switch {
+	case key.Curve == elliptic.P256():
+		crv = coseCrvP256
+	case key.Curve == elliptic.P384():
+		crv = coseCrvP384
+	case key.Curve == elliptic.P521():
+		crv = coseCrvP521
+	case key.Curve.Params().Name == secp256k1Crv || key.Curve == btcec.S256():
+		crv = coseCrvSecp256k1
+	default:
+		return nil, fmt.Errorf("unsupported curve '%s'", key.Curve.Params().Name)
+	}
+
+	size := (key.Curve.Params().BitSize + 7) / 8 //nolint:gomnd
+
+	return map[int64]interface{}{
+		coseKeyKty: int64(coseKtyEC2),
+		coseKeyCrv: crv,
+		coseKeyX:   key.X.FillBytes(make([]byte, size)),
+		coseKeyY:   key.Y.FillBytes(make([]byte, size)),
+	}, nil
+}
+
+// FromCOSEKey decodes a CBOR encoded COSE_Key into a public JWK, see ToCOSEKey for the supported keys. The COSE kid
+// is set as the JWK kid and the COSE alg, if present, as the JWK alg. Private COSE keys, compressed EC2 points and
+// unknown algorithms are rejected.
+func FromCOSEKey(cbor []byte) (*jwk.JWK, error) {
+	coseKey, err := decodeCOSEKeyMap(cbor)
+	if err != nil {
+		return nil, fmt.Errorf("fromCOSEKey: invalid COSE_Key: %w", err)
+	}
+
+	kty, ok := coseKey[coseKeyKty].(int64)
+	if !ok {
+		return nil, errors.New("fromCOSEKey: missing or invalid kty")
+	}
+
+	var key *jwk.JWK
+
+	switch kty {
+	case coseKtyEC2:
+		key, err = jwkFromEC2COSEKey(coseKey)
+	case coseKtyOKP:
+		key, err = jwkFromOKPCOSEKey(coseKey)
+	case coseKtyRSA:
+		key, err = jwkFromRSACOSEKey(coseKey)
+	default:
+		err = fmt.Errorf("unsupported kty %d", kty)
+	}
+
+	if err != nil {
+		return nil, fmt.Errorf("fromCOSEKey: %w", err)
+	}
+
+	key.Algorithm = ""
+
+	if rawAlg, ok := coseKey[coseKeyAlg]; ok {
+		key.Algorithm, err = jwaFromCOSEAlg(rawAlg)
+		if err != nil {
+			return nil, fmt.Errorf("fromCOSEKey: %w", err)
+		}
+	}
+
+	if rawKID, ok := coseKey[coseKeyKid]; ok {
+		kid, ok := rawKID.([]byte)
+		if !ok {
+			return nil, errors.New("fromCOSEKey: invalid kid")
+		}
+
+		key.KeyID = string(kid)
+	}
+
+	return key, nil
+}
+
+func jwkFromEC2COSEKey(coseKey map[int64]interface{}) (*jwk.JWK, error) {
+	if _, ok := coseKey[coseKeyD]; ok {
+		return nil, errors.New("private COSE keys are not supported")
+	}
+
+	var curve elliptic.Curve
+
+	switch crv, _ := coseKey[coseKeyCrv].(int64); crv {
+	case coseCrvP256:
+		curve = elliptic.P256()
+	case coseCrvP384:
+		curve = elliptic.P384()
+	case coseCrvP521:
+		curve = elliptic.P521()
+	case coseCrvSecp256k1:
+		curve = btcec.S256()
+	default:
+		return nil, fmt.Errorf("unsupported EC2 curve %v", coseKey[coseKeyCrv])
+	}
+
+	if _, ok := coseKey[coseKeyY].(bool); ok {
+		return nil, errors.New("compressed EC2 points are not supported")
+	}
+
+	x, okX := coseKey[coseKeyX].([]byte)
+	y, okY := coseKey[coseKeyY].([]byte)
+
+	size := (curve.Params().BitSize + 7) / 8 //nolint:gomnd
+	if !okX || !okY || len(x) != size || len(y) != size {
+		return nil, fmt.Errorf("EC2 coordinates must be %d bytes byte strings", size)
+	}
+
+	return JWKFromECCoordinates(curve, new(big.Int).SetBytes(x), new(big.Int).SetBytes(y))
+}
+
+func jwkFromOKPCOSEKey(coseKey map[int64]interface{}) (*jwk.JWK, error) {
+	if _, ok := coseKey[coseKeyD]; ok {
+		return nil, errors.New("private COSE keys are not supported")
+	}
+
+	x, ok := coseKey[coseKeyX].([]byte)
+	if !ok {
+		return nil, errors.New("missing or invalid OKP x")
+	}
+
+	switch crv, _ := coseKey[coseKeyCrv].(int64); crv {
+	case coseCrvEd25519:
+		if len(x) != ed25519.PublicKeySize {
+			return nil, fmt.Errorf("Ed25519 x must be %d bytes", ed25519.PublicKeySize)
+		}
+
+		return JWKFromKey(ed25519.PublicKey(x))
+	case coseCrvX25519:
+		if len(x) != x25519KeySize {
+			return nil, fmt.Errorf("X25519 x must be %d bytes", x25519KeySize)
+		}
+
+		return JWKFromX25519Key(x)
+	default:
+		return nil, fmt.Errorf("unsupported OKP curve %v", coseKey[coseKeyCrv])
+	}
+}
+
+func jwkFromRSACOSEKey(coseKey map[int64]interface{}) (*jwk.JWK, error) {
+	for label := int64(coseKeyRSAD); label >= coseKeyRSAQInv; label-- {
+		if _, ok := coseKey[label]; ok {
+			return nil, errors.New("private COSE keys are not supported")
+		}
+	}
+
+	n, okN := coseKey[coseKeyN].([]byte)
+	e, okE := coseKey[coseKeyE].([]byte)
+
+	if !okN || !okE || len(n) == 0 || len(e) == 0 {
+		return nil, errors.New("missing or invalid RSA n or e")
+	}
+
+	exponent := new(big.Int).SetBytes(e)
+	if !exponent.IsInt64() || exponent.Int64() > math.MaxInt32 {
+		return nil, errors.New("RSA exponent is too large")
+	}
+
+	return JWKFromKey(&rsa.PublicKey{N: new(big.Int).SetBytes(n), E: int(exponent.Int64())})
+}
+
+func jwaFromCOSEAlg(rawAlg interface{}) (string, error) {
+	coseAlg, ok := rawAlg.(int64)
+	if !ok {
+		return "", fmt.Errorf("unsupported alg %v", rawAlg)
+	}
+
+	for jwa, alg := range coseAlgs {
+		if alg == coseAlg {
+			return jwa, nil
+		}
+	}
+
+	return "", fmt.Errorf("unsupported alg %d", coseAlg)
+}
@@ -0,0 +1,236 @@
+/*
+Copyright Gen Digital Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package jwksupport
+
+import (
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/rsa"
+	"encoding/hex"
+	"testing"
+
+	"github.com/btcsuite/btcd/btcec/v2"
+	"github.com/go-jose/go-jose/v3"
+	"github.com/stretchr/testify/require"
+
+	"github.com/dellekappa/kms-go/doc/jose/jwk"
+)
+
+func TestCOSEKey(t *testing.T) {
+	t.Run("RFC 9052 C.7.1 P-256 key", func(t *testing.T) {
+		coseKey, err := hex.DecodeString("a501020242313120012158" +
+			"20bac5b11cad8f99f9c72b05cf4b9e26d244dc189f745228255a219a86d6a09eff2258" +
+			"2020138bf82dc1b6d562be0fa54ab7804a3a64b6d72ccfed6b6fb6ed28bbfc117e")
+		require.NoError(t, err)
+
+		key, err := FromCOSEKey(coseKey)
+		require.NoError(t, err)
+		require.Equal(t, "11", key.KeyID)
+		require.Equal(t, "EC", key.Kty)
+		require.Equal(t, "P-256", key.Crv)
+		require.Empty(t, key.Algorithm)
+
+		pub, ok := key.Key.(*ecdsa.PublicKey)
+		require.True(t, ok)
+		require.Equal(t, "bac5b11cad8f99f9c72b05cf4b9e26d244dc189f745228255a219a86d6a09eff",
+			hex.EncodeToString(pub.X.Bytes()))
+
+		encoded, err := ToCOSEKey(key)
+		require.NoError(t, err)
+		require.Equal(t, coseKey, encoded)
+	})
+
+	t.Run("Ed25519 deterministic encoding", func(t *testing.T) {
+		pub, _, err := ed25519.GenerateKey(rand.Reader)
+		require.NoError(t, err)
+
+		key, err := JWKFromKey(pub)
+		require.NoError(t, err)
+
+		key.Algorithm = "EdDSA"
+
+		encoded, err := ToCOSEKey(key)
+		require.NoError(t, err)
+		// {1: 1, 3: -8, -1: 6, -2: x}
+		require.Equal(t, append([]byte{0xa4, 0x01, 0x01, 0x03, 0x27, 0x20, 0x06, 0x21, 0x58, 0x20}, pub...), encoded)
+	})
+
+	t.Run("round trip", func(t *testing.T) {
+		p384Key, err := ecdsa.GenerateKey(elliptic.P384(), rand.Reader)
+		require.NoError(t, err)
+
+		p521Key, err := ecdsa.GenerateKey(elliptic.P521(), rand.Reader)
+		require.NoError(t, err)
+
+		secp256k1Key, err := ecdsa.GenerateKey(btcec.S256(), rand.Reader)
+		require.NoError(t, err)
+
+		edPub, edPriv, err := ed25519.GenerateKey(rand.Reader)
+		require.NoError(t, err)
+
+		rsaKey, err := rsa.GenerateKey(rand.Reader, 2048)
+		require.NoError(t, err)
+
+		x25519JWK, err := JWKFromX25519Key(edPub)
+		require.NoError(t, err)
+
+		for name, tc := range map[string]struct {
+			key interface{}
+			alg string
+		}{
+			"P-384":           {key: &p384Key.PublicKey, alg: "ES384"},
+			"P-521 private":   {key: p521Key, alg: "ES512"},
+			"secp256k1":       {key: &secp256k1Key.PublicKey, alg: "ES256K"},
+			"Ed25519":         {key: edPub},
+			"Ed25519 private": {key: edPriv, alg: "EdDSA"},
+			"RSA":             {key: &rsaKey.PublicKey, alg: "PS256"},
+			"RSA private":     {key: rsaKey, alg: "RS256"},
+		} {
+			t.Run(name, func(t *testing.T) {
+				key, err := JWKFromKey(tc.key)
+				require.NoError(t, err)
+
+				key.KeyID = "kid-" + name
+				key.Algorithm = tc.alg
+
+				encoded, err := ToCOSEKey(key)
+				require.NoError(t, err)
+
+				decoded, err := FromCOSEKey(encoded)
+				require.NoError(t, err)
+				require.True(t, key.SamePublicKey(decoded))
+				require.Equal(t, key.KeyID, decoded.KeyID)
+				require.Equal(t, tc.alg, decoded.Algorithm)
+				require.Equal(t, key.Public().Kty, decoded.Kty)
+			})
+		}
+
+		t.Run("X25519", func(t *testing.T) {
+			encoded, err := ToCOSEKey(x25519JWK)
+			require.NoError(t, err)
+
+			decoded, err := FromCOSEKey(encoded)
+			require.NoError(t, err)
+			require.Equal(t, "X25519", decoded.Crv)
+			require.Equal(t, []byte(edPub), decoded.Key)
+		})
+	})
+
+	t.Run("ToCOSEKey errors", func(t *testing.T) {
+		_, err := ToCOSEKey(nil)
+		require.EqualError(t, err, "toCOSEKey: jwk is empty")
+
+		p224Key, err := ecdsa.GenerateKey(elliptic.P224(), rand.Reader)
+		require.NoError(t, err)
+
+		_, err = ToCOSEKey(&jwk.JWK{JSONWebKey: jose.JSONWebKey{Key: &p224Key.PublicKey}})
+		require.EqualError(t, err, "toCOSEKey: unsupported curve 'P-224'")
+
+		_, err = ToCOSEKey(&jwk.JWK{JSONWebKey: jose.JSONWebKey{Key: []byte("oct")}})
+		require.ErrorContains(t, err, "toCOSEKey: unsupported raw key")
+
+		edPub, _, err := ed25519.GenerateKey(rand.Reader)
+		require.NoError(t, err)
+
+		_, err = ToCOSEKey(&jwk.JWK{JSONWebKey: jose.JSONWebKey{Key: edPub, Algorithm: "HS256"}})
+		require.EqualError(t, err, "toCOSEKey: unsupported alg 'HS256'")
+	})
+
+	t.Run("FromCOSEKey errors", func(t *testing.T) {
+		ed25519X := "215820" + hex.EncodeToString(make([]byte, 32))
+
+		for coseHex, errMsg := range map[string]string{
+			"":                                      "fromCOSEKey: invalid COSE_Key: unexpected end of CBOR data",
+			"80":                                    "fromCOSEKey: invalid COSE_Key: CBOR data is not a map",
+			"a10101ff":                              "fromCOSEKey: invalid COSE_Key: unexpected data after CBOR map",
+			"bf0101ff":                              "fromCOSEKey: invalid COSE_Key: unsupported CBOR indefinite length or reserved item", //nolint:lll
+			"a20101010102":                          "fromCOSEKey: invalid COSE_Key: duplicate CBOR map key 1",
+			"a1015901":                              "fromCOSEKey: invalid COSE_Key: unexpected end of CBOR data",
+			"a1019a000000ff":                        "fromCOSEKey: invalid COSE_Key: CBOR argument is not in its shortest form",
+			"b1":                                    "fromCOSEKey: invalid COSE_Key: COSE_Key has more than 16 parameters",
+			"a1616101":                              "fromCOSEKey: invalid COSE_Key: COSE_Key label: CBOR item is not an integer", //nolint:lll
+			"a101c1":                                "fromCOSEKey: invalid COSE_Key: unsupported CBOR major type 6",
+			"a101a0":                                "fromCOSEKey: invalid COSE_Key: unsupported CBOR major type 5",
+			"a101f93e00":                            "fromCOSEKey: invalid COSE_Key: unsupported CBOR float or simple value",
+			"a10162fffe":                            "fromCOSEKey: invalid COSE_Key: CBOR text string is not valid UTF-8",
+			"a10401":                                "fromCOSEKey: invalid COSE_Key: COSE_Key key_ops is not an array",
+			"a10491":                                "fromCOSEKey: invalid COSE_Key: COSE_Key key_ops has more than 16 operations",      //nolint:lll
+			"a10481f4":                              "fromCOSEKey: invalid COSE_Key: COSE_Key key_ops must be integers or text strings", //nolint:lll
+			"a0":                                    "fromCOSEKey: missing or invalid kty",
+			"a10104":                                "fromCOSEKey: unsupported kty 4",
+			"a3010120060458":                        "fromCOSEKey: invalid COSE_Key: unexpected end of CBOR data",
+			"a2010120" + "07":                       "fromCOSEKey: missing or invalid OKP x",
+			"a3010120" + "07" + ed25519X:            "fromCOSEKey: unsupported OKP curve 7",
+			"a3010120" + "06" + "214100":            "fromCOSEKey: Ed25519 x must be 32 bytes",
+			"a4010120" + "06" + ed25519X + "2340":   "fromCOSEKey: private COSE keys are not supported",
+			"a4010120" + "06" + ed25519X + "033863": "fromCOSEKey: unsupported alg -100",
+			"a4010120" + "06" + ed25519X + "0201":   "fromCOSEKey: invalid kid",
+			"a40102200121" + "4100" + "22f5":        "fromCOSEKey: compressed EC2 points are not supported",
+			"a40102200121" + "4100" + "224100":      "fromCOSEKey: EC2 coordinates must be 32 bytes byte strings",
+			"a2010220" + "04":                       "fromCOSEKey: unsupported EC2 curve 4",
+			"a4010320410121410122" + "4101":         "fromCOSEKey: private COSE keys are not supported",
+			"a201032041":                            "fromCOSEKey: invalid COSE_Key: unexpected end of CBOR data",
+			"a2010320" + "4101":                     "fromCOSEKey: missing or invalid RSA n or e",
+		} {
+			coseKey, err := hex.DecodeString(coseHex)
+			require.NoError(t, err, coseHex)
+
+			_, err = FromCOSEKey(coseKey)
+			require.EqualError(t, err, errMsg, coseHex)
+		}
+	})
+
+	t.Run("unknown labels and key_ops are skipped", func(t *testing.T) {
+		pub, _, err := ed25519.GenerateKey(rand.Reader)
+		require.NoError(t, err)
+
+		// {1: 1, 4: [2, "sign"], -1: 6, -2: x, 100: true}
+		coseKey := append([]byte{0xa5, 0x01, 0x01, 0x04, 0x82, 0x02, 0x64, 's', 'i', 'g', 'n', 0x20, 0x06, 0x21, 0x58,
+			0x20}, pub...)
+		coseKey = append(coseKey, 0x18, 0x64, 0xf5)
+
+		key, err := FromCOSEKey(coseKey)
+		require.NoError(t, err)
+		require.Equal(t, ed25519.PublicKey(pub), key.Key)
+	})
+
+	t.Run("oversized COSE_Key", func(t *testing.T) {
+		_, err := FromCOSEKey(make([]byte, 8193))
+		require.EqualError(t, err, "fromCOSEKey: invalid COSE_Key: COSE_Key exceeds 8192 bytes")
+	})
+}
+
+func FuzzFromCOSEKey(f *testing.F) {
+	for _, seed := range fuzzSeeds(f) {
+		j := &jwk.JWK{}
+		require.NoError(f, j.UnmarshalJSON(seed))
+
+		coseKey, err := ToCOSEKey(j)
+		if err != nil {
+			continue
+		}
+
+		f.Add(coseKey)
+	}
+
+	f.Fuzz(func(t *testing.T, data []byte) {
+		j, err := FromCOSEKey(data)
+		if err != nil {
+			return
+		}
+
+		// a decoded key encodes again and decodes to the same key.
+		coseKey, err := ToCOSEKey(j)
+		require.NoError(t, err)
+
+		decoded, err := FromCOSEKey(coseKey)
+		require.NoError(t, err)
+		require.Equal(t, j.Key, decoded.Key)
+	})
+}
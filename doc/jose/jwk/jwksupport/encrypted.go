@@ -0,0 +1,147 @@
+/*
+Copyright Gen Digital Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package jwksupport
+
+import (
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/rsa"
+	"errors"
+	"fmt"
+
+	"github.com/go-jose/go-jose/v3"
+
+	"github.com/dellekappa/kms-go/doc/jose/jwk"
+)
+
+const (
+	// DefaultPBES2Iterations is the default PBKDF2 iteration count of EncryptJWK, as recommended by OWASP for
+	// PBKDF2-HMAC-SHA256.
+	DefaultPBES2Iterations = 310000
+
+	// minPBES2Iterations is the minimum iteration count recommended by
+	// https://tools.ietf.org/html/rfc7518#section-4.8.1.2.
+	minPBES2Iterations = 1000
+	// maxPBES2Iterations is the maximum iteration count go-jose accepts when decrypting.
+	maxPBES2Iterations = 1000000
+
+	encryptedJWKContentType = "jwk+json"
+)
+
+// encryptJWKOpts holds options for EncryptJWK.
+type encryptJWKOpts struct {
+	iterations int
+}
+
+// EncryptJWKOpt is the EncryptJWK option.
+type EncryptJWKOpt func(opts *encryptJWKOpts)
+
+// WithPBES2Iterations option sets the PBKDF2 iteration count used to derive the key wrapping key from the password,
+// between 1000 and 1000000. It defaults to DefaultPBES2Iterations.
+func WithPBES2Iterations(iterations int) EncryptJWKOpt {
+	return func(opts *encryptJWKOpts) {
+		opts.iterations = iterations
+	}
+}
+
+// EncryptJWK encrypts j, typically a private key, with password as a compact serialized JWE using
+// PBES2-HS256+A128KW key wrapping and A256GCM content encryption, as per
+// https://tools.ietf.org/html/rfc7517#section-7. DecryptJWK reverses the encryption.
+func EncryptJWK(j *jwk.JWK, password []byte, opts ...EncryptJWKOpt) ([]byte, error) {
+	eOpts := &encryptJWKOpts{iterations: DefaultPBES2Iterations}
+
+	for _, opt := range opts {
+		opt(eOpts)
+	}
+
+	if eOpts.iterations < minPBES2Iterations || eOpts.iterations > maxPBES2Iterations {
+		return nil, fmt.Errorf("encryptJWK: PBES2 iteration count must be between %d and %d, got %d",
+			minPBES2Iterations, maxPBES2Iterations, eOpts.iterations)
+	}
+
+	if len(password) == 0 {
+		return nil, errors.New("encryptJWK: password is empty")
+	}
+
+	jwkBytes, err := j.MarshalJSON()
+	if err != nil {
+		return nil, fmt.Errorf("encryptJWK: marshal JWK: %w", err)
+	}
+
+	encrypter, err := jose.NewEncrypter(jose.A256GCM, jose.Recipient{
+		Algorithm:  jose.PBES2_HS256_A128KW,
+		Key:        password,
+		PBES2Count: eOpts.iterations,
+	}, (&jose.EncrypterOptions{}).WithContentType(encryptedJWKContentType))
+	if err != nil {
+		return nil, fmt.Errorf("encryptJWK: create encrypter: %w", err)
+	}
+
+	jwe, err := encrypter.Encrypt(jwkBytes)
+	if err != nil {
+		return nil, fmt.Errorf("encryptJWK: encrypt: %w", err)
+	}
+
+	serialized, err := jwe.CompactSerialize()
+	if err != nil {
+		return nil, fmt.Errorf("encryptJWK: serialize: %w", err)
+	}
+
+	return []byte(serialized), nil
+}
+
+// DecryptJWK decrypts a JWK encrypted by EncryptJWK with password. Only PBES2-HS256+A128KW key wrapping and A256GCM
+// content encryption are accepted, and the decrypted content must be a well-formed JWK.
+func DecryptJWK(data, password []byte) (*jwk.JWK, error) {
+	jwe, err := jose.ParseEncrypted(string(data))
+	if err != nil {
+		return nil, fmt.Errorf("decryptJWK: parse JWE: %w", err)
+	}
+
+	if alg := jose.KeyAlgorithm(jwe.Header.Algorithm); alg != jose.PBES2_HS256_A128KW {
+		return nil, fmt.Errorf("decryptJWK: unsupported key management algorithm '%s'", alg)
+	}
+
+	if enc, _ := jwe.Header.ExtraHeaders[jose.HeaderKey("enc")].(string); jose.ContentEncryption(enc) != jose.A256GCM {
+		return nil, fmt.Errorf("decryptJWK: unsupported content encryption algorithm '%s'", enc)
+	}
+
+	jwkBytes, err := jwe.Decrypt(password)
+	if err != nil {
+		return nil, fmt.Errorf("decryptJWK: decrypt: %w", err)
+	}
+
+	j := &jwk.JWK{}
+
+	err = j.UnmarshalJSON(jwkBytes)
+	if err != nil {
+		return nil, fmt.Errorf("decryptJWK: invalid JWK: %w", err)
+	}
+
+	err = validateKeyMaterial(j)
+	if err != nil {
+		return nil, fmt.Errorf("decryptJWK: invalid JWK: %w", err)
+	}
+
+	return j, nil
+}
+
+// validateKeyMaterial checks the key material of j, beyond the checks done when unmarshalling it.
+func validateKeyMaterial(j *jwk.JWK) error {
+	switch key := j.Key.(type) {
+	case nil:
+		return errors.New("missing key material")
+	case *rsa.PrivateKey:
+		return key.Validate()
+	case *ecdsa.PrivateKey, *ecdsa.PublicKey, *rsa.PublicKey, ed25519.PrivateKey, ed25519.PublicKey:
+		if !j.Valid() {
+			return errors.New("incomplete key material")
+		}
+	}
+
+	return nil
+}
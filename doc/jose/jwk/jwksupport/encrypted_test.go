@@ -0,0 +1,149 @@
+/*
+Copyright Gen Digital Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package jwksupport
+
+import (
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/rsa"
+	"testing"
+
+	"github.com/btcsuite/btcd/btcec/v2"
+	"github.com/go-jose/go-jose/v3"
+	"github.com/stretchr/testify/require"
+
+	"github.com/dellekappa/kms-go/doc/jose/jwk"
+)
+
+func TestEncryptDecryptJWK(t *testing.T) {
+	password := []byte("correct horse battery staple")
+
+	ecKey, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+
+	secp256k1Key, err := ecdsa.GenerateKey(btcec.S256(), rand.Reader)
+	require.NoError(t, err)
+
+	_, edKey, err := ed25519.GenerateKey(rand.Reader)
+	require.NoError(t, err)
+
+	rsaKey, err := rsa.GenerateKey(rand.Reader, 2048)
+	require.NoError(t, err)
+
+	t.Run("success", func(t *testing.T) {
+		tests := []struct {
+			name string
+			key  interface {
+				Equal(x crypto.PrivateKey) bool
+			}
+		}{
+			{name: "P-256", key: ecKey},
+			{name: "secp256k1", key: secp256k1Key},
+			{name: "Ed25519", key: edKey},
+			{name: "RSA", key: rsaKey},
+		}
+
+		for _, tc := range tests {
+			t.Run(tc.name, func(t *testing.T) {
+				j, err := JWKFromKey(tc.key)
+				require.NoError(t, err)
+
+				j.KeyID = "backup"
+
+				encrypted, err := EncryptJWK(j, password, WithPBES2Iterations(minPBES2Iterations))
+				require.NoError(t, err)
+
+				jwe, err := jose.ParseEncrypted(string(encrypted))
+				require.NoError(t, err)
+				require.Equal(t, string(jose.PBES2_HS256_A128KW), jwe.Header.Algorithm)
+				require.Equal(t, encryptedJWKContentType, jwe.Header.ExtraHeaders[jose.HeaderContentType])
+
+				decrypted, err := DecryptJWK(encrypted, password)
+				require.NoError(t, err)
+				require.Equal(t, "backup", decrypted.KeyID)
+				require.True(t, tc.key.Equal(decrypted.Key))
+			})
+		}
+	})
+
+	t.Run("default iteration count", func(t *testing.T) {
+		j, err := JWKFromKey(ecKey)
+		require.NoError(t, err)
+
+		encrypted, err := EncryptJWK(j, password)
+		require.NoError(t, err)
+
+		jwe, err := jose.ParseEncrypted(string(encrypted))
+		require.NoError(t, err)
+		require.EqualValues(t, DefaultPBES2Iterations, jwe.Header.ExtraHeaders["p2c"])
+	})
+
+	t.Run("encrypt errors", func(t *testing.T) {
+		j, err := JWKFromKey(ecKey)
+		require.NoError(t, err)
+
+		_, err = EncryptJWK(j, password, WithPBES2Iterations(999))
+		require.EqualError(t, err, "encryptJWK: PBES2 iteration count must be between 1000 and 1000000, got 999")
+
+		_, err = EncryptJWK(j, password, WithPBES2Iterations(maxPBES2Iterations+1))
+		require.EqualError(t, err,
+			"encryptJWK: PBES2 iteration count must be between 1000 and 1000000, got 1000001")
+
+		_, err = EncryptJWK(j, nil)
+		require.EqualError(t, err, "encryptJWK: password is empty")
+
+		_, err = EncryptJWK(&jwk.JWK{}, password)
+		require.ErrorContains(t, err, "encryptJWK: marshal JWK")
+	})
+
+	t.Run("decrypt errors", func(t *testing.T) {
+		j, err := JWKFromKey(ecKey)
+		require.NoError(t, err)
+
+		encrypted, err := EncryptJWK(j, password, WithPBES2Iterations(minPBES2Iterations))
+		require.NoError(t, err)
+
+		_, err = DecryptJWK(encrypted, []byte("wrong password"))
+		require.ErrorContains(t, err, "decryptJWK: decrypt")
+
+		_, err = DecryptJWK([]byte("not a JWE"), password)
+		require.ErrorContains(t, err, "decryptJWK: parse JWE")
+
+		encryptWith := func(alg jose.KeyAlgorithm, enc jose.ContentEncryption, key interface{},
+			plaintext []byte) []byte {
+			encrypter, err := jose.NewEncrypter(enc, jose.Recipient{Algorithm: alg, Key: key, PBES2Count: 1000}, nil)
+			require.NoError(t, err)
+
+			jwe, err := encrypter.Encrypt(plaintext)
+			require.NoError(t, err)
+
+			serialized, err := jwe.CompactSerialize()
+			require.NoError(t, err)
+
+			return []byte(serialized)
+		}
+
+		jwkBytes, err := j.MarshalJSON()
+		require.NoError(t, err)
+
+		_, err = DecryptJWK(encryptWith(jose.A128KW, jose.A256GCM, make([]byte, 16), jwkBytes), make([]byte, 16))
+		require.EqualError(t, err, "decryptJWK: unsupported key management algorithm 'A128KW'")
+
+		_, err = DecryptJWK(encryptWith(jose.PBES2_HS256_A128KW, jose.A128CBC_HS256, password, jwkBytes), password)
+		require.EqualError(t, err, "decryptJWK: unsupported content encryption algorithm 'A128CBC-HS256'")
+
+		_, err = DecryptJWK(encryptWith(jose.PBES2_HS256_A128KW, jose.A256GCM, password, []byte("{}")), password)
+		require.ErrorContains(t, err, "decryptJWK: invalid JWK")
+
+		_, err = DecryptJWK(encryptWith(jose.PBES2_HS256_A128KW, jose.A256GCM, password, []byte("not JSON")),
+			password)
+		require.ErrorContains(t, err, "decryptJWK: invalid JWK")
+	})
+}
@@ -0,0 +1,52 @@
+/*
+Copyright Gen Digital Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package jwksupport
+
+import (
+	"bytes"
+	"fmt"
+
+	"github.com/dellekappa/kms-go/doc/jose/jwk"
+)
+
+// FuzzJWKRoundTrip is a go-fuzz style harness of the JWK decoder, for fuzzing it with a custom corpus (FuzzParseJWK
+// runs it under go test -fuzz). data is parsed as a JWK, which is then marshalled, parsed and marshalled again: both
+// marshalled JWKs must be identical. The public key of the JWK is also converted with PublicKeyFromJWK.
+// It returns 1 if data is a valid JWK, 0 otherwise, and panics if the round trip isn't stable. Any other panic is a
+// bug of the decoder.
+func FuzzJWKRoundTrip(data []byte) int {
+	key := &jwk.JWK{}
+
+	if err := key.UnmarshalJSON(data); err != nil {
+		return 0
+	}
+
+	// the conversion may fail, it must not panic.
+	_, _ = PublicKeyFromJWK(key) //nolint:errcheck
+
+	marshalled, err := key.MarshalJSON()
+	if err != nil {
+		panic(fmt.Sprintf("jwk round trip: marshal parsed JWK %s: %v", data, err))
+	}
+
+	reparsed := &jwk.JWK{}
+
+	if err = reparsed.UnmarshalJSON(marshalled); err != nil {
+		panic(fmt.Sprintf("jwk round trip: parse marshalled JWK %s: %v", marshalled, err))
+	}
+
+	remarshalled, err := reparsed.MarshalJSON()
+	if err != nil {
+		panic(fmt.Sprintf("jwk round trip: marshal reparsed JWK %s: %v", marshalled, err))
+	}
+
+	if !bytes.Equal(marshalled, remarshalled) {
+		panic(fmt.Sprintf("jwk round trip: unstable JWK %s, marshalled as %s then %s", data, marshalled, remarshalled))
+	}
+
+	return 1
+}
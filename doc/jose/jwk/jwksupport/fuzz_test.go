@@ -0,0 +1,70 @@
+/*
+Copyright Gen Digital Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package jwksupport
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/rsa"
+	"testing"
+
+	"github.com/btcsuite/btcd/btcec/v2"
+	"github.com/stretchr/testify/require"
+)
+
+// fuzzSeeds returns valid JWKs of the main key types, as seed corpus of FuzzParseJWK.
+func fuzzSeeds(t testing.TB) [][]byte {
+	t.Helper()
+
+	seeds := [][]byte{
+		[]byte(`{"kty":"OKP","crv":"Ed25519","x":"11qYAYKxCrfVS_7TyWQHOg7hcvPapiMlrwIaaPcHURo"}`),
+		[]byte(`{"kty":"OKP","crv":"X25519","x":"hSDwCYkwp1R0i33ctD73Wg2_Og0mOBr066SpjqqbTmo","kid":"x"}`),
+		[]byte(`{"kty":"oct","k":"GawgguFyGrWKav7AX4VKUg"}`),
+	}
+
+	ecKey, err := ecdsa.GenerateKey(elliptic.P384(), rand.Reader)
+	require.NoError(t, err)
+
+	secp256k1Key, err := ecdsa.GenerateKey(btcec.S256(), rand.Reader)
+	require.NoError(t, err)
+
+	rsaKey, err := rsa.GenerateKey(rand.Reader, 2048)
+	require.NoError(t, err)
+
+	for _, key := range []interface{}{ecKey, &ecKey.PublicKey, &secp256k1Key.PublicKey, &rsaKey.PublicKey} {
+		j, err := JWKFromKey(key)
+		require.NoError(t, err)
+
+		jwkBytes, err := j.MarshalJSON()
+		require.NoError(t, err)
+
+		seeds = append(seeds, jwkBytes)
+	}
+
+	return seeds
+}
+
+func TestFuzzJWKRoundTrip(t *testing.T) {
+	for _, seed := range fuzzSeeds(t) {
+		require.Equal(t, 1, FuzzJWKRoundTrip(seed), string(seed))
+	}
+
+	for _, invalid := range []string{``, `{}`, `null`, `{"kty":"EC","crv":"P-256","x":"AAAA","y":"AAAA"}`} {
+		require.Equal(t, 0, FuzzJWKRoundTrip([]byte(invalid)), invalid)
+	}
+}
+
+func FuzzParseJWK(f *testing.F) {
+	for _, seed := range fuzzSeeds(f) {
+		f.Add(seed)
+	}
+
+	f.Fuzz(func(_ *testing.T, data []byte) {
+		FuzzJWKRoundTrip(data)
+	})
+}
@@ -0,0 +1,166 @@
+/*
+Copyright Gen Digital Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package jwksupport
+
+import (
+	"crypto/ecdh"
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
+	"errors"
+	"fmt"
+	"io"
+	"math/big"
+
+	"github.com/btcsuite/btcd/btcec/v2"
+	"github.com/trustbloc/bbs-signature-go/bbs12381g2pub"
+
+	"github.com/dellekappa/kms-go/doc/jose/jwk"
+	"github.com/dellekappa/kms-go/spi/kms"
+)
+
+const (
+	bbsSeedSize = 32
+	// maxScalarAttempts bounds the rejection sampling of EC private scalars, the probability of a random value being
+	// out of range is at most 2^-32 for the supported curves.
+	maxScalarAttempts = 16
+)
+
+// GenerateJWK generates a new private key of type kt using crypto/rand.Reader and returns it as a JWK.
+// See GenerateJWKWithReader for the supported key types.
+func GenerateJWK(kt kms.KeyType) (*jwk.JWK, error) {
+	return GenerateJWKWithReader(kt, rand.Reader)
+}
+
+// GenerateJWKWithReader generates a new private key of type kt from the bytes read from random and returns it as a
+// JWK. The key is derived only from the bytes read from random, making it reproducible with a deterministic reader,
+// unlike the standard library key generation functions which may ignore their io.Reader argument.
+//
+// Supported key types are ED25519Type, the ECDSA NIST P-256/384/521 key types (including their NISTPxxxECDHKWType
+// counterparts), the secp256k1 key types, BLS12381G2Type and the RSA3072Type and RSA4096Type RSA key types. RSA keys
+// are generated with rsa.GenerateKey and are not reproducible from random, use fixtures for deterministic RSA test keys.
+//
+// random MUST be a cryptographically secure random source (e.g. crypto/rand.Reader or a FIPS validated DRBG) in
+// production, a deterministic reader must only be used to produce test vectors.
+func GenerateJWKWithReader(kt kms.KeyType, random io.Reader) (*jwk.JWK, error) {
+	if random == nil {
+		return nil, errors.New("generateJWK: random reader is nil")
+	}
+
+	privKey, err := generateKey(kt, random)
+	if err != nil {
+		return nil, fmt.Errorf("generateJWK: %w", err)
+	}
+
+	j, err := JWKFromKey(privKey)
+	if err != nil {
+		return nil, fmt.Errorf("generateJWK: %w", err)
+	}
+
+	return j, nil
+}
+
+func generateKey(kt kms.KeyType, random io.Reader) (interface{}, error) {
+	switch kt {
+	case kms.ED25519Type:
+		seed := make([]byte, ed25519.SeedSize)
+
+		if _, err := io.ReadFull(random, seed); err != nil {
+			return nil, fmt.Errorf("failed to read seed: %w", err)
+		}
+
+		return ed25519.NewKeyFromSeed(seed), nil
+	case kms.ECDSAP256TypeIEEEP1363, kms.ECDSAP256TypeDER, kms.NISTP256ECDHKWType:
+		return generateNISTPKey(elliptic.P256(), ecdh.P256(), random)
+	case kms.ECDSAP384TypeIEEEP1363, kms.ECDSAP384TypeDER, kms.NISTP384ECDHKWType:
+		return generateNISTPKey(elliptic.P384(), ecdh.P384(), random)
+	case kms.ECDSAP521TypeIEEEP1363, kms.ECDSAP521TypeDER, kms.NISTP521ECDHKWType:
+		return generateNISTPKey(elliptic.P521(), ecdh.P521(), random)
+	case kms.ECDSASecp256k1TypeIEEEP1363, kms.ECDSASecp256k1TypeDER:
+		d, err := randomScalar(btcec.S256(), random)
+		if err != nil {
+			return nil, err
+		}
+
+		privKey, _ := btcec.PrivKeyFromBytes(d)
+
+		return privKey.ToECDSA(), nil
+	case kms.BLS12381G2Type:
+		seed := make([]byte, bbsSeedSize)
+
+		if _, err := io.ReadFull(random, seed); err != nil {
+			return nil, fmt.Errorf("failed to read seed: %w", err)
+		}
+
+		_, privKey, err := bbs12381g2pub.GenerateKeyPair(sha256.New, seed)
+		if err != nil {
+			return nil, fmt.Errorf("failed to generate BLS12381G2 key: %w", err)
+		}
+
+		return privKey, nil
+	case kms.RSA3072Type:
+		return rsa.GenerateKey(random, 3072) //nolint:gomnd
+	case kms.RSA4096Type:
+		return rsa.GenerateKey(random, 4096) //nolint:gomnd
+	default:
+		return nil, fmt.Errorf("unsupported key type: %s", kt)
+	}
+}
+
+func generateNISTPKey(curve elliptic.Curve, ecdhCurve ecdh.Curve, random io.Reader) (*ecdsa.PrivateKey, error) {
+	d, err := randomScalar(curve, random)
+	if err != nil {
+		return nil, err
+	}
+
+	ecdhKey, err := ecdhCurve.NewPrivateKey(d)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create %s key: %w", curve.Params().Name, err)
+	}
+
+	// uncompressed point: 0x04 || X || Y.
+	pub := ecdhKey.PublicKey().Bytes()
+	coordSize := (len(pub) - 1) / 2 //nolint:gomnd
+
+	return &ecdsa.PrivateKey{
+		PublicKey: ecdsa.PublicKey{
+			Curve: curve,
+			X:     new(big.Int).SetBytes(pub[1 : 1+coordSize]),
+			Y:     new(big.Int).SetBytes(pub[1+coordSize:]),
+		},
+		D: new(big.Int).SetBytes(d),
+	}, nil
+}
+
+// randomScalar reads a private scalar in [1, N-1] for curve from random, by rejection sampling of values of the bit
+// size of N.
+func randomScalar(curve elliptic.Curve, random io.Reader) ([]byte, error) {
+	n := curve.Params().N
+	bitSize := n.BitLen()
+	buf := make([]byte, (bitSize+7)/8) //nolint:gomnd
+
+	for i := 0; i < maxScalarAttempts; i++ {
+		if _, err := io.ReadFull(random, buf); err != nil {
+			return nil, fmt.Errorf("failed to read private scalar: %w", err)
+		}
+
+		// clear the excess bits of the most significant byte.
+		if excess := len(buf)*8 - bitSize; excess > 0 {
+			buf[0] &= 0xff >> excess
+		}
+
+		d := new(big.Int).SetBytes(buf)
+		if d.Sign() > 0 && d.Cmp(n) < 0 {
+			return buf, nil
+		}
+	}
+
+	return nil, errors.New("failed to generate a private scalar in range")
+}
@@ -0,0 +1,164 @@
+/*
+Copyright Gen Digital Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package jwksupport
+
+import (
+	"bytes"
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
+	"io"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"github.com/trustbloc/bbs-signature-go/bbs12381g2pub"
+
+	"github.com/dellekappa/kms-go/spi/kms"
+)
+
+// deterministicReader returns an endless stream of bytes derived from seed, for test vectors only.
+type deterministicReader struct {
+	block   [sha256.Size]byte
+	counter byte
+	buf     []byte
+}
+
+func newDeterministicReader(seed string) *deterministicReader {
+	return &deterministicReader{block: sha256.Sum256([]byte(seed))}
+}
+
+func (r *deterministicReader) Read(p []byte) (int, error) {
+	for len(r.buf) < len(p) {
+		r.counter++
+		r.block = sha256.Sum256(append(r.block[:], r.counter))
+		r.buf = append(r.buf, r.block[:]...)
+	}
+
+	n := copy(p, r.buf)
+	r.buf = r.buf[n:]
+
+	return n, nil
+}
+
+func TestGenerateJWKWithReader(t *testing.T) {
+	keyTypes := []struct {
+		kt  kms.KeyType
+		kty string
+		crv string
+	}{
+		{kt: kms.ED25519Type, kty: "OKP", crv: "Ed25519"},
+		{kt: kms.ECDSAP256TypeIEEEP1363, kty: "EC", crv: "P-256"},
+		{kt: kms.ECDSAP384TypeDER, kty: "EC", crv: "P-384"},
+		{kt: kms.NISTP521ECDHKWType, kty: "EC", crv: "P-521"},
+		{kt: kms.ECDSASecp256k1TypeIEEEP1363, kty: "EC", crv: "secp256k1"},
+		{kt: kms.BLS12381G2Type, kty: "EC", crv: "BLS12381_G2"},
+	}
+
+	for _, tc := range keyTypes {
+		t.Run(string(tc.kt), func(t *testing.T) {
+			j1, err := GenerateJWKWithReader(tc.kt, newDeterministicReader("seed"))
+			require.NoError(t, err)
+			require.False(t, j1.IsPublic())
+			require.Equal(t, tc.kty, j1.Kty)
+			require.Equal(t, tc.crv, j1.Crv)
+
+			j2, err := GenerateJWKWithReader(tc.kt, newDeterministicReader("seed"))
+			require.NoError(t, err)
+
+			j3, err := GenerateJWKWithReader(tc.kt, newDeterministicReader("other seed"))
+			require.NoError(t, err)
+
+			b1, err := j1.MarshalJSON()
+			require.NoError(t, err)
+
+			b2, err := j2.MarshalJSON()
+			require.NoError(t, err)
+
+			b3, err := j3.MarshalJSON()
+			require.NoError(t, err)
+
+			require.Equal(t, b1, b2)
+			require.NotEqual(t, b1, b3)
+
+			j, err := GenerateJWK(tc.kt)
+			require.NoError(t, err)
+			require.Equal(t, tc.crv, j.Crv)
+		})
+	}
+
+	t.Run("generated keys are valid", func(t *testing.T) {
+		j, err := GenerateJWKWithReader(kms.ED25519Type, newDeterministicReader("seed"))
+		require.NoError(t, err)
+
+		edKey, ok := j.Key.(ed25519.PrivateKey)
+		require.True(t, ok)
+
+		sig := ed25519.Sign(edKey, []byte("msg"))
+		require.True(t, ed25519.Verify(edKey.Public().(ed25519.PublicKey), []byte("msg"), sig))
+
+		j, err = GenerateJWKWithReader(kms.ECDSAP256TypeDER, newDeterministicReader("seed"))
+		require.NoError(t, err)
+
+		ecKey, ok := j.Key.(*ecdsa.PrivateKey)
+		require.True(t, ok)
+		require.True(t, ecKey.Curve.IsOnCurve(ecKey.X, ecKey.Y))
+
+		x, y := ecKey.Curve.ScalarBaseMult(ecKey.D.Bytes())
+		require.Zero(t, x.Cmp(ecKey.X))
+		require.Zero(t, y.Cmp(ecKey.Y))
+
+		j, err = GenerateJWKWithReader(kms.BLS12381G2Type, newDeterministicReader("seed"))
+		require.NoError(t, err)
+
+		_, ok = j.Key.(*bbs12381g2pub.PrivateKey)
+		require.True(t, ok)
+
+		for _, tc := range []struct {
+			kt   kms.KeyType
+			bits int
+		}{
+			{kt: kms.RSA3072Type, bits: 3072},
+			{kt: kms.RSA4096Type, bits: 4096},
+		} {
+			j, err = GenerateJWK(tc.kt)
+			require.NoError(t, err)
+			require.NoError(t, j.Validate(tc.kt))
+
+			rsaKey, ok := j.Key.(*rsa.PrivateKey)
+			require.True(t, ok)
+			require.Equal(t, tc.bits, rsaKey.N.BitLen())
+			require.NoError(t, rsaKey.Validate())
+
+			digest := sha256.Sum256([]byte("msg"))
+
+			sig, err := rsa.SignPSS(rand.Reader, rsaKey, crypto.SHA256, digest[:], nil)
+			require.NoError(t, err)
+			require.NoError(t, rsa.VerifyPSS(&rsaKey.PublicKey, crypto.SHA256, digest[:], sig, nil))
+		}
+	})
+
+	t.Run("error cases", func(t *testing.T) {
+		_, err := GenerateJWKWithReader(kms.ED25519Type, nil)
+		require.EqualError(t, err, "generateJWK: random reader is nil")
+
+		_, err = GenerateJWKWithReader(kms.RSAPS256Type, newDeterministicReader("seed"))
+		require.EqualError(t, err, "generateJWK: unsupported key type: RSAPS256")
+
+		for _, kt := range []kms.KeyType{kms.ED25519Type, kms.ECDSAP256TypeDER, kms.ECDSASecp256k1TypeDER,
+			kms.BLS12381G2Type} {
+			_, err = GenerateJWKWithReader(kt, bytes.NewReader([]byte{1, 2, 3}))
+			require.ErrorIs(t, err, io.ErrUnexpectedEOF)
+		}
+
+		// an all zero scalar is always out of range.
+		_, err = GenerateJWKWithReader(kms.ECDSAP256TypeDER, bytes.NewReader(make([]byte, 32*maxScalarAttempts)))
+		require.EqualError(t, err, "generateJWK: failed to generate a private scalar in range")
+	})
+}
@@ -19,6 +19,7 @@ import (
 	"fmt"
 	"math/big"
 
+	ml "github.com/IBM/mathlib"
 	"github.com/btcsuite/btcd/btcec/v2"
 	"github.com/go-jose/go-jose/v3"
 	"github.com/trustbloc/bbs-signature-go/bbs12381g2pub"
@@ -31,15 +32,31 @@ import (
 const (
 	ecKty          = "EC"
 	okpKty         = "OKP"
+	rsaKty         = "RSA"
 	x25519Crv      = "X25519"
+	secp256k1Crv   = "secp256k1"
 	bls12381G2Crv  = "BLS12381_G2"
 	bls12381G2Size = 96
+	bls12381G1Crv  = "BLS12381_G1"
+	bls12381G1Size = 48
 )
 
 // JWKFromKey creates a JWK from an opaque key struct.
 // It's e.g. *ecdsa.PublicKey, *ecdsa.PrivateKey, ed25519.VerificationMethod, *bbs12381g2pub.PrivateKey or
-// *bbs12381g2pub.PublicKey.
+// *bbs12381g2pub.PublicKey. The kid of the JWK is empty, see JWKFromKeyWithKID.
+// An ed25519.PrivateKey must be 64 bytes long, or be its 32 bytes seed, an ed25519.PublicKey 32 bytes long.
+// *btcec.PublicKey and *btcec.PrivateKey keys give secp256k1 EC JWKs, with their d member for private keys.
 func JWKFromKey(opaqueKey interface{}) (*jwk.JWK, error) {
+	opaqueKey, err := checkEd25519KeySize(opaqueKey)
+	if err != nil {
+		return nil, fmt.Errorf("create JWK: %w", err)
+	}
+
+	opaqueKey, err = btcecToECDSA(opaqueKey)
+	if err != nil {
+		return nil, fmt.Errorf("create JWK: %w", err)
+	}
+
 	key := &jwk.JWK{
 		JSONWebKey: jose.JSONWebKey{
 			Key: opaqueKey,
@@ -60,10 +77,122 @@ func JWKFromKey(opaqueKey interface{}) (*jwk.JWK, error) {
 	return key, nil
 }
 
+// checkEd25519KeySize checks the size of Ed25519 keys, which are slices that would make JWK marshalling panic if
+// truncated. A 32 bytes private key is taken as the seed of the private key.
+func checkEd25519KeySize(opaqueKey interface{}) (interface{}, error) {
+	switch key := opaqueKey.(type) {
+	case ed25519.PrivateKey:
+		switch len(key) {
+		case ed25519.PrivateKeySize:
+			return key, nil
+		case ed25519.SeedSize:
+			return ed25519.NewKeyFromSeed(key), nil
+		default:
+			return nil, fmt.Errorf("invalid Ed25519 private key size %d: expected %d bytes or a %d bytes seed",
+				len(key), ed25519.PrivateKeySize, ed25519.SeedSize)
+		}
+	case ed25519.PublicKey:
+		if len(key) != ed25519.PublicKeySize {
+			return nil, fmt.Errorf("invalid Ed25519 public key size %d: expected %d bytes", len(key),
+				ed25519.PublicKeySize)
+		}
+	}
+
+	return opaqueKey, nil
+}
+
+// btcecToECDSA converts btcec keys to ecdsa keys on the btcec.S256 curve, which JWKs are marshalled with the secp256k1
+// crv from.
+func btcecToECDSA(opaqueKey interface{}) (interface{}, error) {
+	switch key := opaqueKey.(type) {
+	case *btcec.PrivateKey:
+		if key == nil {
+			return nil, errors.New("secp256k1 private key is nil")
+		}
+
+		return key.ToECDSA(), nil
+	case *btcec.PublicKey:
+		if key == nil {
+			return nil, errors.New("secp256k1 public key is nil")
+		}
+
+		return key.ToECDSA(), nil
+	}
+
+	return opaqueKey, nil
+}
+
+// JWKFromKeyWithKID creates a JWK from an opaque key struct as JWKFromKey does, with its kid set to the base64url
+// encoded RFC 7638 SHA-256 thumbprint of the key (see KIDThumbprint). It is preferred over JWKFromKey for JWKs
+// exchanged with other parties, which can compute the same kid from the key.
+func JWKFromKeyWithKID(opaqueKey interface{}) (*jwk.JWK, error) {
+	key, err := JWKFromKey(opaqueKey)
+	if err != nil {
+		return nil, err
+	}
+
+	err = KIDThumbprint(key)
+	if err != nil {
+		return nil, fmt.Errorf("create JWK: failed to set kid: %w", err)
+	}
+
+	return key, nil
+}
+
+// FromCertificate creates a JWK from the public key of the given X.509 certificate. The certificate is set as the
+// JWK's x5c chain and its SHA-256 thumbprint as x5t#S256.
+// Certificates carrying a secp256k1 key are not parsed by crypto/x509, in which case the key is read from
+// cert.RawSubjectPublicKeyInfo.
+func FromCertificate(cert *x509.Certificate) (*jwk.JWK, error) {
+	if cert == nil {
+		return nil, errors.New("fromCertificate: certificate is empty")
+	}
+
+	var (
+		pubKey interface{}
+		err    error
+	)
+
+	switch key := cert.PublicKey.(type) {
+	case *ecdsa.PublicKey, *rsa.PublicKey, ed25519.PublicKey:
+		pubKey = key
+	case nil:
+		pubKey, err = parseSecp256k1DER(cert.RawSubjectPublicKeyInfo)
+		if err != nil {
+			return nil, fmt.Errorf("fromCertificate: failed to parse secp256k1 public key: %w", err)
+		}
+	default:
+		return nil, fmt.Errorf("fromCertificate: unsupported public key type %T", cert.PublicKey)
+	}
+
+	key, err := JWKFromKey(pubKey)
+	if err != nil {
+		return nil, fmt.Errorf("fromCertificate: %w", err)
+	}
+
+	key.Certificates = []*x509.Certificate{cert}
+
+	err = key.ComputeX5TS256()
+	if err != nil {
+		return nil, fmt.Errorf("fromCertificate: %w", err)
+	}
+
+	return key, nil
+}
+
 // PubKeyBytesToKey creates an opaque key struct from the given public key bytes.
 // It's e.g. *ecdsa.PublicKey, *ecdsa.PrivateKey, ed25519.VerificationMethod, *bbs12381g2pub.PrivateKey or
-// *bbs12381g2pub.PublicKey.
-func PubKeyBytesToKey(bytes []byte, keyType kms.KeyType) (interface{}, error) { // nolint:gocyclo,funlen
+// *bbs12381g2pub.PublicKey. Key types registered with RegisterKeyType are decoded by their KeyTypeHandler.
+func PubKeyBytesToKey(bytes []byte, keyType kms.KeyType) (interface{}, error) {
+	handler, ok := keyTypeHandler(keyType)
+	if !ok {
+		return nil, fmt.Errorf("invalid key type: %s", keyType)
+	}
+
+	return handler.PubKeyBytesToKey(bytes)
+}
+
+func builtinPubKeyBytesToKey(bytes []byte, keyType kms.KeyType) (interface{}, error) { // nolint:gocyclo,funlen
 	switch keyType {
 	case kms.ED25519Type:
 		return ed25519.PublicKey(bytes), nil
@@ -71,15 +200,19 @@ func PubKeyBytesToKey(bytes []byte, keyType kms.KeyType) (interface{}, error) {
 		return bytes, nil
 	case kms.BLS12381G2Type:
 		return bbs12381g2pub.UnmarshalPublicKey(bytes)
-	case kms.ECDSAP256TypeIEEEP1363, kms.ECDSAP384TypeIEEEP1363, kms.ECDSAP521TypeIEEEP1363:
-		crv := getECDSACurve(keyType)
-		x, y := elliptic.Unmarshal(crv, bytes)
+	case kms.BLS12381G1Type:
+		if len(bytes) != bls12381G1Size {
+			return nil, &jwk.JWKError{
+				Field:  "x",
+				Reason: fmt.Sprintf("must be %d bytes, got %d", bls12381G1Size, len(bytes)),
+				Err:    errors.New("invalid size of public key"),
+			}
+		}
 
-		return &ecdsa.PublicKey{
-			Curve: crv,
-			X:     x,
-			Y:     y,
-		}, nil
+		return ml.Curves[ml.BLS12_381_BBS].NewG1FromCompressed(bytes)
+	case kms.ECDSAP256TypeIEEEP1363, kms.ECDSAP384TypeIEEEP1363, kms.ECDSAP521TypeIEEEP1363:
+		// uncompressed, compressed and hybrid X9.62 encodings are accepted.
+		return jwk.ParseECPoint(getECDSACurve(keyType), bytes)
 	case kms.ECDSASecp256k1TypeIEEEP1363:
 		pubKey, err := btcec.ParsePubKey(bytes)
 		if err != nil {
@@ -100,15 +233,31 @@ func PubKeyBytesToKey(bytes []byte, keyType kms.KeyType) (interface{}, error) {
 		}
 
 		return ecKey, nil
-	case kms.RSARS256, kms.RSAPS256:
+	case kms.RSARS256, kms.RSAPS256, kms.RSA3072, kms.RSA4096:
 		pubKeyRsa, err := x509.ParsePKIXPublicKey(bytes)
 		if err != nil {
 			return nil, errors.New("rsa: invalid public key")
 		}
 
+		err = validateKeySize(pubKeyRsa, keyType)
+		if err != nil {
+			return nil, fmt.Errorf("rsa: %w", err)
+		}
+
 		return pubKeyRsa, nil
 	case kms.ECDSASecp256k1TypeDER:
 		return parseSecp256k1DER(bytes)
+	case kms.MLDSA44Type, kms.MLDSA65Type, kms.MLDSA87Type:
+		_, size, _ := jwk.MLDSAParameterSet(keyType)
+		if len(bytes) != size {
+			return nil, &jwk.JWKError{
+				Field:  "pub",
+				Reason: fmt.Sprintf("must be %d bytes, got %d", size, len(bytes)),
+				Err:    errors.New("invalid size of public key"),
+			}
+		}
+
+		return bytes, nil
 	case kms.NISTP256ECDHKWType, kms.NISTP384ECDHKWType, kms.NISTP521ECDHKWType:
 		crv := getECDSACurve(keyType)
 		pubKey := &cryptoapi.PublicKey{}
@@ -130,6 +279,102 @@ func PubKeyBytesToKey(bytes []byte, keyType kms.KeyType) (interface{}, error) {
 	}
 }
 
+// DetectKeyType guesses the kms.KeyType of the given public key bytes. It recognizes PKIX (DER) encoded ECDSA and RSA
+// keys, secp256k1 DER keys, raw compressed/uncompressed EC points, BBS+ (BLS12-381 G2), BLS12-381 G1 and ML-DSA keys
+// as well as raw 32 bytes OKP keys.
+// Returns:
+//   - the detected key type if the bytes match exactly one key type, empty otherwise.
+//   - the list of all candidate key types matching the bytes (more than one when the detection is ambiguous, e.g.
+//     a raw 32 bytes key is either Ed25519 or X25519).
+//   - error if no key type matches the bytes.
+func DetectKeyType(pubBytes []byte) (kms.KeyType, []kms.KeyType, error) {
+	candidates := detectPKIXKeyType(pubBytes)
+	if len(candidates) == 0 {
+		candidates = detectRawKeyType(pubBytes)
+	}
+
+	switch len(candidates) {
+	case 0:
+		return "", nil, errors.New("detectKeyType: unrecognized public key bytes")
+	case 1:
+		return candidates[0], candidates, nil
+	default:
+		return "", candidates, nil
+	}
+}
+
+func detectPKIXKeyType(pubBytes []byte) []kms.KeyType {
+	pubKey, err := x509.ParsePKIXPublicKey(pubBytes)
+	if err != nil {
+		if _, err = parseSecp256k1DER(pubBytes); err == nil {
+			return []kms.KeyType{kms.ECDSASecp256k1TypeDER}
+		}
+
+		return nil
+	}
+
+	switch key := pubKey.(type) {
+	case *ecdsa.PublicKey:
+		switch key.Curve {
+		case elliptic.P256():
+			return []kms.KeyType{kms.ECDSAP256TypeDER}
+		case elliptic.P384():
+			return []kms.KeyType{kms.ECDSAP384TypeDER}
+		case elliptic.P521():
+			return []kms.KeyType{kms.ECDSAP521TypeDER}
+		}
+	case *rsa.PublicKey:
+		return []kms.KeyType{kms.RSARS256Type, kms.RSAPS256Type}
+	}
+
+	return nil
+}
+
+func detectRawKeyType(pubBytes []byte) []kms.KeyType {
+	var candidates []kms.KeyType
+
+	switch len(pubBytes) {
+	case ed25519.PublicKeySize:
+		return []kms.KeyType{kms.ED25519Type, kms.X25519ECDHKWType}
+	case bls12381G2Size:
+		if _, err := bbs12381g2pub.UnmarshalPublicKey(pubBytes); err == nil {
+			return []kms.KeyType{kms.BLS12381G2Type}
+		}
+	case bls12381G1Size:
+		if _, err := ml.Curves[ml.BLS12_381_BBS].NewG1FromCompressed(pubBytes); err == nil {
+			return []kms.KeyType{kms.BLS12381G1Type}
+		}
+	}
+
+	for _, kt := range []kms.KeyType{kms.MLDSA44Type, kms.MLDSA65Type, kms.MLDSA87Type} {
+		if _, size, _ := jwk.MLDSAParameterSet(kt); len(pubBytes) == size {
+			return []kms.KeyType{kt}
+		}
+	}
+
+	for _, kt := range []kms.KeyType{
+		kms.ECDSAP256TypeIEEEP1363, kms.ECDSAP384TypeIEEEP1363, kms.ECDSAP521TypeIEEEP1363,
+	} {
+		curve := getECDSACurve(kt)
+
+		// only uncompressed and hybrid points are detected, compressed NIST P-256 points can't be told apart from
+		// compressed secp256k1 points.
+		if len(pubBytes) != 1+2*((curve.Params().BitSize+7)/8) {
+			continue
+		}
+
+		if _, err := jwk.ParseECPoint(curve, pubBytes); err == nil {
+			candidates = append(candidates, kt)
+		}
+	}
+
+	if _, err := btcec.ParsePubKey(pubBytes); err == nil {
+		candidates = append(candidates, kms.ECDSASecp256k1TypeIEEEP1363)
+	}
+
+	return candidates
+}
+
 // JWKFromX25519Key is similar to JWKFromKey but is specific to X25519 keys when using a public key as raw []byte.
 // This builder function presets the curve and key type in the JWK.
 // Using JWKFromKey for X25519 raw keys will not have these fields set and will not provide the right JWK output.
@@ -156,8 +401,93 @@ func JWKFromX25519Key(pubKey []byte) (*jwk.JWK, error) {
 	return key, nil
 }
 
-// PubKeyBytesToJWK converts marshalled bytes of keyType into JWK.
-func PubKeyBytesToJWK(bytes []byte, keyType kms.KeyType) (*jwk.JWK, error) {
+// JWKFromECCoordinates creates a public EC JWK from the x and y coordinates of a point of curve, which must be one of
+// elliptic.P256(), elliptic.P384(), elliptic.P521() or btcec.S256(). It fails if the point is not on the curve.
+// The JWK kty, crv and alg (ES256, ES384, ES512 or ES256K) are set and its x and y members are padded to the curve
+// field size.
+func JWKFromECCoordinates(curve elliptic.Curve, x, y *big.Int) (*jwk.JWK, error) {
+	if curve == nil || x == nil || y == nil {
+		return nil, errors.New("jwkFromECCoordinates: curve and coordinates are required")
+	}
+
+	var alg string
+
+	switch curve {
+	case elliptic.P256():
+		alg = "ES256"
+	case elliptic.P384():
+		alg = "ES384"
+	case elliptic.P521():
+		alg = "ES512"
+	case btcec.S256():
+		alg = "ES256K"
+	default:
+		return nil, fmt.Errorf("jwkFromECCoordinates: unsupported curve '%s'", curve.Params().Name)
+	}
+
+	if x.Sign() < 0 || y.Sign() < 0 || !curve.IsOnCurve(x, y) {
+		return nil, fmt.Errorf("jwkFromECCoordinates: point is not on curve %s", curve.Params().Name)
+	}
+
+	key := &jwk.JWK{
+		JSONWebKey: jose.JSONWebKey{
+			Key: &ecdsa.PublicKey{
+				Curve: curve,
+				X:     new(big.Int).Set(x),
+				Y:     new(big.Int).Set(y),
+			},
+			Algorithm: alg,
+		},
+	}
+
+	// marshal/unmarshal to get all JWK's fields other than Key filled, coordinates are padded when marshalled.
+	keyBytes, err := key.MarshalJSON()
+	if err != nil {
+		return nil, fmt.Errorf("jwkFromECCoordinates: %w", err)
+	}
+
+	err = key.UnmarshalJSON(keyBytes)
+	if err != nil {
+		return nil, fmt.Errorf("jwkFromECCoordinates: %w", err)
+	}
+
+	return key, nil
+}
+
+// PubKeyBytesToJWK converts marshalled bytes of keyType into JWK. Use the WithKID option to set the JWK kid. Key types
+// registered with RegisterKeyType are encoded by their KeyTypeHandler.
+func PubKeyBytesToJWK(bytes []byte, keyType kms.KeyType, opts ...JWKOpt) (*jwk.JWK, error) {
+	jOpts := &jwkOpts{}
+
+	for _, opt := range opts {
+		opt(jOpts)
+	}
+
+	key, err := pubKeyBytesToJWK(bytes, keyType)
+	if err != nil {
+		return nil, err
+	}
+
+	if jOpts.kidMode != nil {
+		err = jOpts.kidMode(key)
+		if err != nil {
+			return nil, fmt.Errorf("convertPubKeyJWK: failed to set kid: %w", err)
+		}
+	}
+
+	return key, nil
+}
+
+func pubKeyBytesToJWK(bytes []byte, keyType kms.KeyType) (*jwk.JWK, error) {
+	handler, ok := keyTypeHandler(keyType)
+	if !ok {
+		return nil, fmt.Errorf("convertPubKeyJWK: invalid key type: %s", keyType)
+	}
+
+	return handler.EncodeJWK(bytes)
+}
+
+func builtinPubKeyBytesToJWK(bytes []byte, keyType kms.KeyType) (*jwk.JWK, error) {
 	switch keyType {
 	case kms.ED25519Type:
 		return &jwk.JWK{
@@ -169,13 +499,28 @@ func PubKeyBytesToJWK(bytes []byte, keyType kms.KeyType) (*jwk.JWK, error) {
 		}, nil
 	case kms.X25519ECDHKWType:
 		return JWKFromX25519Key(bytes)
-	case kms.BLS12381G2Type,
+	case kms.MLDSA44Type, kms.MLDSA65Type, kms.MLDSA87Type:
+		key, err := builtinPubKeyBytesToKey(bytes, keyType)
+		if err != nil {
+			return nil, err
+		}
+
+		alg, _, _ := jwk.MLDSAParameterSet(keyType)
+
+		return &jwk.JWK{
+			JSONWebKey: jose.JSONWebKey{
+				Key:       key,
+				Algorithm: alg,
+			},
+			Kty: jwk.AKPKty,
+		}, nil
+	case kms.BLS12381G2Type, kms.BLS12381G1Type,
 		kms.ECDSASecp256k1TypeIEEEP1363, kms.ECDSASecp256k1TypeDER,
 		kms.ECDSAP256TypeIEEEP1363, kms.ECDSAP384TypeIEEEP1363, kms.ECDSAP521TypeIEEEP1363,
 		kms.ECDSAP256TypeDER, kms.ECDSAP384TypeDER, kms.ECDSAP521TypeDER,
 		kms.NISTP256ECDHKWType, kms.NISTP384ECDHKWType, kms.NISTP521ECDHKWType,
-		kms.RSARS256, kms.RSAPS256:
-		key, err := PubKeyBytesToKey(bytes, keyType)
+		kms.RSARS256, kms.RSAPS256, kms.RSA3072, kms.RSA4096:
+		key, err := builtinPubKeyBytesToKey(bytes, keyType)
 		if err != nil {
 			return nil, err
 		}
@@ -186,6 +531,12 @@ func PubKeyBytesToJWK(bytes []byte, keyType kms.KeyType) (*jwk.JWK, error) {
 	}
 }
 
+// validateKeySize checks key is large enough for keyType, see jwk.JWK.Validate.
+func validateKeySize(key interface{}, keyType kms.KeyType) error {
+	return (&jwk.JWK{JSONWebKey: jose.JSONWebKey{Key: key}}).Validate(keyType)
+}
+
+// getECDSACurve returns the curve of the EC keyType, nil if keyType is not an EC key type.
 func getECDSACurve(keyType kms.KeyType) elliptic.Curve {
 	switch keyType {
 	case kms.ECDSAP256TypeIEEEP1363, kms.ECDSAP256TypeDER, kms.NISTP256ECDHKWType:
@@ -196,9 +547,9 @@ func getECDSACurve(keyType kms.KeyType) elliptic.Curve {
 		return elliptic.P521()
 	case kms.ECDSASecp256k1TypeIEEEP1363, kms.ECDSASecp256k1TypeDER:
 		return btcec.S256()
+	default:
+		return nil
 	}
-
-	return nil
 }
 
 type publicKeyInfo struct {
@@ -229,50 +580,91 @@ func parseSecp256k1DER(keyBytes []byte) (*ecdsa.PublicKey, error) {
 	return pubKey.ToECDSA(), nil
 }
 
-// PublicKeyFromJWK builds a cryptoapi.PublicKey from jwkKey.
-func PublicKeyFromJWK(jwkKey *jwk.JWK) (*cryptoapi.PublicKey, error) {
-	if jwkKey != nil {
-		pubKey := &cryptoapi.PublicKey{
-			KID:   jwkKey.KeyID,
-			Curve: jwkKey.Crv,
-			Type:  jwkKey.Kty,
+// PublicKeyFromJWK builds a cryptoapi.PublicKey from jwkKey. JWKs of key types registered with RegisterKeyType are
+// decoded by their KeyTypeHandler. RSA keys smaller than jwk.DefaultMinRSAKeyBits, or than the size set with
+// jwk.WithMinRSAKeyBits, are rejected with an error wrapping jwk.ErrWeakKey.
+func PublicKeyFromJWK(jwkKey *jwk.JWK, opts ...jwk.ValidateOpt) (*cryptoapi.PublicKey, error) {
+	if jwkKey == nil {
+		return nil, errors.New("publicKeyFromJWK: jwk is empty")
+	}
+
+	if pub, ok := jwkKey.Public().Key.(*rsa.PublicKey); ok {
+		if err := jwk.CheckRSAKeySize(pub, jwk.MinRSAKeyBits(opts...)); err != nil {
+			return nil, fmt.Errorf("publicKeyFromJWK: %w", err)
 		}
+	}
 
-		switch key := jwkKey.Key.(type) {
-		case *ecdsa.PublicKey:
-			pubKey.X = key.X.Bytes()
-			pubKey.Y = key.Y.Bytes()
-		case *ecdsa.PrivateKey:
-			pubKey.X = key.X.Bytes()
-			pubKey.Y = key.Y.Bytes()
-		case *bbs12381g2pub.PublicKey:
-			bbsKey, _ := key.Marshal() //nolint:errcheck // bbs marshal public key does not return any error
-
-			pubKey.X = bbsKey
-		case *bbs12381g2pub.PrivateKey:
-			bbsKey, _ := key.PublicKey().Marshal() //nolint:errcheck // bbs marshal public key does not return any error
-
-			pubKey.X = bbsKey
-		case ed25519.PublicKey:
-			pubKey.X = key
-		case *rsa.PublicKey:
-			pubKey.N = key.N.Bytes()
-			pubKey.E = big.NewInt(int64(key.E)).Bytes()
-		case ed25519.PrivateKey:
-			var ok bool
-
-			pubEdKey, ok := key.Public().(ed25519.PublicKey)
-			if !ok {
-				return nil, errors.New("publicKeyFromJWK: invalid 25519 private key")
-			}
+	pubKey, err := builtinPublicKeyFromJWK(jwkKey)
+	if !errors.Is(err, ErrUnsupportedJWK) {
+		return pubKey, err
+	}
 
-			pubKey.X = pubEdKey
-		default:
-			return nil, fmt.Errorf("publicKeyFromJWK: unsupported jwk key type %T", jwkKey.Key)
+	pubKey, err = registeredPublicKeyFromJWK(jwkKey)
+	if !errors.Is(err, ErrUnsupportedJWK) {
+		return pubKey, err
+	}
+
+	return nil, fmt.Errorf("publicKeyFromJWK: unsupported jwk key type %T", jwkKey.Key)
+}
+
+func builtinPublicKeyFromJWK(jwkKey *jwk.JWK) (*cryptoapi.PublicKey, error) {
+	pubKey := &cryptoapi.PublicKey{
+		KID:   jwkKey.KeyID,
+		Curve: jwk.CanonicalCurveName(jwkKey.Crv),
+		Type:  jwkKey.Kty,
+	}
+
+	switch key := jwkKey.Key.(type) {
+	case *ecdsa.PublicKey:
+		pubKey.X, pubKey.Y = ecCoordinatesBytes(key)
+	case *ecdsa.PrivateKey:
+		pubKey.X, pubKey.Y = ecCoordinatesBytes(&key.PublicKey)
+	case *bbs12381g2pub.PublicKey:
+		bbsKey, _ := key.Marshal() //nolint:errcheck // bbs marshal public key does not return any error
+
+		pubKey.X = bbsKey
+	case *bbs12381g2pub.PrivateKey:
+		bbsKey, _ := key.PublicKey().Marshal() //nolint:errcheck // bbs marshal public key does not return any error
+
+		pubKey.X = bbsKey
+	case *ml.G1:
+		pubKey.X = key.Compressed()
+	case ed25519.PublicKey:
+		pubKey.X = key
+	case *rsa.PublicKey:
+		pubKey.N = key.N.Bytes()
+		pubKey.E = big.NewInt(int64(key.E)).Bytes()
+	case ed25519.PrivateKey:
+		var ok bool
+
+		pubEdKey, ok := key.Public().(ed25519.PublicKey)
+		if !ok {
+			return nil, errors.New("publicKeyFromJWK: invalid 25519 private key")
+		}
+
+		pubKey.X = pubEdKey
+	case []byte:
+		kt, err := jwkKey.KeyType()
+		if err != nil {
+			return nil, ErrUnsupportedJWK
 		}
 
-		return pubKey, nil
+		if _, _, ok := jwk.MLDSAParameterSet(kt); !ok {
+			return nil, ErrUnsupportedJWK
+		}
+
+		pubKey.X = key
+	default:
+		return nil, ErrUnsupportedJWK
 	}
 
-	return nil, errors.New("publicKeyFromJWK: jwk is empty")
+	return pubKey, nil
+}
+
+// ecCoordinatesBytes returns the coordinates of key left-padded to the curve field size, e.g. 66 bytes for P-521
+// whose coordinates often have a zero high-order byte.
+func ecCoordinatesBytes(key *ecdsa.PublicKey) ([]byte, []byte) {
+	size := (key.Curve.Params().BitSize + 7) / 8 //nolint:gomnd
+
+	return key.X.FillBytes(make([]byte, size)), key.Y.FillBytes(make([]byte, size))
 }
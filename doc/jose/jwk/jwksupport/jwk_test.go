@@ -7,6 +7,8 @@ SPDX-License-Identifier: Apache-2.0
 package jwksupport
 
 import (
+	"crypto"
+	"crypto/ecdh"
 	"crypto/ecdsa"
 	"crypto/ed25519"
 	"crypto/elliptic"
@@ -19,9 +21,12 @@ import (
 	"encoding/base64"
 	"encoding/json"
 	"fmt"
+	"math/big"
 	"strings"
 	"testing"
+	"time"
 
+	ml "github.com/IBM/mathlib"
 	"github.com/btcsuite/btcd/btcec/v2"
 	"github.com/go-jose/go-jose/v3"
 	"github.com/stretchr/testify/require"
@@ -503,6 +508,24 @@ func TestPubKeyBytesToKey(t *testing.T) {
 		}
 	}
 
+	t.Run("IEEE P1363 X9.62 point encodings", func(t *testing.T) {
+		for _, keyType := range []kms.KeyType{kms.ECDSAP256TypeIEEEP1363, kms.ECDSASecp256k1TypeIEEEP1363} {
+			privKey, err := ecdsa.GenerateKey(getECDSACurve(keyType), rand.Reader)
+			require.NoError(t, err)
+
+			encodings := []jwk.ECPointEncoding{jwk.ECPointUncompressed, jwk.ECPointCompressed, jwk.ECPointHybrid}
+
+			for _, encoding := range encodings {
+				pkb, err := (&jwk.JWK{JSONWebKey: jose.JSONWebKey{Key: privKey}}).PublicKeyBytesIn(encoding)
+				require.NoError(t, err)
+
+				pk, err := PubKeyBytesToKey(pkb, keyType)
+				require.NoError(t, err)
+				require.True(t, privKey.PublicKey.Equal(pk))
+			}
+		}
+	})
+
 	t.Run("Secp256k1DER parse errors", func(t *testing.T) {
 		t.Run("asn.1 data invalid", func(t *testing.T) {
 			pkb := []byte("foo bar baz")
@@ -600,6 +623,10 @@ func TestPubKeyBytesToJWK(t *testing.T) {
 			name:    "BLS12381G2 test",
 			keyType: kms.BLS12381G2Type,
 		},
+		{
+			name:    "BLS12381G1 test",
+			keyType: kms.BLS12381G1Type,
+		},
 		{
 			name:    "X25519 test",
 			keyType: kms.X25519ECDHKWType,
@@ -652,6 +679,35 @@ func TestPubKeyBytesToJWK(t *testing.T) {
 
 				_, err = PubKeyBytesToJWK([]byte("invalidbbsKey"), tc.keyType)
 				require.EqualError(t, err, "invalid size of public key")
+			case kms.BLS12381G1Type:
+				keyBytes := newBLS12381G1PubKey(t).Compressed()
+
+				jwkKey, err := PubKeyBytesToJWK(keyBytes, tc.keyType)
+				require.NoError(t, err)
+				require.NotEmpty(t, jwkKey)
+				require.Equal(t, ecKty, jwkKey.Kty)
+				require.Equal(t, bls12381G1Crv, jwkKey.Crv)
+
+				kt, err := jwkKey.KeyType()
+				require.NoError(t, err)
+				require.Equal(t, kms.BLS12381G1Type, kt)
+
+				pubKeyBytes, err := jwkKey.PublicKeyBytes()
+				require.NoError(t, err)
+				require.Equal(t, keyBytes, pubKeyBytes)
+
+				pubKey, err := PublicKeyFromJWK(jwkKey)
+				require.NoError(t, err)
+				require.Equal(t, keyBytes, pubKey.X)
+				require.Len(t, pubKey.X, bls12381G1Size)
+
+				_, err = PubKeyBytesToJWK([]byte("invalidbbsKey"), tc.keyType)
+				require.EqualError(t, err, "invalid size of public key")
+
+				var jwkErr *jwk.JWKError
+				require.ErrorAs(t, err, &jwkErr)
+				require.Equal(t, "x", jwkErr.Field)
+				require.Equal(t, "must be 48 bytes, got 13", jwkErr.Reason)
 			case kms.ECDSAP256TypeIEEEP1363, kms.ECDSAP384TypeIEEEP1363, kms.ECDSAP521TypeIEEEP1363:
 				crv := getECDSACurve(tc.keyType)
 				privKey, err := ecdsa.GenerateKey(crv, rand.Reader)
@@ -736,6 +792,18 @@ func TestEmptyCurve(t *testing.T) {
 	require.Empty(t, crv)
 }
 
+func TestGetECDSACurve(t *testing.T) {
+	for kt, curve := range map[kms.KeyType]elliptic.Curve{
+		kms.ECDSAP256TypeIEEEP1363:      elliptic.P256(),
+		kms.ECDSAP384TypeDER:            elliptic.P384(),
+		kms.NISTP521ECDHKWType:          elliptic.P521(),
+		kms.ECDSASecp256k1TypeIEEEP1363: btcec.S256(),
+		kms.ED25519Type:                 nil,
+	} {
+		require.Equal(t, curve, getECDSACurve(kt), kt)
+	}
+}
+
 func TestPublicKeyFromJWK(t *testing.T) {
 	prv256Key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
 	require.NoError(t, err)
@@ -782,6 +850,9 @@ func TestPublicKeyFromJWK(t *testing.T) {
 	jwkBLSPubKey, err := JWKFromKey(bbsPubKey)
 	require.NoError(t, err)
 
+	jwkBLSG1PubKey, err := JWKFromKey(newBLS12381G1PubKey(t))
+	require.NoError(t, err)
+
 	tests := []struct {
 		name   string
 		jwkKey *jwk.JWK
@@ -826,6 +897,10 @@ func TestPublicKeyFromJWK(t *testing.T) {
 			name:   "success BBS key from JWK with public key",
 			jwkKey: jwkBLSPubKey,
 		},
+		{
+			name:   "success BLS12381 G1 key from JWK with public key",
+			jwkKey: jwkBLSG1PubKey,
+		},
 		{
 			name: "fail invalid key type",
 			jwkKey: &jwk.JWK{
@@ -854,10 +929,43 @@ func TestPublicKeyFromJWK(t *testing.T) {
 		})
 	}
 
+	t.Run("success with secp256k1 curve alias", func(t *testing.T) {
+		secp256k1Key, err := ecdsa.GenerateKey(btcec.S256(), rand.Reader)
+		require.NoError(t, err)
+
+		pubKey, err := PublicKeyFromJWK(&jwk.JWK{
+			JSONWebKey: jose.JSONWebKey{Key: &secp256k1Key.PublicKey},
+			Kty:        "EC",
+			Crv:        "P-256K",
+		})
+		require.NoError(t, err)
+		require.Equal(t, "secp256k1", pubKey.Curve)
+	})
+
 	t.Run("failure with empty jwk", func(t *testing.T) {
 		_, err = PublicKeyFromJWK(nil)
 		require.EqualError(t, err, "publicKeyFromJWK: jwk is empty")
 	})
+
+	t.Run("minimum RSA key size", func(t *testing.T) {
+		rsaKey, err := rsa.GenerateKey(rand.Reader, 2048)
+		require.NoError(t, err)
+
+		rsaJWK := &jwk.JWK{JSONWebKey: jose.JSONWebKey{Key: &rsaKey.PublicKey}, Kty: "RSA"}
+
+		_, err = PublicKeyFromJWK(rsaJWK)
+		require.NoError(t, err)
+
+		_, err = PublicKeyFromJWK(rsaJWK, jwk.WithMinRSAKeyBits(3072))
+		require.ErrorIs(t, err, jwk.ErrWeakKey)
+		require.EqualError(t, err, "publicKeyFromJWK: weak key: RSA key size 2048 bits is below the 3072 bits minimum")
+
+		smallKey, err := rsa.GenerateKey(rand.Reader, 1024)
+		require.NoError(t, err)
+
+		_, err = PublicKeyFromJWK(&jwk.JWK{JSONWebKey: jose.JSONWebKey{Key: smallKey}, Kty: "RSA"})
+		require.ErrorIs(t, err, jwk.ErrWeakKey)
+	})
 }
 
 func TestRSAKeyFailParse(t *testing.T) {
@@ -882,6 +990,250 @@ func TestRSAKey(t *testing.T) {
 	require.NotNil(t, pb.N)
 	require.NotNil(t, pb.E)
 	require.Equal(t, "RSA", pb.Type)
+
+	t.Run("PublicKeyBytes round trip", func(t *testing.T) {
+		for _, k := range []interface{}{key, &key.PublicKey} {
+			keyBytes, err := (&jwk.JWK{JSONWebKey: jose.JSONWebKey{Key: k}}).PublicKeyBytes()
+			require.NoError(t, err)
+			require.Equal(t, pubBytes, keyBytes)
+
+			roundTrip, err := PubKeyBytesToJWK(keyBytes, kms.RSARS256)
+			require.NoError(t, err)
+
+			rsaPubKey, ok := roundTrip.Key.(*rsa.PublicKey)
+			require.True(t, ok)
+			require.Zero(t, key.N.Cmp(rsaPubKey.N))
+			require.Equal(t, key.E, rsaPubKey.E)
+		}
+	})
+
+	t.Run("error key below the size of the key type", func(t *testing.T) {
+		_, err = PubKeyBytesToJWK(pubBytes, kms.RSA3072)
+		require.EqualError(t, err, "rsa: validate: weak key: RSA key size is below the 3072 bits required by key type RSA3072")
+
+		var jwkErr *jwk.JWKError
+		require.ErrorAs(t, err, &jwkErr)
+		require.Equal(t, "n", jwkErr.Field)
+	})
+}
+
+func TestFromCertificate(t *testing.T) {
+	t.Run("success", func(t *testing.T) {
+		ecKey, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+		require.NoError(t, err)
+
+		rsaKey, err := rsa.GenerateKey(rand.Reader, 2048)
+		require.NoError(t, err)
+
+		edPub, edKey, err := ed25519.GenerateKey(rand.Reader)
+		require.NoError(t, err)
+
+		tests := []struct {
+			name    string
+			pubKey  interface{}
+			privKey interface{}
+			keyType kms.KeyType
+		}{
+			{name: "ECDSA P-256", pubKey: &ecKey.PublicKey, privKey: ecKey, keyType: kms.ECDSAP256TypeIEEEP1363},
+			{name: "RSA", pubKey: &rsaKey.PublicKey, privKey: rsaKey, keyType: kms.RSAPS256Type},
+			{name: "Ed25519", pubKey: edPub, privKey: edKey, keyType: kms.ED25519Type},
+		}
+
+		for _, tc := range tests {
+			t.Run(tc.name, func(t *testing.T) {
+				cert := createCertificate(t, tc.pubKey, tc.privKey)
+
+				key, err := FromCertificate(cert)
+				require.NoError(t, err)
+				require.Equal(t, tc.pubKey, key.Key)
+				require.Equal(t, []*x509.Certificate{cert}, key.Certificates)
+
+				thumbprint := sha256.Sum256(cert.Raw)
+				require.Equal(t, thumbprint[:], key.X509CertThumbprintS256)
+
+				kt, err := key.KeyType()
+				require.NoError(t, err)
+				require.Equal(t, tc.keyType, kt)
+
+				jwkBytes, err := key.MarshalJSON()
+				require.NoError(t, err)
+				require.Contains(t, string(jwkBytes), `"x5c"`)
+				require.Contains(t, string(jwkBytes), `"x5t#S256"`)
+			})
+		}
+	})
+
+	t.Run("success secp256k1", func(t *testing.T) {
+		privKey, err := ecdsa.GenerateKey(btcec.S256(), rand.Reader)
+		require.NoError(t, err)
+
+		spki, err := marshalSecp256k1DER(&privKey.PublicKey)
+		require.NoError(t, err)
+
+		cert := &x509.Certificate{
+			Raw:                     []byte("raw certificate"),
+			RawSubjectPublicKeyInfo: spki,
+		}
+
+		key, err := FromCertificate(cert)
+		require.NoError(t, err)
+		require.Equal(t, []*x509.Certificate{cert}, key.Certificates)
+
+		kt, err := key.KeyType()
+		require.NoError(t, err)
+		require.Equal(t, kms.ECDSASecp256k1TypeIEEEP1363, kt)
+
+		ecKey, ok := key.Key.(*ecdsa.PublicKey)
+		require.True(t, ok)
+		require.Equal(t, privKey.X, ecKey.X)
+		require.Equal(t, privKey.Y, ecKey.Y)
+	})
+
+	t.Run("failure", func(t *testing.T) {
+		key, err := FromCertificate(nil)
+		require.EqualError(t, err, "fromCertificate: certificate is empty")
+		require.Nil(t, key)
+
+		key, err = FromCertificate(&x509.Certificate{PublicKey: "bad key"})
+		require.EqualError(t, err, "fromCertificate: unsupported public key type string")
+		require.Nil(t, key)
+
+		key, err = FromCertificate(&x509.Certificate{RawSubjectPublicKeyInfo: []byte("bad key")})
+		require.ErrorContains(t, err, "fromCertificate: failed to parse secp256k1 public key")
+		require.Nil(t, key)
+	})
+}
+
+func createCertificate(t *testing.T, pubKey, privKey interface{}) *x509.Certificate {
+	t.Helper()
+
+	template := &x509.Certificate{
+		SerialNumber: big.NewInt(1),
+		Subject:      pkix.Name{CommonName: "test"},
+		NotBefore:    time.Now().Add(-time.Hour),
+		NotAfter:     time.Now().Add(time.Hour),
+	}
+
+	certDER, err := x509.CreateCertificate(rand.Reader, template, template, pubKey, privKey)
+	require.NoError(t, err)
+
+	cert, err := x509.ParseCertificate(certDER)
+	require.NoError(t, err)
+
+	return cert
+}
+
+func TestDetectKeyType(t *testing.T) {
+	p256Key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+
+	p256DER, err := x509.MarshalPKIXPublicKey(&p256Key.PublicKey)
+	require.NoError(t, err)
+
+	p384Key, err := ecdsa.GenerateKey(elliptic.P384(), rand.Reader)
+	require.NoError(t, err)
+
+	secp256k1Key, err := ecdsa.GenerateKey(btcec.S256(), rand.Reader)
+	require.NoError(t, err)
+
+	secp256k1DER, err := marshalSecp256k1DER(&secp256k1Key.PublicKey)
+	require.NoError(t, err)
+
+	secp256k1Compressed, err := (&jwk.JWK{JSONWebKey: jose.JSONWebKey{Key: &secp256k1Key.PublicKey}}).PublicKeyBytes()
+	require.NoError(t, err)
+
+	rsaKey, err := rsa.GenerateKey(rand.Reader, 2048)
+	require.NoError(t, err)
+
+	rsaDER, err := x509.MarshalPKIXPublicKey(&rsaKey.PublicKey)
+	require.NoError(t, err)
+
+	edPubKey, _, err := ed25519.GenerateKey(rand.Reader)
+	require.NoError(t, err)
+
+	bbsPubKey, _, err := bbs12381g2pub.GenerateKeyPair(sha256.New, nil)
+	require.NoError(t, err)
+
+	bbsPubKeyBytes, err := bbsPubKey.Marshal()
+	require.NoError(t, err)
+
+	t.Run("success unambiguous key types", func(t *testing.T) {
+		tests := []struct {
+			name     string
+			keyBytes []byte
+			keyType  kms.KeyType
+		}{
+			{
+				name:     "P-256 DER",
+				keyBytes: p256DER,
+				keyType:  kms.ECDSAP256TypeDER,
+			},
+			{
+				name:     "P-384 IEEE1363",
+				keyBytes: elliptic.Marshal(elliptic.P384(), p384Key.X, p384Key.Y),
+				keyType:  kms.ECDSAP384TypeIEEEP1363,
+			},
+			{
+				name:     "secp256k1 DER",
+				keyBytes: secp256k1DER,
+				keyType:  kms.ECDSASecp256k1TypeDER,
+			},
+			{
+				name:     "secp256k1 compressed",
+				keyBytes: secp256k1Compressed,
+				keyType:  kms.ECDSASecp256k1TypeIEEEP1363,
+			},
+			{
+				name:     "BLS12381G2",
+				keyBytes: bbsPubKeyBytes,
+				keyType:  kms.BLS12381G2Type,
+			},
+			{
+				name:     "BLS12381G1",
+				keyBytes: newBLS12381G1PubKey(t).Compressed(),
+				keyType:  kms.BLS12381G1Type,
+			},
+		}
+
+		for _, tc := range tests {
+			t.Run(tc.name, func(t *testing.T) {
+				kt, candidates, err := DetectKeyType(tc.keyBytes)
+				require.NoError(t, err)
+				require.Equal(t, tc.keyType, kt)
+				require.Equal(t, []kms.KeyType{tc.keyType}, candidates)
+
+				_, err = PubKeyBytesToKey(tc.keyBytes, kt)
+				require.NoError(t, err)
+			})
+		}
+	})
+
+	t.Run("success ambiguous key types", func(t *testing.T) {
+		kt, candidates, err := DetectKeyType(edPubKey)
+		require.NoError(t, err)
+		require.Empty(t, kt)
+		require.Equal(t, []kms.KeyType{kms.ED25519Type, kms.X25519ECDHKWType}, candidates)
+
+		kt, candidates, err = DetectKeyType(rsaDER)
+		require.NoError(t, err)
+		require.Empty(t, kt)
+		require.Equal(t, []kms.KeyType{kms.RSARS256Type, kms.RSAPS256Type}, candidates)
+	})
+
+	t.Run("failure unrecognized key bytes", func(t *testing.T) {
+		kt, candidates, err := DetectKeyType([]byte("invalid key"))
+		require.EqualError(t, err, "detectKeyType: unrecognized public key bytes")
+		require.Empty(t, kt)
+		require.Empty(t, candidates)
+	})
+}
+
+func newBLS12381G1PubKey(t *testing.T) *ml.G1 {
+	t.Helper()
+
+	curve := ml.Curves[ml.BLS12_381_BBS]
+
+	return curve.GenG1.Mul(curve.NewRandomZr(rand.Reader))
 }
 
 type PublicKeyInfo struct {
@@ -912,3 +1264,605 @@ func marshalSecp256k1DER(pub *ecdsa.PublicKey) ([]byte, error) {
 
 	return out, nil
 }
+
+func TestJWKFromKeyEd25519Size(t *testing.T) {
+	pubKey, privKey, err := ed25519.GenerateKey(rand.Reader)
+	require.NoError(t, err)
+
+	t.Run("seed", func(t *testing.T) {
+		key, err := JWKFromKey(ed25519.PrivateKey(privKey.Seed()))
+		require.NoError(t, err)
+		require.Equal(t, privKey, key.Key)
+	})
+
+	for _, size := range []int{31, 33} {
+		_, err = JWKFromKey(ed25519.PrivateKey(make([]byte, size)))
+		require.EqualError(t, err, fmt.Sprintf(
+			"create JWK: invalid Ed25519 private key size %d: expected 64 bytes or a 32 bytes seed", size))
+
+		_, err = JWKFromKey(ed25519.PublicKey(make([]byte, size)))
+		require.EqualError(t, err, fmt.Sprintf(
+			"create JWK: invalid Ed25519 public key size %d: expected 32 bytes", size))
+	}
+
+	_, err = JWKFromKey(privKey[:ed25519.PrivateKeySize-1])
+	require.ErrorContains(t, err, "invalid Ed25519 private key size 63")
+
+	key, err := JWKFromKey(pubKey)
+	require.NoError(t, err)
+	require.Equal(t, pubKey, key.Key)
+}
+
+func TestJWKFromKeyBTCEC(t *testing.T) {
+	privKey, err := btcec.NewPrivateKey()
+	require.NoError(t, err)
+
+	t.Run("private key", func(t *testing.T) {
+		key, err := JWKFromKey(privKey)
+		require.NoError(t, err)
+		require.Equal(t, "EC", key.Kty)
+		require.Equal(t, "secp256k1", key.Crv)
+		require.False(t, key.IsPublic())
+		require.Equal(t, privKey.ToECDSA(), key.Key)
+
+		jwkBytes, err := key.MarshalJSON()
+		require.NoError(t, err)
+		require.Contains(t, string(jwkBytes), `"d":`)
+	})
+
+	t.Run("public key", func(t *testing.T) {
+		key, err := JWKFromKey(privKey.PubKey())
+		require.NoError(t, err)
+		require.Equal(t, "secp256k1", key.Crv)
+		require.True(t, key.IsPublic())
+		require.Equal(t, privKey.PubKey().ToECDSA(), key.Key)
+
+		ecdsaKey, err := JWKFromKey(privKey.PubKey().ToECDSA())
+		require.NoError(t, err)
+		require.Equal(t, ecdsaKey, key)
+	})
+
+	t.Run("nil keys", func(t *testing.T) {
+		_, err := JWKFromKey((*btcec.PrivateKey)(nil))
+		require.EqualError(t, err, "create JWK: secp256k1 private key is nil")
+
+		_, err = JWKFromKey((*btcec.PublicKey)(nil))
+		require.EqualError(t, err, "create JWK: secp256k1 public key is nil")
+	})
+}
+
+func TestP521CoordinatesLength(t *testing.T) {
+	const coordinateSize = 66
+
+	requireCoordinates := func(t *testing.T, key *jwk.JWK) {
+		t.Helper()
+
+		jwkBytes, err := key.MarshalJSON()
+		require.NoError(t, err)
+
+		var members struct {
+			X string `json:"x"`
+			Y string `json:"y"`
+		}
+
+		require.NoError(t, json.Unmarshal(jwkBytes, &members))
+
+		for _, c := range []string{members.X, members.Y} {
+			coordinate, err := base64.RawURLEncoding.DecodeString(c)
+			require.NoError(t, err)
+			require.Len(t, coordinate, coordinateSize)
+		}
+
+		pubKey, err := PublicKeyFromJWK(key)
+		require.NoError(t, err)
+		require.Len(t, pubKey.X, coordinateSize)
+		require.Len(t, pubKey.Y, coordinateSize)
+	}
+
+	// about half of the P-521 coordinates have a zero high-order byte.
+	for i := 0; i < 32; i++ {
+		privKey, err := ecdsa.GenerateKey(elliptic.P521(), rand.Reader)
+		require.NoError(t, err)
+
+		key, err := JWKFromKey(privKey)
+		require.NoError(t, err)
+		requireCoordinates(t, key)
+
+		key, err = JWKFromKey(&privKey.PublicKey)
+		require.NoError(t, err)
+		requireCoordinates(t, key)
+
+		key, err = PubKeyBytesToJWK(elliptic.Marshal(elliptic.P521(), privKey.X, privKey.Y),
+			kms.ECDSAP521TypeIEEEP1363)
+		require.NoError(t, err)
+		requireCoordinates(t, key)
+
+		derKey, err := x509.MarshalPKIXPublicKey(&privKey.PublicKey)
+		require.NoError(t, err)
+
+		key, err = PubKeyBytesToJWK(derKey, kms.ECDSAP521TypeDER)
+		require.NoError(t, err)
+		requireCoordinates(t, key)
+	}
+}
+
+func TestJWKFromKeyWithKID(t *testing.T) {
+	t.Run("RFC 8037 Ed25519 thumbprint", func(t *testing.T) {
+		pubKey, err := base64.RawURLEncoding.DecodeString("11qYAYKxCrfVS_7TyWQHOg7hcvPapiMlrwIaaPcHURo")
+		require.NoError(t, err)
+
+		key, err := JWKFromKeyWithKID(ed25519.PublicKey(pubKey))
+		require.NoError(t, err)
+		require.Equal(t, "kPrK_qmxVWaYVA9wwBF6Iuo3vVzz7TxHCTwXBygrS4k", key.KeyID)
+
+		key, err = JWKFromKey(ed25519.PublicKey(pubKey))
+		require.NoError(t, err)
+		require.Empty(t, key.KeyID)
+	})
+
+	t.Run("private key kid is the thumbprint of its public key", func(t *testing.T) {
+		privKey, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+		require.NoError(t, err)
+
+		privJWK, err := JWKFromKeyWithKID(privKey)
+		require.NoError(t, err)
+
+		pubJWK, err := JWKFromKeyWithKID(&privKey.PublicKey)
+		require.NoError(t, err)
+		require.Equal(t, pubJWK.KeyID, privJWK.KeyID)
+
+		tp, err := pubJWK.JSONWebKey.Thumbprint(crypto.SHA256)
+		require.NoError(t, err)
+		require.Equal(t, base64.RawURLEncoding.EncodeToString(tp), pubJWK.KeyID)
+	})
+
+	t.Run("unsupported key", func(t *testing.T) {
+		_, err := JWKFromKeyWithKID("not a key")
+		require.ErrorContains(t, err, "create JWK")
+	})
+}
+
+func TestPubKeyBytesToJWKWithKID(t *testing.T) {
+	t.Run("RFC 8037 Ed25519 thumbprint", func(t *testing.T) {
+		// test vector from https://tools.ietf.org/html/rfc8037#appendix-A.3
+		pubKey, err := base64.RawURLEncoding.DecodeString("11qYAYKxCrfVS_7TyWQHOg7hcvPapiMlrwIaaPcHURo")
+		require.NoError(t, err)
+
+		key, err := PubKeyBytesToJWK(pubKey, kms.ED25519Type, WithKID(KIDThumbprint))
+		require.NoError(t, err)
+		require.Equal(t, "kPrK_qmxVWaYVA9wwBF6Iuo3vVzz7TxHCTwXBygrS4k", key.KeyID)
+	})
+
+	t.Run("thumbprint matches go-jose thumbprint", func(t *testing.T) {
+		for _, keyType := range []kms.KeyType{kms.ECDSAP256TypeIEEEP1363, kms.ECDSAP384TypeDER, kms.RSAPS256} {
+			pubKeyBytes := getPublicKeyBytes(t, keyType)
+
+			key, err := PubKeyBytesToJWK(pubKeyBytes, keyType, WithKID(KIDThumbprint))
+			require.NoError(t, err)
+
+			tp, err := key.JSONWebKey.Thumbprint(crypto.SHA256)
+			require.NoError(t, err)
+			require.Equal(t, base64.RawURLEncoding.EncodeToString(tp), key.KeyID)
+		}
+	})
+
+	t.Run("thumbprint of keys not supported by go-jose", func(t *testing.T) {
+		x25519Key := make([]byte, cryptoutil.Curve25519KeySize)
+		_, err := rand.Read(x25519Key)
+		require.NoError(t, err)
+
+		key, err := PubKeyBytesToJWK(x25519Key, kms.X25519ECDHKWType, WithKID(KIDThumbprint))
+		require.NoError(t, err)
+
+		tp := sha256.Sum256([]byte(fmt.Sprintf(`{"crv":"X25519","kty":"OKP","x":"%s"}`,
+			base64.RawURLEncoding.EncodeToString(x25519Key))))
+		require.Equal(t, base64.RawURLEncoding.EncodeToString(tp[:]), key.KeyID)
+
+		for _, keyType := range []kms.KeyType{kms.ECDSASecp256k1TypeIEEEP1363, kms.BLS12381G2Type} {
+			key, err = PubKeyBytesToJWK(getPublicKeyBytes(t, keyType), keyType, WithKID(KIDThumbprint))
+			require.NoError(t, err)
+			require.NotEmpty(t, key.KeyID)
+		}
+	})
+
+	t.Run("explicit kid value", func(t *testing.T) {
+		key, err := PubKeyBytesToJWK(getPublicKeyBytes(t, kms.ED25519Type), kms.ED25519Type, WithKID(KIDValue("key-1")))
+		require.NoError(t, err)
+		require.Equal(t, "key-1", key.KeyID)
+	})
+
+	t.Run("empty kid by default", func(t *testing.T) {
+		key, err := PubKeyBytesToJWK(getPublicKeyBytes(t, kms.ED25519Type), kms.ED25519Type)
+		require.NoError(t, err)
+		require.Empty(t, key.KeyID)
+	})
+
+	t.Run("error thumbprint of unsupported key type", func(t *testing.T) {
+		_, err := Thumbprint(&jwk.JWK{JSONWebKey: jose.JSONWebKey{Key: []byte("secret")}})
+		require.EqualError(t, err, "thumbprint: unsupported key type 'oct'")
+	})
+}
+
+func getPublicKeyBytes(t *testing.T, keyType kms.KeyType) []byte {
+	t.Helper()
+
+	switch keyType {
+	case kms.ED25519Type:
+		pubKey, _, err := ed25519.GenerateKey(rand.Reader)
+		require.NoError(t, err)
+
+		return pubKey
+	case kms.ECDSAP256TypeIEEEP1363:
+		privKey, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+		require.NoError(t, err)
+
+		return elliptic.Marshal(privKey.Curve, privKey.X, privKey.Y)
+	case kms.ECDSAP384TypeDER:
+		privKey, err := ecdsa.GenerateKey(elliptic.P384(), rand.Reader)
+		require.NoError(t, err)
+
+		keyBytes, err := x509.MarshalPKIXPublicKey(&privKey.PublicKey)
+		require.NoError(t, err)
+
+		return keyBytes
+	case kms.RSAPS256:
+		privKey, err := rsa.GenerateKey(rand.Reader, 2048)
+		require.NoError(t, err)
+
+		keyBytes, err := x509.MarshalPKIXPublicKey(&privKey.PublicKey)
+		require.NoError(t, err)
+
+		return keyBytes
+	case kms.ECDSASecp256k1TypeIEEEP1363:
+		privKey, err := btcec.NewPrivateKey()
+		require.NoError(t, err)
+
+		return privKey.PubKey().SerializeUncompressed()
+	case kms.BLS12381G2Type:
+		pubKey, _, err := bbs12381g2pub.GenerateKeyPair(sha256.New, nil)
+		require.NoError(t, err)
+
+		keyBytes, err := pubKey.Marshal()
+		require.NoError(t, err)
+
+		return keyBytes
+	}
+
+	require.Failf(t, "unsupported key type", "%s", keyType)
+
+	return nil
+}
+
+func TestPublicKeyToStdPublicKey(t *testing.T) {
+	p256Key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+
+	p384Key, err := ecdsa.GenerateKey(elliptic.P384(), rand.Reader)
+	require.NoError(t, err)
+
+	p521Key, err := ecdsa.GenerateKey(elliptic.P521(), rand.Reader)
+	require.NoError(t, err)
+
+	secp256k1Key, err := ecdsa.GenerateKey(btcec.S256(), rand.Reader)
+	require.NoError(t, err)
+
+	rsaKey, err := rsa.GenerateKey(rand.Reader, 2048)
+	require.NoError(t, err)
+
+	edPubKey, _, err := ed25519.GenerateKey(rand.Reader)
+	require.NoError(t, err)
+
+	tests := []struct {
+		name string
+		key  crypto.PublicKey
+	}{
+		{name: "P-256", key: &p256Key.PublicKey},
+		{name: "P-384", key: &p384Key.PublicKey},
+		{name: "P-521", key: &p521Key.PublicKey},
+		{name: "secp256k1", key: &secp256k1Key.PublicKey},
+		{name: "RSA", key: &rsaKey.PublicKey},
+		{name: "Ed25519", key: edPubKey},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			jwkKey, err := JWKFromKey(tc.key)
+			require.NoError(t, err)
+
+			pubKey, err := PublicKeyFromJWK(jwkKey)
+			require.NoError(t, err)
+
+			stdKey, err := pubKey.ToStdPublicKey()
+			require.NoError(t, err)
+			require.Equal(t, tc.key, stdKey)
+
+			roundTripJWK, err := JWKFromKey(stdKey)
+			require.NoError(t, err)
+			require.Equal(t, jwkKey.Crv, roundTripJWK.Crv)
+			require.Equal(t, jwkKey.Kty, roundTripJWK.Kty)
+		})
+	}
+
+	t.Run("X25519", func(t *testing.T) {
+		x25519Key, err := ecdh.X25519().GenerateKey(rand.Reader)
+		require.NoError(t, err)
+
+		pubKey := &cryptoapi.PublicKey{X: x25519Key.PublicKey().Bytes(), Curve: "X25519", Type: "OKP"}
+
+		stdKey, err := pubKey.ToStdPublicKey()
+		require.NoError(t, err)
+		require.True(t, x25519Key.PublicKey().Equal(stdKey))
+	})
+
+	t.Run("tink curve names", func(t *testing.T) {
+		pubKey := &cryptoapi.PublicKey{
+			X:     p256Key.X.Bytes(),
+			Y:     p256Key.Y.Bytes(),
+			Curve: "NIST_P256",
+			Type:  "EC",
+		}
+
+		stdKey, err := pubKey.ToStdPublicKey()
+		require.NoError(t, err)
+		require.Equal(t, &p256Key.PublicKey, stdKey)
+	})
+
+	t.Run("errors", func(t *testing.T) {
+		tests := []struct {
+			name   string
+			pubKey *cryptoapi.PublicKey
+			err    string
+		}{
+			{
+				name: "nil public key",
+				err:  "toStdPublicKey: public key is nil",
+			},
+			{
+				name:   "point not on curve",
+				pubKey: &cryptoapi.PublicKey{X: p256Key.X.Bytes(), Y: p384Key.Y.Bytes(), Curve: "P-256", Type: "EC"},
+				err:    "toStdPublicKey: point is not on curve P-256",
+			},
+			{
+				name:   "invalid secp256k1 point",
+				pubKey: &cryptoapi.PublicKey{X: p256Key.X.Bytes(), Y: p256Key.Y.Bytes(), Curve: "secp256k1", Type: "EC"},
+				err:    "toStdPublicKey: invalid secp256k1 public key",
+			},
+			{
+				name:   "unsupported curve",
+				pubKey: &cryptoapi.PublicKey{X: []byte{1}, Curve: "BLS12381_G2", Type: "EC"},
+				err:    "toStdPublicKey: unsupported curve 'BLS12381_G2'",
+			},
+			{
+				name:   "invalid Ed25519 key size",
+				pubKey: &cryptoapi.PublicKey{X: []byte{1, 2, 3}, Curve: "Ed25519", Type: "OKP"},
+				err:    "toStdPublicKey: invalid Ed25519 public key size 3",
+			},
+			{
+				name:   "invalid X25519 key size",
+				pubKey: &cryptoapi.PublicKey{X: []byte{1, 2, 3}, Curve: "X25519", Type: "OKP"},
+				err:    "toStdPublicKey: invalid X25519 public key",
+			},
+			{
+				name:   "RSA key without exponent",
+				pubKey: &cryptoapi.PublicKey{N: rsaKey.N.Bytes(), Type: "RSA"},
+				err:    "toStdPublicKey: RSA public key is missing modulus or exponent",
+			},
+			{
+				name:   "RSA key with invalid exponent",
+				pubKey: &cryptoapi.PublicKey{N: rsaKey.N.Bytes(), E: []byte{1}, Type: "RSA"},
+				err:    "toStdPublicKey: invalid RSA public exponent",
+			},
+		}
+
+		for _, tc := range tests {
+			t.Run(tc.name, func(t *testing.T) {
+				_, err := tc.pubKey.ToStdPublicKey()
+				require.ErrorContains(t, err, tc.err)
+			})
+		}
+	})
+}
+
+func TestJWK_SamePublicKey(t *testing.T) {
+	t.Run("EC key derived from IEEE P1363 and DER encodings", func(t *testing.T) {
+		var privKey *ecdsa.PrivateKey
+
+		// find a key with a leading zero byte in its X coordinate to exercise the padding normalization.
+		for privKey == nil || privKey.X.BitLen() > 248 {
+			var err error
+
+			privKey, err = ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+			require.NoError(t, err)
+		}
+
+		ieeeJWK, err := PubKeyBytesToJWK(elliptic.Marshal(privKey.Curve, privKey.X, privKey.Y),
+			kms.ECDSAP256TypeIEEEP1363)
+		require.NoError(t, err)
+
+		derBytes, err := x509.MarshalPKIXPublicKey(&privKey.PublicKey)
+		require.NoError(t, err)
+
+		derJWK, err := PubKeyBytesToJWK(derBytes, kms.ECDSAP256TypeDER)
+		require.NoError(t, err)
+
+		require.True(t, ieeeJWK.SamePublicKey(derJWK))
+		require.True(t, derJWK.SamePublicKey(ieeeJWK))
+
+		// coordinates without padding.
+		unpaddedJWK := &jwk.JWK{JSONWebKey: jose.JSONWebKey{Key: &ecdsa.PublicKey{
+			Curve: elliptic.P256(),
+			X:     new(big.Int).SetBytes(privKey.X.Bytes()),
+			Y:     new(big.Int).SetBytes(privKey.Y.Bytes()),
+		}}}
+		require.True(t, ieeeJWK.SamePublicKey(unpaddedJWK))
+
+		privJWK, err := JWKFromKey(privKey)
+		require.NoError(t, err)
+		require.True(t, privJWK.SamePublicKey(derJWK))
+
+		otherKey, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+		require.NoError(t, err)
+
+		otherJWK, err := JWKFromKey(&otherKey.PublicKey)
+		require.NoError(t, err)
+		require.False(t, otherJWK.SamePublicKey(derJWK))
+
+		p384Key, err := ecdsa.GenerateKey(elliptic.P384(), rand.Reader)
+		require.NoError(t, err)
+
+		p384JWK, err := JWKFromKey(&p384Key.PublicKey)
+		require.NoError(t, err)
+		require.False(t, p384JWK.SamePublicKey(derJWK))
+	})
+
+	t.Run("other key types", func(t *testing.T) {
+		edPub, edPriv, err := ed25519.GenerateKey(rand.Reader)
+		require.NoError(t, err)
+
+		edJWK, err := JWKFromKey(edPub)
+		require.NoError(t, err)
+
+		edPrivJWK, err := JWKFromKey(edPriv)
+		require.NoError(t, err)
+		require.True(t, edJWK.SamePublicKey(edPrivJWK))
+
+		x25519JWK, err := JWKFromX25519Key(edPub)
+		require.NoError(t, err)
+		require.False(t, edJWK.SamePublicKey(x25519JWK))
+		require.False(t, x25519JWK.SamePublicKey(edJWK))
+
+		rsaKey, err := rsa.GenerateKey(rand.Reader, 2048)
+		require.NoError(t, err)
+
+		rsaJWK, err := JWKFromKey(&rsaKey.PublicKey)
+		require.NoError(t, err)
+
+		rsaPrivJWK, err := JWKFromKey(rsaKey)
+		require.NoError(t, err)
+		require.True(t, rsaJWK.SamePublicKey(rsaPrivJWK))
+		require.False(t, rsaJWK.SamePublicKey(edJWK))
+		require.False(t, edJWK.SamePublicKey(rsaJWK))
+
+		require.False(t, edJWK.SamePublicKey(nil))
+	})
+}
+
+func TestJWKFromECCoordinates(t *testing.T) {
+	tests := []struct {
+		curve elliptic.Curve
+		crv   string
+		alg   string
+		size  int
+	}{
+		{curve: elliptic.P256(), crv: "P-256", alg: "ES256", size: 32},
+		{curve: elliptic.P384(), crv: "P-384", alg: "ES384", size: 48},
+		{curve: elliptic.P521(), crv: "P-521", alg: "ES512", size: 66},
+		{curve: btcec.S256(), crv: "secp256k1", alg: "ES256K", size: 32},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.crv, func(t *testing.T) {
+			var privKey *ecdsa.PrivateKey
+
+			// a key with a short X coordinate checks the padding of the JWK x member.
+			for privKey == nil || len(privKey.X.Bytes()) == tc.size {
+				var err error
+
+				privKey, err = ecdsa.GenerateKey(tc.curve, rand.Reader)
+				require.NoError(t, err)
+			}
+
+			j, err := JWKFromECCoordinates(tc.curve, privKey.X, privKey.Y)
+			require.NoError(t, err)
+			require.Equal(t, "EC", j.Kty)
+			require.Equal(t, tc.crv, j.Crv)
+			require.Equal(t, tc.alg, j.Algorithm)
+			require.True(t, j.IsPublic())
+
+			ecKey, ok := j.Key.(*ecdsa.PublicKey)
+			require.True(t, ok)
+			require.Zero(t, ecKey.X.Cmp(privKey.X))
+			require.Zero(t, ecKey.Y.Cmp(privKey.Y))
+
+			jwkBytes, err := j.MarshalJSON()
+			require.NoError(t, err)
+
+			members := map[string]string{}
+			require.NoError(t, json.Unmarshal(jwkBytes, &members))
+
+			x, err := base64.RawURLEncoding.DecodeString(members["x"])
+			require.NoError(t, err)
+			require.Len(t, x, tc.size)
+		})
+	}
+
+	t.Run("errors", func(t *testing.T) {
+		key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+		require.NoError(t, err)
+
+		_, err = JWKFromECCoordinates(nil, key.X, key.Y)
+		require.EqualError(t, err, "jwkFromECCoordinates: curve and coordinates are required")
+
+		_, err = JWKFromECCoordinates(elliptic.P256(), key.X, nil)
+		require.EqualError(t, err, "jwkFromECCoordinates: curve and coordinates are required")
+
+		_, err = JWKFromECCoordinates(elliptic.P224(), key.X, key.Y)
+		require.EqualError(t, err, "jwkFromECCoordinates: unsupported curve 'P-224'")
+
+		_, err = JWKFromECCoordinates(elliptic.P256(), key.X, new(big.Int).Add(key.Y, big.NewInt(1)))
+		require.EqualError(t, err, "jwkFromECCoordinates: point is not on curve P-256")
+
+		_, err = JWKFromECCoordinates(elliptic.P384(), key.X, key.Y)
+		require.EqualError(t, err, "jwkFromECCoordinates: point is not on curve P-384")
+
+		_, err = JWKFromECCoordinates(elliptic.P256(), new(big.Int).Neg(key.X), key.Y)
+		require.EqualError(t, err, "jwkFromECCoordinates: point is not on curve P-256")
+	})
+}
+
+func TestMLDSAKeyTypes(t *testing.T) {
+	for _, kt := range []kms.KeyType{kms.MLDSA44Type, kms.MLDSA65Type, kms.MLDSA87Type} {
+		t.Run(string(kt), func(t *testing.T) {
+			alg, size, ok := jwk.MLDSAParameterSet(kt)
+			require.True(t, ok)
+
+			pub := make([]byte, size)
+			_, err := rand.Read(pub)
+			require.NoError(t, err)
+
+			key, err := PubKeyBytesToKey(pub, kt)
+			require.NoError(t, err)
+			require.Equal(t, pub, key)
+
+			jwkKey, err := PubKeyBytesToJWK(pub, kt, WithKID(KIDThumbprint))
+			require.NoError(t, err)
+			require.Equal(t, "AKP", jwkKey.Kty)
+			require.Equal(t, alg, jwkKey.Algorithm)
+			require.NotEmpty(t, jwkKey.KeyID)
+
+			jwkBytes, err := jwkKey.MarshalJSON()
+			require.NoError(t, err)
+
+			parsed := &jwk.JWK{}
+			require.NoError(t, parsed.UnmarshalJSON(jwkBytes))
+
+			pubKey, err := PublicKeyFromJWK(parsed)
+			require.NoError(t, err)
+			require.Equal(t, pub, pubKey.X)
+			require.Equal(t, "AKP", pubKey.Type)
+			require.Equal(t, jwkKey.KeyID, pubKey.KID)
+
+			detected, _, err := DetectKeyType(pub)
+			require.NoError(t, err)
+			require.Equal(t, kt, detected)
+
+			_, err = PubKeyBytesToJWK(pub[1:], kt)
+			require.EqualError(t, err, "invalid size of public key")
+		})
+	}
+
+	t.Run("raw bytes JWK of another key type", func(t *testing.T) {
+		_, err := PublicKeyFromJWK(&jwk.JWK{JSONWebKey: jose.JSONWebKey{Key: []byte("raw")}, Kty: "AKP"})
+		require.EqualError(t, err, "publicKeyFromJWK: unsupported jwk key type []uint8")
+	})
+}
@@ -0,0 +1,201 @@
+/*
+Copyright Gen Digital Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package jwksupport
+
+import (
+	"errors"
+	"fmt"
+	"sync"
+
+	"github.com/dellekappa/kms-go/doc/jose/jwk"
+	cryptoapi "github.com/dellekappa/kms-go/spi/crypto"
+	"github.com/dellekappa/kms-go/spi/kms"
+)
+
+// ErrUnsupportedJWK is returned by KeyTypeHandler.DecodeJWK when the JWK is not of the handler's key type.
+var ErrUnsupportedJWK = errors.New("unsupported jwk") // nolint: gochecknoglobals
+
+// KeyTypeHandler converts the public keys of a kms.KeyType, see RegisterKeyType.
+type KeyTypeHandler interface {
+	// EncodeJWK encodes marshalled public key bytes into a JWK, as returned by PubKeyBytesToJWK.
+	EncodeJWK(pubBytes []byte) (*jwk.JWK, error)
+	// DecodeJWK builds a cryptoapi.PublicKey from jwkKey, as returned by PublicKeyFromJWK. It returns
+	// ErrUnsupportedJWK if jwkKey is not of the handler's key type.
+	DecodeJWK(jwkKey *jwk.JWK) (*cryptoapi.PublicKey, error)
+	// PubKeyBytesToKey decodes marshalled public key bytes into an opaque key, as returned by PubKeyBytesToKey.
+	PubKeyBytesToKey(pubBytes []byte) (interface{}, error)
+}
+
+// KeyTypeSigner signs messages with the private keys of a kms.KeyType, e.g. a post-quantum backend for key types the
+// KMS crypto does not sign with. Wrapper suite signers route the signatures of a key type to its KeyTypeSigner when one
+// is registered, see RegisterKeyType and RegisterKeyTypeSigner.
+type KeyTypeSigner interface {
+	// Sign signs msg with the private key of the key handle kh, as returned by the KMS.
+	Sign(msg []byte, kh interface{}) ([]byte, error)
+}
+
+// keyTypeRegistry holds the key type handlers, built-in key types are registered at package initialization.
+type keyTypeRegistry struct {
+	mu       sync.RWMutex
+	handlers map[kms.KeyType]KeyTypeHandler
+	// custom lists the key types registered with RegisterKeyType, in registration order.
+	custom  []kms.KeyType
+	signers map[kms.KeyType]KeyTypeSigner
+}
+
+var registry = newBuiltinKeyTypeRegistry() // nolint: gochecknoglobals
+
+// RegisterKeyType registers handler for the public keys of kt, so that PubKeyBytesToJWK, PubKeyBytesToKey and
+// PublicKeyFromJWK support kt. Built-in key types are registered the same way. PublicKeyFromJWK tries the handlers of
+// custom key types in registration order for JWKs not supported by the built-in key types.
+// If handler also implements KeyTypeSigner, it is registered as the signer of kt.
+// RegisterKeyType panics if handler is nil or if kt is already registered.
+func RegisterKeyType(kt kms.KeyType, handler KeyTypeHandler) {
+	registry.register(kt, handler, true)
+
+	if signer, ok := handler.(KeyTypeSigner); ok {
+		RegisterKeyTypeSigner(kt, signer)
+	}
+}
+
+// RegisterKeyTypeSigner registers signer as the signing backend of kt, e.g. for a built-in key type like
+// kms.MLDSA44Type whose JWKs are supported by this package but whose keys the KMS crypto does not sign with.
+// RegisterKeyTypeSigner panics if signer is nil or if a signer is already registered for kt.
+func RegisterKeyTypeSigner(kt kms.KeyType, signer KeyTypeSigner) {
+	if signer == nil {
+		panic("jwksupport: RegisterKeyTypeSigner signer is nil")
+	}
+
+	registry.mu.Lock()
+	defer registry.mu.Unlock()
+
+	if _, ok := registry.signers[kt]; ok {
+		panic(fmt.Sprintf("jwksupport: RegisterKeyTypeSigner called twice for key type %s", kt))
+	}
+
+	registry.signers[kt] = signer
+}
+
+// KeyTypeSignerFor returns the KeyTypeSigner registered for kt, ok is false if there is none.
+func KeyTypeSignerFor(kt kms.KeyType) (KeyTypeSigner, bool) {
+	registry.mu.RLock()
+	defer registry.mu.RUnlock()
+
+	signer, ok := registry.signers[kt]
+
+	return signer, ok
+}
+
+// HasKeyTypeSigners reports whether a KeyTypeSigner is registered for any key type, so that signers can skip
+// resolving the key type of their key when there is none.
+func HasKeyTypeSigners() bool {
+	registry.mu.RLock()
+	defer registry.mu.RUnlock()
+
+	return len(registry.signers) > 0
+}
+
+// SupportedKeyTypesForJWK returns the key types whose public keys PubKeyBytesToJWK encodes as JWK: the built-in key
+// types followed by the key types registered with RegisterKeyType, in registration order.
+func SupportedKeyTypesForJWK() []kms.KeyType {
+	registry.mu.RLock()
+	defer registry.mu.RUnlock()
+
+	keyTypes := make([]kms.KeyType, 0, len(builtinKeyTypes)+len(registry.custom))
+	keyTypes = append(keyTypes, builtinKeyTypes...)
+
+	return append(keyTypes, registry.custom...)
+}
+
+func (r *keyTypeRegistry) register(kt kms.KeyType, handler KeyTypeHandler, custom bool) {
+	if handler == nil {
+		panic("jwksupport: RegisterKeyType handler is nil")
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if _, ok := r.handlers[kt]; ok {
+		panic(fmt.Sprintf("jwksupport: RegisterKeyType called twice for key type %s", kt))
+	}
+
+	r.handlers[kt] = handler
+
+	if custom {
+		r.custom = append(r.custom, kt)
+	}
+}
+
+func keyTypeHandler(kt kms.KeyType) (KeyTypeHandler, bool) {
+	registry.mu.RLock()
+	defer registry.mu.RUnlock()
+
+	handler, ok := registry.handlers[kt]
+
+	return handler, ok
+}
+
+// registeredPublicKeyFromJWK decodes jwkKey with the first custom key type handler supporting it.
+func registeredPublicKeyFromJWK(jwkKey *jwk.JWK) (*cryptoapi.PublicKey, error) {
+	registry.mu.RLock()
+
+	handlers := make([]KeyTypeHandler, 0, len(registry.custom))
+	for _, kt := range registry.custom {
+		handlers = append(handlers, registry.handlers[kt])
+	}
+
+	registry.mu.RUnlock()
+
+	for _, handler := range handlers {
+		pubKey, err := handler.DecodeJWK(jwkKey)
+		if !errors.Is(err, ErrUnsupportedJWK) {
+			return pubKey, err
+		}
+	}
+
+	return nil, ErrUnsupportedJWK
+}
+
+// builtinKeyTypes are the key types supported by this package.
+var builtinKeyTypes = []kms.KeyType{ // nolint: gochecknoglobals
+	kms.ED25519Type, kms.X25519ECDHKWType,
+	kms.BLS12381G2Type, kms.BLS12381G1Type,
+	kms.ECDSAP256TypeIEEEP1363, kms.ECDSAP384TypeIEEEP1363, kms.ECDSAP521TypeIEEEP1363,
+	kms.ECDSASecp256k1TypeIEEEP1363, kms.ECDSASecp256k1TypeDER,
+	kms.ECDSAP256TypeDER, kms.ECDSAP384TypeDER, kms.ECDSAP521TypeDER,
+	kms.NISTP256ECDHKWType, kms.NISTP384ECDHKWType, kms.NISTP521ECDHKWType,
+	kms.RSARS256Type, kms.RSAPS256Type, kms.RSA3072Type, kms.RSA4096Type,
+	kms.MLDSA44Type, kms.MLDSA65Type, kms.MLDSA87Type,
+}
+
+func newBuiltinKeyTypeRegistry() *keyTypeRegistry {
+	r := &keyTypeRegistry{
+		handlers: map[kms.KeyType]KeyTypeHandler{},
+		signers:  map[kms.KeyType]KeyTypeSigner{},
+	}
+
+	for _, kt := range builtinKeyTypes {
+		r.register(kt, builtinKeyType(kt), false)
+	}
+
+	return r
+}
+
+// builtinKeyType is the KeyTypeHandler of the key types supported by this package.
+type builtinKeyType kms.KeyType
+
+func (b builtinKeyType) EncodeJWK(pubBytes []byte) (*jwk.JWK, error) {
+	return builtinPubKeyBytesToJWK(pubBytes, kms.KeyType(b))
+}
+
+func (b builtinKeyType) DecodeJWK(jwkKey *jwk.JWK) (*cryptoapi.PublicKey, error) {
+	return builtinPublicKeyFromJWK(jwkKey)
+}
+
+func (b builtinKeyType) PubKeyBytesToKey(pubBytes []byte) (interface{}, error) {
+	return builtinPubKeyBytesToKey(pubBytes, kms.KeyType(b))
+}
@@ -0,0 +1,143 @@
+/*
+Copyright Gen Digital Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package jwksupport
+
+import (
+	"crypto/ed25519"
+	"crypto/rand"
+	"errors"
+	"testing"
+
+	"github.com/go-jose/go-jose/v3"
+	"github.com/stretchr/testify/require"
+
+	"github.com/dellekappa/kms-go/doc/jose/jwk"
+	cryptoapi "github.com/dellekappa/kms-go/spi/crypto"
+	"github.com/dellekappa/kms-go/spi/kms"
+)
+
+const testPQKeyType = kms.KeyType("TESTPQ")
+
+// testPQKey is the opaque key of testPQKeyType.
+type testPQKey []byte
+
+type testPQHandler struct{}
+
+func (testPQHandler) EncodeJWK(pubBytes []byte) (*jwk.JWK, error) {
+	if len(pubBytes) == 0 {
+		return nil, errors.New("empty key")
+	}
+
+	return &jwk.JWK{JSONWebKey: jose.JSONWebKey{Key: testPQKey(pubBytes)}, Kty: "TESTPQ"}, nil
+}
+
+func (testPQHandler) DecodeJWK(jwkKey *jwk.JWK) (*cryptoapi.PublicKey, error) {
+	key, ok := jwkKey.Key.(testPQKey)
+	if !ok {
+		return nil, ErrUnsupportedJWK
+	}
+
+	return &cryptoapi.PublicKey{KID: jwkKey.KeyID, Type: jwkKey.Kty, X: key}, nil
+}
+
+func (testPQHandler) PubKeyBytesToKey(pubBytes []byte) (interface{}, error) {
+	return testPQKey(pubBytes), nil
+}
+
+// testPQSigner is a testPQHandler signing with its key type.
+type testPQSigner struct {
+	testPQHandler
+}
+
+func (testPQSigner) Sign(msg []byte, _ interface{}) ([]byte, error) {
+	return append([]byte("pq:"), msg...), nil
+}
+
+func TestRegisterKeyType(t *testing.T) {
+	_, err := PubKeyBytesToKey([]byte("pq"), testPQKeyType)
+	require.EqualError(t, err, "invalid key type: TESTPQ")
+	require.NotContains(t, SupportedKeyTypesForJWK(), testPQKeyType)
+
+	RegisterKeyType(testPQKeyType, testPQHandler{})
+
+	t.Run("supported key types", func(t *testing.T) {
+		keyTypes := SupportedKeyTypesForJWK()
+		require.Contains(t, keyTypes, kms.ED25519Type)
+		require.Contains(t, keyTypes, kms.MLDSA65Type)
+		require.Contains(t, keyTypes, testPQKeyType)
+
+		for _, kt := range keyTypes {
+			_, ok := keyTypeHandler(kt)
+			require.True(t, ok, kt)
+		}
+	})
+
+	t.Run("custom key type", func(t *testing.T) {
+		key, err := PubKeyBytesToKey([]byte("pq"), testPQKeyType)
+		require.NoError(t, err)
+		require.Equal(t, testPQKey("pq"), key)
+
+		jwkKey, err := PubKeyBytesToJWK([]byte("pq"), testPQKeyType, WithKID(KIDValue("kid")))
+		require.NoError(t, err)
+		require.Equal(t, "kid", jwkKey.KeyID)
+		require.Equal(t, testPQKey("pq"), jwkKey.Key)
+
+		pubKey, err := PublicKeyFromJWK(jwkKey)
+		require.NoError(t, err)
+		require.Equal(t, "kid", pubKey.KID)
+		require.Equal(t, []byte("pq"), pubKey.X)
+
+		_, err = PubKeyBytesToJWK(nil, testPQKeyType)
+		require.EqualError(t, err, "empty key")
+	})
+
+	t.Run("built-in key types are unaffected", func(t *testing.T) {
+		pub, _, err := ed25519.GenerateKey(rand.Reader)
+		require.NoError(t, err)
+
+		jwkKey, err := PubKeyBytesToJWK(pub, kms.ED25519Type)
+		require.NoError(t, err)
+
+		pubKey, err := PublicKeyFromJWK(jwkKey)
+		require.NoError(t, err)
+		require.Equal(t, []byte(pub), pubKey.X)
+
+		_, err = PublicKeyFromJWK(&jwk.JWK{JSONWebKey: jose.JSONWebKey{Key: "unknown"}})
+		require.EqualError(t, err, "publicKeyFromJWK: unsupported jwk key type string")
+	})
+
+	t.Run("register twice panics", func(t *testing.T) {
+		require.Panics(t, func() { RegisterKeyType(testPQKeyType, testPQHandler{}) })
+		require.Panics(t, func() { RegisterKeyType(kms.ED25519Type, testPQHandler{}) })
+		require.Panics(t, func() { RegisterKeyType("other", nil) })
+	})
+}
+
+func TestRegisterKeyTypeSigner(t *testing.T) {
+	const signingKeyType = kms.KeyType("TESTPQSIGNER")
+
+	_, ok := KeyTypeSignerFor(signingKeyType)
+	require.False(t, ok)
+
+	RegisterKeyType(signingKeyType, testPQSigner{})
+
+	signer, ok := KeyTypeSignerFor(signingKeyType)
+	require.True(t, ok)
+	require.True(t, HasKeyTypeSigners())
+
+	sig, err := signer.Sign([]byte("msg"), nil)
+	require.NoError(t, err)
+	require.Equal(t, []byte("pq:msg"), sig)
+
+	require.Panics(t, func() { RegisterKeyTypeSigner(signingKeyType, testPQSigner{}) })
+	require.Panics(t, func() { RegisterKeyTypeSigner(kms.MLDSA87Type, nil) })
+
+	RegisterKeyTypeSigner(kms.MLDSA87Type, testPQSigner{})
+
+	_, ok = KeyTypeSignerFor(kms.MLDSA87Type)
+	require.True(t, ok)
+}
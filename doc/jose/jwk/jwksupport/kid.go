@@ -0,0 +1,118 @@
+/*
+Copyright Gen Digital Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package jwksupport
+
+import (
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+
+	"github.com/dellekappa/kms-go/doc/jose/jwk"
+)
+
+// thumbprintMembers are the required JWK members of each key type used to compute a JWK thumbprint, as per
+// https://tools.ietf.org/html/rfc7638#section-3.2, https://tools.ietf.org/html/rfc8037#section-2 and
+// draft-ietf-cose-dilithium for AKP keys.
+var thumbprintMembers = map[string][]string{ //nolint:gochecknoglobals
+	ecKty:      {"crv", "kty", "x", "y"},
+	okpKty:     {"crv", "kty", "x"},
+	rsaKty:     {"e", "kty", "n"},
+	jwk.AKPKty: {"alg", "kty", "pub"},
+}
+
+// optionalThumbprintMembers are thumbprint members absent from some JWKs, like "y" for BLS12-381 keys which are
+// marshalled as compressed points.
+var optionalThumbprintMembers = map[string]bool{"y": true} //nolint:gochecknoglobals
+
+// KIDMode sets the kid of a JWK built by PubKeyBytesToJWK.
+type KIDMode func(key *jwk.JWK) error
+
+// KIDThumbprint is a KIDMode setting kid to the base64url encoded RFC 7638 SHA-256 thumbprint of the JWK.
+func KIDThumbprint(key *jwk.JWK) error {
+	tp, err := Thumbprint(key)
+	if err != nil {
+		return err
+	}
+
+	key.KeyID = base64.RawURLEncoding.EncodeToString(tp)
+
+	return nil
+}
+
+// KIDValue returns a KIDMode setting kid to the explicit value s.
+func KIDValue(s string) KIDMode {
+	return func(key *jwk.JWK) error {
+		key.KeyID = s
+
+		return nil
+	}
+}
+
+// jwkOpts holds options for the JWK creation.
+type jwkOpts struct {
+	kidMode KIDMode
+}
+
+// JWKOpt is the JWK creation option.
+type JWKOpt func(opts *jwkOpts)
+
+// WithKID option sets the kid of the created JWK with mode, KIDThumbprint or KIDValue. Without this option, kid is
+// empty.
+func WithKID(mode KIDMode) JWKOpt {
+	return func(opts *jwkOpts) {
+		opts.kidMode = mode
+	}
+}
+
+// Thumbprint computes the RFC 7638 SHA-256 thumbprint of key. Unlike go-jose's JSONWebKey.Thumbprint, it supports
+// all the key types marshalled by jwk.JWK (X25519, secp256k1 and BLS12-381 keys included).
+func Thumbprint(key *jwk.JWK) ([]byte, error) {
+	jwkBytes, err := key.MarshalJSON()
+	if err != nil {
+		return nil, fmt.Errorf("thumbprint: marshal JWK: %w", err)
+	}
+
+	var members map[string]interface{}
+
+	err = json.Unmarshal(jwkBytes, &members)
+	if err != nil {
+		return nil, fmt.Errorf("thumbprint: unmarshal JWK: %w", err)
+	}
+
+	kty, _ := members["kty"].(string)
+
+	required, ok := thumbprintMembers[kty]
+	if !ok {
+		return nil, fmt.Errorf("thumbprint: unsupported key type '%s'", kty)
+	}
+
+	// encoding/json marshals map keys sorted lexicographically and without whitespaces, as required by RFC 7638.
+	input := make(map[string]interface{}, len(required))
+
+	for _, m := range required {
+		v, found := members[m]
+		if !found {
+			if optionalThumbprintMembers[m] {
+				continue
+			}
+
+			return nil, fmt.Errorf("thumbprint: JWK member '%s' is missing", m)
+		}
+
+		input[m] = v
+	}
+
+	inputBytes, err := json.Marshal(input)
+	if err != nil {
+		return nil, fmt.Errorf("thumbprint: marshal thumbprint input: %w", err)
+	}
+
+	tp := sha256.Sum256(inputBytes)
+
+	return tp[:], nil
+}
@@ -0,0 +1,111 @@
+/*
+Copyright Gen Digital Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package jwksupport
+
+import (
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/rsa"
+	"errors"
+	"fmt"
+	"strings"
+
+	ml "github.com/IBM/mathlib"
+	"github.com/trustbloc/bbs-signature-go/bbs12381g2pub"
+
+	"github.com/dellekappa/kms-go/doc/jose/jwk"
+	"github.com/dellekappa/kms-go/spi/kms"
+)
+
+// ErrKeyTypeMismatch is returned by JWKMatchesKeyType when a JWK is not a key of the expected kms.KeyType.
+var ErrKeyTypeMismatch = errors.New("jwk does not match key type")
+
+// JWKMatchesKeyType checks j is a key of type kt, e.g. before handing j to a signer built for kt: the kty and crv of
+// j must be the ones of kt (a P-256 JWK doesn't match ECDSAP384TypeIEEEP1363), RSA keys must satisfy the modulus size
+// of kt (see jwk.JWK.Validate) and ML-DSA keys must have the alg of kt. When kty or crv are not set, they are derived
+// from the key of j. The returned error wraps ErrKeyTypeMismatch and describes both the JWK's and the expected values.
+func JWKMatchesKeyType(j *jwk.JWK, kt kms.KeyType) error {
+	if j == nil {
+		return errors.New("jwkMatchesKeyType: jwk is empty")
+	}
+
+	expectedKty, expectedCrv, ok := keyTypeKtyCrv(kt)
+	if !ok {
+		return fmt.Errorf("jwkMatchesKeyType: unsupported key type %s", kt)
+	}
+
+	kty, crv := jwkKtyCrv(j)
+
+	if !strings.EqualFold(kty, expectedKty) || !strings.EqualFold(crv, expectedCrv) {
+		return fmt.Errorf("jwkMatchesKeyType: %w %s: jwk has kty '%s' and crv '%s', expected kty '%s' and crv '%s'",
+			ErrKeyTypeMismatch, kt, kty, crv, expectedKty, expectedCrv)
+	}
+
+	switch expectedKty {
+	case rsaKty:
+		if err := j.Validate(kt); err != nil {
+			return fmt.Errorf("jwkMatchesKeyType: %w %s: %w", ErrKeyTypeMismatch, kt, err)
+		}
+	case jwk.AKPKty:
+		if alg, _, _ := jwk.MLDSAParameterSet(kt); j.Algorithm != alg {
+			return fmt.Errorf("jwkMatchesKeyType: %w %s: jwk has alg '%s', expected alg '%s'",
+				ErrKeyTypeMismatch, kt, j.Algorithm, alg)
+		}
+	}
+
+	return nil
+}
+
+// keyTypeKtyCrv returns the JWK kty and crv of the keys of kt, crv is empty for key types without curve.
+func keyTypeKtyCrv(kt kms.KeyType) (string, string, bool) {
+	switch kt {
+	case kms.ED25519Type:
+		return okpKty, "Ed25519", true
+	case kms.X25519ECDHKWType:
+		return okpKty, x25519Crv, true
+	case kms.BLS12381G2Type:
+		return ecKty, bls12381G2Crv, true
+	case kms.BLS12381G1Type:
+		return ecKty, bls12381G1Crv, true
+	case kms.RSARS256Type, kms.RSAPS256Type, kms.RSA3072Type, kms.RSA4096Type:
+		return rsaKty, "", true
+	case kms.MLDSA44Type, kms.MLDSA65Type, kms.MLDSA87Type:
+		return jwk.AKPKty, "", true
+	}
+
+	if curve := getECDSACurve(kt); curve != nil {
+		return ecKty, curve.Params().Name, true
+	}
+
+	return "", "", false
+}
+
+// jwkKtyCrv returns the kty and canonical crv of j, derived from its key when they are not set.
+func jwkKtyCrv(j *jwk.JWK) (string, string) {
+	kty, crv := j.Kty, jwk.CanonicalCurveName(j.Crv)
+
+	if kty != "" && (crv != "" || !strings.EqualFold(kty, ecKty) && !strings.EqualFold(kty, okpKty)) {
+		return kty, crv
+	}
+
+	switch key := j.Key.(type) {
+	case *ecdsa.PublicKey:
+		return ecKty, key.Curve.Params().Name
+	case *ecdsa.PrivateKey:
+		return ecKty, key.Curve.Params().Name
+	case *rsa.PublicKey, *rsa.PrivateKey:
+		return rsaKty, ""
+	case ed25519.PublicKey, ed25519.PrivateKey:
+		return okpKty, "Ed25519"
+	case *bbs12381g2pub.PublicKey, *bbs12381g2pub.PrivateKey:
+		return ecKty, bls12381G2Crv
+	case *ml.G1:
+		return ecKty, bls12381G1Crv
+	}
+
+	return kty, crv
+}
@@ -0,0 +1,142 @@
+/*
+Copyright Gen Digital Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package jwksupport
+
+import (
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/rsa"
+	"testing"
+
+	"github.com/btcsuite/btcd/btcec/v2"
+	"github.com/go-jose/go-jose/v3"
+	"github.com/stretchr/testify/require"
+
+	"github.com/dellekappa/kms-go/doc/jose/jwk"
+	"github.com/dellekappa/kms-go/spi/kms"
+)
+
+func TestJWKMatchesKeyType(t *testing.T) {
+	p256Key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+
+	p256JWK, err := JWKFromKey(&p256Key.PublicKey)
+	require.NoError(t, err)
+
+	secp256k1Key, err := ecdsa.GenerateKey(btcec.S256(), rand.Reader)
+	require.NoError(t, err)
+
+	edPub, _, err := ed25519.GenerateKey(rand.Reader)
+	require.NoError(t, err)
+
+	rsaKey, err := rsa.GenerateKey(rand.Reader, 2048)
+	require.NoError(t, err)
+
+	x25519JWK, err := JWKFromX25519Key(make([]byte, 32))
+	require.NoError(t, err)
+
+	mldsaJWK, err := PubKeyBytesToJWK(make([]byte, 1952), kms.MLDSA65Type)
+	require.NoError(t, err)
+
+	t.Run("match", func(t *testing.T) {
+		tests := []struct {
+			name string
+			jwk  *jwk.JWK
+			kt   kms.KeyType
+		}{
+			{name: "P-256 IEEE P1363", jwk: p256JWK, kt: kms.ECDSAP256TypeIEEEP1363},
+			{name: "P-256 DER", jwk: p256JWK, kt: kms.ECDSAP256TypeDER},
+			{name: "P-256 ECDH-KW", jwk: p256JWK, kt: kms.NISTP256ECDHKWType},
+			{
+				name: "P-256 private key without kty and crv",
+				jwk:  &jwk.JWK{JSONWebKey: jose.JSONWebKey{Key: p256Key}},
+				kt:   kms.ECDSAP256TypeIEEEP1363,
+			},
+			{
+				name: "P-256 with curve alias",
+				jwk:  &jwk.JWK{JSONWebKey: jose.JSONWebKey{Key: p256Key}, Kty: "EC", Crv: "secp256r1"},
+				kt:   kms.ECDSAP256TypeIEEEP1363,
+			},
+			{
+				name: "secp256k1",
+				jwk:  &jwk.JWK{JSONWebKey: jose.JSONWebKey{Key: &secp256k1Key.PublicKey}},
+				kt:   kms.ECDSASecp256k1TypeIEEEP1363,
+			},
+			{name: "Ed25519", jwk: &jwk.JWK{JSONWebKey: jose.JSONWebKey{Key: edPub}}, kt: kms.ED25519Type},
+			{name: "X25519", jwk: x25519JWK, kt: kms.X25519ECDHKWType},
+			{name: "RSA", jwk: &jwk.JWK{JSONWebKey: jose.JSONWebKey{Key: &rsaKey.PublicKey}}, kt: kms.RSAPS256Type},
+			{name: "ML-DSA", jwk: mldsaJWK, kt: kms.MLDSA65Type},
+		}
+
+		for _, tc := range tests {
+			t.Run(tc.name, func(t *testing.T) {
+				require.NoError(t, JWKMatchesKeyType(tc.jwk, tc.kt))
+			})
+		}
+	})
+
+	t.Run("mismatch", func(t *testing.T) {
+		tests := []struct {
+			name   string
+			jwk    *jwk.JWK
+			kt     kms.KeyType
+			errMsg string
+		}{
+			{
+				name: "P-256 for P-384",
+				jwk:  p256JWK,
+				kt:   kms.ECDSAP384TypeIEEEP1363,
+				errMsg: "jwkMatchesKeyType: jwk does not match key type ECDSAP384IEEEP1363: jwk has kty 'EC' and " +
+					"crv 'P-256', expected kty 'EC' and crv 'P-384'",
+			},
+			{
+				name: "Ed25519 for X25519",
+				jwk:  &jwk.JWK{JSONWebKey: jose.JSONWebKey{Key: edPub}},
+				kt:   kms.X25519ECDHKWType,
+				errMsg: "jwkMatchesKeyType: jwk does not match key type X25519ECDHKW: jwk has kty 'OKP' and " +
+					"crv 'Ed25519', expected kty 'OKP' and crv 'X25519'",
+			},
+			{
+				name: "RSA for P-256",
+				jwk:  &jwk.JWK{JSONWebKey: jose.JSONWebKey{Key: &rsaKey.PublicKey}},
+				kt:   kms.ECDSAP256TypeDER,
+				errMsg: "jwkMatchesKeyType: jwk does not match key type ECDSAP256DER: jwk has kty 'RSA' and " +
+					"crv '', expected kty 'EC' and crv 'P-256'",
+			},
+			{
+				name: "RSA 2048 for RSA 3072",
+				jwk:  &jwk.JWK{JSONWebKey: jose.JSONWebKey{Key: &rsaKey.PublicKey}},
+				kt:   kms.RSA3072Type,
+				errMsg: "jwkMatchesKeyType: jwk does not match key type RSA3072: validate: weak key: RSA key size is " +
+					"below the 3072 bits required by key type RSA3072",
+			},
+			{
+				name: "ML-DSA-65 for ML-DSA-44",
+				jwk:  mldsaJWK,
+				kt:   kms.MLDSA44Type,
+				errMsg: "jwkMatchesKeyType: jwk does not match key type MLDSA44: jwk has alg 'ML-DSA-65', " +
+					"expected alg 'ML-DSA-44'",
+			},
+		}
+
+		for _, tc := range tests {
+			t.Run(tc.name, func(t *testing.T) {
+				err := JWKMatchesKeyType(tc.jwk, tc.kt)
+				require.ErrorIs(t, err, ErrKeyTypeMismatch)
+				require.EqualError(t, err, tc.errMsg)
+			})
+		}
+	})
+
+	t.Run("errors", func(t *testing.T) {
+		require.EqualError(t, JWKMatchesKeyType(nil, kms.ED25519Type), "jwkMatchesKeyType: jwk is empty")
+		require.EqualError(t, JWKMatchesKeyType(p256JWK, kms.AES256GCMType),
+			"jwkMatchesKeyType: unsupported key type AES256GCM")
+	})
+}
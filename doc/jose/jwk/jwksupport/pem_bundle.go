@@ -0,0 +1,59 @@
+/*
+Copyright Gen Digital Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package jwksupport
+
+import (
+	"crypto/x509"
+	"encoding/pem"
+	"errors"
+	"fmt"
+
+	"github.com/dellekappa/kms-go/doc/jose/jwk"
+)
+
+const pemCertificateType = "CERTIFICATE"
+
+// JWKSetFromPEMBundle creates a JWK Set from the public keys of the certificates of the concatenated PEM blocks
+// pemBundle, e.g. a CA bundle file. Each CERTIFICATE block is converted with FromCertificate, in order, other blocks
+// (private keys, CRLs, ...) are skipped. Errors report the index of the failing block in the bundle, counting all
+// blocks from 0. A bundle without any certificate is an error.
+func JWKSetFromPEMBundle(pemBundle []byte) (*jwk.JWKSet, error) {
+	set := &jwk.JWKSet{}
+
+	rest := pemBundle
+
+	for index := 0; ; index++ {
+		var block *pem.Block
+
+		block, rest = pem.Decode(rest)
+		if block == nil {
+			break
+		}
+
+		if block.Type != pemCertificateType {
+			continue
+		}
+
+		cert, err := x509.ParseCertificate(block.Bytes)
+		if err != nil {
+			return nil, fmt.Errorf("jwkSetFromPEMBundle: block %d: %w", index, err)
+		}
+
+		key, err := FromCertificate(cert)
+		if err != nil {
+			return nil, fmt.Errorf("jwkSetFromPEMBundle: block %d: %w", index, err)
+		}
+
+		set.Keys = append(set.Keys, key)
+	}
+
+	if len(set.Keys) == 0 {
+		return nil, errors.New("jwkSetFromPEMBundle: no certificate found")
+	}
+
+	return set, nil
+}
@@ -0,0 +1,74 @@
+/*
+Copyright Gen Digital Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package jwksupport
+
+import (
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/x509"
+	"encoding/pem"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestJWKSetFromPEMBundle(t *testing.T) {
+	ecKey, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+
+	edPub, edPriv, err := ed25519.GenerateKey(rand.Reader)
+	require.NoError(t, err)
+
+	ecCert := createCertificate(t, &ecKey.PublicKey, ecKey)
+	edCert := createCertificate(t, edPub, edPriv)
+
+	ecPrivDER, err := x509.MarshalPKCS8PrivateKey(ecKey)
+	require.NoError(t, err)
+
+	certPEM := func(cert *x509.Certificate) []byte {
+		return pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: cert.Raw})
+	}
+
+	privKeyPEM := pem.EncodeToMemory(&pem.Block{Type: "PRIVATE KEY", Bytes: ecPrivDER})
+
+	t.Run("success", func(t *testing.T) {
+		var bundle []byte
+
+		bundle = append(bundle, certPEM(ecCert)...)
+		bundle = append(bundle, privKeyPEM...)
+		bundle = append(bundle, "\n# comment between blocks\n"...)
+		bundle = append(bundle, certPEM(edCert)...)
+
+		set, err := JWKSetFromPEMBundle(bundle)
+		require.NoError(t, err)
+		require.Len(t, set.Keys, 2)
+
+		require.Equal(t, &ecKey.PublicKey, set.Keys[0].Key)
+		require.Equal(t, ecCert, set.Keys[0].Certificates[0])
+		require.NotEmpty(t, set.Keys[0].X509CertThumbprintS256)
+		require.Equal(t, edPub, set.Keys[1].Key)
+	})
+
+	t.Run("failures", func(t *testing.T) {
+		_, err := JWKSetFromPEMBundle(privKeyPEM)
+		require.EqualError(t, err, "jwkSetFromPEMBundle: no certificate found")
+
+		_, err = JWKSetFromPEMBundle([]byte("not PEM"))
+		require.EqualError(t, err, "jwkSetFromPEMBundle: no certificate found")
+
+		var bundle []byte
+
+		bundle = append(bundle, certPEM(ecCert)...)
+		bundle = append(bundle, privKeyPEM...)
+		bundle = append(bundle, pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: []byte("invalid")})...)
+
+		_, err = JWKSetFromPEMBundle(bundle)
+		require.ErrorContains(t, err, "jwkSetFromPEMBundle: block 2: x509: ")
+	})
+}
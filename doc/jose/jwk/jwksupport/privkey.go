@@ -0,0 +1,237 @@
+/*
+Copyright Gen Digital Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package jwksupport
+
+import (
+	"crypto/ecdh"
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/rsa"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/asn1"
+	"errors"
+	"fmt"
+
+	"github.com/btcsuite/btcd/btcec/v2"
+	"github.com/trustbloc/bbs-signature-go/bbs12381g2pub"
+
+	"github.com/dellekappa/kms-go/doc/jose/jwk"
+	"github.com/dellekappa/kms-go/spi/kms"
+)
+
+const x25519PrivateKeySize = 32
+
+// oidSecp256k1 is the secp256k1 named curve OID (https://www.secg.org/sec2-v2.pdf).
+var oidSecp256k1 = asn1.ObjectIdentifier{1, 3, 132, 0, 10} //nolint:gochecknoglobals
+
+// ErrNoPrivateKey is returned by PrivateKeyFromJWK for JWKs holding a public key only.
+var ErrNoPrivateKey = errors.New("jwk holds no private key") //nolint:gochecknoglobals
+
+// ecPrivateKey is the SEC1 EC private key structure (https://tools.ietf.org/html/rfc5915).
+type ecPrivateKey struct {
+	Version       int
+	PrivateKey    []byte
+	NamedCurveOID asn1.ObjectIdentifier `asn1:"optional,explicit,tag:0"`
+	PublicKey     asn1.BitString        `asn1:"optional,explicit,tag:1"`
+}
+
+// pkcs8 is the PKCS#8 private key structure (https://tools.ietf.org/html/rfc5208).
+type pkcs8 struct {
+	Version    int
+	Algo       pkix.AlgorithmIdentifier
+	PrivateKey []byte
+}
+
+// PrivKeyBytesToKey creates a private key from the given private key bytes of type keyType. privBytes are PKCS#8 DER
+// encoded for all key types, SEC1 DER encoded EC keys and PKCS#1 DER encoded RSA keys are parsed as a fallback. Ed25519
+// and X25519 keys are also accepted in their raw 32 bytes form (the seed for Ed25519).
+// The returned key is an *ecdsa.PrivateKey for ECDSA, NIST P ECDH and secp256k1 key types, an *rsa.PrivateKey for RSA
+// key types, an ed25519.PrivateKey for ED25519Type or an *ecdh.PrivateKey for X25519ECDHKWType.
+func PrivKeyBytesToKey(privBytes []byte, keyType kms.KeyType) (interface{}, error) {
+	switch keyType {
+	case kms.ED25519Type:
+		return parseEd25519PrivateKey(privBytes)
+	case kms.X25519ECDHKWType:
+		return parseX25519PrivateKey(privBytes)
+	case kms.ECDSAP256TypeIEEEP1363, kms.ECDSAP384TypeIEEEP1363, kms.ECDSAP521TypeIEEEP1363,
+		kms.ECDSAP256TypeDER, kms.ECDSAP384TypeDER, kms.ECDSAP521TypeDER,
+		kms.NISTP256ECDHKWType, kms.NISTP384ECDHKWType, kms.NISTP521ECDHKWType:
+		return parseECDSAPrivateKey(privBytes, keyType)
+	case kms.ECDSASecp256k1TypeIEEEP1363, kms.ECDSASecp256k1TypeDER:
+		return parseSecp256k1PrivateKey(privBytes)
+	case kms.RSARS256, kms.RSAPS256, kms.RSA3072, kms.RSA4096:
+		rsaKey, err := parseRSAPrivateKey(privBytes)
+		if err != nil {
+			return nil, err
+		}
+
+		err = validateKeySize(rsaKey, keyType)
+		if err != nil {
+			return nil, fmt.Errorf("privKeyBytesToKey: %w", err)
+		}
+
+		return rsaKey, nil
+	default:
+		return nil, fmt.Errorf("privKeyBytesToKey: invalid key type: %s", keyType)
+	}
+}
+
+func parseEd25519PrivateKey(privBytes []byte) (ed25519.PrivateKey, error) {
+	if len(privBytes) == ed25519.SeedSize {
+		return ed25519.NewKeyFromSeed(privBytes), nil
+	}
+
+	key, err := x509.ParsePKCS8PrivateKey(privBytes)
+	if err != nil {
+		return nil, fmt.Errorf("privKeyBytesToKey: failed to parse ed25519 private key: %w", err)
+	}
+
+	edKey, ok := key.(ed25519.PrivateKey)
+	if !ok {
+		return nil, fmt.Errorf("privKeyBytesToKey: key type mismatch: expected ed25519 private key, got %T", key)
+	}
+
+	return edKey, nil
+}
+
+func parseX25519PrivateKey(privBytes []byte) (*ecdh.PrivateKey, error) {
+	if len(privBytes) == x25519PrivateKeySize {
+		key, err := ecdh.X25519().NewPrivateKey(privBytes)
+		if err != nil {
+			return nil, fmt.Errorf("privKeyBytesToKey: failed to parse x25519 private key: %w", err)
+		}
+
+		return key, nil
+	}
+
+	key, err := x509.ParsePKCS8PrivateKey(privBytes)
+	if err != nil {
+		return nil, fmt.Errorf("privKeyBytesToKey: failed to parse x25519 private key: %w", err)
+	}
+
+	xKey, ok := key.(*ecdh.PrivateKey)
+	if !ok || xKey.Curve() != ecdh.X25519() {
+		return nil, fmt.Errorf("privKeyBytesToKey: key type mismatch: expected x25519 private key, got %T", key)
+	}
+
+	return xKey, nil
+}
+
+func parseECDSAPrivateKey(privBytes []byte, keyType kms.KeyType) (*ecdsa.PrivateKey, error) {
+	var ecKey *ecdsa.PrivateKey
+
+	key, err := x509.ParsePKCS8PrivateKey(privBytes)
+	if err != nil {
+		ecKey, err = x509.ParseECPrivateKey(privBytes)
+		if err != nil {
+			return nil, fmt.Errorf("privKeyBytesToKey: failed to parse ecdsa private key as PKCS#8 or SEC1: %w", err)
+		}
+	} else {
+		var ok bool
+
+		ecKey, ok = key.(*ecdsa.PrivateKey)
+		if !ok {
+			return nil, fmt.Errorf("privKeyBytesToKey: key type mismatch: expected ecdsa private key, got %T", key)
+		}
+	}
+
+	crv := getECDSACurve(keyType)
+	if ecKey.Curve != crv {
+		return nil, fmt.Errorf("privKeyBytesToKey: curve mismatch: expected %s, got %s", crv.Params().Name,
+			ecKey.Curve.Params().Name)
+	}
+
+	return ecKey, nil
+}
+
+// parseSecp256k1PrivateKey parses PKCS#8 or SEC1 secp256k1 private keys, which are not supported by crypto/x509.
+func parseSecp256k1PrivateKey(privBytes []byte) (*ecdsa.PrivateKey, error) {
+	sec1Bytes := privBytes
+
+	var p8 pkcs8
+
+	if rest, err := asn1.Unmarshal(privBytes, &p8); err == nil && len(rest) == 0 {
+		sec1Bytes = p8.PrivateKey
+
+		var namedCurveOID asn1.ObjectIdentifier
+
+		if _, err = asn1.Unmarshal(p8.Algo.Parameters.FullBytes, &namedCurveOID); err == nil &&
+			!namedCurveOID.Equal(oidSecp256k1) {
+			return nil, fmt.Errorf("privKeyBytesToKey: curve mismatch: expected secp256k1, got OID %s",
+				namedCurveOID)
+		}
+	}
+
+	var ecKey ecPrivateKey
+
+	rest, err := asn1.Unmarshal(sec1Bytes, &ecKey)
+	if err != nil {
+		return nil, fmt.Errorf("privKeyBytesToKey: failed to parse secp256k1 private key as PKCS#8 or SEC1: %w", err)
+	}
+
+	if len(rest) != 0 {
+		return nil, errors.New("privKeyBytesToKey: trailing data after ASN.1 of secp256k1 private key")
+	}
+
+	if len(ecKey.NamedCurveOID) > 0 && !ecKey.NamedCurveOID.Equal(oidSecp256k1) {
+		return nil, fmt.Errorf("privKeyBytesToKey: curve mismatch: expected secp256k1, got OID %s",
+			ecKey.NamedCurveOID)
+	}
+
+	privKey, _ := btcec.PrivKeyFromBytes(ecKey.PrivateKey)
+
+	return privKey.ToECDSA(), nil
+}
+
+func parseRSAPrivateKey(privBytes []byte) (*rsa.PrivateKey, error) {
+	key, err := x509.ParsePKCS8PrivateKey(privBytes)
+	if err != nil {
+		rsaKey, e := x509.ParsePKCS1PrivateKey(privBytes)
+		if e != nil {
+			return nil, fmt.Errorf("privKeyBytesToKey: failed to parse rsa private key as PKCS#8 or PKCS#1: %w", err)
+		}
+
+		return rsaKey, nil
+	}
+
+	rsaKey, ok := key.(*rsa.PrivateKey)
+	if !ok {
+		return nil, fmt.Errorf("privKeyBytesToKey: key type mismatch: expected rsa private key, got %T", key)
+	}
+
+	return rsaKey, nil
+}
+
+// PrivateKeyFromJWK returns the private key held by j, to import it in a KMS with ImportPrivateKey: an
+// *ecdsa.PrivateKey for EC keys (including secp256k1), an ed25519.PrivateKey for Ed25519 keys, an *rsa.PrivateKey for
+// RSA keys or a *bbs12381g2pub.PrivateKey for BLS12381G2 keys. JWKs without private key material are rejected with
+// ErrNoPrivateKey.
+func PrivateKeyFromJWK(j *jwk.JWK) (interface{}, error) {
+	if j == nil {
+		return nil, errors.New("privateKeyFromJWK: jwk is nil")
+	}
+
+	switch key := j.Key.(type) {
+	case *ecdsa.PrivateKey:
+		if key.D == nil {
+			return nil, fmt.Errorf("privateKeyFromJWK: %w", ErrNoPrivateKey)
+		}
+
+		return key, nil
+	case ed25519.PrivateKey:
+		if len(key) != ed25519.PrivateKeySize {
+			return nil, fmt.Errorf("privateKeyFromJWK: invalid ed25519 private key size %d", len(key))
+		}
+
+		return key, nil
+	case *rsa.PrivateKey, *bbs12381g2pub.PrivateKey:
+		return key, nil
+	default:
+		return nil, fmt.Errorf("privateKeyFromJWK: %w, got %T", ErrNoPrivateKey, key)
+	}
+}
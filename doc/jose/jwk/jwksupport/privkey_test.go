@@ -0,0 +1,255 @@
+/*
+Copyright Gen Digital Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package jwksupport
+
+import (
+	"crypto"
+	"crypto/ecdh"
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/asn1"
+	"testing"
+
+	"github.com/btcsuite/btcd/btcec/v2"
+	"github.com/go-jose/go-jose/v3"
+	"github.com/stretchr/testify/require"
+
+	"github.com/dellekappa/kms-go/doc/jose/jwk"
+	"github.com/dellekappa/kms-go/spi/kms"
+)
+
+func TestPrivKeyBytesToKey(t *testing.T) {
+	t.Run("ed25519", func(t *testing.T) {
+		_, privKey, err := ed25519.GenerateKey(rand.Reader)
+		require.NoError(t, err)
+
+		pkcs8Bytes, err := x509.MarshalPKCS8PrivateKey(privKey)
+		require.NoError(t, err)
+
+		for _, privBytes := range [][]byte{pkcs8Bytes, privKey.Seed()} {
+			key, e := PrivKeyBytesToKey(privBytes, kms.ED25519Type)
+			require.NoError(t, e)
+			require.Equal(t, privKey, key)
+		}
+	})
+
+	t.Run("x25519", func(t *testing.T) {
+		privKey, err := ecdh.X25519().GenerateKey(rand.Reader)
+		require.NoError(t, err)
+
+		pkcs8Bytes, err := x509.MarshalPKCS8PrivateKey(privKey)
+		require.NoError(t, err)
+
+		for _, privBytes := range [][]byte{pkcs8Bytes, privKey.Bytes()} {
+			key, e := PrivKeyBytesToKey(privBytes, kms.X25519ECDHKWType)
+			require.NoError(t, e)
+			require.True(t, privKey.Equal(key))
+		}
+	})
+
+	t.Run("ecdsa", func(t *testing.T) {
+		tests := []struct {
+			keyType kms.KeyType
+			curve   elliptic.Curve
+		}{
+			{keyType: kms.ECDSAP256TypeDER, curve: elliptic.P256()},
+			{keyType: kms.ECDSAP384TypeIEEEP1363, curve: elliptic.P384()},
+			{keyType: kms.NISTP521ECDHKWType, curve: elliptic.P521()},
+		}
+
+		for _, tc := range tests {
+			privKey, err := ecdsa.GenerateKey(tc.curve, rand.Reader)
+			require.NoError(t, err)
+
+			pkcs8Bytes, err := x509.MarshalPKCS8PrivateKey(privKey)
+			require.NoError(t, err)
+
+			sec1Bytes, err := x509.MarshalECPrivateKey(privKey)
+			require.NoError(t, err)
+
+			for _, privBytes := range [][]byte{pkcs8Bytes, sec1Bytes} {
+				key, e := PrivKeyBytesToKey(privBytes, tc.keyType)
+				require.NoError(t, e)
+				require.True(t, privKey.Equal(key))
+			}
+		}
+	})
+
+	t.Run("secp256k1", func(t *testing.T) {
+		privKey, err := btcec.NewPrivateKey()
+		require.NoError(t, err)
+
+		sec1Bytes, pkcs8Bytes := marshalSecp256k1PrivateKey(t, privKey)
+
+		for _, privBytes := range [][]byte{pkcs8Bytes, sec1Bytes} {
+			for _, keyType := range []kms.KeyType{kms.ECDSASecp256k1TypeDER, kms.ECDSASecp256k1TypeIEEEP1363} {
+				key, e := PrivKeyBytesToKey(privBytes, keyType)
+				require.NoError(t, e)
+				require.True(t, privKey.ToECDSA().Equal(key))
+			}
+		}
+	})
+
+	t.Run("rsa", func(t *testing.T) {
+		privKey, err := rsa.GenerateKey(rand.Reader, 2048)
+		require.NoError(t, err)
+
+		pkcs8Bytes, err := x509.MarshalPKCS8PrivateKey(privKey)
+		require.NoError(t, err)
+
+		for _, privBytes := range [][]byte{pkcs8Bytes, x509.MarshalPKCS1PrivateKey(privKey)} {
+			key, e := PrivKeyBytesToKey(privBytes, kms.RSAPS256)
+			require.NoError(t, e)
+			require.True(t, privKey.Equal(key))
+
+			_, e = PrivKeyBytesToKey(privBytes, kms.RSA4096)
+			require.EqualError(t, e, "privKeyBytesToKey: validate: weak key: RSA key size is below the 4096 bits required by"+
+				" key type RSA4096")
+		}
+	})
+
+	t.Run("error key type mismatch", func(t *testing.T) {
+		_, edPrivKey, err := ed25519.GenerateKey(rand.Reader)
+		require.NoError(t, err)
+
+		edPKCS8, err := x509.MarshalPKCS8PrivateKey(edPrivKey)
+		require.NoError(t, err)
+
+		ecPrivKey, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+		require.NoError(t, err)
+
+		ecPKCS8, err := x509.MarshalPKCS8PrivateKey(ecPrivKey)
+		require.NoError(t, err)
+
+		ecSEC1, err := x509.MarshalECPrivateKey(ecPrivKey)
+		require.NoError(t, err)
+
+		_, err = PrivKeyBytesToKey(ecPKCS8, kms.ED25519Type)
+		require.EqualError(t, err, "privKeyBytesToKey: key type mismatch: expected ed25519 private key, "+
+			"got *ecdsa.PrivateKey")
+
+		_, err = PrivKeyBytesToKey(edPKCS8, kms.X25519ECDHKWType)
+		require.EqualError(t, err, "privKeyBytesToKey: key type mismatch: expected x25519 private key, "+
+			"got ed25519.PrivateKey")
+
+		_, err = PrivKeyBytesToKey(edPKCS8, kms.ECDSAP256TypeDER)
+		require.EqualError(t, err, "privKeyBytesToKey: key type mismatch: expected ecdsa private key, "+
+			"got ed25519.PrivateKey")
+
+		_, err = PrivKeyBytesToKey(ecPKCS8, kms.ECDSAP384TypeDER)
+		require.EqualError(t, err, "privKeyBytesToKey: curve mismatch: expected P-384, got P-256")
+
+		_, err = PrivKeyBytesToKey(edPKCS8, kms.RSARS256)
+		require.EqualError(t, err, "privKeyBytesToKey: key type mismatch: expected rsa private key, "+
+			"got ed25519.PrivateKey")
+
+		_, err = PrivKeyBytesToKey(ecPKCS8, kms.ECDSASecp256k1TypeDER)
+		require.EqualError(t, err, "privKeyBytesToKey: curve mismatch: expected secp256k1, got OID 1.2.840.10045.3.1.7")
+
+		_, err = PrivKeyBytesToKey(ecSEC1, kms.ECDSASecp256k1TypeDER)
+		require.EqualError(t, err, "privKeyBytesToKey: curve mismatch: expected secp256k1, got OID 1.2.840.10045.3.1.7")
+	})
+
+	t.Run("error invalid key bytes", func(t *testing.T) {
+		for _, keyType := range []kms.KeyType{
+			kms.ED25519Type, kms.X25519ECDHKWType, kms.ECDSAP256TypeDER, kms.ECDSASecp256k1TypeDER, kms.RSARS256,
+		} {
+			_, err := PrivKeyBytesToKey([]byte("invalid"), keyType)
+			require.ErrorContains(t, err, "privKeyBytesToKey: failed to parse")
+		}
+
+		_, err := PrivKeyBytesToKey([]byte("invalid"), kms.BLS12381G2Type)
+		require.EqualError(t, err, "privKeyBytesToKey: invalid key type: BLS12381G2")
+	})
+}
+
+func marshalSecp256k1PrivateKey(t *testing.T, privKey *btcec.PrivateKey) ([]byte, []byte) {
+	t.Helper()
+
+	oidECPublicKey := asn1.ObjectIdentifier{1, 2, 840, 10045, 2, 1}
+
+	sec1Bytes, err := asn1.Marshal(ecPrivateKey{
+		Version:       1,
+		PrivateKey:    privKey.Serialize(),
+		NamedCurveOID: oidSecp256k1,
+	})
+	require.NoError(t, err)
+
+	curveOID, err := asn1.Marshal(oidSecp256k1)
+	require.NoError(t, err)
+
+	pkcs8Bytes, err := asn1.Marshal(pkcs8{
+		Algo: pkix.AlgorithmIdentifier{
+			Algorithm:  oidECPublicKey,
+			Parameters: asn1.RawValue{FullBytes: curveOID},
+		},
+		PrivateKey: sec1Bytes,
+	})
+	require.NoError(t, err)
+
+	return sec1Bytes, pkcs8Bytes
+}
+
+func TestPrivateKeyFromJWK(t *testing.T) {
+	_, edPrivKey, err := ed25519.GenerateKey(rand.Reader)
+	require.NoError(t, err)
+
+	ecPrivKey, err := ecdsa.GenerateKey(elliptic.P384(), rand.Reader)
+	require.NoError(t, err)
+
+	secp256k1Key, err := btcec.NewPrivateKey()
+	require.NoError(t, err)
+
+	rsaPrivKey, err := rsa.GenerateKey(rand.Reader, 2048)
+	require.NoError(t, err)
+
+	t.Run("success", func(t *testing.T) {
+		for _, privKey := range []interface{}{edPrivKey, ecPrivKey, secp256k1Key.ToECDSA(), rsaPrivKey} {
+			privJWK, e := JWKFromKey(privKey)
+			require.NoError(t, e)
+
+			jwkBytes, e := privJWK.MarshalJSON()
+			require.NoError(t, e)
+
+			parsed := &jwk.JWK{}
+			require.NoError(t, parsed.UnmarshalJSON(jwkBytes))
+
+			key, e := PrivateKeyFromJWK(parsed)
+			require.NoError(t, e)
+			require.True(t, privKey.(interface{ Equal(crypto.PrivateKey) bool }).Equal(key))
+		}
+	})
+
+	t.Run("error public JWK", func(t *testing.T) {
+		for _, privKey := range []interface{}{edPrivKey, ecPrivKey, rsaPrivKey} {
+			privJWK, e := JWKFromKey(privKey)
+			require.NoError(t, e)
+
+			_, e = PrivateKeyFromJWK(privJWK.Public())
+			require.ErrorIs(t, e, ErrNoPrivateKey)
+		}
+
+		_, err = PrivateKeyFromJWK(&jwk.JWK{JSONWebKey: jose.JSONWebKey{Key: &ecPrivKey.PublicKey}})
+		require.EqualError(t, err, "privateKeyFromJWK: jwk holds no private key, got *ecdsa.PublicKey")
+
+		_, err = PrivateKeyFromJWK(&jwk.JWK{JSONWebKey: jose.JSONWebKey{Key: &ecdsa.PrivateKey{}}})
+		require.ErrorIs(t, err, ErrNoPrivateKey)
+	})
+
+	t.Run("error invalid JWK", func(t *testing.T) {
+		_, err = PrivateKeyFromJWK(nil)
+		require.EqualError(t, err, "privateKeyFromJWK: jwk is nil")
+
+		_, err = PrivateKeyFromJWK(&jwk.JWK{JSONWebKey: jose.JSONWebKey{Key: edPrivKey[:10]}})
+		require.EqualError(t, err, "privateKeyFromJWK: invalid ed25519 private key size 10")
+	})
+}
@@ -0,0 +1,37 @@
+/*
+Copyright Gen Digital Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package jwksupport
+
+import (
+	"fmt"
+
+	"github.com/dellekappa/kms-go/spi/kms"
+)
+
+// RequiredJWKMembers returns the members that must be present in the public JWK of a key of type kt, sorted, e.g.
+// ["crv","kty","x","y"] for EC keys, ["crv","kty","x"] for OKP keys and ["e","kty","n"] for RSA keys. These are the
+// members hashed by Thumbprint, "y" excepted for BLS12-381 keys which are marshalled as compressed points.
+func RequiredJWKMembers(kt kms.KeyType) ([]string, error) {
+	kty, _, ok := keyTypeKtyCrv(kt)
+	if !ok {
+		return nil, fmt.Errorf("requiredJWKMembers: unsupported key type '%s'", kt)
+	}
+
+	compressed := kt == kms.BLS12381G2Type || kt == kms.BLS12381G1Type
+
+	members := make([]string, 0, len(thumbprintMembers[kty]))
+
+	for _, m := range thumbprintMembers[kty] {
+		if compressed && optionalThumbprintMembers[m] {
+			continue
+		}
+
+		members = append(members, m)
+	}
+
+	return members, nil
+}
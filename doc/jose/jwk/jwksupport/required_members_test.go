@@ -0,0 +1,68 @@
+/*
+Copyright Gen Digital Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package jwksupport
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/dellekappa/kms-go/spi/kms"
+)
+
+func TestRequiredJWKMembers(t *testing.T) {
+	for kt, want := range map[kms.KeyType][]string{
+		kms.ECDSAP256TypeIEEEP1363:      {"crv", "kty", "x", "y"},
+		kms.ECDSAP521TypeDER:            {"crv", "kty", "x", "y"},
+		kms.ECDSASecp256k1TypeIEEEP1363: {"crv", "kty", "x", "y"},
+		kms.NISTP384ECDHKWType:          {"crv", "kty", "x", "y"},
+		kms.ED25519Type:                 {"crv", "kty", "x"},
+		kms.X25519ECDHKWType:            {"crv", "kty", "x"},
+		kms.BLS12381G2Type:              {"crv", "kty", "x"},
+		kms.RSAPS256Type:                {"e", "kty", "n"},
+		kms.RSA4096Type:                 {"e", "kty", "n"},
+		kms.MLDSA65Type:                 {"alg", "kty", "pub"},
+	} {
+		members, err := RequiredJWKMembers(kt)
+		require.NoError(t, err, kt)
+		require.Equal(t, want, members, kt)
+	}
+
+	// the returned slice is a copy of the thumbprint members.
+	members, err := RequiredJWKMembers(kms.ED25519Type)
+	require.NoError(t, err)
+
+	members[0] = "d"
+	require.Equal(t, []string{"crv", "kty", "x"}, thumbprintMembers[okpKty])
+
+	_, err = RequiredJWKMembers(kms.AES256GCMType)
+	require.EqualError(t, err, "requiredJWKMembers: unsupported key type 'AES256GCM'")
+
+	t.Run("members of generated keys", func(t *testing.T) {
+		for _, kt := range []kms.KeyType{
+			kms.ECDSAP384TypeIEEEP1363, kms.ECDSASecp256k1TypeIEEEP1363, kms.ED25519Type, kms.BLS12381G2Type,
+		} {
+			key, err := GenerateJWK(kt)
+			require.NoError(t, err, kt)
+
+			pubBytes, err := json.Marshal(key.Public())
+			require.NoError(t, err, kt)
+
+			var pubMembers map[string]interface{}
+
+			require.NoError(t, json.Unmarshal(pubBytes, &pubMembers), kt)
+
+			required, err := RequiredJWKMembers(kt)
+			require.NoError(t, err, kt)
+
+			for _, m := range required {
+				require.Contains(t, pubMembers, m, kt)
+			}
+		}
+	})
+}
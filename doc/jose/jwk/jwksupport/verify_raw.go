@@ -0,0 +1,124 @@
+/*
+Copyright Gen Digital Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package jwksupport
+
+import (
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/rsa"
+	"errors"
+	"fmt"
+	"math/big"
+
+	"github.com/dellekappa/kms-go/doc/jose/jwk"
+	"github.com/dellekappa/kms-go/spi/kms"
+)
+
+// ErrInvalidSignature is returned by VerifyRaw when the signature does not verify.
+var ErrInvalidSignature = errors.New("invalid signature")
+
+// VerifyRaw verifies sig, the signature of msg by the key of type kt marshalled as pubBytes (as exported by the KMS,
+// see PubKeyBytesToKey), for callers holding raw public key bytes rather than a JWK. Signatures are verified as the
+// KMS creates them:
+//   - ED25519Type: Ed25519 over msg.
+//   - ECDSA DER key types: ASN.1 DER signature over the SHA-256, SHA-384 or SHA-512 (P-256, P-384, P-521) digest of
+//     msg, SHA-256 for secp256k1.
+//   - ECDSA IEEE-P1363 key types: the same with the r||s signature encoding.
+//   - RSARS256Type: RSASSA-PKCS1-v1_5 with SHA-256, RSAPS256Type: RSASSA-PSS with SHA-256 and a 32 bytes salt.
+//
+// A signature which does not verify returns an error wrapping ErrInvalidSignature. RSA keys smaller than
+// jwk.DefaultMinRSAKeyBits, or than the size set with jwk.WithMinRSAKeyBits, are rejected with an error wrapping
+// jwk.ErrWeakKey.
+func VerifyRaw(sig, msg, pubBytes []byte, kt kms.KeyType, opts ...jwk.ValidateOpt) error {
+	pubKey, err := PubKeyBytesToKey(pubBytes, kt)
+	if err != nil {
+		return fmt.Errorf("verifyRaw: %w", err)
+	}
+
+	switch kt {
+	case kms.ED25519Type:
+		edKey, ok := pubKey.(ed25519.PublicKey)
+		if !ok || len(edKey) != ed25519.PublicKeySize {
+			return fmt.Errorf("verifyRaw: invalid %s public key", kt)
+		}
+
+		if !ed25519.Verify(edKey, msg, sig) {
+			return fmt.Errorf("verifyRaw: %w", ErrInvalidSignature)
+		}
+	case kms.ECDSAP256TypeDER, kms.ECDSAP384TypeDER, kms.ECDSAP521TypeDER, kms.ECDSASecp256k1TypeDER,
+		kms.ECDSAP256TypeIEEEP1363, kms.ECDSAP384TypeIEEEP1363, kms.ECDSAP521TypeIEEEP1363,
+		kms.ECDSASecp256k1TypeIEEEP1363:
+		ecKey, ok := pubKey.(*ecdsa.PublicKey)
+		if !ok {
+			return fmt.Errorf("verifyRaw: invalid %s public key", kt)
+		}
+
+		if !verifyECDSA(ecKey, sig, msg, kt) {
+			return fmt.Errorf("verifyRaw: %w", ErrInvalidSignature)
+		}
+	case kms.RSARS256Type, kms.RSAPS256Type:
+		rsaKey, ok := pubKey.(*rsa.PublicKey)
+		if !ok {
+			return fmt.Errorf("verifyRaw: invalid %s public key", kt)
+		}
+
+		if err = jwk.CheckRSAKeySize(rsaKey, jwk.MinRSAKeyBits(opts...)); err != nil {
+			return fmt.Errorf("verifyRaw: %w", err)
+		}
+
+		digest := crypto.SHA256.New()
+		digest.Write(msg)
+
+		if kt == kms.RSAPS256Type {
+			err = rsa.VerifyPSS(rsaKey, crypto.SHA256, digest.Sum(nil), sig,
+				&rsa.PSSOptions{SaltLength: rsa.PSSSaltLengthEqualsHash})
+		} else {
+			err = rsa.VerifyPKCS1v15(rsaKey, crypto.SHA256, digest.Sum(nil), sig)
+		}
+
+		if err != nil {
+			return fmt.Errorf("verifyRaw: %w", ErrInvalidSignature)
+		}
+	default:
+		return fmt.Errorf("verifyRaw: key type %s is not supported", kt)
+	}
+
+	return nil
+}
+
+func verifyECDSA(pubKey *ecdsa.PublicKey, sig, msg []byte, kt kms.KeyType) bool {
+	var h crypto.Hash
+
+	switch kt {
+	case kms.ECDSAP384TypeDER, kms.ECDSAP384TypeIEEEP1363:
+		h = crypto.SHA384
+	case kms.ECDSAP521TypeDER, kms.ECDSAP521TypeIEEEP1363:
+		h = crypto.SHA512
+	default:
+		h = crypto.SHA256
+	}
+
+	digest := h.New()
+	digest.Write(msg)
+
+	switch kt {
+	case kms.ECDSAP256TypeDER, kms.ECDSAP384TypeDER, kms.ECDSAP521TypeDER, kms.ECDSASecp256k1TypeDER:
+		return ecdsa.VerifyASN1(pubKey, digest.Sum(nil), sig)
+	}
+
+	keySize := (pubKey.Curve.Params().BitSize + 7) / 8 //nolint:gomnd
+
+	if len(sig) != 2*keySize {
+		return false
+	}
+
+	r := new(big.Int).SetBytes(sig[:keySize])
+	s := new(big.Int).SetBytes(sig[keySize:])
+
+	return ecdsa.Verify(pubKey, digest.Sum(nil), r, s)
+}
@@ -0,0 +1,132 @@
+/*
+Copyright Gen Digital Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package jwksupport
+
+import (
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
+	"crypto/sha512"
+	"crypto/x509"
+	"testing"
+
+	"github.com/btcsuite/btcd/btcec/v2"
+	"github.com/stretchr/testify/require"
+
+	"github.com/dellekappa/kms-go/doc/jose/jwk"
+	"github.com/dellekappa/kms-go/spi/kms"
+)
+
+func TestVerifyRaw(t *testing.T) {
+	msg := []byte("message")
+
+	edPub, edPriv, err := ed25519.GenerateKey(rand.Reader)
+	require.NoError(t, err)
+
+	p256Key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+
+	p384Key, err := ecdsa.GenerateKey(elliptic.P384(), rand.Reader)
+	require.NoError(t, err)
+
+	secp256k1Key, err := btcec.NewPrivateKey()
+	require.NoError(t, err)
+
+	rsaKey, err := rsa.GenerateKey(rand.Reader, 2048)
+	require.NoError(t, err)
+
+	p256DER, err := x509.MarshalPKIXPublicKey(&p256Key.PublicKey)
+	require.NoError(t, err)
+
+	rsaDER, err := x509.MarshalPKIXPublicKey(&rsaKey.PublicKey)
+	require.NoError(t, err)
+
+	sha256Digest := sha256.Sum256(msg)
+	sha384Digest := sha512.Sum384(msg)
+
+	p256Sig, err := ecdsa.SignASN1(rand.Reader, p256Key, sha256Digest[:])
+	require.NoError(t, err)
+
+	p384R, p384S, err := ecdsa.Sign(rand.Reader, p384Key, sha384Digest[:])
+	require.NoError(t, err)
+
+	p384Sig := make([]byte, 96)
+	p384R.FillBytes(p384Sig[:48])
+	p384S.FillBytes(p384Sig[48:])
+
+	secp256k1R, secp256k1S, err := ecdsa.Sign(rand.Reader, secp256k1Key.ToECDSA(), sha256Digest[:])
+	require.NoError(t, err)
+
+	secp256k1Sig := make([]byte, 64)
+	secp256k1R.FillBytes(secp256k1Sig[:32])
+	secp256k1S.FillBytes(secp256k1Sig[32:])
+
+	rs256Sig, err := rsa.SignPKCS1v15(rand.Reader, rsaKey, crypto.SHA256, sha256Digest[:])
+	require.NoError(t, err)
+
+	ps256Sig, err := rsa.SignPSS(rand.Reader, rsaKey, crypto.SHA256, sha256Digest[:],
+		&rsa.PSSOptions{SaltLength: rsa.PSSSaltLengthEqualsHash})
+	require.NoError(t, err)
+
+	tests := []struct {
+		kt       kms.KeyType
+		pubBytes []byte
+		sig      []byte
+	}{
+		{kms.ED25519Type, edPub, ed25519.Sign(edPriv, msg)},
+		{kms.ECDSAP256TypeDER, p256DER, p256Sig},
+		{kms.ECDSAP384TypeIEEEP1363, elliptic.Marshal(elliptic.P384(), p384Key.X, p384Key.Y), p384Sig}, //nolint:staticcheck
+		{kms.ECDSASecp256k1TypeIEEEP1363, secp256k1Key.PubKey().SerializeUncompressed(), secp256k1Sig},
+		{kms.RSARS256Type, rsaDER, rs256Sig},
+		{kms.RSAPS256Type, rsaDER, ps256Sig},
+	}
+
+	t.Run("success", func(t *testing.T) {
+		for _, tc := range tests {
+			require.NoError(t, VerifyRaw(tc.sig, msg, tc.pubBytes, tc.kt), tc.kt)
+		}
+	})
+
+	t.Run("invalid signature", func(t *testing.T) {
+		for _, tc := range tests {
+			err := VerifyRaw(tc.sig, []byte("other message"), tc.pubBytes, tc.kt)
+			require.ErrorIs(t, err, ErrInvalidSignature, tc.kt)
+
+			err = VerifyRaw(tc.sig[1:], msg, tc.pubBytes, tc.kt)
+			require.ErrorIs(t, err, ErrInvalidSignature, tc.kt)
+		}
+
+		// RS256 signature verified as PS256.
+		err := VerifyRaw(rs256Sig, msg, rsaDER, kms.RSAPS256Type)
+		require.ErrorIs(t, err, ErrInvalidSignature)
+	})
+
+	t.Run("minimum RSA key size", func(t *testing.T) {
+		require.NoError(t, VerifyRaw(rs256Sig, msg, rsaDER, kms.RSARS256Type, jwk.WithMinRSAKeyBits(2048)))
+
+		err := VerifyRaw(rs256Sig, msg, rsaDER, kms.RSARS256Type, jwk.WithMinRSAKeyBits(3072))
+		require.ErrorIs(t, err, jwk.ErrWeakKey)
+	})
+
+	t.Run("invalid key", func(t *testing.T) {
+		err := VerifyRaw(p256Sig, msg, []byte("invalid"), kms.ECDSAP256TypeDER)
+		require.ErrorContains(t, err, "verifyRaw: failed to parse ecdsa key in DER format")
+
+		err = VerifyRaw(p256Sig, msg, edPub[1:], kms.ED25519Type)
+		require.EqualError(t, err, "verifyRaw: invalid ED25519 public key")
+
+		err = VerifyRaw(nil, msg, edPub, "badType")
+		require.EqualError(t, err, "verifyRaw: invalid key type: badType")
+
+		err = VerifyRaw(nil, msg, make([]byte, 1312), kms.MLDSA44Type)
+		require.EqualError(t, err, "verifyRaw: key type MLDSA44 is not supported")
+	})
+}
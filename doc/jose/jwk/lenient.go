@@ -0,0 +1,89 @@
+/*
+Copyright Gen Digital Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package jwk
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"strings"
+)
+
+// lenientMembers are the base64url encoded key value members normalized by ParseJWKLenient.
+var lenientMembers = []string{ //nolint:gochecknoglobals
+	"x", "y", "d", "n", "e", "p", "q", "dp", "dq", "qi", "k", "pub", "priv",
+}
+
+// ParseJWKLenient decodes a JWK whose key value members (x, y, d, n, e, p, q, dp, dq, qi, k, pub and priv) may be
+// encoded with the standard base64 alphabet instead of base64url, with or without padding, as produced by some
+// non-conformant implementations. The members are normalized to unpadded base64url before the JWK is decoded as by
+// UnmarshalJSON, which stays strict.
+//
+// Security caveat: leniency means several encodings map to the same key, so the JWK bytes no longer identify the key
+// uniquely. Don't compare, hash or sign the raw JWK of a leniently parsed key, compute its thumbprint from the parsed
+// JWK instead, and only use ParseJWKLenient for input from known non-conformant sources.
+func ParseJWKLenient(data []byte) (*JWK, error) {
+	var members map[string]json.RawMessage
+
+	err := json.Unmarshal(data, &members)
+	if err != nil {
+		return nil, fmt.Errorf("parseJWKLenient: unable to read JWK: %w", err)
+	}
+
+	for _, name := range lenientMembers {
+		rawValue, ok := members[name]
+		if !ok {
+			continue
+		}
+
+		var value string
+
+		if err = json.Unmarshal(rawValue, &value); err != nil {
+			// not a string, left to UnmarshalJSON to reject.
+			continue
+		}
+
+		decoded, err := decodeLenientBase64(value)
+		if err != nil {
+			return nil, fmt.Errorf("parseJWKLenient: %w", &JWKError{
+				Field:  name,
+				Reason: "is neither base64url nor base64 encoded",
+				Err:    err,
+			})
+		}
+
+		members[name], err = json.Marshal(base64.RawURLEncoding.EncodeToString(decoded))
+		if err != nil {
+			return nil, fmt.Errorf("parseJWKLenient: %w", err)
+		}
+	}
+
+	normalized, err := json.Marshal(members)
+	if err != nil {
+		return nil, fmt.Errorf("parseJWKLenient: %w", err)
+	}
+
+	key := &JWK{}
+
+	err = key.UnmarshalJSON(normalized)
+	if err != nil {
+		return nil, fmt.Errorf("parseJWKLenient: %w", err)
+	}
+
+	return key, nil
+}
+
+// decodeLenientBase64 decodes s encoded with either the base64url or the standard base64 alphabet, padded or not.
+func decodeLenientBase64(s string) ([]byte, error) {
+	s = strings.TrimRight(s, "=")
+
+	if strings.ContainsAny(s, "+/") {
+		return base64.RawStdEncoding.DecodeString(s)
+	}
+
+	return base64.RawURLEncoding.DecodeString(s)
+}
@@ -0,0 +1,92 @@
+/*
+Copyright Gen Digital Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package jwk
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"testing"
+
+	"github.com/go-jose/go-jose/v3"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseJWKLenient(t *testing.T) {
+	// a key with coordinates encoded with '+' or '/' in standard base64.
+	var ecKey *ecdsa.PrivateKey
+
+	for {
+		key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+		require.NoError(t, err)
+
+		std := base64.StdEncoding.EncodeToString(key.X.FillBytes(make([]byte, 32)))
+		if base64.RawURLEncoding.EncodeToString(key.X.FillBytes(make([]byte, 32))) != std[:len(std)-1] {
+			ecKey = key
+
+			break
+		}
+	}
+
+	strictJWK, err := (&JWK{JSONWebKey: jose.JSONWebKey{Key: &ecKey.PublicKey, KeyID: "kid"}}).MarshalJSON()
+	require.NoError(t, err)
+
+	stdJWK := func(encoding *base64.Encoding) []byte {
+		jwkBytes, err := json.Marshal(map[string]string{
+			"kty": "EC",
+			"crv": "P-256",
+			"kid": "kid",
+			"x":   encoding.EncodeToString(ecKey.X.FillBytes(make([]byte, 32))),
+			"y":   encoding.EncodeToString(ecKey.Y.FillBytes(make([]byte, 32))),
+		})
+		require.NoError(t, err)
+
+		return jwkBytes
+	}
+
+	for name, jwkBytes := range map[string][]byte{
+		"base64url":        strictJWK,
+		"padded base64url": stdJWK(base64.URLEncoding),
+		"base64":           stdJWK(base64.StdEncoding),
+		"unpadded base64":  stdJWK(base64.RawStdEncoding),
+	} {
+		t.Run(name, func(t *testing.T) {
+			key, err := ParseJWKLenient(jwkBytes)
+			require.NoError(t, err)
+			require.Equal(t, "kid", key.KeyID)
+			require.True(t, key.SamePublicKey(&JWK{JSONWebKey: jose.JSONWebKey{Key: &ecKey.PublicKey}}))
+		})
+	}
+
+	t.Run("UnmarshalJSON stays strict", func(t *testing.T) {
+		require.Error(t, (&JWK{}).UnmarshalJSON(stdJWK(base64.StdEncoding)))
+	})
+
+	t.Run("extra members are preserved", func(t *testing.T) {
+		key, err := ParseJWKLenient([]byte(`{"ext":true,` + string(stdJWK(base64.StdEncoding))[1:]))
+		require.NoError(t, err)
+		require.Equal(t, map[string]interface{}{"ext": true}, key.Extra)
+	})
+
+	t.Run("errors", func(t *testing.T) {
+		_, err := ParseJWKLenient([]byte("not json"))
+		require.ErrorContains(t, err, "parseJWKLenient: unable to read JWK")
+
+		_, err = ParseJWKLenient([]byte(`{"kty":"EC","crv":"P-256","x":"a-b+","y":"AAAA"}`))
+
+		var jwkErr *JWKError
+
+		require.True(t, errors.As(err, &jwkErr))
+		require.Equal(t, "x", jwkErr.Field)
+
+		_, err = ParseJWKLenient([]byte(`{"kty":"EC","crv":"P-256","x":"AAAA","y":"AAAA"}`))
+		require.ErrorContains(t, err, "parseJWKLenient:")
+	})
+}
@@ -0,0 +1,197 @@
+/*
+Copyright Gen Digital Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package jwk
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+)
+
+const (
+	// DefaultMaxModulusBits is the default maximum bit length of an RSA modulus accepted by ParseJWKWithOptions.
+	DefaultMaxModulusBits = 8192
+	// DefaultMaxCertChainLength is the default maximum number of x5c certificates accepted by ParseJWKWithOptions.
+	DefaultMaxCertChainLength = 10
+	// DefaultMaxFieldLength is the default maximum length, in encoded characters, of a base64 or base64url encoded
+	// member (key values, thumbprints and x5c certificates) accepted by ParseJWKWithOptions.
+	DefaultMaxFieldLength = 16384
+)
+
+// DecodeOptions are the limits applied by ParseJWKWithOptions to decode JWKs from untrusted sources. A zero value
+// limit selects its default.
+type DecodeOptions struct {
+	// MaxModulusBits is the maximum bit length of the RSA modulus "n", DefaultMaxModulusBits if zero.
+	MaxModulusBits int
+	// MaxCertChainLength is the maximum number of certificates in the "x5c" chain, DefaultMaxCertChainLength if zero.
+	MaxCertChainLength int
+	// MaxFieldLength is the maximum length, in encoded characters, of each base64 or base64url encoded member,
+	// DefaultMaxFieldLength if zero.
+	MaxFieldLength int
+	// StrictBase64 rejects base64url encoded members which are not in the canonical encoding of their value: padded,
+	// with non-zero trailing bits or with line breaks. Such members decode to the same key as its canonical JWK, e.g.
+	// letting a re-encoded JWK pass for a different key in signed or hashed content.
+	StrictBase64 bool
+}
+
+// encodedMembers are the base64url encoded JWK members, "x5c" certificates being base64 encoded.
+var encodedMembers = []string{ //nolint:gochecknoglobals
+	"x", "y", "d", "n", "e", "p", "q", "dp", "dq", "qi", "k", "pub", "priv", "x5t", "x5t#S256",
+}
+
+func (o DecodeOptions) withDefaults() DecodeOptions {
+	if o.MaxModulusBits <= 0 {
+		o.MaxModulusBits = DefaultMaxModulusBits
+	}
+
+	if o.MaxCertChainLength <= 0 {
+		o.MaxCertChainLength = DefaultMaxCertChainLength
+	}
+
+	if o.MaxFieldLength <= 0 {
+		o.MaxFieldLength = DefaultMaxFieldLength
+	}
+
+	return o
+}
+
+// ParseJWKWithOptions decodes a JWK from an untrusted source as UnmarshalJSON does, after checking its members
+// against the limits of opts: the sizes of the encoded members, the number of x5c certificates and the bit length
+// of the RSA modulus are checked before they are decoded, so that oversized JWKs fail with a JWKError wrapping
+// ErrInvalidKey instead of being allocated and processed. UnmarshalJSON doesn't apply any limit.
+func ParseJWKWithOptions(data []byte, opts DecodeOptions) (*JWK, error) {
+	opts = opts.withDefaults()
+
+	var members map[string]json.RawMessage
+
+	err := json.Unmarshal(data, &members)
+	if err != nil {
+		return nil, fmt.Errorf("parseJWKWithOptions: unable to read JWK: %w", err)
+	}
+
+	for _, name := range encodedMembers {
+		// the JSON string length bounds the encoded value length, escapes only making it longer.
+		if len(members[name]) > opts.MaxFieldLength+len(`""`) {
+			return nil, fmt.Errorf("parseJWKWithOptions: %w", fieldLengthError(name, opts.MaxFieldLength))
+		}
+	}
+
+	if opts.StrictBase64 {
+		err = checkCanonicalBase64(members)
+		if err != nil {
+			return nil, fmt.Errorf("parseJWKWithOptions: %w", err)
+		}
+	}
+
+	err = checkModulusBits(members["n"], opts.MaxModulusBits)
+	if err != nil {
+		return nil, fmt.Errorf("parseJWKWithOptions: %w", err)
+	}
+
+	err = checkCertChain(members["x5c"], opts)
+	if err != nil {
+		return nil, fmt.Errorf("parseJWKWithOptions: %w", err)
+	}
+
+	key := &JWK{}
+
+	err = key.UnmarshalJSON(data)
+	if err != nil {
+		return nil, fmt.Errorf("parseJWKWithOptions: %w", err)
+	}
+
+	return key, nil
+}
+
+// checkCanonicalBase64 checks the base64url encoded members are encoded exactly as base64.RawURLEncoding encodes
+// their value.
+func checkCanonicalBase64(members map[string]json.RawMessage) error {
+	for _, name := range encodedMembers {
+		rawValue, ok := members[name]
+		if !ok {
+			continue
+		}
+
+		var value string
+
+		if err := json.Unmarshal(rawValue, &value); err != nil {
+			continue // not a string, left to UnmarshalJSON to reject.
+		}
+
+		decoded, err := base64.RawURLEncoding.Strict().DecodeString(value)
+		if err != nil || base64.RawURLEncoding.EncodeToString(decoded) != value {
+			return &JWKError{
+				Field:  name,
+				Reason: "must be canonical unpadded base64url",
+				Err:    ErrInvalidKey,
+			}
+		}
+	}
+
+	return nil
+}
+
+// checkModulusBits checks the bit length of the base64url encoded RSA modulus rawN, if set, is at most maxBits.
+func checkModulusBits(rawN json.RawMessage, maxBits int) error {
+	if rawN == nil {
+		return nil
+	}
+
+	var n byteBuffer
+
+	if err := n.UnmarshalJSON(rawN); err != nil {
+		return nil //nolint:nilerr // an invalid modulus is rejected by UnmarshalJSON.
+	}
+
+	if bits := bitLen(n.bigInt()); bits > maxBits {
+		return &JWKError{
+			Field:  "n",
+			Reason: fmt.Sprintf("modulus must be at most %d bits, got %d", maxBits, bits),
+			Err:    ErrInvalidKey,
+		}
+	}
+
+	return nil
+}
+
+// checkCertChain checks the x5c chain rawX5C, if set, has at most opts.MaxCertChainLength certificates of at most
+// opts.MaxFieldLength encoded characters each.
+func checkCertChain(rawX5C json.RawMessage, opts DecodeOptions) error {
+	if rawX5C == nil {
+		return nil
+	}
+
+	var chain []json.RawMessage
+
+	if err := json.Unmarshal(rawX5C, &chain); err != nil {
+		return nil //nolint:nilerr // an invalid chain is rejected by UnmarshalJSON.
+	}
+
+	if len(chain) > opts.MaxCertChainLength {
+		return &JWKError{
+			Field:  "x5c",
+			Reason: fmt.Sprintf("must have at most %d certificates, got %d", opts.MaxCertChainLength, len(chain)),
+			Err:    ErrInvalidKey,
+		}
+	}
+
+	for _, cert := range chain {
+		if len(cert) > opts.MaxFieldLength+len(`""`) {
+			return fieldLengthError("x5c", opts.MaxFieldLength)
+		}
+	}
+
+	return nil
+}
+
+func fieldLengthError(field string, maxLength int) *JWKError {
+	return &JWKError{
+		Field:  field,
+		Reason: fmt.Sprintf("must be at most %d characters long", maxLength),
+		Err:    ErrInvalidKey,
+	}
+}
@@ -0,0 +1,176 @@
+/*
+Copyright Gen Digital Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package jwk
+
+import (
+	"bytes"
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/rsa"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/go-jose/go-jose/v3"
+	"github.com/stretchr/testify/require"
+)
+
+func rsaJWKWithModulusBits(bits int) []byte {
+	n := bytes.Repeat([]byte{0xff}, (bits+7)/8)
+	n[0] >>= (8 - bits%8) % 8
+
+	return []byte(fmt.Sprintf(`{"kty":"RSA","n":"%s","e":"AQAB"}`, base64.RawURLEncoding.EncodeToString(n)))
+}
+
+func TestParseJWKWithOptions(t *testing.T) {
+	ecKey, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+
+	ecJWK, err := (&JWK{JSONWebKey: jose.JSONWebKey{Key: &ecKey.PublicKey, KeyID: "kid"}}).MarshalJSON()
+	require.NoError(t, err)
+
+	requireJWKError := func(t *testing.T, err error, field, reason string) {
+		t.Helper()
+
+		var jwkErr *JWKError
+
+		require.True(t, errors.As(err, &jwkErr), err)
+		require.ErrorIs(t, err, ErrInvalidKey)
+		require.Equal(t, field, jwkErr.Field)
+		require.Equal(t, reason, jwkErr.Reason)
+	}
+
+	t.Run("success with default limits", func(t *testing.T) {
+		key, err := ParseJWKWithOptions(ecJWK, DecodeOptions{})
+		require.NoError(t, err)
+		require.Equal(t, "kid", key.KeyID)
+
+		key, err = ParseJWKWithOptions(rsaJWKWithModulusBits(DefaultMaxModulusBits), DecodeOptions{})
+		require.NoError(t, err)
+		require.Equal(t, DefaultMaxModulusBits, key.Key.(*rsa.PublicKey).N.BitLen())
+	})
+
+	t.Run("modulus too large", func(t *testing.T) {
+		jwkBytes := rsaJWKWithModulusBits(DefaultMaxModulusBits + 1)
+
+		_, err := ParseJWKWithOptions(jwkBytes, DecodeOptions{})
+		requireJWKError(t, err, "n", "modulus must be at most 8192 bits, got 8193")
+
+		_, err = ParseJWKWithOptions(jwkBytes, DecodeOptions{MaxModulusBits: 16384})
+		require.NoError(t, err)
+
+		_, err = ParseJWKWithOptions(rsaJWKWithModulusBits(2049), DecodeOptions{MaxModulusBits: 2048})
+		requireJWKError(t, err, "n", "modulus must be at most 2048 bits, got 2049")
+	})
+
+	t.Run("field too long", func(t *testing.T) {
+		_, err := ParseJWKWithOptions(ecJWK, DecodeOptions{MaxFieldLength: 42})
+		requireJWKError(t, err, "x", "must be at most 42 characters long")
+
+		_, err = ParseJWKWithOptions(ecJWK, DecodeOptions{MaxFieldLength: 43})
+		require.NoError(t, err)
+
+		_, err = ParseJWKWithOptions(rsaJWKWithModulusBits(DefaultMaxModulusBits),
+			DecodeOptions{MaxFieldLength: 1024})
+		requireJWKError(t, err, "n", "must be at most 1024 characters long")
+	})
+
+	t.Run("x5c chain", func(t *testing.T) {
+		withChain := func(certs ...string) []byte {
+			return []byte(fmt.Sprintf(`{"kty":"oct","k":"AAAA","x5c":["%s"]}`, strings.Join(certs, `","`)))
+		}
+
+		_, err := ParseJWKWithOptions(withChain("AAAA", "AAAA", "AAAA"), DecodeOptions{MaxCertChainLength: 2})
+		requireJWKError(t, err, "x5c", "must have at most 2 certificates, got 3")
+
+		_, err = ParseJWKWithOptions(withChain(strings.Repeat("A", 20)), DecodeOptions{MaxFieldLength: 16})
+		requireJWKError(t, err, "x5c", "must be at most 16 characters long")
+	})
+
+	t.Run("invalid JSON", func(t *testing.T) {
+		_, err := ParseJWKWithOptions([]byte("not json"), DecodeOptions{})
+		require.ErrorContains(t, err, "parseJWKWithOptions: unable to read JWK")
+
+		_, err = ParseJWKWithOptions([]byte(`{"kty":"RSA","n":1,"e":"AQAB"}`), DecodeOptions{})
+		require.ErrorContains(t, err, "parseJWKWithOptions:")
+	})
+}
+
+func TestParseJWKWithOptionsStrictBase64(t *testing.T) {
+	pub, _, err := ed25519.GenerateKey(rand.Reader)
+	require.NoError(t, err)
+
+	x := base64.RawURLEncoding.EncodeToString(pub)
+	okpJWK := func(x string) []byte {
+		return []byte(fmt.Sprintf(`{"kty":"OKP","crv":"Ed25519","x":"%s"}`, x))
+	}
+
+	strict := DecodeOptions{StrictBase64: true}
+
+	t.Run("canonical key", func(t *testing.T) {
+		key, err := ParseJWKWithOptions(okpJWK(x), strict)
+		require.NoError(t, err)
+		require.Equal(t, pub, key.Key)
+	})
+
+	t.Run("re-padded key", func(t *testing.T) {
+		// the last of the 43 characters of x carries 4 bits, setting its 2 trailing bits re-encodes the same key.
+		const alphabet = "ABCDEFGHIJKLMNOPQRSTUVWXYZabcdefghijklmnopqrstuvwxyz0123456789-_"
+
+		last := strings.IndexByte(alphabet, x[len(x)-1])
+		repadded := x[:len(x)-1] + string(alphabet[last|0x03])
+		require.NotEqual(t, x, repadded)
+
+		key, err := ParseJWKWithOptions(okpJWK(repadded), DecodeOptions{})
+		require.NoError(t, err)
+		require.Equal(t, pub, key.Key)
+
+		_, err = ParseJWKWithOptions(okpJWK(repadded), strict)
+		require.ErrorIs(t, err, ErrInvalidKey)
+
+		var jwkErr *JWKError
+
+		require.True(t, errors.As(err, &jwkErr))
+		require.Equal(t, "x", jwkErr.Field)
+		require.Equal(t, "must be canonical unpadded base64url", jwkErr.Reason)
+	})
+
+	t.Run("padded and line broken key", func(t *testing.T) {
+		for _, encoded := range []string{x + "=", x[:20] + `\n` + x[20:]} {
+			_, err := ParseJWKWithOptions(okpJWK(encoded), strict)
+			require.ErrorIs(t, err, ErrInvalidKey)
+		}
+	})
+}
+
+func FuzzParseJWKWithOptions(f *testing.F) {
+	f.Add([]byte(`{"kty":"EC","crv":"P-256","x":"AAAA","y":"AAAA"}`))
+	f.Add([]byte(`{"kty":"OKP","crv":"Ed25519","x":"11qYAYKxCrfVS_7TyWQHOg7hcvPapiMlrwIaaPcHURo"}`))
+	f.Add(rsaJWKWithModulusBits(2048))
+	f.Add([]byte(`{"kty":"RSA","n":"AQAB","e":"AQAB","x5c":["AAAA"]}`))
+
+	opts := DecodeOptions{MaxModulusBits: 4096, MaxCertChainLength: 2, MaxFieldLength: 1024}
+
+	f.Fuzz(func(t *testing.T, data []byte) {
+		key, err := ParseJWKWithOptions(data, opts)
+		if err != nil {
+			return
+		}
+
+		if rsaKey, ok := key.Key.(*rsa.PublicKey); ok && rsaKey.N.BitLen() > opts.MaxModulusBits {
+			t.Fatalf("modulus of %d bits exceeds the limit", rsaKey.N.BitLen())
+		}
+
+		if len(key.Certificates) > opts.MaxCertChainLength {
+			t.Fatalf("x5c chain of %d certificates exceeds the limit", len(key.Certificates))
+		}
+	})
+}
@@ -0,0 +1,72 @@
+/*
+Copyright Gen Digital Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package jwk
+
+import (
+	"bufio"
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+)
+
+// LineError is the parse error of a line of newline-delimited JWKs.
+type LineError struct {
+	// Line is the 1-based line number.
+	Line int
+	Err  error
+}
+
+// Error returns the line number and the parse error.
+func (e *LineError) Error() string {
+	return fmt.Sprintf("line %d: %v", e.Line, e.Err)
+}
+
+// Unwrap returns the parse error.
+func (e *LineError) Unwrap() error {
+	return e.Err
+}
+
+// ParseJWKLines decodes newline-delimited JWKs (one JSON JWK per line, not a JWKS object) from r. Blank lines are
+// skipped. A line that fails to parse does not abort the stream: the JWKs of the valid lines are returned in order
+// along with an error joining a *LineError per invalid line. A read error of r stops parsing and is returned joined
+// with the line errors found so far.
+func ParseJWKLines(r io.Reader) ([]*JWK, error) {
+	var (
+		keys []*JWK
+		errs []error
+	)
+
+	reader := bufio.NewReader(r)
+
+	for lineNumber := 1; ; lineNumber++ {
+		// ReadBytes is used rather than a bufio.Scanner since JWKs with certificate chains may exceed its token size.
+		line, readErr := reader.ReadBytes('\n')
+
+		if line = bytes.TrimSpace(line); len(line) > 0 {
+			key := &JWK{}
+
+			if err := key.UnmarshalJSON(line); err != nil {
+				errs = append(errs, &LineError{Line: lineNumber, Err: err})
+			} else {
+				keys = append(keys, key)
+			}
+		}
+
+		if errors.Is(readErr, io.EOF) {
+			break
+		}
+
+		if readErr != nil {
+			errs = append(errs, fmt.Errorf("parseJWKLines: read line %d: %w", lineNumber, readErr))
+
+			break
+		}
+	}
+
+	return keys, errors.Join(errs...)
+}
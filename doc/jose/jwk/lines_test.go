@@ -0,0 +1,91 @@
+/*
+Copyright Gen Digital Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package jwk
+
+import (
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/elliptic"
+	"crypto/rand"
+	"errors"
+	"io"
+	"strings"
+	"testing"
+
+	"github.com/go-jose/go-jose/v3"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseJWKLines(t *testing.T) {
+	ecKey, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+
+	edPub, _, err := ed25519.GenerateKey(rand.Reader)
+	require.NoError(t, err)
+
+	ecJWK, err := (&JWK{JSONWebKey: jose.JSONWebKey{Key: &ecKey.PublicKey, KeyID: "ec"}}).MarshalJSON()
+	require.NoError(t, err)
+
+	edJWK, err := (&JWK{JSONWebKey: jose.JSONWebKey{Key: edPub, KeyID: "ed"}}).MarshalJSON()
+	require.NoError(t, err)
+
+	t.Run("success with blank lines and CRLF", func(t *testing.T) {
+		stream := "\n" + string(ecJWK) + "\r\n   \n" + string(edJWK)
+
+		keys, err := ParseJWKLines(strings.NewReader(stream))
+		require.NoError(t, err)
+		require.Len(t, keys, 2)
+		require.Equal(t, "ec", keys[0].KeyID)
+		require.True(t, ecKey.PublicKey.Equal(keys[0].Key))
+		require.Equal(t, "ed", keys[1].KeyID)
+		require.Equal(t, edPub, keys[1].Key)
+	})
+
+	t.Run("empty stream", func(t *testing.T) {
+		keys, err := ParseJWKLines(strings.NewReader(""))
+		require.NoError(t, err)
+		require.Empty(t, keys)
+	})
+
+	t.Run("invalid lines are reported without aborting the stream", func(t *testing.T) {
+		stream := strings.Join([]string{string(ecJWK), "not a jwk", "", `{"kty":"EC"}`, string(edJWK)}, "\n")
+
+		keys, err := ParseJWKLines(strings.NewReader(stream))
+		require.Len(t, keys, 2)
+		require.Equal(t, "ec", keys[0].KeyID)
+		require.Equal(t, "ed", keys[1].KeyID)
+
+		lineErrs := err.(interface{ Unwrap() []error }).Unwrap() //nolint:errorlint
+		require.Len(t, lineErrs, 2)
+
+		var lineErr *LineError
+
+		require.ErrorAs(t, lineErrs[0], &lineErr)
+		require.Equal(t, 2, lineErr.Line)
+		require.ErrorContains(t, lineErrs[0], "line 2: ")
+
+		require.ErrorAs(t, lineErrs[1], &lineErr)
+		require.Equal(t, 4, lineErr.Line)
+	})
+
+	t.Run("read error", func(t *testing.T) {
+		errRead := errors.New("read failed")
+
+		keys, err := ParseJWKLines(io.MultiReader(strings.NewReader(string(ecJWK)+"\n"), &failingReader{err: errRead}))
+		require.ErrorIs(t, err, errRead)
+		require.EqualError(t, err, "parseJWKLines: read line 2: read failed")
+		require.Len(t, keys, 1)
+	})
+}
+
+type failingReader struct {
+	err error
+}
+
+func (r *failingReader) Read([]byte) (int, error) {
+	return 0, r.err
+}
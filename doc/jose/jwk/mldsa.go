@@ -0,0 +1,138 @@
+/*
+Copyright Gen Digital Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package jwk
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/go-jose/go-jose/v3"
+
+	"github.com/dellekappa/kms-go/spi/kms"
+)
+
+// AKPKty is the JWK key type of the algorithm key pairs (draft-ietf-cose-dilithium), whose parameter set is given by
+// the alg member.
+const AKPKty = "AKP"
+
+// The JOSE algorithms of the ML-DSA (FIPS 204) parameter sets, they are the alg member of their AKP JWKs.
+const (
+	MLDSA44Alg = "ML-DSA-44"
+	MLDSA65Alg = "ML-DSA-65"
+	MLDSA87Alg = "ML-DSA-87"
+)
+
+// mldsaParameterSet is an ML-DSA (FIPS 204) parameter set.
+type mldsaParameterSet struct {
+	alg        string
+	keyType    kms.KeyType
+	pubKeySize int
+}
+
+// mldsaParameterSets are the ML-DSA parameter sets, with their FIPS 204 section 4 public key sizes.
+var mldsaParameterSets = []mldsaParameterSet{ //nolint:gochecknoglobals
+	{alg: MLDSA44Alg, keyType: kms.MLDSA44Type, pubKeySize: 1312},
+	{alg: MLDSA65Alg, keyType: kms.MLDSA65Type, pubKeySize: 1952},
+	{alg: MLDSA87Alg, keyType: kms.MLDSA87Type, pubKeySize: 2592},
+}
+
+// MLDSAParameterSet returns the JOSE algorithm name and the public key size in bytes of the ML-DSA key type kt, ok is
+// false if kt is not an ML-DSA key type.
+func MLDSAParameterSet(kt kms.KeyType) (alg string, pubKeySize int, ok bool) {
+	for _, ps := range mldsaParameterSets {
+		if ps.keyType == kt {
+			return ps.alg, ps.pubKeySize, true
+		}
+	}
+
+	return "", 0, false
+}
+
+func mldsaParameterSetByAlg(alg string) (mldsaParameterSet, bool) {
+	for _, ps := range mldsaParameterSets {
+		if ps.alg == alg {
+			return ps, true
+		}
+	}
+
+	return mldsaParameterSet{}, false
+}
+
+func isAKP(kty string) bool {
+	return strings.EqualFold(kty, AKPKty)
+}
+
+// isMLDSA reports whether j holds an ML-DSA public key: the raw key bytes with the AKP key type and an ML-DSA alg.
+func (j *JWK) isMLDSA() bool {
+	_, ok := j.Key.([]byte)
+	if !ok || !isAKP(j.Kty) {
+		return false
+	}
+
+	_, ok = mldsaParameterSetByAlg(j.Algorithm)
+
+	return ok
+}
+
+func unmarshalMLDSA(jwk *jsonWebKey) (*JWK, error) {
+	ps, ok := mldsaParameterSetByAlg(jwk.Alg)
+	if !ok {
+		return nil, &JWKError{
+			Field:  "alg",
+			Reason: fmt.Sprintf("unsupported AKP algorithm '%s'", jwk.Alg),
+			Err:    ErrInvalidKey,
+		}
+	}
+
+	if jwk.Pub == nil {
+		return nil, missingFieldError("pub")
+	}
+
+	if jwk.Priv != nil {
+		return nil, &JWKError{Field: "priv", Reason: "private keys are not supported", Err: ErrInvalidKey}
+	}
+
+	if err := checkFieldSize("pub", jwk.Pub, ps.pubKeySize); err != nil {
+		return nil, err
+	}
+
+	return &JWK{
+		JSONWebKey: jose.JSONWebKey{
+			Key: jwk.Pub.data, KeyID: jwk.Kid, Algorithm: jwk.Alg, Use: jwk.Use,
+		},
+		Kty: jwk.Kty,
+	}, nil
+}
+
+func marshalMLDSA(jwk *JWK) ([]byte, error) {
+	key, ok := jwk.Key.([]byte)
+	if !ok {
+		return nil, errors.New("marshalMLDSA: invalid key")
+	}
+
+	ps, ok := mldsaParameterSetByAlg(jwk.Algorithm)
+	if !ok || len(key) != ps.pubKeySize {
+		return nil, errors.New("marshalMLDSA: invalid key")
+	}
+
+	raw := jsonWebKey{
+		Kty: AKPKty,
+		Alg: ps.alg,
+		Pub: &byteBuffer{data: key},
+	}
+
+	raw.Kid = jwk.KeyID
+	raw.Use = jwk.Use
+
+	if len(jwk.X509CertThumbprintS256) > 0 {
+		raw.X5tS256 = &byteBuffer{data: jwk.X509CertThumbprintS256}
+	}
+
+	return json.Marshal(raw)
+}
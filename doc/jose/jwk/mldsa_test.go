@@ -0,0 +1,112 @@
+/*
+Copyright Gen Digital Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package jwk
+
+import (
+	"bytes"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/go-jose/go-jose/v3"
+	"github.com/stretchr/testify/require"
+
+	"github.com/dellekappa/kms-go/spi/kms"
+)
+
+func TestJWK_MLDSA(t *testing.T) {
+	for _, kt := range []kms.KeyType{kms.MLDSA44Type, kms.MLDSA65Type, kms.MLDSA87Type} {
+		t.Run(string(kt), func(t *testing.T) {
+			alg, size, ok := MLDSAParameterSet(kt)
+			require.True(t, ok)
+
+			pub := bytes.Repeat([]byte{0x2a}, size)
+
+			key := &JWK{
+				JSONWebKey: jose.JSONWebKey{Key: pub, KeyID: "kid", Algorithm: alg},
+				Kty:        "AKP",
+			}
+
+			jwkBytes, err := key.MarshalJSON()
+			require.NoError(t, err)
+
+			var members map[string]interface{}
+
+			require.NoError(t, json.Unmarshal(jwkBytes, &members))
+			require.Equal(t, map[string]interface{}{
+				"kty": "AKP",
+				"alg": alg,
+				"kid": "kid",
+				"pub": base64.RawURLEncoding.EncodeToString(pub),
+			}, members)
+
+			parsed := &JWK{}
+			require.NoError(t, parsed.UnmarshalJSON(jwkBytes))
+			require.Equal(t, pub, parsed.Key)
+			require.Equal(t, alg, parsed.Algorithm)
+			require.Nil(t, parsed.Extra)
+
+			keyType, err := parsed.KeyType()
+			require.NoError(t, err)
+			require.Equal(t, kt, keyType)
+
+			pubBytes, err := parsed.PublicKeyBytes()
+			require.NoError(t, err)
+			require.Equal(t, pub, pubBytes)
+
+			require.True(t, parsed.SamePublicKey(key))
+		})
+	}
+
+	t.Run("not an ML-DSA key type", func(t *testing.T) {
+		_, _, ok := MLDSAParameterSet(kms.ED25519Type)
+		require.False(t, ok)
+	})
+
+	t.Run("unmarshal errors", func(t *testing.T) {
+		pub := base64.RawURLEncoding.EncodeToString(make([]byte, 1312))
+
+		tests := []struct {
+			name  string
+			jwk   string
+			field string
+		}{
+			{name: "unknown alg", jwk: fmt.Sprintf(`{"kty":"AKP","alg":"SLH-DSA","pub":"%s"}`, pub), field: "alg"},
+			{name: "missing pub", jwk: `{"kty":"AKP","alg":"ML-DSA-44"}`, field: "pub"},
+			{name: "wrong pub size", jwk: fmt.Sprintf(`{"kty":"AKP","alg":"ML-DSA-65","pub":"%s"}`, pub), field: "pub"},
+			{
+				name:  "private key",
+				jwk:   fmt.Sprintf(`{"kty":"AKP","alg":"ML-DSA-44","pub":"%s","priv":"AAAA"}`, pub),
+				field: "priv",
+			},
+		}
+
+		for _, tc := range tests {
+			t.Run(tc.name, func(t *testing.T) {
+				err := (&JWK{}).UnmarshalJSON([]byte(tc.jwk))
+				require.ErrorIs(t, err, ErrInvalidKey)
+
+				var jwkErr *JWKError
+
+				require.True(t, errors.As(err, &jwkErr))
+				require.Equal(t, tc.field, jwkErr.Field)
+			})
+		}
+	})
+
+	t.Run("marshal invalid key size", func(t *testing.T) {
+		key := &JWK{
+			JSONWebKey: jose.JSONWebKey{Key: make([]byte, 10), Algorithm: "ML-DSA-44"},
+			Kty:        "AKP",
+		}
+
+		_, err := marshalMLDSA(key)
+		require.EqualError(t, err, "marshalMLDSA: invalid key")
+	})
+}
@@ -0,0 +1,59 @@
+/*
+Copyright Gen Digital Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package jwk
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+)
+
+// ErrPrivateKeyMaterial is returned by MarshalPublicOnly when the JWK holds private or secret key material.
+var ErrPrivateKeyMaterial = errors.New("jwk contains private key material")
+
+// privateMembers are the JWK members holding private or secret key material (RFC 7518 section 6, RFC 8037 and the
+// ML-DSA priv member).
+var privateMembers = []string{"d", "p", "q", "dp", "dq", "qi", "oth", "k", "priv"} //nolint:gochecknoglobals
+
+// PrivateMembers returns the names of the JWK members holding private or secret key material: the RSA, EC and OKP
+// private members, the oct key k and the ML-DSA priv member.
+func PrivateMembers() []string {
+	return append([]string(nil), privateMembers...)
+}
+
+// MarshalPublicOnly marshals j as MarshalJSON does, for JWKs published to other parties (registries, DID documents,
+// JWKS endpoints). Rather than silently serializing a private key, it fails with an error wrapping
+// ErrPrivateKeyMaterial if the marshalled JWK has a private or secret member: callers must explicitly call Public()
+// first. Symmetric oct keys have no public form and are always rejected.
+func MarshalPublicOnly(j *JWK) ([]byte, error) {
+	if j == nil {
+		return nil, errors.New("marshalPublicOnly: jwk is nil")
+	}
+
+	jwkBytes, err := j.MarshalJSON()
+	if err != nil {
+		return nil, fmt.Errorf("marshalPublicOnly: %w", err)
+	}
+
+	// the marshalled members are checked rather than the key type, to also cover key types registered by other
+	// packages.
+	var members map[string]json.RawMessage
+
+	err = json.Unmarshal(jwkBytes, &members)
+	if err != nil {
+		return nil, fmt.Errorf("marshalPublicOnly: %w", err)
+	}
+
+	for _, name := range privateMembers {
+		if _, ok := members[name]; ok {
+			return nil, fmt.Errorf("marshalPublicOnly: %w: '%s' member of kid '%s', call Public() first",
+				ErrPrivateKeyMaterial, name, j.KeyID)
+		}
+	}
+
+	return jwkBytes, nil
+}
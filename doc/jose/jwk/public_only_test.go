@@ -0,0 +1,89 @@
+/*
+Copyright Gen Digital Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package jwk
+
+import (
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
+	"testing"
+
+	"github.com/go-jose/go-jose/v3"
+	"github.com/stretchr/testify/require"
+	"github.com/trustbloc/bbs-signature-go/bbs12381g2pub"
+)
+
+func TestMarshalPublicOnly(t *testing.T) {
+	ecKey, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+
+	rsaKey, err := rsa.GenerateKey(rand.Reader, 2048)
+	require.NoError(t, err)
+
+	_, edPriv, err := ed25519.GenerateKey(rand.Reader)
+	require.NoError(t, err)
+
+	_, bbsPriv, err := bbs12381g2pub.GenerateKeyPair(sha256.New, nil)
+	require.NoError(t, err)
+
+	privateJWKs := map[string]*JWK{
+		"ec": {
+			JSONWebKey: jose.JSONWebKey{Key: ecKey, KeyID: "ec"}, Kty: ecKty, Crv: "P-256",
+		},
+		"rsa": {JSONWebKey: jose.JSONWebKey{Key: rsaKey}, Kty: "RSA"},
+		"oct": {JSONWebKey: jose.JSONWebKey{Key: []byte("0123456789abcdef")}, Kty: "oct"},
+	}
+
+	for member, j := range map[string]*JWK{"d": privateJWKs["ec"], "k": privateJWKs["oct"]} {
+		_, err = MarshalPublicOnly(j)
+		require.ErrorIs(t, err, ErrPrivateKeyMaterial)
+		require.ErrorContains(t, err, "'"+member+"' member")
+	}
+
+	_, err = MarshalPublicOnly(privateJWKs["ec"])
+	require.EqualError(t, err,
+		"marshalPublicOnly: jwk contains private key material: 'd' member of kid 'ec', call Public() first")
+
+	for _, j := range []*JWK{
+		privateJWKs["ec"], privateJWKs["rsa"],
+		{JSONWebKey: jose.JSONWebKey{Key: edPriv}, Kty: okpKty, Crv: ed25519Crv},
+		{JSONWebKey: jose.JSONWebKey{Key: bbsPriv}, Kty: ecKty, Crv: bls12381G2Crv},
+	} {
+		_, err = MarshalPublicOnly(j)
+		require.ErrorIs(t, err, ErrPrivateKeyMaterial)
+
+		pubBytes, err := MarshalPublicOnly(j.Public())
+		require.NoError(t, err)
+
+		expected, err := j.Public().MarshalJSON()
+		require.NoError(t, err)
+		require.Equal(t, expected, pubBytes)
+	}
+
+	x25519JWK := &JWK{JSONWebKey: jose.JSONWebKey{Key: make([]byte, 32)}, Kty: okpKty, Crv: x25519Crv}
+
+	_, err = MarshalPublicOnly(x25519JWK)
+	require.NoError(t, err)
+
+	_, err = MarshalPublicOnly(nil)
+	require.EqualError(t, err, "marshalPublicOnly: jwk is nil")
+
+	_, err = MarshalPublicOnly(&JWK{JSONWebKey: jose.JSONWebKey{Key: "invalid"}})
+	require.ErrorContains(t, err, "marshalPublicOnly: ")
+}
+
+func TestPrivateMembers(t *testing.T) {
+	members := PrivateMembers()
+	require.Contains(t, members, "d")
+	require.Contains(t, members, "priv")
+
+	members[0] = "x"
+	require.Equal(t, "d", PrivateMembers()[0])
+}
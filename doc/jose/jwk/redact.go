@@ -0,0 +1,92 @@
+/*
+Copyright Gen Digital Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package jwk
+
+import (
+	"strconv"
+	"strings"
+)
+
+const redactedValue = "[private]"
+
+// String returns a redacted representation of j safe to log, e.g. "JWK{kty=EC crv=P-256 alg=ES256 use=sig kid=key-1
+// d=[private]}": the kty, crv, alg, use and kid members, and a [private] placeholder for each member holding private
+// or secret key material. Key values, public ones included, are never printed, see DescribeJWK for their lengths.
+// String makes fmt verbs like %v and %+v safe for *JWK values.
+func (j *JWK) String() string {
+	if j == nil {
+		return "JWK<nil>"
+	}
+
+	fields := redactedFields(j)
+	values := make([]string, len(fields))
+
+	for i, f := range fields {
+		values[i] = f.name + "=" + f.value
+	}
+
+	return "JWK{" + strings.Join(values, " ") + "}"
+}
+
+// GoString returns the redacted representation of j printed by %#v, e.g. `&jwk.JWK{kty:"EC", crv:"P-256",
+// alg:"ES256", use:"sig", kid:"key-1", d:[private]}`, see String.
+func (j *JWK) GoString() string {
+	if j == nil {
+		return "(*jwk.JWK)(nil)"
+	}
+
+	fields := redactedFields(j)
+	values := make([]string, len(fields))
+
+	for i, f := range fields {
+		value := f.value
+		if value != redactedValue {
+			value = strconv.Quote(value)
+		}
+
+		values[i] = f.name + ":" + value
+	}
+
+	return "&jwk.JWK{" + strings.Join(values, ", ") + "}"
+}
+
+type redactedField struct {
+	name  string
+	value string
+}
+
+// redactedFields returns the describing members of j (kty, crv, alg, use and kid) followed by a redactedValue field
+// for each of its private members.
+func redactedFields(j *JWK) []redactedField {
+	members, err := jwkMembers(j)
+	if err != nil {
+		// j can't be marshalled, describe it from its fields.
+		fields := []redactedField{
+			{"kty", j.Kty}, {"crv", j.Crv}, {"alg", j.Algorithm}, {"use", j.Use}, {"kid", j.KeyID},
+		}
+
+		if j.Key != nil && !j.IsPublic() {
+			fields = append(fields, redactedField{"key", redactedValue})
+		}
+
+		return fields
+	}
+
+	fields := make([]redactedField, 0, len(headerMembers)+1)
+
+	for _, name := range headerMembers {
+		fields = append(fields, redactedField{name, stringMember(members, name)})
+	}
+
+	for _, name := range privateMembers {
+		if _, ok := members[name]; ok {
+			fields = append(fields, redactedField{name, redactedValue})
+		}
+	}
+
+	return fields
+}
@@ -0,0 +1,78 @@
+/*
+Copyright Gen Digital Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package jwk
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/rsa"
+	"encoding/base64"
+	"fmt"
+	"testing"
+
+	"github.com/go-jose/go-jose/v3"
+	"github.com/stretchr/testify/require"
+)
+
+func TestJWK_String(t *testing.T) {
+	ecKey, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+
+	rsaKey, err := rsa.GenerateKey(rand.Reader, 2048)
+	require.NoError(t, err)
+
+	secret := []byte("0123456789abcdef")
+
+	ecPriv := &JWK{
+		JSONWebKey: jose.JSONWebKey{Key: ecKey, KeyID: "key-1", Algorithm: "ES256", Use: "sig"},
+		Kty:        "EC",
+		Crv:        "P-256",
+	}
+
+	t.Run("private key", func(t *testing.T) {
+		require.Equal(t, "JWK{kty=EC crv=P-256 alg=ES256 use=sig kid=key-1 d=[private]}", ecPriv.String())
+		require.Equal(t, `&jwk.JWK{kty:"EC", crv:"P-256", alg:"ES256", use:"sig", kid:"key-1", d:[private]}`,
+			ecPriv.GoString())
+
+		d := base64.RawURLEncoding.EncodeToString(ecKey.D.Bytes())
+
+		for _, verb := range []string{"%v", "%+v", "%#v", "%s"} {
+			formatted := fmt.Sprintf(verb, ecPriv)
+			require.NotContains(t, formatted, d, verb)
+			require.NotContains(t, formatted, ecKey.D.String(), verb)
+			require.Contains(t, formatted, "[private]", verb)
+		}
+
+		// JWKs nested in other values are redacted as well.
+		require.Contains(t, fmt.Sprintf("%+v", struct{ Key *JWK }{ecPriv}), "d=[private]")
+		require.Contains(t, fmt.Sprintf("%v", []*JWK{ecPriv}), "d=[private]")
+
+		rsaPriv := &JWK{JSONWebKey: jose.JSONWebKey{Key: rsaKey}, Kty: "RSA"}
+		require.Equal(t, "JWK{kty=RSA crv= alg= use= kid= d=[private] p=[private] q=[private] dp=[private] "+
+			"dq=[private] qi=[private]}", rsaPriv.String())
+		require.NotContains(t, fmt.Sprintf("%+v", rsaPriv), rsaKey.D.String())
+
+		octKey := &JWK{JSONWebKey: jose.JSONWebKey{Key: secret, KeyID: "hmac", Algorithm: "HS256"}, Kty: "oct"}
+		require.Equal(t, "JWK{kty=oct crv= alg=HS256 use= kid=hmac k=[private]}", octKey.String())
+		require.NotContains(t, fmt.Sprintf("%#v", octKey), base64.RawURLEncoding.EncodeToString(secret))
+	})
+
+	t.Run("public key", func(t *testing.T) {
+		require.Equal(t, "JWK{kty=EC crv=P-256 alg=ES256 use=sig kid=key-1}", ecPriv.Public().String())
+	})
+
+	t.Run("nil and invalid keys", func(t *testing.T) {
+		var nilKey *JWK
+
+		require.Equal(t, "JWK<nil>", nilKey.String())
+		require.Equal(t, "(*jwk.JWK)(nil)", nilKey.GoString())
+
+		invalid := &JWK{JSONWebKey: jose.JSONWebKey{Key: "invalid", KeyID: "key-2"}, Kty: "EC"}
+		require.Equal(t, "JWK{kty=EC crv= alg= use= kid=key-2 key=[private]}", invalid.String())
+	})
+}
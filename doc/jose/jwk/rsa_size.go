@@ -0,0 +1,60 @@
+/*
+Copyright Gen Digital Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package jwk
+
+import (
+	"crypto/rsa"
+	"errors"
+	"fmt"
+)
+
+// DefaultMinRSAKeyBits is the default minimum RSA modulus size in bits, see WithMinRSAKeyBits.
+const DefaultMinRSAKeyBits = 2048
+
+// ErrWeakKey is returned for RSA keys with a modulus smaller than the required minimum size.
+var ErrWeakKey = errors.New("weak key")
+
+// ValidateOpt is an option of JWK.Validate.
+type ValidateOpt func(opts *validateOpts)
+
+type validateOpts struct {
+	minRSAKeyBits int
+}
+
+// WithMinRSAKeyBits option raises the minimum RSA modulus size in bits from DefaultMinRSAKeyBits to bits, e.g. 3072 to
+// forbid 2048 bits keys in a deployment. It can't lower the minimum size of key types requiring larger keys (e.g.
+// RSA4096Type).
+func WithMinRSAKeyBits(bits int) ValidateOpt {
+	return func(opts *validateOpts) {
+		opts.minRSAKeyBits = bits
+	}
+}
+
+// MinRSAKeyBits returns the minimum RSA modulus size in bits set by opts, DefaultMinRSAKeyBits if none is set.
+func MinRSAKeyBits(opts ...ValidateOpt) int {
+	vOpts := &validateOpts{minRSAKeyBits: DefaultMinRSAKeyBits}
+
+	for _, opt := range opts {
+		opt(vOpts)
+	}
+
+	return vOpts.minRSAKeyBits
+}
+
+// CheckRSAKeySize checks the modulus of pub has at least minBits bits, returning a JWKError wrapping ErrWeakKey
+// otherwise.
+func CheckRSAKeySize(pub *rsa.PublicKey, minBits int) error {
+	if pub.N != nil && pub.N.BitLen() >= minBits {
+		return nil
+	}
+
+	return &JWKError{
+		Field:  "n",
+		Reason: fmt.Sprintf("must be at least %d bits, got %d", minBits, bitLen(pub.N)),
+		Err:    fmt.Errorf("%w: RSA key size %d bits is below the %d bits minimum", ErrWeakKey, bitLen(pub.N), minBits),
+	}
+}
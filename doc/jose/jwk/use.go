@@ -0,0 +1,81 @@
+/*
+Copyright Gen Digital Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package jwk
+
+import (
+	"strings"
+)
+
+const (
+	useSig = "sig"
+	useEnc = "enc"
+)
+
+// signatureAlgs are the JWS algorithms (RFC 7518 section 3, RFC 8037, RFC 8812), ML-DSA algorithms are matched with
+// mldsaParameterSetByAlg.
+var signatureAlgs = map[string]struct{}{ //nolint:gochecknoglobals
+	"HS256": {}, "HS384": {}, "HS512": {},
+	"RS256": {}, "RS384": {}, "RS512": {},
+	"PS256": {}, "PS384": {}, "PS512": {},
+	"ES256": {}, "ES384": {}, "ES512": {}, "ES256K": {},
+	"EdDSA": {},
+}
+
+// encryptionAlgs are the JWE key management algorithms (RFC 7518 section 4, ECDH-1PU) and, for keys used directly as
+// content encryption keys, the content encryption algorithms (RFC 7518 section 5).
+var encryptionAlgs = map[string]struct{}{ //nolint:gochecknoglobals
+	"RSA1_5": {}, "RSA-OAEP": {}, "RSA-OAEP-256": {}, "RSA-OAEP-384": {}, "RSA-OAEP-512": {},
+	"A128KW": {}, "A192KW": {}, "A256KW": {},
+	"A128GCMKW": {}, "A192GCMKW": {}, "A256GCMKW": {}, "dir": {},
+	"A128GCM": {}, "A192GCM": {}, "A256GCM": {}, "XC20P": {},
+	"A128CBC-HS256": {}, "A192CBC-HS384": {}, "A256CBC-HS512": {},
+}
+
+// encryptionAlgPrefixes are the prefixes of the families of JWE key management algorithms: ECDH-ES, ECDH-ES+A128KW,
+// ECDH-1PU+A256KW, PBES2-HS256+A128KW...
+var encryptionAlgPrefixes = []string{"ECDH-ES", "ECDH-1PU", "PBES2-"} //nolint:gochecknoglobals
+
+// InferUse sets the "use" member of j from its "alg" when use is empty: "sig" for signature algorithms, "enc" for key
+// management and content encryption algorithms. An explicitly set use is never overridden, and use is left empty for
+// an empty or unknown alg. InferUse returns true if it set use.
+func (j *JWK) InferUse() bool {
+	if j.Use != "" {
+		return false
+	}
+
+	use := algUse(j.Algorithm)
+	if use == "" {
+		return false
+	}
+
+	j.Use = use
+
+	return true
+}
+
+// algUse returns the public key use of the keys of alg, "" if alg is unknown.
+func algUse(alg string) string {
+	if _, ok := signatureAlgs[alg]; ok {
+		return useSig
+	}
+
+	if _, ok := mldsaParameterSetByAlg(alg); ok {
+		return useSig
+	}
+
+	if _, ok := encryptionAlgs[alg]; ok {
+		return useEnc
+	}
+
+	for _, prefix := range encryptionAlgPrefixes {
+		if strings.HasPrefix(alg, prefix) {
+			return useEnc
+		}
+	}
+
+	return ""
+}
@@ -0,0 +1,72 @@
+/*
+Copyright Gen Digital Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package jwk
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/go-jose/go-jose/v3"
+	"github.com/stretchr/testify/require"
+)
+
+func TestJWK_InferUse(t *testing.T) {
+	t.Run("inferred from alg", func(t *testing.T) {
+		for alg, use := range map[string]string{
+			"ES256":              "sig",
+			"ES256K":             "sig",
+			"EdDSA":              "sig",
+			"PS512":              "sig",
+			"HS256":              "sig",
+			"ML-DSA-65":          "sig",
+			"RSA-OAEP-256":       "enc",
+			"A256KW":             "enc",
+			"dir":                "enc",
+			"ECDH-ES":            "enc",
+			"ECDH-ES+A256KW":     "enc",
+			"ECDH-1PU+A128KW":    "enc",
+			"PBES2-HS512+A256KW": "enc",
+			"A256GCM":            "enc",
+			"XC20P":              "enc",
+		} {
+			key := &JWK{JSONWebKey: jose.JSONWebKey{Algorithm: alg}}
+
+			require.True(t, key.InferUse(), alg)
+			require.Equal(t, use, key.Use, alg)
+
+			require.False(t, key.InferUse(), alg)
+			require.Equal(t, use, key.Use, alg)
+		}
+	})
+
+	t.Run("use not changed", func(t *testing.T) {
+		for _, key := range []*JWK{
+			{JSONWebKey: jose.JSONWebKey{Algorithm: "ES256", Use: "enc"}},
+			{JSONWebKey: jose.JSONWebKey{Algorithm: "ECDH-ES", Use: "sig"}},
+			{JSONWebKey: jose.JSONWebKey{Algorithm: "unknown"}},
+			{JSONWebKey: jose.JSONWebKey{Algorithm: "es256"}},
+			{},
+		} {
+			use := key.Use
+
+			require.False(t, key.InferUse(), key.Algorithm)
+			require.Equal(t, use, key.Use, key.Algorithm)
+		}
+	})
+
+	t.Run("unmarshalled JWK", func(t *testing.T) {
+		key := &JWK{}
+
+		require.NoError(t, json.Unmarshal([]byte(`{"kty":"OKP","crv":"Ed25519","alg":"EdDSA",`+
+			`"x":"11qYAYKxCrfVS_7TyWQHOg7hcvPapiMlrwIaaPcHURo"}`), key))
+		require.True(t, key.InferUse())
+
+		keyBytes, err := json.Marshal(key)
+		require.NoError(t, err)
+		require.Contains(t, string(keyBytes), `"use":"sig"`)
+	})
+}
@@ -0,0 +1,58 @@
+/*
+Copyright Gen Digital Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package jwk
+
+import (
+	"fmt"
+	"strings"
+)
+
+const octKty = "oct"
+
+// wrapKeySizes are the AES key sizes in bytes of the AES Key Wrap algorithms (RFC 7518 section 4.4).
+var wrapKeySizes = map[string]int{ //nolint:gochecknoglobals
+	"A128KW": 16, //nolint:gomnd
+	"A192KW": 24, //nolint:gomnd
+	"A256KW": 32, //nolint:gomnd
+}
+
+// WrapKeyBytes returns the key of a symmetric key wrapping JWK: an oct JWK whose alg is A128KW, A192KW or A256KW and
+// whose k member has the size of its alg (16, 24 or 32 bytes). It lets misconfigured wrapping keys be caught when they
+// are loaded rather than when wrapping with them. The returned JWKError wraps ErrInvalidKey.
+func (j *JWK) WrapKeyBytes() ([]byte, error) {
+	if !strings.EqualFold(j.Kty, octKty) {
+		return nil, &JWKError{
+			Field:  "kty",
+			Reason: fmt.Sprintf("wrapping key must be an oct key, got '%s'", j.Kty),
+			Err:    ErrInvalidKey,
+		}
+	}
+
+	size, ok := wrapKeySizes[j.Algorithm]
+	if !ok {
+		return nil, &JWKError{
+			Field:  "alg",
+			Reason: fmt.Sprintf("unsupported key wrapping algorithm '%s'", j.Algorithm),
+			Err:    ErrInvalidKey,
+		}
+	}
+
+	key, ok := j.Key.([]byte)
+	if !ok || len(key) == 0 {
+		return nil, missingFieldError("k")
+	}
+
+	if len(key) != size {
+		return nil, &JWKError{
+			Field:  "k",
+			Reason: fmt.Sprintf("must be %d bytes for %s, got %d", size, j.Algorithm, len(key)),
+			Err:    ErrInvalidKey,
+		}
+	}
+
+	return key, nil
+}
@@ -0,0 +1,71 @@
+/*
+Copyright Gen Digital Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package jwk
+
+import (
+	"encoding/base64"
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestJWK_WrapKeyBytes(t *testing.T) {
+	octJWK := func(t *testing.T, alg string, size int) *JWK {
+		t.Helper()
+
+		k := base64.RawURLEncoding.EncodeToString(make([]byte, size))
+
+		j := &JWK{}
+		require.NoError(t, j.UnmarshalJSON([]byte(fmt.Sprintf(`{"kty":"oct","alg":"%s","k":"%s"}`, alg, k))))
+
+		return j
+	}
+
+	requireJWKError := func(t *testing.T, err error, field, reason string) {
+		t.Helper()
+
+		require.ErrorIs(t, err, ErrInvalidKey)
+
+		var jwkErr *JWKError
+
+		require.ErrorAs(t, err, &jwkErr)
+		require.Equal(t, field, jwkErr.Field)
+		require.Equal(t, reason, jwkErr.Reason)
+	}
+
+	t.Run("success", func(t *testing.T) {
+		for alg, size := range map[string]int{"A128KW": 16, "A192KW": 24, "A256KW": 32} {
+			key, err := octJWK(t, alg, size).WrapKeyBytes()
+			require.NoError(t, err, alg)
+			require.Len(t, key, size, alg)
+		}
+	})
+
+	t.Run("key size not matching alg", func(t *testing.T) {
+		_, err := octJWK(t, "A256KW", 16).WrapKeyBytes()
+		requireJWKError(t, err, "k", "must be 32 bytes for A256KW, got 16")
+
+		_, err = octJWK(t, "A128KW", 32).WrapKeyBytes()
+		requireJWKError(t, err, "k", "must be 16 bytes for A128KW, got 32")
+	})
+
+	t.Run("not a wrapping key", func(t *testing.T) {
+		_, err := octJWK(t, "HS256", 32).WrapKeyBytes()
+		requireJWKError(t, err, "alg", "unsupported key wrapping algorithm 'HS256'")
+
+		j := &JWK{}
+		require.NoError(t, j.UnmarshalJSON([]byte(
+			`{"kty":"OKP","crv":"X25519","alg":"A256KW","x":"hSDwCYkwp1R0i33ctD73Wg2_Og0mOBr066SpjqqbTmo"}`)))
+
+		_, err = j.WrapKeyBytes()
+		requireJWKError(t, err, "kty", "wrapping key must be an oct key, got 'OKP'")
+
+		_, err = (&JWK{Kty: "oct"}).WrapKeyBytes()
+		requireJWKError(t, err, "alg", "unsupported key wrapping algorithm ''")
+	})
+}
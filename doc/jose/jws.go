@@ -10,16 +10,26 @@ import (
 	"encoding/base64"
 	"errors"
 	"fmt"
+	"slices"
 	"strings"
 
 	"github.com/go-jose/go-jose/v3/json"
 )
 
+// ErrUnsecuredJWS is returned when parsing an unsecured JWS, with the "none" alg, without the AllowUnsecured option.
+var ErrUnsecuredJWS = errors.New("unsecured JWS with 'none' alg is not allowed")
+
+// ErrDisallowedAlgorithm is returned when parsing a JWS whose "alg" header is not one of the algorithms set with the
+// AllowedAlgorithms option.
+var ErrDisallowedAlgorithm = errors.New("JWS alg is not allowed")
+
 const (
 	jwsPartsCount    = 3
 	jwsHeaderPart    = 0
 	jwsPayloadPart   = 1
 	jwsSignaturePart = 2
+
+	unsecuredAlg = "none"
 )
 
 // JSONWebSignature defines JSON Web Signature (https://tools.ietf.org/html/rfc7515)
@@ -28,8 +38,9 @@ type JSONWebSignature struct {
 	UnprotectedHeaders Headers
 	Payload            []byte
 
-	signature   []byte
-	joseHeaders Headers
+	signature           []byte
+	joseHeaders         Headers
+	b64ProtectedHeaders string
 }
 
 // SignatureVerifier makes verification of JSON Web Signature.
@@ -110,9 +121,19 @@ type Signer interface {
 	Headers() Headers
 }
 
-// NewJWS creates JSON Web Signature.
-func NewJWS(protectedHeaders, unprotectedHeaders Headers, payload []byte, signer Signer) (*JSONWebSignature, error) {
+// NewJWS creates JSON Web Signature. The WithType and WithContentType options set the "typ" and "cty" protected
+// headers, which must be strings when set in protectedHeaders. The WithEmbeddedJWK option sets the "jwk" protected
+// header to the public key of signer.
+func NewJWS(protectedHeaders, unprotectedHeaders Headers, payload []byte, signer Signer,
+	opts ...HeaderOpt) (*JSONWebSignature, error) {
 	headers := mergeHeaders(protectedHeaders, signer.Headers())
+	applyHeaderOpts(headers, opts)
+
+	err := embedSignerJWK(headers, unprotectedHeaders, signer, opts)
+	if err != nil {
+		return nil, fmt.Errorf("sign JWS: %w", err)
+	}
+
 	jws := &JSONWebSignature{
 		ProtectedHeaders:   headers,
 		UnprotectedHeaders: unprotectedHeaders,
@@ -120,24 +141,29 @@ func NewJWS(protectedHeaders, unprotectedHeaders Headers, payload []byte, signer
 		joseHeaders:        headers,
 	}
 
-	signature, err := sign(jws.joseHeaders, payload, signer)
+	b64Headers, signature, err := sign(jws.joseHeaders, payload, signer)
 	if err != nil {
 		return nil, fmt.Errorf("sign JWS: %w", err)
 	}
 
 	jws.signature = signature
+	jws.b64ProtectedHeaders = b64Headers
 
 	return jws, nil
 }
 
 // SerializeCompact makes JWS Compact Serialization (https://tools.ietf.org/html/rfc7515#section-7.1)
 func (s JSONWebSignature) SerializeCompact(detached bool) (string, error) {
-	byteHeaders, err := json.Marshal(s.joseHeaders)
-	if err != nil {
-		return "", fmt.Errorf("marshal JWS JOSE Headers: %w", err)
-	}
+	b64Headers := s.b64ProtectedHeaders
 
-	b64Headers := base64.RawURLEncoding.EncodeToString(byteHeaders)
+	if b64Headers == "" {
+		byteHeaders, err := json.Marshal(s.joseHeaders)
+		if err != nil {
+			return "", fmt.Errorf("marshal JWS JOSE Headers: %w", err)
+		}
+
+		b64Headers = base64.RawURLEncoding.EncodeToString(byteHeaders)
+	}
 
 	b64Payload := ""
 	if !detached {
@@ -178,28 +204,55 @@ func mergeHeaders(h1, h2 Headers) Headers {
 	return h
 }
 
-func sign(joseHeaders Headers, payload []byte, signer Signer) ([]byte, error) {
+// sign signs payload with joseHeaders as protected headers, returning the base64url encoded protected headers and the
+// signature.
+func sign(joseHeaders Headers, payload []byte, signer Signer) (string, []byte, error) {
 	err := checkJWSHeaders(joseHeaders)
 	if err != nil {
-		return nil, fmt.Errorf("check JOSE headers: %w", err)
+		return "", nil, fmt.Errorf("check JOSE headers: %w", err)
 	}
 
-	sigInput, err := signingInput(joseHeaders, "", payload)
+	b64Headers, sigInput, err := JWSSigningInput(joseHeaders, payload)
 	if err != nil {
-		return nil, fmt.Errorf("prepare JWS verification data: %w", err)
+		return "", nil, fmt.Errorf("prepare JWS verification data: %w", err)
 	}
 
 	signature, err := signer.Sign(sigInput)
 	if err != nil {
-		return nil, fmt.Errorf("sign JWS verification data: %w", err)
+		return "", nil, fmt.Errorf("sign JWS verification data: %w", err)
 	}
 
-	return signature, nil
+	return b64Headers, signature, nil
+}
+
+// JWSSigningInput returns the base64url encoded protected header of a JWS signing payload with the protected headers,
+// as set in its compact serialization, and the JWS Signing Input (RFC 7515 section 5.1) to sign:
+// ASCII(BASE64URL(UTF8(protected)) || '.' || BASE64URL(payload)). Both are encoded without padding. The payload is
+// not encoded when protected has the "b64" header set to false (RFC 7797). It lets callers sign the exact input
+// themselves, e.g. with an external HSM, and assemble the compact JWS as header.payload.signature.
+func JWSSigningInput(protected map[string]interface{}, payload []byte) (string, []byte, error) {
+	headersBytes, err := json.Marshal(protected)
+	if err != nil {
+		return "", nil, fmt.Errorf("serialize JWS headers: %w", err)
+	}
+
+	b64Headers := base64.RawURLEncoding.EncodeToString(headersBytes)
+
+	sigInput, err := signingInput(protected, b64Headers, payload)
+	if err != nil {
+		return "", nil, err
+	}
+
+	return b64Headers, sigInput, nil
 }
 
 // jwsParseOpts holds options for the JWS Parsing.
 type jwsParseOpts struct {
 	detachedPayload []byte
+	requiredSigners []string
+	allowUnsecured  bool
+	understoodCrit  []string
+	allowedAlgs     []string
 }
 
 // JWSParseOpt is the JWS Parser option.
@@ -212,6 +265,25 @@ func WithJWSDetachedPayload(payload []byte) JWSParseOpt {
 	}
 }
 
+// AllowUnsecured option sets whether unsecured JWS (https://tools.ietf.org/html/rfc7518#section-3.6), with the
+// "none" alg and an empty signature, are accepted. They are rejected with ErrUnsecuredJWS by default. An accepted
+// unsecured JWS is not passed to the verifier as it carries no signature to verify.
+func AllowUnsecured(allow bool) JWSParseOpt {
+	return func(opts *jwsParseOpts) {
+		opts.allowUnsecured = allow
+	}
+}
+
+// AllowedAlgorithms option restricts the "alg" headers of the parsed JWS signatures to algs, e.g. []string{"ES256",
+// "EdDSA"}, against algorithm downgrades. A signature with another alg is rejected with ErrDisallowedAlgorithm before
+// the verifier is called, so before its key is resolved. Unsecured JWS are only accepted if algs also lists "none"
+// and AllowUnsecured is set. By default, all the algorithms supported by the verifier are accepted.
+func AllowedAlgorithms(algs []string) JWSParseOpt {
+	return func(opts *jwsParseOpts) {
+		opts.allowedAlgs = algs
+	}
+}
+
 // ParseJWS parses serialized JWS. Currently only JWS Compact Serialization parsing is supported.
 func ParseJWS(jws string, verifier SignatureVerifier, opts ...JWSParseOpt) (*JSONWebSignature, error) {
 	pOpts := &jwsParseOpts{}
@@ -262,19 +334,49 @@ func parseCompacted(jwsCompact string, verifier SignatureVerifier, opts *jwsPars
 		return nil, fmt.Errorf("decode base64 signature: %w", err)
 	}
 
-	err = verifier.Verify(joseHeaders, payload, sInput, signature)
+	err = checkCriticalHeaders(joseHeaders, nil, opts)
+	if err != nil {
+		return nil, err
+	}
+
+	err = verifySignature(verifier, joseHeaders, payload, sInput, signature, opts)
 	if err != nil {
 		return nil, err
 	}
 
 	return &JSONWebSignature{
-		ProtectedHeaders: joseHeaders,
-		Payload:          payload,
-		signature:        signature,
-		joseHeaders:      joseHeaders,
+		ProtectedHeaders:    joseHeaders,
+		Payload:             payload,
+		signature:           signature,
+		joseHeaders:         joseHeaders,
+		b64ProtectedHeaders: parts[jwsHeaderPart],
 	}, nil
 }
 
+// verifySignature verifies signature with verifier, unless it is the empty signature of an unsecured JWS allowed by
+// opts.
+func verifySignature(verifier SignatureVerifier, joseHeaders Headers, payload, sInput, signature []byte,
+	opts *jwsParseOpts) error {
+	if alg, _ := joseHeaders.Algorithm(); opts.allowedAlgs != nil && !slices.Contains(opts.allowedAlgs, alg) {
+		return fmt.Errorf("%w: '%s'", ErrDisallowedAlgorithm, alg)
+	}
+
+	// the alg is compared case-insensitively to also reject variants like "None" which lenient verifiers might accept.
+	if alg, _ := joseHeaders.Algorithm(); strings.EqualFold(alg, unsecuredAlg) {
+		if !opts.allowUnsecured || alg != unsecuredAlg {
+			return ErrUnsecuredJWS
+		}
+
+		if len(signature) > 0 {
+			return errors.New("unsecured JWS must have an empty signature")
+		}
+
+		return nil
+	}
+
+	return verifier.Verify(joseHeaders, payload, sInput, signature)
+}
+
 func parseCompactedPayload(jwsPayload string, opts *jwsParseOpts) ([]byte, error) {
 	if len(opts.detachedPayload) > 0 {
 		return opts.detachedPayload, nil
@@ -289,7 +391,11 @@ func parseCompactedPayload(jwsPayload string, opts *jwsParseOpts) ([]byte, error
 }
 
 func parseCompactedHeaders(parts []string) (Headers, error) {
-	headersBytes, err := base64.RawURLEncoding.DecodeString(parts[jwsHeaderPart])
+	return parseJWSHeaders(parts[jwsHeaderPart])
+}
+
+func parseJWSHeaders(b64Headers string) (Headers, error) {
+	headersBytes, err := base64.RawURLEncoding.DecodeString(b64Headers)
 	if err != nil {
 		return nil, fmt.Errorf("decode base64 header: %w", err)
 	}
@@ -310,11 +416,6 @@ func parseCompactedHeaders(parts []string) (Headers, error) {
 }
 
 func signingInput(headers Headers, header string, payload []byte) ([]byte, error) {
-	headersBytes, err := json.Marshal(headers)
-	if err != nil {
-		return nil, fmt.Errorf("serialize JWS headers: %w", err)
-	}
-
 	hBase64 := true
 
 	if b64, ok := headers[HeaderB64Payload]; ok {
@@ -327,6 +428,11 @@ func signingInput(headers Headers, header string, payload []byte) ([]byte, error
 	headersStr := header
 
 	if headersStr == "" {
+		headersBytes, err := json.Marshal(headers)
+		if err != nil {
+			return nil, fmt.Errorf("serialize JWS headers: %w", err)
+		}
+
 		headersStr = base64.RawURLEncoding.EncodeToString(headersBytes)
 	}
 
@@ -346,7 +452,7 @@ func checkJWSHeaders(headers Headers) error {
 		return fmt.Errorf("%s JWS header is not defined", HeaderAlgorithm)
 	}
 
-	return nil
+	return checkStringHeaders(headers)
 }
 
 func convertMapToValue(vOriginToBeMap, vDest interface{}) error {
@@ -0,0 +1,106 @@
+/*
+Copyright Gen Digital Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package jose
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+
+	"github.com/dellekappa/kms-go/doc/jose/jwk"
+)
+
+// ErrEmbeddedJWKWithKID is returned when the WithEmbeddedJWK option is set for a JWS with a "kid" header: the key of
+// a self-describing JWS is its embedded jwk, it must not also be identified by a kid resolved by the verifier.
+var ErrEmbeddedJWKWithKID = errors.New("embedded jwk header can't be combined with a kid header")
+
+// JWKSigner is a Signer exposing its signing key, as required by the WithEmbeddedJWK option. SigningKey may return
+// the private key, only its public projection is embedded.
+type JWKSigner interface {
+	Signer
+	SigningKey() *jwk.JWK
+}
+
+// JWKTrustFunc decides whether the public key embedded in the "jwk" header of a JWS is trusted, returning an error if
+// it isn't.
+type JWKTrustFunc func(key *jwk.JWK) error
+
+// embedSignerJWK sets the "jwk" protected header to the public key of signer if the WithEmbeddedJWK option is set.
+func embedSignerJWK(protectedHeaders, unprotectedHeaders Headers, signer Signer, opts []HeaderOpt) error {
+	if !newHeaderOpts(opts).embedJWK {
+		return nil
+	}
+
+	if _, ok := protectedHeaders[HeaderKeyID]; ok {
+		return ErrEmbeddedJWKWithKID
+	}
+
+	if _, ok := unprotectedHeaders[HeaderKeyID]; ok {
+		return ErrEmbeddedJWKWithKID
+	}
+
+	jwkSigner, ok := signer.(JWKSigner)
+	if !ok {
+		return fmt.Errorf("embedded jwk header requires a JWKSigner, got %T", signer)
+	}
+
+	key := jwkSigner.SigningKey()
+	if key == nil || key.Key == nil {
+		return errors.New("embedded jwk header: signer has no signing key")
+	}
+
+	if jwkKty(key) == "oct" {
+		return errors.New("embedded jwk header: symmetric signing keys can't be embedded")
+	}
+
+	protectedHeaders[HeaderJSONWebKey] = key.Public()
+
+	return nil
+}
+
+// NewEmbeddedJWKSigVerifier creates a SignatureVerifier verifying a JWS signature with the public key embedded in its
+// "jwk" protected header, see WithEmbeddedJWK. The embedded key is checked against the JWS "alg" header and opts are
+// applied as in NewKIDSigVerifier.
+//
+// trust is the explicit trust decision of the caller on the embedded key (see the ParseHeaderJWK warning): a
+// verified signature only proves the signer holds the embedded key. trust may accept any key when the key is bound
+// by other means after verification, like the jkt confirmation of a DPoP bound access token. A nil trust rejects
+// every JWS.
+func NewEmbeddedJWKSigVerifier(trust JWKTrustFunc, opts ...StreamVerifierOpt) SignatureVerifier {
+	return SignatureVerifierFunc(func(joseHeaders Headers, payload, signingInput, signature []byte) error {
+		if trust == nil {
+			return errors.New("embeddedJWKSigVerifier: no trust decision for the jwk header")
+		}
+
+		alg, _ := joseHeaders.Algorithm()
+
+		key, err := ParseHeaderJWK(joseHeaders)
+		if err != nil {
+			return fmt.Errorf("embeddedJWKSigVerifier: %w", err)
+		}
+
+		err = trust(key)
+		if err != nil {
+			return fmt.Errorf("embeddedJWKSigVerifier: %w", err)
+		}
+
+		err = checkJWKAlg(key, alg)
+		if err != nil {
+			return fmt.Errorf("embeddedJWKSigVerifier: %w", err)
+		}
+
+		// the signing input starts with the base64url encoded protected headers, followed by '.'.
+		b64Headers, _, _ := bytes.Cut(signingInput, []byte("."))
+
+		err = verifyWithKey(string(b64Headers), payload, signature, key, opts...)
+		if err != nil {
+			return fmt.Errorf("embeddedJWKSigVerifier: %w", err)
+		}
+
+		return nil
+	})
+}
@@ -0,0 +1,171 @@
+/*
+Copyright Gen Digital Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package jose
+
+import (
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/elliptic"
+	"crypto/rand"
+	"errors"
+	"testing"
+
+	"github.com/go-jose/go-jose/v3"
+	"github.com/stretchr/testify/require"
+
+	"github.com/dellekappa/kms-go/doc/jose/jwk"
+)
+
+type jwkTestSigner struct {
+	key     *jwk.JWK
+	headers Headers
+	sign    func([]byte) []byte
+}
+
+func (s *jwkTestSigner) Sign(data []byte) ([]byte, error) {
+	return s.sign(data), nil
+}
+
+func (s *jwkTestSigner) Headers() Headers {
+	return s.headers
+}
+
+func (s *jwkTestSigner) SigningKey() *jwk.JWK {
+	return s.key
+}
+
+func TestWithEmbeddedJWK(t *testing.T) {
+	payload := []byte("payload")
+
+	ecKey, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+
+	_, edKey, err := ed25519.GenerateKey(rand.Reader)
+	require.NoError(t, err)
+
+	ecSigner := &jwkTestSigner{
+		key:     &jwk.JWK{JSONWebKey: jose.JSONWebKey{Key: ecKey}, Kty: "EC", Crv: "P-256"},
+		headers: Headers{HeaderAlgorithm: "ES256"},
+		sign:    ecdsaStreamTestSigner(t, ecKey, crypto.SHA256),
+	}
+
+	edSigner := &jwkTestSigner{
+		key:     &jwk.JWK{JSONWebKey: jose.JSONWebKey{Key: edKey}, Kty: "OKP", Crv: "Ed25519"},
+		headers: Headers{HeaderAlgorithm: eddsaAlg},
+		sign: func(data []byte) []byte {
+			return ed25519.Sign(edKey, data)
+		},
+	}
+
+	trustAny := func(*jwk.JWK) error { return nil }
+
+	t.Run("compact JWS", func(t *testing.T) {
+		for _, signer := range []*jwkTestSigner{ecSigner, edSigner} {
+			jws, err := NewJWS(nil, nil, payload, signer, WithType("dpop+jwt"), WithEmbeddedJWK(true))
+			require.NoError(t, err)
+
+			compactJWS, err := jws.SerializeCompact(false)
+			require.NoError(t, err)
+
+			var embedded *jwk.JWK
+
+			parsed, err := ParseJWS(compactJWS, NewEmbeddedJWKSigVerifier(func(key *jwk.JWK) error {
+				embedded = key
+
+				return nil
+			}))
+			require.NoError(t, err)
+			require.Equal(t, payload, parsed.Payload)
+			require.True(t, embedded.SamePublicKey(signer.key))
+
+			// the private key is not embedded.
+			headerJWK, err := ParseHeaderJWK(parsed.ProtectedHeaders)
+			require.NoError(t, err)
+			require.True(t, headerJWK.IsPublic())
+		}
+	})
+
+	t.Run("JWS builder", func(t *testing.T) {
+		jws, err := NewJWSBuilder(payload).AddSigner(edSigner, nil, nil, WithEmbeddedJWK(true)).Build()
+		require.NoError(t, err)
+
+		jwsJSON, err := jws.SerializeJSON(false)
+		require.NoError(t, err)
+
+		_, err = ParseGeneralJWS(jwsJSON, NewEmbeddedJWKSigVerifier(trustAny))
+		require.NoError(t, err)
+	})
+
+	t.Run("not embedded", func(t *testing.T) {
+		jws, err := NewJWS(nil, nil, payload, ecSigner, WithEmbeddedJWK(false))
+		require.NoError(t, err)
+
+		_, ok := jws.ProtectedHeaders.JWK()
+		require.False(t, ok)
+
+		compactJWS, err := jws.SerializeCompact(false)
+		require.NoError(t, err)
+
+		_, err = ParseJWS(compactJWS, NewEmbeddedJWKSigVerifier(trustAny))
+		require.ErrorContains(t, err, "embeddedJWKSigVerifier: parseHeaderJWK: jwk header is missing")
+	})
+
+	t.Run("kid-only policy", func(t *testing.T) {
+		_, err := NewJWS(Headers{HeaderKeyID: "key-1"}, nil, payload, ecSigner, WithEmbeddedJWK(true))
+		require.ErrorIs(t, err, ErrEmbeddedJWKWithKID)
+
+		_, err = NewJWS(nil, Headers{HeaderKeyID: "key-1"}, payload, ecSigner, WithEmbeddedJWK(true))
+		require.ErrorIs(t, err, ErrEmbeddedJWKWithKID)
+
+		_, err = NewJWSBuilder(payload).AddSigner(ecSigner, Headers{HeaderKeyID: "key-1"}, nil,
+			WithEmbeddedJWK(true)).Build()
+		require.ErrorIs(t, err, ErrEmbeddedJWKWithKID)
+	})
+
+	t.Run("invalid signer", func(t *testing.T) {
+		_, err := NewJWS(nil, nil, payload, &testSigner{headers: Headers{HeaderAlgorithm: "ES256"}},
+			WithEmbeddedJWK(true))
+		require.EqualError(t, err, "sign JWS: embedded jwk header requires a JWKSigner, got *jose.testSigner")
+
+		_, err = NewJWS(nil, nil, payload, &jwkTestSigner{headers: Headers{HeaderAlgorithm: "ES256"}},
+			WithEmbeddedJWK(true))
+		require.EqualError(t, err, "sign JWS: embedded jwk header: signer has no signing key")
+
+		_, err = NewJWS(nil, nil, payload, &jwkTestSigner{
+			key:     &jwk.JWK{JSONWebKey: jose.JSONWebKey{Key: []byte("secret")}, Kty: "oct"},
+			headers: Headers{HeaderAlgorithm: "HS256"},
+		}, WithEmbeddedJWK(true))
+		require.EqualError(t, err, "sign JWS: embedded jwk header: symmetric signing keys can't be embedded")
+	})
+
+	t.Run("verification failures", func(t *testing.T) {
+		jws, err := NewJWS(nil, nil, payload, ecSigner, WithEmbeddedJWK(true))
+		require.NoError(t, err)
+
+		compactJWS, err := jws.SerializeCompact(false)
+		require.NoError(t, err)
+
+		_, err = ParseJWS(compactJWS, NewEmbeddedJWKSigVerifier(nil))
+		require.ErrorContains(t, err, "embeddedJWKSigVerifier: no trust decision for the jwk header")
+
+		errUntrusted := errors.New("untrusted")
+
+		_, err = ParseJWS(compactJWS, NewEmbeddedJWKSigVerifier(func(*jwk.JWK) error { return errUntrusted }))
+		require.ErrorIs(t, err, errUntrusted)
+
+		// embedded key of another algorithm.
+		jws, err = NewJWS(Headers{HeaderAlgorithm: "ES384"}, nil, payload, ecSigner, WithEmbeddedJWK(true))
+		require.NoError(t, err)
+
+		compactJWS, err = jws.SerializeCompact(false)
+		require.NoError(t, err)
+
+		_, err = ParseJWS(compactJWS, NewEmbeddedJWKSigVerifier(trustAny))
+		require.ErrorContains(t, err, "embeddedJWKSigVerifier: ")
+	})
+}
@@ -0,0 +1,373 @@
+/*
+Copyright Gen Digital Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package jose
+
+import (
+	"encoding/base64"
+	"errors"
+	"fmt"
+
+	"github.com/go-jose/go-jose/v3/json"
+)
+
+// GeneralJSONWebSignature is a JWS carrying one or more signatures over the same payload, as serialized with the JWS
+// JSON general serialization (https://tools.ietf.org/html/rfc7515#section-7.2.1).
+type GeneralJSONWebSignature struct {
+	Payload    []byte
+	Signatures []*JWSSignature
+}
+
+// JWSSignature is a single signature of a GeneralJSONWebSignature. Only ProtectedHeaders are part of the signing
+// input, UnprotectedHeaders are serialized in the signature's "header" member.
+type JWSSignature struct {
+	ProtectedHeaders   Headers
+	UnprotectedHeaders Headers
+	Signature          []byte
+
+	b64ProtectedHeaders string
+}
+
+// KeyID gets the Key ID of the signature from its protected or unprotected headers.
+func (s *JWSSignature) KeyID() (string, bool) {
+	return s.joseHeaders().KeyID()
+}
+
+// joseHeaders returns the union of the protected and unprotected headers.
+func (s *JWSSignature) joseHeaders() Headers {
+	return mergeHeaders(s.ProtectedHeaders, s.UnprotectedHeaders)
+}
+
+// rawGeneralJSONWebSignature represents a RAW JWS that is used for JSON serialization/deserialization.
+type rawGeneralJSONWebSignature struct {
+	Payload    string             `json:"payload,omitempty"`
+	Signatures []*rawJWSSignature `json:"signatures"`
+}
+
+type rawJWSSignature struct {
+	B64ProtectedHeaders string  `json:"protected,omitempty"`
+	UnprotectedHeaders  Headers `json:"header,omitempty"`
+	B64Signature        string  `json:"signature"`
+}
+
+type jwsBuilderSigner struct {
+	signer             Signer
+	protectedHeaders   Headers
+	unprotectedHeaders Headers
+	headerOpts         []HeaderOpt
+}
+
+// JWSBuilder builds a GeneralJSONWebSignature signed by one or more signers. Use NewJWS for a single signer JWS to be
+// serialized with the compact serialization.
+type JWSBuilder struct {
+	payload []byte
+	signers []*jwsBuilderSigner
+}
+
+// NewJWSBuilder creates a JWSBuilder for the given payload.
+func NewJWSBuilder(payload []byte) *JWSBuilder {
+	return &JWSBuilder{payload: payload}
+}
+
+// AddSigner adds a signer to the JWS. protectedHeaders are merged with the signer's headers, taking precedence over
+// them. unprotectedHeaders are not integrity protected as they are excluded from the signing input: they must not
+// contain the "alg" header, which must stay in the protected headers, nor any protected header.
+// When more than one signer is added, each signature must have a "kid" header, either protected or unprotected.
+// The WithType and WithContentType options set the "typ" and "cty" protected headers of the signature,
+// WithEmbeddedJWK sets its "jwk" protected header to the public key of signer.
+func (b *JWSBuilder) AddSigner(signer Signer, protectedHeaders, unprotectedHeaders Headers,
+	opts ...HeaderOpt) *JWSBuilder {
+	b.signers = append(b.signers, &jwsBuilderSigner{
+		signer:             signer,
+		protectedHeaders:   protectedHeaders,
+		unprotectedHeaders: unprotectedHeaders,
+		headerOpts:         opts,
+	})
+
+	return b
+}
+
+// Build signs the payload with all the added signers.
+func (b *JWSBuilder) Build() (*GeneralJSONWebSignature, error) {
+	if len(b.signers) == 0 {
+		return nil, errors.New("build JWS: no signer")
+	}
+
+	jws := &GeneralJSONWebSignature{
+		Payload: b.payload,
+	}
+
+	for i, s := range b.signers {
+		headers := mergeHeaders(s.protectedHeaders, s.signer.Headers())
+		applyHeaderOpts(headers, s.headerOpts)
+
+		err := embedSignerJWK(headers, s.unprotectedHeaders, s.signer, s.headerOpts)
+		if err != nil {
+			return nil, fmt.Errorf("build JWS: signature %d: %w", i, err)
+		}
+
+		err = checkJWSUnprotectedHeaders(headers, s.unprotectedHeaders)
+		if err != nil {
+			return nil, fmt.Errorf("build JWS: signature %d: %w", i, err)
+		}
+
+		sig := &JWSSignature{
+			ProtectedHeaders:   headers,
+			UnprotectedHeaders: s.unprotectedHeaders,
+		}
+
+		if _, ok := sig.KeyID(); !ok && len(b.signers) > 1 {
+			return nil, fmt.Errorf("build JWS: signature %d: %s JWS header is required with multiple signers",
+				i, HeaderKeyID)
+		}
+
+		_, sig.Signature, err = sign(headers, b.payload, s.signer)
+		if err != nil {
+			return nil, fmt.Errorf("build JWS: signature %d: %w", i, err)
+		}
+
+		jws.Signatures = append(jws.Signatures, sig)
+	}
+
+	if _, err := jws.isB64Payload(); err != nil {
+		return nil, fmt.Errorf("build JWS: %w", err)
+	}
+
+	return jws, nil
+}
+
+// SerializeJSON makes JWS JSON general serialization (https://tools.ietf.org/html/rfc7515#section-7.2.1).
+func (s *GeneralJSONWebSignature) SerializeJSON(detached bool) (string, error) {
+	b64Payload, err := s.isB64Payload()
+	if err != nil {
+		return "", err
+	}
+
+	raw := &rawGeneralJSONWebSignature{}
+
+	if !detached {
+		raw.Payload = string(s.Payload)
+
+		if b64Payload {
+			raw.Payload = base64.RawURLEncoding.EncodeToString(s.Payload)
+		}
+	}
+
+	for _, sig := range s.Signatures {
+		b64Headers := sig.b64ProtectedHeaders
+
+		if b64Headers == "" {
+			headersBytes, e := json.Marshal(sig.ProtectedHeaders)
+			if e != nil {
+				return "", fmt.Errorf("marshal JWS JOSE Headers: %w", e)
+			}
+
+			b64Headers = base64.RawURLEncoding.EncodeToString(headersBytes)
+		}
+
+		raw.Signatures = append(raw.Signatures, &rawJWSSignature{
+			B64ProtectedHeaders: b64Headers,
+			UnprotectedHeaders:  sig.UnprotectedHeaders,
+			B64Signature:        base64.RawURLEncoding.EncodeToString(sig.Signature),
+		})
+	}
+
+	jwsBytes, err := json.Marshal(raw)
+	if err != nil {
+		return "", fmt.Errorf("marshal JWS JSON serialization: %w", err)
+	}
+
+	return string(jwsBytes), nil
+}
+
+// isB64Payload returns the value of the "b64" header shared by all signatures (https://tools.ietf.org/html/rfc7797).
+func (s *GeneralJSONWebSignature) isB64Payload() (bool, error) {
+	if len(s.Signatures) == 0 {
+		return false, errors.New("JWS has no signature")
+	}
+
+	var b64 bool
+
+	for i, sig := range s.Signatures {
+		sigB64 := true
+
+		if v, ok := sig.ProtectedHeaders[HeaderB64Payload]; ok {
+			if sigB64, ok = v.(bool); !ok {
+				return false, errors.New("invalid b64 header")
+			}
+		}
+
+		if i > 0 && sigB64 != b64 {
+			return false, errors.New("b64 header must be the same for all signatures")
+		}
+
+		b64 = sigB64
+	}
+
+	return b64, nil
+}
+
+// WithJWSRequiredSigners option sets the key IDs of the signatures which must be present and valid when parsing a
+// JWS JSON general serialization. Signatures from other signers which fail verification are then discarded instead of
+// failing the parsing.
+func WithJWSRequiredSigners(kids ...string) JWSParseOpt {
+	return func(opts *jwsParseOpts) {
+		opts.requiredSigners = kids
+	}
+}
+
+// ParseGeneralJWS parses a JWS JSON general serialization and verifies its signatures with verifier.
+// By default, all signatures must be valid. If WithJWSRequiredSigners is set, only the signatures of the required
+// signers must be valid and the returned JWS only holds the signatures which were successfully verified.
+// The verifier is given the union of the protected and unprotected headers of each signature as JOSE headers, a
+// DefaultSigningInputVerifier must therefore not be used with signatures having unprotected headers.
+func ParseGeneralJWS(jws string, verifier SignatureVerifier, opts ...JWSParseOpt) (*GeneralJSONWebSignature, error) {
+	pOpts := &jwsParseOpts{}
+
+	for _, opt := range opts {
+		opt(pOpts)
+	}
+
+	raw := &rawGeneralJSONWebSignature{}
+
+	err := json.Unmarshal([]byte(jws), raw)
+	if err != nil {
+		return nil, fmt.Errorf("unmarshal JWS JSON serialization: %w", err)
+	}
+
+	parsed := &GeneralJSONWebSignature{}
+
+	for i, rawSig := range raw.Signatures {
+		sig, e := parseJWSSignature(rawSig)
+		if e != nil {
+			return nil, fmt.Errorf("signature %d: %w", i, e)
+		}
+
+		parsed.Signatures = append(parsed.Signatures, sig)
+	}
+
+	b64Payload, err := parsed.isB64Payload()
+	if err != nil {
+		return nil, err
+	}
+
+	parsed.Payload, err = parseGeneralPayload(raw.Payload, b64Payload, pOpts)
+	if err != nil {
+		return nil, err
+	}
+
+	parsed.Signatures, err = verifyGeneralSignatures(parsed, verifier, pOpts)
+	if err != nil {
+		return nil, err
+	}
+
+	return parsed, nil
+}
+
+func parseJWSSignature(rawSig *rawJWSSignature) (*JWSSignature, error) {
+	headers, err := parseJWSHeaders(rawSig.B64ProtectedHeaders)
+	if err != nil {
+		return nil, err
+	}
+
+	err = checkJWSUnprotectedHeaders(headers, rawSig.UnprotectedHeaders)
+	if err != nil {
+		return nil, err
+	}
+
+	signature, err := base64.RawURLEncoding.DecodeString(rawSig.B64Signature)
+	if err != nil {
+		return nil, fmt.Errorf("decode base64 signature: %w", err)
+	}
+
+	return &JWSSignature{
+		ProtectedHeaders:    headers,
+		UnprotectedHeaders:  rawSig.UnprotectedHeaders,
+		Signature:           signature,
+		b64ProtectedHeaders: rawSig.B64ProtectedHeaders,
+	}, nil
+}
+
+// checkJWSUnprotectedHeaders checks the protected and unprotected headers are disjoint and that "alg" is protected
+// (https://tools.ietf.org/html/rfc7515#section-7.2.1).
+func checkJWSUnprotectedHeaders(protectedHeaders, unprotectedHeaders Headers) error {
+	if _, ok := unprotectedHeaders[HeaderAlgorithm]; ok {
+		return fmt.Errorf("%s JWS header must be protected", HeaderAlgorithm)
+	}
+
+	for k := range unprotectedHeaders {
+		if _, ok := protectedHeaders[k]; ok {
+			return fmt.Errorf("%s JWS header is both protected and unprotected", k)
+		}
+	}
+
+	return nil
+}
+
+func parseGeneralPayload(payload string, b64Payload bool, opts *jwsParseOpts) ([]byte, error) {
+	if len(opts.detachedPayload) > 0 {
+		return opts.detachedPayload, nil
+	}
+
+	if !b64Payload {
+		return []byte(payload), nil
+	}
+
+	decoded, err := base64.RawURLEncoding.DecodeString(payload)
+	if err != nil {
+		return nil, fmt.Errorf("decode base64 payload: %w", err)
+	}
+
+	return decoded, nil
+}
+
+func verifyGeneralSignatures(jws *GeneralJSONWebSignature, verifier SignatureVerifier,
+	opts *jwsParseOpts) ([]*JWSSignature, error) {
+	var verified []*JWSSignature
+
+	verifiedKIDs := make(map[string]bool)
+
+	for i, sig := range jws.Signatures {
+		sInput, err := signingInput(sig.ProtectedHeaders, sig.b64ProtectedHeaders, jws.Payload)
+		if err != nil {
+			return nil, fmt.Errorf("signature %d: build signing input: %w", i, err)
+		}
+
+		err = checkCriticalHeaders(sig.ProtectedHeaders, sig.UnprotectedHeaders, opts)
+		if err == nil {
+			err = verifySignature(verifier, sig.joseHeaders(), jws.Payload, sInput, sig.Signature, opts)
+		}
+
+		if err != nil {
+			if len(opts.requiredSigners) == 0 {
+				return nil, fmt.Errorf("signature %d: %w", i, err)
+			}
+
+			continue
+		}
+
+		// an unsecured signature does not prove its kid signed the payload.
+		if alg, _ := sig.ProtectedHeaders.Algorithm(); alg == unsecuredAlg {
+			verified = append(verified, sig)
+
+			continue
+		}
+
+		if kid, ok := sig.KeyID(); ok {
+			verifiedKIDs[kid] = true
+		}
+
+		verified = append(verified, sig)
+	}
+
+	for _, kid := range opts.requiredSigners {
+		if !verifiedKIDs[kid] {
+			return nil, fmt.Errorf("signature of required signer '%s' is missing or invalid", kid)
+		}
+	}
+
+	return verified, nil
+}
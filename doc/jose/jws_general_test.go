@@ -0,0 +1,426 @@
+/*
+Copyright Gen Digital Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package jose
+
+import (
+	"crypto/ed25519"
+	"crypto/rand"
+	"errors"
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/go-jose/go-jose/v3/json"
+	"github.com/stretchr/testify/require"
+)
+
+func TestJWSBuilder(t *testing.T) {
+	payload := []byte("payload")
+
+	serviceSigner := newEd25519TestSigner(t, "service")
+	notarySigner := newEd25519TestSigner(t, "notary")
+
+	t.Run("success multiple signers", func(t *testing.T) {
+		jws, err := NewJWSBuilder(payload).
+			AddSigner(serviceSigner, Headers{"typ": "JWT"}, nil).
+			AddSigner(notarySigner, nil, nil).
+			Build()
+		require.NoError(t, err)
+		require.Len(t, jws.Signatures, 2)
+
+		kid, ok := jws.Signatures[0].KeyID()
+		require.True(t, ok)
+		require.Equal(t, "service", kid)
+
+		typ, ok := jws.Signatures[0].ProtectedHeaders.Type()
+		require.True(t, ok)
+		require.Equal(t, "JWT", typ)
+
+		kid, ok = jws.Signatures[1].KeyID()
+		require.True(t, ok)
+		require.Equal(t, "notary", kid)
+
+		jwsJSON, err := jws.SerializeJSON(false)
+		require.NoError(t, err)
+
+		raw := &rawGeneralJSONWebSignature{}
+		require.NoError(t, json.Unmarshal([]byte(jwsJSON), raw))
+		require.Len(t, raw.Signatures, 2)
+		require.NotEmpty(t, raw.Payload)
+
+		verifier := newEd25519TestVerifier(serviceSigner, notarySigner)
+
+		parsed, err := ParseGeneralJWS(jwsJSON, verifier)
+		require.NoError(t, err)
+		require.Equal(t, payload, parsed.Payload)
+		require.Len(t, parsed.Signatures, 2)
+		require.Equal(t, jws.Signatures[0].ProtectedHeaders, parsed.Signatures[0].ProtectedHeaders)
+		require.Equal(t, jws.Signatures[1].Signature, parsed.Signatures[1].Signature)
+
+		// re-serializing a parsed JWS keeps the original protected headers.
+		reserialized, err := parsed.SerializeJSON(false)
+		require.NoError(t, err)
+		require.Equal(t, jwsJSON, reserialized)
+
+		// detached payload
+		jwsJSON, err = jws.SerializeJSON(true)
+		require.NoError(t, err)
+		require.NotContains(t, jwsJSON, `"payload"`)
+
+		parsed, err = ParseGeneralJWS(jwsJSON, verifier, WithJWSDetachedPayload(payload))
+		require.NoError(t, err)
+		require.Equal(t, payload, parsed.Payload)
+	})
+
+	t.Run("success single signer without kid", func(t *testing.T) {
+		jws, err := NewJWSBuilder(payload).
+			AddSigner(&testSigner{headers: Headers{"alg": "dummy"}, signature: []byte("signature")}, nil, nil).
+			Build()
+		require.NoError(t, err)
+		require.Len(t, jws.Signatures, 1)
+
+		jwsJSON, err := jws.SerializeJSON(false)
+		require.NoError(t, err)
+
+		parsed, err := ParseGeneralJWS(jwsJSON, &testVerifier{})
+		require.NoError(t, err)
+		require.Equal(t, jws, parsed.withoutRawHeaders())
+	})
+
+	t.Run("success unencoded payload", func(t *testing.T) {
+		jws, err := NewJWSBuilder(payload).
+			AddSigner(serviceSigner, Headers{"b64": false}, nil).
+			AddSigner(notarySigner, Headers{"b64": false}, nil).
+			Build()
+		require.NoError(t, err)
+
+		jwsJSON, err := jws.SerializeJSON(false)
+		require.NoError(t, err)
+		require.Contains(t, jwsJSON, `"payload":"payload"`)
+
+		parsed, err := ParseGeneralJWS(jwsJSON, newEd25519TestVerifier(serviceSigner, notarySigner))
+		require.NoError(t, err)
+		require.Equal(t, payload, parsed.Payload)
+	})
+
+	t.Run("failure", func(t *testing.T) {
+		_, err := NewJWSBuilder(payload).Build()
+		require.EqualError(t, err, "build JWS: no signer")
+
+		_, err = NewJWSBuilder(payload).
+			AddSigner(serviceSigner, nil, nil).
+			AddSigner(&testSigner{headers: Headers{"alg": "dummy"}}, nil, nil).
+			Build()
+		require.EqualError(t, err, "build JWS: signature 1: kid JWS header is required with multiple signers")
+
+		_, err = NewJWSBuilder(payload).
+			AddSigner(serviceSigner, nil, nil).
+			AddSigner(&testSigner{headers: Headers{"kid": "other"}}, nil, nil).
+			Build()
+		require.EqualError(t, err, "build JWS: signature 1: check JOSE headers: alg JWS header is not defined")
+
+		_, err = NewJWSBuilder(payload).
+			AddSigner(serviceSigner, Headers{"b64": false}, nil).
+			AddSigner(notarySigner, nil, nil).
+			Build()
+		require.EqualError(t, err, "build JWS: b64 header must be the same for all signatures")
+
+		_, err = (&GeneralJSONWebSignature{}).SerializeJSON(false)
+		require.EqualError(t, err, "JWS has no signature")
+
+		_, err = (&GeneralJSONWebSignature{Signatures: []*JWSSignature{{ProtectedHeaders: getUnmarshallableMap()}}}).
+			SerializeJSON(false)
+		require.ErrorContains(t, err, "marshal JWS JOSE Headers")
+	})
+}
+
+func TestParseGeneralJWS(t *testing.T) {
+	payload := []byte("payload")
+
+	serviceSigner := newEd25519TestSigner(t, "service")
+	notarySigner := newEd25519TestSigner(t, "notary")
+	otherSigner := newEd25519TestSigner(t, "other")
+
+	jws, err := NewJWSBuilder(payload).
+		AddSigner(serviceSigner, nil, nil).
+		AddSigner(notarySigner, nil, nil).
+		AddSigner(otherSigner, nil, nil).
+		Build()
+	require.NoError(t, err)
+
+	jwsJSON, err := jws.SerializeJSON(false)
+	require.NoError(t, err)
+
+	// the verifier does not know the key of "other".
+	verifier := newEd25519TestVerifier(serviceSigner, notarySigner)
+
+	t.Run("success with required signers", func(t *testing.T) {
+		parsed, err := ParseGeneralJWS(jwsJSON, verifier, WithJWSRequiredSigners("service", "notary"))
+		require.NoError(t, err)
+		require.Len(t, parsed.Signatures, 2)
+
+		parsed, err = ParseGeneralJWS(jwsJSON, verifier, WithJWSRequiredSigners("notary"))
+		require.NoError(t, err)
+		require.Len(t, parsed.Signatures, 2)
+	})
+
+	t.Run("failure all signatures required", func(t *testing.T) {
+		parsed, err := ParseGeneralJWS(jwsJSON, verifier)
+		require.EqualError(t, err, "signature 2: unknown kid 'other'")
+		require.Nil(t, parsed)
+	})
+
+	t.Run("failure required signer invalid or missing", func(t *testing.T) {
+		parsed, err := ParseGeneralJWS(jwsJSON, verifier, WithJWSRequiredSigners("service", "other"))
+		require.EqualError(t, err, "signature of required signer 'other' is missing or invalid")
+		require.Nil(t, parsed)
+
+		parsed, err = ParseGeneralJWS(jwsJSON, verifier, WithJWSRequiredSigners("unknown"))
+		require.EqualError(t, err, "signature of required signer 'unknown' is missing or invalid")
+		require.Nil(t, parsed)
+	})
+
+	t.Run("failure tampered payload", func(t *testing.T) {
+		parsed, err := ParseGeneralJWS(jwsJSON, verifier, WithJWSDetachedPayload([]byte("tampered")),
+			WithJWSRequiredSigners("service"))
+		require.EqualError(t, err, "signature of required signer 'service' is missing or invalid")
+		require.Nil(t, parsed)
+	})
+
+	t.Run("failure invalid JWS JSON", func(t *testing.T) {
+		tests := []struct {
+			name string
+			jws  string
+			err  string
+		}{
+			{
+				name: "invalid JSON",
+				jws:  "{",
+				err:  "unmarshal JWS JSON serialization",
+			},
+			{
+				name: "no signatures",
+				jws:  `{"payload":"cGF5bG9hZA"}`,
+				err:  "JWS has no signature",
+			},
+			{
+				name: "invalid protected headers",
+				jws:  `{"payload":"cGF5bG9hZA","signatures":[{"protected":"invalid","signature":"c2ln"}]}`,
+				err:  "signature 0: unmarshal JSON headers",
+			},
+			{
+				name: "missing alg",
+				jws:  `{"payload":"cGF5bG9hZA","signatures":[{"protected":"e30","signature":"c2ln"}]}`,
+				err:  "signature 0: alg JWS header is not defined",
+			},
+			{
+				name: "invalid signature",
+				jws:  `{"payload":"cGF5bG9hZA","signatures":[{"protected":"eyJhbGciOiJFZERTQSJ9","signature":"*"}]}`,
+				err:  "signature 0: decode base64 signature",
+			},
+			{
+				name: "invalid payload",
+				jws:  `{"payload":"*","signatures":[{"protected":"eyJhbGciOiJFZERTQSJ9","signature":"c2ln"}]}`,
+				err:  "decode base64 payload",
+			},
+			{
+				name: "invalid b64 header",
+				jws:  `{"payload":"cGF5bG9hZA","signatures":[{"protected":"eyJhbGciOiJFZERTQSIsImI2NCI6MX0","signature":"c2ln"}]}`, //nolint:lll
+				err:  "invalid b64 header",
+			},
+		}
+
+		for _, tc := range tests {
+			t.Run(tc.name, func(t *testing.T) {
+				parsed, err := ParseGeneralJWS(tc.jws, &testVerifier{})
+				require.ErrorContains(t, err, tc.err)
+				require.Nil(t, parsed)
+			})
+		}
+	})
+}
+
+func TestParseGeneralJWSUnsecured(t *testing.T) {
+	payload := []byte("payload")
+
+	serviceSigner := newEd25519TestSigner(t, "service")
+
+	jws, err := NewJWSBuilder(payload).
+		AddSigner(serviceSigner, nil, nil).
+		AddSigner(&testSigner{headers: Headers{HeaderAlgorithm: "none"}}, Headers{HeaderKeyID: "notary"}, nil).
+		Build()
+	require.NoError(t, err)
+
+	jwsJSON, err := jws.SerializeJSON(false)
+	require.NoError(t, err)
+
+	verifier := newEd25519TestVerifier(serviceSigner)
+
+	t.Run("rejected by default", func(t *testing.T) {
+		parsed, err := ParseGeneralJWS(jwsJSON, verifier)
+		require.ErrorIs(t, err, ErrUnsecuredJWS)
+		require.EqualError(t, err, "signature 1: unsecured JWS with 'none' alg is not allowed")
+		require.Nil(t, parsed)
+	})
+
+	t.Run("allowed", func(t *testing.T) {
+		parsed, err := ParseGeneralJWS(jwsJSON, verifier, AllowUnsecured(true))
+		require.NoError(t, err)
+		require.Len(t, parsed.Signatures, 2)
+	})
+
+	t.Run("allowed unsecured signature does not satisfy required signer", func(t *testing.T) {
+		parsed, err := ParseGeneralJWS(jwsJSON, verifier, AllowUnsecured(true), WithJWSRequiredSigners("notary"))
+		require.EqualError(t, err, "signature of required signer 'notary' is missing or invalid")
+		require.Nil(t, parsed)
+	})
+}
+
+func TestParseGeneralJWSAllowedAlgorithms(t *testing.T) {
+	serviceSigner := newEd25519TestSigner(t, "service")
+
+	jws, err := NewJWSBuilder([]byte("payload")).
+		AddSigner(serviceSigner, nil, nil).
+		AddSigner(&testSigner{headers: Headers{HeaderAlgorithm: "HS256"}, signature: []byte("mac")},
+			Headers{HeaderKeyID: "legacy"}, nil).
+		Build()
+	require.NoError(t, err)
+
+	jwsJSON, err := jws.SerializeJSON(false)
+	require.NoError(t, err)
+
+	verifier := newEd25519TestVerifier(serviceSigner)
+
+	parsed, err := ParseGeneralJWS(jwsJSON, verifier, AllowedAlgorithms([]string{"EdDSA"}))
+	require.ErrorIs(t, err, ErrDisallowedAlgorithm)
+	require.EqualError(t, err, "signature 1: JWS alg is not allowed: 'HS256'")
+	require.Nil(t, parsed)
+}
+
+func TestJWSUnprotectedHeaders(t *testing.T) {
+	payload := []byte("payload")
+
+	serviceSigner := newEd25519TestSigner(t, "service")
+	notarySigner := newEd25519TestSigner(t, "notary")
+	verifier := newEd25519TestVerifier(serviceSigner, notarySigner)
+
+	t.Run("success kid in unprotected headers", func(t *testing.T) {
+		jws, err := NewJWSBuilder(payload).
+			AddSigner(&algOnlySigner{serviceSigner}, nil, Headers{"kid": "service", "note": "original"}).
+			AddSigner(&algOnlySigner{notarySigner}, nil, Headers{"kid": "notary"}).
+			Build()
+		require.NoError(t, err)
+
+		_, ok := jws.Signatures[0].ProtectedHeaders.KeyID()
+		require.False(t, ok)
+
+		kid, ok := jws.Signatures[0].KeyID()
+		require.True(t, ok)
+		require.Equal(t, "service", kid)
+
+		jwsJSON, err := jws.SerializeJSON(false)
+		require.NoError(t, err)
+		require.Contains(t, jwsJSON, `"header":{"kid":"service","note":"original"}`)
+
+		parsed, err := ParseGeneralJWS(jwsJSON, verifier)
+		require.NoError(t, err)
+		require.Equal(t, jws, parsed.withoutRawHeaders())
+
+		// unprotected headers are not part of the signing input.
+		jwsJSON = strings.Replace(jwsJSON, `"note":"original"`, `"note":"modified"`, 1)
+
+		parsed, err = ParseGeneralJWS(jwsJSON, verifier)
+		require.NoError(t, err)
+		require.Equal(t, Headers{"kid": "service", "note": "modified"}, parsed.Signatures[0].UnprotectedHeaders)
+	})
+
+	t.Run("failure invalid unprotected headers", func(t *testing.T) {
+		_, err := NewJWSBuilder(payload).
+			AddSigner(&algOnlySigner{serviceSigner}, nil, Headers{"alg": "EdDSA", "kid": "service"}).
+			Build()
+		require.EqualError(t, err, "build JWS: signature 0: alg JWS header must be protected")
+
+		_, err = NewJWSBuilder(payload).
+			AddSigner(serviceSigner, nil, Headers{"kid": "service"}).
+			Build()
+		require.EqualError(t, err, "build JWS: signature 0: kid JWS header is both protected and unprotected")
+
+		parsed, err := ParseGeneralJWS(
+			`{"signatures":[{"protected":"eyJhbGciOiJFZERTQSJ9","header":{"alg":"none"},"signature":"c2ln"}]}`,
+			&testVerifier{})
+		require.EqualError(t, err, "signature 0: alg JWS header must be protected")
+		require.Nil(t, parsed)
+	})
+}
+
+type algOnlySigner struct {
+	*ed25519TestSigner
+}
+
+func (s *algOnlySigner) Headers() Headers {
+	return Headers{HeaderAlgorithm: "EdDSA"}
+}
+
+// withoutRawHeaders returns a copy of the JWS without the original protected headers kept from parsing.
+func (s *GeneralJSONWebSignature) withoutRawHeaders() *GeneralJSONWebSignature {
+	c := &GeneralJSONWebSignature{Payload: s.Payload}
+
+	for _, sig := range s.Signatures {
+		c.Signatures = append(c.Signatures, &JWSSignature{
+			ProtectedHeaders:   sig.ProtectedHeaders,
+			UnprotectedHeaders: sig.UnprotectedHeaders,
+			Signature:          sig.Signature,
+		})
+	}
+
+	return c
+}
+
+type ed25519TestSigner struct {
+	kid     string
+	privKey ed25519.PrivateKey
+}
+
+func newEd25519TestSigner(t *testing.T, kid string) *ed25519TestSigner {
+	t.Helper()
+
+	_, privKey, err := ed25519.GenerateKey(rand.Reader)
+	require.NoError(t, err)
+
+	return &ed25519TestSigner{kid: kid, privKey: privKey}
+}
+
+func (s *ed25519TestSigner) Sign(data []byte) ([]byte, error) {
+	return ed25519.Sign(s.privKey, data), nil
+}
+
+func (s *ed25519TestSigner) Headers() Headers {
+	return Headers{HeaderAlgorithm: "EdDSA", HeaderKeyID: s.kid}
+}
+
+func newEd25519TestVerifier(signers ...*ed25519TestSigner) SignatureVerifier {
+	pubKeys := make(map[string]ed25519.PublicKey, len(signers))
+
+	for _, s := range signers {
+		pubKeys[s.kid] = s.privKey.Public().(ed25519.PublicKey) //nolint:errcheck,forcetypeassert
+	}
+
+	return SignatureVerifierFunc(func(joseHeaders Headers, _, signingInput, signature []byte) error {
+		kid, _ := joseHeaders.KeyID()
+
+		pubKey, ok := pubKeys[kid]
+		if !ok {
+			return fmt.Errorf("unknown kid '%s'", kid)
+		}
+
+		if !ed25519.Verify(pubKey, signingInput, signature) {
+			return errors.New("invalid signature")
+		}
+
+		return nil
+	})
+}
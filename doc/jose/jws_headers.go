@@ -0,0 +1,174 @@
+/*
+Copyright Gen Digital Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package jose
+
+import (
+	"errors"
+	"fmt"
+	"slices"
+)
+
+// ErrUnknownCriticalHeader is returned when parsing a JWS whose "crit" header lists an extension header which is
+// neither processed by the parser nor understood by the caller, see UnderstoodCritical.
+var ErrUnknownCriticalHeader = errors.New("unknown critical JWS header")
+
+// registeredJWSHeaders are the headers defined by RFC 7515 and RFC 7518, which must not be listed in the "crit"
+// header.
+var registeredJWSHeaders = map[string]bool{ //nolint:gochecknoglobals
+	HeaderAlgorithm:                   true,
+	HeaderJWKSetURL:                   true,
+	HeaderJSONWebKey:                  true,
+	HeaderKeyID:                       true,
+	HeaderX509URL:                     true,
+	HeaderX509CertificateChain:        true,
+	HeaderX509CertificateDigestSha1:   true,
+	HeaderX509CertificateDigestSha256: true,
+	HeaderType:                        true,
+	HeaderContentType:                 true,
+	HeaderCritical:                    true,
+}
+
+// JWSHeaders are the decoded headers of a JWS signature, as returned by JSONWebSignature.Headers and
+// JWSSignature.Headers after parsing and verification, to apply application policies (e.g. on critical headers) to
+// a verified JWS.
+type JWSHeaders struct {
+	// Protected are the integrity protected headers.
+	Protected Headers
+	// Unprotected are the headers not covered by the signature, only found in the JWS JSON serialization.
+	Unprotected Headers
+	// RawProtected is the base64url encoded protected header, as found in the parsed JWS. It is empty for the
+	// signatures of a GeneralJSONWebSignature which wasn't parsed.
+	RawProtected string
+}
+
+// KID gets the Key ID from the protected headers, or from the unprotected ones if not protected.
+func (h JWSHeaders) KID() (string, bool) {
+	if kid, ok := h.Protected.KeyID(); ok {
+		return kid, true
+	}
+
+	return h.Unprotected.KeyID()
+}
+
+// Algorithm gets the signature algorithm from the protected headers, "alg" being always protected.
+func (h JWSHeaders) Algorithm() (string, bool) {
+	return h.Protected.Algorithm()
+}
+
+// Type gets the "typ" header (e.g. "JWT") from the protected headers, or from the unprotected ones if not protected.
+func (h JWSHeaders) Type() (string, bool) {
+	if typ, ok := h.Protected.Type(); ok {
+		return typ, true
+	}
+
+	return h.Unprotected.Type()
+}
+
+// ContentType gets the "cty" header from the protected headers, or from the unprotected ones if not protected.
+func (h JWSHeaders) ContentType() (string, bool) {
+	if cty, ok := h.Protected.ContentType(); ok {
+		return cty, true
+	}
+
+	return h.Unprotected.ContentType()
+}
+
+// Critical gets the names of the protected "crit" header (https://tools.ietf.org/html/rfc7515#section-4.1.11). It
+// returns nil if the header is not present or is not an array of strings.
+func (h JWSHeaders) Critical() []string {
+	return h.Protected.Critical()
+}
+
+// Critical gets the names of the "crit" header, nil if the header is not present or is not an array of strings.
+func (h Headers) Critical() []string {
+	raw, ok := h[HeaderCritical]
+	if !ok {
+		return nil
+	}
+
+	switch crit := raw.(type) {
+	case []string:
+		return crit
+	case []interface{}:
+		names := make([]string, 0, len(crit))
+
+		for _, v := range crit {
+			name, ok := v.(string)
+			if !ok {
+				return nil
+			}
+
+			names = append(names, name)
+		}
+
+		return names
+	default:
+		return nil
+	}
+}
+
+// Headers returns the decoded headers of the JWS.
+func (s JSONWebSignature) Headers() JWSHeaders {
+	return JWSHeaders{
+		Protected:    s.ProtectedHeaders,
+		Unprotected:  s.UnprotectedHeaders,
+		RawProtected: s.b64ProtectedHeaders,
+	}
+}
+
+// Headers returns the decoded headers of the signature.
+func (s *JWSSignature) Headers() JWSHeaders {
+	return JWSHeaders{
+		Protected:    s.ProtectedHeaders,
+		Unprotected:  s.UnprotectedHeaders,
+		RawProtected: s.b64ProtectedHeaders,
+	}
+}
+
+// UnderstoodCritical option sets the names of the extension headers the caller understands and processes when
+// listed in the "crit" header of a parsed JWS, in addition to the "b64" header processed by the parser
+// (https://tools.ietf.org/html/rfc7515#section-4.1.11). A JWS with any other critical header is rejected with
+// ErrUnknownCriticalHeader, before its signature is verified.
+func UnderstoodCritical(names []string) JWSParseOpt {
+	return func(opts *jwsParseOpts) {
+		opts.understoodCrit = names
+	}
+}
+
+// checkCriticalHeaders checks the "crit" header of a JWS signature: it must be protected, be a non-empty array of
+// extension header names present in the protected headers, and only list headers understood by the parser or the
+// caller.
+func checkCriticalHeaders(protectedHeaders, unprotectedHeaders Headers, opts *jwsParseOpts) error {
+	if _, ok := unprotectedHeaders[HeaderCritical]; ok {
+		return fmt.Errorf("%s JWS header must be protected", HeaderCritical)
+	}
+
+	if _, ok := protectedHeaders[HeaderCritical]; !ok {
+		return nil
+	}
+
+	crit := protectedHeaders.Critical()
+	if len(crit) == 0 {
+		return fmt.Errorf("%s JWS header must be a non-empty array of strings", HeaderCritical)
+	}
+
+	for _, name := range crit {
+		if registeredJWSHeaders[name] {
+			return fmt.Errorf("%s JWS header must not list the registered header '%s'", HeaderCritical, name)
+		}
+
+		if _, ok := protectedHeaders[name]; !ok {
+			return fmt.Errorf("critical JWS header '%s' is not present", name)
+		}
+
+		if name != HeaderB64Payload && !slices.Contains(opts.understoodCrit, name) {
+			return fmt.Errorf("%w: '%s'", ErrUnknownCriticalHeader, name)
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,169 @@
+/*
+Copyright Gen Digital Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package jose
+
+import (
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestJWSHeaders(t *testing.T) {
+	t.Run("compact JWS", func(t *testing.T) {
+		jws, err := NewJWS(Headers{"kid": "key1", "crit": []string{"b64"}, "b64": true}, nil, []byte("payload"),
+			&testSigner{
+				headers:   Headers{"alg": "EdDSA"},
+				signature: []byte("signature"),
+			})
+		require.NoError(t, err)
+
+		jwsCompact, err := jws.SerializeCompact(false)
+		require.NoError(t, err)
+
+		parsed, err := ParseJWS(jwsCompact, &testVerifier{})
+		require.NoError(t, err)
+
+		headers := parsed.Headers()
+		require.Equal(t, strings.Split(jwsCompact, ".")[0], headers.RawProtected)
+		require.Equal(t, jws.Headers().RawProtected, headers.RawProtected)
+		require.Empty(t, headers.Unprotected)
+
+		kid, ok := headers.KID()
+		require.True(t, ok)
+		require.Equal(t, "key1", kid)
+
+		alg, ok := headers.Algorithm()
+		require.True(t, ok)
+		require.Equal(t, "EdDSA", alg)
+
+		require.Equal(t, []string{"b64"}, headers.Critical())
+	})
+
+	t.Run("general JWS", func(t *testing.T) {
+		signer := newEd25519TestSigner(t, "service")
+
+		jws, err := NewJWSBuilder([]byte("payload")).
+			AddSigner(signer, Headers{"crit": []string{"exp"}, "exp": 1}, Headers{"nonce": "n"}).
+			Build()
+		require.NoError(t, err)
+
+		jwsJSON, err := jws.SerializeJSON(false)
+		require.NoError(t, err)
+
+		parsed, err := ParseGeneralJWS(jwsJSON, newEd25519TestVerifier(signer), UnderstoodCritical([]string{"exp"}))
+		require.NoError(t, err)
+
+		headers := parsed.Signatures[0].Headers()
+		require.NotEmpty(t, headers.RawProtected)
+		require.Equal(t, Headers{"nonce": "n"}, headers.Unprotected)
+
+		kid, ok := headers.KID()
+		require.True(t, ok)
+		require.Equal(t, "service", kid)
+
+		require.Equal(t, []string{"exp"}, headers.Critical())
+	})
+
+	t.Run("critical", func(t *testing.T) {
+		require.Nil(t, Headers{}.Critical())
+		require.Nil(t, Headers{"crit": "b64"}.Critical())
+		require.Nil(t, Headers{"crit": []interface{}{"b64", 1}}.Critical())
+		require.Equal(t, []string{"b64"}, Headers{"crit": []interface{}{"b64"}}.Critical())
+
+		_, ok := JWSHeaders{Unprotected: Headers{"alg": "EdDSA"}}.Algorithm()
+		require.False(t, ok)
+
+		kid, ok := JWSHeaders{Unprotected: Headers{"kid": "key1"}}.KID()
+		require.True(t, ok)
+		require.Equal(t, "key1", kid)
+	})
+}
+
+func TestUnderstoodCritical(t *testing.T) {
+	compactJWS := func(protectedHeaders string) string {
+		return fmt.Sprintf("%s.%s.%s", base64.RawURLEncoding.EncodeToString([]byte(protectedHeaders)),
+			base64.RawURLEncoding.EncodeToString([]byte("payload")),
+			base64.RawURLEncoding.EncodeToString([]byte("signature")))
+	}
+
+	t.Run("b64 is understood", func(t *testing.T) {
+		_, err := ParseJWS(compactJWS(`{"alg":"EdDSA","b64":true,"crit":["b64"]}`), &testVerifier{})
+		require.NoError(t, err)
+	})
+
+	t.Run("header understood by the caller", func(t *testing.T) {
+		jws := compactJWS(`{"alg":"EdDSA","exp":1,"crit":["exp"]}`)
+
+		_, err := ParseJWS(jws, &testVerifier{})
+		require.ErrorIs(t, err, ErrUnknownCriticalHeader)
+		require.EqualError(t, err, "unknown critical JWS header: 'exp'")
+
+		_, err = ParseJWS(jws, &testVerifier{}, UnderstoodCritical([]string{"iat", "exp"}))
+		require.NoError(t, err)
+	})
+
+	t.Run("rejected before verification", func(t *testing.T) {
+		_, err := ParseJWS(compactJWS(`{"alg":"EdDSA","exp":1,"crit":["exp"]}`),
+			&testVerifier{err: errors.New("bad signature")})
+		require.ErrorIs(t, err, ErrUnknownCriticalHeader)
+	})
+
+	t.Run("invalid crit header", func(t *testing.T) {
+		for header, errMsg := range map[string]string{
+			`{"alg":"EdDSA","crit":[]}`:               "crit JWS header must be a non-empty array of strings",
+			`{"alg":"EdDSA","crit":"exp"}`:            "crit JWS header must be a non-empty array of strings",
+			`{"alg":"EdDSA","crit":["alg"]}`:          "crit JWS header must not list the registered header 'alg'",
+			`{"alg":"EdDSA","crit":["exp"]}`:          "critical JWS header 'exp' is not present",
+			`{"alg":"none","exp":1,"crit":["other"]}`: "critical JWS header 'other' is not present",
+		} {
+			_, err := ParseJWS(compactJWS(header), &testVerifier{}, UnderstoodCritical([]string{"exp"}),
+				AllowUnsecured(true))
+			require.EqualError(t, err, errMsg, header)
+		}
+	})
+
+	t.Run("general JWS", func(t *testing.T) {
+		signer := newEd25519TestSigner(t, "service")
+		otherSigner := newEd25519TestSigner(t, "other")
+
+		jws, err := NewJWSBuilder([]byte("payload")).
+			AddSigner(signer, nil, nil).
+			AddSigner(otherSigner, Headers{"exp": 1, "crit": []string{"exp"}}, nil).
+			Build()
+		require.NoError(t, err)
+
+		jwsJSON, err := jws.SerializeJSON(false)
+		require.NoError(t, err)
+
+		verifier := newEd25519TestVerifier(signer, otherSigner)
+
+		_, err = ParseGeneralJWS(jwsJSON, verifier)
+		require.ErrorIs(t, err, ErrUnknownCriticalHeader)
+
+		parsed, err := ParseGeneralJWS(jwsJSON, verifier, WithJWSRequiredSigners("service"))
+		require.NoError(t, err)
+		require.Len(t, parsed.Signatures, 1)
+
+		_, err = ParseGeneralJWS(jwsJSON, verifier, UnderstoodCritical([]string{"exp"}))
+		require.NoError(t, err)
+
+		jws, err = NewJWSBuilder([]byte("payload")).
+			AddSigner(signer, Headers{"exp": 1}, Headers{"crit": []string{"exp"}}).
+			Build()
+		require.NoError(t, err)
+
+		jwsJSON, err = jws.SerializeJSON(false)
+		require.NoError(t, err)
+
+		_, err = ParseGeneralJWS(jwsJSON, verifier, UnderstoodCritical([]string{"exp"}))
+		require.EqualError(t, err, "signature 0: crit JWS header must be protected")
+	})
+}
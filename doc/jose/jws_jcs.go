@@ -0,0 +1,78 @@
+/*
+Copyright Gen Digital Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package jose
+
+import (
+	"encoding/json"
+	"fmt"
+)
+
+// SignJSON signs the JSON Canonicalization Scheme (JCS, RFC 8785) serialization of obj and returns a compact JWS with
+// a detached payload. Any JSON serialization of obj (different member order, whitespace or number formatting) has
+// the same canonical form, so the JWS can be verified with VerifyJSON against obj as received by the verifier.
+func SignJSON(obj interface{}, signer Signer) (string, error) {
+	canonical, err := marshalCanonicalJSON(obj)
+	if err != nil {
+		return "", fmt.Errorf("signJSON: %w", err)
+	}
+
+	jws, err := NewJWS(nil, nil, canonical, signer)
+	if err != nil {
+		return "", fmt.Errorf("signJSON: %w", err)
+	}
+
+	compactJWS, err := jws.SerializeCompact(true)
+	if err != nil {
+		return "", fmt.Errorf("signJSON: %w", err)
+	}
+
+	return compactJWS, nil
+}
+
+// VerifyJSON verifies jws, a compact JWS with a detached payload created by SignJSON, against the JCS serialization
+// of obj.
+func VerifyJSON(jws string, obj interface{}, verifier SignatureVerifier) error {
+	canonical, err := marshalCanonicalJSON(obj)
+	if err != nil {
+		return fmt.Errorf("verifyJSON: %w", err)
+	}
+
+	_, err = ParseJWS(jws, verifier, WithJWSDetachedPayload(canonical))
+	if err != nil {
+		return fmt.Errorf("verifyJSON: %w", err)
+	}
+
+	return nil
+}
+
+// marshalCanonicalJSON returns the JCS serialization of obj. obj may be a json.RawMessage or []byte holding JSON
+// data, which is canonicalized as is.
+func marshalCanonicalJSON(obj interface{}) ([]byte, error) {
+	var (
+		data []byte
+		err  error
+	)
+
+	switch o := obj.(type) {
+	case json.RawMessage:
+		data = o
+	case []byte:
+		data = o
+	default:
+		data, err = json.Marshal(obj)
+		if err != nil {
+			return nil, fmt.Errorf("marshal JSON: %w", err)
+		}
+	}
+
+	canonical, err := canonicalizeJSON(data)
+	if err != nil {
+		return nil, fmt.Errorf("canonicalize JSON: %w", err)
+	}
+
+	return canonical, nil
+}
@@ -0,0 +1,144 @@
+/*
+Copyright Gen Digital Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package jose
+
+import (
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestCanonicalizeJSON(t *testing.T) {
+	t.Run("success", func(t *testing.T) {
+		tests := []struct {
+			name string
+			json string
+			want string
+		}{
+			{"literals", ` [ null , true , false , "" , [ ] , { } ] `, `[null,true,false,"",[],{}]`},
+			{"member order", `{"b": 1, "a": {"d": 2, "c": 3}, "": 0}`, `{"":0,"a":{"c":3,"d":2},"b":1}`},
+			{
+				// RFC 8785 section 3.2.3 sorting example, "\ud83d\ude00" sorts before "\ufb33" in UTF-16.
+				"UTF-16 member order",
+				`{"\u20ac": "Euro Sign", "\r": "Carriage Return", "\ufb33": "Hebrew Letter Dalet With Dagesh",
+				  "1": "One", "\ud83d\ude00": "Emoji: Grinning Face", "\u0080": "Control", "\u00f6": "Latin Small Letter O With Diaeresis"}`,
+				"{\"\\r\":\"Carriage Return\",\"1\":\"One\",\"\u0080\":\"Control\"," +
+					"\"\u00f6\":\"Latin Small Letter O With Diaeresis\",\"\u20ac\":\"Euro Sign\"," +
+					"\"\U0001F600\":\"Emoji: Grinning Face\",\"\ufb33\":\"Hebrew Letter Dalet With Dagesh\"}",
+			},
+			{
+				"numbers",
+				`[0, -0, 1, -1.5, 1E3, 1e21, 1e20, 1e-7, 0.000001, 333333333.3333333, 4.50, 2e-3, 0.1e1,
+				  1.7976931348623157e308, 5e-324, -5e-324, 9007199254740993]`,
+				`[0,0,1,-1.5,1000,1e+21,100000000000000000000,1e-7,0.000001,333333333.3333333,4.5,0.002,1,` +
+					`1.7976931348623157e+308,5e-324,-5e-324,9007199254740992]`,
+			},
+			{
+				"strings",
+				`["\u20ac$\u000F\u000aA'\u0042\u0022\u005c\\\"\/", "\u007f\u2028<>&"]`,
+				"[\"\u20ac$\\u000f\\nA'B\\\"\\\\\\\\\\\"/\",\"\u007f\u2028<>&\"]",
+			},
+		}
+
+		for _, tc := range tests {
+			canonical, err := canonicalizeJSON([]byte(tc.json))
+			require.NoError(t, err, tc.name)
+			require.Equal(t, tc.want, string(canonical), tc.name)
+		}
+	})
+
+	t.Run("failure", func(t *testing.T) {
+		for _, tc := range []struct {
+			json string
+			err  string
+		}{
+			{`{"a": 1, "b": 2, "a": 3}`, "duplicate object member name 'a'"},
+			{`{"a": {"b": 1, "b": 1}}`, "duplicate object member name 'b'"},
+			{`[1e400]`, "number 1e400 is not an IEEE 754 double"},
+			{`{"a": 1} {}`, "unexpected data after the JSON value"},
+			{`{"a": 1} x`, "unexpected data after the JSON value"},
+			{``, "read JSON: EOF"},
+			{`{"a": }`, "read JSON: "},
+			{`[1, 2`, "read JSON: "},
+		} {
+			_, err := canonicalizeJSON([]byte(tc.json))
+			require.ErrorContains(t, err, tc.err, tc.json)
+		}
+	})
+}
+
+type jcsTestSigner struct {
+	sign func([]byte) []byte
+}
+
+func (s jcsTestSigner) Sign(data []byte) ([]byte, error) {
+	return s.sign(data), nil
+}
+
+func (s jcsTestSigner) Headers() Headers {
+	return Headers{HeaderAlgorithm: "ES256", HeaderKeyID: "key-1"}
+}
+
+func TestSignJSON(t *testing.T) {
+	ecKey, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+
+	signer := jcsTestSigner{sign: ecdsaStreamTestSigner(t, ecKey, crypto.SHA256)}
+	verifier := NewKMSSigVerifier(mapPubKeyExporter{"key-1": ecKey}, nil)
+
+	type claims struct {
+		Subject string                 `json:"sub"`
+		Amount  float64                `json:"amount"`
+		Extra   map[string]interface{} `json:"extra"`
+	}
+
+	obj := &claims{Subject: "did:example:123", Amount: 1.5, Extra: map[string]interface{}{"z": true, "a": nil}}
+
+	compactJWS, err := SignJSON(obj, signer)
+	require.NoError(t, err)
+
+	parts := strings.Split(compactJWS, ".")
+	require.Len(t, parts, 3)
+	require.Empty(t, parts[1])
+
+	t.Run("verify", func(t *testing.T) {
+		require.NoError(t, VerifyJSON(compactJWS, obj, verifier))
+
+		// the same object serialized differently.
+		require.NoError(t, VerifyJSON(compactJWS,
+			[]byte(`{ "extra": {"a": null, "z": true}, "amount": 15e-1, "sub": "did:example:123" }`), verifier))
+
+		generic := map[string]interface{}{
+			"sub": "did:example:123", "amount": 1.5, "extra": map[string]interface{}{"z": true, "a": nil},
+		}
+		require.NoError(t, VerifyJSON(compactJWS, generic, verifier))
+	})
+
+	t.Run("tampered object", func(t *testing.T) {
+		tampered := *obj
+		tampered.Amount = 15
+
+		err = VerifyJSON(compactJWS, &tampered, verifier)
+		require.ErrorContains(t, err, "verifyJSON: ")
+	})
+
+	t.Run("invalid object", func(t *testing.T) {
+		_, err = SignJSON(make(chan int), signer)
+		require.ErrorContains(t, err, "signJSON: marshal JSON: ")
+
+		_, err = SignJSON([]byte(`{"a": 1, "a": 2}`), signer)
+		require.EqualError(t, err, "signJSON: canonicalize JSON: duplicate object member name 'a'")
+
+		err = VerifyJSON(compactJWS, []byte(`{`), verifier)
+		require.ErrorContains(t, err, "verifyJSON: canonicalize JSON: read JSON: ")
+	})
+}
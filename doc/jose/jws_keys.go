@@ -0,0 +1,102 @@
+/*
+Copyright Gen Digital Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package jose
+
+import (
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/rsa"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/dellekappa/kms-go/doc/jose/jwk"
+)
+
+// VerifyWithKeys verifies the signature of the compact serialized jws against each key of keys compatible with its
+// "alg" header, as needed when the JWS has no "kid" header to select the key with. A key is compatible if its "alg"
+// is empty or equal to the JWS "alg" and its public key type (and curve) matches the algorithm, see
+// NewStreamVerifier for the supported algorithms. Verification stops at the first key verifying the signature and
+// its kid is returned. If no key verifies the signature, the returned error aggregates the failure of each
+// compatible key. opts are applied to the StreamVerifier of each key.
+func VerifyWithKeys(jws string, keys []*jwk.JWK, opts ...StreamVerifierOpt) (string, error) {
+	parts := strings.Split(jws, ".")
+	if len(parts) != jwsPartsCount {
+		return "", errors.New("verifyWithKeys: invalid JWS compact format")
+	}
+
+	var matchedKID string
+
+	verifier := SignatureVerifierFunc(func(joseHeaders Headers, payload, _, signature []byte) error {
+		// ParseJWS ensures the alg header is set.
+		alg, _ := joseHeaders.Algorithm()
+
+		var errs []error
+
+		for _, key := range keys {
+			if key == nil || !isJWKCompatibleWithAlg(key, alg) {
+				continue
+			}
+
+			err := verifyWithKey(parts[jwsHeaderPart], payload, signature, key, opts...)
+			if err == nil {
+				matchedKID = key.KeyID
+
+				return nil
+			}
+
+			errs = append(errs, fmt.Errorf("key '%s': %w", key.KeyID, err))
+		}
+
+		if len(errs) == 0 {
+			return fmt.Errorf("no key compatible with alg '%s'", alg)
+		}
+
+		return errors.Join(errs...)
+	})
+
+	_, err := ParseJWS(jws, verifier)
+	if err != nil {
+		return "", fmt.Errorf("verifyWithKeys: %w", err)
+	}
+
+	return matchedKID, nil
+}
+
+func verifyWithKey(b64Headers string, payload, signature []byte, key *jwk.JWK, opts ...StreamVerifierOpt) error {
+	v, err := NewStreamVerifier(b64Headers, signature, key.Public(), opts...)
+	if err != nil {
+		return err
+	}
+
+	if _, err = v.Write(payload); err != nil {
+		return err
+	}
+
+	return v.Close()
+}
+
+func isJWKCompatibleWithAlg(key *jwk.JWK, alg string) bool {
+	if key.Algorithm != "" && key.Algorithm != alg {
+		return false
+	}
+
+	switch pubKey := key.Public().Key.(type) {
+	case ed25519.PublicKey:
+		return alg == eddsaAlg
+	case *ecdsa.PublicKey:
+		sAlg, ok := streamAlgs[alg]
+
+		return ok && sAlg.curve == pubKey.Curve
+	case *rsa.PublicKey:
+		sAlg, ok := streamAlgs[alg]
+
+		return ok && sAlg.curve == nil
+	default:
+		return false
+	}
+}
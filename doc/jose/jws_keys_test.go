@@ -0,0 +1,114 @@
+/*
+Copyright Gen Digital Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package jose
+
+import (
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/rsa"
+	"encoding/base64"
+	"testing"
+
+	"github.com/go-jose/go-jose/v3"
+	"github.com/stretchr/testify/require"
+
+	"github.com/dellekappa/kms-go/doc/jose/jwk"
+)
+
+func TestVerifyWithKeys(t *testing.T) {
+	payload := []byte("payload")
+
+	ecKey, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+
+	otherECKey, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+
+	ecKey384, err := ecdsa.GenerateKey(elliptic.P384(), rand.Reader)
+	require.NoError(t, err)
+
+	rsaKey, err := rsa.GenerateKey(rand.Reader, 2048)
+	require.NoError(t, err)
+
+	edPub, edPriv, err := ed25519.GenerateKey(rand.Reader)
+	require.NoError(t, err)
+
+	newJWK := func(kid, alg string, key interface{}) *jwk.JWK {
+		return &jwk.JWK{JSONWebKey: jose.JSONWebKey{KeyID: kid, Algorithm: alg, Key: key}}
+	}
+
+	compactJWS := func(alg string, sign func([]byte) []byte) string {
+		b64Headers, signature := signStreamTestJWS(t, Headers{HeaderAlgorithm: alg}, payload, true, sign)
+
+		return b64Headers + "." + base64.RawURLEncoding.EncodeToString(payload) + "." +
+			base64.RawURLEncoding.EncodeToString(signature)
+	}
+
+	es256JWS := compactJWS("ES256", ecdsaStreamTestSigner(t, ecKey, crypto.SHA256))
+
+	keys := []*jwk.JWK{
+		nil,
+		newJWK("ed", "", edPub),
+		newJWK("rsa", "", &rsaKey.PublicKey),
+		newJWK("ec384", "", &ecKey384.PublicKey),
+		newJWK("other-alg", "ES384", &ecKey.PublicKey),
+		newJWK("other", "", &otherECKey.PublicKey),
+		newJWK("ec", "ES256", ecKey),
+		newJWK("ec-duplicate", "", &ecKey.PublicKey),
+	}
+
+	t.Run("success", func(t *testing.T) {
+		kid, err := VerifyWithKeys(es256JWS, keys)
+		require.NoError(t, err)
+		require.Equal(t, "ec", kid)
+
+		kid, err = VerifyWithKeys(compactJWS("EdDSA", func(sInput []byte) []byte {
+			return ed25519.Sign(edPriv, sInput)
+		}), keys)
+		require.NoError(t, err)
+		require.Equal(t, "ed", kid)
+
+		kid, err = VerifyWithKeys(compactJWS("PS256", rsaStreamTestSigner(t, rsaKey, crypto.SHA256, true)), keys)
+		require.NoError(t, err)
+		require.Equal(t, "rsa", kid)
+	})
+
+	t.Run("error no key verifies the signature", func(t *testing.T) {
+		_, err := VerifyWithKeys(es256JWS, []*jwk.JWK{
+			newJWK("other", "", &otherECKey.PublicKey),
+			newJWK("other-2", "ES256", &otherECKey.PublicKey),
+		})
+		require.EqualError(t, err, "verifyWithKeys: key 'other': verify JWS signature: invalid signature\n"+
+			"key 'other-2': verify JWS signature: invalid signature")
+	})
+
+	t.Run("error no compatible key", func(t *testing.T) {
+		_, err := VerifyWithKeys(es256JWS, []*jwk.JWK{
+			newJWK("ed", "", edPub),
+			newJWK("rsa", "", &rsaKey.PublicKey),
+			newJWK("ec384", "", &ecKey384.PublicKey),
+			newJWK("other-alg", "ES384", &ecKey.PublicKey),
+		})
+		require.EqualError(t, err, "verifyWithKeys: no key compatible with alg 'ES256'")
+
+		_, err = VerifyWithKeys(es256JWS, nil)
+		require.EqualError(t, err, "verifyWithKeys: no key compatible with alg 'ES256'")
+	})
+
+	t.Run("error invalid JWS", func(t *testing.T) {
+		_, err := VerifyWithKeys("not a jws", keys)
+		require.EqualError(t, err, "verifyWithKeys: invalid JWS compact format")
+
+		b64Headers := base64.RawURLEncoding.EncodeToString([]byte(`{"typ":"JWT"}`))
+
+		_, err = VerifyWithKeys(b64Headers+".cGF5bG9hZA.c2ln", keys)
+		require.EqualError(t, err, "verifyWithKeys: alg JWS header is not defined")
+	})
+}
@@ -0,0 +1,110 @@
+/*
+Copyright Gen Digital Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package jose
+
+import (
+	"bytes"
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/rsa"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/dellekappa/kms-go/doc/jose/jwk"
+)
+
+// ErrJWKAlgMismatch is returned when the key resolved for the "kid" of a JWS can't be used with its "alg", like an RSA
+// key with HS256, to prevent algorithm confusion attacks.
+var ErrJWKAlgMismatch = errors.New("resolved key does not match JWS alg")
+
+// JWKResolver resolves the "kid" JOSE header of a JWS into the public key verifying its signature.
+type JWKResolver func(kid string) (*jwk.JWK, error)
+
+// NewKIDSigVerifier creates a SignatureVerifier verifying a JWS signature with the key resolver returns for its "kid"
+// header. The resolved key type ("kty") and "alg", if set, are cross-checked against the JWS "alg" header before
+// verification and ErrJWKAlgMismatch is returned if they don't match. See NewStreamVerifier for the supported
+// algorithms, opts are applied to the StreamVerifier of the resolved key.
+func NewKIDSigVerifier(resolver JWKResolver, opts ...StreamVerifierOpt) SignatureVerifier {
+	return SignatureVerifierFunc(func(joseHeaders Headers, payload, signingInput, signature []byte) error {
+		alg, _ := joseHeaders.Algorithm()
+
+		kid, ok := joseHeaders.KeyID()
+		if !ok {
+			return errors.New("kidSigVerifier: 'kid' JOSE header is not present")
+		}
+
+		key, err := resolver(kid)
+		if err != nil {
+			return fmt.Errorf("kidSigVerifier: resolve kid '%s': %w", kid, err)
+		}
+
+		err = checkJWKAlg(key, alg)
+		if err != nil {
+			return fmt.Errorf("kidSigVerifier: kid '%s': %w", kid, err)
+		}
+
+		// the signing input starts with the base64url encoded protected headers, followed by '.'.
+		b64Headers, _, _ := bytes.Cut(signingInput, []byte("."))
+
+		err = verifyWithKey(string(b64Headers), payload, signature, key, opts...)
+		if err != nil {
+			return fmt.Errorf("kidSigVerifier: kid '%s': %w", kid, err)
+		}
+
+		return nil
+	})
+}
+
+// checkJWKAlg checks key is usable with the JWS alg: its "alg", if set, must be alg and its "kty" must be the key type
+// of the alg family.
+func checkJWKAlg(key *jwk.JWK, alg string) error {
+	if key == nil {
+		return errors.New("resolved key is nil")
+	}
+
+	if key.Algorithm != "" && key.Algorithm != alg {
+		return fmt.Errorf("%w: key alg '%s', JWS alg '%s'", ErrJWKAlgMismatch, key.Algorithm, alg)
+	}
+
+	var kty string
+
+	switch {
+	case strings.HasPrefix(alg, "HS"):
+		kty = "oct"
+	case strings.HasPrefix(alg, "RS"), strings.HasPrefix(alg, "PS"):
+		kty = "RSA"
+	case strings.HasPrefix(alg, "ES"):
+		kty = "EC"
+	case alg == eddsaAlg:
+		kty = "OKP"
+	default:
+		return fmt.Errorf("unsupported algorithm '%s'", alg)
+	}
+
+	if keyKty := jwkKty(key); keyKty != kty {
+		return fmt.Errorf("%w: kty '%s', JWS alg '%s' requires kty '%s'", ErrJWKAlgMismatch, keyKty, alg, kty)
+	}
+
+	return nil
+}
+
+// jwkKty returns the "kty" of key, derived from its key material as the Kty field is only set for unmarshalled JWKs.
+func jwkKty(key *jwk.JWK) string {
+	switch key.Key.(type) {
+	case *rsa.PublicKey, *rsa.PrivateKey:
+		return "RSA"
+	case *ecdsa.PublicKey, *ecdsa.PrivateKey:
+		return "EC"
+	case ed25519.PublicKey, ed25519.PrivateKey:
+		return "OKP"
+	case []byte:
+		return "oct"
+	default:
+		return key.Kty
+	}
+}
@@ -0,0 +1,106 @@
+/*
+Copyright Gen Digital Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package jose
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/dellekappa/kms-go/doc/jose/jwk"
+	"github.com/dellekappa/kms-go/doc/jose/jwk/jwksupport"
+	"github.com/dellekappa/kms-go/doc/util/jwkkid"
+	"github.com/dellekappa/kms-go/spi/kms"
+)
+
+// ErrUnknownKID is returned by a KIDMapper for a kid it can't map to a KMS key ID.
+var ErrUnknownKID = errors.New("unknown kid")
+
+// KIDMapper maps the "kid" header of a JWS, e.g. a JWK thumbprint, to the ID the KMS stores the key under, as the KMS
+// may assign storage IDs unrelated to the kids of the JWS.
+type KIDMapper interface {
+	KMSKeyID(kid string) (string, error)
+}
+
+// PubKeyExporter exports the public keys of the keys kept in a KMS, see kms.KeyManager.
+type PubKeyExporter interface {
+	ExportPubKeyBytes(keyID string) ([]byte, kms.KeyType, error)
+}
+
+// KIDMap is a KIDMapper looking up the KMS key IDs of kids in a map.
+type KIDMap map[string]string
+
+// KMSKeyID returns the KMS key ID of kid, or ErrUnknownKID if kid is not in m.
+func (m KIDMap) KMSKeyID(kid string) (string, error) {
+	keyID, ok := m[kid]
+	if !ok {
+		return "", fmt.Errorf("%w '%s'", ErrUnknownKID, kid)
+	}
+
+	return keyID, nil
+}
+
+// ThumbprintKIDMapper is a KIDMapper for JWS kids set to the JWK thumbprint of the key (RFC 7638, as computed by
+// jwkkid.CreateKID). It derives the thumbprint of each of the KMS keys KeyIDs on the fly, exporting their public keys
+// from KMS, and returns the ID of the key matching the kid. Lookups cost one export per key, KIDMap should be preferred
+// for large sets of keys.
+type ThumbprintKIDMapper struct {
+	KMS    PubKeyExporter
+	KeyIDs []string
+}
+
+// KMSKeyID returns the ID of the KMS key which thumbprint is kid, or ErrUnknownKID if none matches.
+func (m *ThumbprintKIDMapper) KMSKeyID(kid string) (string, error) {
+	for _, keyID := range m.KeyIDs {
+		pubBytes, keyType, err := m.KMS.ExportPubKeyBytes(keyID)
+		if err != nil {
+			return "", fmt.Errorf("export public key '%s': %w", keyID, err)
+		}
+
+		thumbprint, err := jwkkid.CreateKID(pubBytes, keyType)
+		if err != nil {
+			return "", fmt.Errorf("thumbprint of key '%s': %w", keyID, err)
+		}
+
+		if thumbprint == kid {
+			return keyID, nil
+		}
+	}
+
+	return "", fmt.Errorf("%w '%s'", ErrUnknownKID, kid)
+}
+
+// NewKMSSigVerifier creates a SignatureVerifier verifying a JWS signature with the public key km exports for its
+// "kid" header, mapped to the KMS key ID by mapper. A nil mapper uses the kid as the KMS key ID. The key is checked
+// against the JWS "alg" and opts are applied as for NewKIDSigVerifier.
+func NewKMSSigVerifier(km PubKeyExporter, mapper KIDMapper, opts ...StreamVerifierOpt) SignatureVerifier {
+	return NewKIDSigVerifier(func(kid string) (*jwk.JWK, error) {
+		keyID := kid
+
+		if mapper != nil {
+			var err error
+
+			keyID, err = mapper.KMSKeyID(kid)
+			if err != nil {
+				return nil, err
+			}
+		}
+
+		pubBytes, keyType, err := km.ExportPubKeyBytes(keyID)
+		if err != nil {
+			return nil, fmt.Errorf("export public key '%s': %w", keyID, err)
+		}
+
+		key, err := jwksupport.PubKeyBytesToJWK(pubBytes, keyType)
+		if err != nil {
+			return nil, err
+		}
+
+		key.KeyID = kid
+
+		return key, nil
+	}, opts...)
+}
@@ -0,0 +1,93 @@
+/*
+Copyright Gen Digital Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package jose
+
+import (
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"encoding/base64"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/dellekappa/kms-go/doc/util/jwkkid"
+	"github.com/dellekappa/kms-go/spi/kms"
+)
+
+// mapPubKeyExporter exports the public keys of a map of KMS key IDs to P-256 keys.
+type mapPubKeyExporter map[string]*ecdsa.PrivateKey
+
+func (m mapPubKeyExporter) ExportPubKeyBytes(keyID string) ([]byte, kms.KeyType, error) {
+	key, ok := m[keyID]
+	if !ok {
+		return nil, "", errors.New("key not found")
+	}
+
+	return elliptic.Marshal(key.Curve, key.X, key.Y), kms.ECDSAP256TypeIEEEP1363, nil //nolint:staticcheck
+}
+
+func TestNewKMSSigVerifier(t *testing.T) {
+	payload := []byte("payload")
+
+	ecKey, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+
+	otherKey, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+
+	km := mapPubKeyExporter{"storage-1": otherKey, "storage-2": ecKey}
+
+	thumbprint, err := jwkkid.CreateKID(elliptic.Marshal(ecKey.Curve, ecKey.X, ecKey.Y), //nolint:staticcheck
+		kms.ECDSAP256TypeIEEEP1363)
+	require.NoError(t, err)
+
+	compactJWS := func(kid string) string {
+		b64Headers, signature := signStreamTestJWS(t, Headers{HeaderAlgorithm: "ES256", HeaderKeyID: kid}, payload,
+			true, ecdsaStreamTestSigner(t, ecKey, crypto.SHA256))
+
+		return b64Headers + "." + base64.RawURLEncoding.EncodeToString(payload) + "." +
+			base64.RawURLEncoding.EncodeToString(signature)
+	}
+
+	t.Run("success", func(t *testing.T) {
+		for name, mapper := range map[string]KIDMapper{
+			"KIDMap":              KIDMap{thumbprint: "storage-2"},
+			"ThumbprintKIDMapper": &ThumbprintKIDMapper{KMS: km, KeyIDs: []string{"storage-1", "storage-2"}},
+		} {
+			verifier := NewKMSSigVerifier(km, mapper)
+
+			parsed, e := ParseJWS(compactJWS(thumbprint), verifier)
+			require.NoError(t, e, name)
+			require.Equal(t, payload, parsed.Payload)
+		}
+
+		parsed, e := ParseJWS(compactJWS("storage-2"), NewKMSSigVerifier(km, nil))
+		require.NoError(t, e)
+		require.Equal(t, payload, parsed.Payload)
+	})
+
+	t.Run("failure", func(t *testing.T) {
+		_, e := ParseJWS(compactJWS(thumbprint), NewKMSSigVerifier(km, KIDMap{}))
+		require.ErrorIs(t, e, ErrUnknownKID)
+
+		_, e = ParseJWS(compactJWS(thumbprint), NewKMSSigVerifier(km,
+			&ThumbprintKIDMapper{KMS: km, KeyIDs: []string{"storage-1"}}))
+		require.ErrorIs(t, e, ErrUnknownKID)
+
+		_, e = ParseJWS(compactJWS(thumbprint), NewKMSSigVerifier(km,
+			&ThumbprintKIDMapper{KMS: km, KeyIDs: []string{"unknown"}}))
+		require.EqualError(t, e, "kidSigVerifier: resolve kid '"+thumbprint+"': export public key 'unknown': key "+
+			"not found")
+
+		// the kid maps to a key which did not sign the JWS.
+		_, e = ParseJWS(compactJWS(thumbprint), NewKMSSigVerifier(km, KIDMap{thumbprint: "storage-1"}))
+		require.ErrorContains(t, e, "invalid signature")
+	})
+}
@@ -0,0 +1,252 @@
+/*
+Copyright Gen Digital Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package jose
+
+import (
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
+	"encoding/base64"
+	"errors"
+	"math/big"
+	"testing"
+
+	"github.com/btcsuite/btcd/btcec/v2"
+	"github.com/go-jose/go-jose/v3"
+	"github.com/stretchr/testify/require"
+
+	secp256k1subtle "github.com/dellekappa/kms-go/crypto/tinkcrypto/primitive/secp256k1/subtle"
+	"github.com/dellekappa/kms-go/doc/jose/jwk"
+)
+
+func TestNewKIDSigVerifier(t *testing.T) {
+	payload := []byte("payload")
+
+	ecKey, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+
+	rsaKey, err := rsa.GenerateKey(rand.Reader, 2048)
+	require.NoError(t, err)
+
+	keys := map[string]*jwk.JWK{
+		"ec":        {JSONWebKey: jose.JSONWebKey{KeyID: "ec", Key: &ecKey.PublicKey}},
+		"rsa":       {JSONWebKey: jose.JSONWebKey{KeyID: "rsa", Key: &rsaKey.PublicKey}},
+		"rsa-ps256": {JSONWebKey: jose.JSONWebKey{KeyID: "rsa-ps256", Algorithm: "PS256", Key: &rsaKey.PublicKey}},
+	}
+
+	verifier := NewKIDSigVerifier(func(kid string) (*jwk.JWK, error) {
+		key, ok := keys[kid]
+		if !ok {
+			return nil, errors.New("not found")
+		}
+
+		return key, nil
+	})
+
+	compactJWS := func(headers Headers, sign func([]byte) []byte) string {
+		b64Headers, signature := signStreamTestJWS(t, headers, payload, true, sign)
+
+		return b64Headers + "." + base64.RawURLEncoding.EncodeToString(payload) + "." +
+			base64.RawURLEncoding.EncodeToString(signature)
+	}
+
+	t.Run("success", func(t *testing.T) {
+		tests := []struct {
+			name string
+			jws  string
+		}{
+			{
+				name: "ES256",
+				jws: compactJWS(Headers{HeaderAlgorithm: "ES256", HeaderKeyID: "ec"},
+					ecdsaStreamTestSigner(t, ecKey, crypto.SHA256)),
+			},
+			{
+				name: "RS256",
+				jws: compactJWS(Headers{HeaderAlgorithm: "RS256", HeaderKeyID: "rsa"},
+					rsaStreamTestSigner(t, rsaKey, crypto.SHA256, false)),
+			},
+			{
+				name: "PS256 with key alg",
+				jws: compactJWS(Headers{HeaderAlgorithm: "PS256", HeaderKeyID: "rsa-ps256"},
+					rsaStreamTestSigner(t, rsaKey, crypto.SHA256, true)),
+			},
+		}
+
+		for _, tc := range tests {
+			t.Run(tc.name, func(t *testing.T) {
+				parsed, err := ParseJWS(tc.jws, verifier)
+				require.NoError(t, err)
+				require.Equal(t, payload, parsed.Payload)
+			})
+		}
+	})
+
+	t.Run("algorithm confusion", func(t *testing.T) {
+		// HMAC keyed with the RSA public key, as an attacker knowing only the public key would sign.
+		hs256Signer := func(sInput []byte) []byte {
+			mac := hmac.New(sha256.New, rsaKey.PublicKey.N.Bytes())
+			mac.Write(sInput)
+
+			return mac.Sum(nil)
+		}
+
+		tests := []struct {
+			name string
+			jws  string
+			err  string
+		}{
+			{
+				name: "HS256 with RSA key",
+				jws:  compactJWS(Headers{HeaderAlgorithm: "HS256", HeaderKeyID: "rsa"}, hs256Signer),
+				err:  "kty 'RSA', JWS alg 'HS256' requires kty 'oct'",
+			},
+			{
+				name: "ES256 with RSA key",
+				jws: compactJWS(Headers{HeaderAlgorithm: "ES256", HeaderKeyID: "rsa"},
+					ecdsaStreamTestSigner(t, ecKey, crypto.SHA256)),
+				err: "kty 'RSA', JWS alg 'ES256' requires kty 'EC'",
+			},
+			{
+				name: "RS256 with EC key",
+				jws: compactJWS(Headers{HeaderAlgorithm: "RS256", HeaderKeyID: "ec"},
+					rsaStreamTestSigner(t, rsaKey, crypto.SHA256, false)),
+				err: "kty 'EC', JWS alg 'RS256' requires kty 'RSA'",
+			},
+			{
+				name: "RS256 with PS256 key",
+				jws: compactJWS(Headers{HeaderAlgorithm: "RS256", HeaderKeyID: "rsa-ps256"},
+					rsaStreamTestSigner(t, rsaKey, crypto.SHA256, false)),
+				err: "key alg 'PS256', JWS alg 'RS256'",
+			},
+		}
+
+		for _, tc := range tests {
+			t.Run(tc.name, func(t *testing.T) {
+				parsed, err := ParseJWS(tc.jws, verifier)
+				require.ErrorIs(t, err, ErrJWKAlgMismatch)
+				require.Contains(t, err.Error(), tc.err)
+				require.Nil(t, parsed)
+			})
+		}
+	})
+
+	t.Run("failure", func(t *testing.T) {
+		es256Signer := ecdsaStreamTestSigner(t, ecKey, crypto.SHA256)
+
+		tests := []struct {
+			name string
+			jws  string
+			err  string
+		}{
+			{
+				name: "missing kid",
+				jws:  compactJWS(Headers{HeaderAlgorithm: "ES256"}, es256Signer),
+				err:  "kidSigVerifier: 'kid' JOSE header is not present",
+			},
+			{
+				name: "unresolved kid",
+				jws:  compactJWS(Headers{HeaderAlgorithm: "ES256", HeaderKeyID: "unknown"}, es256Signer),
+				err:  "kidSigVerifier: resolve kid 'unknown': not found",
+			},
+			{
+				name: "unsupported alg",
+				jws:  compactJWS(Headers{HeaderAlgorithm: "XS256", HeaderKeyID: "ec"}, es256Signer),
+				err:  "kidSigVerifier: kid 'ec': unsupported algorithm 'XS256'",
+			},
+			{
+				name: "invalid signature",
+				jws: compactJWS(Headers{HeaderAlgorithm: "ES256", HeaderKeyID: "ec"}, func([]byte) []byte {
+					return make([]byte, 64)
+				}),
+				err: "kidSigVerifier: kid 'ec': verify JWS signature: invalid signature",
+			},
+		}
+
+		for _, tc := range tests {
+			t.Run(tc.name, func(t *testing.T) {
+				parsed, err := ParseJWS(tc.jws, verifier)
+				require.EqualError(t, err, tc.err)
+				require.Nil(t, parsed)
+			})
+		}
+	})
+
+	t.Run("nil key", func(t *testing.T) {
+		nilVerifier := NewKIDSigVerifier(func(string) (*jwk.JWK, error) {
+			return nil, nil
+		})
+
+		_, err := ParseJWS(compactJWS(Headers{HeaderAlgorithm: "ES256", HeaderKeyID: "ec"},
+			ecdsaStreamTestSigner(t, ecKey, crypto.SHA256)), nilVerifier)
+		require.EqualError(t, err, "kidSigVerifier: kid 'ec': resolved key is nil")
+	})
+}
+
+func TestNewKIDSigVerifierEnforceLowS(t *testing.T) {
+	payload := []byte("payload")
+
+	key, err := ecdsa.GenerateKey(btcec.S256(), rand.Reader)
+	require.NoError(t, err)
+
+	resolver := func(kid string) (*jwk.JWK, error) {
+		return &jwk.JWK{JSONWebKey: jose.JSONWebKey{KeyID: kid, Key: &key.PublicKey}}, nil
+	}
+
+	n := key.Curve.Params().N
+
+	// lowS and highS sign with the low-S and the high-S (r, n - s) forms of the same signature.
+	lowS := func(sInput []byte) []byte {
+		digest := sha256.Sum256(sInput)
+
+		r, s, err := ecdsa.Sign(rand.Reader, key, digest[:])
+		require.NoError(t, err)
+
+		if s.Cmp(new(big.Int).Rsh(n, 1)) > 0 {
+			s.Sub(n, s)
+		}
+
+		return append(r.FillBytes(make([]byte, 32)), s.FillBytes(make([]byte, 32))...)
+	}
+
+	highS := func(sInput []byte) []byte {
+		sig := lowS(sInput)
+		s := new(big.Int).Sub(n, new(big.Int).SetBytes(sig[32:]))
+
+		return append(sig[:32], s.FillBytes(make([]byte, 32))...)
+	}
+
+	compactJWS := func(sign func([]byte) []byte) string {
+		b64Headers, signature := signStreamTestJWS(t, Headers{HeaderAlgorithm: "ES256K", HeaderKeyID: "k1"}, payload,
+			true, sign)
+
+		return b64Headers + "." + base64.RawURLEncoding.EncodeToString(payload) + "." +
+			base64.RawURLEncoding.EncodeToString(signature)
+	}
+
+	t.Run("low-S ES256K signature", func(t *testing.T) {
+		parsed, err := ParseJWS(compactJWS(lowS), NewKIDSigVerifier(resolver))
+		require.NoError(t, err)
+		require.Equal(t, payload, parsed.Payload)
+	})
+
+	t.Run("high-S ES256K signature is rejected by default", func(t *testing.T) {
+		parsed, err := ParseJWS(compactJWS(highS), NewKIDSigVerifier(resolver))
+		require.ErrorIs(t, err, secp256k1subtle.ErrNonCanonicalSignature)
+		require.Nil(t, parsed)
+	})
+
+	t.Run("high-S ES256K signature with EnforceLowS(false)", func(t *testing.T) {
+		parsed, err := ParseJWS(compactJWS(highS),
+			NewKIDSigVerifier(resolver, WithStreamVerifierEnforceLowS(false)))
+		require.NoError(t, err)
+		require.Equal(t, payload, parsed.Payload)
+	})
+}
@@ -0,0 +1,121 @@
+/*
+Copyright Gen Digital Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package jose
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/dellekappa/kms-go/crypto/tinkcrypto"
+	"github.com/dellekappa/kms-go/doc/jose/jwk"
+	cryptoapi "github.com/dellekappa/kms-go/spi/crypto"
+)
+
+// nestedJWTContentType is the "cty" header of a JWE whose plaintext is a JWS (RFC 7519 section 5.2).
+const nestedJWTContentType = "JWT"
+
+// SignThenEncrypt signs payload with signer and encrypts the compact serialized JWS to recipient, as a nested JWT
+// (RFC 7519 section 11.2). The JWE is compact serialized, its "cty" header is "JWT", enc its content encryption
+// algorithm and its CEK is wrapped with ECDH-ES key wrapping. recipient is an EC public key JWK on P-256, P-384 or
+// P-521 or an X25519 public key JWK, Ed25519 keys are not converted and are rejected.
+func SignThenEncrypt(payload []byte, signer Signer, recipient *jwk.JWK, enc string) ([]byte, error) {
+	recPubKey, err := recipientAgreementKey(recipient)
+	if err != nil {
+		return nil, fmt.Errorf("signThenEncrypt: %w", err)
+	}
+
+	recPubKey.KID = recipient.KeyID
+
+	jws, err := NewJWS(nil, nil, payload, signer)
+	if err != nil {
+		return nil, fmt.Errorf("signThenEncrypt: %w", err)
+	}
+
+	compactJWS, err := jws.SerializeCompact(false)
+	if err != nil {
+		return nil, fmt.Errorf("signThenEncrypt: %w", err)
+	}
+
+	c, err := tinkcrypto.New()
+	if err != nil {
+		return nil, fmt.Errorf("signThenEncrypt: %w", err)
+	}
+
+	jweEncrypter, err := NewJWEEncrypt(EncAlg(enc), "", nestedJWTContentType, "", nil,
+		[]*cryptoapi.PublicKey{recPubKey}, c)
+	if err != nil {
+		return nil, fmt.Errorf("signThenEncrypt: %w", err)
+	}
+
+	jwe, err := jweEncrypter.Encrypt([]byte(compactJWS))
+	if err != nil {
+		return nil, fmt.Errorf("signThenEncrypt: %w", err)
+	}
+
+	serializedJWE, err := jwe.CompactSerialize(json.Marshal)
+	if err != nil {
+		return nil, fmt.Errorf("signThenEncrypt: %w", err)
+	}
+
+	return []byte(serializedJWE), nil
+}
+
+// DecryptThenVerify decrypts data, a JWE created by SignThenEncrypt, with recipientPriv and verifies the nested JWS
+// with verifier. The JWS payload is returned only if both succeed. The JWE "cty" header must be "JWT".
+//
+// recipientPriv is an EC private key JWK on P-256, P-384 or P-521 or an X25519 JWK whose Key is its 32 bytes private
+// key (as for Reencrypt). With multiple recipients, its kid selects the recipient to decrypt as.
+func DecryptThenVerify(data []byte, recipientPriv *jwk.JWK, verifier SignatureVerifier) ([]byte, error) {
+	if verifier == nil {
+		return nil, errors.New("decryptThenVerify: verifier is required")
+	}
+
+	parsedJWE, err := Deserialize(string(data))
+	if err != nil {
+		return nil, fmt.Errorf("decryptThenVerify: %w", err)
+	}
+
+	cty, _ := parsedJWE.ProtectedHeaders.ContentType()
+	if !strings.EqualFold(cty, nestedJWTContentType) {
+		return nil, fmt.Errorf("decryptThenVerify: cty header '%s' is not '%s'", cty, nestedJWTContentType)
+	}
+
+	err = checkOurRecipient(parsedJWE, recipientPriv)
+	if err != nil {
+		return nil, fmt.Errorf("decryptThenVerify: %w", err)
+	}
+
+	recKH, err := recipientKeyHandle(recipientPriv)
+	if err != nil {
+		return nil, fmt.Errorf("decryptThenVerify: %w", err)
+	}
+
+	c, err := tinkcrypto.New()
+	if err != nil {
+		return nil, fmt.Errorf("decryptThenVerify: %w", err)
+	}
+
+	recKM := &recipientKeyManager{kh: recKH}
+
+	if len(parsedJWE.Recipients) > 1 {
+		recKM.kid = recipientPriv.KeyID
+	}
+
+	plaintext, err := NewJWEDecrypt(nil, c, recKM).Decrypt(parsedJWE)
+	if err != nil {
+		return nil, fmt.Errorf("decryptThenVerify: %w", err)
+	}
+
+	jws, err := ParseJWS(string(plaintext), verifier)
+	if err != nil {
+		return nil, fmt.Errorf("decryptThenVerify: %w", err)
+	}
+
+	return jws.Payload, nil
+}
@@ -0,0 +1,147 @@
+/*
+Copyright Gen Digital Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package jose
+
+import (
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/elliptic"
+	"crypto/rand"
+	"encoding/json"
+	"errors"
+	"testing"
+
+	"github.com/go-jose/go-jose/v3"
+	"github.com/stretchr/testify/require"
+	"golang.org/x/crypto/curve25519"
+
+	"github.com/dellekappa/kms-go/crypto/tinkcrypto"
+	"github.com/dellekappa/kms-go/doc/jose/jwk"
+	cryptoapi "github.com/dellekappa/kms-go/spi/crypto"
+)
+
+func TestSignThenEncrypt(t *testing.T) {
+	signingKey, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+
+	signer := jcsTestSigner{sign: ecdsaStreamTestSigner(t, signingKey, crypto.SHA256)}
+	verifier := NewKMSSigVerifier(mapPubKeyExporter{"key-1": signingKey}, nil)
+
+	recPrivKey, err := ecdsa.GenerateKey(elliptic.P384(), rand.Reader)
+	require.NoError(t, err)
+
+	recPriv := &jwk.JWK{JSONWebKey: jose.JSONWebKey{Key: recPrivKey, KeyID: "rec-1"}, Kty: "EC", Crv: "P-384"}
+	recPub := &jwk.JWK{JSONWebKey: jose.JSONWebKey{Key: &recPrivKey.PublicKey, KeyID: "rec-1"}, Kty: "EC",
+		Crv: "P-384"}
+
+	payload := []byte(`{"sub":"did:example:123"}`)
+
+	t.Run("EC recipient", func(t *testing.T) {
+		nested, err := SignThenEncrypt(payload, signer, recPub, A256GCMALG)
+		require.NoError(t, err)
+
+		parsedJWE, err := Deserialize(string(nested))
+		require.NoError(t, err)
+
+		cty, _ := parsedJWE.ProtectedHeaders.ContentType()
+		require.Equal(t, "JWT", cty)
+
+		enc, _ := parsedJWE.ProtectedHeaders.Encryption()
+		require.Equal(t, A256GCMALG, enc)
+
+		pt, err := DecryptThenVerify(nested, recPriv, verifier)
+		require.NoError(t, err)
+		require.Equal(t, payload, pt)
+	})
+
+	t.Run("X25519 recipient", func(t *testing.T) {
+		privKey := make([]byte, curve25519.ScalarSize)
+		_, err := rand.Read(privKey)
+		require.NoError(t, err)
+
+		pubKey, err := curve25519.X25519(privKey, curve25519.Basepoint)
+		require.NoError(t, err)
+
+		x25519Priv := &jwk.JWK{JSONWebKey: jose.JSONWebKey{Key: privKey}, Kty: "OKP", Crv: "X25519"}
+		x25519Pub := &jwk.JWK{JSONWebKey: jose.JSONWebKey{Key: pubKey}, Kty: "OKP", Crv: "X25519"}
+
+		nested, err := SignThenEncrypt(payload, signer, x25519Pub, XC20PALG)
+		require.NoError(t, err)
+
+		pt, err := DecryptThenVerify(nested, x25519Priv, verifier)
+		require.NoError(t, err)
+		require.Equal(t, payload, pt)
+	})
+
+	t.Run("sign then encrypt failures", func(t *testing.T) {
+		_, err := SignThenEncrypt(payload, signer, nil, A256GCMALG)
+		require.EqualError(t, err, "signThenEncrypt: recipient key is required")
+
+		edPub, _, err := ed25519.GenerateKey(rand.Reader)
+		require.NoError(t, err)
+
+		_, err = SignThenEncrypt(payload, signer,
+			&jwk.JWK{JSONWebKey: jose.JSONWebKey{Key: edPub}, Kty: "OKP", Crv: "Ed25519"}, A256GCMALG)
+		require.EqualError(t, err, "signThenEncrypt: unsupported recipient key type 'OKP' and curve 'Ed25519'")
+
+		_, err = SignThenEncrypt(payload, signer, recPub, "A512GCM")
+		require.EqualError(t, err, "signThenEncrypt: encryption algorithm 'A512GCM' not supported")
+
+		failingSigner := &testSigner{headers: Headers{HeaderAlgorithm: "ES256"}, err: errors.New("sign error")}
+
+		_, err = SignThenEncrypt(payload, failingSigner, recPub, A256GCMALG)
+		require.ErrorContains(t, err, "signThenEncrypt: ")
+		require.ErrorContains(t, err, "sign error")
+	})
+
+	t.Run("decrypt then verify failures", func(t *testing.T) {
+		nested, err := SignThenEncrypt(payload, signer, recPub, A256GCMALG)
+		require.NoError(t, err)
+
+		_, err = DecryptThenVerify(nested, recPriv, nil)
+		require.EqualError(t, err, "decryptThenVerify: verifier is required")
+
+		_, err = DecryptThenVerify([]byte("not a JWE"), recPriv, verifier)
+		require.ErrorContains(t, err, "decryptThenVerify: ")
+
+		_, err = DecryptThenVerify(nested, nil, verifier)
+		require.EqualError(t, err, "decryptThenVerify: our private key is required")
+
+		otherKey, err := ecdsa.GenerateKey(elliptic.P384(), rand.Reader)
+		require.NoError(t, err)
+
+		_, err = DecryptThenVerify(nested,
+			&jwk.JWK{JSONWebKey: jose.JSONWebKey{Key: otherKey}, Kty: "EC", Crv: "P-384"}, verifier)
+		require.ErrorContains(t, err, "decryptThenVerify: ")
+
+		// the signature is not trusted, the payload is not returned.
+		pt, err := DecryptThenVerify(nested, recPriv, &testVerifier{err: errors.New("bad signature")})
+		require.ErrorContains(t, err, "bad signature")
+		require.Nil(t, pt)
+	})
+
+	t.Run("JWE without the JWT content type", func(t *testing.T) {
+		recPubKey, err := recipientAgreementKey(recPub)
+		require.NoError(t, err)
+
+		c, err := tinkcrypto.New()
+		require.NoError(t, err)
+
+		jweEncrypter, err := NewJWEEncrypt(A256GCM, "", "", "", nil, []*cryptoapi.PublicKey{recPubKey}, c)
+		require.NoError(t, err)
+
+		jwe, err := jweEncrypter.Encrypt(payload)
+		require.NoError(t, err)
+
+		serializedJWE, err := jwe.CompactSerialize(json.Marshal)
+		require.NoError(t, err)
+
+		_, err = DecryptThenVerify([]byte(serializedJWE), recPriv, verifier)
+		require.EqualError(t, err, "decryptThenVerify: cty header '' is not 'JWT'")
+	})
+}
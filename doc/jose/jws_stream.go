@@ -0,0 +1,322 @@
+/*
+Copyright Gen Digital Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package jose
+
+import (
+	"bytes"
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/elliptic"
+	"crypto/rsa"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"hash"
+	"io"
+	"math/big"
+
+	"github.com/btcsuite/btcd/btcec/v2"
+
+	secp256k1subtle "github.com/dellekappa/kms-go/crypto/tinkcrypto/primitive/secp256k1/subtle"
+	"github.com/dellekappa/kms-go/doc/jose/jwk"
+)
+
+// DefaultStreamVerifierBufferLimit is the default maximum size of the signing input buffered by a StreamVerifier for
+// algorithms which can't pre-hash the payload (EdDSA).
+const DefaultStreamVerifierBufferLimit = 64 << 20 // 64 MiB
+
+// streamVerifierOpts holds options for the StreamVerifier.
+type streamVerifierOpts struct {
+	bufferLimit   int
+	pssSaltLength int
+	minRSAKeyBits int
+	allowHighS    bool
+}
+
+// StreamVerifierOpt is the StreamVerifier option.
+type StreamVerifierOpt func(opts *streamVerifierOpts)
+
+// WithStreamVerifierBufferLimit option sets the maximum size in bytes of the signing input buffered for EdDSA, which
+// requires the full message to verify a signature. Writing more than limit bytes of signing input fails.
+func WithStreamVerifierBufferLimit(limit int) StreamVerifierOpt {
+	return func(opts *streamVerifierOpts) {
+		opts.bufferLimit = limit
+	}
+}
+
+// WithStreamVerifierPSSSaltLength option sets the salt length expected in PS256/384/512 signatures: an explicit number
+// of bytes, rsa.PSSSaltLengthEqualsHash (the default, as required by RFC 7518) or rsa.PSSSaltLengthAuto to accept any
+// salt length. It is meant for interoperability with signers not following RFC 7518.
+func WithStreamVerifierPSSSaltLength(saltLength int) StreamVerifierOpt {
+	return func(opts *streamVerifierOpts) {
+		opts.pssSaltLength = saltLength
+	}
+}
+
+// WithStreamVerifierMinRSAKeyBits option sets the minimum modulus size in bits of RSA public keys, e.g. 3072 to forbid
+// 2048 bits keys in a deployment. Smaller keys are rejected with an error wrapping jwk.ErrWeakKey. It defaults to
+// jwk.DefaultMinRSAKeyBits.
+func WithStreamVerifierMinRSAKeyBits(bits int) StreamVerifierOpt {
+	return func(opts *streamVerifierOpts) {
+		opts.minRSAKeyBits = bits
+	}
+}
+
+// WithStreamVerifierEnforceLowS option sets whether high-S ES256K signatures are rejected with an error wrapping
+// secp256k1 subtle.ErrNonCanonicalSignature, as required for Ethereum interoperability. It defaults to true, set it to
+// false to accept legacy high-S signatures.
+func WithStreamVerifierEnforceLowS(enforce bool) StreamVerifierOpt {
+	return func(opts *streamVerifierOpts) {
+		opts.allowHighS = !enforce
+	}
+}
+
+type streamAlg struct {
+	hash  crypto.Hash
+	curve elliptic.Curve
+	pss   bool
+}
+
+// streamAlgs are the algorithms supported by the StreamVerifier, EdDSA excepted as it does not pre-hash the payload.
+var streamAlgs = map[string]streamAlg{ //nolint:gochecknoglobals
+	"ES256":  {hash: crypto.SHA256, curve: elliptic.P256()},
+	"ES384":  {hash: crypto.SHA384, curve: elliptic.P384()},
+	"ES512":  {hash: crypto.SHA512, curve: elliptic.P521()},
+	"ES256K": {hash: crypto.SHA256, curve: btcec.S256()},
+	"RS256":  {hash: crypto.SHA256},
+	"RS384":  {hash: crypto.SHA384},
+	"RS512":  {hash: crypto.SHA512},
+	"PS256":  {hash: crypto.SHA256, pss: true},
+	"PS384":  {hash: crypto.SHA384, pss: true},
+	"PS512":  {hash: crypto.SHA512, pss: true},
+}
+
+const eddsaAlg = "EdDSA"
+
+// StreamVerifier verifies a JWS signature over a payload written to it, without holding the payload in memory.
+// The signing input is digested as the payload is written and the signature is verified on Close.
+type StreamVerifier struct {
+	alg           string
+	pubKey        crypto.PublicKey
+	signature     []byte
+	pssSaltLength int
+	minRSAKeyBits int
+	allowHighS    bool
+
+	digest  hash.Hash
+	buf     *bytes.Buffer
+	limit   int
+	encoder io.WriteCloser
+	closed  bool
+}
+
+// NewStreamVerifier creates a StreamVerifier of signature for the JWS with base64url encoded protected headers
+// b64Headers, signed by pubKey (a *jwk.JWK or an *ecdsa.PublicKey, *rsa.PublicKey or ed25519.PublicKey).
+// The payload must be written to the returned StreamVerifier, unencoded, and Close must be called to verify the
+// signature. ES256/384/512, ES256K, RS256/384/512 and PS256/384/512 payloads are digested incrementally, EdDSA
+// payloads are buffered up to DefaultStreamVerifierBufferLimit bytes of signing input, see
+// WithStreamVerifierBufferLimit.
+func NewStreamVerifier(b64Headers string, signature []byte, pubKey crypto.PublicKey,
+	opts ...StreamVerifierOpt) (*StreamVerifier, error) {
+	vOpts := &streamVerifierOpts{
+		bufferLimit:   DefaultStreamVerifierBufferLimit,
+		pssSaltLength: rsa.PSSSaltLengthEqualsHash,
+		minRSAKeyBits: jwk.DefaultMinRSAKeyBits,
+	}
+
+	for _, opt := range opts {
+		opt(vOpts)
+	}
+
+	headers, err := parseJWSHeaders(b64Headers)
+	if err != nil {
+		return nil, fmt.Errorf("new stream verifier: %w", err)
+	}
+
+	alg, _ := headers.Algorithm()
+
+	b64Payload := true
+
+	if b64, ok := headers[HeaderB64Payload]; ok {
+		if b64Payload, ok = b64.(bool); !ok {
+			return nil, errors.New("new stream verifier: invalid b64 header")
+		}
+	}
+
+	if k, ok := pubKey.(*jwk.JWK); ok {
+		pubKey = k.Key
+	}
+
+	v := &StreamVerifier{
+		alg:           alg,
+		pubKey:        pubKey,
+		signature:     signature,
+		pssSaltLength: vOpts.pssSaltLength,
+		minRSAKeyBits: vOpts.minRSAKeyBits,
+		allowHighS:    vOpts.allowHighS,
+		limit:         vOpts.bufferLimit,
+	}
+
+	err = v.init()
+	if err != nil {
+		return nil, fmt.Errorf("new stream verifier: %w", err)
+	}
+
+	// the signing input is ASCII(BASE64URL(UTF8(JWS Protected Header))) || '.' || ASCII(BASE64URL(JWS Payload)).
+	_, err = v.writeSigningInput([]byte(b64Headers + "."))
+	if err != nil {
+		return nil, fmt.Errorf("new stream verifier: %w", err)
+	}
+
+	if b64Payload {
+		v.encoder = base64.NewEncoder(base64.RawURLEncoding, writerFunc(v.writeSigningInput))
+	}
+
+	return v, nil
+}
+
+func (v *StreamVerifier) init() error {
+	if v.alg == eddsaAlg {
+		if _, ok := v.pubKey.(ed25519.PublicKey); !ok {
+			return fmt.Errorf("%s requires an ed25519 public key, got %T", v.alg, v.pubKey)
+		}
+
+		v.buf = &bytes.Buffer{}
+
+		return nil
+	}
+
+	sAlg, ok := streamAlgs[v.alg]
+	if !ok {
+		return fmt.Errorf("unsupported algorithm '%s'", v.alg)
+	}
+
+	switch key := v.pubKey.(type) {
+	case *ecdsa.PublicKey:
+		if sAlg.curve == nil || key.Curve != sAlg.curve {
+			return fmt.Errorf("%s does not support ECDSA %s public key", v.alg, key.Curve.Params().Name)
+		}
+
+		// the signature is the IEEE P1363 concatenation of R and S, each padded to the curve field size.
+		if sigSize := 2 * ecdsaFieldSize(key.Curve); len(v.signature) != sigSize {
+			return fmt.Errorf("invalid %s signature length: expected %d, got %d", v.alg, sigSize, len(v.signature))
+		}
+	case *rsa.PublicKey:
+		if sAlg.curve != nil {
+			return fmt.Errorf("%s does not support RSA public key", v.alg)
+		}
+
+		if err := jwk.CheckRSAKeySize(key, v.minRSAKeyBits); err != nil {
+			return err
+		}
+	default:
+		return fmt.Errorf("%s does not support public key type %T", v.alg, v.pubKey)
+	}
+
+	v.digest = sAlg.hash.New()
+
+	return nil
+}
+
+// Write writes a chunk of the payload.
+func (v *StreamVerifier) Write(p []byte) (int, error) {
+	if v.closed {
+		return 0, errors.New("stream verifier is closed")
+	}
+
+	if v.encoder != nil {
+		return v.encoder.Write(p)
+	}
+
+	return v.writeSigningInput(p)
+}
+
+func (v *StreamVerifier) writeSigningInput(p []byte) (int, error) {
+	if v.buf == nil {
+		return v.digest.Write(p)
+	}
+
+	if v.buf.Len()+len(p) > v.limit {
+		return 0, fmt.Errorf("%s signing input exceeds the buffer limit of %d bytes", eddsaAlg, v.limit)
+	}
+
+	return v.buf.Write(p)
+}
+
+// Close verifies the signature of the payload written to the StreamVerifier.
+func (v *StreamVerifier) Close() error {
+	if v.closed {
+		return errors.New("stream verifier is closed")
+	}
+
+	v.closed = true
+
+	if v.encoder != nil {
+		if err := v.encoder.Close(); err != nil {
+			return fmt.Errorf("verify JWS signature: %w", err)
+		}
+	}
+
+	if err := v.verify(); err != nil {
+		return fmt.Errorf("verify JWS signature: %w", err)
+	}
+
+	return nil
+}
+
+func (v *StreamVerifier) verify() error {
+	if v.buf != nil {
+		if !ed25519.Verify(v.pubKey.(ed25519.PublicKey), v.buf.Bytes(), v.signature) { //nolint:forcetypeassert
+			return errors.New("invalid signature")
+		}
+
+		return nil
+	}
+
+	sAlg := streamAlgs[v.alg]
+	digest := v.digest.Sum(nil)
+
+	switch key := v.pubKey.(type) {
+	case *ecdsa.PublicKey:
+		// the signature size is checked on creation.
+		keySize := ecdsaFieldSize(key.Curve)
+
+		r := new(big.Int).SetBytes(v.signature[:keySize])
+		s := new(big.Int).SetBytes(v.signature[keySize:])
+
+		if key.Curve == btcec.S256() && !v.allowHighS && !secp256k1subtle.IsLowS(key.Curve, s) {
+			return secp256k1subtle.ErrNonCanonicalSignature
+		}
+
+		if !ecdsa.Verify(key, digest, r, s) {
+			return errors.New("invalid signature")
+		}
+
+		return nil
+	case *rsa.PublicKey:
+		if sAlg.pss {
+			return rsa.VerifyPSS(key, sAlg.hash, digest, v.signature,
+				&rsa.PSSOptions{SaltLength: v.pssSaltLength})
+		}
+
+		return rsa.VerifyPKCS1v15(key, sAlg.hash, digest, v.signature)
+	}
+
+	return fmt.Errorf("unsupported public key type %T", v.pubKey)
+}
+
+// ecdsaFieldSize returns the size in bytes of the field elements of curve.
+func ecdsaFieldSize(curve elliptic.Curve) int {
+	return (curve.Params().BitSize + 7) / 8 //nolint:gomnd
+}
+
+type writerFunc func(p []byte) (int, error)
+
+func (f writerFunc) Write(p []byte) (int, error) {
+	return f(p)
+}
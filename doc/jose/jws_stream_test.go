@@ -0,0 +1,373 @@
+/*
+Copyright Gen Digital Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package jose
+
+import (
+	"bytes"
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/rsa"
+	"encoding/base64"
+	"fmt"
+	"io"
+	"testing"
+
+	"github.com/go-jose/go-jose/v3/json"
+	"github.com/stretchr/testify/require"
+
+	"github.com/dellekappa/kms-go/doc/jose/jwk"
+)
+
+func TestStreamVerifier(t *testing.T) {
+	payload := bytes.Repeat([]byte("streamed payload "), 10000)
+
+	ecKey256, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+
+	ecKey384, err := ecdsa.GenerateKey(elliptic.P384(), rand.Reader)
+	require.NoError(t, err)
+
+	ecKey521, err := ecdsa.GenerateKey(elliptic.P521(), rand.Reader)
+	require.NoError(t, err)
+
+	rsaKey, err := rsa.GenerateKey(rand.Reader, 2048)
+	require.NoError(t, err)
+
+	edPub, edPriv, err := ed25519.GenerateKey(rand.Reader)
+	require.NoError(t, err)
+
+	tests := []struct {
+		alg    string
+		pubKey crypto.PublicKey
+		sign   func(sInput []byte) []byte
+	}{
+		{alg: "ES256", pubKey: &ecKey256.PublicKey, sign: ecdsaStreamTestSigner(t, ecKey256, crypto.SHA256)},
+		{alg: "ES384", pubKey: &ecKey384.PublicKey, sign: ecdsaStreamTestSigner(t, ecKey384, crypto.SHA384)},
+		{alg: "ES512", pubKey: &ecKey521.PublicKey, sign: ecdsaStreamTestSigner(t, ecKey521, crypto.SHA512)},
+		{alg: "RS256", pubKey: &rsaKey.PublicKey, sign: rsaStreamTestSigner(t, rsaKey, crypto.SHA256, false)},
+		{alg: "RS512", pubKey: &rsaKey.PublicKey, sign: rsaStreamTestSigner(t, rsaKey, crypto.SHA512, false)},
+		{alg: "PS256", pubKey: &rsaKey.PublicKey, sign: rsaStreamTestSigner(t, rsaKey, crypto.SHA256, true)},
+		{alg: "PS384", pubKey: &rsaKey.PublicKey, sign: rsaStreamTestSigner(t, rsaKey, crypto.SHA384, true)},
+		{alg: "EdDSA", pubKey: edPub, sign: func(sInput []byte) []byte { return ed25519.Sign(edPriv, sInput) }},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.alg, func(t *testing.T) {
+			for _, b64 := range []bool{true, false} {
+				headers := Headers{HeaderAlgorithm: tc.alg}
+				if !b64 {
+					headers[HeaderB64Payload] = false
+				}
+
+				b64Headers, signature := signStreamTestJWS(t, headers, payload, b64, tc.sign)
+
+				v, err := NewStreamVerifier(b64Headers, signature, tc.pubKey)
+				require.NoError(t, err)
+
+				// small buffer to exercise chunks not aligned with base64 blocks.
+				_, err = io.CopyBuffer(v, bytes.NewReader(payload), make([]byte, 1000))
+				require.NoError(t, err)
+				require.NoError(t, v.Close())
+
+				v, err = NewStreamVerifier(b64Headers, signature, tc.pubKey)
+				require.NoError(t, err)
+
+				_, err = v.Write(payload[1:])
+				require.NoError(t, err)
+				require.ErrorContains(t, v.Close(), "verify JWS signature")
+			}
+		})
+	}
+
+	t.Run("JWK public key", func(t *testing.T) {
+		b64Headers, signature := signStreamTestJWS(t, Headers{HeaderAlgorithm: "ES256"}, payload, true,
+			ecdsaStreamTestSigner(t, ecKey256, crypto.SHA256))
+
+		v, err := NewStreamVerifier(b64Headers, signature, &jwk.JWK{})
+		require.EqualError(t, err, "new stream verifier: ES256 does not support public key type <nil>")
+		require.Nil(t, v)
+
+		pubJWK := &jwk.JWK{}
+		pubJWK.Key = &ecKey256.PublicKey
+
+		v, err = NewStreamVerifier(b64Headers, signature, pubJWK)
+		require.NoError(t, err)
+
+		_, err = v.Write(payload)
+		require.NoError(t, err)
+		require.NoError(t, v.Close())
+	})
+
+	t.Run("EdDSA buffer limit", func(t *testing.T) {
+		sign := func(sInput []byte) []byte { return ed25519.Sign(edPriv, sInput) }
+		b64Headers, signature := signStreamTestJWS(t, Headers{HeaderAlgorithm: "EdDSA"}, payload, true, sign)
+
+		v, err := NewStreamVerifier(b64Headers, signature, edPub, WithStreamVerifierBufferLimit(1024))
+		require.NoError(t, err)
+
+		_, err = v.Write(payload)
+		require.EqualError(t, err, "EdDSA signing input exceeds the buffer limit of 1024 bytes")
+	})
+
+	t.Run("PSS salt length", func(t *testing.T) {
+		signPSS := func(saltLength int) func([]byte) []byte {
+			return func(sInput []byte) []byte {
+				digest := crypto.SHA256.New()
+				digest.Write(sInput)
+
+				signature, err := rsa.SignPSS(rand.Reader, rsaKey, crypto.SHA256, digest.Sum(nil),
+					&rsa.PSSOptions{SaltLength: saltLength})
+				require.NoError(t, err)
+
+				return signature
+			}
+		}
+
+		verify := func(signature []byte, b64Headers string, opts ...StreamVerifierOpt) error {
+			v, err := NewStreamVerifier(b64Headers, signature, &rsaKey.PublicKey, opts...)
+			require.NoError(t, err)
+
+			_, err = v.Write(payload)
+			require.NoError(t, err)
+
+			return v.Close()
+		}
+
+		headers := Headers{HeaderAlgorithm: "PS256"}
+
+		b64Headers, hashSaltSig := signStreamTestJWS(t, headers, payload, true, signPSS(rsa.PSSSaltLengthEqualsHash))
+		_, maxSaltSig := signStreamTestJWS(t, headers, payload, true, signPSS(rsa.PSSSaltLengthAuto))
+		_, saltSig := signStreamTestJWS(t, headers, payload, true, signPSS(20))
+
+		// default: salt length equal to the hash size.
+		require.NoError(t, verify(hashSaltSig, b64Headers))
+		require.Error(t, verify(maxSaltSig, b64Headers))
+		require.Error(t, verify(saltSig, b64Headers))
+
+		auto := WithStreamVerifierPSSSaltLength(rsa.PSSSaltLengthAuto)
+		require.NoError(t, verify(hashSaltSig, b64Headers, auto))
+		require.NoError(t, verify(maxSaltSig, b64Headers, auto))
+		require.NoError(t, verify(saltSig, b64Headers, auto))
+
+		explicit := WithStreamVerifierPSSSaltLength(20)
+		require.NoError(t, verify(saltSig, b64Headers, explicit))
+		require.Error(t, verify(hashSaltSig, b64Headers, explicit))
+
+		require.NoError(t, verify(hashSaltSig, b64Headers,
+			WithStreamVerifierPSSSaltLength(rsa.PSSSaltLengthEqualsHash)))
+	})
+
+	t.Run("minimum RSA key size", func(t *testing.T) {
+		b64Headers, signature := signStreamTestJWS(t, Headers{HeaderAlgorithm: "RS256"}, payload, true,
+			rsaStreamTestSigner(t, rsaKey, crypto.SHA256, false))
+
+		_, err := NewStreamVerifier(b64Headers, signature, &rsaKey.PublicKey, WithStreamVerifierMinRSAKeyBits(3072))
+		require.ErrorIs(t, err, jwk.ErrWeakKey)
+		require.EqualError(t, err, "new stream verifier: weak key: RSA key size 2048 bits is below the 3072 bits "+
+			"minimum")
+
+		smallKey, err := rsa.GenerateKey(rand.Reader, 1024)
+		require.NoError(t, err)
+
+		b64Headers, signature = signStreamTestJWS(t, Headers{HeaderAlgorithm: "RS256"}, payload, true,
+			rsaStreamTestSigner(t, smallKey, crypto.SHA256, false))
+
+		_, err = NewStreamVerifier(b64Headers, signature, &smallKey.PublicKey)
+		require.ErrorIs(t, err, jwk.ErrWeakKey)
+
+		v, err := NewStreamVerifier(b64Headers, signature, &smallKey.PublicKey, WithStreamVerifierMinRSAKeyBits(1024))
+		require.NoError(t, err)
+
+		_, err = v.Write(payload)
+		require.NoError(t, err)
+		require.NoError(t, v.Close())
+	})
+
+	t.Run("write after close", func(t *testing.T) {
+		b64Headers, signature := signStreamTestJWS(t, Headers{HeaderAlgorithm: "RS256"}, payload, true,
+			rsaStreamTestSigner(t, rsaKey, crypto.SHA256, false))
+
+		v, err := NewStreamVerifier(b64Headers, signature, &rsaKey.PublicKey)
+		require.NoError(t, err)
+
+		_, err = v.Write(payload)
+		require.NoError(t, err)
+		require.NoError(t, v.Close())
+
+		_, err = v.Write(payload)
+		require.EqualError(t, err, "stream verifier is closed")
+		require.EqualError(t, v.Close(), "stream verifier is closed")
+	})
+
+	t.Run("error on invalid headers and keys", func(t *testing.T) {
+		b64 := func(headers Headers) string {
+			headersBytes, e := json.Marshal(headers)
+			require.NoError(t, e)
+
+			return base64.RawURLEncoding.EncodeToString(headersBytes)
+		}
+
+		tests := []struct {
+			name       string
+			b64Headers string
+			pubKey     crypto.PublicKey
+			err        string
+		}{
+			{
+				name:       "invalid base64 headers",
+				b64Headers: "!",
+				err:        "new stream verifier: decode base64 header",
+			},
+			{
+				name:       "missing alg",
+				b64Headers: b64(Headers{}),
+				err:        "new stream verifier: alg JWS header is not defined",
+			},
+			{
+				name:       "invalid b64 header",
+				b64Headers: b64(Headers{HeaderAlgorithm: "ES256", HeaderB64Payload: "no"}),
+				err:        "new stream verifier: invalid b64 header",
+			},
+			{
+				name:       "unsupported algorithm",
+				b64Headers: b64(Headers{HeaderAlgorithm: "HS256"}),
+				pubKey:     &ecKey256.PublicKey,
+				err:        "new stream verifier: unsupported algorithm 'HS256'",
+			},
+			{
+				name:       "curve mismatch",
+				b64Headers: b64(Headers{HeaderAlgorithm: "ES256"}),
+				pubKey:     &ecKey384.PublicKey,
+				err:        "new stream verifier: ES256 does not support ECDSA P-384 public key",
+			},
+			{
+				name:       "RSA key with ES algorithm",
+				b64Headers: b64(Headers{HeaderAlgorithm: "ES256"}),
+				pubKey:     &rsaKey.PublicKey,
+				err:        "new stream verifier: ES256 does not support RSA public key",
+			},
+			{
+				name:       "EdDSA with ECDSA key",
+				b64Headers: b64(Headers{HeaderAlgorithm: "EdDSA"}),
+				pubKey:     &ecKey256.PublicKey,
+				err:        "new stream verifier: EdDSA requires an ed25519 public key, got *ecdsa.PublicKey",
+			},
+		}
+
+		for _, tc := range tests {
+			t.Run(tc.name, func(t *testing.T) {
+				v, err := NewStreamVerifier(tc.b64Headers, nil, tc.pubKey)
+				require.ErrorContains(t, err, tc.err)
+				require.Nil(t, v)
+			})
+		}
+	})
+
+	t.Run("error invalid ECDSA signature length", func(t *testing.T) {
+		tests := []struct {
+			alg    string
+			pubKey *ecdsa.PublicKey
+			sign   func([]byte) []byte
+			err    string
+		}{
+			{
+				alg:    "ES256",
+				pubKey: &ecKey256.PublicKey,
+				sign:   ecdsaStreamTestSigner(t, ecKey256, crypto.SHA256),
+				err:    "new stream verifier: invalid ES256 signature length: expected 64, got %d",
+			},
+			{
+				alg:    "ES384",
+				pubKey: &ecKey384.PublicKey,
+				sign:   ecdsaStreamTestSigner(t, ecKey384, crypto.SHA384),
+				err:    "new stream verifier: invalid ES384 signature length: expected 96, got %d",
+			},
+			{
+				alg:    "ES512",
+				pubKey: &ecKey521.PublicKey,
+				sign:   ecdsaStreamTestSigner(t, ecKey521, crypto.SHA512),
+				err:    "new stream verifier: invalid ES512 signature length: expected 132, got %d",
+			},
+		}
+
+		for _, tc := range tests {
+			t.Run(tc.alg, func(t *testing.T) {
+				b64Headers, signature := signStreamTestJWS(t, Headers{HeaderAlgorithm: tc.alg}, payload, true, tc.sign)
+
+				// truncated, extended and DER-sized signatures.
+				for _, sig := range [][]byte{signature[1:], append(signature, 0), []byte("signature")} {
+					v, err := NewStreamVerifier(b64Headers, sig, tc.pubKey)
+					require.EqualError(t, err, fmt.Sprintf(tc.err, len(sig)))
+					require.Nil(t, v)
+				}
+			})
+		}
+	})
+}
+
+func signStreamTestJWS(t *testing.T, headers Headers, payload []byte, b64 bool,
+	sign func(sInput []byte) []byte) (string, []byte) {
+	t.Helper()
+
+	headersBytes, err := json.Marshal(headers)
+	require.NoError(t, err)
+
+	b64Headers := base64.RawURLEncoding.EncodeToString(headersBytes)
+
+	sInput := []byte(b64Headers + ".")
+	if b64 {
+		sInput = append(sInput, base64.RawURLEncoding.EncodeToString(payload)...)
+	} else {
+		sInput = append(sInput, payload...)
+	}
+
+	return b64Headers, sign(sInput)
+}
+
+func ecdsaStreamTestSigner(t *testing.T, key *ecdsa.PrivateKey, h crypto.Hash) func([]byte) []byte {
+	return func(sInput []byte) []byte {
+		digest := h.New()
+		digest.Write(sInput)
+
+		r, s, err := ecdsa.Sign(rand.Reader, key, digest.Sum(nil))
+		require.NoError(t, err)
+
+		keySize := (key.Curve.Params().BitSize + 7) / 8
+
+		signature := make([]byte, 2*keySize)
+		r.FillBytes(signature[:keySize])
+		s.FillBytes(signature[keySize:])
+
+		return signature
+	}
+}
+
+func rsaStreamTestSigner(t *testing.T, key *rsa.PrivateKey, h crypto.Hash, pss bool) func([]byte) []byte {
+	return func(sInput []byte) []byte {
+		digest := h.New()
+		digest.Write(sInput)
+
+		var (
+			signature []byte
+			err       error
+		)
+
+		if pss {
+			signature, err = rsa.SignPSS(rand.Reader, key, h, digest.Sum(nil),
+				&rsa.PSSOptions{SaltLength: rsa.PSSSaltLengthEqualsHash})
+		} else {
+			signature, err = rsa.SignPKCS1v15(rand.Reader, key, h, digest.Sum(nil))
+		}
+
+		require.NoError(t, err)
+
+		return signature
+	}
+}
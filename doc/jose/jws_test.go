@@ -245,6 +245,85 @@ func TestParseJWS(t *testing.T) {
 	require.Nil(t, parsedJWS)
 }
 
+func TestParseJWSUnsecured(t *testing.T) {
+	payload := base64.RawURLEncoding.EncodeToString([]byte("payload"))
+
+	unsecuredJWS := func(alg, signature string) string {
+		return base64.RawURLEncoding.EncodeToString([]byte(`{"alg":"`+alg+`"}`)) + "." + payload + "." + signature
+	}
+
+	// the verifier accepts any signature, the "none" alg must be rejected before reaching it.
+	verifier := &testVerifier{}
+
+	t.Run("rejected by default", func(t *testing.T) {
+		for _, alg := range []string{"none", "None", "NONE"} {
+			parsedJWS, err := ParseJWS(unsecuredJWS(alg, ""), verifier)
+			require.ErrorIs(t, err, ErrUnsecuredJWS)
+			require.Nil(t, parsedJWS)
+
+			parsedJWS, err = ParseJWS(unsecuredJWS(alg, ""), verifier, AllowUnsecured(false))
+			require.ErrorIs(t, err, ErrUnsecuredJWS)
+			require.Nil(t, parsedJWS)
+		}
+	})
+
+	t.Run("allowed", func(t *testing.T) {
+		parsedJWS, err := ParseJWS(unsecuredJWS("none", ""), &testVerifier{err: errors.New("not called")},
+			AllowUnsecured(true))
+		require.NoError(t, err)
+		require.Equal(t, []byte("payload"), parsedJWS.Payload)
+		require.Empty(t, parsedJWS.Signature())
+	})
+
+	t.Run("allowed with invalid alg case", func(t *testing.T) {
+		parsedJWS, err := ParseJWS(unsecuredJWS("None", ""), verifier, AllowUnsecured(true))
+		require.ErrorIs(t, err, ErrUnsecuredJWS)
+		require.Nil(t, parsedJWS)
+	})
+
+	t.Run("allowed with signature", func(t *testing.T) {
+		parsedJWS, err := ParseJWS(unsecuredJWS("none", "c2lnbmF0dXJl"), verifier, AllowUnsecured(true))
+		require.EqualError(t, err, "unsecured JWS must have an empty signature")
+		require.Nil(t, parsedJWS)
+	})
+}
+
+func TestParseJWSAllowedAlgorithms(t *testing.T) {
+	payload := base64.RawURLEncoding.EncodeToString([]byte("payload"))
+
+	jwsWithAlg := func(alg string) string {
+		return base64.RawURLEncoding.EncodeToString([]byte(`{"alg":"`+alg+`"}`)) + "." + payload + ".c2lnbmF0dXJl"
+	}
+
+	t.Run("all algorithms allowed by default", func(t *testing.T) {
+		parsedJWS, err := ParseJWS(jwsWithAlg("HS256"), &testVerifier{})
+		require.NoError(t, err)
+		require.NotNil(t, parsedJWS)
+	})
+
+	t.Run("allowed", func(t *testing.T) {
+		parsedJWS, err := ParseJWS(jwsWithAlg("EdDSA"), &testVerifier{}, AllowedAlgorithms([]string{"ES256", "EdDSA"}))
+		require.NoError(t, err)
+		require.NotNil(t, parsedJWS)
+	})
+
+	t.Run("disallowed alg is rejected before verifying", func(t *testing.T) {
+		verifier := &testVerifier{err: errors.New("verifier must not be called")}
+
+		for _, alg := range []string{"HS256", "eddsa", "none"} {
+			parsedJWS, err := ParseJWS(jwsWithAlg(alg), verifier, AllowedAlgorithms([]string{"ES256", "EdDSA"}),
+				AllowUnsecured(true))
+			require.ErrorIs(t, err, ErrDisallowedAlgorithm)
+			require.EqualError(t, err, "JWS alg is not allowed: '"+alg+"'")
+			require.Nil(t, parsedJWS)
+		}
+
+		parsedJWS, err := ParseJWS(jwsWithAlg("ES256"), verifier, AllowedAlgorithms([]string{}))
+		require.ErrorIs(t, err, ErrDisallowedAlgorithm)
+		require.Nil(t, parsedJWS)
+	})
+}
+
 func TestIsCompactJWS(t *testing.T) {
 	require.True(t, IsCompactJWS("a.b.c"))
 	require.False(t, IsCompactJWS("a.b"))
@@ -252,6 +331,53 @@ func TestIsCompactJWS(t *testing.T) {
 	require.False(t, IsCompactJWS(""))
 }
 
+func TestJWSSigningInput(t *testing.T) {
+	protected := map[string]interface{}{"alg": "ES256", "kid": "key-1"}
+	// a 4 bytes payload needs padding with the padded encodings.
+	payload := []byte("test")
+
+	b64Headers, sigInput, err := JWSSigningInput(protected, payload)
+	require.NoError(t, err)
+	require.Equal(t, "eyJhbGciOiJFUzI1NiIsImtpZCI6ImtleS0xIn0", b64Headers)
+	require.Equal(t, b64Headers+".dGVzdA", string(sigInput))
+	require.NotContains(t, string(sigInput), "=")
+
+	var signedInput []byte
+
+	jws, err := NewJWS(protected, nil, payload, signFunc(func(data []byte) ([]byte, error) {
+		signedInput = data
+
+		return []byte("signature"), nil
+	}))
+	require.NoError(t, err)
+	require.Equal(t, sigInput, signedInput)
+
+	compact, err := jws.SerializeCompact(false)
+	require.NoError(t, err)
+	require.Equal(t, string(sigInput)+"."+base64.RawURLEncoding.EncodeToString([]byte("signature")), compact)
+
+	_, sigInput, err = JWSSigningInput(map[string]interface{}{"alg": "ES256", "b64": false}, payload)
+	require.NoError(t, err)
+	require.True(t, strings.HasSuffix(string(sigInput), ".test"))
+
+	_, _, err = JWSSigningInput(map[string]interface{}{"alg": "ES256", "b64": "false"}, payload)
+	require.EqualError(t, err, "invalid b64 header")
+
+	_, _, err = JWSSigningInput(getUnmarshallableMap(), payload)
+	require.ErrorContains(t, err, "serialize JWS headers")
+}
+
+// signFunc is a Signer signing with a function, without headers.
+type signFunc func(data []byte) ([]byte, error)
+
+func (f signFunc) Sign(data []byte) ([]byte, error) {
+	return f(data)
+}
+
+func (f signFunc) Headers() Headers {
+	return nil
+}
+
 type testSigner struct {
 	headers   Headers
 	signature []byte
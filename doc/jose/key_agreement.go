@@ -0,0 +1,150 @@
+/*
+Copyright Gen Digital Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package jose
+
+import (
+	"crypto/ecdsa"
+	"errors"
+	"fmt"
+
+	"golang.org/x/crypto/curve25519"
+
+	"github.com/dellekappa/kms-go/crypto/tinkcrypto"
+	ecdhpb "github.com/dellekappa/kms-go/crypto/tinkcrypto/primitive/proto/ecdh_aead_go_proto"
+	"github.com/dellekappa/kms-go/doc/jose/jwk"
+	cryptoapi "github.com/dellekappa/kms-go/spi/crypto"
+)
+
+// DeriveCEK runs the sender side of ECDH-ES Direct Key Agreement (RFC 7518 section 4.6) with recipientPub, an EC
+// public key on P-256, P-384 or P-521 or an X25519 OKP public key, for callers encrypting the content with their own
+// AEAD. It generates an ephemeral key and returns the content encryption key of the enc algorithm (e.g. "A256GCM")
+// with the ephemeral public key to set as the epk header. apu and apv are used as is in the Concat KDF, they must be
+// set base64url encoded as the apu and apv headers. RecoverCEK derives the same key on the recipient side.
+func DeriveCEK(recipientPub *jwk.JWK, enc string, apu, apv []byte) ([]byte, *jwk.JWK, error) {
+	keySize, err := directCEKSize(enc)
+	if err != nil {
+		return nil, nil, fmt.Errorf("deriveCEK: %w", err)
+	}
+
+	recPubKey, err := recipientAgreementKey(recipientPub)
+	if err != nil {
+		return nil, nil, fmt.Errorf("deriveCEK: %w", err)
+	}
+
+	z, epk, err := ephemeralKeyAgreement(recPubKey)
+	if err != nil {
+		return nil, nil, fmt.Errorf("deriveCEK: %w", err)
+	}
+
+	mEPK, err := convertRecEPKToMarshalledJWK(epk)
+	if err != nil {
+		return nil, nil, fmt.Errorf("deriveCEK: %w", err)
+	}
+
+	epkJWK, err := parseEPK(mEPK)
+	if err != nil {
+		return nil, nil, fmt.Errorf("deriveCEK: %w", err)
+	}
+
+	return tinkcrypto.ConcatKDF(enc, z, apu, apv, nil, keySize), epkJWK, nil
+}
+
+// RecoverCEK runs the recipient side of ECDH-ES Direct Key Agreement: it derives the content encryption key of the enc
+// algorithm from recipientPriv and the epk header of the message, with the same apu and apv as DeriveCEK.
+// recipientPriv is an EC private key JWK. X25519 JWKs do not carry private keys, the Key of an X25519 recipientPriv
+// is its 32 bytes private key. epk is validated as ParseEPK does and must be on the curve of recipientPriv.
+func RecoverCEK(recipientPriv, epk *jwk.JWK, enc string, apu, apv []byte) ([]byte, error) {
+	keySize, err := directCEKSize(enc)
+	if err != nil {
+		return nil, fmt.Errorf("recoverCEK: %w", err)
+	}
+
+	if recipientPriv == nil || epk == nil {
+		return nil, errors.New("recoverCEK: recipient key and epk are required")
+	}
+
+	mEPK, err := epk.MarshalJSON()
+	if err != nil {
+		return nil, fmt.Errorf("recoverCEK: marshal epk: %w", err)
+	}
+
+	epk, err = parseEPK(mEPK)
+	if err != nil {
+		return nil, fmt.Errorf("recoverCEK: %w", err)
+	}
+
+	var z []byte
+
+	switch privKey := recipientPriv.Key.(type) {
+	case *ecdsa.PrivateKey:
+		epkPubKey, ok := epk.Key.(*ecdsa.PublicKey)
+		if !ok || epkPubKey.Curve != privKey.Curve {
+			return nil, fmt.Errorf("recoverCEK: epk curve '%s' does not match recipient key curve '%s'",
+				epk.Crv, privKey.Curve.Params().Name)
+		}
+
+		z = ecSharedSecret(privKey, epkPubKey)
+	case []byte:
+		epkPubKey, ok := epk.Key.([]byte)
+		if !ok || recipientPriv.Kty != okpKty || recipientPriv.Crv != x25519Curve {
+			return nil, fmt.Errorf("recoverCEK: epk curve '%s' does not match recipient key curve '%s'",
+				epk.Crv, recipientPriv.Crv)
+		}
+
+		z, err = curve25519.X25519(privKey, epkPubKey)
+		if err != nil {
+			return nil, fmt.Errorf("recoverCEK: %w", err)
+		}
+	default:
+		return nil, fmt.Errorf("recoverCEK: unsupported recipient private key type %T", recipientPriv.Key)
+	}
+
+	return tinkcrypto.ConcatKDF(enc, z, apu, apv, nil, keySize), nil
+}
+
+// directCEKSize returns the size of the CEK of enc, which must be a supported content encryption algorithm.
+func directCEKSize(enc string) (int, error) {
+	if _, ok := aeadAlg[EncAlg(enc)]; !ok {
+		return 0, fmt.Errorf("unsupported content encryption algorithm '%s'", enc)
+	}
+
+	return cekSize(EncAlg(enc)), nil
+}
+
+// recipientAgreementKey converts the recipient public JWK of a key agreement to a cryptoapi.PublicKey.
+func recipientAgreementKey(recipientPub *jwk.JWK) (*cryptoapi.PublicKey, error) {
+	if recipientPub == nil {
+		return nil, errors.New("recipient key is required")
+	}
+
+	if kty, ok := epkCurves[recipientPub.Crv]; !ok || kty != recipientPub.Kty {
+		return nil, fmt.Errorf("unsupported recipient key type '%s' and curve '%s'", recipientPub.Kty,
+			recipientPub.Crv)
+	}
+
+	switch key := recipientPub.Key.(type) {
+	case *ecdsa.PublicKey:
+		return &cryptoapi.PublicKey{
+			X:     key.X.Bytes(),
+			Y:     key.Y.Bytes(),
+			Curve: recipientPub.Crv,
+			Type:  ecdhpb.KeyType_EC.String(),
+		}, nil
+	case []byte:
+		if len(key) != x25519KeySize {
+			return nil, fmt.Errorf("invalid recipient X25519 key size %d", len(key))
+		}
+
+		return &cryptoapi.PublicKey{
+			X:     key,
+			Curve: recipientPub.Crv,
+			Type:  ecdhpb.KeyType_OKP.String(),
+		}, nil
+	default:
+		return nil, fmt.Errorf("recipient key must be a public key, got %T", recipientPub.Key)
+	}
+}
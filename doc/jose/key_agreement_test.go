@@ -0,0 +1,118 @@
+/*
+Copyright Gen Digital Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package jose
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"testing"
+
+	"github.com/go-jose/go-jose/v3"
+	josecipher "github.com/go-jose/go-jose/v3/cipher"
+	"github.com/stretchr/testify/require"
+	"golang.org/x/crypto/curve25519"
+
+	"github.com/dellekappa/kms-go/doc/jose/jwk"
+)
+
+func TestDeriveRecoverCEK(t *testing.T) {
+	apu, apv := []byte("Alice"), []byte("Bob")
+
+	t.Run("EC", func(t *testing.T) {
+		for _, curve := range []elliptic.Curve{elliptic.P256(), elliptic.P384(), elliptic.P521()} {
+			privKey, err := ecdsa.GenerateKey(curve, rand.Reader)
+			require.NoError(t, err)
+
+			pub := &jwk.JWK{JSONWebKey: jose.JSONWebKey{Key: &privKey.PublicKey}, Kty: "EC", Crv: curve.Params().Name}
+			priv := &jwk.JWK{JSONWebKey: jose.JSONWebKey{Key: privKey}, Kty: "EC", Crv: curve.Params().Name}
+
+			cek, epk, err := DeriveCEK(pub, "A128GCM", apu, apv)
+			require.NoError(t, err)
+			require.Len(t, cek, 16)
+			require.Equal(t, curve.Params().Name, epk.Crv)
+			require.True(t, epk.IsPublic())
+
+			recovered, err := RecoverCEK(priv, epk, "A128GCM", apu, apv)
+			require.NoError(t, err)
+			require.Equal(t, cek, recovered)
+
+			// interoperable with go-jose ECDH-ES.
+			epkPubKey, ok := epk.Key.(*ecdsa.PublicKey)
+			require.True(t, ok)
+			require.Equal(t, cek, josecipher.DeriveECDHES("A128GCM", apu, apv, privKey, epkPubKey, 16))
+
+			recovered, err = RecoverCEK(priv, epk, "A128GCM", apu, []byte("Eve"))
+			require.NoError(t, err)
+			require.NotEqual(t, cek, recovered)
+		}
+	})
+
+	t.Run("X25519", func(t *testing.T) {
+		privKey := make([]byte, curve25519.ScalarSize)
+		_, err := rand.Read(privKey)
+		require.NoError(t, err)
+
+		pubKey, err := curve25519.X25519(privKey, curve25519.Basepoint)
+		require.NoError(t, err)
+
+		pub := &jwk.JWK{JSONWebKey: jose.JSONWebKey{Key: pubKey}, Kty: "OKP", Crv: "X25519"}
+		priv := &jwk.JWK{JSONWebKey: jose.JSONWebKey{Key: privKey}, Kty: "OKP", Crv: "X25519"}
+
+		cek, epk, err := DeriveCEK(pub, "A256CBC-HS512", apu, apv)
+		require.NoError(t, err)
+		require.Len(t, cek, 64)
+		require.Equal(t, "X25519", epk.Crv)
+
+		recovered, err := RecoverCEK(priv, epk, "A256CBC-HS512", apu, apv)
+		require.NoError(t, err)
+		require.Equal(t, cek, recovered)
+	})
+
+	t.Run("failures", func(t *testing.T) {
+		privKey, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+		require.NoError(t, err)
+
+		pub := &jwk.JWK{JSONWebKey: jose.JSONWebKey{Key: &privKey.PublicKey}, Kty: "EC", Crv: "P-256"}
+		priv := &jwk.JWK{JSONWebKey: jose.JSONWebKey{Key: privKey}, Kty: "EC", Crv: "P-256"}
+
+		_, _, err = DeriveCEK(pub, "A128KW", nil, nil)
+		require.EqualError(t, err, "deriveCEK: unsupported content encryption algorithm 'A128KW'")
+
+		_, _, err = DeriveCEK(nil, "A256GCM", nil, nil)
+		require.EqualError(t, err, "deriveCEK: recipient key is required")
+
+		_, _, err = DeriveCEK(priv, "A256GCM", nil, nil)
+		require.EqualError(t, err, "deriveCEK: recipient key must be a public key, got *ecdsa.PrivateKey")
+
+		_, _, err = DeriveCEK(&jwk.JWK{JSONWebKey: jose.JSONWebKey{Key: &privKey.PublicKey}, Kty: "EC",
+			Crv: "secp256k1"}, "A256GCM", nil, nil)
+		require.EqualError(t, err, "deriveCEK: unsupported recipient key type 'EC' and curve 'secp256k1'")
+
+		_, epk, err := DeriveCEK(pub, "A256GCM", nil, nil)
+		require.NoError(t, err)
+
+		_, err = RecoverCEK(priv, epk, "A999GCM", nil, nil)
+		require.EqualError(t, err, "recoverCEK: unsupported content encryption algorithm 'A999GCM'")
+
+		_, err = RecoverCEK(priv, nil, "A256GCM", nil, nil)
+		require.EqualError(t, err, "recoverCEK: recipient key and epk are required")
+
+		_, err = RecoverCEK(priv, priv, "A256GCM", nil, nil)
+		require.ErrorIs(t, err, ErrEPKPrivateKey)
+
+		otherKey, err := ecdsa.GenerateKey(elliptic.P384(), rand.Reader)
+		require.NoError(t, err)
+
+		_, err = RecoverCEK(&jwk.JWK{JSONWebKey: jose.JSONWebKey{Key: otherKey}, Kty: "EC", Crv: "P-384"}, epk,
+			"A256GCM", nil, nil)
+		require.EqualError(t, err, "recoverCEK: epk curve 'P-256' does not match recipient key curve 'P-384'")
+
+		_, err = RecoverCEK(pub, epk, "A256GCM", nil, nil)
+		require.EqualError(t, err, "recoverCEK: unsupported recipient private key type *ecdsa.PublicKey")
+	})
+}
@@ -0,0 +1,47 @@
+/*
+Copyright Gen Digital Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package jose
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/base64"
+	"fmt"
+)
+
+// keyCommitmentLabel is the HMAC input of the A256GCM-KC key commitment.
+const keyCommitmentLabel = "A256GCM-KC key commitment"
+
+// keyCommitment computes the commitment of cek as HMAC-SHA256(cek, keyCommitmentLabel). AES-GCM is not key-committing:
+// a ciphertext can be crafted to decrypt successfully under different keys, which matters when the same JWE is sent
+// to multiple recipients. The commitment binds the JWE to a single content encryption key.
+func keyCommitment(cek []byte) []byte {
+	mac := hmac.New(sha256.New, cek)
+	mac.Write([]byte(keyCommitmentLabel))
+
+	return mac.Sum(nil)
+}
+
+// verifyKeyCommitment verifies the key commitment protected header of an A256GCM-KC JWE matches cek.
+func verifyKeyCommitment(protectedHeaders Headers, cek []byte) error {
+	kc, ok := protectedHeaders.stringValue(HeaderKeyCommitment)
+	if !ok {
+		return fmt.Errorf("%s header is required with %s content encryption", HeaderKeyCommitment, A256GCMKC)
+	}
+
+	commitment, err := base64.RawURLEncoding.DecodeString(kc)
+	if err != nil {
+		return fmt.Errorf("decode %s header: %w", HeaderKeyCommitment, err)
+	}
+
+	if !hmac.Equal(commitment, keyCommitment(cek)) {
+		// the CEK is not the committed one, fail as an invalid wrapped CEK does.
+		return errCEKUnwrap
+	}
+
+	return nil
+}
@@ -0,0 +1,107 @@
+/*
+Copyright Gen Digital Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package jose
+
+import (
+	"crypto/aes"
+	"encoding/base64"
+	"errors"
+	"fmt"
+
+	josecipher "github.com/go-jose/go-jose/v3/cipher"
+)
+
+// HeaderWrappedCEK is the base64url encoded content encryption key wrapped with AES-KW under a master CEK. The master
+// CEK is the key wrapped for each recipient: recipients unwrap the master CEK first, then the content encryption key.
+const HeaderWrappedCEK = "wcek" // string
+
+// WrapCEKUnderMaster wraps sectionCEK with AES-KW (RFC 3394) under masterCEK, which must be a 16, 24 or 32 bytes AES
+// key. This allows a single master CEK wrapped once per recipient to protect many section CEKs.
+func WrapCEKUnderMaster(sectionCEK, masterCEK []byte) ([]byte, error) {
+	block, err := aes.NewCipher(masterCEK)
+	if err != nil {
+		return nil, fmt.Errorf("wrapCEKUnderMaster: invalid master CEK: %w", err)
+	}
+
+	wrapped, err := josecipher.KeyWrap(block, sectionCEK)
+	if err != nil {
+		return nil, fmt.Errorf("wrapCEKUnderMaster: %w", err)
+	}
+
+	return wrapped, nil
+}
+
+// UnwrapCEKUnderMaster unwraps a section CEK wrapped by WrapCEKUnderMaster under masterCEK.
+func UnwrapCEKUnderMaster(wrappedCEK, masterCEK []byte) ([]byte, error) {
+	block, err := aes.NewCipher(masterCEK)
+	if err != nil {
+		return nil, fmt.Errorf("unwrapCEKUnderMaster: invalid master CEK: %w", err)
+	}
+
+	sectionCEK, err := josecipher.KeyUnwrap(block, wrappedCEK)
+	if err != nil {
+		return nil, fmt.Errorf("unwrapCEKUnderMaster: %w", err)
+	}
+
+	return sectionCEK, nil
+}
+
+// EncryptWithMasterCEK encrypts plaintext with AAD using sectionCEK as the content encryption key. masterCEK is wrapped
+// for each recipient instead of sectionCEK, which is wrapped under masterCEK in the HeaderWrappedCEK protected header.
+// JWEDecrypt.Decrypt unwraps both keys transparently. The caller should clear masterCEK once all sections are
+// encrypted. Direct Key Agreement and authcrypt (ECDH-1PU) are not supported.
+func (je *JWEEncrypt) EncryptWithMasterCEK(plaintext, aad, sectionCEK, masterCEK []byte) (*JSONWebEncryption, error) {
+	if je.direct {
+		return nil, errors.New("jweencryptWithMasterCEK: direct key agreement has no wrapped key")
+	}
+
+	if je.senderKH != nil && je.skid != "" {
+		return nil, errors.New("jweencryptWithMasterCEK: authcrypt (ECDH-1PU) is not supported")
+	}
+
+	if len(sectionCEK) != cekSize(je.encAlg) {
+		return nil, fmt.Errorf("jweencryptWithMasterCEK: section CEK size %d is invalid for %s, expected %d",
+			len(sectionCEK), je.encAlg, cekSize(je.encAlg))
+	}
+
+	wrappedCEK, err := WrapCEKUnderMaster(sectionCEK, masterCEK)
+	if err != nil {
+		return nil, fmt.Errorf("jweencryptWithMasterCEK: %w", err)
+	}
+
+	protectedHeaders, plaintext, err := je.buildProtectedHeaders(plaintext)
+	if err != nil {
+		return nil, fmt.Errorf("jweencryptWithMasterCEK: %w", err)
+	}
+
+	protectedHeaders[HeaderWrappedCEK] = base64.RawURLEncoding.EncodeToString(wrappedCEK)
+
+	return je.encryptWithCEK(protectedHeaders, plaintext, aad, sectionCEK, masterCEK)
+}
+
+// unwrapSectionCEK returns the section CEK wrapped in the HeaderWrappedCEK protected header under masterCEK, or
+// masterCEK itself if the header is not set. masterCEK is cleared once the section CEK is unwrapped.
+func unwrapSectionCEK(protectedHeaders Headers, masterCEK []byte) ([]byte, error) {
+	wcek, ok := protectedHeaders.stringValue(HeaderWrappedCEK)
+	if !ok {
+		return masterCEK, nil
+	}
+
+	defer clear(masterCEK)
+
+	wrappedCEK, err := base64.RawURLEncoding.DecodeString(wcek)
+	if err != nil {
+		return nil, fmt.Errorf("decode %s header: %w", HeaderWrappedCEK, err)
+	}
+
+	sectionCEK, err := UnwrapCEKUnderMaster(wrappedCEK, masterCEK)
+	if err != nil {
+		return nil, errCEKUnwrap
+	}
+
+	return sectionCEK, nil
+}
@@ -0,0 +1,70 @@
+/*
+Copyright Gen Digital Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package jose
+
+import (
+	"encoding/base64"
+	"testing"
+
+	"github.com/google/tink/go/subtle/random"
+	"github.com/stretchr/testify/require"
+)
+
+func TestWrapCEKUnderMaster(t *testing.T) {
+	for _, masterSize := range []uint32{16, 24, 32} {
+		masterCEK := random.GetRandomBytes(masterSize)
+		sectionCEK := random.GetRandomBytes(64)
+
+		wrapped, err := WrapCEKUnderMaster(sectionCEK, masterCEK)
+		require.NoError(t, err)
+		require.Len(t, wrapped, len(sectionCEK)+8)
+
+		unwrapped, err := UnwrapCEKUnderMaster(wrapped, masterCEK)
+		require.NoError(t, err)
+		require.Equal(t, sectionCEK, unwrapped)
+
+		_, err = UnwrapCEKUnderMaster(wrapped, random.GetRandomBytes(masterSize))
+		require.EqualError(t, err, "unwrapCEKUnderMaster: go-jose/go-jose: failed to unwrap key")
+	}
+
+	t.Run("invalid master CEK size", func(t *testing.T) {
+		_, err := WrapCEKUnderMaster(random.GetRandomBytes(32), random.GetRandomBytes(20))
+		require.EqualError(t, err, "wrapCEKUnderMaster: invalid master CEK: crypto/aes: invalid key size 20")
+
+		_, err = UnwrapCEKUnderMaster(random.GetRandomBytes(40), random.GetRandomBytes(20))
+		require.EqualError(t, err, "unwrapCEKUnderMaster: invalid master CEK: crypto/aes: invalid key size 20")
+	})
+
+	t.Run("invalid section CEK size", func(t *testing.T) {
+		_, err := WrapCEKUnderMaster(random.GetRandomBytes(20), random.GetRandomBytes(32))
+		require.ErrorContains(t, err, "wrapCEKUnderMaster: ")
+	})
+}
+
+func TestUnwrapSectionCEK(t *testing.T) {
+	masterCEK := random.GetRandomBytes(32)
+
+	t.Run("no wrapped CEK header returns the master CEK", func(t *testing.T) {
+		cek, err := unwrapSectionCEK(Headers{}, masterCEK)
+		require.NoError(t, err)
+		require.Equal(t, masterCEK, cek)
+	})
+
+	t.Run("master CEK is cleared after unwrapping", func(t *testing.T) {
+		sectionCEK := random.GetRandomBytes(32)
+
+		wrapped, err := WrapCEKUnderMaster(sectionCEK, masterCEK)
+		require.NoError(t, err)
+
+		master := append([]byte{}, masterCEK...)
+
+		cek, err := unwrapSectionCEK(Headers{HeaderWrappedCEK: base64.RawURLEncoding.EncodeToString(wrapped)}, master)
+		require.NoError(t, err)
+		require.Equal(t, sectionCEK, cek)
+		require.Equal(t, make([]byte, len(master)), master)
+	})
+}
@@ -0,0 +1,149 @@
+/*
+Copyright Gen Digital Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package jose
+
+import (
+	"crypto/aes"
+	"crypto/sha256"
+	"crypto/sha512"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"hash"
+	"math"
+
+	josecipher "github.com/go-jose/go-jose/v3/cipher"
+	"golang.org/x/crypto/pbkdf2"
+)
+
+const (
+	// HeaderPBES2Salt is the base64url encoded PBES2 salt input (RFC 7518 section 4.8.1.1).
+	HeaderPBES2Salt = "p2s" // string
+	// HeaderPBES2Count is the PBES2 PBKDF2 iteration count (RFC 7518 section 4.8.1.2).
+	HeaderPBES2Count = "p2c" // number
+
+	// DefaultMinPBES2Count is the default minimum PBES2 iteration count accepted by JWEDecrypt, as recommended by
+	// RFC 7518 section 4.8.1.2.
+	DefaultMinPBES2Count = 1000
+
+	// maxPBES2Count bounds the PBKDF2 work a JWE can ask the decrypter for, as go-jose does.
+	maxPBES2Count = 1000000
+	// minPBES2SaltSize is the minimum salt input size of RFC 7518 section 4.8.1.1.
+	minPBES2SaltSize = 8
+)
+
+// ErrPBES2CountTooLow is returned by JWEDecrypt.Decrypt for PBES2 JWEs whose p2c header is below the minimum iteration
+// count set with WithMinPBES2Count, as a low count makes the password easier to brute force.
+var ErrPBES2CountTooLow = errors.New("PBES2 iteration count is too low")
+
+// pbes2Alg holds the PBKDF2 hash and the AES-KW key size of a PBES2 key management algorithm.
+type pbes2Alg struct {
+	hash    func() hash.Hash
+	keySize int
+}
+
+// pbes2Algs are the PBES2 key management algorithms of RFC 7518 section 4.8.
+var pbes2Algs = map[string]pbes2Alg{ //nolint:gochecknoglobals
+	"PBES2-HS256+A128KW": {hash: sha256.New, keySize: 16},    //nolint:gomnd
+	"PBES2-HS384+A192KW": {hash: sha512.New384, keySize: 24}, //nolint:gomnd
+	"PBES2-HS512+A256KW": {hash: sha512.New, keySize: 32},    //nolint:gomnd
+}
+
+func isPBES2(alg string) bool {
+	_, ok := pbes2Algs[alg]
+
+	return ok
+}
+
+// unwrapPBES2CEK derives the key wrapping key of the PBES2 JWE jwe from the decrypter's password and the p2s and p2c
+// protected headers, and unwraps the CEK of its recipient.
+func (jd *JWEDecrypt) unwrapPBES2CEK(jwe *JSONWebEncryption, alg string) ([]byte, error) {
+	if len(jd.pbes2Password) == 0 {
+		return nil, fmt.Errorf("'%s' key management requires a password, see WithPBES2Password", alg)
+	}
+
+	if len(jwe.Recipients) != 1 {
+		return nil, fmt.Errorf("'%s' key management requires a single recipient", alg)
+	}
+
+	p2c, err := pbes2Count(jwe.ProtectedHeaders)
+	if err != nil {
+		return nil, err
+	}
+
+	if p2c < jd.minPBES2Count {
+		return nil, fmt.Errorf("%w: %s %d is below the minimum of %d", ErrPBES2CountTooLow, HeaderPBES2Count, p2c,
+			jd.minPBES2Count)
+	}
+
+	if p2c > maxPBES2Count {
+		return nil, fmt.Errorf("%s %d exceeds the maximum of %d", HeaderPBES2Count, p2c, maxPBES2Count)
+	}
+
+	p2s, ok := jwe.ProtectedHeaders.stringValue(HeaderPBES2Salt)
+	if !ok {
+		return nil, fmt.Errorf("jwe is missing PBES2 salt '%s' header", HeaderPBES2Salt)
+	}
+
+	saltInput, err := base64.RawURLEncoding.DecodeString(p2s)
+	if err != nil {
+		return nil, fmt.Errorf("decode %s header: %w", HeaderPBES2Salt, err)
+	}
+
+	if len(saltInput) < minPBES2SaltSize {
+		return nil, fmt.Errorf("%s header must be at least %d bytes long", HeaderPBES2Salt, minPBES2SaltSize)
+	}
+
+	// RFC 7518 section 4.8.1.1: the salt is the UTF8(alg) || 0x00 || salt input.
+	salt := make([]byte, 0, len(alg)+1+len(saltInput))
+	salt = append(append(append(salt, alg...), 0), saltInput...)
+
+	kek := pbkdf2.Key(jd.pbes2Password, salt, p2c, pbes2Algs[alg].keySize, pbes2Algs[alg].hash)
+	defer clear(kek)
+
+	block, err := aes.NewCipher(kek)
+	if err != nil {
+		return nil, err
+	}
+
+	cek, err := josecipher.KeyUnwrap(block, []byte(jwe.Recipients[0].EncryptedKey))
+	if err != nil {
+		return nil, errCEKUnwrap
+	}
+
+	return cek, nil
+}
+
+// pbes2Count returns the p2c header of headers, a positive integer.
+func pbes2Count(headers Headers) (int, error) {
+	var count float64
+
+	switch v := headers[HeaderPBES2Count].(type) {
+	case nil:
+		return 0, fmt.Errorf("jwe is missing PBES2 count '%s' header", HeaderPBES2Count)
+	case float64:
+		count = v
+	case int:
+		count = float64(v)
+	case json.Number:
+		n, err := v.Float64()
+		if err != nil {
+			return 0, fmt.Errorf("invalid %s header: %w", HeaderPBES2Count, err)
+		}
+
+		count = n
+	default:
+		return 0, fmt.Errorf("invalid %s header type %T", HeaderPBES2Count, v)
+	}
+
+	if count < 1 || count != math.Trunc(count) || count > math.MaxInt32 {
+		return 0, fmt.Errorf("invalid %s header %v: must be a positive integer", HeaderPBES2Count, count)
+	}
+
+	return int(count), nil
+}
@@ -0,0 +1,93 @@
+/*
+Copyright Gen Digital Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package jose
+
+import (
+	"testing"
+
+	"github.com/go-jose/go-jose/v3"
+	"github.com/stretchr/testify/require"
+)
+
+func TestJWEDecryptPBES2(t *testing.T) {
+	password := []byte("correct horse battery staple")
+	plaintext := []byte("secret message")
+
+	encrypt := func(t *testing.T, alg jose.KeyAlgorithm, count int) *JSONWebEncryption {
+		t.Helper()
+
+		encrypter, err := jose.NewEncrypter(jose.A256GCM, jose.Recipient{
+			Algorithm: alg, Key: password, PBES2Count: count,
+		}, nil)
+		require.NoError(t, err)
+
+		jwe, err := encrypter.Encrypt(plaintext)
+		require.NoError(t, err)
+
+		serialized, err := jwe.CompactSerialize()
+		require.NoError(t, err)
+
+		parsed, err := Deserialize(serialized)
+		require.NoError(t, err)
+
+		return parsed
+	}
+
+	t.Run("success", func(t *testing.T) {
+		for _, alg := range []jose.KeyAlgorithm{
+			jose.PBES2_HS256_A128KW, jose.PBES2_HS384_A192KW, jose.PBES2_HS512_A256KW,
+		} {
+			decrypted, err := NewJWEDecrypt(nil, nil, nil, WithPBES2Password(password)).Decrypt(
+				encrypt(t, alg, DefaultMinPBES2Count))
+			require.NoError(t, err, alg)
+			require.Equal(t, plaintext, decrypted, alg)
+		}
+	})
+
+	t.Run("iteration count below the minimum", func(t *testing.T) {
+		_, err := NewJWEDecrypt(nil, nil, nil, WithPBES2Password(password)).Decrypt(
+			encrypt(t, jose.PBES2_HS512_A256KW, 999))
+		require.ErrorIs(t, err, ErrPBES2CountTooLow)
+		require.EqualError(t, err, "jwedecrypt: PBES2 iteration count is too low: p2c 999 is below the minimum of 1000")
+
+		jwe := encrypt(t, jose.PBES2_HS512_A256KW, 2000)
+
+		_, err = NewJWEDecrypt(nil, nil, nil, WithPBES2Password(password), WithMinPBES2Count(10000)).Decrypt(jwe)
+		require.ErrorIs(t, err, ErrPBES2CountTooLow)
+
+		_, err = NewJWEDecrypt(nil, nil, nil, WithPBES2Password(password), WithMinPBES2Count(2000)).Decrypt(jwe)
+		require.NoError(t, err)
+	})
+
+	t.Run("failures", func(t *testing.T) {
+		jwe := encrypt(t, jose.PBES2_HS512_A256KW, DefaultMinPBES2Count)
+
+		_, err := NewJWEDecrypt(nil, nil, nil).Decrypt(jwe)
+		require.EqualError(t, err,
+			"jwedecrypt: 'PBES2-HS512+A256KW' key management requires a password, see WithPBES2Password")
+
+		_, err = NewJWEDecrypt(nil, nil, nil, WithPBES2Password([]byte("wrong"))).Decrypt(jwe)
+		require.ErrorIs(t, err, ErrDecryptionFailed)
+
+		jwe.ProtectedHeaders[HeaderPBES2Count] = 1.5
+		_, err = NewJWEDecrypt(nil, nil, nil, WithPBES2Password(password)).Decrypt(jwe)
+		require.EqualError(t, err, "jwedecrypt: invalid p2c header 1.5: must be a positive integer")
+
+		jwe.ProtectedHeaders[HeaderPBES2Count] = maxPBES2Count + 1
+		_, err = NewJWEDecrypt(nil, nil, nil, WithPBES2Password(password)).Decrypt(jwe)
+		require.EqualError(t, err, "jwedecrypt: p2c 1000001 exceeds the maximum of 1000000")
+
+		jwe.ProtectedHeaders[HeaderPBES2Count] = DefaultMinPBES2Count
+		jwe.ProtectedHeaders[HeaderPBES2Salt] = "c2FsdA"
+		_, err = NewJWEDecrypt(nil, nil, nil, WithPBES2Password(password)).Decrypt(jwe)
+		require.EqualError(t, err, "jwedecrypt: p2s header must be at least 8 bytes long")
+
+		delete(jwe.ProtectedHeaders, HeaderPBES2Salt)
+		_, err = NewJWEDecrypt(nil, nil, nil, WithPBES2Password(password)).Decrypt(jwe)
+		require.EqualError(t, err, "jwedecrypt: jwe is missing PBES2 salt 'p2s' header")
+	})
+}
@@ -0,0 +1,195 @@
+/*
+Copyright Gen Digital Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package jose
+
+import (
+	"crypto/ecdsa"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/google/tink/go/keyset"
+	"golang.org/x/crypto/curve25519"
+
+	"github.com/dellekappa/kms-go/crypto/tinkcrypto"
+	"github.com/dellekappa/kms-go/crypto/tinkcrypto/primitive/composite/ecdh"
+	"github.com/dellekappa/kms-go/crypto/tinkcrypto/primitive/composite/keyio"
+	ecdhpb "github.com/dellekappa/kms-go/crypto/tinkcrypto/primitive/proto/ecdh_aead_go_proto"
+	"github.com/dellekappa/kms-go/doc/jose/jwk"
+	cryptoapi "github.com/dellekappa/kms-go/spi/crypto"
+	"github.com/dellekappa/kms-go/spi/kms"
+)
+
+// Reencrypt decrypts the serialized JWE jwe with ourPriv and encrypts its plaintext again to newRecipient, e.g. in a
+// forwarding proxy, without parsing the plaintext. The "enc", "typ", "cty" and "zip" headers and the AAD of jwe are
+// preserved, the CEK is wrapped for newRecipient with ECDH-ES key wrapping, or derived with ECDH-ES Direct Key
+// Agreement if jwe used it. The result is compact serialized if jwe was, JSON serialized otherwise.
+//
+// ourPriv is an EC private key JWK on P-256, P-384 or P-521 or an X25519 JWK whose Key is its 32 bytes private key (as
+// for RecoverCEK), newRecipient an EC or X25519 public key JWK. With multiple recipients, ourPriv's kid selects the
+// recipient to decrypt as, it must match the kid of one of them. Authcrypt (ECDH-1PU) JWEs are not supported as the
+// sender key can't be resolved.
+func Reencrypt(jwe []byte, ourPriv *jwk.JWK, newRecipient *jwk.JWK) ([]byte, error) {
+	parsedJWE, err := Deserialize(string(jwe))
+	if err != nil {
+		return nil, fmt.Errorf("reencrypt: %w", err)
+	}
+
+	err = checkOurRecipient(parsedJWE, ourPriv)
+	if err != nil {
+		return nil, fmt.Errorf("reencrypt: %w", err)
+	}
+
+	ourKH, err := recipientKeyHandle(ourPriv)
+	if err != nil {
+		return nil, fmt.Errorf("reencrypt: %w", err)
+	}
+
+	newRecPubKey, err := recipientAgreementKey(newRecipient)
+	if err != nil {
+		return nil, fmt.Errorf("reencrypt: %w", err)
+	}
+
+	newRecPubKey.KID = newRecipient.KeyID
+
+	c, err := tinkcrypto.New()
+	if err != nil {
+		return nil, fmt.Errorf("reencrypt: %w", err)
+	}
+
+	// read the headers to preserve before Decrypt adds the epk of the recipient to the protected headers.
+	encAlg, _ := parsedJWE.ProtectedHeaders.Encryption()
+	typ, _ := parsedJWE.ProtectedHeaders.Type()
+	cty, _ := parsedJWE.ProtectedHeaders.ContentType()
+	alg, _ := parsedJWE.ProtectedHeaders.Algorithm()
+	_, compressed := parsedJWE.ProtectedHeaders.Compression()
+	aad := []byte(parsedJWE.AAD)
+
+	recKM := &recipientKeyManager{kh: ourKH}
+
+	if len(parsedJWE.Recipients) > 1 {
+		recKM.kid = ourPriv.KeyID
+	}
+
+	plaintext, err := NewJWEDecrypt(nil, c, recKM).Decrypt(parsedJWE)
+	if err != nil {
+		return nil, fmt.Errorf("reencrypt: %w", err)
+	}
+
+	encOpts := []JWEEncryptOpt{WithCompression(compressed)}
+
+	if alg == tinkcrypto.ECDHESAlg {
+		encOpts = append(encOpts, WithDirectKeyAgreement())
+	}
+
+	jweEncrypter, err := NewJWEEncrypt(EncAlg(encAlg), typ, cty, "", nil, []*cryptoapi.PublicKey{newRecPubKey}, c,
+		encOpts...)
+	if err != nil {
+		return nil, fmt.Errorf("reencrypt: %w", err)
+	}
+
+	newJWE, err := jweEncrypter.EncryptWithAuthData(plaintext, aad)
+	if err != nil {
+		return nil, fmt.Errorf("reencrypt: %w", err)
+	}
+
+	var serializedJWE string
+
+	if strings.HasPrefix(strings.TrimSpace(string(jwe)), "{") {
+		serializedJWE, err = newJWE.FullSerialize(json.Marshal)
+	} else {
+		serializedJWE, err = newJWE.CompactSerialize(json.Marshal)
+	}
+
+	if err != nil {
+		return nil, fmt.Errorf("reencrypt: %w", err)
+	}
+
+	return []byte(serializedJWE), nil
+}
+
+// checkOurRecipient checks ourPriv identifies one of the recipients of a multi-recipient jwe by its kid.
+func checkOurRecipient(jwe *JSONWebEncryption, ourPriv *jwk.JWK) error {
+	if ourPriv == nil {
+		return errors.New("our private key is required")
+	}
+
+	if len(jwe.Recipients) < 2 { //nolint:gomnd
+		return nil
+	}
+
+	if ourPriv.KeyID == "" {
+		return errors.New("the kid of our private key is required to select our recipient in a multi-recipient jwe")
+	}
+
+	for _, rec := range jwe.Recipients {
+		if rec.Header != nil && rec.Header.KID == ourPriv.KeyID {
+			return nil
+		}
+	}
+
+	return fmt.Errorf("no recipient with kid '%s'", ourPriv.KeyID)
+}
+
+// recipientKeyHandle converts the recipient private key ourPriv into an ECDH key wrapping handle to unwrap CEKs.
+func recipientKeyHandle(ourPriv *jwk.JWK) (*keyset.Handle, error) {
+	var privKey *cryptoapi.PrivateKey
+
+	switch key := ourPriv.Key.(type) {
+	case *ecdsa.PrivateKey:
+		privKey = &cryptoapi.PrivateKey{
+			PublicKey: cryptoapi.PublicKey{
+				Type:  ecdhpb.KeyType_EC.String(),
+				Curve: key.Curve.Params().Name,
+				X:     key.X.Bytes(),
+				Y:     key.Y.Bytes(),
+			},
+			D: key.D.Bytes(),
+		}
+	case []byte:
+		if ourPriv.Kty != okpKty || ourPriv.Crv != x25519Curve || len(key) != x25519KeySize {
+			return nil, fmt.Errorf("unsupported private key type '%s' and curve '%s'", ourPriv.Kty, ourPriv.Crv)
+		}
+
+		pubKey, err := curve25519.X25519(key, curve25519.Basepoint)
+		if err != nil {
+			return nil, fmt.Errorf("derive X25519 public key: %w", err)
+		}
+
+		privKey = &cryptoapi.PrivateKey{
+			PublicKey: cryptoapi.PublicKey{
+				Type:  ecdhpb.KeyType_OKP.String(),
+				Curve: x25519Curve,
+				X:     pubKey,
+			},
+			D: key,
+		}
+	default:
+		return nil, fmt.Errorf("our key must be an EC or X25519 private key, got %T", ourPriv.Key)
+	}
+
+	privKey.PublicKey.KID = ourPriv.KeyID
+
+	return keyio.PrivateKeyToKeysetHandle(privKey, ecdh.AES256GCM)
+}
+
+// recipientKeyManager is the kms.KeyManager of Reencrypt, it only gets the handle of our key, by kid if set (i.e. for
+// multi-recipient JWEs). The other kms.KeyManager methods are not implemented as JWEDecrypt doesn't call them.
+type recipientKeyManager struct {
+	kms.KeyManager
+	kid string
+	kh  *keyset.Handle
+}
+
+func (m *recipientKeyManager) Get(keyID string) (interface{}, error) {
+	if m.kid != "" && keyID != m.kid {
+		return nil, fmt.Errorf("key '%s' not found", keyID)
+	}
+
+	return m.kh, nil
+}
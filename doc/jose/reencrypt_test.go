@@ -0,0 +1,176 @@
+/*
+Copyright Gen Digital Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package jose
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"github.com/go-jose/go-jose/v3"
+	"github.com/stretchr/testify/require"
+	"golang.org/x/crypto/curve25519"
+
+	"github.com/dellekappa/kms-go/crypto/tinkcrypto"
+	"github.com/dellekappa/kms-go/doc/jose/jwk"
+	cryptoapi "github.com/dellekappa/kms-go/spi/crypto"
+)
+
+func TestReencrypt(t *testing.T) {
+	c, err := tinkcrypto.New()
+	require.NoError(t, err)
+
+	newECKey := func(t *testing.T, kid string, curve elliptic.Curve) *jwk.JWK {
+		t.Helper()
+
+		privKey, e := ecdsa.GenerateKey(curve, rand.Reader)
+		require.NoError(t, e)
+
+		return &jwk.JWK{JSONWebKey: jose.JSONWebKey{Key: privKey, KeyID: kid}, Kty: "EC", Crv: curve.Params().Name}
+	}
+
+	newX25519Key := func(t *testing.T, kid string) (*jwk.JWK, *jwk.JWK) {
+		t.Helper()
+
+		privKey := make([]byte, curve25519.ScalarSize)
+		_, e := rand.Read(privKey)
+		require.NoError(t, e)
+
+		pubKey, e := curve25519.X25519(privKey, curve25519.Basepoint)
+		require.NoError(t, e)
+
+		return &jwk.JWK{JSONWebKey: jose.JSONWebKey{Key: privKey, KeyID: kid}, Kty: "OKP", Crv: "X25519"},
+			&jwk.JWK{JSONWebKey: jose.JSONWebKey{Key: pubKey, KeyID: kid}, Kty: "OKP", Crv: "X25519"}
+	}
+
+	recipientKey := func(t *testing.T, pub *jwk.JWK) *cryptoapi.PublicKey {
+		t.Helper()
+
+		recPubKey, e := recipientAgreementKey(pub)
+		require.NoError(t, e)
+
+		recPubKey.KID = pub.KeyID
+
+		return recPubKey
+	}
+
+	decrypt := func(t *testing.T, serializedJWE []byte, priv *jwk.JWK) (*JSONWebEncryption, []byte) {
+		t.Helper()
+
+		parsedJWE, e := Deserialize(string(serializedJWE))
+		require.NoError(t, e)
+
+		kh, e := recipientKeyHandle(priv)
+		require.NoError(t, e)
+
+		pt, e := NewJWEDecrypt(nil, c, &recipientKeyManager{kid: priv.KeyID, kh: kh}).Decrypt(parsedJWE)
+		require.NoError(t, e)
+
+		return parsedJWE, pt
+	}
+
+	pt := []byte("forwarded message")
+
+	ours := newECKey(t, "ours", elliptic.P256())
+	downstream := newECKey(t, "downstream", elliptic.P384())
+
+	t.Run("compact single recipient JWE", func(t *testing.T) {
+		for _, opts := range [][]JWEEncryptOpt{nil, {WithDirectKeyAgreement()}, {WithCompression(true)}} {
+			jweEncrypter, e := NewJWEEncrypt(A128CBCHS256, "application/example+jwe", "JWT", "", nil,
+				[]*cryptoapi.PublicKey{recipientKey(t, ours.Public())}, c, opts...)
+			require.NoError(t, e)
+
+			jwe, e := jweEncrypter.Encrypt(pt)
+			require.NoError(t, e)
+
+			jweCompact, e := jwe.CompactSerialize(json.Marshal)
+			require.NoError(t, e)
+
+			reencrypted, e := Reencrypt([]byte(jweCompact), ours, downstream.Public())
+			require.NoError(t, e)
+			require.Len(t, strings.Split(string(reencrypted), "."), 5)
+
+			newJWE, msg := decrypt(t, reencrypted, downstream)
+			require.Equal(t, pt, msg)
+
+			jwe, e = Deserialize(jweCompact)
+			require.NoError(t, e)
+
+			for _, header := range []string{HeaderEncryption, HeaderAlgorithm, HeaderType, HeaderContentType,
+				HeaderCompression} {
+				require.Equal(t, jwe.ProtectedHeaders[header], newJWE.ProtectedHeaders[header], header)
+			}
+
+			require.Equal(t, "downstream", newJWE.ProtectedHeaders[HeaderKeyID])
+		}
+	})
+
+	t.Run("multi-recipient JWE", func(t *testing.T) {
+		oursX25519, oursX25519Pub := newX25519Key(t, "ours-x25519")
+		_, otherPub := newX25519Key(t, "other")
+
+		jweEncrypter, e := NewJWEEncrypt(XC20P, "", "", "", nil,
+			[]*cryptoapi.PublicKey{recipientKey(t, otherPub), recipientKey(t, oursX25519Pub)}, c)
+		require.NoError(t, e)
+
+		jwe, e := jweEncrypter.EncryptWithAuthData(pt, []byte("aad"))
+		require.NoError(t, e)
+
+		jweJSON, e := jwe.FullSerialize(json.Marshal)
+		require.NoError(t, e)
+
+		reencrypted, e := Reencrypt([]byte(jweJSON), oursX25519, downstream.Public())
+		require.NoError(t, e)
+		require.True(t, strings.HasPrefix(string(reencrypted), "{"))
+
+		newJWE, msg := decrypt(t, reencrypted, downstream)
+		require.Equal(t, pt, msg)
+		require.Equal(t, "aad", newJWE.AAD)
+		require.EqualValues(t, XC20P, newJWE.ProtectedHeaders[HeaderEncryption])
+
+		oursX25519.KeyID = ""
+
+		_, e = Reencrypt([]byte(jweJSON), oursX25519, downstream.Public())
+		require.EqualError(t, e, "reencrypt: the kid of our private key is required to select our recipient in a "+
+			"multi-recipient jwe")
+
+		oursX25519.KeyID = "unknown"
+
+		_, e = Reencrypt([]byte(jweJSON), oursX25519, downstream.Public())
+		require.EqualError(t, e, "reencrypt: no recipient with kid 'unknown'")
+	})
+
+	t.Run("failures", func(t *testing.T) {
+		jweEncrypter, e := NewJWEEncrypt(A256GCM, "", "", "", nil,
+			[]*cryptoapi.PublicKey{recipientKey(t, ours.Public())}, c)
+		require.NoError(t, e)
+
+		jwe, e := jweEncrypter.Encrypt(pt)
+		require.NoError(t, e)
+
+		jweCompact, e := jwe.CompactSerialize(json.Marshal)
+		require.NoError(t, e)
+
+		_, e = Reencrypt([]byte("not a jwe"), ours, downstream.Public())
+		require.ErrorContains(t, e, "reencrypt: ")
+
+		_, e = Reencrypt([]byte(jweCompact), nil, downstream.Public())
+		require.EqualError(t, e, "reencrypt: our private key is required")
+
+		_, e = Reencrypt([]byte(jweCompact), ours.Public(), downstream.Public())
+		require.EqualError(t, e, "reencrypt: our key must be an EC or X25519 private key, got *ecdsa.PublicKey")
+
+		_, e = Reencrypt([]byte(jweCompact), ours, downstream)
+		require.EqualError(t, e, "reencrypt: recipient key must be a public key, got *ecdsa.PrivateKey")
+
+		_, e = Reencrypt([]byte(jweCompact), newECKey(t, "ours", elliptic.P256()), downstream.Public())
+		require.ErrorIs(t, e, ErrDecryptionFailed)
+	})
+}
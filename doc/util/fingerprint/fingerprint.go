@@ -12,34 +12,32 @@ import (
 	"crypto/elliptic"
 	"crypto/rsa"
 	"crypto/x509"
-	"encoding/binary"
 	"errors"
 	"fmt"
 
-	"github.com/btcsuite/btcutil/base58"
-
 	"github.com/dellekappa/kms-go/doc/jose/jwk"
+	"github.com/dellekappa/kms-go/doc/util/multicodec"
 )
 
 const (
 	// X25519PubKeyMultiCodec for Curve25519 public key in multicodec table.
 	// source: https://github.com/multiformats/multicodec/blob/master/table.csv.
-	X25519PubKeyMultiCodec = 0xec
+	X25519PubKeyMultiCodec = multicodec.X25519PubKey
 	// ED25519PubKeyMultiCodec for Ed25519 public key in multicodec table.
-	ED25519PubKeyMultiCodec = 0xed
+	ED25519PubKeyMultiCodec = multicodec.ED25519PubKey
 	// BLS12381g2PubKeyMultiCodec for BLS12-381 G2 public key in multicodec table.
-	BLS12381g2PubKeyMultiCodec = 0xeb
+	BLS12381g2PubKeyMultiCodec = multicodec.BLS12381g2PubKey
 	// BLS12381g1g2PubKeyMultiCodec for BLS12-381 G1G2 public key in multicodec table.
-	BLS12381g1g2PubKeyMultiCodec = 0xee
+	BLS12381g1g2PubKeyMultiCodec = multicodec.BLS12381g1g2PubKey
 	// P256PubKeyMultiCodec for NIST P-256 public key in multicodec table.
-	P256PubKeyMultiCodec = 0x1200
+	P256PubKeyMultiCodec = multicodec.P256PubKey
 	// P384PubKeyMultiCodec for NIST P-384 public key in multicodec table.
-	P384PubKeyMultiCodec = 0x1201
+	P384PubKeyMultiCodec = multicodec.P384PubKey
 	// P521PubKeyMultiCodec for NIST P-521 public key in multicodec table.
-	P521PubKeyMultiCodec = 0x1202
+	P521PubKeyMultiCodec = multicodec.P521PubKey
 
 	// RSAPubKeyMultiCodec for RSA public key in multicodec table.
-	RSAPubKeyMultiCodec = 0x1205
+	RSAPubKeyMultiCodec = multicodec.RSAPubKey
 
 	// Default BLS 12-381 public key length in G2 field.
 	bls12381G2PublicKeyLen = 96
@@ -115,6 +113,7 @@ func CreateDIDKeyByJwk(jsonWebKey *jwk.JWK) (string, string, error) { //nolint:g
 			return "", "", fmt.Errorf("unexpected RSA key type %T", jsonWebKey.Key)
 		}
 
+		// the rsa-pub multicodec holds a PKCS#1 RSAPublicKey, unlike the PKIX bytes of JWK.PublicKeyBytes and of the KMS.
 		didKey, keyID := CreateDIDKeyByCode(RSAPubKeyMultiCodec, x509.MarshalPKCS1PublicKey(key))
 
 		return didKey, keyID, nil
@@ -150,53 +149,28 @@ func ecCodeAndCurve(ecCurve string) (uint64, elliptic.Curve, error) {
 // KeyFingerprint generates a multicode fingerprint for pubKeyValue (raw key []byte).
 // It is mainly used as the controller ID (methodSpecification ID) of a did key.
 func KeyFingerprint(code uint64, pubKeyValue []byte) string {
-	multicodecValue := multicodec(code)
-	mcLength := len(multicodecValue)
-	buf := make([]uint8, mcLength+len(pubKeyValue))
-	copy(buf, multicodecValue)
-	copy(buf[mcLength:], pubKeyValue)
-
-	return fmt.Sprintf("z%s", base58.Encode(buf))
-}
-
-func multicodec(code uint64) []byte {
-	buf := make([]byte, binary.MaxVarintLen64)
-	bw := binary.PutUvarint(buf, code)
-
-	return buf[:bw]
+	return multicodec.Encode(code, pubKeyValue)
 }
 
 // PubKeyFromFingerprint extracts the raw public key from a did:key fingerprint.
 func PubKeyFromFingerprint(fingerprint string) ([]byte, uint64, error) {
 	// did:key:MULTIBASE(base58-btc, MULTICODEC(public-key-type, raw-public-key-bytes))
 	// https://w3c-ccg.github.io/did-method-key/#format
-	const maxMulticodecBytes = 9
-
-	if len(fingerprint) < 2 || fingerprint[0] != 'z' {
-		return nil, 0, errors.New("unknown key encoding")
-	}
-
-	mc := base58.Decode(fingerprint[1:]) // skip leading "z"
-
-	code, br := binary.Uvarint(mc)
-	if br == 0 {
-		return nil, 0, errors.New("unknown key encoding")
-	}
-
-	if br > maxMulticodecBytes {
-		return nil, 0, errors.New("code exceeds maximum size")
+	pubKey, code, err := multicodec.Decode(fingerprint)
+	if err != nil {
+		return nil, 0, err
 	}
 
 	if code == BLS12381g1g2PubKeyMultiCodec {
 		// for BBS+ G1G2 did:key type, return the G2 public key only (discard G1 key for now).
-		if len(mc[br+g1CompressedSize:]) != bls12381G2PublicKeyLen {
+		if len(pubKey) < g1CompressedSize || len(pubKey[g1CompressedSize:]) != bls12381G2PublicKeyLen {
 			return nil, 0, errors.New("invalid bbs+ public key")
 		}
 
-		return mc[br+g1CompressedSize:], code, nil
+		return pubKey[g1CompressedSize:], code, nil
 	}
 
-	return mc[br:], code, nil
+	return pubKey, code, nil
 }
 
 // PubKeyFromDIDKey parses the did:key DID and returns the key's raw value.
@@ -151,8 +151,7 @@ func TestCreateKID(t *testing.T) {
 		"invalid character 'b' looking for beginning of value")
 
 	_, err = CreateKID(badPubKey, kms.ECDSAP256TypeIEEEP1363)
-	require.EqualError(t, err, "createKID: failed to build jwk: create JWK: go-jose/go-jose: "+
-		"invalid EC key (nil, or X/Y missing)")
+	require.EqualError(t, err, "createKID: failed to build jwk: parseECPoint: invalid point prefix 0x62")
 
 	ecKey, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
 	require.NoError(t, err)
@@ -0,0 +1,161 @@
+/*
+Copyright Gen Digital Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package kmssigner
+
+import (
+	"crypto"
+	"crypto/ed25519"
+	"crypto/rsa"
+	"encoding/asn1"
+	"errors"
+	"fmt"
+	"io"
+	"math/big"
+
+	"github.com/dellekappa/kms-go/doc/jose/jwk"
+	"github.com/dellekappa/kms-go/doc/jose/jwk/jwksupport"
+	"github.com/dellekappa/kms-go/spi/kms"
+	"github.com/dellekappa/kms-go/wrapper/api"
+)
+
+// KeyManager exports the public keys of the keys kept in a KMS and signs with them, as api.KMSCrypto does.
+type KeyManager interface {
+	ExportPubKeyBytes(id string) ([]byte, kms.KeyType, error)
+	FixedKeySigner(pub *jwk.JWK) (api.FixedKeySigner, error)
+}
+
+// cryptoSigner is a crypto.Signer signing with a KMS key, see NewCryptoSigner.
+type cryptoSigner struct {
+	pub     crypto.PublicKey
+	keyType kms.KeyType
+	signer  api.FixedKeySigner
+}
+
+// NewCryptoSigner returns a crypto.Signer signing with the KMS key kid of km, for the standard library APIs expecting
+// one (x509, tls). Its private key never leaves the KMS: digests are signed with the api.DigestSigner of the key,
+// Ed25519 keys sign full messages. Public returns the standard library public key (*ecdsa.PublicKey,
+// ed25519.PublicKey or *rsa.PublicKey).
+//
+// ECDSA signatures are returned ASN.1 DER encoded as crypto/ecdsa does, whatever the encoding of the KMS key type.
+// RSA signatures use the padding of the KMS key type, opts must match it: *rsa.PSSOptions with a salt as long as the
+// hash for RSAPS256 keys, PKCS #1 v1.5 otherwise.
+func NewCryptoSigner(km KeyManager, kid string) (crypto.Signer, error) {
+	signer, err := newCryptoSigner(km, kid)
+	if err != nil {
+		return nil, fmt.Errorf("newCryptoSigner: %w", err)
+	}
+
+	return signer, nil
+}
+
+func newCryptoSigner(km KeyManager, kid string) (*cryptoSigner, error) {
+	pubBytes, keyType, err := km.ExportPubKeyBytes(kid)
+	if err != nil {
+		return nil, fmt.Errorf("export public key: %w", err)
+	}
+
+	pubJWK, err := jwksupport.PubKeyBytesToJWK(pubBytes, keyType)
+	if err != nil {
+		return nil, fmt.Errorf("convert public key: %w", err)
+	}
+
+	pubJWK.KeyID = kid
+
+	signer, err := km.FixedKeySigner(pubJWK)
+	if err != nil {
+		return nil, fmt.Errorf("get signer: %w", err)
+	}
+
+	return &cryptoSigner{
+		pub:     pubJWK.Key,
+		keyType: keyType,
+		signer:  signer,
+	}, nil
+}
+
+// Public returns the public key of the KMS key.
+func (s *cryptoSigner) Public() crypto.PublicKey {
+	return s.pub
+}
+
+// Sign signs digest with the KMS key, see NewCryptoSigner. Ed25519 keys sign the full message, passed as digest with
+// a zero opts.HashFunc() as for crypto/ed25519.
+func (s *cryptoSigner) Sign(_ io.Reader, digest []byte, opts crypto.SignerOpts) ([]byte, error) {
+	switch s.pub.(type) {
+	case ed25519.PublicKey:
+		if opts.HashFunc() != crypto.Hash(0) {
+			return nil, errors.New("ed25519: cannot sign hashed message")
+		}
+
+		return s.signer.Sign(digest)
+	case *rsa.PublicKey:
+		if err := s.checkRSAOpts(opts); err != nil {
+			return nil, err
+		}
+	}
+
+	digestSigner, ok := s.signer.(api.DigestSigner)
+	if !ok {
+		return nil, fmt.Errorf("%w: %s keys can't sign digests", api.ErrNotSupported, s.keyType)
+	}
+
+	sig, err := digestSigner.SignDigest(digest)
+	if err != nil {
+		return nil, err
+	}
+
+	switch s.keyType {
+	case kms.ECDSAP256TypeIEEEP1363, kms.ECDSAP384TypeIEEEP1363, kms.ECDSAP521TypeIEEEP1363,
+		kms.ECDSASecp256k1TypeIEEEP1363:
+		return ieeeP1363ToDER(sig)
+	}
+
+	return sig, nil
+}
+
+// checkRSAOpts checks the padding and hash requested by opts are the ones of the RSA key type, as the KMS signs with
+// the padding of the key type.
+func (s *cryptoSigner) checkRSAOpts(opts crypto.SignerOpts) error {
+	if (s.keyType == kms.RSARS256Type || s.keyType == kms.RSAPS256Type) && opts.HashFunc() != crypto.SHA256 {
+		return fmt.Errorf("rsa: %s keys sign SHA-256 digests, got %s", s.keyType, opts.HashFunc())
+	}
+
+	pssOpts, isPSS := opts.(*rsa.PSSOptions)
+
+	if s.keyType != kms.RSAPS256Type {
+		if isPSS {
+			return fmt.Errorf("rsa: %s keys can't sign with PSS padding", s.keyType)
+		}
+
+		return nil
+	}
+
+	if !isPSS {
+		return fmt.Errorf("rsa: %s keys sign with PSS padding, PKCS #1 v1.5 padding was requested", s.keyType)
+	}
+
+	if pssOpts.SaltLength != rsa.PSSSaltLengthEqualsHash && pssOpts.SaltLength != crypto.SHA256.Size() {
+		return fmt.Errorf("rsa: %s keys sign with a salt as long as the hash, got salt length %d", s.keyType,
+			pssOpts.SaltLength)
+	}
+
+	return nil
+}
+
+// ieeeP1363ToDER converts the IEEE P1363 encoded ECDSA signature sig (R || S) to its ASN.1 DER encoding.
+func ieeeP1363ToDER(sig []byte) ([]byte, error) {
+	if len(sig) == 0 || len(sig)%2 != 0 {
+		return nil, fmt.Errorf("invalid IEEE P1363 signature length %d", len(sig))
+	}
+
+	return asn1.Marshal(struct {
+		R, S *big.Int
+	}{
+		R: new(big.Int).SetBytes(sig[:len(sig)/2]),
+		S: new(big.Int).SetBytes(sig[len(sig)/2:]),
+	})
+}
@@ -0,0 +1,174 @@
+/*
+Copyright Gen Digital Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package kmssigner_test
+
+import (
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
+	"crypto/sha512"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/dellekappa/kms-go/doc/jose/jwk"
+	"github.com/dellekappa/kms-go/doc/util/kmssigner"
+	kmsapi "github.com/dellekappa/kms-go/spi/kms"
+	"github.com/dellekappa/kms-go/wrapper/api"
+)
+
+// rsaKeyManager is a KeyManager of a single RSA key, signing digests with the padding of its key type.
+type rsaKeyManager struct {
+	priv    *rsa.PrivateKey
+	keyType kmsapi.KeyType
+}
+
+func (m *rsaKeyManager) ExportPubKeyBytes(string) ([]byte, kmsapi.KeyType, error) {
+	pubBytes, err := x509.MarshalPKIXPublicKey(&m.priv.PublicKey)
+
+	return pubBytes, m.keyType, err
+}
+
+func (m *rsaKeyManager) FixedKeySigner(*jwk.JWK) (api.FixedKeySigner, error) {
+	return &rsaSigner{m}, nil
+}
+
+type rsaSigner struct {
+	*rsaKeyManager
+}
+
+func (s *rsaSigner) Sign([]byte) ([]byte, error) {
+	return nil, api.ErrNotSupported
+}
+
+func (s *rsaSigner) SignDigest(digest []byte) ([]byte, error) {
+	if s.keyType == kmsapi.RSAPS256Type {
+		return rsa.SignPSS(rand.Reader, s.priv, crypto.SHA256, digest,
+			&rsa.PSSOptions{SaltLength: rsa.PSSSaltLengthEqualsHash})
+	}
+
+	return rsa.SignPKCS1v15(rand.Reader, s.priv, crypto.SHA256, digest)
+}
+
+func (s *rsaSigner) Algorithm() string {
+	return kmssigner.KeyTypeToJWA(s.keyType)
+}
+
+func (s *rsaSigner) KeyType() kmsapi.KeyType {
+	return s.keyType
+}
+
+func TestNewCryptoSigner(t *testing.T) {
+	kmsCrypto := newKMSCrypto(t)
+
+	msg := []byte("message to sign")
+	digest := sha256.Sum256(msg)
+
+	t.Run("ECDSA signatures are DER encoded", func(t *testing.T) {
+		for _, keyType := range []kmsapi.KeyType{kmsapi.ECDSAP256TypeIEEEP1363, kmsapi.ECDSAP256TypeDER} {
+			pub, err := kmsCrypto.Create(keyType)
+			require.NoError(t, err)
+
+			signer, err := kmssigner.NewCryptoSigner(kmsCrypto, pub.KeyID)
+			require.NoError(t, err)
+
+			ecPub, ok := signer.Public().(*ecdsa.PublicKey)
+			require.True(t, ok)
+			require.True(t, ecPub.Equal(pub.Key))
+
+			sig, err := signer.Sign(rand.Reader, digest[:], crypto.SHA256)
+			require.NoError(t, err)
+			require.True(t, ecdsa.VerifyASN1(ecPub, digest[:], sig), keyType)
+		}
+	})
+
+	t.Run("Ed25519 signs the full message", func(t *testing.T) {
+		pub, err := kmsCrypto.Create(kmsapi.ED25519Type)
+		require.NoError(t, err)
+
+		signer, err := kmssigner.NewCryptoSigner(kmsCrypto, pub.KeyID)
+		require.NoError(t, err)
+
+		edPub, ok := signer.Public().(ed25519.PublicKey)
+		require.True(t, ok)
+
+		sig, err := signer.Sign(rand.Reader, msg, crypto.Hash(0))
+		require.NoError(t, err)
+		require.True(t, ed25519.Verify(edPub, msg, sig))
+
+		_, err = signer.Sign(rand.Reader, digest[:], crypto.SHA256)
+		require.EqualError(t, err, "ed25519: cannot sign hashed message")
+	})
+
+	t.Run("RSA padding", func(t *testing.T) {
+		priv, err := rsa.GenerateKey(rand.Reader, 2048)
+		require.NoError(t, err)
+
+		pssOpts := &rsa.PSSOptions{SaltLength: rsa.PSSSaltLengthEqualsHash, Hash: crypto.SHA256}
+
+		signer, err := kmssigner.NewCryptoSigner(&rsaKeyManager{priv: priv, keyType: kmsapi.RSAPS256Type}, "kid")
+		require.NoError(t, err)
+		require.True(t, priv.PublicKey.Equal(signer.Public()))
+
+		sig, err := signer.Sign(rand.Reader, digest[:], pssOpts)
+		require.NoError(t, err)
+		require.NoError(t, rsa.VerifyPSS(&priv.PublicKey, crypto.SHA256, digest[:], sig, pssOpts))
+
+		_, err = signer.Sign(rand.Reader, digest[:], crypto.SHA256)
+		require.EqualError(t, err, "rsa: RSAPS256 keys sign with PSS padding, PKCS #1 v1.5 padding was requested")
+
+		_, err = signer.Sign(rand.Reader, digest[:], &rsa.PSSOptions{SaltLength: rsa.PSSSaltLengthAuto,
+			Hash: crypto.SHA256})
+		require.EqualError(t, err, "rsa: RSAPS256 keys sign with a salt as long as the hash, got salt length 0")
+
+		digest384 := sha512.Sum384(msg)
+
+		_, err = signer.Sign(rand.Reader, digest384[:], crypto.SHA384)
+		require.EqualError(t, err, "rsa: RSAPS256 keys sign SHA-256 digests, got SHA-384")
+
+		csrPEM, err := kmssigner.CreateCSR(&rsaKeyManager{priv: priv, keyType: kmsapi.RSAPS256Type}, "kid",
+			pkix.Name{CommonName: "rsa"}, nil)
+		require.NoError(t, err)
+
+		block, _ := pem.Decode(csrPEM)
+		require.NotNil(t, block)
+
+		csr, err := x509.ParseCertificateRequest(block.Bytes)
+		require.NoError(t, err)
+		require.NoError(t, csr.CheckSignature())
+
+		signer, err = kmssigner.NewCryptoSigner(&rsaKeyManager{priv: priv, keyType: kmsapi.RSARS256Type}, "kid")
+		require.NoError(t, err)
+
+		sig, err = signer.Sign(rand.Reader, digest[:], crypto.SHA256)
+		require.NoError(t, err)
+		require.NoError(t, rsa.VerifyPKCS1v15(&priv.PublicKey, crypto.SHA256, digest[:], sig))
+
+		_, err = signer.Sign(rand.Reader, digest[:], pssOpts)
+		require.EqualError(t, err, "rsa: RSARS256 keys can't sign with PSS padding")
+	})
+
+	t.Run("failures", func(t *testing.T) {
+		_, err := kmssigner.NewCryptoSigner(kmsCrypto, "unknown")
+		require.ErrorContains(t, err, "newCryptoSigner: export public key: ")
+
+		pub, err := kmsCrypto.Create(kmsapi.ECDSAP384TypeIEEEP1363)
+		require.NoError(t, err)
+
+		signer, err := kmssigner.NewCryptoSigner(kmsCrypto, pub.KeyID)
+		require.NoError(t, err)
+
+		_, err = signer.Sign(rand.Reader, digest[:], crypto.SHA256)
+		require.ErrorContains(t, err, "invalid digest length")
+	})
+}
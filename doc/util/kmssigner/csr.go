@@ -0,0 +1,45 @@
+/*
+Copyright Gen Digital Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package kmssigner
+
+import (
+	"crypto/rand"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"fmt"
+
+	"github.com/dellekappa/kms-go/spi/kms"
+)
+
+// CreateCSR creates a PKCS #10 certificate signing request for the KMS key kid of km, e.g. to get it certified by a
+// CA, and returns it PEM encoded ("CERTIFICATE REQUEST" block). The request is signed with the KMS key, its private
+// key never leaves the KMS, see NewCryptoSigner. Only the key types supported by crypto/x509 can be certified: ECDSA
+// keys on NIST P curves, Ed25519 and RSA keys.
+func CreateCSR(km KeyManager, kid string, subject pkix.Name, dnsNames []string) ([]byte, error) {
+	signer, err := newCryptoSigner(km, kid)
+	if err != nil {
+		return nil, fmt.Errorf("createCSR: %w", err)
+	}
+
+	template := &x509.CertificateRequest{
+		Subject:  subject,
+		DNSNames: dnsNames,
+	}
+
+	if signer.keyType == kms.RSAPS256Type {
+		// x509 signs RSA requests with PKCS #1 v1.5 padding by default.
+		template.SignatureAlgorithm = x509.SHA256WithRSAPSS
+	}
+
+	csr, err := x509.CreateCertificateRequest(rand.Reader, template, signer)
+	if err != nil {
+		return nil, fmt.Errorf("createCSR: %w", err)
+	}
+
+	return pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE REQUEST", Bytes: csr}), nil
+}
@@ -0,0 +1,84 @@
+/*
+Copyright Gen Digital Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package kmssigner_test
+
+import (
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/dellekappa/kms-go/doc/jose/jwk/jwksupport"
+	"github.com/dellekappa/kms-go/doc/util/kmssigner"
+	mockstorage "github.com/dellekappa/kms-go/internal/mock/storage"
+	"github.com/dellekappa/kms-go/kms"
+	"github.com/dellekappa/kms-go/secretlock/noop"
+	kmsapi "github.com/dellekappa/kms-go/spi/kms"
+	"github.com/dellekappa/kms-go/wrapper/api"
+	"github.com/dellekappa/kms-go/wrapper/localsuite"
+)
+
+func newKMSCrypto(t *testing.T) api.KMSCrypto {
+	t.Helper()
+
+	store, err := kms.NewAriesProviderWrapper(mockstorage.NewMockStoreProvider())
+	require.NoError(t, err)
+
+	suite, err := localsuite.NewLocalCryptoSuite("local-lock://custom/primary/key/", store, &noop.NoLock{})
+	require.NoError(t, err)
+
+	kmsCrypto, err := suite.KMSCrypto()
+	require.NoError(t, err)
+
+	return kmsCrypto
+}
+
+func TestCreateCSR(t *testing.T) {
+	kmsCrypto := newKMSCrypto(t)
+
+	subject := pkix.Name{CommonName: "service.example.com", Organization: []string{"Example"}}
+
+	for _, keyType := range []kmsapi.KeyType{
+		kmsapi.ECDSAP256TypeIEEEP1363, kmsapi.ECDSAP384TypeDER, kmsapi.ECDSAP521TypeIEEEP1363, kmsapi.ED25519Type,
+	} {
+		t.Run(string(keyType), func(t *testing.T) {
+			pub, err := kmsCrypto.Create(keyType)
+			require.NoError(t, err)
+
+			csrPEM, err := kmssigner.CreateCSR(kmsCrypto, pub.KeyID, subject, []string{"service.example.com"})
+			require.NoError(t, err)
+
+			block, rest := pem.Decode(csrPEM)
+			require.NotNil(t, block)
+			require.Empty(t, rest)
+			require.Equal(t, "CERTIFICATE REQUEST", block.Type)
+
+			csr, err := x509.ParseCertificateRequest(block.Bytes)
+			require.NoError(t, err)
+			require.NoError(t, csr.CheckSignature())
+			require.Equal(t, "service.example.com", csr.Subject.CommonName)
+			require.Equal(t, []string{"service.example.com"}, csr.DNSNames)
+
+			csrJWK, err := jwksupport.JWKFromKey(csr.PublicKey)
+			require.NoError(t, err)
+			require.True(t, pub.SamePublicKey(csrJWK))
+		})
+	}
+
+	t.Run("failures", func(t *testing.T) {
+		_, err := kmssigner.CreateCSR(kmsCrypto, "unknown", subject, nil)
+		require.ErrorContains(t, err, "createCSR: export public key: ")
+
+		pub, err := kmsCrypto.Create(kmsapi.ECDSASecp256k1TypeIEEEP1363)
+		require.NoError(t, err)
+
+		_, err = kmssigner.CreateCSR(kmsCrypto, pub.KeyID, subject, nil)
+		require.ErrorContains(t, err, "createCSR: x509: ")
+	})
+}
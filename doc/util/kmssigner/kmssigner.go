@@ -9,15 +9,19 @@ package kmssigner
 import (
 	"strings"
 
+	"github.com/dellekappa/kms-go/doc/jose/jwk"
 	"github.com/dellekappa/kms-go/spi/crypto"
 	"github.com/dellekappa/kms-go/spi/kms"
 )
 
 const (
-	p256Alg = "ES256"
-	p384Alg = "ES384"
-	p521Alg = "ES521"
-	edAlg   = "EdDSA"
+	p256Alg      = "ES256"
+	p384Alg      = "ES384"
+	p521Alg      = "ES512"
+	secp256k1Alg = "ES256K"
+	edAlg        = "EdDSA"
+	rs256Alg     = "RS256"
+	ps256Alg     = "PS256"
 )
 
 // KMSSigner implements JWS Signer interface using a KMS key handle and a crypto.Crypto instance.
@@ -69,8 +73,20 @@ func KeyTypeToJWA(keyType kms.KeyType) string {
 		return p384Alg
 	case kms.ECDSAP521IEEEP1363, kms.ECDSAP521DER:
 		return p521Alg
+	case kms.ECDSASecp256k1IEEEP1363, kms.ECDSASecp256k1DER:
+		return secp256k1Alg
 	case kms.ED25519:
 		return edAlg
+	case kms.RSARS256:
+		return rs256Alg
+	case kms.RSAPS256:
+		return ps256Alg
+	case kms.MLDSA44:
+		return jwk.MLDSA44Alg
+	case kms.MLDSA65:
+		return jwk.MLDSA65Alg
+	case kms.MLDSA87:
+		return jwk.MLDSA87Alg
 	}
 
 	return ""
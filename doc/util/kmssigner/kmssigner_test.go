@@ -51,13 +51,42 @@ func TestKMSSigner_Alg(t *testing.T) {
 		{
 			name:        "test ECDSA alg from P521 key type in IEEE format",
 			kmsKT:       kmsapi.ECDSAP521IEEEP1363,
-			expectedAlg: p521Alg,
+			expectedAlg: "ES512",
+		},
+		{
+			name:        "test ECDSA alg from secp256k1 key type in DER format",
+			kmsKT:       kmsapi.ECDSASecp256k1DER,
+			expectedAlg: "ES256K",
+		},
+		{
+			name:        "test ECDSA alg from secp256k1 key type in IEEE format",
+			kmsKT:       kmsapi.ECDSASecp256k1IEEEP1363,
+			expectedAlg: "ES256K",
 		},
 		{
 			name:        "test EdDSA alg from ed25519 key type",
 			kmsKT:       kmsapi.ED25519,
 			expectedAlg: edAlg,
 		},
+		{
+			name:        "test RS256 alg from RSA RS256 key type",
+			kmsKT:       kmsapi.RSARS256,
+			expectedAlg: "RS256",
+		},
+		{
+			name:        "test PS256 alg from RSA PS256 key type",
+			kmsKT:       kmsapi.RSAPS256,
+			expectedAlg: "PS256",
+		},
+		{
+			name:        "test ML-DSA-65 alg from ML-DSA 65 key type",
+			kmsKT:       kmsapi.MLDSA65,
+			expectedAlg: "ML-DSA-65",
+		},
+		{
+			name:  "test empty alg from key type without JOSE alg",
+			kmsKT: kmsapi.BLS12381G2,
+		},
 		{
 			name: "test empty alg from empty key type",
 		},
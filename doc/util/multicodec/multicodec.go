@@ -0,0 +1,73 @@
+/*
+Copyright Gen Digital Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+// Package multicodec provides the public key multicodec table and the base58-btc multibase encoding of multicodec
+// prefixed keys, as used by did:key identifiers (https://w3c-ccg.github.io/did-method-key/#format).
+package multicodec
+
+import (
+	"encoding/binary"
+	"errors"
+	"fmt"
+
+	"github.com/btcsuite/btcutil/base58"
+)
+
+// Public key codes of the multicodec table, source: https://github.com/multiformats/multicodec/blob/master/table.csv.
+const (
+	// X25519PubKey for Curve25519 public key.
+	X25519PubKey = 0xec
+	// ED25519PubKey for Ed25519 public key.
+	ED25519PubKey = 0xed
+	// Secp256k1PubKey for compressed secp256k1 public key.
+	Secp256k1PubKey = 0xe7
+	// BLS12381g2PubKey for BLS12-381 G2 public key.
+	BLS12381g2PubKey = 0xeb
+	// BLS12381g1g2PubKey for BLS12-381 G1G2 public key.
+	BLS12381g1g2PubKey = 0xee
+	// P256PubKey for compressed NIST P-256 public key.
+	P256PubKey = 0x1200
+	// P384PubKey for compressed NIST P-384 public key.
+	P384PubKey = 0x1201
+	// P521PubKey for compressed NIST P-521 public key.
+	P521PubKey = 0x1202
+	// RSAPubKey for PKCS#1 RSA public key.
+	RSAPubKey = 0x1205
+)
+
+// base58BTCPrefix is the multibase prefix of base58-btc encoded values (https://github.com/multiformats/multibase).
+const base58BTCPrefix = 'z'
+
+// maxCodeBytes is the maximum size of an unsigned varint multicodec code.
+const maxCodeBytes = 9
+
+// Encode returns the base58-btc multibase encoding of key prefixed with the unsigned varint of code.
+func Encode(code uint64, key []byte) string {
+	buf := make([]byte, binary.MaxVarintLen64, binary.MaxVarintLen64+len(key))
+	n := binary.PutUvarint(buf, code)
+
+	return string(base58BTCPrefix) + base58.Encode(append(buf[:n], key...))
+}
+
+// Decode parses the base58-btc multibase value and returns the key and its multicodec code.
+func Decode(value string) ([]byte, uint64, error) {
+	if len(value) < 2 || value[0] != base58BTCPrefix {
+		return nil, 0, errors.New("unknown key encoding")
+	}
+
+	mc := base58.Decode(value[1:])
+
+	code, n := binary.Uvarint(mc)
+	if n <= 0 {
+		return nil, 0, errors.New("unknown key encoding")
+	}
+
+	if n > maxCodeBytes {
+		return nil, 0, fmt.Errorf("code exceeds maximum size")
+	}
+
+	return mc[n:], code, nil
+}
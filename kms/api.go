@@ -15,6 +15,11 @@ import (
 // key ID could be found.
 var ErrKeyNotFound = errors.New("key not found")
 
+// ErrKeyNotExportable is an error type that a KeyManager backed by a non-extractable key store (e.g. an HSM) returns
+// when key material can't be exported. Such a KeyManager returning it from CreateAndExportPubKeyBytes must still
+// return the key ID of the created key.
+var ErrKeyNotExportable = errors.New("key not exportable")
+
 // CryptoBox is a libsodium crypto service used by legacy authcrypt packer.
 // TODO remove this service when legacy packer is retired from the framework.
 type CryptoBox interface {
@@ -24,58 +24,75 @@ import (
 	"github.com/dellekappa/kms-go/crypto/tinkcrypto/primitive/secp256k1"
 )
 
-// nolint:gocyclo,funlen
-func keyTemplate(keyType kms.KeyType, _ ...kms.KeyOpts) (*tinkpb.KeyTemplate, error) {
-	switch keyType {
-	case kms.AES128GCMType:
-		return aead.AES128GCMKeyTemplate(), nil
-	case kms.AES256GCMNoPrefixType:
-		// RAW (to support keys not generated by Tink)
-		return aead.AES256GCMNoPrefixKeyTemplate(), nil
-	case kms.AES256GCMType:
-		return aead.AES256GCMKeyTemplate(), nil
-	case kms.ChaCha20Poly1305Type:
-		return aead.ChaCha20Poly1305KeyTemplate(), nil
-	case kms.XChaCha20Poly1305Type:
-		return aead.XChaCha20Poly1305KeyTemplate(), nil
-	case kms.ECDSAP256TypeDER:
-		return signature.ECDSAP256KeyWithoutPrefixTemplate(), nil
-	case kms.ECDSAP384TypeDER:
-		// Since Tink's signature.ECDSAP384KeyWithoutPrefixTemplate() uses SHA_512 as the hashing function during
-		// signature/verification, the kms type must explicitly use SHA_384 just as IEEEP384 key template below.
-		// For this reason, the KMS cannot use Tink's `signature.ECDSAP384KeyWithoutPrefixTemplate()` template here.
+// keyTemplates are the key types the local KMS creates keys of, with their key template. It is the single source of
+// keyTemplate and SupportedKeyTypes.
+var keyTemplates = []struct { //nolint:gochecknoglobals
+	keyType  kms.KeyType
+	template func() (*tinkpb.KeyTemplate, error)
+}{
+	{kms.AES128GCMType, withoutError(aead.AES128GCMKeyTemplate)},
+	// RAW (to support keys not generated by Tink)
+	{kms.AES256GCMNoPrefixType, withoutError(aead.AES256GCMNoPrefixKeyTemplate)},
+	{kms.AES256GCMType, withoutError(aead.AES256GCMKeyTemplate)},
+	{kms.ChaCha20Poly1305Type, withoutError(aead.ChaCha20Poly1305KeyTemplate)},
+	{kms.XChaCha20Poly1305Type, withoutError(aead.XChaCha20Poly1305KeyTemplate)},
+	{kms.ECDSAP256TypeDER, withoutError(signature.ECDSAP256KeyWithoutPrefixTemplate)},
+	// Since Tink's signature.ECDSAP384KeyWithoutPrefixTemplate() uses SHA_512 as the hashing function during
+	// signature/verification, the kms type must explicitly use SHA_384 just as IEEEP384 key template below.
+	// For this reason, the KMS cannot use Tink's `signature.ECDSAP384KeyWithoutPrefixTemplate()` template here.
+	{kms.ECDSAP384TypeDER, withoutError(func() *tinkpb.KeyTemplate {
 		return createECDSAKeyTemplate(ecdsapb.EcdsaSignatureEncoding_DER, commonpb.HashType_SHA384,
-			commonpb.EllipticCurveType_NIST_P384), nil
-	case kms.ECDSAP521TypeDER:
-		return signature.ECDSAP521KeyWithoutPrefixTemplate(), nil
-	case kms.ECDSAP256TypeIEEEP1363:
-		// JWS keys should sign using IEEE_P1363 format only (not DER format)
-		return createECDSAIEEE1363KeyTemplate(commonpb.HashType_SHA256, commonpb.EllipticCurveType_NIST_P256), nil
-	case kms.ECDSAP384TypeIEEEP1363:
-		return createECDSAIEEE1363KeyTemplate(commonpb.HashType_SHA384, commonpb.EllipticCurveType_NIST_P384), nil
-	case kms.ECDSAP521TypeIEEEP1363:
-		return createECDSAIEEE1363KeyTemplate(commonpb.HashType_SHA512, commonpb.EllipticCurveType_NIST_P521), nil
-	case kms.ED25519Type:
-		return signature.ED25519KeyWithoutPrefixTemplate(), nil
-	case kms.HMACSHA256Tag256Type:
-		return mac.HMACSHA256Tag256KeyTemplate(), nil
-	case kms.NISTP256ECDHKWType:
-		return ecdh.NISTP256ECDHKWKeyTemplate(), nil
-	case kms.NISTP384ECDHKWType:
-		return ecdh.NISTP384ECDHKWKeyTemplate(), nil
-	case kms.NISTP521ECDHKWType:
-		return ecdh.NISTP521ECDHKWKeyTemplate(), nil
-	case kms.X25519ECDHKWType:
-		return ecdh.X25519ECDHKWKeyTemplate(), nil
-	case kms.BLS12381G2Type:
-		return bbs.BLS12381G2KeyTemplate(), nil
-	case kms.ECDSASecp256k1DER:
-		return secp256k1.DERKeyTemplate()
-	case kms.ECDSASecp256k1IEEEP1363:
-		return secp256k1.IEEEP1363KeyTemplate()
-	default:
-		return nil, fmt.Errorf("getKeyTemplate: key type '%s' unrecognized", keyType)
+			commonpb.EllipticCurveType_NIST_P384)
+	})},
+	{kms.ECDSAP521TypeDER, withoutError(signature.ECDSAP521KeyWithoutPrefixTemplate)},
+	// JWS keys should sign using IEEE_P1363 format only (not DER format)
+	{kms.ECDSAP256TypeIEEEP1363, withoutError(func() *tinkpb.KeyTemplate {
+		return createECDSAIEEE1363KeyTemplate(commonpb.HashType_SHA256, commonpb.EllipticCurveType_NIST_P256)
+	})},
+	{kms.ECDSAP384TypeIEEEP1363, withoutError(func() *tinkpb.KeyTemplate {
+		return createECDSAIEEE1363KeyTemplate(commonpb.HashType_SHA384, commonpb.EllipticCurveType_NIST_P384)
+	})},
+	{kms.ECDSAP521TypeIEEEP1363, withoutError(func() *tinkpb.KeyTemplate {
+		return createECDSAIEEE1363KeyTemplate(commonpb.HashType_SHA512, commonpb.EllipticCurveType_NIST_P521)
+	})},
+	{kms.ECDSASecp256k1TypeDER, secp256k1.DERKeyTemplate},
+	{kms.ECDSASecp256k1TypeIEEEP1363, secp256k1.IEEEP1363KeyTemplate},
+	{kms.ED25519Type, withoutError(signature.ED25519KeyWithoutPrefixTemplate)},
+	{kms.HMACSHA256Tag256Type, withoutError(mac.HMACSHA256Tag256KeyTemplate)},
+	{kms.NISTP256ECDHKWType, withoutError(ecdh.NISTP256ECDHKWKeyTemplate)},
+	{kms.NISTP384ECDHKWType, withoutError(ecdh.NISTP384ECDHKWKeyTemplate)},
+	{kms.NISTP521ECDHKWType, withoutError(ecdh.NISTP521ECDHKWKeyTemplate)},
+	{kms.X25519ECDHKWType, withoutError(ecdh.X25519ECDHKWKeyTemplate)},
+	{kms.BLS12381G2Type, withoutError(bbs.BLS12381G2KeyTemplate)},
+}
+
+func withoutError(template func() *tinkpb.KeyTemplate) func() (*tinkpb.KeyTemplate, error) {
+	return func() (*tinkpb.KeyTemplate, error) {
+		return template(), nil
+	}
+}
+
+// SupportedKeyTypes returns the key types the local KMS creates keys of with Create. Key types registered with
+// jwksupport.RegisterKeyType only get a JWK encoding, the local KMS can't create their keys, they are listed by
+// jwksupport.SupportedKeyTypesForJWK.
+func SupportedKeyTypes() []kms.KeyType {
+	keyTypes := make([]kms.KeyType, len(keyTemplates))
+
+	for i, kt := range keyTemplates {
+		keyTypes[i] = kt.keyType
 	}
+
+	return keyTypes
+}
+
+func keyTemplate(keyType kms.KeyType, _ ...kms.KeyOpts) (*tinkpb.KeyTemplate, error) {
+	for _, kt := range keyTemplates {
+		if kt.keyType == keyType {
+			return kt.template()
+		}
+	}
+
+	return nil, fmt.Errorf("getKeyTemplate: key type '%s' unrecognized", keyType)
 }
 
 func createECDSAIEEE1363KeyTemplate(hashType commonpb.HashType, curve commonpb.EllipticCurveType) *tinkpb.KeyTemplate {
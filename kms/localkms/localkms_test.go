@@ -637,6 +637,28 @@ func TestLocalKMS_getKeyTemplate(t *testing.T) {
 	require.Equal(t, "type.googleapis.com/google.crypto.tink.HmacKey", keyTemplate.TypeUrl)
 }
 
+func TestSupportedKeyTypes(t *testing.T) {
+	supported := SupportedKeyTypes()
+	require.NotEmpty(t, supported)
+
+	for _, kt := range supported {
+		_, err := getKeyTemplate(kt)
+		require.NoError(t, err, kt)
+	}
+
+	for _, kt := range []kmsapi.KeyType{
+		kmsapi.RSARS256Type, kmsapi.RSAPS256Type, kmsapi.BLS12381G1Type, kmsapi.MLDSA44Type, kmsapi.CLCredDefType,
+	} {
+		require.NotContains(t, supported, kt)
+
+		_, err := getKeyTemplate(kt)
+		require.Error(t, err, kt)
+	}
+
+	supported[0] = "changed"
+	require.NotEqual(t, supported, SupportedKeyTypes())
+}
+
 func createMasterKeyAndSecretLock(t *testing.T) secretlock.Service {
 	t.Helper()
 
@@ -8,6 +8,7 @@ package wrapper
 
 import (
 	"github.com/dellekappa/kms-go/doc/jose/jwk"
+	"github.com/dellekappa/kms-go/doc/util/kmssigner"
 	"github.com/dellekappa/kms-go/spi/kms"
 	wrapperapi "github.com/dellekappa/kms-go/wrapper/api"
 )
@@ -99,8 +100,9 @@ func makeMockFixedKey(m *MockKMSCrypto) (*MockFixedKeyCrypto, error) {
 	}
 
 	fkc := &MockFixedKeyCrypto{
-		SignErr:   m.SignErr,
-		VerifyErr: m.VerifyErr,
+		SignErr:    m.SignErr,
+		VerifyErr:  m.VerifyErr,
+		KeyTypeVal: m.PubKeyType,
 	}
 
 	return fkc, nil
@@ -108,9 +110,10 @@ func makeMockFixedKey(m *MockKMSCrypto) (*MockFixedKeyCrypto, error) {
 
 // MockFixedKeyCrypto mocks kmscrypto.FixedKeyCrypto.
 type MockFixedKeyCrypto struct {
-	SignVal   []byte
-	SignErr   error
-	VerifyErr error
+	SignVal    []byte
+	SignErr    error
+	VerifyErr  error
+	KeyTypeVal kms.KeyType
 }
 
 // Sign mock.
@@ -123,6 +126,16 @@ func (m *MockFixedKeyCrypto) SignMulti(msgs [][]byte) ([]byte, error) {
 	return m.SignVal, m.SignErr
 }
 
+// Algorithm mock.
+func (m *MockFixedKeyCrypto) Algorithm() string {
+	return kmssigner.KeyTypeToJWA(m.KeyTypeVal)
+}
+
+// KeyType mock.
+func (m *MockFixedKeyCrypto) KeyType() kms.KeyType {
+	return m.KeyTypeVal
+}
+
 // Verify mock.
 func (m *MockFixedKeyCrypto) Verify(sig, msg []byte) error {
 	return m.VerifyErr
@@ -0,0 +1,23 @@
+/*
+Copyright Gen Digital Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package crypto
+
+// KeyAgreer computes ECDH shared secrets with static private keys kept in a KMS. The scalar multiplication happens
+// behind the KMS abstraction, the private keys are never exposed.
+type KeyAgreer interface {
+	// ComputeSharedSecret returns the ECDH shared secret Z of the private key senderKID with recipientPub: the X
+	// coordinate of the shared point padded to the curve size for EC keys, the X25519 output for OKP keys.
+	ComputeSharedSecret(senderKID string, recipientPub *PublicKey) ([]byte, error)
+}
+
+// KMSSenderKey is an ECDH-1PU sender static key kept in a KMS. Set with WithSender() in place of a private key handle,
+// it lets WrapKey() derive the sender part of the ECDH-1PU shared secret with KeyAgreer, for KMSs whose private keys
+// can't be exported.
+type KMSSenderKey struct {
+	KID       string
+	KeyAgreer KeyAgreer
+}
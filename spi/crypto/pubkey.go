@@ -0,0 +1,120 @@
+/*
+Copyright Gen Digital Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package crypto
+
+import (
+	"crypto"
+	"crypto/ecdh"
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/elliptic"
+	"crypto/rsa"
+	"errors"
+	"fmt"
+	"math/big"
+
+	"github.com/btcsuite/btcd/btcec/v2"
+)
+
+const (
+	rsaKeyType   = "RSA"
+	x25519Curve  = "X25519"
+	ed25519Curve = "Ed25519"
+)
+
+// ToStdPublicKey reconstructs the standard library public key represented by pk: an *ecdsa.PublicKey for NIST P and
+// secp256k1 curves, an *rsa.PublicKey for RSA keys, an ed25519.PublicKey for Ed25519 keys or an *ecdh.PublicKey for
+// X25519 keys. It is the reverse of building a PublicKey from a JWK.
+func (pk *PublicKey) ToStdPublicKey() (crypto.PublicKey, error) {
+	if pk == nil {
+		return nil, errors.New("toStdPublicKey: public key is nil")
+	}
+
+	if pk.Type == rsaKeyType || (pk.Curve == "" && len(pk.N) > 0) {
+		return pk.toRSAPublicKey()
+	}
+
+	switch pk.Curve {
+	case ed25519Curve:
+		if len(pk.X) != ed25519.PublicKeySize {
+			return nil, fmt.Errorf("toStdPublicKey: invalid Ed25519 public key size %d", len(pk.X))
+		}
+
+		return ed25519.PublicKey(append([]byte{}, pk.X...)), nil
+	case x25519Curve:
+		pubKey, err := ecdh.X25519().NewPublicKey(pk.X)
+		if err != nil {
+			return nil, fmt.Errorf("toStdPublicKey: invalid X25519 public key: %w", err)
+		}
+
+		return pubKey, nil
+	case "secp256k1", "SECP256K1":
+		return pk.toSecp256k1PublicKey()
+	}
+
+	curve, err := nistCurve(pk.Curve)
+	if err != nil {
+		return nil, fmt.Errorf("toStdPublicKey: %w", err)
+	}
+
+	x := new(big.Int).SetBytes(pk.X)
+	y := new(big.Int).SetBytes(pk.Y)
+
+	if !curve.IsOnCurve(x, y) {
+		return nil, fmt.Errorf("toStdPublicKey: point is not on curve %s", curve.Params().Name)
+	}
+
+	return &ecdsa.PublicKey{Curve: curve, X: x, Y: y}, nil
+}
+
+func (pk *PublicKey) toRSAPublicKey() (*rsa.PublicKey, error) {
+	if len(pk.N) == 0 || len(pk.E) == 0 {
+		return nil, errors.New("toStdPublicKey: RSA public key is missing modulus or exponent")
+	}
+
+	e := new(big.Int).SetBytes(pk.E)
+	if !e.IsInt64() || e.Int64() > int64(^uint32(0)>>1) || e.Int64() < 2 {
+		return nil, errors.New("toStdPublicKey: invalid RSA public exponent")
+	}
+
+	return &rsa.PublicKey{N: new(big.Int).SetBytes(pk.N), E: int(e.Int64())}, nil
+}
+
+func (pk *PublicKey) toSecp256k1PublicKey() (*ecdsa.PublicKey, error) {
+	const coordinateSize = 32
+
+	if len(pk.X) > coordinateSize || len(pk.Y) > coordinateSize {
+		return nil, errors.New("toStdPublicKey: invalid secp256k1 public key coordinates size")
+	}
+
+	// uncompressed SEC1 point: 0x04 || X || Y.
+	point := make([]byte, 1+2*coordinateSize)
+	point[0] = 0x04
+
+	new(big.Int).SetBytes(pk.X).FillBytes(point[1 : 1+coordinateSize])
+	new(big.Int).SetBytes(pk.Y).FillBytes(point[1+coordinateSize:])
+
+	pubKey, err := btcec.ParsePubKey(point)
+	if err != nil {
+		return nil, fmt.Errorf("toStdPublicKey: invalid secp256k1 public key: %w", err)
+	}
+
+	return pubKey.ToECDSA(), nil
+}
+
+func nistCurve(name string) (elliptic.Curve, error) {
+	switch name {
+	case "P-256", "NIST_P256", "secp256r1":
+		return elliptic.P256(), nil
+	case "P-384", "NIST_P384", "secp384r1":
+		return elliptic.P384(), nil
+	case "P-521", "NIST_P521", "secp521r1":
+		return elliptic.P521(), nil
+	default:
+		return nil, fmt.Errorf("unsupported curve '%s'", name)
+	}
+}
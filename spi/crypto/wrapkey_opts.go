@@ -11,6 +11,8 @@ type wrapKeyOpts struct {
 	useXC20PKW bool
 	tag        []byte
 	epk        *PrivateKey
+	directEnc  string
+	directSize int
 }
 
 // NewOpt creates a new empty wrap key option.
@@ -42,6 +44,16 @@ func (pk *wrapKeyOpts) EPK() *PrivateKey {
 	return pk.epk
 }
 
+// DirectKeyAgreementEnc is the content encryption algorithm of the CEK derived with ECDH-ES direct key agreement.
+func (pk *wrapKeyOpts) DirectKeyAgreementEnc() string {
+	return pk.directEnc
+}
+
+// DirectKeyAgreementKeySize is the size in bytes of the CEK derived with ECDH-ES direct key agreement.
+func (pk *wrapKeyOpts) DirectKeyAgreementKeySize() int {
+	return pk.directSize
+}
+
 // WrapKeyOpts are the crypto.Wrap key options.
 type WrapKeyOpts func(opts *wrapKeyOpts)
 
@@ -50,6 +62,7 @@ type WrapKeyOpts func(opts *wrapKeyOpts)
 // Sender is a key used for ECDH-1PU key agreement for authenticating the sender.
 // senderkey can be of the following there types:
 //   - *keyset.Handle (requires private key handle for crypto.WrapKey())
+//   - *crypto.KMSSenderKey (available for WrapKey() only, the sender private key is kept in a KMS)
 //   - *crypto.PublicKey (available for UnwrapKey() only)
 //   - *ecdsa.PublicKey (available for UnwrapKey() only)
 func WithSender(senderKey interface{}) WrapKeyOpts {
@@ -86,3 +99,15 @@ func WithEPK(epk *PrivateKey) WrapKeyOpts {
 		opts.epk = epk
 	}
 }
+
+// WithDirectKeyAgreement option is to instruct the key unwrapping function to derive the CEK using ECDH-ES in Direct
+// Key Agreement mode (alg `ECDH-ES`) as per https://tools.ietf.org/html/rfc7518#section-4.6: the Concat KDF output is
+// the CEK itself and no key is unwrapped. enc is the JWE content encryption algorithm used as the KDF AlgorithmID and
+// keySize the size in bytes of the CEK it requires. It is useful for UnwrapKey() call only since the sender derives
+// the CEK from its own ephemeral key.
+func WithDirectKeyAgreement(enc string, keySize int) WrapKeyOpts {
+	return func(opts *wrapKeyOpts) {
+		opts.directEnc = enc
+		opts.directSize = keySize
+	}
+}
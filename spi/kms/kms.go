@@ -131,6 +131,10 @@ const (
 	RSARS256 = "RSARS256"
 	// RSAPS256 key type value.
 	RSAPS256 = "RSAPS256"
+	// RSA3072 key type value (RSA key with a 3072 bits modulus).
+	RSA3072 = "RSA3072"
+	// RSA4096 key type value (RSA key with a 4096 bits modulus).
+	RSA4096 = "RSA4096"
 	// HMACSHA256Tag256 key type value.
 	HMACSHA256Tag256 = "HMACSHA256Tag256"
 	// NISTP256ECDHKW key type value.
@@ -143,6 +147,14 @@ const (
 	X25519ECDHKW = "X25519ECDHKW"
 	// BLS12381G2 BBS+ key type value.
 	BLS12381G2 = "BLS12381G2"
+	// BLS12381G1 BBS key type value (public key on the G1 group).
+	BLS12381G1 = "BLS12381G1"
+	// MLDSA44 ML-DSA-44 (FIPS 204) key type value.
+	MLDSA44 = "MLDSA44"
+	// MLDSA65 ML-DSA-65 (FIPS 204) key type value.
+	MLDSA65 = "MLDSA65"
+	// MLDSA87 ML-DSA-87 (FIPS 204) key type value.
+	MLDSA87 = "MLDSA87"
 	// CLCredDef key type value.
 	CLCredDef = "CLCredDef"
 	// CLMasterSecret key type value.
@@ -185,6 +197,10 @@ const (
 	RSARS256Type = KeyType(RSARS256)
 	// RSAPS256Type key type value.
 	RSAPS256Type = KeyType(RSAPS256)
+	// RSA3072Type key type value (RSA key with a 3072 bits modulus).
+	RSA3072Type = KeyType(RSA3072)
+	// RSA4096Type key type value (RSA key with a 4096 bits modulus).
+	RSA4096Type = KeyType(RSA4096)
 	// HMACSHA256Tag256Type key type value.
 	HMACSHA256Tag256Type = KeyType(HMACSHA256Tag256)
 	// NISTP256ECDHKWType key type value.
@@ -197,6 +213,14 @@ const (
 	X25519ECDHKWType = KeyType(X25519ECDHKW)
 	// BLS12381G2Type BBS+ key type value.
 	BLS12381G2Type = KeyType(BLS12381G2)
+	// BLS12381G1Type BBS key type value (public key on the G1 group).
+	BLS12381G1Type = KeyType(BLS12381G1)
+	// MLDSA44Type ML-DSA-44 (FIPS 204) key type value.
+	MLDSA44Type = KeyType(MLDSA44)
+	// MLDSA65Type ML-DSA-65 (FIPS 204) key type value.
+	MLDSA65Type = KeyType(MLDSA65)
+	// MLDSA87Type ML-DSA-87 (FIPS 204) key type value.
+	MLDSA87Type = KeyType(MLDSA87)
 	// CLCredDefType type value.
 	CLCredDefType = KeyType(CLCredDef)
 	// CLMasterSecretType key type value.
@@ -0,0 +1,131 @@
+/*
+Copyright Gen Digital Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package cryptoutil
+
+import (
+	"crypto/ed25519"
+	"crypto/rand"
+	"crypto/sha512"
+	"errors"
+	"fmt"
+
+	"filippo.io/edwards25519"
+)
+
+// batchCoefficientSize is the size in bytes of the random coefficients used to combine the verification equations of
+// a batch, 128 bits giving a 2^-128 probability for an invalid batch to pass.
+const batchCoefficientSize = 16
+
+// BatchVerifyEd25519 verifies the Ed25519 signatures sigs of msgs with the public keys pubs, pubs[i] being the key of
+// sigs[i] over msgs[i]. The verification equations are combined with random coefficients and checked at once, which is
+// faster than verifying each signature for large batches.
+//
+// It returns ok=true and badIndex=-1 when all signatures are valid. When the batch fails, the signatures are verified
+// one by one and badIndex is the index of the first invalid signature. err is only returned when the slices do not
+// have the same length or when the random coefficients can't be generated.
+//
+// The batch equation is cofactored (https://eprint.iacr.org/2020/1244): signatures valid for ed25519.Verify are always
+// accepted, but crafted signatures with small order components may be accepted by the batch while being rejected by
+// ed25519.Verify.
+func BatchVerifyEd25519(pubs []ed25519.PublicKey, msgs, sigs [][]byte) (bool, int, error) {
+	if len(pubs) != len(msgs) || len(pubs) != len(sigs) {
+		return false, -1, fmt.Errorf("batchVerifyEd25519: mismatched lengths: %d public keys, %d messages and "+
+			"%d signatures", len(pubs), len(msgs), len(sigs))
+	}
+
+	ok, err := batchVerifyEd25519(pubs, msgs, sigs)
+	if err != nil {
+		return false, -1, fmt.Errorf("batchVerifyEd25519: %w", err)
+	}
+
+	if ok {
+		return true, -1, nil
+	}
+
+	for i := range sigs {
+		if !verifyEd25519(pubs[i], msgs[i], sigs[i]) {
+			return false, i, nil
+		}
+	}
+
+	// signatures accepted by ed25519.Verify always satisfy the cofactored batch equation, so this is not expected.
+	return false, -1, errors.New("batchVerifyEd25519: batch failed but all signatures are valid")
+}
+
+func verifyEd25519(pub ed25519.PublicKey, msg, sig []byte) bool {
+	return len(pub) == ed25519.PublicKeySize && ed25519.Verify(pub, msg, sig)
+}
+
+// batchVerifyEd25519 checks [8]([-sum(z_i*s_i)]B + sum([z_i]R_i) + sum([z_i*k_i]A_i)) == 0 with random z_i.
+func batchVerifyEd25519(pubs []ed25519.PublicKey, msgs, sigs [][]byte) (bool, error) {
+	n := len(sigs)
+
+	scalars := make([]*edwards25519.Scalar, 0, 2*n+1)
+	points := make([]*edwards25519.Point, 0, 2*n+1)
+
+	bCoefficient := edwards25519.NewScalar()
+
+	scalars = append(scalars, bCoefficient)
+	points = append(points, edwards25519.NewGeneratorPoint())
+
+	for i := 0; i < n; i++ {
+		if len(pubs[i]) != ed25519.PublicKeySize || len(sigs[i]) != ed25519.SignatureSize {
+			return false, nil
+		}
+
+		a, err := new(edwards25519.Point).SetBytes(pubs[i])
+		if err != nil {
+			return false, nil //nolint:nilerr // an invalid public key fails the batch.
+		}
+
+		r, err := new(edwards25519.Point).SetBytes(sigs[i][:32])
+		if err != nil {
+			return false, nil //nolint:nilerr // an invalid signature fails the batch.
+		}
+
+		s, err := edwards25519.NewScalar().SetCanonicalBytes(sigs[i][32:])
+		if err != nil {
+			return false, nil //nolint:nilerr // an invalid signature fails the batch.
+		}
+
+		z, err := randomBatchCoefficient()
+		if err != nil {
+			return false, err
+		}
+
+		h := sha512.New()
+		h.Write(sigs[i][:32])
+		h.Write(pubs[i])
+		h.Write(msgs[i])
+
+		k, err := edwards25519.NewScalar().SetUniformBytes(h.Sum(nil))
+		if err != nil {
+			return false, err
+		}
+
+		bCoefficient.Subtract(bCoefficient, edwards25519.NewScalar().Multiply(z, s))
+
+		scalars = append(scalars, z, edwards25519.NewScalar().Multiply(z, k))
+		points = append(points, r, a)
+	}
+
+	check := new(edwards25519.Point).VarTimeMultiScalarMult(scalars, points)
+	check.MultByCofactor(check)
+
+	return check.Equal(edwards25519.NewIdentityPoint()) == 1, nil
+}
+
+func randomBatchCoefficient() (*edwards25519.Scalar, error) {
+	var b [32]byte
+
+	if _, err := rand.Read(b[:batchCoefficientSize]); err != nil {
+		return nil, fmt.Errorf("failed to generate random coefficient: %w", err)
+	}
+
+	// a 128 bits value is always lower than the group order.
+	return edwards25519.NewScalar().SetCanonicalBytes(b[:])
+}
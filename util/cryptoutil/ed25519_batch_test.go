@@ -0,0 +1,127 @@
+/*
+Copyright Gen Digital Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package cryptoutil
+
+import (
+	"crypto/ed25519"
+	"crypto/rand"
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestBatchVerifyEd25519(t *testing.T) {
+	newBatch := func(t *testing.T, n int) ([]ed25519.PublicKey, [][]byte, [][]byte) {
+		t.Helper()
+
+		pubs := make([]ed25519.PublicKey, n)
+		msgs := make([][]byte, n)
+		sigs := make([][]byte, n)
+
+		for i := 0; i < n; i++ {
+			pub, priv, err := ed25519.GenerateKey(rand.Reader)
+			require.NoError(t, err)
+
+			pubs[i] = pub
+			msgs[i] = []byte(fmt.Sprintf("message %d", i))
+			sigs[i] = ed25519.Sign(priv, msgs[i])
+		}
+
+		return pubs, msgs, sigs
+	}
+
+	t.Run("success", func(t *testing.T) {
+		for _, n := range []int{0, 1, 2, 64} {
+			pubs, msgs, sigs := newBatch(t, n)
+
+			ok, badIndex, err := BatchVerifyEd25519(pubs, msgs, sigs)
+			require.NoError(t, err)
+			require.True(t, ok)
+			require.Equal(t, -1, badIndex)
+		}
+	})
+
+	t.Run("invalid signature index is returned", func(t *testing.T) {
+		tests := []struct {
+			name    string
+			corrupt func(pubs []ed25519.PublicKey, msgs, sigs [][]byte)
+		}{
+			{
+				name: "tampered message",
+				corrupt: func(_ []ed25519.PublicKey, msgs, _ [][]byte) {
+					msgs[5] = []byte("tampered")
+				},
+			},
+			{
+				name: "tampered signature",
+				corrupt: func(_ []ed25519.PublicKey, _, sigs [][]byte) {
+					sigs[5][0] ^= 0xff
+				},
+			},
+			{
+				name: "non canonical signature scalar",
+				corrupt: func(_ []ed25519.PublicKey, _, sigs [][]byte) {
+					sigs[5][63] = 0xff
+				},
+			},
+			{
+				name: "truncated signature",
+				corrupt: func(_ []ed25519.PublicKey, _, sigs [][]byte) {
+					sigs[5] = sigs[5][:10]
+				},
+			},
+			{
+				name: "swapped public key",
+				corrupt: func(pubs []ed25519.PublicKey, _, _ [][]byte) {
+					pubs[5] = pubs[6]
+				},
+			},
+			{
+				name: "invalid public key size",
+				corrupt: func(pubs []ed25519.PublicKey, _, _ [][]byte) {
+					pubs[5] = pubs[5][:16]
+				},
+			},
+		}
+
+		for _, tc := range tests {
+			t.Run(tc.name, func(t *testing.T) {
+				pubs, msgs, sigs := newBatch(t, 10)
+				tc.corrupt(pubs, msgs, sigs)
+
+				ok, badIndex, err := BatchVerifyEd25519(pubs, msgs, sigs)
+				require.NoError(t, err)
+				require.False(t, ok)
+				require.Equal(t, 5, badIndex)
+			})
+		}
+	})
+
+	t.Run("first invalid signature index is returned", func(t *testing.T) {
+		pubs, msgs, sigs := newBatch(t, 10)
+		msgs[3] = []byte("tampered")
+		msgs[7] = []byte("tampered")
+
+		ok, badIndex, err := BatchVerifyEd25519(pubs, msgs, sigs)
+		require.NoError(t, err)
+		require.False(t, ok)
+		require.Equal(t, 3, badIndex)
+	})
+
+	t.Run("error mismatched lengths", func(t *testing.T) {
+		pubs, msgs, sigs := newBatch(t, 3)
+
+		ok, badIndex, err := BatchVerifyEd25519(pubs, msgs[:2], sigs)
+		require.EqualError(t, err, "batchVerifyEd25519: mismatched lengths: 3 public keys, 2 messages and 3 signatures")
+		require.False(t, ok)
+		require.Equal(t, -1, badIndex)
+
+		_, _, err = BatchVerifyEd25519(pubs, msgs, sigs[:1])
+		require.Error(t, err)
+	})
+}
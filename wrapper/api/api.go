@@ -9,6 +9,7 @@ import (
 	"errors"
 
 	"github.com/dellekappa/kms-go/doc/jose/jwk"
+	cryptoapi "github.com/dellekappa/kms-go/spi/crypto"
 	kmsapi "github.com/dellekappa/kms-go/spi/kms"
 )
 
@@ -44,6 +45,35 @@ type KeyCreator interface {
 	ExportPubKeyBytes(id string) ([]byte, kmsapi.KeyType, error)
 }
 
+// JWKExporter is optionally implemented by KMSs storing public keys in JWK form, as common for cloud KMSs. KeyCreator
+// implementations prefer ExportJWK over converting ExportPubKeyBytes results to JWK, which loses JWK metadata like
+// the KMS-assigned alg.
+type JWKExporter interface {
+	ExportJWK(kid string) (*jwk.JWK, kmsapi.KeyType, error)
+}
+
+// KeyRef references a key kept in the wrapped KMS, signing with it without ever holding its private key.
+type KeyRef interface {
+	KeyID() string
+	FixedKeySigner
+}
+
+// NonExtractableKeyCreator creates keypairs which private keys never leave the wrapped KMS, returning a KeyRef to sign
+// with them and public keys in JWK format. CreateNonExtractable returns ErrNotSupported if the KMS can't sign with a
+// key reference.
+type NonExtractableKeyCreator interface {
+	KeyCreator
+	CreateNonExtractable(keyType kmsapi.KeyType) (KeyRef, *jwk.JWK, error)
+}
+
+// ImportingKeyCreator imports private JWKs in the wrapped KMS, e.g. keys received from partners, to sign with them later.
+// ImportJWK returns the key ID assigned by the KMS, not the kid of the JWK, and fails for public-only JWKs.
+// ImportJWK returns ErrNotSupported if the KMS can't import private keys.
+type ImportingKeyCreator interface {
+	KeyCreator
+	ImportJWK(j *jwk.JWK) (string, error)
+}
+
 // KMSCrypto provides wrapped kms and crypto operations.
 type KMSCrypto interface {
 	KeyCreator
@@ -77,6 +107,39 @@ type KMSCryptoSigner interface {
 // FixedKeySigner provides the common signer interface, using a fixed key for each signer instance.
 type FixedKeySigner interface {
 	Sign(msg []byte) ([]byte, error)
+	// Algorithm returns the JOSE alg of the signatures (e.g. ES256 or EdDSA), or an empty string if the key type has no
+	// registered JOSE alg or can't be resolved.
+	Algorithm() string
+	// KeyType returns the KMS key type of the signing key, or an empty string if it can't be resolved. Signers may
+	// resolve it from the KMS on the first call rather than when they are created.
+	KeyType() kmsapi.KeyType
+}
+
+// DigestSigner is optionally implemented by FixedKeySigners able to sign a digest computed outside of the KMS, e.g. by
+// an HSM integration. SignDigest does not hash digest, whose length must match the hash of the signature algorithm
+// (32 bytes for ES256, 48 bytes for ES384, ...). Ed25519 keys can't sign digests as Ed25519 requires the full message.
+type DigestSigner interface {
+	SignDigest(digest []byte) ([]byte, error)
+}
+
+// DigestVerifier is optionally implemented by KMSCryptoVerifiers able to verify signatures of digests computed outside
+// of the KMS, see DigestSigner.
+type DigestVerifier interface {
+	VerifyDigest(sig, digest []byte, pub *jwk.JWK) error
+}
+
+// DualFormatSigner is optionally implemented by FixedKeySigners of ECDSA keys able to return a single signature
+// encoded both as IEEE P1363 and as DER, for protocols needing both encodings of the same signature. Signing twice
+// would give two different signatures as ECDSA signatures are randomized.
+type DualFormatSigner interface {
+	SignBoth(msg []byte) (p1363 []byte, der []byte, err error)
+}
+
+// KeyAgreer is optionally implemented by KMSCrypto implementations able to compute ECDH shared secrets with the
+// private keys kept in the wrapped KMS. Set in a cryptoapi.KMSSenderKey, it lets ECDH-1PU encrypters authenticate the
+// sender with a key which private key is never exported.
+type KeyAgreer interface {
+	cryptoapi.KeyAgreer
 }
 
 // KMSCryptoMultiSigner provides signing operations, including multi-signatures.
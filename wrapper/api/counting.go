@@ -0,0 +1,151 @@
+/*
+Copyright Gen Digital Inc. All Rights Reserved.
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package api
+
+import (
+	"errors"
+	"sync"
+	"sync/atomic"
+
+	kmsapi "github.com/dellekappa/kms-go/spi/kms"
+)
+
+// ErrKeyUsageExceeded is returned by a CountingSigner once its key produced the configured number of signatures.
+var ErrKeyUsageExceeded = errors.New("key usage limit exceeded") // nolint: gochecknoglobals
+
+var (
+	_ FixedKeySigner   = &CountingSigner{}
+	_ DigestSigner     = &CountingSigner{}
+	_ DualFormatSigner = &CountingSigner{}
+)
+
+// CountingSigner is a FixedKeySigner decorator enforcing a key usage policy: it counts the signatures produced by the
+// wrapped signer and fails with ErrKeyUsageExceeded once limit signatures were produced, so that the key gets rotated.
+// Failed signatures are not counted. It is safe for concurrent use.
+type CountingSigner struct {
+	signer     FixedKeySigner
+	limit      uint64
+	count      atomic.Uint64
+	onLimit    func(count uint64)
+	rotateOnce sync.Once
+}
+
+// CountingSignerOpt is an option of NewCountingSigner.
+type CountingSignerOpt func(s *CountingSigner)
+
+// WithRotationCallback option sets a callback invoked once, with the signature count, when the limit is reached,
+// i.e. after the last allowed signature is produced. It is called synchronously by the Sign call which reached the
+// limit.
+func WithRotationCallback(callback func(count uint64)) CountingSignerOpt {
+	return func(s *CountingSigner) {
+		s.onLimit = callback
+	}
+}
+
+// NewCountingSigner creates a CountingSigner allowing signer to produce at most limit signatures.
+func NewCountingSigner(signer FixedKeySigner, limit uint64, opts ...CountingSignerOpt) *CountingSigner {
+	s := &CountingSigner{
+		signer: signer,
+		limit:  limit,
+	}
+
+	for _, opt := range opts {
+		opt(s)
+	}
+
+	return s
+}
+
+// Sign signs msg with the wrapped signer, or returns ErrKeyUsageExceeded if the limit is reached.
+func (s *CountingSigner) Sign(msg []byte) ([]byte, error) {
+	return s.countSignature(func() ([]byte, error) {
+		return s.signer.Sign(msg)
+	})
+}
+
+// SignDigest signs digest with the wrapped signer, see DigestSigner, counting it as a signature. It returns
+// ErrNotSupported if the wrapped signer doesn't sign digests.
+func (s *CountingSigner) SignDigest(digest []byte) ([]byte, error) {
+	ds, ok := s.signer.(DigestSigner)
+	if !ok {
+		return nil, ErrNotSupported
+	}
+
+	return s.countSignature(func() ([]byte, error) {
+		return ds.SignDigest(digest)
+	})
+}
+
+// SignBoth signs msg with the wrapped signer, see DualFormatSigner, counting it as a single signature. It returns
+// ErrNotSupported if the wrapped signer doesn't support it.
+func (s *CountingSigner) SignBoth(msg []byte) ([]byte, []byte, error) {
+	ds, ok := s.signer.(DualFormatSigner)
+	if !ok {
+		return nil, nil, ErrNotSupported
+	}
+
+	var der []byte
+
+	p1363, err := s.countSignature(func() ([]byte, error) {
+		p1363, d, e := ds.SignBoth(msg)
+		der = d
+
+		return p1363, e
+	})
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return p1363, der, nil
+}
+
+// Algorithm returns the JOSE alg of the wrapped signer.
+func (s *CountingSigner) Algorithm() string {
+	return s.signer.Algorithm()
+}
+
+// KeyType returns the KMS key type of the wrapped signer.
+func (s *CountingSigner) KeyType() kmsapi.KeyType {
+	return s.signer.KeyType()
+}
+
+// Count returns the number of signatures produced so far, including signatures in progress.
+func (s *CountingSigner) Count() uint64 {
+	return s.count.Load()
+}
+
+// countSignature reserves a signature within the limit before calling sign, releasing it if sign fails.
+func (s *CountingSigner) countSignature(sign func() ([]byte, error)) ([]byte, error) {
+	var n uint64
+
+	for {
+		c := s.count.Load()
+		if c >= s.limit {
+			return nil, ErrKeyUsageExceeded
+		}
+
+		if s.count.CompareAndSwap(c, c+1) {
+			n = c + 1
+
+			break
+		}
+	}
+
+	sig, err := sign()
+	if err != nil {
+		s.count.Add(^uint64(0))
+
+		return nil, err
+	}
+
+	if n == s.limit && s.onLimit != nil {
+		s.rotateOnce.Do(func() {
+			s.onLimit(n)
+		})
+	}
+
+	return sig, nil
+}
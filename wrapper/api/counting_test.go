@@ -0,0 +1,154 @@
+/*
+Copyright Gen Digital Inc. All Rights Reserved.
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package api
+
+import (
+	"errors"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	kmsapi "github.com/dellekappa/kms-go/spi/kms"
+)
+
+type testSigner struct {
+	err error
+}
+
+func (s *testSigner) Sign(msg []byte) ([]byte, error) {
+	if s.err != nil {
+		return nil, s.err
+	}
+
+	return append([]byte("sig:"), msg...), nil
+}
+
+func (s *testSigner) Algorithm() string {
+	return "EdDSA"
+}
+
+func (s *testSigner) KeyType() kmsapi.KeyType {
+	return kmsapi.ED25519Type
+}
+
+type testDigestSigner struct {
+	testSigner
+}
+
+func (s *testDigestSigner) SignDigest(digest []byte) ([]byte, error) {
+	return s.Sign(digest)
+}
+
+func (s *testDigestSigner) SignBoth(msg []byte) ([]byte, []byte, error) {
+	sig, err := s.Sign(msg)
+
+	return sig, sig, err
+}
+
+func TestCountingSigner(t *testing.T) {
+	t.Run("limit", func(t *testing.T) {
+		var rotated []uint64
+
+		s := NewCountingSigner(&testSigner{}, 2, WithRotationCallback(func(count uint64) {
+			rotated = append(rotated, count)
+		}))
+		require.Equal(t, "EdDSA", s.Algorithm())
+		require.Equal(t, kmsapi.ED25519Type, s.KeyType())
+
+		sig, err := s.Sign([]byte("1"))
+		require.NoError(t, err)
+		require.Equal(t, []byte("sig:1"), sig)
+		require.Empty(t, rotated)
+
+		_, err = s.Sign([]byte("2"))
+		require.NoError(t, err)
+		require.Equal(t, []uint64{2}, rotated)
+
+		_, err = s.Sign([]byte("3"))
+		require.ErrorIs(t, err, ErrKeyUsageExceeded)
+		require.Equal(t, uint64(2), s.Count())
+		require.Equal(t, []uint64{2}, rotated)
+	})
+
+	t.Run("failed signatures are not counted", func(t *testing.T) {
+		signer := &testSigner{err: errors.New("sign failed")}
+		s := NewCountingSigner(signer, 1)
+
+		_, err := s.Sign([]byte("msg"))
+		require.EqualError(t, err, "sign failed")
+		require.Zero(t, s.Count())
+
+		signer.err = nil
+
+		_, err = s.Sign([]byte("msg"))
+		require.NoError(t, err)
+		require.Equal(t, uint64(1), s.Count())
+	})
+
+	t.Run("digest and dual format signatures are counted", func(t *testing.T) {
+		s := NewCountingSigner(&testDigestSigner{}, 2)
+
+		_, err := s.SignDigest([]byte("digest"))
+		require.NoError(t, err)
+
+		p1363, der, err := s.SignBoth([]byte("msg"))
+		require.NoError(t, err)
+		require.Equal(t, []byte("sig:msg"), p1363)
+		require.Equal(t, []byte("sig:msg"), der)
+
+		_, err = s.SignDigest([]byte("digest"))
+		require.ErrorIs(t, err, ErrKeyUsageExceeded)
+
+		_, _, err = s.SignBoth([]byte("msg"))
+		require.ErrorIs(t, err, ErrKeyUsageExceeded)
+
+		s = NewCountingSigner(&testSigner{}, 2)
+
+		_, err = s.SignDigest([]byte("digest"))
+		require.ErrorIs(t, err, ErrNotSupported)
+
+		_, _, err = s.SignBoth([]byte("msg"))
+		require.ErrorIs(t, err, ErrNotSupported)
+		require.Zero(t, s.Count())
+	})
+
+	t.Run("concurrent signatures", func(t *testing.T) {
+		const limit = 50
+
+		var (
+			wg        sync.WaitGroup
+			mu        sync.Mutex
+			signed    int
+			rotations int
+		)
+
+		s := NewCountingSigner(&testSigner{}, limit, WithRotationCallback(func(uint64) {
+			mu.Lock()
+			rotations++
+			mu.Unlock()
+		}))
+
+		for i := 0; i < 2*limit; i++ {
+			wg.Add(1)
+
+			go func() {
+				defer wg.Done()
+
+				if _, err := s.Sign([]byte("msg")); err == nil {
+					mu.Lock()
+					signed++
+					mu.Unlock()
+				}
+			}()
+		}
+
+		wg.Wait()
+
+		require.Equal(t, limit, signed)
+		require.Equal(t, 1, rotations)
+	})
+}
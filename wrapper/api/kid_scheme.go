@@ -0,0 +1,77 @@
+/*
+Copyright Gen Digital Inc. All Rights Reserved.
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package api
+
+import (
+	"fmt"
+
+	"github.com/dellekappa/kms-go/doc/jose/jwk"
+	"github.com/dellekappa/kms-go/doc/util/jwkkid"
+	kmsapi "github.com/dellekappa/kms-go/spi/kms"
+)
+
+var (
+	_ KIDScheme = ThumbprintScheme{}
+	_ KIDScheme = PassthroughScheme{}
+	_ KIDScheme = KIDSchemeFunc(nil)
+)
+
+// KIDScheme derives the kid of the public JWKs returned by a KeyCreator from the key created in the KMS: its storageID,
+// the ID the KMS stores it under (e.g. an AWS KMS key ARN), and its public key bytes pubBytes of type kt, as exported
+// by the KMS.
+//
+// The kid may differ from storageID. Signing with such a JWK through a KMSCrypto, which looks keys up by kid, then
+// requires mapping the kid back to storageID, e.g. with a jose.KIDMapper.
+type KIDScheme interface {
+	KID(storageID string, pubBytes []byte, kt kmsapi.KeyType) (string, error)
+}
+
+// KIDSchemeFunc is a KIDScheme function, e.g. wrapping the storage ID of the key in a caller specific kid format.
+type KIDSchemeFunc func(storageID string, pubBytes []byte, kt kmsapi.KeyType) (string, error)
+
+// KID calls f(storageID, pubBytes, kt).
+func (f KIDSchemeFunc) KID(storageID string, pubBytes []byte, kt kmsapi.KeyType) (string, error) {
+	return f(storageID, pubBytes, kt)
+}
+
+// ThumbprintScheme is a KIDScheme whose kid is the base64url encoded RFC 7638 thumbprint of the public key (see
+// jwkkid.CreateKID), which is also the storage ID of keys created by the local KMS.
+type ThumbprintScheme struct{}
+
+// KID returns the thumbprint of pubBytes.
+func (ThumbprintScheme) KID(_ string, pubBytes []byte, kt kmsapi.KeyType) (string, error) {
+	return jwkkid.CreateKID(pubBytes, kt)
+}
+
+// PassthroughScheme is a KIDScheme using the KMS storage ID of the key verbatim as kid, e.g. the ARN of AWS KMS keys.
+type PassthroughScheme struct{}
+
+// KID returns storageID.
+func (PassthroughScheme) KID(storageID string, _ []byte, _ kmsapi.KeyType) (string, error) {
+	return storageID, nil
+}
+
+// ApplyKIDScheme returns pub, whose kid is the KMS storage ID of the key, with the kid derived by scheme from its public
+// key bytes pubBytes of type kt. pub is returned as is if scheme is nil, it is copied rather than modified otherwise.
+func ApplyKIDScheme(scheme KIDScheme, pub *jwk.JWK, pubBytes []byte, kt kmsapi.KeyType) (*jwk.JWK, error) {
+	if scheme == nil {
+		return pub, nil
+	}
+
+	kid, err := scheme.KID(pub.KeyID, pubBytes, kt)
+	if err != nil {
+		return nil, fmt.Errorf("kid scheme: %w", err)
+	}
+
+	if kid != pub.KeyID {
+		// don't modify a JWK owned by the KMS.
+		pubCopy := *pub
+		pubCopy.KeyID = kid
+		pub = &pubCopy
+	}
+
+	return pub, nil
+}
@@ -6,8 +6,13 @@ SPDX-License-Identifier: Apache-2.0
 package localsuite
 
 import (
+	"errors"
+	"fmt"
+
 	"github.com/dellekappa/kms-go/doc/jose/jwk"
 	"github.com/dellekappa/kms-go/doc/jose/jwk/jwksupport"
+	"github.com/dellekappa/kms-go/doc/util/kmssigner"
+	kmsservice "github.com/dellekappa/kms-go/kms"
 	"github.com/dellekappa/kms-go/spi/kms"
 	"github.com/dellekappa/kms-go/wrapper/api"
 )
@@ -18,10 +23,20 @@ func newKeyCreator(kms keyCreator) api.RawKeyCreator {
 
 type keyCreatorImpl struct {
 	kms keyCreator
+	// keyRefKMS and cr are required to create non-extractable keys.
+	keyRefKMS keyGetter
+	cr        signer
+	// kidScheme derives the kid of the created JWKs, they keep the KMS storage ID if nil.
+	kidScheme api.KIDScheme
 }
 
 func (k *keyCreatorImpl) Create(keyType kms.KeyType) (*jwk.JWK, error) {
-	return createKey(k.kms, keyType)
+	pub, pubBytes, err := createKeyWithPubBytes(k.kms, keyType)
+	if err != nil {
+		return nil, err
+	}
+
+	return k.applyKIDScheme(pub, pubBytes, keyType)
 }
 
 func (k *keyCreatorImpl) ExportPubKeyBytes(id string) ([]byte, kms.KeyType, error) {
@@ -29,7 +44,7 @@ func (k *keyCreatorImpl) ExportPubKeyBytes(id string) ([]byte, kms.KeyType, erro
 }
 
 func (k *keyCreatorImpl) CreateRaw(keyType kms.KeyType) (string, interface{}, error) {
-	kid, pkBytes, err := k.kms.CreateAndExportPubKeyBytes(keyType)
+	kid, pkBytes, err := createAndExportPubKeyBytes(k.kms, keyType)
 	if err != nil {
 		return "", nil, err
 	}
@@ -42,20 +57,202 @@ func (k *keyCreatorImpl) CreateRaw(keyType kms.KeyType) (string, interface{}, er
 	return kid, raw, nil
 }
 
+// CreateNonExtractable creates a key of keyType and returns a KeyRef signing with it inside the KMS, and its public
+// key. The private key is never retrieved from the KMS outside of signing operations.
+func (k *keyCreatorImpl) CreateNonExtractable(keyType kms.KeyType) (api.KeyRef, *jwk.JWK, error) {
+	if k.keyRefKMS == nil || k.cr == nil {
+		return nil, nil, api.ErrNotSupported
+	}
+
+	pub, pubBytes, err := createKeyWithPubBytes(k.kms, keyType)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	keyRef := &keyRefImpl{kid: pub.KeyID, keyType: keyType, kms: k.keyRefKMS, cr: k.cr}
+
+	pub, err = k.applyKIDScheme(pub, pubBytes, keyType)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return keyRef, pub, nil
+}
+
+// applyKIDScheme sets the kid of pub, whose kid is the KMS storage ID of the key, with the kidScheme of k.
+func (k *keyCreatorImpl) applyKIDScheme(pub *jwk.JWK, pubBytes []byte, keyType kms.KeyType) (*jwk.JWK, error) {
+	if k.kidScheme == nil {
+		return pub, nil
+	}
+
+	if pubBytes == nil {
+		var err error
+
+		pubBytes, _, err = k.kms.ExportPubKeyBytes(pub.KeyID)
+		if err != nil {
+			return nil, fmt.Errorf("kid scheme: %w", err)
+		}
+	}
+
+	return api.ApplyKIDScheme(k.kidScheme, pub, pubBytes, keyType)
+}
+
+// ImportJWK imports the private key of j in the KMS and returns the key ID assigned by the KMS.
+func (k *keyCreatorImpl) ImportJWK(j *jwk.JWK) (string, error) {
+	importer, ok := privateKeyImporterOf(k.kms)
+	if !ok {
+		return "", api.ErrNotSupported
+	}
+
+	privKey, err := jwksupport.PrivateKeyFromJWK(j)
+	if err != nil {
+		return "", fmt.Errorf("importJWK: %w", err)
+	}
+
+	keyType, err := j.KeyType()
+	if err != nil {
+		return "", fmt.Errorf("importJWK: %w", err)
+	}
+
+	kid, _, err := importer.ImportPrivateKey(privKey, keyType)
+	if err != nil {
+		return "", fmt.Errorf("importJWK: %w", err)
+	}
+
+	return kid, nil
+}
+
 func createKey(creator keyCreator, keyType kms.KeyType) (*jwk.JWK, error) {
-	kid, pkBytes, err := creator.CreateAndExportPubKeyBytes(keyType)
+	pk, _, err := createKeyWithPubBytes(creator, keyType)
+
+	return pk, err
+}
+
+// createKeyWithPubBytes creates a key of keyType and returns its public JWK, with the KMS storage ID as kid, and its
+// public key bytes as exported by the KMS.
+func createKeyWithPubBytes(creator keyCreator, keyType kms.KeyType) (*jwk.JWK, []byte, error) {
+	if exporter, ok := jwkExporterOf(creator); ok {
+		return createAndExportJWK(creator, exporter, keyType)
+	}
+
+	kid, pkBytes, err := createAndExportPubKeyBytes(creator, keyType)
 	if err != nil {
-		return nil, err
+		return nil, nil, err
 	}
 
 	pk, err := jwksupport.PubKeyBytesToJWK(pkBytes, keyType)
 	if err != nil {
-		return nil, err
+		return nil, nil, err
 	}
 
 	pk.KeyID = kid
 
-	return pk, nil
+	return pk, pkBytes, nil
+}
+
+// createAndExportPubKeyBytes creates a key of keyType and exports its public key. KMSs which don't export key material
+// at creation time return kmsservice.ErrKeyNotExportable with the created key ID, the public key is then exported on its
+// own.
+func createAndExportPubKeyBytes(creator keyCreator, keyType kms.KeyType) (string, []byte, error) {
+	kid, pkBytes, err := creator.CreateAndExportPubKeyBytes(keyType)
+	if errors.Is(err, kmsservice.ErrKeyNotExportable) && kid != "" {
+		pkBytes, _, err = creator.ExportPubKeyBytes(kid)
+	}
+
+	if err != nil {
+		return "", nil, err
+	}
+
+	return kid, pkBytes, nil
+}
+
+// jwkExporterOf returns creator as an api.JWKExporter, looking through the key type cache.
+func jwkExporterOf(creator keyCreator) (api.JWKExporter, bool) {
+	if cached, ok := creator.(*cachedKeyCreator); ok {
+		creator = cached.keyCreator
+	}
+
+	exporter, ok := creator.(api.JWKExporter)
+
+	return exporter, ok
+}
+
+// privateKeyImporterOf returns creator as a privateKeyImporter, looking through the key type cache.
+func privateKeyImporterOf(creator keyCreator) (privateKeyImporter, bool) {
+	if cached, ok := creator.(*cachedKeyCreator); ok {
+		creator = cached.keyCreator
+	}
+
+	importer, ok := creator.(privateKeyImporter)
+
+	return importer, ok
+}
+
+// createAndExportJWK creates a key of keyType and exports its public JWK as stored by the KMS, keeping metadata like
+// the KMS-assigned alg. The key ID is the one returned at creation, even if the KMS doesn't export key material then.
+// The public key bytes are nil if the KMS didn't export them at creation.
+func createAndExportJWK(creator keyCreator, exporter api.JWKExporter, keyType kms.KeyType) (*jwk.JWK, []byte, error) {
+	kid, pkBytes, err := creator.CreateAndExportPubKeyBytes(keyType)
+	if err != nil && (!errors.Is(err, kmsservice.ErrKeyNotExportable) || kid == "") {
+		return nil, nil, err
+	}
+
+	pk, _, err := exporter.ExportJWK(kid)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	if pk.KeyID != kid {
+		// don't modify the JWK owned by the KMS.
+		pkCopy := *pk
+		pkCopy.KeyID = kid
+		pk = &pkCopy
+	}
+
+	return pk, pkBytes, nil
+}
+
+// KeyTypeCacheStats returns the statistics of the key type cache, or empty statistics if the cache is disabled.
+func (k *keyCreatorImpl) KeyTypeCacheStats() KeyTypeCacheStats {
+	cached, ok := k.kms.(*cachedKeyCreator)
+	if !ok {
+		return KeyTypeCacheStats{}
+	}
+
+	return cached.cache.stats()
+}
+
+// keyRefImpl signs with the key kid, fetching its handle from the KMS for each signature.
+type keyRefImpl struct {
+	kid     string
+	keyType kms.KeyType
+	kms     keyGetter
+	cr      signer
+}
+
+func (r *keyRefImpl) KeyID() string {
+	return r.kid
 }
 
-var _ api.KeyCreator = &keyCreatorImpl{}
+func (r *keyRefImpl) Algorithm() string {
+	return kmssigner.KeyTypeToJWA(r.keyType)
+}
+
+func (r *keyRefImpl) KeyType() kms.KeyType {
+	return r.keyType
+}
+
+func (r *keyRefImpl) Sign(msg []byte) ([]byte, error) {
+	kh, err := r.kms.Get(r.kid)
+	if err != nil {
+		return nil, err
+	}
+
+	return signerFor(r.keyType, r.cr).Sign(msg, kh)
+}
+
+var (
+	_ api.KeyCreator               = &keyCreatorImpl{}
+	_ api.NonExtractableKeyCreator = &keyCreatorImpl{}
+	_ api.ImportingKeyCreator      = &keyCreatorImpl{}
+)
@@ -6,15 +6,26 @@ SPDX-License-Identifier: Apache-2.0
 package localsuite
 
 import (
+	"crypto/ecdsa"
 	"crypto/ed25519"
+	"crypto/elliptic"
 	"crypto/rand"
 	"errors"
 	"testing"
 
+	"github.com/go-jose/go-jose/v3"
 	"github.com/stretchr/testify/require"
 
+	"github.com/dellekappa/kms-go/doc/jose/jwk"
+	"github.com/dellekappa/kms-go/doc/jose/jwk/jwksupport"
+	"github.com/dellekappa/kms-go/doc/util/jwkkid"
+	mockstorage "github.com/dellekappa/kms-go/internal/mock/storage"
+	"github.com/dellekappa/kms-go/kms"
+	mockcrypto "github.com/dellekappa/kms-go/mock/crypto"
 	mockkms "github.com/dellekappa/kms-go/mock/kms"
+	"github.com/dellekappa/kms-go/secretlock/noop"
 	kmsapi "github.com/dellekappa/kms-go/spi/kms"
+	"github.com/dellekappa/kms-go/wrapper/api"
 )
 
 const (
@@ -90,3 +101,379 @@ func TestKeyCreator(t *testing.T) {
 		require.Empty(t, kid)
 	})
 }
+
+func TestKeyCreator_CreateNonExtractable(t *testing.T) {
+	t.Run("success with local suite", func(t *testing.T) {
+		store, err := kms.NewAriesProviderWrapper(mockstorage.NewMockStoreProvider())
+		require.NoError(t, err)
+
+		suite, err := NewLocalCryptoSuite("local-lock://custom/primary/key/", store, &noop.NoLock{},
+			WithKeyTypeCache(10))
+		require.NoError(t, err)
+
+		creator, err := suite.KeyCreator()
+		require.NoError(t, err)
+
+		nonExtractable, ok := creator.(api.NonExtractableKeyCreator)
+		require.True(t, ok)
+
+		keyRef, pub, err := nonExtractable.CreateNonExtractable(kmsapi.ECDSAP256TypeDER)
+		require.NoError(t, err)
+		require.Equal(t, pub.KeyID, keyRef.KeyID())
+		require.True(t, pub.IsPublic())
+		require.Equal(t, kmsapi.ECDSAP256TypeDER, keyRef.KeyType())
+		require.Equal(t, "ES256", keyRef.Algorithm())
+
+		sig, err := keyRef.Sign([]byte("msg"))
+		require.NoError(t, err)
+
+		verifier, err := suite.KMSCryptoVerifier()
+		require.NoError(t, err)
+		require.NoError(t, verifier.Verify(sig, []byte("msg"), pub))
+	})
+
+	t.Run("success with KMS not exporting key material at creation", func(t *testing.T) {
+		keyBytes, _, err := ed25519.GenerateKey(rand.Reader)
+		require.NoError(t, err)
+
+		km := &nonExportingKeyManager{KeyManager: &mockkms.KeyManager{
+			CrAndExportPubKeyID:    keyID,
+			ExportPubKeyBytesValue: keyBytes,
+			ExportPubKeyTypeValue:  kmsapi.ED25519Type,
+		}}
+
+		creator := &keyCreatorImpl{kms: km, keyRefKMS: km, cr: &mockcrypto.Crypto{SignValue: []byte("sig")}}
+
+		keyRef, pub, err := creator.CreateNonExtractable(kmsapi.ED25519Type)
+		require.NoError(t, err)
+		require.Equal(t, keyID, pub.KeyID)
+		require.Equal(t, ed25519.PublicKey(keyBytes), pub.Key)
+		require.Equal(t, keyID, keyRef.KeyID())
+
+		sig, err := keyRef.Sign([]byte("msg"))
+		require.NoError(t, err)
+		require.Equal(t, []byte("sig"), sig)
+
+		kid, pubRaw, err := creator.CreateRaw(kmsapi.ED25519Type)
+		require.NoError(t, err)
+		require.Equal(t, keyID, kid)
+		require.Equal(t, ed25519.PublicKey(keyBytes), pubRaw)
+	})
+
+	t.Run("error public key not exportable", func(t *testing.T) {
+		km := &nonExportingKeyManager{KeyManager: &mockkms.KeyManager{
+			CrAndExportPubKeyID:  keyID,
+			ExportPubKeyBytesErr: kms.ErrKeyNotExportable,
+		}}
+
+		creator := &keyCreatorImpl{kms: km, keyRefKMS: km, cr: &mockcrypto.Crypto{}}
+
+		_, _, err := creator.CreateNonExtractable(kmsapi.ED25519Type)
+		require.ErrorIs(t, err, kms.ErrKeyNotExportable)
+	})
+
+	t.Run("error key handle", func(t *testing.T) {
+		errExpected := errors.New("expected error")
+
+		keyBytes, _, err := ed25519.GenerateKey(rand.Reader)
+		require.NoError(t, err)
+
+		km := &mockkms.KeyManager{
+			CrAndExportPubKeyValue: keyBytes,
+			CrAndExportPubKeyID:    keyID,
+			GetKeyErr:              errExpected,
+		}
+
+		creator := &keyCreatorImpl{kms: km, keyRefKMS: km, cr: &mockcrypto.Crypto{}}
+
+		keyRef, _, err := creator.CreateNonExtractable(kmsapi.ED25519Type)
+		require.NoError(t, err)
+
+		_, err = keyRef.Sign([]byte("msg"))
+		require.ErrorIs(t, err, errExpected)
+	})
+
+	t.Run("error not supported", func(t *testing.T) {
+		creator := newKeyCreator(&mockkms.KeyManager{})
+
+		_, _, err := creator.(api.NonExtractableKeyCreator).CreateNonExtractable(kmsapi.ED25519Type)
+		require.ErrorIs(t, err, api.ErrNotSupported)
+	})
+}
+
+func TestKeyCreator_ExportJWK(t *testing.T) {
+	pubKey, _, err := ed25519.GenerateKey(rand.Reader)
+	require.NoError(t, err)
+
+	kmsJWK := &jwk.JWK{
+		JSONWebKey: jose.JSONWebKey{KeyID: "kms-kid", Algorithm: "EdDSA", Key: pubKey},
+		Kty:        "OKP",
+		Crv:        "Ed25519",
+	}
+
+	t.Run("success", func(t *testing.T) {
+		km := &jwkExportingKeyManager{
+			KeyManager: &mockkms.KeyManager{CrAndExportPubKeyID: keyID, CrAndExportPubKeyValue: []byte("ignored")},
+			jwk:        kmsJWK,
+		}
+
+		for name, creator := range map[string]api.RawKeyCreator{
+			"direct":         newKeyCreator(km),
+			"key type cache": newKeyCreator(&cachedKeyCreator{keyCreator: km, cache: newKeyTypeCache(10, 0)}),
+		} {
+			t.Run(name, func(t *testing.T) {
+				pubJWK, err := creator.Create(kmsapi.ED25519Type)
+				require.NoError(t, err)
+				require.Equal(t, keyID, pubJWK.KeyID)
+				require.Equal(t, "EdDSA", pubJWK.Algorithm)
+				require.Equal(t, pubKey, pubJWK.Key)
+
+				// the KMS JWK is left untouched.
+				require.Equal(t, "kms-kid", kmsJWK.KeyID)
+			})
+		}
+	})
+
+	t.Run("success KMS not exporting key material at creation", func(t *testing.T) {
+		km := &jwkExportingKeyManager{
+			KeyManager: &mockkms.KeyManager{CrAndExportPubKeyID: keyID},
+			jwk:        kmsJWK,
+		}
+
+		pubJWK, err := newKeyCreator(&nonExportingJWKKeyManager{jwkExportingKeyManager: km}).Create(kmsapi.ED25519Type)
+		require.NoError(t, err)
+		require.Equal(t, keyID, pubJWK.KeyID)
+		require.Equal(t, "EdDSA", pubJWK.Algorithm)
+	})
+
+	t.Run("kms create err", func(t *testing.T) {
+		errExpected := errors.New("expected error")
+
+		creator := newKeyCreator(&jwkExportingKeyManager{
+			KeyManager: &mockkms.KeyManager{CrAndExportPubKeyErr: errExpected},
+			jwk:        kmsJWK,
+		})
+
+		pubJWK, err := creator.Create(kmsapi.ED25519Type)
+		require.ErrorIs(t, err, errExpected)
+		require.Nil(t, pubJWK)
+	})
+
+	t.Run("ExportJWK err", func(t *testing.T) {
+		errExpected := errors.New("expected error")
+
+		creator := newKeyCreator(&jwkExportingKeyManager{
+			KeyManager: &mockkms.KeyManager{CrAndExportPubKeyID: keyID},
+			err:        errExpected,
+		})
+
+		pubJWK, err := creator.Create(kmsapi.ED25519Type)
+		require.ErrorIs(t, err, errExpected)
+		require.Nil(t, pubJWK)
+	})
+}
+
+func TestKeyCreator_KIDScheme(t *testing.T) {
+	const arn = "arn:aws:kms:eu-west-1:111122223333:key/1234abcd-12ab-34cd-56ef-1234567890ab"
+
+	keyBytes, _, err := ed25519.GenerateKey(rand.Reader)
+	require.NoError(t, err)
+
+	thumbprint, err := jwkkid.CreateKID(keyBytes, kmsapi.ED25519Type)
+	require.NoError(t, err)
+
+	t.Run("default thumbprint scheme with local suite", func(t *testing.T) {
+		store, err := kms.NewAriesProviderWrapper(mockstorage.NewMockStoreProvider())
+		require.NoError(t, err)
+
+		suite, err := NewLocalCryptoSuite("local-lock://custom/primary/key/", store, &noop.NoLock{})
+		require.NoError(t, err)
+
+		creator, err := suite.KeyCreator()
+		require.NoError(t, err)
+
+		pub, err := creator.Create(kmsapi.ED25519Type)
+		require.NoError(t, err)
+
+		pubBytes, err := pub.PublicKeyBytes()
+		require.NoError(t, err)
+
+		kid, err := jwkkid.CreateKID(pubBytes, kmsapi.ED25519Type)
+		require.NoError(t, err)
+		require.Equal(t, kid, pub.KeyID)
+
+		// the local KMS stores keys under their thumbprint, they can be signed with by kid.
+		kmsCrypto, err := suite.KMSCrypto()
+		require.NoError(t, err)
+
+		_, err = kmsCrypto.Sign([]byte("msg"), pub)
+		require.NoError(t, err)
+	})
+
+	t.Run("schemes", func(t *testing.T) {
+		for name, tc := range map[string]struct {
+			scheme api.KIDScheme
+			kid    string
+		}{
+			"thumbprint":  {api.ThumbprintScheme{}, thumbprint},
+			"passthrough": {api.PassthroughScheme{}, arn},
+			"func": {api.KIDSchemeFunc(func(storageID string, _ []byte, kt kmsapi.KeyType) (string, error) {
+				return storageID + "#" + string(kt), nil
+			}), arn + "#ED25519"},
+		} {
+			km := &mockkms.KeyManager{
+				CrAndExportPubKeyValue: keyBytes,
+				CrAndExportPubKeyID:    arn,
+			}
+
+			creator := &keyCreatorImpl{kms: km, keyRefKMS: km, cr: &mockcrypto.Crypto{}, kidScheme: tc.scheme}
+
+			pub, err := creator.Create(kmsapi.ED25519Type)
+			require.NoError(t, err, name)
+			require.Equal(t, tc.kid, pub.KeyID, name)
+
+			keyRef, pub, err := creator.CreateNonExtractable(kmsapi.ED25519Type)
+			require.NoError(t, err, name)
+			require.Equal(t, tc.kid, pub.KeyID, name)
+			require.Equal(t, arn, keyRef.KeyID(), name)
+
+			kid, _, err := creator.CreateRaw(kmsapi.ED25519Type)
+			require.NoError(t, err, name)
+			require.Equal(t, arn, kid, name)
+		}
+	})
+
+	t.Run("KMS exporting JWKs without key material at creation", func(t *testing.T) {
+		kmsJWK := &jwk.JWK{
+			JSONWebKey: jose.JSONWebKey{KeyID: arn, Key: ed25519.PublicKey(keyBytes)},
+			Kty:        "OKP",
+			Crv:        "Ed25519",
+		}
+
+		km := &nonExportingJWKKeyManager{jwkExportingKeyManager: &jwkExportingKeyManager{
+			KeyManager: &mockkms.KeyManager{
+				CrAndExportPubKeyID:    arn,
+				ExportPubKeyBytesValue: keyBytes,
+				ExportPubKeyTypeValue:  kmsapi.ED25519Type,
+			},
+			jwk: kmsJWK,
+		}}
+
+		pub, err := (&keyCreatorImpl{kms: km, kidScheme: api.ThumbprintScheme{}}).Create(kmsapi.ED25519Type)
+		require.NoError(t, err)
+		require.Equal(t, thumbprint, pub.KeyID)
+
+		// the KMS JWK is left untouched.
+		require.Equal(t, arn, kmsJWK.KeyID)
+	})
+
+	t.Run("scheme error", func(t *testing.T) {
+		errExpected := errors.New("expected error")
+
+		km := &mockkms.KeyManager{CrAndExportPubKeyValue: keyBytes, CrAndExportPubKeyID: arn}
+
+		creator := &keyCreatorImpl{kms: km, keyRefKMS: km, cr: &mockcrypto.Crypto{},
+			kidScheme: api.KIDSchemeFunc(func(string, []byte, kmsapi.KeyType) (string, error) {
+				return "", errExpected
+			})}
+
+		_, err := creator.Create(kmsapi.ED25519Type)
+		require.ErrorIs(t, err, errExpected)
+
+		_, _, err = creator.CreateNonExtractable(kmsapi.ED25519Type)
+		require.ErrorIs(t, err, errExpected)
+	})
+}
+
+func TestKeyCreator_ImportJWK(t *testing.T) {
+	km, _ := newTestLocalKMS(t)
+
+	_, edPrivKey, err := ed25519.GenerateKey(rand.Reader)
+	require.NoError(t, err)
+
+	ecPrivKey, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+
+	t.Run("success", func(t *testing.T) {
+		for name, creator := range map[string]api.RawKeyCreator{
+			"direct":         newKeyCreator(km),
+			"key type cache": newKeyCreator(&cachedKeyCreator{keyCreator: km, cache: newKeyTypeCache(10, 0)}),
+		} {
+			t.Run(name, func(t *testing.T) {
+				for keyType, privKey := range map[kmsapi.KeyType]interface{}{
+					kmsapi.ED25519Type:            edPrivKey,
+					kmsapi.ECDSAP256TypeIEEEP1363: ecPrivKey,
+				} {
+					privJWK := &jwk.JWK{JSONWebKey: jose.JSONWebKey{KeyID: "partner-kid", Key: privKey}}
+
+					kid, err := creator.(api.ImportingKeyCreator).ImportJWK(privJWK)
+					require.NoError(t, err)
+					require.NotEqual(t, "partner-kid", kid)
+
+					pkBytes, kt, err := creator.ExportPubKeyBytes(kid)
+					require.NoError(t, err)
+					require.Equal(t, keyType, kt)
+
+					pubJWK, err := jwksupport.PubKeyBytesToJWK(pkBytes, kt)
+					require.NoError(t, err)
+					require.True(t, pubJWK.SamePublicKey(privJWK))
+				}
+			})
+		}
+	})
+
+	t.Run("error public JWK", func(t *testing.T) {
+		pubJWK := &jwk.JWK{JSONWebKey: jose.JSONWebKey{Key: edPrivKey.Public()}}
+
+		_, err := newKeyCreator(km).(api.ImportingKeyCreator).ImportJWK(pubJWK)
+		require.ErrorIs(t, err, jwksupport.ErrNoPrivateKey)
+	})
+
+	t.Run("error KMS import failure", func(t *testing.T) {
+		creator := newKeyCreator(&mockkms.KeyManager{ImportPrivateKeyErr: errors.New("import failed")})
+
+		_, err := creator.(api.ImportingKeyCreator).ImportJWK(&jwk.JWK{JSONWebKey: jose.JSONWebKey{Key: edPrivKey}})
+		require.EqualError(t, err, "importJWK: import failed")
+	})
+
+	t.Run("error not supported", func(t *testing.T) {
+		creator := newKeyCreator(struct{ keyCreator }{km})
+
+		_, err := creator.(api.ImportingKeyCreator).ImportJWK(&jwk.JWK{JSONWebKey: jose.JSONWebKey{Key: edPrivKey}})
+		require.ErrorIs(t, err, api.ErrNotSupported)
+	})
+}
+
+// jwkExportingKeyManager implements api.JWKExporter, exporting jwk or returning err.
+type jwkExportingKeyManager struct {
+	*mockkms.KeyManager
+	jwk *jwk.JWK
+	err error
+}
+
+func (m *jwkExportingKeyManager) ExportJWK(string) (*jwk.JWK, kmsapi.KeyType, error) {
+	if m.err != nil {
+		return nil, "", m.err
+	}
+
+	return m.jwk, kmsapi.ED25519Type, nil
+}
+
+// nonExportingJWKKeyManager is a jwkExportingKeyManager not exporting key material at creation.
+type nonExportingJWKKeyManager struct {
+	*jwkExportingKeyManager
+}
+
+func (m *nonExportingJWKKeyManager) CreateAndExportPubKeyBytes(kmsapi.KeyType,
+	...kmsapi.KeyOpts) (string, []byte, error) {
+	return m.CrAndExportPubKeyID, nil, kms.ErrKeyNotExportable
+}
+
+// nonExportingKeyManager returns kms.ErrKeyNotExportable with the created key ID from CreateAndExportPubKeyBytes.
+type nonExportingKeyManager struct {
+	*mockkms.KeyManager
+}
+
+func (m *nonExportingKeyManager) CreateAndExportPubKeyBytes(kmsapi.KeyType, ...kmsapi.KeyOpts) (string, []byte, error) {
+	return m.CrAndExportPubKeyID, nil, kms.ErrKeyNotExportable
+}
@@ -0,0 +1,118 @@
+/*
+Copyright Gen Digital Inc. All Rights Reserved.
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package localsuite
+
+import (
+	"sync"
+	"time"
+
+	"github.com/bluele/gcache"
+
+	kmsapi "github.com/dellekappa/kms-go/spi/kms"
+)
+
+// defaultKeyTypeCacheTTL is the time after which a cached key type expires, so that rotated keys are eventually
+// re-exported from the KMS.
+const defaultKeyTypeCacheTTL = 5 * time.Minute
+
+// KeyTypeCacheStats holds the statistics of a KeyCreator key type cache.
+type KeyTypeCacheStats struct {
+	Hits   uint64
+	Misses uint64
+	Size   int
+}
+
+// KeyTypeCacheStatsProvider is implemented by KeyCreators built with the WithKeyTypeCache option.
+type KeyTypeCacheStatsProvider interface {
+	KeyTypeCacheStats() KeyTypeCacheStats
+}
+
+type exportedKey struct {
+	pubKey  []byte
+	keyType kmsapi.KeyType
+}
+
+// keyTypeCache caches ExportPubKeyBytes results by key ID.
+type keyTypeCache struct {
+	mu    sync.Mutex
+	cache gcache.Cache
+}
+
+func newKeyTypeCache(size int, ttl time.Duration) *keyTypeCache {
+	return &keyTypeCache{
+		cache: gcache.New(size).LRU().Expiration(ttl).Build(),
+	}
+}
+
+func (c *keyTypeCache) get(kid string) ([]byte, kmsapi.KeyType, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	v, err := c.cache.Get(kid)
+	if err != nil {
+		return nil, "", false
+	}
+
+	key, ok := v.(*exportedKey)
+	if !ok {
+		return nil, "", false
+	}
+
+	return key.pubKey, key.keyType, true
+}
+
+func (c *keyTypeCache) set(kid string, pubKey []byte, keyType kmsapi.KeyType) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	// gcache Set only fails when a serialize function is configured, which is not the case here.
+	_ = c.cache.Set(kid, &exportedKey{pubKey: pubKey, keyType: keyType}) //nolint:errcheck
+}
+
+func (c *keyTypeCache) stats() KeyTypeCacheStats {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	return KeyTypeCacheStats{
+		Hits:   c.cache.HitCount(),
+		Misses: c.cache.MissCount(),
+		Size:   c.cache.Len(false),
+	}
+}
+
+// cachedKeyCreator is a keyCreator caching the public key bytes and key type of the keys it creates and exports.
+type cachedKeyCreator struct {
+	keyCreator
+	cache *keyTypeCache
+}
+
+func (c *cachedKeyCreator) CreateAndExportPubKeyBytes(kt kmsapi.KeyType,
+	opts ...kmsapi.KeyOpts) (string, []byte, error) {
+	kid, pubKey, err := c.keyCreator.CreateAndExportPubKeyBytes(kt, opts...)
+	if err != nil {
+		// the key ID is returned with kms.ErrKeyNotExportable errors.
+		return kid, nil, err
+	}
+
+	c.cache.set(kid, pubKey, kt)
+
+	return kid, pubKey, nil
+}
+
+func (c *cachedKeyCreator) ExportPubKeyBytes(id string) ([]byte, kmsapi.KeyType, error) {
+	if pubKey, kt, ok := c.cache.get(id); ok {
+		return pubKey, kt, nil
+	}
+
+	pubKey, kt, err := c.keyCreator.ExportPubKeyBytes(id)
+	if err != nil {
+		return nil, "", err
+	}
+
+	c.cache.set(id, pubKey, kt)
+
+	return pubKey, kt, nil
+}
@@ -0,0 +1,145 @@
+/*
+Copyright Gen Digital Inc. All Rights Reserved.
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package localsuite
+
+import (
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	mockstorage "github.com/dellekappa/kms-go/internal/mock/storage"
+	"github.com/dellekappa/kms-go/kms"
+	mockkms "github.com/dellekappa/kms-go/mock/kms"
+	"github.com/dellekappa/kms-go/secretlock/noop"
+	kmsapi "github.com/dellekappa/kms-go/spi/kms"
+)
+
+func TestKeyTypeCache(t *testing.T) {
+	t.Run("success cached export", func(t *testing.T) {
+		store, err := kms.NewAriesProviderWrapper(mockstorage.NewMockStoreProvider())
+		require.NoError(t, err)
+
+		suite, err := NewLocalCryptoSuite("local-lock://custom/primary/key/", store, &noop.NoLock{},
+			WithKeyTypeCache(10))
+		require.NoError(t, err)
+
+		creator, err := suite.KeyCreator()
+		require.NoError(t, err)
+
+		pub, err := creator.Create(kmsapi.ECDSAP256TypeDER)
+		require.NoError(t, err)
+
+		statsProvider, ok := creator.(KeyTypeCacheStatsProvider)
+		require.True(t, ok)
+		require.Equal(t, KeyTypeCacheStats{Size: 1}, statsProvider.KeyTypeCacheStats())
+
+		// a new KeyCreator from the same suite shares the cache.
+		creator, err = suite.KeyCreator()
+		require.NoError(t, err)
+
+		pkBytes, kt, err := creator.ExportPubKeyBytes(pub.KeyID)
+		require.NoError(t, err)
+		require.Equal(t, kmsapi.ECDSAP256TypeDER, kt)
+		require.NotEmpty(t, pkBytes)
+
+		require.Equal(t, KeyTypeCacheStats{Hits: 1, Size: 1},
+			creator.(KeyTypeCacheStatsProvider).KeyTypeCacheStats())
+	})
+
+	t.Run("export is served from cache until ttl expires", func(t *testing.T) {
+		km := &countingKeyCreator{KeyManager: &mockkms.KeyManager{
+			ExportPubKeyBytesValue: []byte("key"),
+			ExportPubKeyTypeValue:  kmsapi.ED25519Type,
+		}}
+
+		creator := newKeyCreator(&cachedKeyCreator{keyCreator: km, cache: newKeyTypeCache(10, 50*time.Millisecond)})
+
+		for i := 0; i < 3; i++ {
+			pkBytes, kt, err := creator.ExportPubKeyBytes(keyID)
+			require.NoError(t, err)
+			require.Equal(t, []byte("key"), pkBytes)
+			require.Equal(t, kmsapi.ED25519Type, kt)
+		}
+
+		require.Equal(t, 1, km.exports)
+		require.Equal(t, KeyTypeCacheStats{Hits: 2, Misses: 1, Size: 1},
+			creator.(KeyTypeCacheStatsProvider).KeyTypeCacheStats())
+
+		time.Sleep(100 * time.Millisecond)
+
+		_, _, err := creator.ExportPubKeyBytes(keyID)
+		require.NoError(t, err)
+		require.Equal(t, 2, km.exports)
+	})
+
+	t.Run("concurrent exports", func(t *testing.T) {
+		km := &countingKeyCreator{KeyManager: &mockkms.KeyManager{
+			ExportPubKeyBytesValue: []byte("key"),
+			ExportPubKeyTypeValue:  kmsapi.ED25519Type,
+		}}
+
+		creator := newKeyCreator(&cachedKeyCreator{keyCreator: km, cache: newKeyTypeCache(10, time.Minute)})
+
+		var wg sync.WaitGroup
+
+		for i := 0; i < 20; i++ {
+			wg.Add(1)
+
+			go func() {
+				defer wg.Done()
+
+				_, kt, err := creator.ExportPubKeyBytes(keyID)
+				require.NoError(t, err)
+				require.Equal(t, kmsapi.ED25519Type, kt)
+			}()
+		}
+
+		wg.Wait()
+
+		stats := creator.(KeyTypeCacheStatsProvider).KeyTypeCacheStats()
+		require.EqualValues(t, 20, stats.Hits+stats.Misses)
+	})
+
+	t.Run("export error is not cached", func(t *testing.T) {
+		errExpected := errors.New("expected error")
+
+		km := &countingKeyCreator{KeyManager: &mockkms.KeyManager{ExportPubKeyBytesErr: errExpected}}
+
+		creator := newKeyCreator(&cachedKeyCreator{keyCreator: km, cache: newKeyTypeCache(10, time.Minute)})
+
+		_, _, err := creator.ExportPubKeyBytes(keyID)
+		require.ErrorIs(t, err, errExpected)
+
+		_, _, err = creator.ExportPubKeyBytes(keyID)
+		require.ErrorIs(t, err, errExpected)
+		require.Equal(t, 2, km.exports)
+		require.Zero(t, creator.(KeyTypeCacheStatsProvider).KeyTypeCacheStats().Size)
+	})
+
+	t.Run("disabled cache has empty stats", func(t *testing.T) {
+		creator := newKeyCreator(&mockkms.KeyManager{})
+
+		require.Equal(t, KeyTypeCacheStats{}, creator.(KeyTypeCacheStatsProvider).KeyTypeCacheStats())
+	})
+}
+
+type countingKeyCreator struct {
+	*mockkms.KeyManager
+
+	mu      sync.Mutex
+	exports int
+}
+
+func (c *countingKeyCreator) ExportPubKeyBytes(id string) ([]byte, kmsapi.KeyType, error) {
+	c.mu.Lock()
+	c.exports++
+	c.mu.Unlock()
+
+	return c.KeyManager.ExportPubKeyBytes(id)
+}
@@ -22,7 +22,17 @@ func NewLocalCryptoSuite(
 	primaryKeyURI string,
 	keyStore kmsapi.Store,
 	secretLock secretlock.Service,
+	opts ...Opt,
 ) (api.Suite, error) {
+	options := &suiteOpts{
+		keyTypeCacheTTL:     defaultKeyTypeCacheTTL,
+		verifierKeyCacheTTL: defaultVerifierKeyCacheTTL,
+	}
+
+	for _, opt := range opts {
+		opt(options)
+	}
+
 	kms, err := localkms.New(primaryKeyURI, &kmsProv{
 		store: keyStore,
 		lock:  secretLock,
@@ -31,15 +41,35 @@ func NewLocalCryptoSuite(
 		return nil, fmt.Errorf("initializing local key manager: %w", err)
 	}
 
-	crypto, err := tinkcrypto.New()
+	tinkCrypto, err := tinkcrypto.New()
 	if err != nil {
 		return nil, err
 	}
 
-	return &suiteImpl{
-		kms:    kms,
-		crypto: crypto,
-	}, nil
+	var crypto allCrypto = tinkCrypto
+
+	if options.ed25519Opts != nil || options.ecdsaHash != 0 {
+		crypto, err = newSignOptsCrypto(tinkCrypto, options)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	suite := &suiteImpl{
+		kms:       kms,
+		crypto:    crypto,
+		kidScheme: options.kidScheme,
+	}
+
+	if options.keyTypeCacheSize > 0 {
+		suite.keyTypeCache = newKeyTypeCache(options.keyTypeCacheSize, options.keyTypeCacheTTL)
+	}
+
+	if options.verifierKeyCacheSize > 0 {
+		suite.verifierKeyCache = newVerifierKeyCache(options.verifierKeyCacheSize, options.verifierKeyCacheTTL)
+	}
+
+	return suite, nil
 }
 
 type kmsProv struct {
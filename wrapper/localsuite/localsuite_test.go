@@ -6,12 +6,19 @@ SPDX-License-Identifier: Apache-2.0
 package localsuite
 
 import (
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/sha512"
+	"math/big"
 	"testing"
 
 	mockstorage "github.com/dellekappa/kms-go/internal/mock/storage"
 	"github.com/dellekappa/kms-go/kms"
 	"github.com/dellekappa/kms-go/secretlock/noop"
+	kmsapi "github.com/dellekappa/kms-go/spi/kms"
 	"github.com/stretchr/testify/require"
+	"golang.org/x/crypto/sha3"
 )
 
 func TestNewLocalCryptoSuite(t *testing.T) {
@@ -34,3 +41,136 @@ func TestNewLocalCryptoSuite(t *testing.T) {
 		require.Nil(t, suite)
 	})
 }
+
+func TestNewLocalCryptoSuiteWithEd25519Options(t *testing.T) {
+	ctx := []byte("domain separation context")
+
+	tests := []struct {
+		name string
+		opt  Opt
+		opts *ed25519.Options
+	}{
+		{name: "Ed25519ph", opt: WithEd25519ph(ctx), opts: &ed25519.Options{Hash: crypto.SHA512, Context: string(ctx)}},
+		{name: "Ed25519ctx", opt: WithEd25519ctx(ctx), opts: &ed25519.Options{Context: string(ctx)}},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			store, err := kms.NewAriesProviderWrapper(mockstorage.NewMockStoreProvider())
+			require.NoError(t, err)
+
+			suite, err := NewLocalCryptoSuite("local-lock://custom/primary/key/", store, &noop.NoLock{}, tc.opt)
+			require.NoError(t, err)
+
+			creator, err := suite.KeyCreator()
+			require.NoError(t, err)
+
+			kc, err := suite.KMSCrypto()
+			require.NoError(t, err)
+
+			msg := []byte("test message")
+
+			edPub, err := creator.Create(kmsapi.ED25519Type)
+			require.NoError(t, err)
+
+			sig, err := kc.Sign(msg, edPub)
+			require.NoError(t, err)
+
+			require.NoError(t, kc.Verify(sig, msg, edPub))
+
+			pubKey, ok := edPub.Key.(ed25519.PublicKey)
+			require.True(t, ok)
+			require.NoError(t, ed25519.VerifyWithOptions(pubKey, prehash(msg, tc.opts), sig, tc.opts))
+			require.False(t, ed25519.Verify(pubKey, msg, sig))
+
+			fks, err := suite.FixedKeySigner(edPub.KeyID)
+			require.NoError(t, err)
+
+			sig, err = fks.Sign(msg)
+			require.NoError(t, err)
+			require.NoError(t, kc.Verify(sig, msg, edPub))
+
+			// other key types are not affected.
+			ecPub, err := creator.Create(kmsapi.ECDSAP256TypeIEEEP1363)
+			require.NoError(t, err)
+
+			sig, err = kc.Sign(msg, ecPub)
+			require.NoError(t, err)
+			require.NoError(t, kc.Verify(sig, msg, ecPub))
+		})
+	}
+
+	t.Run("error invalid context", func(t *testing.T) {
+		store, err := kms.NewAriesProviderWrapper(mockstorage.NewMockStoreProvider())
+		require.NoError(t, err)
+
+		_, err = NewLocalCryptoSuite("local-lock://custom/primary/key/", store, &noop.NoLock{},
+			WithEd25519ph(make([]byte, 256)))
+		require.EqualError(t, err, "ed25519 options: context of 256 bytes exceeds the maximum of 255 bytes")
+
+		_, err = NewLocalCryptoSuite("local-lock://custom/primary/key/", store, &noop.NoLock{},
+			WithEd25519ctx(nil))
+		require.EqualError(t, err, "ed25519 options: Ed25519ctx requires a non empty context")
+	})
+}
+
+func prehash(msg []byte, opts *ed25519.Options) []byte {
+	if opts.Hash != crypto.SHA512 {
+		return msg
+	}
+
+	digest := sha512.Sum512(msg)
+
+	return digest[:]
+}
+
+func TestNewLocalCryptoSuiteWithECDSAHash(t *testing.T) {
+	store, err := kms.NewAriesProviderWrapper(mockstorage.NewMockStoreProvider())
+	require.NoError(t, err)
+
+	suite, err := NewLocalCryptoSuite("local-lock://custom/primary/key/", store, &noop.NoLock{},
+		WithECDSAHash(crypto.SHA3_256), WithEd25519ctx([]byte("context")))
+	require.NoError(t, err)
+
+	creator, err := suite.KeyCreator()
+	require.NoError(t, err)
+
+	kc, err := suite.KMSCrypto()
+	require.NoError(t, err)
+
+	msg := []byte("test message")
+
+	for _, kt := range []kmsapi.KeyType{kmsapi.ECDSAP256TypeIEEEP1363, kmsapi.ECDSAP521TypeDER,
+		kmsapi.ECDSASecp256k1TypeIEEEP1363} {
+		pub, err := creator.Create(kt)
+		require.NoError(t, err)
+
+		sig, err := kc.Sign(msg, pub)
+		require.NoError(t, err)
+		require.NoError(t, kc.Verify(sig, msg, pub))
+
+		if kt == kmsapi.ECDSAP256TypeIEEEP1363 {
+			ecPub, ok := pub.Key.(*ecdsa.PublicKey)
+			require.True(t, ok)
+
+			digest := sha3.Sum256(msg)
+			require.True(t, ecdsa.Verify(ecPub, digest[:], new(big.Int).SetBytes(sig[:32]),
+				new(big.Int).SetBytes(sig[32:])))
+		}
+	}
+
+	// Ed25519 keys still use the Ed25519 options.
+	edPub, err := creator.Create(kmsapi.ED25519Type)
+	require.NoError(t, err)
+
+	sig, err := kc.Sign(msg, edPub)
+	require.NoError(t, err)
+	require.NoError(t, kc.Verify(sig, msg, edPub))
+	require.False(t, ed25519.Verify(edPub.Key.(ed25519.PublicKey), msg, sig))
+
+	t.Run("error hash not available", func(t *testing.T) {
+		_, err = NewLocalCryptoSuite("local-lock://custom/primary/key/", store, &noop.NoLock{},
+			WithECDSAHash(crypto.MD4))
+		require.EqualError(t, err, "ecdsa options: hash MD4 is not available")
+	})
+}
@@ -7,10 +7,12 @@ package localsuite
 
 import (
 	"github.com/dellekappa/kms-go/doc/jose/jwk"
+	"github.com/dellekappa/kms-go/doc/util/kmssigner"
+	"github.com/dellekappa/kms-go/spi/kms"
 	"github.com/dellekappa/kms-go/wrapper/api"
 )
 
-func newKMSCryptoMultiSigner(kms keyGetter, crypto multiSigner) api.KMSCryptoMultiSigner {
+func newKMSCryptoMultiSigner(kms signingKeyGetter, crypto multiSigner) api.KMSCryptoMultiSigner {
 	return &multiSignerImpl{
 		kms:    kms,
 		crypto: crypto,
@@ -18,7 +20,7 @@ func newKMSCryptoMultiSigner(kms keyGetter, crypto multiSigner) api.KMSCryptoMul
 }
 
 type multiSignerImpl struct {
-	kms    keyGetter
+	kms    signingKeyGetter
 	crypto multiSigner
 }
 
@@ -48,23 +50,25 @@ func (m *multiSignerImpl) FixedMultiSignerGivenKID(kid string) (api.FixedKeyMult
 	return getFixedMultiSigner(m.kms, m.crypto, kid)
 }
 
-func getFixedMultiSigner(kms keyGetter, crypto multiSigner, kid string) (api.FixedKeyMultiSigner, error) {
-	kh, err := kms.Get(kid)
+func getFixedMultiSigner(km signingKeyGetter, crypto multiSigner, kid string) (api.FixedKeyMultiSigner, error) {
+	kh, err := km.Get(kid)
 	if err != nil {
 		return nil, err
 	}
 
 	return &fixedMultiSignerImpl{
-		cr: crypto,
-		kh: kh,
+		cr:      crypto,
+		kh:      kh,
+		keyType: newLazyKeyType(km, kid),
 	}, nil
 }
 
 var _ api.KMSCryptoMultiSigner = &multiSignerImpl{}
 
 type fixedMultiSignerImpl struct {
-	cr multiSigner
-	kh interface{}
+	cr      multiSigner
+	kh      interface{}
+	keyType *lazyKeyType
 }
 
 func (f *fixedMultiSignerImpl) SignMulti(msgs [][]byte) ([]byte, error) {
@@ -75,4 +79,17 @@ func (f *fixedMultiSignerImpl) Sign(msg []byte) ([]byte, error) {
 	return f.cr.Sign(msg, f.kh)
 }
 
+func (f *fixedMultiSignerImpl) Algorithm() string {
+	return kmssigner.KeyTypeToJWA(f.KeyType())
+}
+
+func (f *fixedMultiSignerImpl) KeyType() kms.KeyType {
+	keyType, err := f.keyType.get()
+	if err != nil {
+		return ""
+	}
+
+	return keyType
+}
+
 var _ api.FixedKeyMultiSigner = &fixedMultiSignerImpl{}
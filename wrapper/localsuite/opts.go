@@ -0,0 +1,98 @@
+/*
+Copyright Gen Digital Inc. All Rights Reserved.
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package localsuite
+
+import (
+	"crypto"
+	"crypto/ed25519"
+	"time"
+
+	"github.com/dellekappa/kms-go/wrapper/api"
+)
+
+// Opt is a NewLocalCryptoSuite option.
+type Opt func(opts *suiteOpts)
+
+type suiteOpts struct {
+	keyTypeCacheSize     int
+	keyTypeCacheTTL      time.Duration
+	verifierKeyCacheSize int
+	verifierKeyCacheTTL  time.Duration
+	ed25519Opts          *ed25519.Options
+	ecdsaHash            crypto.Hash
+	kidScheme            api.KIDScheme
+}
+
+// WithKeyTypeCache enables an LRU cache of size entries in the suite's KeyCreators, mapping a key ID to its exported
+// public key bytes and key type. Cached entries expire after 5 minutes unless WithKeyTypeCacheTTL is set.
+// Cache statistics are available by asserting the KeyCreator to a KeyTypeCacheStatsProvider.
+func WithKeyTypeCache(size int) Opt {
+	return func(opts *suiteOpts) {
+		opts.keyTypeCacheSize = size
+	}
+}
+
+// WithKeyTypeCacheTTL sets the expiration time of the entries of the cache enabled with WithKeyTypeCache.
+func WithKeyTypeCacheTTL(ttl time.Duration) Opt {
+	return func(opts *suiteOpts) {
+		opts.keyTypeCacheTTL = ttl
+	}
+}
+
+// WithVerifierKeyCache enables an LRU cache of size entries in the suite's KMSCrypto and KMSCryptoVerifier, mapping the
+// RFC 7638 thumbprint and kid of the JWKs signatures are verified against to their parsed verification key, so that
+// repeated verifications against the same JWK don't parse the key again. Cached entries expire after 5 minutes unless
+// WithVerifierKeyCacheTTL is set. Cache statistics are available by asserting the KMSCrypto or KMSCryptoVerifier to a
+// VerifierKeyCacheStatsProvider.
+func WithVerifierKeyCache(size int) Opt {
+	return func(opts *suiteOpts) {
+		opts.verifierKeyCacheSize = size
+	}
+}
+
+// WithVerifierKeyCacheTTL sets the expiration time of the entries of the cache enabled with WithVerifierKeyCache.
+func WithVerifierKeyCacheTTL(ttl time.Duration) Opt {
+	return func(opts *suiteOpts) {
+		opts.verifierKeyCacheTTL = ttl
+	}
+}
+
+// WithEd25519ph makes the suite's signers and verifiers use Ed25519ph (pre-hashed Ed25519, RFC 8032) with the given
+// domain separation context for Ed25519 keys. The message is hashed with SHA-512 by the suite. Other key types are not
+// affected. context must not exceed 255 bytes.
+func WithEd25519ph(context []byte) Opt {
+	return func(opts *suiteOpts) {
+		opts.ed25519Opts = &ed25519.Options{Hash: crypto.SHA512, Context: string(context)}
+	}
+}
+
+// WithEd25519ctx makes the suite's signers and verifiers use Ed25519ctx (RFC 8032) with the given domain separation
+// context for Ed25519 keys. Other key types are not affected. context must not be empty nor exceed 255 bytes.
+func WithEd25519ctx(context []byte) Opt {
+	return func(opts *suiteOpts) {
+		opts.ed25519Opts = &ed25519.Options{Context: string(context)}
+	}
+}
+
+// WithECDSAHash makes the suite's signers and verifiers digest messages with hash for ECDSA keys (NIST P and secp256k1
+// curves) instead of the SHA-2 hash of the key type, for example crypto.SHA3_256. Other key types are not affected.
+// JWS signed this way must use a non registered `alg` value chosen by the caller, as ES256/ES384/ES512 and ES256K
+// mandate SHA-2 digests. Any hash can be used with any curve, the suite fails to be created only if hash is not
+// available.
+func WithECDSAHash(hash crypto.Hash) Opt {
+	return func(opts *suiteOpts) {
+		opts.ecdsaHash = hash
+	}
+}
+
+// WithKIDScheme sets the api.KIDScheme deriving the kid of the public JWKs returned by the suite's KeyCreators, which
+// keep the KMS storage ID of their key by default. The KeyRefs of non-extractable keys and the key IDs returned by CreateRaw stay
+// the KMS storage IDs, as do the kids of the JWKs created by the KMSCrypto, which looks keys up by kid.
+func WithKIDScheme(scheme api.KIDScheme) Opt {
+	return func(opts *suiteOpts) {
+		opts.kidScheme = scheme
+	}
+}
@@ -6,6 +6,9 @@ SPDX-License-Identifier: Apache-2.0
 package localsuite
 
 import (
+	"github.com/google/tink/go/tink"
+
+	cryptoapi "github.com/dellekappa/kms-go/spi/crypto"
 	kmsapi "github.com/dellekappa/kms-go/spi/kms"
 )
 
@@ -13,6 +16,28 @@ type signer interface {
 	Sign(msg []byte, kh interface{}) ([]byte, error)
 }
 
+// digestSigner signs and verifies digests computed outside of the KMS, see tinkcrypto.Crypto.SignDigest.
+type digestSigner interface {
+	SignDigest(digest []byte, kh interface{}) ([]byte, error)
+	VerifyDigest(sig, digest []byte, kh interface{}) error
+}
+
+// dualFormatSigner signs once with ECDSA keys, returning both signature encodings, see tinkcrypto.Crypto.SignBoth.
+type dualFormatSigner interface {
+	SignBoth(msg []byte, kh interface{}) ([]byte, []byte, error)
+}
+
+// primitiveSigner creates signing primitives reusable across Sign calls, see tinkcrypto.Crypto.NewSigner. Cryptos
+// signing with suite options (signOptsCrypto) don't implement it, their signatures differ from the primitive's ones.
+type primitiveSigner interface {
+	NewSigner(kh interface{}) (tink.Signer, error)
+}
+
+// keyAgreer computes ECDH shared secrets with private key handles, see tinkcrypto.Crypto.ComputeSharedSecret.
+type keyAgreer interface {
+	ComputeSharedSecret(recPubKey *cryptoapi.PublicKey, kh interface{}) ([]byte, error)
+}
+
 type multiSigner interface {
 	signer
 	SignMulti(messages [][]byte, kh interface{}) ([]byte, error)
@@ -31,12 +56,23 @@ type keyGetter interface {
 	Get(keyID string) (interface{}, error)
 }
 
+// signingKeyGetter gets signing key handles along with their key type.
+type signingKeyGetter interface {
+	keyGetter
+	ExportPubKeyBytes(keyID string) ([]byte, kmsapi.KeyType, error)
+}
+
 type keyHandleFetcher interface {
 	PubKeyBytesToHandle(pubKeyBytes []byte, keyType kmsapi.KeyType, opts ...kmsapi.KeyOpts) (interface{}, error)
 	ExportPubKeyBytes(keyID string) ([]byte, kmsapi.KeyType, error)
 	keyGetter
 }
 
+// privateKeyImporter imports private keys in the KMS, see kms.KeyManager.ImportPrivateKey.
+type privateKeyImporter interface {
+	ImportPrivateKey(privKey interface{}, kt kmsapi.KeyType, opts ...kmsapi.PrivateKeyOpts) (string, interface{}, error)
+}
+
 type keyCreator interface {
 	CreateAndExportPubKeyBytes(kt kmsapi.KeyType, opts ...kmsapi.KeyOpts) (string, []byte, error)
 	ExportPubKeyBytes(id string) ([]byte, kmsapi.KeyType, error)
@@ -0,0 +1,93 @@
+/*
+Copyright Gen Digital Inc. All Rights Reserved.
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package localsuite
+
+import (
+	"crypto"
+	"crypto/ed25519"
+	"errors"
+	"fmt"
+
+	"github.com/dellekappa/kms-go/crypto/tinkcrypto"
+)
+
+type signOptsCryptoImpl interface {
+	allCrypto
+	SignEd25519(msg []byte, kh interface{}, opts *ed25519.Options) ([]byte, error)
+	VerifyEd25519(sig, msg []byte, kh interface{}, opts *ed25519.Options) error
+	SignECDSA(msg []byte, kh interface{}, hash crypto.Hash) ([]byte, error)
+	VerifyECDSA(sig, msg []byte, kh interface{}, hash crypto.Hash) error
+	digestSigner
+	dualFormatSigner
+}
+
+// signOptsCrypto signs and verifies with Ed25519ph or Ed25519ctx options when the key is an Ed25519 key and with an
+// ECDSA hash override when the key is an ECDSA key, other key types are signed and verified by the wrapped crypto as
+// usual.
+type signOptsCrypto struct {
+	signOptsCryptoImpl
+	ed25519Opts *ed25519.Options
+	ecdsaHash   crypto.Hash
+}
+
+func newSignOptsCrypto(crypto signOptsCryptoImpl, options *suiteOpts) (*signOptsCrypto, error) {
+	if opts := options.ed25519Opts; opts != nil {
+		if len(opts.Context) > tinkcrypto.Ed25519MaxContextSize {
+			return nil, fmt.Errorf("ed25519 options: context of %d bytes exceeds the maximum of %d bytes",
+				len(opts.Context), tinkcrypto.Ed25519MaxContextSize)
+		}
+
+		if opts.Hash == 0 && opts.Context == "" {
+			return nil, errors.New("ed25519 options: Ed25519ctx requires a non empty context")
+		}
+	}
+
+	if options.ecdsaHash != 0 && !options.ecdsaHash.Available() {
+		return nil, fmt.Errorf("ecdsa options: hash %s is not available", options.ecdsaHash)
+	}
+
+	return &signOptsCrypto{
+		signOptsCryptoImpl: crypto,
+		ed25519Opts:        options.ed25519Opts,
+		ecdsaHash:          options.ecdsaHash,
+	}, nil
+}
+
+func (c *signOptsCrypto) Sign(msg []byte, kh interface{}) ([]byte, error) {
+	if c.ed25519Opts != nil {
+		sig, err := c.SignEd25519(msg, kh, c.ed25519Opts)
+		if !errors.Is(err, tinkcrypto.ErrNotEd25519Key) {
+			return sig, err
+		}
+	}
+
+	if c.ecdsaHash != 0 {
+		sig, err := c.SignECDSA(msg, kh, c.ecdsaHash)
+		if !errors.Is(err, tinkcrypto.ErrNotECDSAKey) {
+			return sig, err
+		}
+	}
+
+	return c.signOptsCryptoImpl.Sign(msg, kh)
+}
+
+func (c *signOptsCrypto) Verify(sig, msg []byte, kh interface{}) error {
+	if c.ed25519Opts != nil {
+		err := c.VerifyEd25519(sig, msg, kh, c.ed25519Opts)
+		if !errors.Is(err, tinkcrypto.ErrNotEd25519Key) {
+			return err
+		}
+	}
+
+	if c.ecdsaHash != 0 {
+		err := c.VerifyECDSA(sig, msg, kh, c.ecdsaHash)
+		if !errors.Is(err, tinkcrypto.ErrNotECDSAKey) {
+			return err
+		}
+	}
+
+	return c.signOptsCryptoImpl.Verify(sig, msg, kh)
+}
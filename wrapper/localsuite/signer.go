@@ -6,12 +6,17 @@ SPDX-License-Identifier: Apache-2.0
 package localsuite
 
 import (
+	"sync"
+
 	"github.com/dellekappa/kms-go/doc/jose/jwk"
+	"github.com/dellekappa/kms-go/doc/jose/jwk/jwksupport"
+	"github.com/dellekappa/kms-go/doc/util/kmssigner"
+	"github.com/dellekappa/kms-go/spi/kms"
 	"github.com/dellekappa/kms-go/wrapper/api"
 )
 
 // newKMSCryptoSigner creates a KMSCryptoSigner using the given kms and crypto implementations.
-func newKMSCryptoSigner(kms keyGetter, crypto signer) api.KMSCryptoSigner {
+func newKMSCryptoSigner(kms signingKeyGetter, crypto signer) api.KMSCryptoSigner {
 	return &kmsCryptoSignerImpl{
 		kms:    kms,
 		crypto: crypto,
@@ -19,7 +24,7 @@ func newKMSCryptoSigner(kms keyGetter, crypto signer) api.KMSCryptoSigner {
 }
 
 type kmsCryptoSignerImpl struct {
-	kms    keyGetter
+	kms    signingKeyGetter
 	crypto signer
 }
 
@@ -29,26 +34,132 @@ func (k *kmsCryptoSignerImpl) Sign(msg []byte, pub *jwk.JWK) ([]byte, error) {
 		return nil, err
 	}
 
-	return k.crypto.Sign(msg, kh)
+	return jwkSigner(pub, k.crypto).Sign(msg, kh)
 }
 
 func (k *kmsCryptoSignerImpl) FixedKeySigner(pub *jwk.JWK) (api.FixedKeySigner, error) {
-	kh, err := k.kms.Get(pub.KeyID)
+	return makeFixedKeySigner(k.kms, k.crypto, pub.KeyID)
+}
+
+func makeFixedKeySigner(km signingKeyGetter, crypto signer, kid string) (api.FixedKeySigner, error) {
+	kh, err := km.Get(kid)
 	if err != nil {
 		return nil, err
 	}
 
 	return &fixedKeySignerImpl{
-		cr: k.crypto,
-		kh: kh,
+		cr:      crypto,
+		kh:      kh,
+		keyType: newLazyKeyType(km, kid),
 	}, nil
 }
 
+// lazyKeyType resolves the key type of a signing key from the KMS the first time it is needed, so that creating a
+// signer doesn't export the public key, which is an extra KMS round trip and fails for non-exportable keys.
+type lazyKeyType struct {
+	km      signingKeyGetter
+	kid     string
+	once    sync.Once
+	keyType kms.KeyType
+	err     error
+}
+
+func newLazyKeyType(km signingKeyGetter, kid string) *lazyKeyType {
+	return &lazyKeyType{km: km, kid: kid}
+}
+
+// get returns the key type, exporting the public key from the KMS on the first call only.
+func (l *lazyKeyType) get() (kms.KeyType, error) {
+	l.once.Do(func() {
+		_, l.keyType, l.err = l.km.ExportPubKeyBytes(l.kid)
+	})
+
+	return l.keyType, l.err
+}
+
+// signer returns the signing backend of the key, see signerFor. The key type is resolved only if signing backends
+// are registered with jwksupport, crypto is returned if it can't be resolved.
+func (l *lazyKeyType) signer(crypto signer) signer {
+	if !jwksupport.HasKeyTypeSigners() {
+		return crypto
+	}
+
+	keyType, err := l.get()
+	if err != nil {
+		return crypto
+	}
+
+	return signerFor(keyType, crypto)
+}
+
+// signerFor returns the signing backend registered with jwksupport for keyType (e.g. ML-DSA), or crypto if there is
+// none.
+func signerFor(keyType kms.KeyType, crypto signer) signer {
+	if keyTypeSigner, ok := jwksupport.KeyTypeSignerFor(keyType); ok {
+		return keyTypeSigner
+	}
+
+	return crypto
+}
+
+// jwkSigner returns the signing backend of the key type of pub, see signerFor.
+func jwkSigner(pub *jwk.JWK, crypto signer) signer {
+	keyType, err := pub.KeyType()
+	if err != nil {
+		return crypto
+	}
+
+	return signerFor(keyType, crypto)
+}
+
+var (
+	_ api.DigestSigner     = &fixedKeySignerImpl{}
+	_ api.DualFormatSigner = &fixedKeySignerImpl{}
+)
+
 type fixedKeySignerImpl struct {
-	cr signer
-	kh interface{}
+	cr      signer
+	kh      interface{}
+	keyType *lazyKeyType
 }
 
 func (f *fixedKeySignerImpl) Sign(msg []byte) ([]byte, error) {
-	return f.cr.Sign(msg, f.kh)
+	return f.keyType.signer(f.cr).Sign(msg, f.kh)
+}
+
+// SignDigest signs digest without hashing it, see api.DigestSigner. It returns api.ErrNotSupported if the crypto
+// doesn't sign digests.
+func (f *fixedKeySignerImpl) SignDigest(digest []byte) ([]byte, error) {
+	ds, ok := f.keyType.signer(f.cr).(digestSigner)
+	if !ok {
+		return nil, api.ErrNotSupported
+	}
+
+	return ds.SignDigest(digest, f.kh)
+}
+
+// SignBoth signs msg once and returns the signature encoded as IEEE P1363 and as DER, see api.DualFormatSigner. It
+// returns api.ErrNotSupported if the crypto doesn't support it.
+func (f *fixedKeySignerImpl) SignBoth(msg []byte) ([]byte, []byte, error) {
+	ds, ok := f.keyType.signer(f.cr).(dualFormatSigner)
+	if !ok {
+		return nil, nil, api.ErrNotSupported
+	}
+
+	return ds.SignBoth(msg, f.kh)
+}
+
+// Algorithm returns the JOSE alg of the signing key, or "" if its key type can't be resolved from the KMS.
+func (f *fixedKeySignerImpl) Algorithm() string {
+	return kmssigner.KeyTypeToJWA(f.KeyType())
+}
+
+// KeyType returns the key type of the signing key, or "" if it can't be resolved from the KMS.
+func (f *fixedKeySignerImpl) KeyType() kms.KeyType {
+	keyType, err := f.keyType.get()
+	if err != nil {
+		return ""
+	}
+
+	return keyType
 }
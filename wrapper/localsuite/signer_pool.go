@@ -0,0 +1,93 @@
+/*
+Copyright Gen Digital Inc. All Rights Reserved.
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package localsuite
+
+import (
+	"fmt"
+
+	"github.com/google/tink/go/tink"
+
+	"github.com/dellekappa/kms-go/doc/jose/jwk"
+	"github.com/dellekappa/kms-go/doc/jose/jwk/jwksupport"
+	"github.com/dellekappa/kms-go/doc/util/kmssigner"
+	cryptoapi "github.com/dellekappa/kms-go/spi/crypto"
+	kmsapi "github.com/dellekappa/kms-go/spi/kms"
+	"github.com/dellekappa/kms-go/wrapper/api"
+)
+
+var _ api.FixedKeySigner = &SignerPool{}
+
+// SignerPool signs with a single KMS key from many goroutines. Unlike a signer built per call, it gets the key handle,
+// key type and public JWK of the key once, when it's created, and its Sign is safe for concurrent use. When the
+// crypto creates reusable signing primitives (see tinkcrypto.Crypto.NewSigner), the primitive is created once and
+// shared by all Sign calls as well.
+type SignerPool struct {
+	cr        signer
+	kh        interface{}
+	primitive tink.Signer
+	keyType   kmsapi.KeyType
+	pub       *jwk.JWK
+}
+
+// NewSignerPool creates a SignerPool signing with the key kid of keyManager using crypto.
+func NewSignerPool(keyManager kmsapi.KeyManager, crypto cryptoapi.Crypto, kid string) (*SignerPool, error) {
+	kh, err := keyManager.Get(kid)
+	if err != nil {
+		return nil, fmt.Errorf("newSignerPool: get key: %w", err)
+	}
+
+	pubKey, keyType, err := keyManager.ExportPubKeyBytes(kid)
+	if err != nil {
+		return nil, fmt.Errorf("newSignerPool: export public key: %w", err)
+	}
+
+	pub, err := jwksupport.PubKeyBytesToJWK(pubKey, keyType)
+	if err != nil {
+		return nil, fmt.Errorf("newSignerPool: %w", err)
+	}
+
+	pub.KeyID = kid
+
+	pool := &SignerPool{
+		cr:      signerFor(keyType, crypto),
+		kh:      kh,
+		keyType: keyType,
+		pub:     pub,
+	}
+
+	// keys not supported by the crypto's signing primitives (e.g. BBS+ keys) are signed with Sign.
+	if ps, ok := pool.cr.(primitiveSigner); ok {
+		if primitive, e := ps.NewSigner(kh); e == nil {
+			pool.primitive = primitive
+		}
+	}
+
+	return pool, nil
+}
+
+// Sign signs msg with the key of the pool.
+func (p *SignerPool) Sign(msg []byte) ([]byte, error) {
+	if p.primitive != nil {
+		return p.primitive.Sign(msg)
+	}
+
+	return p.cr.Sign(msg, p.kh)
+}
+
+// Algorithm returns the JOSE alg of the signatures, see api.FixedKeySigner.
+func (p *SignerPool) Algorithm() string {
+	return kmssigner.KeyTypeToJWA(p.keyType)
+}
+
+// KeyType returns the KMS key type of the signing key.
+func (p *SignerPool) KeyType() kmsapi.KeyType {
+	return p.keyType
+}
+
+// PublicJWK returns a copy of the public JWK of the signing key, its kid is the KMS key ID.
+func (p *SignerPool) PublicJWK() *jwk.JWK {
+	return p.pub.Clone()
+}
@@ -0,0 +1,152 @@
+/*
+Copyright Gen Digital Inc. All Rights Reserved.
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package localsuite
+
+import (
+	"errors"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/dellekappa/kms-go/crypto/tinkcrypto"
+	mockstorage "github.com/dellekappa/kms-go/internal/mock/storage"
+	"github.com/dellekappa/kms-go/kms"
+	"github.com/dellekappa/kms-go/kms/localkms"
+	mockcrypto "github.com/dellekappa/kms-go/mock/crypto"
+	mockkms "github.com/dellekappa/kms-go/mock/kms"
+	"github.com/dellekappa/kms-go/secretlock/noop"
+	kmsapi "github.com/dellekappa/kms-go/spi/kms"
+)
+
+func newTestLocalKMS(tb testing.TB) (*localkms.LocalKMS, *tinkcrypto.Crypto) {
+	tb.Helper()
+
+	store, err := kms.NewAriesProviderWrapper(mockstorage.NewMockStoreProvider())
+	require.NoError(tb, err)
+
+	km, err := localkms.New("local-lock://custom/primary/key/", &kmsProv{store: store, lock: &noop.NoLock{}})
+	require.NoError(tb, err)
+
+	cr, err := tinkcrypto.New()
+	require.NoError(tb, err)
+
+	return km, cr
+}
+
+func TestSignerPool(t *testing.T) {
+	km, cr := newTestLocalKMS(t)
+
+	for _, tc := range []struct {
+		keyType kmsapi.KeyType
+		alg     string
+	}{
+		{keyType: kmsapi.ECDSAP256TypeIEEEP1363, alg: "ES256"},
+		{keyType: kmsapi.ED25519Type, alg: "EdDSA"},
+		{keyType: kmsapi.ECDSAP521TypeIEEEP1363, alg: "ES512"},
+	} {
+		kid, _, err := km.CreateAndExportPubKeyBytes(tc.keyType)
+		require.NoError(t, err)
+
+		pool, err := NewSignerPool(km, cr, kid)
+		require.NoError(t, err)
+		require.Equal(t, tc.keyType, pool.KeyType())
+		require.Equal(t, tc.alg, pool.Algorithm())
+
+		pub := pool.PublicJWK()
+		require.Equal(t, kid, pub.KeyID)
+
+		pub.KeyID = "modified"
+		require.Equal(t, kid, pool.PublicJWK().KeyID)
+
+		var wg sync.WaitGroup
+
+		sigs := make([][]byte, 10)
+		errs := make([]error, 10)
+
+		for i := range sigs {
+			wg.Add(1)
+
+			go func(i int) {
+				defer wg.Done()
+
+				sigs[i], errs[i] = pool.Sign([]byte("message"))
+			}(i)
+		}
+
+		wg.Wait()
+
+		verifier := newKMSCrypto(km, cr)
+
+		for i := range sigs {
+			require.NoError(t, errs[i], tc.keyType)
+			require.NoError(t, verifier.Verify(sigs[i], []byte("message"), pool.PublicJWK()), tc.keyType)
+		}
+	}
+
+	t.Run("crypto without signing primitives", func(t *testing.T) {
+		kid, _, err := km.CreateAndExportPubKeyBytes(kmsapi.ECDSAP256TypeIEEEP1363)
+		require.NoError(t, err)
+
+		pool, err := NewSignerPool(km, &mockcrypto.Crypto{SignValue: []byte("signature")}, kid)
+		require.NoError(t, err)
+
+		sig, err := pool.Sign([]byte("message"))
+		require.NoError(t, err)
+		require.Equal(t, []byte("signature"), sig)
+	})
+
+	t.Run("KMS failures", func(t *testing.T) {
+		_, err := NewSignerPool(&mockkms.KeyManager{GetKeyErr: errors.New("get failed")}, cr, "kid")
+		require.EqualError(t, err, "newSignerPool: get key: get failed")
+
+		_, err = NewSignerPool(&mockkms.KeyManager{ExportPubKeyBytesErr: errors.New("export failed")}, cr, "kid")
+		require.EqualError(t, err, "newSignerPool: export public key: export failed")
+	})
+}
+
+func BenchmarkSignerPool(b *testing.B) {
+	km, cr := newTestLocalKMS(b)
+
+	kid, _, err := km.CreateAndExportPubKeyBytes(kmsapi.ECDSAP256TypeIEEEP1363)
+	require.NoError(b, err)
+
+	msg := []byte("message")
+
+	b.Run("SignerPool", func(b *testing.B) {
+		pool, err := NewSignerPool(km, cr, kid)
+		require.NoError(b, err)
+
+		b.ReportAllocs()
+		b.ResetTimer()
+
+		b.RunParallel(func(pb *testing.PB) {
+			for pb.Next() {
+				if _, err := pool.Sign(msg); err != nil {
+					b.Fatal(err)
+				}
+			}
+		})
+	})
+
+	b.Run("FixedKeySigner per call", func(b *testing.B) {
+		b.ReportAllocs()
+		b.ResetTimer()
+
+		b.RunParallel(func(pb *testing.PB) {
+			for pb.Next() {
+				fks, err := makeFixedKeySigner(km, cr, kid)
+				if err != nil {
+					b.Fatal(err)
+				}
+
+				if _, err = fks.Sign(msg); err != nil {
+					b.Fatal(err)
+				}
+			}
+		})
+	})
+}
@@ -11,20 +11,46 @@ import (
 )
 
 type suiteImpl struct {
-	kms    keyManager
-	crypto allCrypto
+	kms              keyManager
+	crypto           allCrypto
+	keyTypeCache     *keyTypeCache
+	verifierKeyCache *verifierKeyCache
+	kidScheme        wrapperapi.KIDScheme
 }
 
 func (s *suiteImpl) KeyCreator() (wrapperapi.KeyCreator, error) {
-	return newKeyCreator(s.kms), nil
+	return s.newKeyCreator(), nil
 }
 
 func (s *suiteImpl) RawKeyCreator() (wrapperapi.RawKeyCreator, error) {
-	return newKeyCreator(s.kms), nil
+	return s.newKeyCreator(), nil
+}
+
+func (s *suiteImpl) newKeyCreator() wrapperapi.RawKeyCreator {
+	creator := &keyCreatorImpl{
+		kms:       s.kms,
+		keyRefKMS: s.kms,
+		cr:        s.crypto,
+		kidScheme: s.kidScheme,
+	}
+
+	if s.keyTypeCache != nil {
+		creator.kms = &cachedKeyCreator{keyCreator: s.kms, cache: s.keyTypeCache}
+	}
+
+	return creator
 }
 
 func (s *suiteImpl) KMSCrypto() (wrapperapi.KMSCrypto, error) {
-	return newKMSCrypto(s.kms, s.crypto), nil
+	return s.newKMSCrypto(), nil
+}
+
+func (s *suiteImpl) newKMSCrypto() *kmsCryptoImpl {
+	return &kmsCryptoImpl{
+		kms:              s.kms,
+		cr:               s.crypto,
+		verifierKeyCache: s.verifierKeyCache,
+	}
 }
 
 func (s *suiteImpl) KMSCryptoSigner() (wrapperapi.KMSCryptoSigner, error) {
@@ -36,7 +62,7 @@ func (s *suiteImpl) KMSCryptoMultiSigner() (wrapperapi.KMSCryptoMultiSigner, err
 }
 
 func (s *suiteImpl) KMSCryptoVerifier() (wrapperapi.KMSCryptoVerifier, error) {
-	return newKMSCrypto(s.kms, s.crypto), nil
+	return s.newKMSCrypto(), nil
 }
 
 func (s *suiteImpl) EncrypterDecrypter() (wrapperapi.EncrypterDecrypter, error) {
@@ -6,12 +6,18 @@ SPDX-License-Identifier: Apache-2.0
 package localsuite
 
 import (
+	"crypto"
+	"crypto/ecdsa"
 	"testing"
 
+	"github.com/dellekappa/kms-go/crypto/tinkcrypto"
+	"github.com/dellekappa/kms-go/doc/jose/jwk"
 	mockstorage "github.com/dellekappa/kms-go/internal/mock/storage"
 	"github.com/dellekappa/kms-go/kms"
 	"github.com/dellekappa/kms-go/secretlock/noop"
+	cryptoapi "github.com/dellekappa/kms-go/spi/crypto"
 	kmsapi "github.com/dellekappa/kms-go/spi/kms"
+	wrapperapi "github.com/dellekappa/kms-go/wrapper/api"
 	"github.com/stretchr/testify/require"
 )
 
@@ -68,6 +74,103 @@ func TestSuite(t *testing.T) {
 		fks, err := suite.FixedKeySigner(pub.KeyID)
 		require.NoError(t, err)
 		require.NotNil(t, fks)
+		require.Equal(t, kmsapi.BLS12381G2Type, fks.KeyType())
+		require.Empty(t, fks.Algorithm())
+	})
+
+	t.Run("FixedKeySigner Algorithm", func(t *testing.T) {
+		for _, tc := range []struct {
+			keyType kmsapi.KeyType
+			alg     string
+		}{
+			{keyType: kmsapi.ECDSAP256TypeIEEEP1363, alg: "ES256"},
+			{keyType: kmsapi.ECDSAP384TypeDER, alg: "ES384"},
+			{keyType: kmsapi.ECDSAP521TypeIEEEP1363, alg: "ES512"},
+			{keyType: kmsapi.ECDSASecp256k1TypeIEEEP1363, alg: "ES256K"},
+			{keyType: kmsapi.ED25519Type, alg: "EdDSA"},
+		} {
+			signingPub, err := creator.Create(tc.keyType)
+			require.NoError(t, err)
+
+			fks, err := suite.FixedKeySigner(signingPub.KeyID)
+			require.NoError(t, err)
+			require.Equal(t, tc.keyType, fks.KeyType())
+			require.Equal(t, tc.alg, fks.Algorithm())
+		}
+	})
+
+	t.Run("FixedKeySigner SignDigest", func(t *testing.T) {
+		for _, tc := range []struct {
+			keyType kmsapi.KeyType
+			hash    crypto.Hash
+		}{
+			{keyType: kmsapi.ECDSAP256TypeIEEEP1363, hash: crypto.SHA256},
+			{keyType: kmsapi.ECDSAP384TypeDER, hash: crypto.SHA384},
+			{keyType: kmsapi.ECDSAP521TypeIEEEP1363, hash: crypto.SHA512},
+		} {
+			signingPub, err := creator.Create(tc.keyType)
+			require.NoError(t, err)
+
+			fks, err := suite.FixedKeySigner(signingPub.KeyID)
+			require.NoError(t, err)
+
+			h := tc.hash.New()
+			h.Write([]byte("message"))
+			digest := h.Sum(nil)
+
+			sig, err := fks.(wrapperapi.DigestSigner).SignDigest(digest)
+			require.NoError(t, err)
+
+			verifier, err := suite.KMSCryptoVerifier()
+			require.NoError(t, err)
+
+			require.NoError(t, verifier.Verify(sig, []byte("message"), signingPub))
+			require.NoError(t, verifier.(wrapperapi.DigestVerifier).VerifyDigest(sig, digest, signingPub))
+
+			_, err = fks.(wrapperapi.DigestSigner).SignDigest(digest[:20])
+			require.ErrorContains(t, err, "invalid digest length")
+		}
+
+		edPub, err := creator.Create(kmsapi.ED25519Type)
+		require.NoError(t, err)
+
+		fks, err := suite.FixedKeySigner(edPub.KeyID)
+		require.NoError(t, err)
+
+		_, err = fks.(wrapperapi.DigestSigner).SignDigest(make([]byte, 32))
+		require.ErrorIs(t, err, tinkcrypto.ErrEd25519RequiresFullMessage)
+	})
+
+	t.Run("FixedKeySigner SignBoth", func(t *testing.T) {
+		for _, keyType := range []kmsapi.KeyType{kmsapi.ECDSAP256TypeIEEEP1363, kmsapi.ECDSAP384TypeDER} {
+			signingPub, err := creator.Create(keyType)
+			require.NoError(t, err)
+
+			fks, err := suite.FixedKeySigner(signingPub.KeyID)
+			require.NoError(t, err)
+
+			p1363, der, err := fks.(wrapperapi.DualFormatSigner).SignBoth([]byte("message"))
+			require.NoError(t, err)
+
+			verifier, err := suite.KMSCryptoVerifier()
+			require.NoError(t, err)
+
+			sig := der
+			if keyType == kmsapi.ECDSAP256TypeIEEEP1363 {
+				sig = p1363
+			}
+
+			require.NoError(t, verifier.Verify(sig, []byte("message"), signingPub))
+		}
+
+		edPub, err := creator.Create(kmsapi.ED25519Type)
+		require.NoError(t, err)
+
+		fks, err := suite.FixedKeySigner(edPub.KeyID)
+		require.NoError(t, err)
+
+		_, _, err = fks.(wrapperapi.DualFormatSigner).SignBoth([]byte("message"))
+		require.ErrorIs(t, err, tinkcrypto.ErrNotECDSAKey)
 	})
 
 	t.Run("KMSCryptoMultiSigner", func(t *testing.T) {
@@ -76,10 +179,42 @@ func TestSuite(t *testing.T) {
 		require.NotNil(t, kcms)
 	})
 
+	t.Run("KeyAgreer", func(t *testing.T) {
+		kc, err := suite.KMSCrypto()
+		require.NoError(t, err)
+
+		alicePub, err := creator.Create(kmsapi.NISTP256ECDHKWType)
+		require.NoError(t, err)
+
+		bobPub, err := creator.Create(kmsapi.NISTP256ECDHKWType)
+		require.NoError(t, err)
+
+		toPublicKey := func(j *jwk.JWK) *cryptoapi.PublicKey {
+			key, ok := j.Key.(*ecdsa.PublicKey)
+			require.True(t, ok)
+
+			return &cryptoapi.PublicKey{X: key.X.Bytes(), Y: key.Y.Bytes(), Curve: j.Crv, Type: j.Kty}
+		}
+
+		ka, ok := kc.(wrapperapi.KeyAgreer)
+		require.True(t, ok)
+
+		z, err := ka.ComputeSharedSecret(alicePub.KeyID, toPublicKey(bobPub))
+		require.NoError(t, err)
+
+		bobZ, err := ka.ComputeSharedSecret(bobPub.KeyID, toPublicKey(alicePub))
+		require.NoError(t, err)
+		require.Equal(t, z, bobZ)
+
+		_, err = ka.ComputeSharedSecret("unknown", toPublicKey(bobPub))
+		require.Error(t, err)
+	})
+
 	t.Run("FixedKeyMultiSigner", func(t *testing.T) {
 		fkms, err := suite.FixedKeyMultiSigner(pub.KeyID)
 		require.NoError(t, err)
 		require.NotNil(t, fkms)
+		require.Equal(t, kmsapi.BLS12381G2Type, fkms.KeyType())
 	})
 
 	t.Run("EncrypterDecrypter", func(t *testing.T) {
@@ -0,0 +1,124 @@
+/*
+Copyright Gen Digital Inc. All Rights Reserved.
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package localsuite
+
+import (
+	"crypto"
+	"encoding/base64"
+	"sync"
+	"time"
+
+	"github.com/bluele/gcache"
+
+	"github.com/dellekappa/kms-go/doc/jose/jwk"
+	"github.com/dellekappa/kms-go/doc/util/jwkkid"
+)
+
+// defaultVerifierKeyCacheTTL is the time after which a cached verification key expires, so that the keys of rotated or
+// deleted KMS keys eventually stop verifying.
+const defaultVerifierKeyCacheTTL = 5 * time.Minute
+
+// VerifierKeyCacheStats holds the statistics of a KMSCryptoVerifier parsed key cache.
+type VerifierKeyCacheStats struct {
+	Hits   uint64
+	Misses uint64
+	Size   int
+}
+
+// VerifierKeyCacheStatsProvider is implemented by the KMSCrypto and KMSCryptoVerifier of a suite built with the
+// WithVerifierKeyCache option.
+type VerifierKeyCacheStatsProvider interface {
+	VerifierKeyCacheStats() VerifierKeyCacheStats
+}
+
+// verifierKeyCache caches the verification key handles parsed from public JWKs, by JWK thumbprint and kid.
+type verifierKeyCache struct {
+	mu    sync.Mutex
+	cache gcache.Cache
+}
+
+func newVerifierKeyCache(size int, ttl time.Duration) *verifierKeyCache {
+	return &verifierKeyCache{
+		cache: gcache.New(size).LRU().Expiration(ttl).Build(),
+	}
+}
+
+func (c *verifierKeyCache) get(key string) (interface{}, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	kh, err := c.cache.Get(key)
+	if err != nil {
+		return nil, false
+	}
+
+	return kh, true
+}
+
+func (c *verifierKeyCache) set(key string, kh interface{}) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	// gcache Set only fails when a serialize function is configured, which is not the case here.
+	_ = c.cache.Set(key, kh) //nolint:errcheck
+}
+
+func (c *verifierKeyCache) stats() VerifierKeyCacheStats {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	return VerifierKeyCacheStats{
+		Hits:   c.cache.HitCount(),
+		Misses: c.cache.MissCount(),
+		Size:   c.cache.Len(false),
+	}
+}
+
+// getCachedKeyHandle returns the verification key handle of pub from cache, or builds it with getKeyHandle and caches
+// it. Entries are keyed by the RFC 7638 thumbprint of pub and its kid, as the kid selects the key type of keys stored
+// in the KMS. pub is not cached if its thumbprint can't be computed.
+func getCachedKeyHandle(pub *jwk.JWK, keyManager keyHandleFetcher, cache *verifierKeyCache) (interface{}, error) {
+	thumbprint, err := jwkThumbprint(pub)
+	if err != nil {
+		return getKeyHandle(pub, keyManager)
+	}
+
+	cacheKey := thumbprint + "#" + pub.KeyID
+
+	if kh, ok := cache.get(cacheKey); ok {
+		return kh, nil
+	}
+
+	kh, err := getKeyHandle(pub, keyManager)
+	if err != nil {
+		return nil, err
+	}
+
+	cache.set(cacheKey, kh)
+
+	return kh, nil
+}
+
+// jwkThumbprint computes the base64url encoded RFC 7638 SHA-256 thumbprint of pub, including the key types go-jose
+// can't compute the thumbprint of (secp256k1, BLS12-381 G2).
+func jwkThumbprint(pub *jwk.JWK) (string, error) {
+	tp, err := pub.Public().JSONWebKey.Thumbprint(crypto.SHA256)
+	if err == nil {
+		return base64.RawURLEncoding.EncodeToString(tp), nil
+	}
+
+	pkBytes, err := pub.PublicKeyBytes()
+	if err != nil {
+		return "", err
+	}
+
+	kt, err := pub.KeyType()
+	if err != nil {
+		return "", err
+	}
+
+	return jwkkid.CreateKID(pkBytes, kt)
+}
@@ -0,0 +1,194 @@
+/*
+Copyright Gen Digital Inc. All Rights Reserved.
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package localsuite
+
+import (
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/dellekappa/kms-go/doc/jose/jwk"
+	"github.com/dellekappa/kms-go/doc/util/jwkkid"
+	mockstorage "github.com/dellekappa/kms-go/internal/mock/storage"
+	"github.com/dellekappa/kms-go/kms"
+	"github.com/dellekappa/kms-go/secretlock/noop"
+	kmsapi "github.com/dellekappa/kms-go/spi/kms"
+	"github.com/dellekappa/kms-go/wrapper/api"
+)
+
+func TestVerifierKeyCache(t *testing.T) {
+	newSuite := func(t *testing.T, opts ...Opt) api.Suite {
+		store, err := kms.NewAriesProviderWrapper(mockstorage.NewMockStoreProvider())
+		require.NoError(t, err)
+
+		suite, err := NewLocalCryptoSuite("local-lock://custom/primary/key/", store, &noop.NoLock{}, opts...)
+		require.NoError(t, err)
+
+		return suite
+	}
+
+	msg := []byte("message")
+
+	for _, kt := range []kmsapi.KeyType{kmsapi.ECDSAP256TypeDER, kmsapi.ED25519Type, kmsapi.ECDSAP384TypeIEEEP1363} {
+		t.Run("repeated verifications hit the cache for "+string(kt), func(t *testing.T) {
+			suite := newSuite(t, WithVerifierKeyCache(10))
+
+			kc, err := suite.KMSCrypto()
+			require.NoError(t, err)
+
+			pub, err := kc.Create(kt)
+			require.NoError(t, err)
+
+			sig, err := kc.Sign(msg, pub)
+			require.NoError(t, err)
+
+			require.NoError(t, kc.Verify(sig, msg, pub))
+			require.Equal(t, VerifierKeyCacheStats{Misses: 1, Size: 1},
+				kc.(VerifierKeyCacheStatsProvider).VerifierKeyCacheStats())
+
+			// a new verifier from the same suite shares the cache.
+			verifier, err := suite.KMSCryptoVerifier()
+			require.NoError(t, err)
+
+			require.NoError(t, verifier.Verify(sig, msg, pub))
+			require.Error(t, verifier.Verify(sig, []byte("other message"), pub))
+			require.Equal(t, VerifierKeyCacheStats{Hits: 2, Misses: 1, Size: 1},
+				verifier.(VerifierKeyCacheStatsProvider).VerifierKeyCacheStats())
+		})
+	}
+
+	t.Run("cache is bounded", func(t *testing.T) {
+		suite := newSuite(t, WithVerifierKeyCache(1))
+
+		kc, err := suite.KMSCrypto()
+		require.NoError(t, err)
+
+		for i := 0; i < 3; i++ {
+			pub, e := kc.Create(kmsapi.ED25519Type)
+			require.NoError(t, e)
+
+			sig, e := kc.Sign(msg, pub)
+			require.NoError(t, e)
+
+			require.NoError(t, kc.Verify(sig, msg, pub))
+		}
+
+		require.Equal(t, VerifierKeyCacheStats{Misses: 3, Size: 1},
+			kc.(VerifierKeyCacheStatsProvider).VerifierKeyCacheStats())
+	})
+
+	t.Run("cached keys expire after the ttl", func(t *testing.T) {
+		suite := newSuite(t, WithVerifierKeyCache(10), WithVerifierKeyCacheTTL(50*time.Millisecond))
+
+		kc, err := suite.KMSCrypto()
+		require.NoError(t, err)
+
+		pub, err := kc.Create(kmsapi.ED25519Type)
+		require.NoError(t, err)
+
+		sig, err := kc.Sign(msg, pub)
+		require.NoError(t, err)
+
+		require.NoError(t, kc.Verify(sig, msg, pub))
+		require.NoError(t, kc.Verify(sig, msg, pub))
+		require.Equal(t, VerifierKeyCacheStats{Hits: 1, Misses: 1, Size: 1},
+			kc.(VerifierKeyCacheStatsProvider).VerifierKeyCacheStats())
+
+		time.Sleep(100 * time.Millisecond)
+
+		require.NoError(t, kc.Verify(sig, msg, pub))
+		require.Equal(t, VerifierKeyCacheStats{Hits: 1, Misses: 2, Size: 1},
+			kc.(VerifierKeyCacheStatsProvider).VerifierKeyCacheStats())
+	})
+
+	t.Run("concurrent verifications", func(t *testing.T) {
+		suite := newSuite(t, WithVerifierKeyCache(10))
+
+		kc, err := suite.KMSCrypto()
+		require.NoError(t, err)
+
+		pub, err := kc.Create(kmsapi.ECDSAP256TypeIEEEP1363)
+		require.NoError(t, err)
+
+		sig, err := kc.Sign(msg, pub)
+		require.NoError(t, err)
+
+		const goroutines = 20
+
+		var wg sync.WaitGroup
+
+		errs := make(chan error, goroutines)
+
+		for i := 0; i < goroutines; i++ {
+			wg.Add(1)
+
+			go func() {
+				defer wg.Done()
+
+				errs <- kc.Verify(sig, msg, pub)
+			}()
+		}
+
+		wg.Wait()
+		close(errs)
+
+		for e := range errs {
+			require.NoError(t, e)
+		}
+
+		stats := kc.(VerifierKeyCacheStatsProvider).VerifierKeyCacheStats()
+		require.Equal(t, uint64(goroutines), stats.Hits+stats.Misses)
+		require.Equal(t, 1, stats.Size)
+	})
+
+	t.Run("thumbprint of keys not supported by go-jose", func(t *testing.T) {
+		suite := newSuite(t)
+
+		creator, err := suite.KeyCreator()
+		require.NoError(t, err)
+
+		pub, err := creator.Create(kmsapi.ECDSASecp256k1TypeIEEEP1363)
+		require.NoError(t, err)
+
+		thumbprint, err := jwkThumbprint(pub)
+		require.NoError(t, err)
+
+		pkBytes, err := pub.PublicKeyBytes()
+		require.NoError(t, err)
+
+		kid, err := jwkkid.CreateKID(pkBytes, kmsapi.ECDSASecp256k1TypeIEEEP1363)
+		require.NoError(t, err)
+		require.Equal(t, kid, thumbprint)
+	})
+
+	t.Run("key without thumbprint is not cached", func(t *testing.T) {
+		suite := newSuite(t, WithVerifierKeyCache(10))
+
+		kc, err := suite.KMSCrypto()
+		require.NoError(t, err)
+
+		require.Error(t, kc.Verify([]byte("sig"), msg, &jwk.JWK{}))
+		require.Equal(t, VerifierKeyCacheStats{}, kc.(VerifierKeyCacheStatsProvider).VerifierKeyCacheStats())
+	})
+
+	t.Run("cache disabled", func(t *testing.T) {
+		suite := newSuite(t)
+
+		kc, err := suite.KMSCrypto()
+		require.NoError(t, err)
+
+		pub, err := kc.Create(kmsapi.ED25519Type)
+		require.NoError(t, err)
+
+		sig, err := kc.Sign(msg, pub)
+		require.NoError(t, err)
+
+		require.NoError(t, kc.Verify(sig, msg, pub))
+		require.Equal(t, VerifierKeyCacheStats{}, kc.(VerifierKeyCacheStatsProvider).VerifierKeyCacheStats())
+	})
+}
@@ -7,6 +7,7 @@ package localsuite
 
 import (
 	"github.com/dellekappa/kms-go/doc/jose/jwk"
+	cryptoapi "github.com/dellekappa/kms-go/spi/crypto"
 	"github.com/dellekappa/kms-go/spi/kms"
 	"github.com/dellekappa/kms-go/wrapper/api"
 )
@@ -20,8 +21,9 @@ func newKMSCrypto(kms keyManager, crypto signerVerifier) api.KMSCrypto {
 }
 
 type kmsCryptoImpl struct {
-	kms keyManager
-	cr  signerVerifier
+	kms              keyManager
+	cr               signerVerifier
+	verifierKeyCache *verifierKeyCache
 }
 
 func (k *kmsCryptoImpl) Create(keyType kms.KeyType) (*jwk.JWK, error) {
@@ -38,7 +40,7 @@ func (k *kmsCryptoImpl) Sign(msg []byte, pub *jwk.JWK) ([]byte, error) {
 		return nil, err
 	}
 
-	return k.cr.Sign(msg, kh)
+	return jwkSigner(pub, k.cr).Sign(msg, kh)
 }
 
 func getKeyHandle(pub *jwk.JWK, keyManager keyHandleFetcher) (interface{}, error) {
@@ -70,7 +72,7 @@ func getKeyHandle(pub *jwk.JWK, keyManager keyHandleFetcher) (interface{}, error
 }
 
 func (k *kmsCryptoImpl) Verify(sig, msg []byte, pub *jwk.JWK) error {
-	kh, err := getKeyHandle(pub, k.kms)
+	kh, err := k.verifierKeyHandle(pub)
 	if err != nil {
 		return err
 	}
@@ -78,6 +80,57 @@ func (k *kmsCryptoImpl) Verify(sig, msg []byte, pub *jwk.JWK) error {
 	return k.cr.Verify(sig, msg, kh)
 }
 
+// verifierKeyHandle returns the key handle verifying signatures of pub, from the verifier key cache if enabled.
+func (k *kmsCryptoImpl) verifierKeyHandle(pub *jwk.JWK) (interface{}, error) {
+	if k.verifierKeyCache != nil {
+		return getCachedKeyHandle(pub, k.kms, k.verifierKeyCache)
+	}
+
+	return getKeyHandle(pub, k.kms)
+}
+
+// VerifyDigest verifies sig signature of digest without hashing it, see api.DigestVerifier. It returns
+// api.ErrNotSupported if the crypto doesn't verify digests.
+func (k *kmsCryptoImpl) VerifyDigest(sig, digest []byte, pub *jwk.JWK) error {
+	dv, ok := k.cr.(digestSigner)
+	if !ok {
+		return api.ErrNotSupported
+	}
+
+	kh, err := k.verifierKeyHandle(pub)
+	if err != nil {
+		return err
+	}
+
+	return dv.VerifyDigest(sig, digest, kh)
+}
+
+// ComputeSharedSecret computes the ECDH shared secret of the private key senderKID with recipientPub without exporting
+// the private key, see api.KeyAgreer. It returns api.ErrNotSupported if the crypto doesn't compute shared secrets.
+func (k *kmsCryptoImpl) ComputeSharedSecret(senderKID string, recipientPub *cryptoapi.PublicKey) ([]byte, error) {
+	ka, ok := k.cr.(keyAgreer)
+	if !ok {
+		return nil, api.ErrNotSupported
+	}
+
+	kh, err := k.kms.Get(senderKID)
+	if err != nil {
+		return nil, err
+	}
+
+	return ka.ComputeSharedSecret(recipientPub, kh)
+}
+
+// VerifierKeyCacheStats returns the statistics of the verifier key cache, or empty statistics if the cache is
+// disabled.
+func (k *kmsCryptoImpl) VerifierKeyCacheStats() VerifierKeyCacheStats {
+	if k.verifierKeyCache == nil {
+		return VerifierKeyCacheStats{}
+	}
+
+	return k.verifierKeyCache.stats()
+}
+
 func (k *kmsCryptoImpl) FixedKeyCrypto(pub *jwk.JWK) (api.FixedKeyCrypto, error) {
 	return makeFixedKeyCrypto(k.kms, k.cr, pub)
 }
@@ -104,18 +157,6 @@ func (k *kmsCryptoImpl) FixedKeySigner(pub *jwk.JWK) (api.FixedKeySigner, error)
 	return makeFixedKeySigner(k.kms, k.cr, pub.KeyID)
 }
 
-func makeFixedKeySigner(kms keyGetter, crypto signer, kid string) (api.FixedKeySigner, error) {
-	kh, err := kms.Get(kid)
-	if err != nil {
-		return nil, err
-	}
-
-	return &fixedKeySignerImpl{
-		cr: crypto,
-		kh: kh,
-	}, nil
-}
-
 type fixedKeyImpl struct {
 	cr    signerVerifier
 	sigKH interface{}
@@ -129,3 +170,5 @@ func (f *fixedKeyImpl) Sign(msg []byte) ([]byte, error) {
 func (f *fixedKeyImpl) Verify(sig, msg []byte) error {
 	return f.cr.Verify(sig, msg, f.verKH)
 }
+
+var _ api.KeyAgreer = &kmsCryptoImpl{}
@@ -12,6 +12,8 @@ import (
 	"testing"
 
 	"github.com/dellekappa/kms-go/doc/jose/jwk"
+	"github.com/dellekappa/kms-go/doc/jose/jwk/jwksupport"
+	"github.com/dellekappa/kms-go/kms"
 	mockcrypto "github.com/dellekappa/kms-go/mock/crypto"
 	mockkms "github.com/dellekappa/kms-go/mock/kms"
 	kmsapi "github.com/dellekappa/kms-go/spi/kms"
@@ -359,6 +361,39 @@ func TestKmsCryptoSigner_FixedKeySigner(t *testing.T) {
 		require.Nil(t, fks)
 	})
 
+	t.Run("non-exportable key signs without a key type", func(t *testing.T) {
+		km := &countingKeyCreator{KeyManager: &mockkms.KeyManager{
+			ExportPubKeyBytesErr: kms.ErrKeyNotExportable,
+		}}
+		ks := newKMSCryptoSigner(km, &mockcrypto.Crypto{SignValue: sig})
+
+		fks, err := ks.FixedKeySigner(pk)
+		require.NoError(t, err)
+		require.Zero(t, km.exports)
+
+		sigOut, err := fks.Sign(msg)
+		require.NoError(t, err)
+		require.Equal(t, sig, sigOut)
+
+		require.Empty(t, fks.KeyType())
+		require.Empty(t, fks.Algorithm())
+	})
+
+	t.Run("fixed key signer algorithm", func(t *testing.T) {
+		km := &countingKeyCreator{KeyManager: &mockkms.KeyManager{
+			ExportPubKeyTypeValue: kmsapi.ECDSAP256TypeDER,
+		}}
+		ks := newKMSCryptoSigner(km, &mockcrypto.Crypto{})
+
+		fks, err := ks.FixedKeySigner(pk)
+		require.NoError(t, err)
+		require.Zero(t, km.exports)
+
+		require.Equal(t, kmsapi.ECDSAP256TypeDER, fks.KeyType())
+		require.Equal(t, "ES256", fks.Algorithm())
+		require.Equal(t, 1, km.exports)
+	})
+
 	t.Run("sign success", func(t *testing.T) {
 		kc := newKMSCryptoSigner(&mockkms.KeyManager{}, &mockcrypto.Crypto{
 			SignValue: sig,
@@ -386,4 +421,29 @@ func TestKmsCryptoSigner_FixedKeySigner(t *testing.T) {
 		require.ErrorIs(t, err, errExpected)
 		require.Nil(t, sigOut)
 	})
+
+	t.Run("sign with registered key type signer", func(t *testing.T) {
+		jwksupport.RegisterKeyTypeSigner(kmsapi.MLDSA44Type, &mockcrypto.Crypto{SignValue: []byte("ml-dsa signature")})
+
+		kc := newKMSCryptoSigner(&mockkms.KeyManager{
+			ExportPubKeyTypeValue: kmsapi.MLDSA44Type,
+		}, &mockcrypto.Crypto{
+			SignErr: errExpected,
+		})
+
+		fks, err := kc.FixedKeySigner(pk)
+		require.NoError(t, err)
+		require.Equal(t, "ML-DSA-44", fks.Algorithm())
+
+		sigOut, err := fks.Sign(msg)
+		require.NoError(t, err)
+		require.Equal(t, []byte("ml-dsa signature"), sigOut)
+
+		sigOut, err = kc.Sign(msg, &jwk.JWK{
+			JSONWebKey: jose.JSONWebKey{Key: make([]byte, 1312), KeyID: "foo", Algorithm: "ML-DSA-44"},
+			Kty:        "AKP",
+		})
+		require.NoError(t, err)
+		require.Equal(t, []byte("ml-dsa signature"), sigOut)
+	})
 }
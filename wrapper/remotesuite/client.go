@@ -0,0 +1,154 @@
+/*
+Copyright Gen Digital Inc. All Rights Reserved.
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package remotesuite
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+
+	"github.com/dellekappa/kms-go/spi/kms"
+)
+
+const contentType = "application/json"
+
+// HTTPClient interface for the http client.
+type HTTPClient interface {
+	Do(req *http.Request) (*http.Response, error)
+}
+
+type createKeyReq struct {
+	KeyType kms.KeyType `json:"key_type"`
+}
+
+type createKeyResp struct {
+	KeyURL    string `json:"key_url"`
+	PublicKey []byte `json:"public_key"`
+}
+
+type exportKeyResp struct {
+	PublicKey []byte `json:"public_key"`
+	KeyType   string `json:"key_type"`
+}
+
+type signReq struct {
+	Message []byte `json:"message"`
+}
+
+type signResp struct {
+	Signature []byte `json:"signature"`
+}
+
+type errMessage struct {
+	Error string `json:"errMessage"`
+}
+
+type httpClient struct {
+	client      HTTPClient
+	keystoreURL string
+}
+
+// NewHTTPClient returns a RemoteKMS calling the keystore keystoreURL of a key server exposing the webkms REST API:
+// keys are created with POST {keystoreURL}/keys, exported with GET {keystoreURL}/keys/{kid}/export and sign with
+// POST {keystoreURL}/keys/{kid}/sign.
+func NewHTTPClient(keystoreURL string, client HTTPClient) RemoteKMS {
+	return &httpClient{
+		client:      client,
+		keystoreURL: strings.TrimSuffix(keystoreURL, "/"),
+	}
+}
+
+func (c *httpClient) Create(ctx context.Context, keyType kms.KeyType) (string, []byte, error) {
+	var resp createKeyResp
+
+	err := c.call(ctx, http.MethodPost, c.keystoreURL+"/keys", &createKeyReq{KeyType: keyType}, &resp)
+	if err != nil {
+		return "", nil, fmt.Errorf("create key: %w", err)
+	}
+
+	return resp.KeyURL[strings.LastIndex(resp.KeyURL, "/")+1:], resp.PublicKey, nil
+}
+
+func (c *httpClient) Export(ctx context.Context, kid string) ([]byte, kms.KeyType, error) {
+	var resp exportKeyResp
+
+	err := c.call(ctx, http.MethodGet, c.keyURL(kid)+"/export", nil, &resp)
+	if err != nil {
+		return nil, "", fmt.Errorf("export key: %w", err)
+	}
+
+	return resp.PublicKey, kms.KeyType(resp.KeyType), nil
+}
+
+func (c *httpClient) Sign(ctx context.Context, kid string, msg []byte) ([]byte, error) {
+	var resp signResp
+
+	err := c.call(ctx, http.MethodPost, c.keyURL(kid)+"/sign", &signReq{Message: msg}, &resp)
+	if err != nil {
+		return nil, fmt.Errorf("sign: %w", err)
+	}
+
+	return resp.Signature, nil
+}
+
+func (c *httpClient) keyURL(kid string) string {
+	return c.keystoreURL + "/keys/" + url.PathEscape(kid)
+}
+
+// call sends req as JSON to destination and reads the JSON response in resp, non 2xx responses return an *HTTPError.
+func (c *httpClient) call(ctx context.Context, method, destination string, req, resp interface{}) error {
+	var body io.Reader
+
+	if req != nil {
+		reqBytes, err := json.Marshal(req)
+		if err != nil {
+			return fmt.Errorf("marshal request: %w", err)
+		}
+
+		body = bytes.NewReader(reqBytes)
+	}
+
+	httpReq, err := http.NewRequestWithContext(ctx, method, destination, body)
+	if err != nil {
+		return fmt.Errorf("build request: %w", err)
+	}
+
+	if req != nil {
+		httpReq.Header.Set("Content-Type", contentType)
+	}
+
+	httpResp, err := c.client.Do(httpReq)
+	if err != nil {
+		return err
+	}
+
+	defer httpResp.Body.Close() //nolint:errcheck
+
+	respBytes, err := io.ReadAll(httpResp.Body)
+	if err != nil {
+		return fmt.Errorf("read response: %w", err)
+	}
+
+	if httpResp.StatusCode < http.StatusOK || httpResp.StatusCode >= http.StatusMultipleChoices {
+		var errMsg errMessage
+
+		_ = json.Unmarshal(respBytes, &errMsg) //nolint:errcheck // the error message is optional.
+
+		return &HTTPError{StatusCode: httpResp.StatusCode, Message: errMsg.Error}
+	}
+
+	err = json.Unmarshal(respBytes, resp)
+	if err != nil {
+		return fmt.Errorf("unmarshal response: %w", err)
+	}
+
+	return nil
+}
@@ -0,0 +1,64 @@
+/*
+Copyright Gen Digital Inc. All Rights Reserved.
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package remotesuite
+
+import (
+	"context"
+	"time"
+
+	wrapperapi "github.com/dellekappa/kms-go/wrapper/api"
+)
+
+const (
+	defaultMaxAttempts    = 3
+	defaultInitialBackoff = 100 * time.Millisecond
+	defaultMaxBackoff     = 2 * time.Second
+)
+
+// Opt is a NewRemoteSuite option.
+type Opt func(opts *suiteOpts)
+
+type suiteOpts struct {
+	ctx            context.Context
+	maxAttempts    int
+	initialBackoff time.Duration
+	maxBackoff     time.Duration
+	kidScheme      wrapperapi.KIDScheme
+}
+
+// WithContext sets the context of the remote calls made through the suite's api interfaces, which don't take a
+// context. Cancelling ctx aborts pending calls and retries. Defaults to context.Background(), the Context methods of
+// KeyCreator and FixedKeySigner take a per call context instead.
+func WithContext(ctx context.Context) Opt {
+	return func(opts *suiteOpts) {
+		opts.ctx = ctx
+	}
+}
+
+// WithMaxAttempts sets the number of attempts of a remote call failing with transient errors, 3 by default. A value
+// of 1 disables retries.
+func WithMaxAttempts(attempts int) Opt {
+	return func(opts *suiteOpts) {
+		opts.maxAttempts = attempts
+	}
+}
+
+// WithBackoff sets the wait before the first retry of a remote call, doubled on each retry up to maxBackoff. Defaults
+// to 100ms, up to 2s.
+func WithBackoff(initial, maxBackoff time.Duration) Opt {
+	return func(opts *suiteOpts) {
+		opts.initialBackoff = initial
+		opts.maxBackoff = maxBackoff
+	}
+}
+
+// WithKIDScheme sets the api.KIDScheme deriving the kid of the public JWKs returned by the suite's KeyCreator, which
+// keep the remote KMS key ID by default. The key IDs returned by CreateRaw stay the remote KMS key IDs.
+func WithKIDScheme(scheme wrapperapi.KIDScheme) Opt {
+	return func(opts *suiteOpts) {
+		opts.kidScheme = scheme
+	}
+}
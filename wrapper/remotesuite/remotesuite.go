@@ -0,0 +1,98 @@
+/*
+Copyright Gen Digital Inc. All Rights Reserved.
+SPDX-License-Identifier: Apache-2.0
+*/
+
+// Package remotesuite provides an api.Suite signing with keys kept in a remote KMS, behind a RemoteKMS client.
+package remotesuite
+
+import (
+	"context"
+
+	"github.com/dellekappa/kms-go/doc/jose/jwk"
+	"github.com/dellekappa/kms-go/spi/kms"
+	wrapperapi "github.com/dellekappa/kms-go/wrapper/api"
+)
+
+// RemoteKMS is the client of a remote KMS service, see NewHTTPClient for a client of the webkms key server REST API.
+// Transient failures are retried by the suite: clients report HTTP status codes as an *HTTPError and network timeouts
+// as a net.Error.
+type RemoteKMS interface {
+	// Create creates a key of keyType, returning its key ID and its public key bytes.
+	Create(ctx context.Context, keyType kms.KeyType) (string, []byte, error)
+	// Export returns the public key bytes of the key kid and its key type.
+	Export(ctx context.Context, kid string) ([]byte, kms.KeyType, error)
+	// Sign signs msg with the private key of the key kid.
+	Sign(ctx context.Context, kid string, msg []byte) ([]byte, error)
+}
+
+// NewRemoteSuite initializes an api.Suite calling client, supporting the KeyCreator, RawKeyCreator, KMSCryptoSigner
+// and FixedKeySigner APIs. Other APIs return api.ErrNotSupported.
+func NewRemoteSuite(client RemoteKMS, opts ...Opt) wrapperapi.Suite {
+	options := &suiteOpts{
+		ctx:            context.Background(),
+		maxAttempts:    defaultMaxAttempts,
+		initialBackoff: defaultInitialBackoff,
+		maxBackoff:     defaultMaxBackoff,
+	}
+
+	for _, opt := range opts {
+		opt(options)
+	}
+
+	return &suite{
+		remote:    &retryingKMS{client: client, opts: options},
+		ctx:       options.ctx,
+		kidScheme: options.kidScheme,
+	}
+}
+
+type suite struct {
+	remote    *retryingKMS
+	ctx       context.Context
+	kidScheme wrapperapi.KIDScheme
+}
+
+func (s *suite) KeyCreator() (wrapperapi.KeyCreator, error) {
+	return s.newKeyCreator(), nil
+}
+
+func (s *suite) RawKeyCreator() (wrapperapi.RawKeyCreator, error) {
+	return s.newKeyCreator(), nil
+}
+
+func (s *suite) newKeyCreator() *KeyCreator {
+	return &KeyCreator{remote: s.remote, ctx: s.ctx, kidScheme: s.kidScheme}
+}
+
+func (s *suite) KMSCryptoSigner() (wrapperapi.KMSCryptoSigner, error) {
+	return &kmsCryptoSigner{remote: s.remote, ctx: s.ctx}, nil
+}
+
+func (s *suite) FixedKeySigner(kid string) (wrapperapi.FixedKeySigner, error) {
+	return makeFixedKeySigner(s.ctx, s.remote, kid)
+}
+
+func (s *suite) KMSCrypto() (wrapperapi.KMSCrypto, error) {
+	return nil, wrapperapi.ErrNotSupported
+}
+
+func (s *suite) KMSCryptoMultiSigner() (wrapperapi.KMSCryptoMultiSigner, error) {
+	return nil, wrapperapi.ErrNotSupported
+}
+
+func (s *suite) KMSCryptoVerifier() (wrapperapi.KMSCryptoVerifier, error) {
+	return nil, wrapperapi.ErrNotSupported
+}
+
+func (s *suite) EncrypterDecrypter() (wrapperapi.EncrypterDecrypter, error) {
+	return nil, wrapperapi.ErrNotSupported
+}
+
+func (s *suite) FixedKeyCrypto(*jwk.JWK) (wrapperapi.FixedKeyCrypto, error) {
+	return nil, wrapperapi.ErrNotSupported
+}
+
+func (s *suite) FixedKeyMultiSigner(string) (wrapperapi.FixedKeyMultiSigner, error) {
+	return nil, wrapperapi.ErrNotSupported
+}
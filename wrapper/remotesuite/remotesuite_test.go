@@ -0,0 +1,186 @@
+/*
+Copyright Gen Digital Inc. All Rights Reserved.
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package remotesuite
+
+import (
+	"context"
+	"crypto/ed25519"
+	"crypto/rand"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/dellekappa/kms-go/spi/kms"
+	wrapperapi "github.com/dellekappa/kms-go/wrapper/api"
+)
+
+// testKeyServer is a webkms key server keeping Ed25519 keys in memory, failing the first failures calls with
+// failStatus.
+type testKeyServer struct {
+	mu         sync.Mutex
+	keys       map[string]ed25519.PrivateKey
+	failures   int32
+	failStatus int
+	calls      int32
+}
+
+func (s *testKeyServer) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	atomic.AddInt32(&s.calls, 1)
+
+	if atomic.AddInt32(&s.failures, -1) >= 0 {
+		w.WriteHeader(s.failStatus)
+		_, _ = w.Write([]byte(`{"errMessage":"failure"}`)) //nolint:errcheck
+
+		return
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	parts := strings.Split(strings.TrimPrefix(r.URL.Path, "/kms/keys"), "/")
+
+	switch {
+	case r.Method == http.MethodPost && r.URL.Path == "/kms/keys":
+		_, priv, _ := ed25519.GenerateKey(rand.Reader) //nolint:errcheck
+		kid := string(rune('a' + len(s.keys)))
+		s.keys[kid] = priv
+
+		writeJSON(w, &createKeyResp{KeyURL: "/kms/keys/" + kid, PublicKey: priv.Public().(ed25519.PublicKey)})
+	case len(parts) == 3 && parts[2] == "export" && s.keys[parts[1]] != nil:
+		writeJSON(w, &exportKeyResp{
+			PublicKey: s.keys[parts[1]].Public().(ed25519.PublicKey), KeyType: string(kms.ED25519Type),
+		})
+	case len(parts) == 3 && parts[2] == "sign" && s.keys[parts[1]] != nil:
+		var req signReq
+
+		_ = json.NewDecoder(r.Body).Decode(&req) //nolint:errcheck
+
+		writeJSON(w, &signResp{Signature: ed25519.Sign(s.keys[parts[1]], req.Message)})
+	default:
+		w.WriteHeader(http.StatusNotFound)
+	}
+}
+
+func writeJSON(w http.ResponseWriter, v interface{}) {
+	_ = json.NewEncoder(w).Encode(v) //nolint:errcheck
+}
+
+func newTestSuite(t *testing.T, opts ...Opt) (wrapperapi.Suite, *testKeyServer) {
+	t.Helper()
+
+	keyServer := &testKeyServer{keys: map[string]ed25519.PrivateKey{}, failStatus: http.StatusServiceUnavailable}
+	server := httptest.NewServer(keyServer)
+	t.Cleanup(server.Close)
+
+	opts = append([]Opt{WithBackoff(time.Millisecond, 5*time.Millisecond)}, opts...)
+
+	return NewRemoteSuite(NewHTTPClient(server.URL+"/kms", server.Client()), opts...), keyServer
+}
+
+func TestRemoteSuite(t *testing.T) {
+	suite, keyServer := newTestSuite(t)
+
+	creator, err := suite.KeyCreator()
+	require.NoError(t, err)
+
+	pub, err := creator.Create(kms.ED25519Type)
+	require.NoError(t, err)
+	require.Equal(t, "a", pub.KeyID)
+
+	signer, err := suite.FixedKeySigner(pub.KeyID)
+	require.NoError(t, err)
+	require.Equal(t, kms.ED25519Type, signer.KeyType())
+	require.Equal(t, "EdDSA", signer.Algorithm())
+
+	sig, err := signer.Sign([]byte("msg"))
+	require.NoError(t, err)
+	require.True(t, ed25519.Verify(pub.Key.(ed25519.PublicKey), []byte("msg"), sig))
+
+	kmsSigner, err := suite.KMSCryptoSigner()
+	require.NoError(t, err)
+
+	sig, err = kmsSigner.Sign([]byte("other msg"), pub)
+	require.NoError(t, err)
+	require.True(t, ed25519.Verify(pub.Key.(ed25519.PublicKey), []byte("other msg"), sig))
+
+	_, err = suite.FixedKeySigner("unknown")
+	require.EqualError(t, err, "export key: remote kms returned HTTP status 404")
+	require.EqualValues(t, 5, atomic.LoadInt32(&keyServer.calls), "404 must not be retried")
+
+	_, err = suite.KMSCrypto()
+	require.ErrorIs(t, err, wrapperapi.ErrNotSupported)
+}
+
+func TestRemoteSuiteKIDScheme(t *testing.T) {
+	suite, _ := newTestSuite(t, WithKIDScheme(wrapperapi.ThumbprintScheme{}))
+
+	creator, err := suite.RawKeyCreator()
+	require.NoError(t, err)
+
+	pub, err := creator.Create(kms.ED25519Type)
+	require.NoError(t, err)
+
+	thumbprint, err := wrapperapi.ThumbprintScheme{}.KID("", pub.Key.(ed25519.PublicKey), kms.ED25519Type)
+	require.NoError(t, err)
+	require.Equal(t, thumbprint, pub.KeyID)
+
+	kid, _, err := creator.CreateRaw(kms.ED25519Type)
+	require.NoError(t, err)
+	require.Equal(t, "b", kid)
+}
+
+func TestRemoteSuiteRetry(t *testing.T) {
+	t.Run("transient errors are retried", func(t *testing.T) {
+		suite, keyServer := newTestSuite(t)
+		atomic.StoreInt32(&keyServer.failures, 2)
+
+		creator, err := suite.KeyCreator()
+		require.NoError(t, err)
+
+		_, err = creator.Create(kms.ED25519Type)
+		require.NoError(t, err)
+		require.EqualValues(t, 3, atomic.LoadInt32(&keyServer.calls))
+	})
+
+	t.Run("attempts are bounded", func(t *testing.T) {
+		suite, keyServer := newTestSuite(t, WithMaxAttempts(2))
+		atomic.StoreInt32(&keyServer.failures, 2)
+		keyServer.failStatus = http.StatusTooManyRequests
+
+		creator, err := suite.KeyCreator()
+		require.NoError(t, err)
+
+		_, err = creator.Create(kms.ED25519Type)
+		require.EqualError(t, err, "create key: remote kms returned HTTP status 429: failure")
+		require.EqualValues(t, 2, atomic.LoadInt32(&keyServer.calls))
+	})
+
+	t.Run("context cancellation stops retries", func(t *testing.T) {
+		ctx, cancel := context.WithCancel(context.Background())
+
+		suite, keyServer := newTestSuite(t, WithContext(ctx), WithBackoff(time.Hour, time.Hour))
+		atomic.StoreInt32(&keyServer.failures, 1)
+
+		creator, err := suite.KeyCreator()
+		require.NoError(t, err)
+
+		time.AfterFunc(10*time.Millisecond, cancel)
+
+		_, err = creator.Create(kms.ED25519Type)
+		require.ErrorIs(t, err, context.Canceled)
+		require.EqualValues(t, 1, atomic.LoadInt32(&keyServer.calls))
+
+		_, err = creator.(*KeyCreator).CreateContext(context.Background(), kms.ED25519Type)
+		require.NoError(t, err)
+	})
+}
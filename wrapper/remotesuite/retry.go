@@ -0,0 +1,135 @@
+/*
+Copyright Gen Digital Inc. All Rights Reserved.
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package remotesuite
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"time"
+
+	"github.com/dellekappa/kms-go/spi/kms"
+)
+
+// HTTPError is returned by RemoteKMS clients for calls answered with a non 2xx HTTP status code.
+type HTTPError struct {
+	StatusCode int
+	Message    string
+}
+
+func (e *HTTPError) Error() string {
+	if e.Message == "" {
+		return fmt.Sprintf("remote kms returned HTTP status %d", e.StatusCode)
+	}
+
+	return fmt.Sprintf("remote kms returned HTTP status %d: %s", e.StatusCode, e.Message)
+}
+
+// isTransient reports whether the call failing with err may succeed if retried: the remote KMS answered with a
+// timeout, throttling or unavailability status code, or the request timed out.
+func isTransient(err error) bool {
+	var httpErr *HTTPError
+
+	if errors.As(err, &httpErr) {
+		switch httpErr.StatusCode {
+		case http.StatusRequestTimeout, http.StatusTooManyRequests, http.StatusInternalServerError,
+			http.StatusBadGateway, http.StatusServiceUnavailable, http.StatusGatewayTimeout:
+			return true
+		}
+
+		return false
+	}
+
+	var netErr net.Error
+
+	return errors.As(err, &netErr) && netErr.Timeout()
+}
+
+// retryingKMS calls client, retrying transient failures with exponential backoff until ctx is done.
+type retryingKMS struct {
+	client RemoteKMS
+	opts   *suiteOpts
+}
+
+func (r *retryingKMS) create(ctx context.Context, keyType kms.KeyType) (string, []byte, error) {
+	var (
+		kid    string
+		pubKey []byte
+	)
+
+	err := r.do(ctx, func() error {
+		var err error
+
+		kid, pubKey, err = r.client.Create(ctx, keyType)
+
+		return err
+	})
+
+	return kid, pubKey, err
+}
+
+func (r *retryingKMS) export(ctx context.Context, kid string) ([]byte, kms.KeyType, error) {
+	var (
+		pubKey  []byte
+		keyType kms.KeyType
+	)
+
+	err := r.do(ctx, func() error {
+		var err error
+
+		pubKey, keyType, err = r.client.Export(ctx, kid)
+
+		return err
+	})
+
+	return pubKey, keyType, err
+}
+
+func (r *retryingKMS) sign(ctx context.Context, kid string, msg []byte) ([]byte, error) {
+	var sig []byte
+
+	err := r.do(ctx, func() error {
+		var err error
+
+		sig, err = r.client.Sign(ctx, kid, msg)
+
+		return err
+	})
+
+	return sig, err
+}
+
+func (r *retryingKMS) do(ctx context.Context, call func() error) error {
+	backoff := r.opts.initialBackoff
+
+	for attempt := 1; ; attempt++ {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+
+		err := call()
+		if err == nil || attempt >= r.opts.maxAttempts || !isTransient(err) {
+			return err
+		}
+
+		timer := time.NewTimer(backoff)
+
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+
+			return fmt.Errorf("%w (after %d attempts, last error: %w)", ctx.Err(), attempt, err)
+		case <-timer.C:
+		}
+
+		backoff *= 2
+		if backoff > r.opts.maxBackoff {
+			backoff = r.opts.maxBackoff
+		}
+	}
+}
@@ -0,0 +1,117 @@
+/*
+Copyright Gen Digital Inc. All Rights Reserved.
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package remotesuite
+
+import (
+	"context"
+
+	"github.com/dellekappa/kms-go/doc/jose/jwk"
+	"github.com/dellekappa/kms-go/doc/jose/jwk/jwksupport"
+	"github.com/dellekappa/kms-go/doc/util/kmssigner"
+	"github.com/dellekappa/kms-go/spi/kms"
+	wrapperapi "github.com/dellekappa/kms-go/wrapper/api"
+)
+
+// KeyCreator is the api.RawKeyCreator of the suite, creating keys in the remote KMS.
+type KeyCreator struct {
+	remote    *retryingKMS
+	ctx       context.Context
+	kidScheme wrapperapi.KIDScheme
+}
+
+// Create creates a key of keyType in the remote KMS and returns its public key, with the remote key ID as kid or the
+// kid derived by the WithKIDScheme option.
+func (k *KeyCreator) Create(keyType kms.KeyType) (*jwk.JWK, error) {
+	return k.CreateContext(k.ctx, keyType)
+}
+
+// CreateContext is Create with a per call context.
+func (k *KeyCreator) CreateContext(ctx context.Context, keyType kms.KeyType) (*jwk.JWK, error) {
+	kid, pkBytes, err := k.remote.create(ctx, keyType)
+	if err != nil {
+		return nil, err
+	}
+
+	pk, err := jwksupport.PubKeyBytesToJWK(pkBytes, keyType)
+	if err != nil {
+		return nil, err
+	}
+
+	pk.KeyID = kid
+
+	return wrapperapi.ApplyKIDScheme(k.kidScheme, pk, pkBytes, keyType)
+}
+
+// CreateRaw creates a key of keyType in the remote KMS and returns its key ID and public key.
+func (k *KeyCreator) CreateRaw(keyType kms.KeyType) (string, interface{}, error) {
+	kid, pkBytes, err := k.remote.create(k.ctx, keyType)
+	if err != nil {
+		return "", nil, err
+	}
+
+	raw, err := jwksupport.PubKeyBytesToKey(pkBytes, keyType)
+	if err != nil {
+		return "", nil, err
+	}
+
+	return kid, raw, nil
+}
+
+// ExportPubKeyBytes returns the public key bytes of the remote key id and its key type.
+func (k *KeyCreator) ExportPubKeyBytes(id string) ([]byte, kms.KeyType, error) {
+	return k.remote.export(k.ctx, id)
+}
+
+type kmsCryptoSigner struct {
+	remote *retryingKMS
+	ctx    context.Context
+}
+
+func (k *kmsCryptoSigner) Sign(msg []byte, pub *jwk.JWK) ([]byte, error) {
+	return k.remote.sign(k.ctx, pub.KeyID, msg)
+}
+
+func (k *kmsCryptoSigner) FixedKeySigner(pub *jwk.JWK) (wrapperapi.FixedKeySigner, error) {
+	return makeFixedKeySigner(k.ctx, k.remote, pub.KeyID)
+}
+
+// FixedKeySigner is the api.FixedKeySigner of the suite, signing with a key of the remote KMS.
+type FixedKeySigner struct {
+	remote  *retryingKMS
+	ctx     context.Context
+	kid     string
+	keyType kms.KeyType
+}
+
+// makeFixedKeySigner creates a FixedKeySigner of kid, fetching its key type from the remote KMS.
+func makeFixedKeySigner(ctx context.Context, remote *retryingKMS, kid string) (*FixedKeySigner, error) {
+	_, keyType, err := remote.export(ctx, kid)
+	if err != nil {
+		return nil, err
+	}
+
+	return &FixedKeySigner{remote: remote, ctx: ctx, kid: kid, keyType: keyType}, nil
+}
+
+// Sign signs msg with the remote key.
+func (f *FixedKeySigner) Sign(msg []byte) ([]byte, error) {
+	return f.SignContext(f.ctx, msg)
+}
+
+// SignContext is Sign with a per call context.
+func (f *FixedKeySigner) SignContext(ctx context.Context, msg []byte) ([]byte, error) {
+	return f.remote.sign(ctx, f.kid, msg)
+}
+
+// Algorithm returns the JOSE alg of the signatures of the remote key.
+func (f *FixedKeySigner) Algorithm() string {
+	return kmssigner.KeyTypeToJWA(f.keyType)
+}
+
+// KeyType returns the KMS key type of the remote key.
+func (f *FixedKeySigner) KeyType() kms.KeyType {
+	return f.keyType
+}
@@ -6,8 +6,12 @@ SPDX-License-Identifier: Apache-2.0
 package websuite
 
 import (
+	"sync"
+
 	webcrypto "github.com/dellekappa/kms-go/crypto/webkms"
+	"github.com/dellekappa/kms-go/doc/util/kmssigner"
 	"github.com/dellekappa/kms-go/kms/webkms"
+	"github.com/dellekappa/kms-go/spi/kms"
 )
 
 func makeFixedKey(
@@ -26,9 +30,32 @@ func makeFixedKey(
 	}, nil
 }
 
+// makeFixedKeySigner creates a fixed key signer. The key type of keyID is fetched from the remote KMS on the first
+// Algorithm or KeyType call, not when the signer is created.
+func makeFixedKeySigner(
+	keyID string,
+	keyGetter *webkms.RemoteKMS,
+	crypto *webcrypto.RemoteCrypto,
+) (*fixedKeyCrypto, error) {
+	fkc, err := makeFixedKey(keyID, keyGetter, crypto)
+	if err != nil {
+		return nil, err
+	}
+
+	fkc.keyID = keyID
+	fkc.keyGetter = keyGetter
+
+	return fkc, nil
+}
+
 type fixedKeyCrypto struct {
-	keyURL interface{}
-	cr     *webcrypto.RemoteCrypto
+	keyURL    interface{}
+	cr        *webcrypto.RemoteCrypto
+	keyID     string
+	keyGetter *webkms.RemoteKMS
+
+	keyTypeOnce sync.Once
+	keyType     kms.KeyType
 }
 
 func (f *fixedKeyCrypto) Sign(msg []byte) ([]byte, error) {
@@ -42,3 +69,23 @@ func (f *fixedKeyCrypto) SignMulti(msgs [][]byte) ([]byte, error) {
 func (f *fixedKeyCrypto) Verify(sig, msg []byte) error {
 	return f.cr.Verify(sig, msg, f.keyURL)
 }
+
+func (f *fixedKeyCrypto) Algorithm() string {
+	return kmssigner.KeyTypeToJWA(f.KeyType())
+}
+
+// KeyType returns the key type of the signing key, or "" if it can't be fetched from the remote KMS.
+func (f *fixedKeyCrypto) KeyType() kms.KeyType {
+	f.keyTypeOnce.Do(func() {
+		if f.keyGetter == nil {
+			return
+		}
+
+		_, keyType, err := f.keyGetter.ExportPubKeyBytes(f.keyID)
+		if err == nil {
+			f.keyType = keyType
+		}
+	})
+
+	return f.keyType
+}
@@ -17,6 +17,8 @@ import (
 type kmsCrypto struct {
 	km *webkms.RemoteKMS
 	cr *webcrypto.RemoteCrypto
+	// kidScheme derives the kid of the created JWKs, they keep the web KMS key ID if nil.
+	kidScheme wrapperapi.KIDScheme
 }
 
 func (k *kmsCrypto) Create(keyType kms.KeyType) (*jwk.JWK, error) {
@@ -32,7 +34,7 @@ func (k *kmsCrypto) Create(keyType kms.KeyType) (*jwk.JWK, error) {
 
 	pk.KeyID = kid
 
-	return pk, nil
+	return wrapperapi.ApplyKIDScheme(k.kidScheme, pk, pkBytes, keyType)
 }
 
 func (k *kmsCrypto) ExportPubKeyBytes(id string) ([]byte, kms.KeyType, error) {
@@ -103,13 +105,13 @@ func (k *kmsCrypto) FixedKeyCrypto(pub *jwk.JWK) (wrapperapi.FixedKeyCrypto, err
 }
 
 func (k *kmsCrypto) FixedKeySigner(pub *jwk.JWK) (wrapperapi.FixedKeySigner, error) {
-	return makeFixedKey(pub.KeyID, k.km, k.cr)
+	return makeFixedKeySigner(pub.KeyID, k.km, k.cr)
 }
 
 func (k *kmsCrypto) FixedKeyMultiSigner(pub *jwk.JWK) (wrapperapi.FixedKeyMultiSigner, error) {
-	return makeFixedKey(pub.KeyID, k.km, k.cr)
+	return makeFixedKeySigner(pub.KeyID, k.km, k.cr)
 }
 
 func (k *kmsCrypto) FixedMultiSignerGivenKID(kid string) (wrapperapi.FixedKeyMultiSigner, error) {
-	return makeFixedKey(kid, k.km, k.cr)
+	return makeFixedKeySigner(kid, k.km, k.cr)
 }
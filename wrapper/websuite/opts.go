@@ -0,0 +1,26 @@
+/*
+Copyright Gen Digital Inc. All Rights Reserved.
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package websuite
+
+import (
+	wrapperapi "github.com/dellekappa/kms-go/wrapper/api"
+)
+
+// Opt is a NewWebCryptoSuite option.
+type Opt func(opts *suiteOpts)
+
+type suiteOpts struct {
+	kidScheme wrapperapi.KIDScheme
+}
+
+// WithKIDScheme sets the api.KIDScheme deriving the kid of the public JWKs returned by the suite's KeyCreators, which
+// keep the web KMS key ID by default. The key IDs returned by CreateRaw stay the web KMS key IDs, as do the kids of
+// the JWKs created by the KMSCrypto, which looks keys up by kid.
+func WithKIDScheme(scheme wrapperapi.KIDScheme) Opt {
+	return func(opts *suiteOpts) {
+		opts.kidScheme = scheme
+	}
+}
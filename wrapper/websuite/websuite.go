@@ -17,19 +17,27 @@ import (
 
 // NewWebCryptoSuite initializes an api.Suite using web kms and crypto
 // clients, supporting all Suite APIs.
-func NewWebCryptoSuite(endpoint string, httpClient *http.Client) wrapperapi.Suite {
+func NewWebCryptoSuite(endpoint string, httpClient *http.Client, opts ...Opt) wrapperapi.Suite {
 	km := webkms.New(endpoint, httpClient)
 	cr := webcrypto.New(endpoint, httpClient)
 
+	options := &suiteOpts{}
+
+	for _, opt := range opts {
+		opt(options)
+	}
+
 	return &suite{
-		km: km,
-		cr: cr,
+		km:        km,
+		cr:        cr,
+		kidScheme: options.kidScheme,
 	}
 }
 
 type suite struct {
-	km *webkms.RemoteKMS
-	cr *webcrypto.RemoteCrypto
+	km        *webkms.RemoteKMS
+	cr        *webcrypto.RemoteCrypto
+	kidScheme wrapperapi.KIDScheme
 }
 
 func (s *suite) KMSCryptoVerifier() (wrapperapi.KMSCryptoVerifier, error) {
@@ -41,8 +49,9 @@ func (s *suite) KMSCryptoVerifier() (wrapperapi.KMSCryptoVerifier, error) {
 
 func (s *suite) KeyCreator() (wrapperapi.KeyCreator, error) {
 	return &kmsCrypto{
-		km: s.km,
-		cr: s.cr,
+		km:        s.km,
+		cr:        s.cr,
+		kidScheme: s.kidScheme,
 	}, nil
 }
 
@@ -59,8 +68,9 @@ func (s *suite) FixedKeyCrypto(pub *jwk.JWK) (wrapperapi.FixedKeyCrypto, error)
 
 func (s *suite) RawKeyCreator() (wrapperapi.RawKeyCreator, error) {
 	return &kmsCrypto{
-		km: s.km,
-		cr: s.cr,
+		km:        s.km,
+		cr:        s.cr,
+		kidScheme: s.kidScheme,
 	}, nil
 }
 
@@ -72,7 +82,7 @@ func (s *suite) KMSCryptoSigner() (wrapperapi.KMSCryptoSigner, error) {
 }
 
 func (s *suite) FixedKeySigner(kid string) (wrapperapi.FixedKeySigner, error) {
-	return makeFixedKey(kid, s.km, s.cr)
+	return makeFixedKeySigner(kid, s.km, s.cr)
 }
 
 func (s *suite) KMSCryptoMultiSigner() (wrapperapi.KMSCryptoMultiSigner, error) {
@@ -83,7 +93,7 @@ func (s *suite) KMSCryptoMultiSigner() (wrapperapi.KMSCryptoMultiSigner, error)
 }
 
 func (s *suite) FixedKeyMultiSigner(kid string) (wrapperapi.FixedKeyMultiSigner, error) {
-	return makeFixedKey(kid, s.km, s.cr)
+	return makeFixedKeySigner(kid, s.km, s.cr)
 }
 
 func (s *suite) EncrypterDecrypter() (wrapperapi.EncrypterDecrypter, error) {